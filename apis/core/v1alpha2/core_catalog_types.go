@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A CatalogWorkloadDefinition is a WorkloadDefinition registered by a
+// DefinitionCatalog.
+type CatalogWorkloadDefinition struct {
+	// Name of the WorkloadDefinition.
+	Name string `json:"name"`
+
+	// Spec of the WorkloadDefinition.
+	Spec WorkloadDefinitionSpec `json:"spec"`
+}
+
+// A CatalogTraitDefinition is a TraitDefinition registered by a
+// DefinitionCatalog.
+type CatalogTraitDefinition struct {
+	// Name of the TraitDefinition.
+	Name string `json:"name"`
+
+	// Spec of the TraitDefinition.
+	Spec TraitDefinitionSpec `json:"spec"`
+}
+
+// A CatalogScopeDefinition is a ScopeDefinition registered by a
+// DefinitionCatalog.
+type CatalogScopeDefinition struct {
+	// Name of the ScopeDefinition.
+	Name string `json:"name"`
+
+	// Spec of the ScopeDefinition.
+	Spec ScopeDefinitionSpec `json:"spec"`
+}
+
+// A DefinitionCatalogSpec defines the desired state of a DefinitionCatalog.
+type DefinitionCatalogSpec struct {
+	// WorkloadDefinitions registered in the namespace of the catalog.
+	// +optional
+	WorkloadDefinitions []CatalogWorkloadDefinition `json:"workloadDefinitions,omitempty"`
+
+	// TraitDefinitions registered in the namespace of the catalog.
+	// +optional
+	TraitDefinitions []CatalogTraitDefinition `json:"traitDefinitions,omitempty"`
+
+	// ScopeDefinitions registered in the namespace of the catalog.
+	// +optional
+	ScopeDefinitions []CatalogScopeDefinition `json:"scopeDefinitions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DefinitionCatalog registers WorkloadDefinitions, TraitDefinitions and
+// ScopeDefinitions in its namespace, so that teams may register definitions
+// without permission to create the cluster scoped ones. The workloads, traits
+// and scopes of a namespace are resolved to the definitions registered in it
+// before the cluster scoped definitions. If several catalogs of a namespace
+// register a definition of the same kind and name the first of them, ordered
+// by name, wins.
+// +kubebuilder:resource:categories={crossplane,oam}
+type DefinitionCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DefinitionCatalogSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DefinitionCatalogList contains a list of DefinitionCatalog.
+type DefinitionCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DefinitionCatalog `json:"items"`
+}
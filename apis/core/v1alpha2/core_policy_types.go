@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A DefinitionUsagePolicySpec defines the desired state of a
+// DefinitionUsagePolicy.
+type DefinitionUsagePolicySpec struct {
+	// NamespaceSelector selects the namespaces of the ApplicationConfigurations
+	// this policy applies to. The policy applies in all namespaces if it is
+	// omitted.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Selector selects the ApplicationConfigurations this policy applies to by
+	// their labels. The policy applies to all ApplicationConfigurations in the
+	// selected namespaces if it is omitted.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// WorkloadDefinitions lists the names of the WorkloadDefinitions whose
+	// workloads the components of selected ApplicationConfigurations may
+	// use. The use of WorkloadDefinitions is not restricted if it is omitted.
+	// +optional
+	WorkloadDefinitions []string `json:"workloadDefinitions,omitempty"`
+
+	// TraitDefinitions lists the names of the TraitDefinitions whose traits
+	// selected ApplicationConfigurations may use. The use of TraitDefinitions
+	// is not restricted if it is omitted.
+	// +optional
+	TraitDefinitions []string `json:"traitDefinitions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DefinitionUsagePolicy restricts the WorkloadDefinitions and
+// TraitDefinitions that the ApplicationConfigurations it selects may use. An
+// ApplicationConfiguration may only use the definitions allowed by every
+// policy that selects it.
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,oam}
+type DefinitionUsagePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DefinitionUsagePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DefinitionUsagePolicyList contains a list of DefinitionUsagePolicy.
+type DefinitionUsagePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DefinitionUsagePolicy `json:"items"`
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A DefinitionRevisionSpec is a snapshot of the spec of a WorkloadDefinition
+// or TraitDefinition.
+type DefinitionRevisionSpec struct {
+	// DefinitionKind is the kind of the definition, i.e. WorkloadDefinition
+	// or TraitDefinition.
+	// +kubebuilder:validation:Enum=WorkloadDefinition;TraitDefinition
+	DefinitionKind string `json:"definitionKind"`
+
+	// DefinitionName is the name of the definition.
+	DefinitionName string `json:"definitionName"`
+
+	// Revision number of the snapshot. The revisions of a definition are
+	// numbered from 1.
+	Revision int64 `json:"revision"`
+
+	// RevisionHash identifies the spec of the snapshot.
+	RevisionHash string `json:"revisionHash"`
+
+	// WorkloadDefinition is the spec of a WorkloadDefinition.
+	// +optional
+	WorkloadDefinition *WorkloadDefinitionSpec `json:"workloadDefinition,omitempty"`
+
+	// TraitDefinition is the spec of a TraitDefinition.
+	// +optional
+	TraitDefinition *TraitDefinitionSpec `json:"traitDefinition,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DefinitionRevision is an immutable snapshot of the spec of a
+// WorkloadDefinition or TraitDefinition. A new revision is created whenever
+// the spec of a definition changes, and ApplicationConfigurations record the
+// revisions of the definitions their workloads and traits were rendered
+// against. Revisions are deleted with their definition.
+// +kubebuilder:printcolumn:JSONPath=".spec.definitionKind",name=DEFINITION-KIND,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.definitionName",name=DEFINITION-NAME,type=string
+// +kubebuilder:printcolumn:JSONPath=".spec.revision",name=REVISION,type=integer
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,oam}
+type DefinitionRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DefinitionRevisionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DefinitionRevisionList contains a list of DefinitionRevision.
+type DefinitionRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DefinitionRevision `json:"items"`
+}
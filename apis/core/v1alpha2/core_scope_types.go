@@ -18,6 +18,7 @@ package v1alpha2
 
 import (
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
@@ -40,11 +41,25 @@ var _ oam.Scope = &HealthScope{}
 // A HealthScopeSpec defines the desired state of a HealthScope.
 type HealthScopeSpec struct {
 	// ProbeTimeout is the amount of time in seconds to wait when receiving a response before marked failure.
+	// Deprecated: Use Timeout instead.
 	ProbeTimeout *int32 `json:"probe-timeout,omitempty"`
 
 	// ProbeInterval is the amount of time in seconds between probing tries.
+	// Deprecated: Use Interval instead.
 	ProbeInterval *int32 `json:"probe-interval,omitempty"`
 
+	// Timeout of checking the health of the workloads in this scope, e.g.
+	// 5s. Workloads whose health can't be checked in time are unhealthy.
+	// Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"probeTimeout,omitempty"`
+
+	// Interval between checks of the health of the workloads in this scope,
+	// e.g. 10s for latency-sensitive applications or 10m for batch
+	// workloads. Defaults to 1m.
+	// +optional
+	Interval *metav1.Duration `json:"probeInterval,omitempty"`
+
 	// WorkloadReferences to the workloads that are in this scope.
 	WorkloadReferences []runtimev1alpha1.TypedReference `json:"workloadRefs"`
 }
@@ -102,3 +117,281 @@ type HealthScopeList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []HealthScope `json:"items"`
 }
+
+var _ oam.Scope = &NetworkScope{}
+
+// A NetworkScopeSpec defines the desired state of a NetworkScope.
+type NetworkScopeSpec struct {
+	// WorkloadReferences to the workloads that are in this scope.
+	WorkloadReferences []runtimev1alpha1.TypedReference `json:"workloadRefs"`
+}
+
+// A NetworkScopeStatus represents the observed state of a NetworkScope.
+type NetworkScopeStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Workloads in this scope and the NetworkPolicies that place their pods
+	// into the network boundary of this scope.
+	Workloads []NetworkScopeWorkload `json:"workloads,omitempty"`
+}
+
+// A NetworkScopeWorkload represents a workload in a NetworkScope.
+type NetworkScopeWorkload struct {
+	// TargetWorkload is the workload in the scope.
+	TargetWorkload runtimev1alpha1.TypedReference `json:"targetWorkload"`
+
+	// NetworkPolicy that selects the pods of the workload. It is empty if
+	// the pods of the workload could not be determined.
+	NetworkPolicy string `json:"networkPolicy,omitempty"`
+
+	// Diagnosis why the pods of the workload could not be determined.
+	Diagnosis string `json:"diagnosis,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A NetworkScope places the pods of its workloads into a shared network
+// boundary. Pods of the workloads in the scope accept ingress traffic from
+// each other only.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type NetworkScope struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkScopeSpec   `json:"spec,omitempty"`
+	Status NetworkScopeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NetworkScopeList contains a list of NetworkScope.
+type NetworkScopeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkScope `json:"items"`
+}
+
+var _ oam.Scope = &ResourceQuotaScope{}
+
+// A ResourceQuotaScopeSpec defines the desired state of a
+// ResourceQuotaScope.
+type ResourceQuotaScopeSpec struct {
+	// Hard limits of the compute resources the workloads in this scope may
+	// request in aggregate. Supported resources are requests.cpu,
+	// requests.memory, limits.cpu and limits.memory. Like in a
+	// ResourceQuota, cpu and memory are synonyms of requests.cpu and
+	// requests.memory.
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+
+	// WorkloadReferences to the workloads that are in this scope.
+	WorkloadReferences []runtimev1alpha1.TypedReference `json:"workloadRefs"`
+}
+
+// A ResourceQuotaScopeStatus represents the observed state of a
+// ResourceQuotaScope.
+type ResourceQuotaScopeStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Hard limits enforced by this scope, normalized to the supported
+	// resources.
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+
+	// Used compute resources of the workloads in this scope.
+	Used corev1.ResourceList `json:"used,omitempty"`
+
+	// Exceeded resources, whose use exceeds their hard limit.
+	Exceeded []corev1.ResourceName `json:"exceeded,omitempty"`
+
+	// Workloads in this scope and the compute resources they use.
+	Workloads []ResourceQuotaScopeWorkload `json:"workloads,omitempty"`
+}
+
+// A ResourceQuotaScopeWorkload represents a workload in a
+// ResourceQuotaScope.
+type ResourceQuotaScopeWorkload struct {
+	// TargetWorkload is the workload in the scope.
+	TargetWorkload runtimev1alpha1.TypedReference `json:"targetWorkload"`
+
+	// Used compute resources of the workload. It is empty if the pods of
+	// the workload could not be determined.
+	Used corev1.ResourceList `json:"used,omitempty"`
+
+	// Diagnosis why the pods of the workload could not be determined.
+	Diagnosis string `json:"diagnosis,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ResourceQuotaScope limits the compute resources its workloads may
+// request in aggregate, and reports their utilization.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type ResourceQuotaScope struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ResourceQuotaScopeSpec   `json:"spec,omitempty"`
+	Status ResourceQuotaScopeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ResourceQuotaScopeList contains a list of ResourceQuotaScope.
+type ResourceQuotaScopeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourceQuotaScope `json:"items"`
+}
+
+var _ oam.Scope = &SecurityScope{}
+
+// A SecurityScopeSpec defines the desired state of a SecurityScope.
+type SecurityScopeSpec struct {
+	// RunAsNonRoot requires the containers of the workloads in this scope to
+	// run as a non-root user.
+	// +optional
+	RunAsNonRoot bool `json:"runAsNonRoot,omitempty"`
+
+	// SeccompProfile the pods of the workloads in this scope run with, e.g.
+	// runtime/default.
+	// +optional
+	SeccompProfile string `json:"seccompProfile,omitempty"`
+
+	// DropCapabilities the containers of the workloads in this scope must
+	// drop, e.g. ALL or NET_RAW.
+	// +optional
+	DropCapabilities []corev1.Capability `json:"dropCapabilities,omitempty"`
+
+	// WorkloadReferences to the workloads that are in this scope.
+	WorkloadReferences []runtimev1alpha1.TypedReference `json:"workloadRefs"`
+}
+
+// A SecurityScopeStatus represents the observed state of a SecurityScope.
+type SecurityScopeStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Workloads in this scope and whether they comply with the security
+	// profile of this scope.
+	Workloads []SecurityScopeWorkload `json:"workloads,omitempty"`
+}
+
+// A SecurityScopeWorkload represents a workload in a SecurityScope.
+type SecurityScopeWorkload struct {
+	// TargetWorkload is the workload in the scope.
+	TargetWorkload runtimev1alpha1.TypedReference `json:"targetWorkload"`
+
+	// Compliant is true if the pods of the workload comply with the security
+	// profile of the scope.
+	Compliant bool `json:"compliant"`
+
+	// Diagnosis why the workload does not comply with the security profile
+	// of the scope.
+	Diagnosis string `json:"diagnosis,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SecurityScope enforces a security profile on the pods of its workloads,
+// and reports the workloads that don't comply with it.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type SecurityScope struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecurityScopeSpec   `json:"spec,omitempty"`
+	Status SecurityScopeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SecurityScopeList contains a list of SecurityScope.
+type SecurityScopeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SecurityScope `json:"items"`
+}
+
+var _ oam.Scope = &PlacementScope{}
+
+// A PlacementZone is a zone the pods of the workloads in a PlacementScope may
+// be placed in.
+type PlacementZone struct {
+	// Name of the zone, i.e. the value of the topology key of its nodes.
+	Name string `json:"name"`
+
+	// Weight of the zone when distributing the replicas of the workloads. The
+	// scheduler prefers zones of higher weight. Zones of weight 0 are used,
+	// but not preferred.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// A PlacementScopeSpec defines the desired state of a PlacementScope.
+type PlacementScopeSpec struct {
+	// Clusters the workloads in this scope are applied to, by name. The
+	// workloads are applied to every cluster if this is empty. A runtime only
+	// applies them if the name of the cluster it runs in is listed.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// TopologyKey is the node label that tells the zone of a node. Defaults to
+	// topology.kubernetes.io/zone.
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// Zones the pods of the workloads in this scope are placed in. Pods may be
+	// placed in any zone if this is empty.
+	// +optional
+	Zones []PlacementZone `json:"zones,omitempty"`
+
+	// WorkloadReferences to the workloads that are in this scope.
+	WorkloadReferences []runtimev1alpha1.TypedReference `json:"workloadRefs"`
+}
+
+// A PlacementScopeStatus represents the observed state of a PlacementScope.
+type PlacementScopeStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Workloads in this scope and where they are placed.
+	Workloads []PlacementScopeWorkload `json:"workloads,omitempty"`
+}
+
+// A PlacementScopeWorkload represents a workload in a PlacementScope.
+type PlacementScopeWorkload struct {
+	// TargetWorkload is the workload in the scope.
+	TargetWorkload runtimev1alpha1.TypedReference `json:"targetWorkload"`
+
+	// Cluster the workload is applied to, if the runtime knows its name.
+	Cluster string `json:"cluster,omitempty"`
+
+	// Zones the pods of the workload are placed in.
+	Zones []string `json:"zones,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A PlacementScope decides the clusters its workloads are applied to, and the
+// zones their pods are spread across.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type PlacementScope struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PlacementScopeSpec   `json:"spec,omitempty"`
+	Status PlacementScopeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PlacementScopeList contains a list of PlacementScope.
+type PlacementScopeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlacementScope `json:"items"`
+}
@@ -18,12 +18,23 @@ package v1alpha2
 
 import (
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 )
 
 var _ oam.Trait = &ManualScalerTrait{}
+var _ oam.Trait = &AutoscalerTrait{}
+var _ oam.Trait = &IngressTrait{}
+var _ oam.Trait = &VolumeClaimTrait{}
+var _ oam.Trait = &MetricsTrait{}
+var _ oam.Trait = &RolloutTrait{}
+var _ oam.Trait = &SidecarTrait{}
+var _ oam.Trait = &EventScalerTrait{}
+var _ oam.Trait = &BackupTrait{}
 
 // A ManualScalerTraitSpec defines the desired state of a ManualScalerTrait.
 type ManualScalerTraitSpec struct {
@@ -61,3 +72,740 @@ type ManualScalerTraitList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []ManualScalerTrait `json:"items"`
 }
+
+// An AutoscalerMetric is a resource of the pods of a workload whose use
+// determines how many replicas the workload should have.
+type AutoscalerMetric struct {
+	// Resource whose use is measured; cpu or memory.
+	// +kubebuilder:validation:Enum=cpu;memory
+	Resource corev1.ResourceName `json:"resource"`
+
+	// TargetAverageUtilization of the resource across the pods of the
+	// workload, as a percentage of what they request.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetAverageUtilization *int32 `json:"targetAverageUtilization,omitempty"`
+
+	// TargetAverageValue of the resource across the pods of the workload.
+	// Takes precedence over TargetAverageUtilization.
+	// +optional
+	TargetAverageValue *resource.Quantity `json:"targetAverageValue,omitempty"`
+}
+
+// An AutoscalerTraitSpec defines the desired state of an AutoscalerTrait.
+type AutoscalerTraitSpec struct {
+	// MinReplicas the workload this trait applies to is scaled down to.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas the workload this trait applies to is scaled up to.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Metrics that determine how many replicas the workload should have.
+	// Defaults to an average CPU utilization of 80%.
+	// +optional
+	Metrics []AutoscalerMetric `json:"metrics,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// An AutoscalerTraitStatus represents the observed state of an
+// AutoscalerTrait.
+type AutoscalerTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// ScaleTargetReference to the resource the HorizontalPodAutoscaler of
+	// this trait scales.
+	// +optional
+	ScaleTargetReference *runtimev1alpha1.TypedReference `json:"scaleTargetRef,omitempty"`
+
+	// CurrentReplicas of the scaled resource, as last observed by the
+	// HorizontalPodAutoscaler.
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// DesiredReplicas of the scaled resource, as last computed by the
+	// HorizontalPodAutoscaler.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An AutoscalerTrait scales a workload between a minimum and maximum number
+// of replicas by the use of the resources of its pods, through a
+// HorizontalPodAutoscaler.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.minReplicas",name=MIN,type=integer
+// +kubebuilder:printcolumn:JSONPath=".spec.maxReplicas",name=MAX,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.currentReplicas",name=REPLICAS,type=integer
+type AutoscalerTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AutoscalerTraitSpec   `json:"spec,omitempty"`
+	Status AutoscalerTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AutoscalerTraitList contains a list of AutoscalerTrait.
+type AutoscalerTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AutoscalerTrait `json:"items"`
+}
+
+// An IngressRule routes the requests for a host and path to a workload.
+type IngressRule struct {
+	// Host whose requests are routed. Requests for all hosts are routed if it
+	// is omitted.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Path whose requests are routed. Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// ServicePort of the Service of the workload the requests are routed to.
+	// Defaults to the first port of the Service.
+	// +optional
+	ServicePort *int32 `json:"servicePort,omitempty"`
+}
+
+// An IngressTraitSpec defines the desired state of an IngressTrait.
+type IngressTraitSpec struct {
+	// Rules that route requests to the workload.
+	// +kubebuilder:validation:MinItems=1
+	Rules []IngressRule `json:"rules"`
+
+	// IngressClassName of the IngressClass of the controller that implements
+	// the Ingress of this trait.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// TLSSecretName of the Secret whose certificate terminates TLS for the
+	// hosts of the rules.
+	// +optional
+	TLSSecretName *string `json:"tlsSecretName,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// An IngressTraitStatus represents the observed state of an IngressTrait.
+type IngressTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// ServiceReference to the Service of the workload the requests are
+	// routed to.
+	// +optional
+	ServiceReference *runtimev1alpha1.TypedReference `json:"serviceRef,omitempty"`
+
+	// Addresses, i.e. IPs or hostnames, the Ingress of this trait is
+	// reachable at.
+	// +optional
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An IngressTrait exposes a workload outside of the cluster, by routing the
+// requests for its rules to the Service of the workload through an Ingress.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type IngressTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IngressTraitSpec   `json:"spec,omitempty"`
+	Status IngressTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IngressTraitList contains a list of IngressTrait.
+type IngressTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IngressTrait `json:"items"`
+}
+
+// A VolumeClaim requests a PersistentVolumeClaim that is mounted into the
+// containers of a workload.
+type VolumeClaim struct {
+	// Name of this claim. Must be unique within its trait.
+	Name string `json:"name"`
+
+	// MountPath at which the volume of this claim will be mounted within the
+	// containers.
+	MountPath string `json:"mountPath"`
+
+	// Containers the volume of this claim is mounted into. It is mounted into
+	// all containers of the workload if omitted.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+
+	// AccessMode of the volume of this claim; RO (read only) or RW (read and
+	// write).
+	// +optional
+	// +kubebuilder:validation:Enum=RO;RW
+	AccessMode *VolumeAccessMode `json:"accessMode,omitempty"`
+
+	// SharingPolicy of the volume of this claim; Exclusive or Shared.
+	// +optional
+	// +kubebuilder:validation:Enum=Exclusive;Shared
+	SharingPolicy *VolumeSharingPolicy `json:"sharingPolicy,omitempty"`
+
+	// Required disk space.
+	Required resource.Quantity `json:"required"`
+
+	// StorageClass of the PersistentVolumeClaim. The default storage class is
+	// used if it is omitted.
+	// +optional
+	StorageClass *string `json:"storageClass,omitempty"`
+}
+
+// A VolumeClaimTraitSpec defines the desired state of a VolumeClaimTrait.
+type VolumeClaimTraitSpec struct {
+	// Claims of PersistentVolumeClaims mounted into the workload.
+	// +kubebuilder:validation:MinItems=1
+	Claims []VolumeClaim `json:"claims"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// A VolumeClaimTraitStatus represents the observed state of a
+// VolumeClaimTrait.
+type VolumeClaimTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Resources managed by this volume claim trait.
+	// +optional
+	Resources []runtimev1alpha1.TypedReference `json:"resources,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A VolumeClaimTrait attaches storage to a workload, by creating
+// PersistentVolumeClaims and mounting them into the pod template found at the
+// podSpecPath of the WorkloadDefinition of the workload.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type VolumeClaimTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VolumeClaimTraitSpec   `json:"spec,omitempty"`
+	Status VolumeClaimTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VolumeClaimTraitList contains a list of VolumeClaimTrait.
+type VolumeClaimTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VolumeClaimTrait `json:"items"`
+}
+
+// A MonitorKind is the kind of the Prometheus Operator monitor that scrapes
+// the metrics of a workload.
+type MonitorKind string
+
+// Monitor kinds.
+const (
+	// MonitorKindServiceMonitor scrapes the pods behind the Service of a
+	// workload.
+	MonitorKindServiceMonitor MonitorKind = "ServiceMonitor"
+
+	// MonitorKindPodMonitor scrapes the pods of a workload directly.
+	MonitorKindPodMonitor MonitorKind = "PodMonitor"
+)
+
+// A MetricsTraitSpec defines the desired state of a MetricsTrait.
+type MetricsTraitSpec struct {
+	// Port of the pods of the workload that serves the metrics, by name or
+	// number.
+	Port intstr.IntOrString `json:"port"`
+
+	// Path the metrics are served at. Defaults to /metrics.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Scheme the metrics are served with; http or https. Defaults to http.
+	// +optional
+	// +kubebuilder:validation:Enum=http;https
+	Scheme string `json:"scheme,omitempty"`
+
+	// Interval at which the metrics are scraped, e.g. 30s. Defaults to the
+	// scrape interval of Prometheus.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// MonitorKind of the monitor that scrapes the metrics; ServiceMonitor or
+	// PodMonitor. Defaults to ServiceMonitor.
+	// +optional
+	// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor
+	MonitorKind MonitorKind `json:"monitorKind,omitempty"`
+
+	// AnnotatePods with the prometheus.io annotations, for Prometheus
+	// instances that discover the pods to scrape by their annotations.
+	// +optional
+	AnnotatePods bool `json:"annotatePods,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// A MetricsTraitStatus represents the observed state of a MetricsTrait.
+type MetricsTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// MonitorReference to the ServiceMonitor or PodMonitor of this trait.
+	// +optional
+	MonitorReference *runtimev1alpha1.TypedReference `json:"monitorRef,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A MetricsTrait makes the metrics of a workload observable, by scraping its
+// pods through a ServiceMonitor or PodMonitor of the Prometheus Operator.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type MetricsTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MetricsTraitSpec   `json:"spec,omitempty"`
+	Status MetricsTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MetricsTraitList contains a list of MetricsTrait.
+type MetricsTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MetricsTrait `json:"items"`
+}
+
+// A RolloutGate determines how a step of a rollout is promoted to the next
+// once its workloads are healthy.
+type RolloutGate string
+
+// Rollout gates.
+const (
+	// RolloutGateAuto promotes a step automatically.
+	RolloutGateAuto RolloutGate = "Auto"
+
+	// RolloutGateManual promotes a step once it is promoted by the
+	// promotion of the RolloutTrait.
+	RolloutGateManual RolloutGate = "Manual"
+)
+
+// A RolloutStep shifts a share of the replicas of a rollout to the workload
+// of the new component revision.
+type RolloutStep struct {
+	// Percent of the replicas that run the new component revision.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// Gate that promotes the step to the next once the workloads are
+	// healthy; Auto or Manual. Defaults to Auto.
+	// +optional
+	// +kubebuilder:validation:Enum=Auto;Manual
+	Gate RolloutGate `json:"gate,omitempty"`
+
+	// PauseSeconds to wait at the step before it is promoted.
+	// +optional
+	PauseSeconds int32 `json:"pauseSeconds,omitempty"`
+}
+
+// A RolloutPromotion promotes the steps of the rollout of a component
+// revision whose gate is Manual.
+type RolloutPromotion struct {
+	// RevisionName of the component revision whose rollout is promoted.
+	RevisionName string `json:"revisionName"`
+
+	// Steps of the rollout that are promoted, counted from the first.
+	Steps int32 `json:"steps"`
+}
+
+// A RolloutTraitSpec defines the desired state of a RolloutTrait.
+type RolloutTraitSpec struct {
+	// Replicas of the component, shared by the workloads of its new and
+	// previous revisions during a rollout.
+	Replicas int32 `json:"replicas"`
+
+	// Steps of the rollout. Defaults to a single step that shifts all the
+	// replicas to the new revision.
+	// +optional
+	Steps []RolloutStep `json:"steps,omitempty"`
+
+	// Promotion of the steps whose gate is Manual.
+	// +optional
+	Promotion *RolloutPromotion `json:"promotion,omitempty"`
+
+	// ProgressDeadlineSeconds a step may take for the workload of the new
+	// revision to become healthy before the rollout is rolled back. Defaults
+	// to 600.
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// Revisions of the component whose workloads are running, newest first.
+	// They are set by the ApplicationConfiguration controller, since the
+	// TraitDefinition of RolloutTraits specifies them as its revisionsPath.
+	// +optional
+	Revisions []WorkloadRevision `json:"revisions,omitempty"`
+
+	// WorkloadReference to the workload of the new component revision.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// A RolloutPhase is the phase of a rollout.
+type RolloutPhase string
+
+// Rollout phases.
+const (
+	// RolloutPhaseProgressing rollouts are shifting replicas to the new
+	// revision.
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+
+	// RolloutPhasePaused rollouts wait for a step to be promoted.
+	RolloutPhasePaused RolloutPhase = "Paused"
+
+	// RolloutPhaseSucceeded rollouts run all the replicas of the new
+	// revision.
+	RolloutPhaseSucceeded RolloutPhase = "Succeeded"
+
+	// RolloutPhaseRolledBack rollouts run all the replicas of the previous
+	// revision again, because the new revision didn't become healthy.
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+)
+
+// A RolloutTraitStatus represents the observed state of a RolloutTrait.
+type RolloutTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Phase of the rollout.
+	// +optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+
+	// TargetRevision is the name of the component revision rolled out.
+	// +optional
+	TargetRevision string `json:"targetRevision,omitempty"`
+
+	// SourceRevision is the name of the component revision rolled out from.
+	// +optional
+	SourceRevision string `json:"sourceRevision,omitempty"`
+
+	// CurrentStep is the index of the step the rollout is at.
+	// +optional
+	CurrentStep int32 `json:"currentStep,omitempty"`
+
+	// StepStartTime is the time the rollout reached the current step.
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+
+	// TargetReplicas of the workload of the target revision.
+	// +optional
+	TargetReplicas int32 `json:"targetReplicas,omitempty"`
+
+	// SourceReplicas of the workload of the source revision.
+	// +optional
+	SourceReplicas int32 `json:"sourceReplicas,omitempty"`
+
+	// Message describes why the rollout is paused or rolled back.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A RolloutTrait progressively shifts the replicas of a component from the
+// workload of its previous revision to the workload of its new revision, and
+// rolls back if the new revision doesn't become healthy.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=PHASE,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.targetRevision",name=TARGET,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.currentStep",name=STEP,type=integer
+type RolloutTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RolloutTraitSpec   `json:"spec,omitempty"`
+	Status RolloutTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RolloutTraitList contains a list of RolloutTrait.
+type RolloutTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RolloutTrait `json:"items"`
+}
+
+// A SidecarVolumeMount mounts a volume of the pod of a workload into its
+// sidecar.
+type SidecarVolumeMount struct {
+	// Name of the volume, e.g. one of a VolumeClaimTrait. An emptyDir volume
+	// of this name is added to the pod if it has none.
+	Name string `json:"name"`
+
+	// MountPath at which the volume will be mounted within the sidecar.
+	MountPath string `json:"mountPath"`
+
+	// ReadOnly mounts the volume read only.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// A SidecarTraitSpec defines the desired state of a SidecarTrait.
+type SidecarTraitSpec struct {
+	// Name of the sidecar container. Defaults to the name of the trait.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Image the sidecar should run.
+	Image string `json:"image"`
+
+	// ImagePullPolicy of the image of the sidecar; Always, IfNotPresent or
+	// Never.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy *ImagePullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Command to be run by the sidecar.
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// Arguments to be passed to the command run by the sidecar.
+	// +optional
+	Arguments []string `json:"args,omitempty"`
+
+	// Environment variables that should be set within the sidecar.
+	// +optional
+	Environment []ContainerEnvVar `json:"env,omitempty"`
+
+	// EnvFrom sets environment variables within the sidecar from all the keys
+	// of Secrets or ConfigMaps.
+	// +optional
+	EnvFrom []ContainerEnvFromSource `json:"envFrom,omitempty"`
+
+	// VolumeMounts of volumes of the pod into the sidecar.
+	// +optional
+	VolumeMounts []SidecarVolumeMount `json:"volumeMounts,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// A SidecarTraitStatus represents the observed state of a SidecarTrait.
+type SidecarTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+
+// A SidecarTrait injects a sidecar container into the pod template found at
+// the podSpecPath of the WorkloadDefinition of a workload.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type SidecarTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SidecarTraitSpec   `json:"spec,omitempty"`
+	Status SidecarTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SidecarTraitList contains a list of SidecarTrait.
+type SidecarTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SidecarTrait `json:"items"`
+}
+
+// An EventScalerTrigger scales a workload by the events of an event source,
+// e.g. the length of a queue.
+type EventScalerTrigger struct {
+	// Type of the KEDA scaler of this trigger, e.g. rabbitmq, kafka or
+	// prometheus.
+	Type string `json:"type"`
+
+	// Name of this trigger.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Metadata configuring the KEDA scaler of this trigger, e.g. the name of
+	// a queue and its target length.
+	Metadata map[string]string `json:"metadata"`
+
+	// AuthenticationRef is the name of a KEDA TriggerAuthentication in the
+	// namespace of the trait, that supplies the credentials of the event
+	// source.
+	// +optional
+	AuthenticationRef *string `json:"authenticationRef,omitempty"`
+}
+
+// An EventScalerTraitSpec defines the desired state of an EventScalerTrait.
+type EventScalerTraitSpec struct {
+	// MinReplicas the workload is scaled down to. Workloads are scaled to
+	// zero while there are no events if it is 0. Defaults to 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas the workload is scaled up to. Defaults to 100.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// PollingIntervalSeconds at which the triggers are checked. Defaults to
+	// 30.
+	// +optional
+	PollingIntervalSeconds *int32 `json:"pollingIntervalSeconds,omitempty"`
+
+	// CooldownPeriodSeconds to wait after the last event before the workload
+	// is scaled to zero. Defaults to 300.
+	// +optional
+	CooldownPeriodSeconds *int32 `json:"cooldownPeriodSeconds,omitempty"`
+
+	// Triggers that scale the workload.
+	// +kubebuilder:validation:MinItems=1
+	Triggers []EventScalerTrigger `json:"triggers"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// An EventScalerTraitStatus represents the observed state of an
+// EventScalerTrait.
+type EventScalerTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// ScaleTargetReference to the resource the ScaledObject of this trait
+	// scales.
+	// +optional
+	ScaleTargetReference *runtimev1alpha1.TypedReference `json:"scaleTargetRef,omitempty"`
+
+	// ScaledObjectReference to the KEDA ScaledObject of this trait.
+	// +optional
+	ScaledObjectReference *runtimev1alpha1.TypedReference `json:"scaledObjectRef,omitempty"`
+
+	// Active is true while the triggers of this trait have events, i.e. while
+	// the scaled resource is not scaled to zero by KEDA.
+	// +optional
+	Active bool `json:"active,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An EventScalerTrait scales a workload by the events of event sources, e.g.
+// queues, through a ScaledObject of KEDA.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".status.active",name=ACTIVE,type=boolean
+type EventScalerTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EventScalerTraitSpec   `json:"spec,omitempty"`
+	Status EventScalerTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EventScalerTraitList contains a list of EventScalerTrait.
+type EventScalerTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EventScalerTrait `json:"items"`
+}
+
+// A BackupTraitSpec defines the desired state of a BackupTrait.
+type BackupTraitSpec struct {
+	// Schedule of the backups in cron format, e.g. "0 2 * * *". The workload
+	// is backed up once if it is omitted.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// TTL of the backups, e.g. 720h, after which Velero deletes them.
+	// Defaults to the TTL of Velero.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// SnapshotVolumes of the workload along with its resources. Defaults to
+	// true.
+	// +optional
+	SnapshotVolumes *bool `json:"snapshotVolumes,omitempty"`
+
+	// StorageLocation is the name of the Velero BackupStorageLocation the
+	// backups are stored in. Defaults to the default location of Velero.
+	// +optional
+	StorageLocation string `json:"storageLocation,omitempty"`
+
+	// VolumeSnapshotLocations are the names of the Velero
+	// VolumeSnapshotLocations the snapshots of the volumes are stored in.
+	// +optional
+	VolumeSnapshotLocations []string `json:"volumeSnapshotLocations,omitempty"`
+
+	// WorkloadReference to the workload this trait applies to.
+	WorkloadReference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// A BackupTraitStatus represents the observed state of a BackupTrait.
+type BackupTraitStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// BackupReference to the Velero Backup or Schedule of this trait.
+	// +optional
+	BackupReference *runtimev1alpha1.TypedReference `json:"backupRef,omitempty"`
+
+	// Phase of the Velero Backup or Schedule of this trait.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// LastBackupTime is the time the last backup of the workload completed,
+	// or was started by the Schedule.
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A BackupTrait backs up the resources and volumes of a workload through a
+// Backup or Schedule of Velero.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.schedule",name=SCHEDULE,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.phase",name=PHASE,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.lastBackupTime",name=LAST-BACKUP,type=date
+type BackupTrait struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupTraitSpec   `json:"spec,omitempty"`
+	Status BackupTraitStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// BackupTraitList contains a list of BackupTrait.
+type BackupTraitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupTrait `json:"items"`
+}
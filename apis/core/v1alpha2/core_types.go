@@ -28,6 +28,82 @@ import (
 type DefinitionReference struct {
 	// Name of the referenced CustomResourceDefinition.
 	Name string `json:"name"`
+
+	// Version of the referenced CustomResourceDefinition that objects of the
+	// definition are created at, e.g. v1beta1. It must be a served version.
+	// Defaults to the storage version if it is served, otherwise to the first
+	// served version.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// A HealthConditionOperator compares a field of a resource.
+type HealthConditionOperator string
+
+// Health condition operators.
+const (
+	// HealthConditionEqual is met if the field equals the value.
+	HealthConditionEqual HealthConditionOperator = "Equal"
+	// HealthConditionNotEqual is met if the field exists and does not equal
+	// the value.
+	HealthConditionNotEqual HealthConditionOperator = "NotEqual"
+	// HealthConditionExists is met if the field exists.
+	HealthConditionExists HealthConditionOperator = "Exists"
+	// HealthConditionEqualField is met if the field equals the field the
+	// value is the path of, e.g. status.readyReplicas and spec.replicas.
+	HealthConditionEqualField HealthConditionOperator = "EqualField"
+	// HealthConditionTrue is met if the field is a list of conditions, e.g.
+	// status.conditions, with a condition of the value as type and status
+	// "True".
+	HealthConditionTrue HealthConditionOperator = "ConditionTrue"
+)
+
+// A HealthCondition is a condition a healthy resource meets.
+type HealthCondition struct {
+	// FieldPath of the field of the resource the condition is about, e.g.
+	// status.phase.
+	FieldPath string `json:"fieldPath"`
+
+	// Operator the field is compared with. Defaults to Equal.
+	// +optional
+	// +kubebuilder:validation:Enum=Equal;NotEqual;Exists;EqualField;ConditionTrue
+	Operator HealthConditionOperator `json:"operator,omitempty"`
+
+	// Value the field is compared to, e.g. Running.
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// A HealthPolicy determines the health of the resources of a definition from
+// their fields, so that their health is known without a bespoke controller.
+type HealthPolicy struct {
+	// Conditions a healthy resource meets, all of them.
+	// +optional
+	Conditions []HealthCondition `json:"conditions,omitempty"`
+
+	// CUE is a CUE expression that is filled with the resource as 'resource'.
+	// The resource is healthy if the expression evaluates 'healthy' to true,
+	// and its optional 'message' describes the health of the resource, e.g.
+	//
+	//	healthy: resource.status.readyReplicas == resource.spec.replicas
+	//	message: "Ready: \(resource.status.readyReplicas)"
+	//
+	// A resource is healthy if it meets both the conditions and the
+	// expression.
+	// +optional
+	CUE string `json:"cue,omitempty"`
+}
+
+// A StatusField is a fact the runtime extracts from the resources of a
+// definition into the status of ApplicationConfigurations, e.g. the external
+// IP of a service.
+type StatusField struct {
+	// Name of the fact, e.g. ip.
+	Name string `json:"name"`
+
+	// FieldPath of the fact in the resource, e.g.
+	// status.loadBalancer.ingress[0].ip.
+	FieldPath string `json:"fieldPath"`
 }
 
 // A ChildResourceKind defines a child Kubernetes resource kind with a selector
@@ -55,17 +131,125 @@ type WorkloadDefinitionSpec struct {
 	// +optional
 	RevisionLabel string `json:"revisionLabel,omitempty"`
 
+	// RevisionEnabled indicates that each revision of a component creates a
+	// new workload of this kind named after the revision, instead of updating
+	// the workload in place. Traits are applied to the workload of the active
+	// revision.
+	// +optional
+	RevisionEnabled bool `json:"revisionEnabled,omitempty"`
+
+	// RevisionHistoryLimit is the number of workloads of old revisions of a
+	// component that are retained when RevisionEnabled is true. The oldest
+	// ones are deleted first. All of them are retained if it is not set.
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
 	// PodSpecPath indicates where/if this workload has K8s podSpec field
 	// if one workload has podSpec, trait can do lot's of assumption such as port, env, volume fields.
+	// Traits such as sidecar injectors reach the pod spec of workloads of any
+	// kind through it, e.g. at spec.template.spec for a Deployment.
 	// +optional
 	PodSpecPath string `json:"podSpecPath,omitempty"`
 
+	// WorkloadNameTemplate is a Go template the names of the workloads of
+	// this kind are rendered from, unless they specify a name. The template
+	// can refer to {{.AppConfigName}}, {{.ComponentName}} and {{.RevisionName}}.
+	// +optional
+	WorkloadNameTemplate string `json:"workloadNameTemplate,omitempty"`
+
+	// Schematic defines how to render the workloads of this kind from a
+	// template. Workloads refer to this definition by the workload type label.
+	// +optional
+	Schematic *Schematic `json:"schematic,omitempty"`
+
+	// HealthPolicy determines the health of the workloads of this kind. The
+	// runtime reports it in the status of ApplicationConfigurations and
+	// HealthScopes.
+	// +optional
+	HealthPolicy *HealthPolicy `json:"healthPolicy,omitempty"`
+
+	// StatusFields are facts the runtime extracts from the workloads of this
+	// kind into the status of ApplicationConfigurations.
+	// +optional
+	StatusFields []StatusField `json:"statusFields,omitempty"`
+
+	// StatusMessage is a Go template the runtime renders a message of the
+	// workloads of this kind from, into the status of ApplicationConfigurations.
+	// The template can refer to the status fields by name, e.g.
+	// "{{.ready}}/{{.replicas}} replicas ready".
+	// +optional
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	// Deprecated definitions can still be used, but the admission webhook
+	// warns about, or is configured to reject, Components that start using
+	// them.
+	// +optional
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// ReplacedBy is the name of the definition that replaces this deprecated
+	// definition.
+	// +optional
+	ReplacedBy string `json:"replacedBy,omitempty"`
+
 	// Extension is used for extension needs by OAM platform builders
 	// +optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Extension *runtime.RawExtension `json:"extension,omitempty"`
 }
 
+// A Schematic defines a template that a workload is rendered from.
+type Schematic struct {
+	// CUE defines a CUE template the workload is rendered from.
+	// +optional
+	CUE *CUE `json:"cue,omitempty"`
+
+	// GoTemplate defines a Go template the workload is rendered from. It is
+	// ignored if a CUE template is defined.
+	// +optional
+	GoTemplate *GoTemplate `json:"goTemplate,omitempty"`
+}
+
+// A CUE defines a CUE template. The 'parameter' field of the template is
+// filled with the spec of the workload, and the 'output' field of the template
+// is rendered as the workload.
+type CUE struct {
+	// Template is the CUE source of the template.
+	Template string `json:"template"`
+}
+
+// A GoTemplate defines a Go text template. The template is executed with the
+// parameter values of the component as data, and its result is parsed as the
+// YAML of the workload.
+type GoTemplate struct {
+	// Template is the source of the template.
+	Template string `json:"template"`
+}
+
+// A DefinitionStatus is the observed state of a WorkloadDefinition or
+// TraitDefinition.
+type DefinitionStatus struct {
+	// UsageCount is the number of Components (of a WorkloadDefinition) or
+	// ApplicationConfigurations (of a TraitDefinition) that use the
+	// definition.
+	UsageCount int32 `json:"usageCount"`
+
+	// UsedBy are the namespaced names of the first Components or
+	// ApplicationConfigurations that use the definition.
+	// +optional
+	UsedBy []string `json:"usedBy,omitempty"`
+
+	// LatestRevision is the DefinitionRevision that snapshots the current
+	// spec of the definition.
+	// +optional
+	LatestRevision *Revision `json:"latestRevision,omitempty"`
+
+	// Schema is the JSON schema of the resources of the definition, read
+	// from the served version of the CustomResourceDefinition it refers to.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Schema *runtime.RawExtension `json:"schema,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // A WorkloadDefinition registers a kind of Kubernetes custom resource as a
@@ -73,12 +257,15 @@ type WorkloadDefinitionSpec struct {
 // is used to validate the schema of the workload when it is embedded in an OAM
 // Component.
 // +kubebuilder:printcolumn:JSONPath=".spec.definitionRef.name",name=DEFINITION-NAME,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.usageCount",name=USED-BY,type=integer
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,oam}
+// +kubebuilder:subresource:status
 type WorkloadDefinition struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec WorkloadDefinitionSpec `json:"spec,omitempty"`
+	Spec   WorkloadDefinitionSpec `json:"spec,omitempty"`
+	Status DefinitionStatus       `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -95,21 +282,96 @@ type TraitDefinitionSpec struct {
 	// Reference to the CustomResourceDefinition that defines this trait kind.
 	Reference DefinitionReference `json:"definitionRef"`
 
-	// Revision indicates whether a trait is aware of component revision
+	// RevisionEnabled indicates that each revision of a component creates a
+	// new workload, and a new instance of each trait of this kind that is
+	// named with the suffix of the revision, rather than updating them in
+	// place.
 	// +optional
 	RevisionEnabled bool `json:"revisionEnabled,omitempty"`
 
-	// WorkloadRefPath indicates where/if a trait accepts a workloadRef object
+	// WorkloadRefPath indicates where/if a trait accepts a workloadRef object.
+	// A path suffixed with [], e.g. spec.workloadRefs[], refers to a list
+	// that the workloadRef object is appended to.
 	// +optional
 	WorkloadRefPath string `json:"workloadRefPath,omitempty"`
 
+	// WorkloadRefPaths are further paths a trait accepts a workloadRef object
+	// at, for traits that take more than one reference to their workload.
+	// Paths are of the same form as WorkloadRefPath.
+	// +optional
+	WorkloadRefPaths []string `json:"workloadRefPaths,omitempty"`
+
+	// RevisionsPath indicates where/if a trait accepts the revisions of its
+	// component whose workloads are running, newest first. Traffic traits use
+	// them to split traffic between the revisions of a component.
+	// +optional
+	RevisionsPath string `json:"revisionsPath,omitempty"`
+
 	// AppliesToWorkloads specifies the list of workload kinds this trait
-	// applies to. Workload kinds are specified in kind.group/version format,
-	// e.g. server.core.oam.dev/v1alpha2. Traits that omit this field apply to
-	// all workload kinds.
+	// applies to. Workload kinds are specified in kind.group/version or
+	// kind.group format, e.g. server.core.oam.dev/v1alpha2, or by the name of
+	// their WorkloadDefinition, e.g. containerizedworkloads.core.oam.dev.
+	// Entries may contain wildcards, e.g. *.core.oam.dev. Traits that omit
+	// this field apply to all workload kinds.
 	// +optional
 	AppliesToWorkloads []string `json:"appliesToWorkloads,omitempty"`
 
+	// ConflictsWith specifies the list of traits that can not be applied to
+	// the same workload as this trait. Traits are specified by the name of
+	// their TraitDefinition or CustomResourceDefinition, e.g.
+	// autoscalers.core.oam.dev, by API group, e.g. *.networking.k8s.io, or by
+	// a selector of the labels of their TraitDefinition prefixed with
+	// labelSelector:, e.g. labelSelector:scaler=true. Traits that omit this
+	// field conflict with no other traits.
+	// +optional
+	ConflictsWith []string `json:"conflictsWith,omitempty"`
+
+	// AllowMultiple specifies whether more than one trait of this kind may be
+	// applied to the same workload of a component.
+	// +optional
+	AllowMultiple bool `json:"allowMultiple,omitempty"`
+
+	// Aliases are short names that ApplicationConfigurations may use to
+	// refer to this trait kind, e.g. scaler. Aliases must be unique across
+	// all TraitDefinitions.
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
+
+	// HealthPolicy determines the health of the traits of this kind. The
+	// runtime reports it in the status of ApplicationConfigurations.
+	// +optional
+	HealthPolicy *HealthPolicy `json:"healthPolicy,omitempty"`
+
+	// ManageWorkload specifies that the controller of the traits of this
+	// kind, e.g. a rollout trait, creates and updates the workload they apply
+	// to. The runtime renders the workload and hands it to the trait in the
+	// app.oam.dev/managed-workload annotation instead of applying it.
+	// +optional
+	ManageWorkload bool `json:"manageWorkload,omitempty"`
+
+	// StatusFields are facts the runtime extracts from the traits of this
+	// kind into the status of ApplicationConfigurations.
+	// +optional
+	StatusFields []StatusField `json:"statusFields,omitempty"`
+
+	// StatusMessage is a Go template the runtime renders a message of the
+	// traits of this kind from, into the status of ApplicationConfigurations.
+	// The template can refer to the status fields by name, e.g.
+	// "{{.ready}}/{{.replicas}} replicas ready".
+	// +optional
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	// Deprecated definitions can still be used, but the admission webhook
+	// warns about, or is configured to reject, ApplicationConfigurations that start using
+	// them.
+	// +optional
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// ReplacedBy is the name of the definition that replaces this deprecated
+	// definition.
+	// +optional
+	ReplacedBy string `json:"replacedBy,omitempty"`
+
 	// Extension is used for extension needs by OAM platform builders
 	// +optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -123,12 +385,15 @@ type TraitDefinitionSpec struct {
 // to validate the schema of the trait when it is embedded in an OAM
 // ApplicationConfiguration.
 // +kubebuilder:printcolumn:JSONPath=".spec.definitionRef.name",name=DEFINITION-NAME,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.usageCount",name=USED-BY,type=integer
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,oam}
+// +kubebuilder:subresource:status
 type TraitDefinition struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec TraitDefinitionSpec `json:"spec,omitempty"`
+	Spec   TraitDefinitionSpec `json:"spec,omitempty"`
+	Status DefinitionStatus    `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -152,6 +417,13 @@ type ScopeDefinitionSpec struct {
 	// multiple instances of this kind of scope.
 	AllowComponentOverlap bool `json:"allowComponentOverlap"`
 
+	// AppliesToWorkloads specifies the list of workload kinds that may join
+	// this kind of scope, in the formats of the appliesToWorkloads of a
+	// TraitDefinition, e.g. containerizedworkloads.core.oam.dev. All workload
+	// kinds may join scopes that omit this field.
+	// +optional
+	AppliesToWorkloads []string `json:"appliesToWorkloads,omitempty"`
+
 	// Extension is used for extension needs by OAM platform builders
 	// +optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -192,8 +464,10 @@ type ComponentParameter struct {
 	// that will be overwritten by the value of this parameter. The type of the
 	// parameter (e.g. int, string) is inferred from the type of these fields;
 	// All fields must be of the same type. Fields are specified as JSON field
-	// paths without a leading dot, for example 'spec.replicas'.
-	FieldPaths []string `json:"fieldPaths"`
+	// paths without a leading dot, for example 'spec.replicas'. Parameters of
+	// components rendered from a Go template don't need any field paths.
+	// +optional
+	FieldPaths []string `json:"fieldPaths,omitempty"`
 
 	// +kubebuilder:default:=false
 	// Required specifies whether or not a value for this parameter must be
@@ -201,9 +475,95 @@ type ComponentParameter struct {
 	// +optional
 	Required *bool `json:"required,omitempty"`
 
+	// Default value of this parameter. It is used when an
+	// ApplicationConfiguration does not supply a value for this parameter.
+	// +optional
+	Default *intstr.IntOrString `json:"default,omitempty"`
+
 	// Description of this parameter.
 	// +optional
 	Description *string `json:"description,omitempty"`
+
+	// Transforms are applied in order to the value of this parameter before
+	// it is written to its field paths.
+	// +optional
+	Transforms []ParameterTransform `json:"transforms,omitempty"`
+
+	// Type of the values of this parameter. Values of any type are accepted
+	// if it is not specified.
+	// +kubebuilder:validation:Enum=string;integer;boolean
+	// +optional
+	Type ParameterType `json:"type,omitempty"`
+
+	// Enum specifies the values this parameter may be assigned. Any value is
+	// accepted if it is empty.
+	// +optional
+	Enum []intstr.IntOrString `json:"enum,omitempty"`
+
+	// Minimum value of this integer parameter.
+	// +optional
+	Minimum *int64 `json:"minimum,omitempty"`
+
+	// Maximum value of this integer parameter.
+	// +optional
+	Maximum *int64 `json:"maximum,omitempty"`
+}
+
+// A ParameterType is the type of the values of a ComponentParameter.
+type ParameterType string
+
+// Parameter types.
+const (
+	// StringParameterType parameters accept string values.
+	StringParameterType ParameterType = "string"
+
+	// IntegerParameterType parameters accept integer values.
+	IntegerParameterType ParameterType = "integer"
+
+	// BooleanParameterType parameters accept the string values "true" and
+	// "false".
+	BooleanParameterType ParameterType = "boolean"
+)
+
+// A ParameterTransformType is the type of a ParameterTransform.
+type ParameterTransformType string
+
+// Parameter transform types.
+const (
+	// Base64EncodeTransformType transforms a string into its base64 encoding.
+	Base64EncodeTransformType ParameterTransformType = "base64enc"
+
+	// Base64DecodeTransformType transforms a base64 encoded string into the
+	// string it encodes.
+	Base64DecodeTransformType ParameterTransformType = "base64dec"
+
+	// ToIntTransformType transforms a string into an integer.
+	ToIntTransformType ParameterTransformType = "toInt"
+
+	// ToStringTransformType transforms an integer into a string.
+	ToStringTransformType ParameterTransformType = "toString"
+
+	// PrintfTransformType transforms a value into a string by formatting it
+	// using the format of the transform, for example 'nginx:%s'.
+	PrintfTransformType ParameterTransformType = "printf"
+
+	// LowerTransformType transforms a string into lower case.
+	LowerTransformType ParameterTransformType = "lower"
+
+	// UpperTransformType transforms a string into upper case.
+	UpperTransformType ParameterTransformType = "upper"
+)
+
+// A ParameterTransform transforms the value of a parameter.
+type ParameterTransform struct {
+	// Type of the transform.
+	// +kubebuilder:validation:Enum=base64enc;base64dec;toInt;toString;printf;lower;upper
+	Type ParameterTransformType `json:"type"`
+
+	// Format used by printf transforms. It must contain exactly one verb,
+	// which is replaced by the value of the parameter.
+	// +optional
+	Format string `json:"format,omitempty"`
 }
 
 // A ComponentSpec defines the desired state of a Component.
@@ -216,11 +576,42 @@ type ComponentSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Workload runtime.RawExtension `json:"workload"`
 
+	// AuxiliaryWorkloads that will be created alongside the workload of this
+	// component, for example the ConfigMap a Deployment mounts. Parameters
+	// and schematics only apply to the main workload.
+	// +optional
+	AuxiliaryWorkloads []AuxiliaryWorkload `json:"auxiliaryWorkloads,omitempty"`
+
 	// Parameters exposed by this component. ApplicationConfigurations that
 	// reference this component may specify values for these parameters, which
 	// will in turn be injected into the embedded workload.
 	// +optional
 	Parameters []ComponentParameter `json:"parameters,omitempty"`
+
+	// Schematic defines how to render the workload of this component from a
+	// template. It takes precedence over the schematic of the WorkloadDefinition.
+	// +optional
+	Schematic *Schematic `json:"schematic,omitempty"`
+
+	// RevisionHistoryLimit is the maximum number of revisions of this
+	// component that are retained. It overrides the revision limit of the
+	// controller. Changing it does not create a new revision.
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// An AuxiliaryWorkload is an additional named workload of a component.
+type AuxiliaryWorkload struct {
+	// Name of the auxiliary workload, unique within its component. Traits
+	// refer to an auxiliary workload by this name.
+	Name string `json:"name"`
+
+	// A Workload that will be created alongside the main workload of the
+	// component. If it has no metadata name it is named after the main
+	// workload, suffixed by the name of the auxiliary workload.
+	// +kubebuilder:validation:EmbeddedResource
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Workload runtime.RawExtension `json:"workload"`
 }
 
 // A ComponentStatus represents the observed state of a Component.
@@ -235,6 +626,19 @@ type ComponentStatus struct {
 	// +optional
 	LatestRevision *Revision `json:"latestRevision,omitempty"`
 
+	// RevisionDiff summarizes how the latest revision of the component
+	// differs from the revision it succeeds.
+	// +optional
+	RevisionDiff *RevisionDiff `json:"revisionDiff,omitempty"`
+
+	// ParameterSchema is the JSON schema of the parameter values an
+	// ApplicationConfiguration may supply to the component. The schema of a
+	// parameter is derived from its declaration and from the schema of the
+	// fields of the workload it overwrites.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ParameterSchema *runtime.RawExtension `json:"parameterSchema,omitempty"`
+
 	// One Component should only be used by one AppConfig
 }
 
@@ -244,6 +648,20 @@ type Revision struct {
 	Revision int64  `json:"revision"`
 }
 
+// A RevisionDiff summarizes how a component revision differs from another.
+type RevisionDiff struct {
+	// From is the name of the revision that is compared against.
+	From string `json:"from"`
+
+	// To is the name of the revision that is compared.
+	To string `json:"to"`
+
+	// FieldPaths of the component spec that differ between the revisions,
+	// e.g. workload.spec.replicas or parameters.image.fieldPaths.
+	// +optional
+	FieldPaths []string `json:"fieldPaths,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // A Component describes how an OAM workload kind may be instantiated.
@@ -251,6 +669,7 @@ type Revision struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:JSONPath=".spec.workload.kind",name=WORKLOAD-KIND,type=string
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
 type Component struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -275,7 +694,27 @@ type ComponentParameterValue struct {
 	Name string `json:"name"`
 
 	// Value to set.
-	Value intstr.IntOrString `json:"value"`
+	// +optional
+	Value intstr.IntOrString `json:"value,omitempty"`
+
+	// ValueFrom sets the value from a field of the rendered workload of
+	// another component of the same ApplicationConfiguration. It takes
+	// precedence over Value.
+	// +optional
+	ValueFrom *ComponentParameterValueFrom `json:"valueFrom,omitempty"`
+}
+
+// A ComponentParameterValueFrom refers to a field of the rendered workload of
+// a component.
+type ComponentParameterValueFrom struct {
+	// FromComponent is the name of the component of the same
+	// ApplicationConfiguration the value is read from. It is rendered before
+	// the component that refers to it.
+	FromComponent string `json:"fromComponent"`
+
+	// FieldPath of the value within the rendered workload of the component,
+	// for example 'metadata.name'. The value must be a string or an integer.
+	FieldPath string `json:"fieldPath"`
 }
 
 // A ComponentTrait specifies a trait that should be applied to a component.
@@ -285,6 +724,12 @@ type ComponentTrait struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Trait runtime.RawExtension `json:"trait"`
 
+	// WorkloadName is the name of the auxiliary workload of the component
+	// this trait applies to. The trait applies to the main workload of the
+	// component if it is empty.
+	// +optional
+	WorkloadName string `json:"workloadName,omitempty"`
+
 	// DataOutputs specify the data output sources from this trait.
 	// +optional
 	DataOutputs []DataOutput `json:"dataOutputs,omitempty"`
@@ -334,6 +779,38 @@ type ApplicationConfigurationComponent struct {
 	// Scopes in which the specified component should exist.
 	// +optional
 	Scopes []ComponentScope `json:"scopes,omitempty"`
+
+	// Patches are applied in order to the rendered workload of the specified
+	// component before it is applied, so that a shared component can be
+	// tweaked without forking it.
+	// +optional
+	Patches []ComponentPatch `json:"patches,omitempty"`
+}
+
+// A PatchType is the type of a ComponentPatch.
+type PatchType string
+
+// Patch types.
+const (
+	// StrategicMergePatchType patches are partial workloads that are merged
+	// into the rendered workload. Workloads of kinds that have no patch
+	// strategy, e.g. custom resources, are patched as JSON merge patches.
+	StrategicMergePatchType PatchType = "StrategicMerge"
+
+	// JSON6902PatchType patches are lists of JSON patch operations as defined
+	// in RFC 6902.
+	JSON6902PatchType PatchType = "JSON6902"
+)
+
+// A ComponentPatch patches the rendered workload of a component.
+type ComponentPatch struct {
+	// Type of the patch.
+	// +kubebuilder:validation:Enum=StrategicMerge;JSON6902
+	Type PatchType `json:"type"`
+
+	// Patch to apply to the rendered workload.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Patch runtime.RawExtension `json:"patch"`
 }
 
 // An ApplicationConfigurationSpec defines the desired state of a
@@ -342,6 +819,72 @@ type ApplicationConfigurationSpec struct {
 	// Components of which this ApplicationConfiguration consists. Each
 	// component will be used to instantiate a workload.
 	Components []ApplicationConfigurationComponent `json:"components"`
+
+	// WorkloadNameTemplate is a Go template the names of the workloads of
+	// this ApplicationConfiguration are rendered from, unless they specify a
+	// name. It takes precedence over the template of the WorkloadDefinition.
+	// The template can refer to {{.AppConfigName}}, {{.ComponentName}} and
+	// {{.RevisionName}}.
+	// +optional
+	WorkloadNameTemplate string `json:"workloadNameTemplate,omitempty"`
+
+	// Environment this ApplicationConfiguration is deployed to. The overlays
+	// of this environment are applied to its components before they are
+	// rendered.
+	// +optional
+	Environment string `json:"environment,omitempty"`
+
+	// Overlays layer environment specific parameter values and traits onto
+	// the components of this ApplicationConfiguration, so that a single
+	// ApplicationConfiguration can be promoted across environments. Overlays
+	// of the same environment are applied in order.
+	// +optional
+	Overlays []ApplicationConfigurationOverlay `json:"overlays,omitempty"`
+
+	// ScopeTraits declare traits at the level of a scope. Each trait is
+	// applied to every component of this ApplicationConfiguration that is
+	// in the scope.
+	// +optional
+	ScopeTraits []ScopeTraits `json:"scopeTraits,omitempty"`
+}
+
+// An ApplicationConfigurationOverlay layers parameter values and traits onto
+// the components of an ApplicationConfiguration deployed to an environment.
+type ApplicationConfigurationOverlay struct {
+	// Environment to which this overlay applies.
+	Environment string `json:"environment"`
+
+	// Components to which this overlay applies.
+	Components []ComponentOverlay `json:"components"`
+}
+
+// A ComponentOverlay layers parameter values and traits onto a component of
+// an ApplicationConfiguration.
+type ComponentOverlay struct {
+	// ComponentName of the component to which this overlay applies.
+	ComponentName string `json:"componentName"`
+
+	// ParameterValues replace the parameter values of the same name of the
+	// component, or are added to them.
+	// +optional
+	ParameterValues []ComponentParameterValue `json:"parameterValues,omitempty"`
+
+	// Traits are merged as JSON merge patches into the traits of the same
+	// apiVersion, kind and name of the component, or are added to them.
+	// +optional
+	Traits []ComponentTrait `json:"traits,omitempty"`
+}
+
+// ScopeTraits are applied to every component of an ApplicationConfiguration
+// that is in a scope.
+type ScopeTraits struct {
+	// A ScopeReference must refer to an OAM scope resource.
+	ScopeReference runtimev1alpha1.TypedReference `json:"scopeRef"`
+
+	// Traits applied to each component in the scope. A trait that is named is
+	// instantiated once per component, with the name of the component
+	// appended to its name.
+	Traits []ComponentTrait `json:"traits"`
 }
 
 // A TraitStatus represents the state of a trait.
@@ -358,6 +901,27 @@ type WorkloadTrait struct {
 
 	// Message will allow controller to leave some additional information for this trait
 	Message string `json:"message,omitempty"`
+	// HealthStatus of the trait according to the health policy of its
+	// TraitDefinition, if it has one.
+	// +optional
+	HealthStatus HealthStatus `json:"healthStatus,omitempty"`
+
+	// HealthDiagnosis describes why the trait is not healthy.
+	// +optional
+	HealthDiagnosis string `json:"healthDiagnosis,omitempty"`
+
+	// StatusFields extracted from the trait as its TraitDefinition specifies.
+	// +optional
+	StatusFields map[string]string `json:"statusFields,omitempty"`
+
+	// StatusMessage rendered from the status fields of the trait.
+	// +optional
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	// DefinitionRevisionName is the name of the DefinitionRevision of the
+	// TraitDefinition the trait was rendered against.
+	// +optional
+	DefinitionRevisionName string `json:"definitionRevisionName,omitempty"`
 }
 
 // A ScopeStatus represents the state of a scope.
@@ -371,6 +935,20 @@ type WorkloadScope struct {
 
 	// Reference to a scope created by an ApplicationConfiguration.
 	Reference runtimev1alpha1.TypedReference `json:"scopeRef"`
+
+	// HealthStatus of the workload the scope reports, e.g. the health a
+	// HealthScope determined for it.
+	// +optional
+	HealthStatus HealthStatus `json:"healthStatus,omitempty"`
+
+	// Diagnosis the scope reports for the workload, e.g. why it is not
+	// healthy.
+	// +optional
+	Diagnosis string `json:"diagnosis,omitempty"`
+
+	// Conditions of the scope, e.g. whether it synced successfully.
+	// +optional
+	Conditions []runtimev1alpha1.Condition `json:"conditions,omitempty"`
 }
 
 // A WorkloadStatus represents the status of a workload.
@@ -388,11 +966,59 @@ type WorkloadStatus struct {
 	// Reference to a workload created by an ApplicationConfiguration.
 	Reference runtimev1alpha1.TypedReference `json:"workloadRef,omitempty"`
 
+	// AuxiliaryWorkloads created alongside this workload.
+	AuxiliaryWorkloads []AuxiliaryWorkloadStatus `json:"auxiliaryWorkloads,omitempty"`
+
 	// Traits associated with this workload.
 	Traits []WorkloadTrait `json:"traits,omitempty"`
 
 	// Scopes associated with this workload.
 	Scopes []WorkloadScope `json:"scopes,omitempty"`
+
+	// Revisions of the component whose workloads are running, newest first.
+	// They are only recorded for revision enabled workloads.
+	// +optional
+	Revisions []WorkloadRevision `json:"revisions,omitempty"`
+	// HealthStatus of the workload according to the health policy of its
+	// WorkloadDefinition, if it has one.
+	// +optional
+	HealthStatus HealthStatus `json:"healthStatus,omitempty"`
+
+	// HealthDiagnosis describes why the workload is not healthy.
+	// +optional
+	HealthDiagnosis string `json:"healthDiagnosis,omitempty"`
+
+	// StatusFields extracted from the workload as its WorkloadDefinition
+	// specifies.
+	// +optional
+	StatusFields map[string]string `json:"statusFields,omitempty"`
+
+	// StatusMessage rendered from the status fields of the workload.
+	// +optional
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	// DefinitionRevisionName is the name of the DefinitionRevision of the
+	// WorkloadDefinition the workload was rendered against.
+	// +optional
+	DefinitionRevisionName string `json:"definitionRevisionName,omitempty"`
+}
+
+// A WorkloadRevision is a running workload of a component revision.
+type WorkloadRevision struct {
+	// RevisionName of the component revision the workload is rendered from.
+	RevisionName string `json:"revisionName"`
+
+	// Reference to the workload.
+	Reference runtimev1alpha1.TypedReference `json:"workloadRef"`
+}
+
+// An AuxiliaryWorkloadStatus represents the state of an auxiliary workload.
+type AuxiliaryWorkloadStatus struct {
+	// Name of the auxiliary workload within its component.
+	Name string `json:"name"`
+
+	// Reference to an auxiliary workload created by an ApplicationConfiguration.
+	Reference runtimev1alpha1.TypedReference `json:"workloadRef"`
 }
 
 // HistoryWorkload contain the old component revision that are still running
@@ -427,6 +1053,52 @@ type ApplicationConfigurationStatus struct {
 
 	// HistoryWorkloads will record history but still working revision workloads.
 	HistoryWorkloads []HistoryWorkload `json:"historyWorkloads,omitempty"`
+
+	// RenderDiff summarizes how the applied resources differ from the resources
+	// rendered from this ApplicationConfiguration. It is only computed when the
+	// app.oam.dev/render-diff annotation is "true", in which case the rendered
+	// resources are applied afterwards, or "dry-run", in which case they are
+	// not applied.
+	// +optional
+	RenderDiff *RenderDiff `json:"renderDiff,omitempty"`
+	// HealthStatus rolls up the health of the workloads and traits of this
+	// ApplicationConfiguration whose definitions have a health policy. It is
+	// UNHEALTHY if any of them is, and unset if none has a health policy.
+	// +optional
+	HealthStatus HealthStatus `json:"healthStatus,omitempty"`
+}
+
+// A DiffAction brings an applied resource in line with its rendered
+// counterpart.
+type DiffAction string
+
+// Diff actions.
+const (
+	DiffActionCreate DiffAction = "Create"
+	DiffActionUpdate DiffAction = "Update"
+	DiffActionDelete DiffAction = "Delete"
+)
+
+// A RenderDiff summarizes how the applied resources differ from the rendered
+// ones.
+type RenderDiff struct {
+	// Resources that differ between the applied and the rendered state.
+	// +optional
+	Resources []ResourceDiff `json:"resources,omitempty"`
+}
+
+// A ResourceDiff summarizes how an applied resource differs from its rendered
+// counterpart.
+type ResourceDiff struct {
+	// Reference to the resource.
+	Reference runtimev1alpha1.TypedReference `json:"ref"`
+
+	// Action that brings the applied resource in line with the rendered one.
+	Action DiffAction `json:"action"`
+
+	// FieldPaths whose applied values differ from the rendered ones.
+	// +optional
+	FieldPaths []string `json:"fieldPaths,omitempty"`
 }
 
 // DependencyStatus represents the observed state of the dependency of
@@ -460,6 +1132,7 @@ type DependencyToObject struct {
 // An ApplicationConfiguration represents an OAM application.
 // +kubebuilder:resource:shortName=appconfig,categories={crossplane,oam}
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 type ApplicationConfiguration struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
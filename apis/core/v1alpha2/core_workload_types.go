@@ -54,6 +54,16 @@ type SecretKeySelector struct {
 	Key string `json:"key"`
 }
 
+// A ConfigMapKeySelector is a reference to a ConfigMap key in the namespace
+// of a workload.
+type ConfigMapKeySelector struct {
+	// The name of the ConfigMap.
+	Name string `json:"name"`
+
+	// The key to select.
+	Key string `json:"key"`
+}
+
 // TODO(negz): The OAM spec calls for float64 quantities in some cases, but this
 // is incompatible with controller-gen and Kubernetes API conventions. We should
 // reassess whether resource.Quantity is appropriate after resolving
@@ -85,6 +95,11 @@ type DiskResource struct {
 	// Ephemeral specifies whether an external disk needs to be mounted.
 	// +optional
 	Ephemeral *bool `json:"ephemeral,omitempty"`
+
+	// StorageClass of the PersistentVolumeClaim generated for an external
+	// disk. The default storage class is used if it is omitted.
+	// +optional
+	StorageClass *string `json:"storageClass,omitempty"`
 }
 
 // A VolumeAccessMode determines how a volume may be accessed.
@@ -105,7 +120,24 @@ const (
 	VolumeSharingPolicyShared    VolumeSharingPolicy = "Shared"
 )
 
-// VolumeResource required by a container.
+// A VolumeObjectSource selects the ConfigMap or Secret whose keys are
+// mounted as files of a volume.
+type VolumeObjectSource struct {
+	// Name of the ConfigMap or Secret.
+	Name string `json:"name"`
+
+	// Keys to mount as files named after them. All keys are mounted if
+	// omitted.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// VolumeResource required by a container. A volume is backed by the
+// ConfigMap, Secret or PersistentVolumeClaim it is read from, if any, or else
+// by a PersistentVolumeClaim generated for its disk, unless the disk is
+// ephemeral. Other volumes are empty directories that live as long as their
+// pod. Volumes of the same name in several containers of a workload are the
+// same volume.
 type VolumeResource struct {
 	// Name of this volume. Must be unique within its container.
 	Name string `json:"name"`
@@ -129,6 +161,19 @@ type VolumeResource struct {
 	// Disk requirements of this volume.
 	// +optional
 	Disk *DiskResource `json:"disk,omitempty"`
+
+	// FromConfigMap mounts the keys of a ConfigMap as files of this volume.
+	// +optional
+	FromConfigMap *VolumeObjectSource `json:"fromConfigMap,omitempty"`
+
+	// FromSecret mounts the keys of a Secret as files of this volume.
+	// +optional
+	FromSecret *VolumeObjectSource `json:"fromSecret,omitempty"`
+
+	// FromPersistentVolumeClaim mounts the named PersistentVolumeClaim as
+	// this volume.
+	// +optional
+	FromPersistentVolumeClaim *string `json:"fromPersistentVolumeClaim,omitempty"`
 }
 
 // ExtendedResource required by a container.
@@ -178,6 +223,27 @@ type ContainerEnvVar struct {
 	// to the environment variable.
 	// +optional
 	FromSecret *SecretKeySelector `json:"fromSecret,omitempty"`
+
+	// FromConfigMap is a ConfigMap key reference which can be used to assign
+	// a value to the environment variable.
+	// +optional
+	FromConfigMap *ConfigMapKeySelector `json:"fromConfigMap,omitempty"`
+}
+
+// A ContainerEnvFromSource sets an environment variable within a container
+// for each key of a Secret or ConfigMap.
+type ContainerEnvFromSource struct {
+	// Prefix prepended to the keys to name the environment variables.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// FromSecret is the name of a Secret whose keys are set.
+	// +optional
+	FromSecret *string `json:"fromSecret,omitempty"`
+
+	// FromConfigMap is the name of a ConfigMap whose keys are set.
+	// +optional
+	FromConfigMap *string `json:"fromConfigMap,omitempty"`
 }
 
 // A ContainerConfigFile specifies a configuration file that should be written
@@ -207,6 +273,16 @@ const (
 	TransportProtocolUDP TransportProtocol = "UDP"
 )
 
+// An ImagePullPolicy determines when the image of a container is pulled.
+type ImagePullPolicy string
+
+// Image pull policies.
+const (
+	ImagePullPolicyAlways       ImagePullPolicy = "Always"
+	ImagePullPolicyIfNotPresent ImagePullPolicy = "IfNotPresent"
+	ImagePullPolicyNever        ImagePullPolicy = "Never"
+)
+
 // A ContainerPort specifies a port that is exposed by a container.
 type ContainerPort struct {
 	// Name of this port. Must be unique within its container. Must be lowercase
@@ -328,6 +404,12 @@ type Container struct {
 	// +optional
 	Environment []ContainerEnvVar `json:"env,omitempty"`
 
+	// EnvFrom sets environment variables within this container from all the
+	// keys of Secrets or ConfigMaps. The variables of Environment take
+	// precedence over them.
+	// +optional
+	EnvFrom []ContainerEnvFromSource `json:"envFrom,omitempty"`
+
 	// ConfigFiles that should be written within this container.
 	// +optional
 	ConfigFiles []ContainerConfigFile `json:"config,omitempty"`
@@ -354,6 +436,13 @@ type Container struct {
 	// credentials required to pull this container's image can be loaded.
 	// +optional
 	ImagePullSecret *string `json:"imagePullSecret,omitempty"`
+
+	// ImagePullPolicy of the image of this container; Always, IfNotPresent
+	// or Never. Defaults to Always for images tagged latest, and to
+	// IfNotPresent otherwise.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy *ImagePullPolicy `json:"imagePullPolicy,omitempty"`
 }
 
 // A ContainerizedWorkloadSpec defines the desired state of a
@@ -369,6 +458,19 @@ type ContainerizedWorkloadSpec struct {
 	// +optional
 	CPUArchitecture *CPUArchitecture `json:"arch,omitempty"`
 
+	// ImagePullSecrets are the names of Secrets from which the credentials
+	// required to pull the images of all the containers of this workload can
+	// be loaded, in addition to the ImagePullSecret of each container.
+	// +optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+
+	// InitContainers of this workload, e.g. to migrate a database. They run
+	// one after another in the order they are declared, each to completion,
+	// before the containers of this workload start. Their probes and ports
+	// are ignored.
+	// +optional
+	InitContainers []Container `json:"initContainers,omitempty"`
+
 	// Containers of which this workload consists.
 	Containers []Container `json:"containers"`
 }
@@ -380,6 +482,47 @@ type ContainerizedWorkloadStatus struct {
 
 	// Resources managed by this containerised workload.
 	Resources []runtimev1alpha1.TypedReference `json:"resources,omitempty"`
+
+	// Replicas of this workload desired by its deployment.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas of this workload observed by its deployment.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// ServiceIP is the cluster IP of the service of this workload, if it
+	// has one.
+	// +optional
+	ServiceIP string `json:"serviceIP,omitempty"`
+
+	// Containers of this workload, as observed in the pods of its
+	// deployment. Only containers that have a liveness or readiness probe
+	// are reported.
+	// +optional
+	Containers []ContainerProbeStatus `json:"containers,omitempty"`
+}
+
+// A ContainerProbeStatus summarises the probe results of a container across
+// the pods of a ContainerizedWorkload.
+type ContainerProbeStatus struct {
+	// Name of the container.
+	Name string `json:"name"`
+
+	// Pods that run the container.
+	Pods int32 `json:"pods"`
+
+	// ReadyPods in which the container passes its readiness probe.
+	ReadyPods int32 `json:"readyPods"`
+
+	// Restarts of the container across its pods, e.g. because its liveness
+	// probe failed.
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+
+	// Message explaining why the container last failed, if it did.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 var _ oam.Workload = &ContainerizedWorkload{}
@@ -405,3 +548,279 @@ type ContainerizedWorkloadList struct {
 	metav1.ListMeta `json:"metadata,omitempty"`
 	Items           []ContainerizedWorkload `json:"items"`
 }
+
+// A TaskWorkloadSpec defines the desired state of a TaskWorkload.
+type TaskWorkloadSpec struct {
+	// The containers of this task, and the operating system and CPU
+	// architecture they require.
+	ContainerizedWorkloadSpec `json:",inline"`
+
+	// Completions of this task, i.e. how many of its pods must succeed.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Completions *int32 `json:"completions,omitempty"`
+
+	// Parallelism of this task, i.e. how many of its pods may run at a time.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+
+	// BackoffLimit of this task, i.e. how many of its pods may fail before
+	// the task fails. Defaults to 6.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// ActiveDeadlineSeconds after which this task fails if it did not
+	// complete.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// TTLSecondsAfterFinished after which the job of this task is deleted
+	// once it completed or failed. The task is not run again.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// A TaskWorkloadStatus represents the observed state of a TaskWorkload.
+type TaskWorkloadStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Resources managed by this task.
+	Resources []runtimev1alpha1.TypedReference `json:"resources,omitempty"`
+
+	// ObservedGeneration of this task that its job runs.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Active pods of this task.
+	// +optional
+	Active int32 `json:"active,omitempty"`
+
+	// Succeeded pods of this task.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// Failed pods of this task.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// StartTime of the job of this task.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime of the job of this task, if it completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+var _ oam.Workload = &TaskWorkload{}
+
+// +kubebuilder:object:root=true
+
+// A TaskWorkload is a workload that runs OCI containers to completion, e.g. to
+// migrate a database.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".status.succeeded",name=SUCCEEDED,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.failed",name=FAILED,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.conditions[?(@.type=='Ready')].status",name=READY,type=string
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=AGE,type=date
+type TaskWorkload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TaskWorkloadSpec   `json:"spec,omitempty"`
+	Status TaskWorkloadStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TaskWorkloadList contains a list of TaskWorkload.
+type TaskWorkloadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TaskWorkload `json:"items"`
+}
+
+// A PodManagementPolicy determines how the pods of a StatefulWorkload are
+// created and deleted.
+type PodManagementPolicy string
+
+// Pod management policies.
+const (
+	// PodManagementPolicyOrderedReady creates pods in the order of their
+	// ordinals, each once its predecessor is ready, and deletes them in
+	// reverse order.
+	PodManagementPolicyOrderedReady PodManagementPolicy = "OrderedReady"
+
+	// PodManagementPolicyParallel creates and deletes pods all at once.
+	PodManagementPolicyParallel PodManagementPolicy = "Parallel"
+)
+
+// A StatefulWorkloadSpec defines the desired state of a StatefulWorkload.
+type StatefulWorkloadSpec struct {
+	// The containers of this workload, and the operating system and CPU
+	// architecture they require. Each replica gets its own claims of the
+	// volumes that require an external disk.
+	ContainerizedWorkloadSpec `json:",inline"`
+
+	// Replicas of this workload. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// PodManagementPolicy of this workload; OrderedReady or Parallel.
+	// Defaults to OrderedReady.
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy *PodManagementPolicy `json:"podManagementPolicy,omitempty"`
+
+	// Partition of the rolling updates of this workload. Only the replicas
+	// whose ordinal is greater than or equal to the partition are updated.
+	// Defaults to 0, i.e. all replicas are updated.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Partition *int32 `json:"partition,omitempty"`
+}
+
+// A StatefulWorkloadStatus represents the observed state of a
+// StatefulWorkload.
+type StatefulWorkloadStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Resources managed by this workload.
+	Resources []runtimev1alpha1.TypedReference `json:"resources,omitempty"`
+
+	// Replicas of this workload desired by its stateful set.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas of this workload observed by its stateful set.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// CurrentReplicas of this workload that run its current revision.
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// UpdatedReplicas of this workload that run its update revision.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// CurrentRevision of this workload, i.e. the revision of the stateful
+	// set its replicas ran before the rollout in progress, if any.
+	// +optional
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
+	// UpdateRevision of this workload, i.e. the revision of the stateful set
+	// its replicas are rolled out to.
+	// +optional
+	UpdateRevision string `json:"updateRevision,omitempty"`
+}
+
+var _ oam.Workload = &StatefulWorkload{}
+
+// +kubebuilder:object:root=true
+
+// A StatefulWorkload is a workload that runs OCI containers with a stable
+// identity and storage per replica, e.g. databases.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".status.replicas",name=REPLICAS,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.readyReplicas",name=READY-REPLICAS,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.updateRevision",name=REVISION,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.conditions[?(@.type=='Ready')].status",name=READY,type=string
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=AGE,type=date
+type StatefulWorkload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StatefulWorkloadSpec   `json:"spec,omitempty"`
+	Status StatefulWorkloadStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StatefulWorkloadList contains a list of StatefulWorkload.
+type StatefulWorkloadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StatefulWorkload `json:"items"`
+}
+
+// A DaemonWorkloadSpec defines the desired state of a DaemonWorkload.
+type DaemonWorkloadSpec struct {
+	// The containers of this workload, and the operating system and CPU
+	// architecture they require.
+	ContainerizedWorkloadSpec `json:",inline"`
+
+	// NodeSelector restricts this workload to the nodes with the supplied
+	// labels. It runs on all nodes that match its operating system and CPU
+	// architecture by default.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// MaxUnavailable nodes during a rolling update of this workload, either
+	// a number or a percentage of its nodes. Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// A DaemonWorkloadStatus represents the observed state of a DaemonWorkload.
+type DaemonWorkloadStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	// Resources managed by this workload.
+	Resources []runtimev1alpha1.TypedReference `json:"resources,omitempty"`
+
+	// DesiredNodes this workload should run on.
+	// +optional
+	DesiredNodes int32 `json:"desiredNodes"`
+
+	// ReadyNodes this workload runs a ready pod on.
+	// +optional
+	ReadyNodes int32 `json:"readyNodes"`
+
+	// UpdatedNodes this workload runs its latest spec on.
+	// +optional
+	UpdatedNodes int32 `json:"updatedNodes,omitempty"`
+
+	// ServiceIP is the cluster IP of the service of this workload, if it
+	// has one.
+	// +optional
+	ServiceIP string `json:"serviceIP,omitempty"`
+}
+
+var _ oam.Workload = &DaemonWorkload{}
+
+// +kubebuilder:object:root=true
+
+// A DaemonWorkload is a workload that runs OCI containers on every node, e.g.
+// log collectors or monitoring agents.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".status.desiredNodes",name=DESIRED,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.readyNodes",name=READY-NODES,type=integer
+// +kubebuilder:printcolumn:JSONPath=".status.conditions[?(@.type=='Ready')].status",name=READY,type=string
+// +kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name=AGE,type=date
+type DaemonWorkload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DaemonWorkloadSpec   `json:"spec,omitempty"`
+	Status DaemonWorkloadStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DaemonWorkloadList contains a list of DaemonWorkload.
+type DaemonWorkloadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DaemonWorkload `json:"items"`
+}
@@ -42,6 +42,166 @@ func (tr *ManualScalerTrait) SetWorkloadReference(r runtimev1alpha1.TypedReferen
 	tr.Spec.WorkloadReference = r
 }
 
+// GetCondition of this AutoscalerTrait.
+func (tr *AutoscalerTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this AutoscalerTrait.
+func (tr *AutoscalerTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this AutoscalerTrait.
+func (tr *AutoscalerTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this AutoscalerTrait.
+func (tr *AutoscalerTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// GetCondition of this IngressTrait.
+func (tr *IngressTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this IngressTrait.
+func (tr *IngressTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this IngressTrait.
+func (tr *IngressTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this IngressTrait.
+func (tr *IngressTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// GetCondition of this VolumeClaimTrait.
+func (tr *VolumeClaimTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this VolumeClaimTrait.
+func (tr *VolumeClaimTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this VolumeClaimTrait.
+func (tr *VolumeClaimTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this VolumeClaimTrait.
+func (tr *VolumeClaimTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// GetCondition of this MetricsTrait.
+func (tr *MetricsTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this MetricsTrait.
+func (tr *MetricsTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this MetricsTrait.
+func (tr *MetricsTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this MetricsTrait.
+func (tr *MetricsTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// GetCondition of this RolloutTrait.
+func (tr *RolloutTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this RolloutTrait.
+func (tr *RolloutTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this RolloutTrait.
+func (tr *RolloutTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this RolloutTrait.
+func (tr *RolloutTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// GetCondition of this SidecarTrait.
+func (tr *SidecarTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this SidecarTrait.
+func (tr *SidecarTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this SidecarTrait.
+func (tr *SidecarTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this SidecarTrait.
+func (tr *SidecarTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// GetCondition of this EventScalerTrait.
+func (tr *EventScalerTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this EventScalerTrait.
+func (tr *EventScalerTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this EventScalerTrait.
+func (tr *EventScalerTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this EventScalerTrait.
+func (tr *EventScalerTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
+// GetCondition of this BackupTrait.
+func (tr *BackupTrait) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return tr.Status.GetCondition(ct)
+}
+
+// SetConditions of this BackupTrait.
+func (tr *BackupTrait) SetConditions(c ...runtimev1alpha1.Condition) {
+	tr.Status.SetConditions(c...)
+}
+
+// GetWorkloadReference of this BackupTrait.
+func (tr *BackupTrait) GetWorkloadReference() runtimev1alpha1.TypedReference {
+	return tr.Spec.WorkloadReference
+}
+
+// SetWorkloadReference of this BackupTrait.
+func (tr *BackupTrait) SetWorkloadReference(r runtimev1alpha1.TypedReference) {
+	tr.Spec.WorkloadReference = r
+}
+
 // GetCondition of this ApplicationConfiguration.
 func (ac *ApplicationConfiguration) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
 	return ac.Status.GetCondition(ct)
@@ -72,6 +232,36 @@ func (wl *ContainerizedWorkload) SetConditions(c ...runtimev1alpha1.Condition) {
 	wl.Status.SetConditions(c...)
 }
 
+// GetCondition of this TaskWorkload.
+func (wl *TaskWorkload) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return wl.Status.GetCondition(ct)
+}
+
+// SetConditions of this TaskWorkload.
+func (wl *TaskWorkload) SetConditions(c ...runtimev1alpha1.Condition) {
+	wl.Status.SetConditions(c...)
+}
+
+// GetCondition of this StatefulWorkload.
+func (wl *StatefulWorkload) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return wl.Status.GetCondition(ct)
+}
+
+// SetConditions of this StatefulWorkload.
+func (wl *StatefulWorkload) SetConditions(c ...runtimev1alpha1.Condition) {
+	wl.Status.SetConditions(c...)
+}
+
+// GetCondition of this DaemonWorkload.
+func (wl *DaemonWorkload) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return wl.Status.GetCondition(ct)
+}
+
+// SetConditions of this DaemonWorkload.
+func (wl *DaemonWorkload) SetConditions(c ...runtimev1alpha1.Condition) {
+	wl.Status.SetConditions(c...)
+}
+
 // GetCondition of this HealthScope.
 func (hs *HealthScope) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
 	return hs.Status.GetCondition(ct)
@@ -91,3 +281,83 @@ func (hs *HealthScope) GetWorkloadReferences() []runtimev1alpha1.TypedReference
 func (hs *HealthScope) AddWorkloadReference(r runtimev1alpha1.TypedReference) {
 	hs.Spec.WorkloadReferences = append(hs.Spec.WorkloadReferences, r)
 }
+
+// GetCondition of this NetworkScope.
+func (ns *NetworkScope) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return ns.Status.GetCondition(ct)
+}
+
+// SetConditions of this NetworkScope.
+func (ns *NetworkScope) SetConditions(c ...runtimev1alpha1.Condition) {
+	ns.Status.SetConditions(c...)
+}
+
+// GetWorkloadReferences to get all workload references for scope.
+func (ns *NetworkScope) GetWorkloadReferences() []runtimev1alpha1.TypedReference {
+	return ns.Spec.WorkloadReferences
+}
+
+// AddWorkloadReference to add a workload reference to this scope.
+func (ns *NetworkScope) AddWorkloadReference(r runtimev1alpha1.TypedReference) {
+	ns.Spec.WorkloadReferences = append(ns.Spec.WorkloadReferences, r)
+}
+
+// GetCondition of this ResourceQuotaScope.
+func (rs *ResourceQuotaScope) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return rs.Status.GetCondition(ct)
+}
+
+// SetConditions of this ResourceQuotaScope.
+func (rs *ResourceQuotaScope) SetConditions(c ...runtimev1alpha1.Condition) {
+	rs.Status.SetConditions(c...)
+}
+
+// GetWorkloadReferences to get all workload references for scope.
+func (rs *ResourceQuotaScope) GetWorkloadReferences() []runtimev1alpha1.TypedReference {
+	return rs.Spec.WorkloadReferences
+}
+
+// AddWorkloadReference to add a workload reference to this scope.
+func (rs *ResourceQuotaScope) AddWorkloadReference(r runtimev1alpha1.TypedReference) {
+	rs.Spec.WorkloadReferences = append(rs.Spec.WorkloadReferences, r)
+}
+
+// GetCondition of this SecurityScope.
+func (ss *SecurityScope) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return ss.Status.GetCondition(ct)
+}
+
+// SetConditions of this SecurityScope.
+func (ss *SecurityScope) SetConditions(c ...runtimev1alpha1.Condition) {
+	ss.Status.SetConditions(c...)
+}
+
+// GetWorkloadReferences to get all workload references for scope.
+func (ss *SecurityScope) GetWorkloadReferences() []runtimev1alpha1.TypedReference {
+	return ss.Spec.WorkloadReferences
+}
+
+// AddWorkloadReference to add a workload reference to this scope.
+func (ss *SecurityScope) AddWorkloadReference(r runtimev1alpha1.TypedReference) {
+	ss.Spec.WorkloadReferences = append(ss.Spec.WorkloadReferences, r)
+}
+
+// GetCondition of this PlacementScope.
+func (ps *PlacementScope) GetCondition(ct runtimev1alpha1.ConditionType) runtimev1alpha1.Condition {
+	return ps.Status.GetCondition(ct)
+}
+
+// SetConditions of this PlacementScope.
+func (ps *PlacementScope) SetConditions(c ...runtimev1alpha1.Condition) {
+	ps.Status.SetConditions(c...)
+}
+
+// GetWorkloadReferences to get all workload references for scope.
+func (ps *PlacementScope) GetWorkloadReferences() []runtimev1alpha1.TypedReference {
+	return ps.Spec.WorkloadReferences
+}
+
+// AddWorkloadReference to add a workload reference to this scope.
+func (ps *PlacementScope) AddWorkloadReference(r runtimev1alpha1.TypedReference) {
+	ps.Spec.WorkloadReferences = append(ps.Spec.WorkloadReferences, r)
+}
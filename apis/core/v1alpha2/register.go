@@ -61,6 +61,30 @@ var (
 	ScopeDefinitionGroupVersionKind = SchemeGroupVersion.WithKind(ScopeDefinitionKind)
 )
 
+// DefinitionUsagePolicy type metadata.
+var (
+	DefinitionUsagePolicyKind             = reflect.TypeOf(DefinitionUsagePolicy{}).Name()
+	DefinitionUsagePolicyGroupKind        = schema.GroupKind{Group: Group, Kind: DefinitionUsagePolicyKind}.String()
+	DefinitionUsagePolicyKindAPIVersion   = DefinitionUsagePolicyKind + "." + SchemeGroupVersion.String()
+	DefinitionUsagePolicyGroupVersionKind = SchemeGroupVersion.WithKind(DefinitionUsagePolicyKind)
+)
+
+// DefinitionCatalog type metadata.
+var (
+	DefinitionCatalogKind             = reflect.TypeOf(DefinitionCatalog{}).Name()
+	DefinitionCatalogGroupKind        = schema.GroupKind{Group: Group, Kind: DefinitionCatalogKind}.String()
+	DefinitionCatalogKindAPIVersion   = DefinitionCatalogKind + "." + SchemeGroupVersion.String()
+	DefinitionCatalogGroupVersionKind = SchemeGroupVersion.WithKind(DefinitionCatalogKind)
+)
+
+// DefinitionRevision type metadata.
+var (
+	DefinitionRevisionKind             = reflect.TypeOf(DefinitionRevision{}).Name()
+	DefinitionRevisionGroupKind        = schema.GroupKind{Group: Group, Kind: DefinitionRevisionKind}.String()
+	DefinitionRevisionKindAPIVersion   = DefinitionRevisionKind + "." + SchemeGroupVersion.String()
+	DefinitionRevisionGroupVersionKind = SchemeGroupVersion.WithKind(DefinitionRevisionKind)
+)
+
 // Component type metadata.
 var (
 	ComponentKind             = reflect.TypeOf(Component{}).Name()
@@ -85,6 +109,30 @@ var (
 	ContainerizedWorkloadGroupVersionKind = SchemeGroupVersion.WithKind(ContainerizedWorkloadKind)
 )
 
+// TaskWorkload type metadata.
+var (
+	TaskWorkloadKind             = reflect.TypeOf(TaskWorkload{}).Name()
+	TaskWorkloadGroupKind        = schema.GroupKind{Group: Group, Kind: TaskWorkloadKind}.String()
+	TaskWorkloadKindAPIVersion   = TaskWorkloadKind + "." + SchemeGroupVersion.String()
+	TaskWorkloadGroupVersionKind = SchemeGroupVersion.WithKind(TaskWorkloadKind)
+)
+
+// StatefulWorkload type metadata.
+var (
+	StatefulWorkloadKind             = reflect.TypeOf(StatefulWorkload{}).Name()
+	StatefulWorkloadGroupKind        = schema.GroupKind{Group: Group, Kind: StatefulWorkloadKind}.String()
+	StatefulWorkloadKindAPIVersion   = StatefulWorkloadKind + "." + SchemeGroupVersion.String()
+	StatefulWorkloadGroupVersionKind = SchemeGroupVersion.WithKind(StatefulWorkloadKind)
+)
+
+// DaemonWorkload type metadata.
+var (
+	DaemonWorkloadKind             = reflect.TypeOf(DaemonWorkload{}).Name()
+	DaemonWorkloadGroupKind        = schema.GroupKind{Group: Group, Kind: DaemonWorkloadKind}.String()
+	DaemonWorkloadKindAPIVersion   = DaemonWorkloadKind + "." + SchemeGroupVersion.String()
+	DaemonWorkloadGroupVersionKind = SchemeGroupVersion.WithKind(DaemonWorkloadKind)
+)
+
 // ManualScalerTrait type metadata.
 var (
 	ManualScalerTraitKind             = reflect.TypeOf(ManualScalerTrait{}).Name()
@@ -93,6 +141,70 @@ var (
 	ManualScalerTraitGroupVersionKind = SchemeGroupVersion.WithKind(ManualScalerTraitKind)
 )
 
+// AutoscalerTrait type metadata.
+var (
+	AutoscalerTraitKind             = reflect.TypeOf(AutoscalerTrait{}).Name()
+	AutoscalerTraitGroupKind        = schema.GroupKind{Group: Group, Kind: AutoscalerTraitKind}.String()
+	AutoscalerTraitKindAPIVersion   = AutoscalerTraitKind + "." + SchemeGroupVersion.String()
+	AutoscalerTraitGroupVersionKind = SchemeGroupVersion.WithKind(AutoscalerTraitKind)
+)
+
+// IngressTrait type metadata.
+var (
+	IngressTraitKind             = reflect.TypeOf(IngressTrait{}).Name()
+	IngressTraitGroupKind        = schema.GroupKind{Group: Group, Kind: IngressTraitKind}.String()
+	IngressTraitKindAPIVersion   = IngressTraitKind + "." + SchemeGroupVersion.String()
+	IngressTraitGroupVersionKind = SchemeGroupVersion.WithKind(IngressTraitKind)
+)
+
+// VolumeClaimTrait type metadata.
+var (
+	VolumeClaimTraitKind             = reflect.TypeOf(VolumeClaimTrait{}).Name()
+	VolumeClaimTraitGroupKind        = schema.GroupKind{Group: Group, Kind: VolumeClaimTraitKind}.String()
+	VolumeClaimTraitKindAPIVersion   = VolumeClaimTraitKind + "." + SchemeGroupVersion.String()
+	VolumeClaimTraitGroupVersionKind = SchemeGroupVersion.WithKind(VolumeClaimTraitKind)
+)
+
+// MetricsTrait type metadata.
+var (
+	MetricsTraitKind             = reflect.TypeOf(MetricsTrait{}).Name()
+	MetricsTraitGroupKind        = schema.GroupKind{Group: Group, Kind: MetricsTraitKind}.String()
+	MetricsTraitKindAPIVersion   = MetricsTraitKind + "." + SchemeGroupVersion.String()
+	MetricsTraitGroupVersionKind = SchemeGroupVersion.WithKind(MetricsTraitKind)
+)
+
+// RolloutTrait type metadata.
+var (
+	RolloutTraitKind             = reflect.TypeOf(RolloutTrait{}).Name()
+	RolloutTraitGroupKind        = schema.GroupKind{Group: Group, Kind: RolloutTraitKind}.String()
+	RolloutTraitKindAPIVersion   = RolloutTraitKind + "." + SchemeGroupVersion.String()
+	RolloutTraitGroupVersionKind = SchemeGroupVersion.WithKind(RolloutTraitKind)
+)
+
+// SidecarTrait type metadata.
+var (
+	SidecarTraitKind             = reflect.TypeOf(SidecarTrait{}).Name()
+	SidecarTraitGroupKind        = schema.GroupKind{Group: Group, Kind: SidecarTraitKind}.String()
+	SidecarTraitKindAPIVersion   = SidecarTraitKind + "." + SchemeGroupVersion.String()
+	SidecarTraitGroupVersionKind = SchemeGroupVersion.WithKind(SidecarTraitKind)
+)
+
+// EventScalerTrait type metadata.
+var (
+	EventScalerTraitKind             = reflect.TypeOf(EventScalerTrait{}).Name()
+	EventScalerTraitGroupKind        = schema.GroupKind{Group: Group, Kind: EventScalerTraitKind}.String()
+	EventScalerTraitKindAPIVersion   = EventScalerTraitKind + "." + SchemeGroupVersion.String()
+	EventScalerTraitGroupVersionKind = SchemeGroupVersion.WithKind(EventScalerTraitKind)
+)
+
+// BackupTrait type metadata.
+var (
+	BackupTraitKind             = reflect.TypeOf(BackupTrait{}).Name()
+	BackupTraitGroupKind        = schema.GroupKind{Group: Group, Kind: BackupTraitKind}.String()
+	BackupTraitKindAPIVersion   = BackupTraitKind + "." + SchemeGroupVersion.String()
+	BackupTraitGroupVersionKind = SchemeGroupVersion.WithKind(BackupTraitKind)
+)
+
 // HealthScope type metadata.
 var (
 	HealthScopeKind             = reflect.TypeOf(HealthScope{}).Name()
@@ -101,13 +213,63 @@ var (
 	HealthScopeGroupVersionKind = SchemeGroupVersion.WithKind(HealthScopeKind)
 )
 
+// NetworkScope type metadata.
+var (
+	NetworkScopeKind             = reflect.TypeOf(NetworkScope{}).Name()
+	NetworkScopeGroupKind        = schema.GroupKind{Group: Group, Kind: NetworkScopeKind}.String()
+	NetworkScopeKindAPIVersion   = NetworkScopeKind + "." + SchemeGroupVersion.String()
+	NetworkScopeGroupVersionKind = SchemeGroupVersion.WithKind(NetworkScopeKind)
+)
+
+// ResourceQuotaScope type metadata.
+var (
+	ResourceQuotaScopeKind             = reflect.TypeOf(ResourceQuotaScope{}).Name()
+	ResourceQuotaScopeGroupKind        = schema.GroupKind{Group: Group, Kind: ResourceQuotaScopeKind}.String()
+	ResourceQuotaScopeKindAPIVersion   = ResourceQuotaScopeKind + "." + SchemeGroupVersion.String()
+	ResourceQuotaScopeGroupVersionKind = SchemeGroupVersion.WithKind(ResourceQuotaScopeKind)
+)
+
+// SecurityScope type metadata.
+var (
+	SecurityScopeKind             = reflect.TypeOf(SecurityScope{}).Name()
+	SecurityScopeGroupKind        = schema.GroupKind{Group: Group, Kind: SecurityScopeKind}.String()
+	SecurityScopeKindAPIVersion   = SecurityScopeKind + "." + SchemeGroupVersion.String()
+	SecurityScopeGroupVersionKind = SchemeGroupVersion.WithKind(SecurityScopeKind)
+)
+
+// PlacementScope type metadata.
+var (
+	PlacementScopeKind             = reflect.TypeOf(PlacementScope{}).Name()
+	PlacementScopeGroupKind        = schema.GroupKind{Group: Group, Kind: PlacementScopeKind}.String()
+	PlacementScopeKindAPIVersion   = PlacementScopeKind + "." + SchemeGroupVersion.String()
+	PlacementScopeGroupVersionKind = SchemeGroupVersion.WithKind(PlacementScopeKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&WorkloadDefinition{}, &WorkloadDefinitionList{})
 	SchemeBuilder.Register(&TraitDefinition{}, &TraitDefinitionList{})
 	SchemeBuilder.Register(&ScopeDefinition{}, &ScopeDefinitionList{})
+	SchemeBuilder.Register(&DefinitionUsagePolicy{}, &DefinitionUsagePolicyList{})
+	SchemeBuilder.Register(&DefinitionCatalog{}, &DefinitionCatalogList{})
+	SchemeBuilder.Register(&DefinitionRevision{}, &DefinitionRevisionList{})
 	SchemeBuilder.Register(&Component{}, &ComponentList{})
 	SchemeBuilder.Register(&ApplicationConfiguration{}, &ApplicationConfigurationList{})
 	SchemeBuilder.Register(&ContainerizedWorkload{}, &ContainerizedWorkloadList{})
+	SchemeBuilder.Register(&TaskWorkload{}, &TaskWorkloadList{})
+	SchemeBuilder.Register(&StatefulWorkload{}, &StatefulWorkloadList{})
+	SchemeBuilder.Register(&DaemonWorkload{}, &DaemonWorkloadList{})
 	SchemeBuilder.Register(&ManualScalerTrait{}, &ManualScalerTraitList{})
+	SchemeBuilder.Register(&AutoscalerTrait{}, &AutoscalerTraitList{})
+	SchemeBuilder.Register(&IngressTrait{}, &IngressTraitList{})
+	SchemeBuilder.Register(&VolumeClaimTrait{}, &VolumeClaimTraitList{})
+	SchemeBuilder.Register(&MetricsTrait{}, &MetricsTraitList{})
+	SchemeBuilder.Register(&RolloutTrait{}, &RolloutTraitList{})
+	SchemeBuilder.Register(&SidecarTrait{}, &SidecarTraitList{})
+	SchemeBuilder.Register(&EventScalerTrait{}, &EventScalerTraitList{})
+	SchemeBuilder.Register(&BackupTrait{}, &BackupTraitList{})
 	SchemeBuilder.Register(&HealthScope{}, &HealthScopeList{})
+	SchemeBuilder.Register(&NetworkScope{}, &NetworkScopeList{})
+	SchemeBuilder.Register(&ResourceQuotaScope{}, &ResourceQuotaScopeList{})
+	SchemeBuilder.Register(&SecurityScope{}, &SecurityScopeList{})
+	SchemeBuilder.Register(&PlacementScope{}, &PlacementScopeList{})
 }
@@ -22,7 +22,10 @@ package v1alpha2
 
 import (
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -72,7 +75,9 @@ func (in *ApplicationConfigurationComponent) DeepCopyInto(out *ApplicationConfig
 	if in.ParameterValues != nil {
 		in, out := &in.ParameterValues, &out.ParameterValues
 		*out = make([]ComponentParameterValue, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Traits != nil {
 		in, out := &in.Traits, &out.Traits
@@ -86,6 +91,13 @@ func (in *ApplicationConfigurationComponent) DeepCopyInto(out *ApplicationConfig
 		*out = make([]ComponentScope, len(*in))
 		copy(*out, *in)
 	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ComponentPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationComponent.
@@ -130,6 +142,28 @@ func (in *ApplicationConfigurationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationConfigurationOverlay) DeepCopyInto(out *ApplicationConfigurationOverlay) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationOverlay.
+func (in *ApplicationConfigurationOverlay) DeepCopy() *ApplicationConfigurationOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationConfigurationOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationConfigurationSpec) DeepCopyInto(out *ApplicationConfigurationSpec) {
 	*out = *in
@@ -140,6 +174,20 @@ func (in *ApplicationConfigurationSpec) DeepCopyInto(out *ApplicationConfigurati
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Overlays != nil {
+		in, out := &in.Overlays, &out.Overlays
+		*out = make([]ApplicationConfigurationOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScopeTraits != nil {
+		in, out := &in.ScopeTraits, &out.ScopeTraits
+		*out = make([]ScopeTraits, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationSpec.
@@ -169,6 +217,11 @@ func (in *ApplicationConfigurationStatus) DeepCopyInto(out *ApplicationConfigura
 		*out = make([]HistoryWorkload, len(*in))
 		copy(*out, *in)
 	}
+	if in.RenderDiff != nil {
+		in, out := &in.RenderDiff, &out.RenderDiff
+		*out = new(RenderDiff)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationConfigurationStatus.
@@ -182,45 +235,32 @@ func (in *ApplicationConfigurationStatus) DeepCopy() *ApplicationConfigurationSt
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CPUResources) DeepCopyInto(out *CPUResources) {
+func (in *AutoscalerMetric) DeepCopyInto(out *AutoscalerMetric) {
 	*out = *in
-	out.Required = in.Required.DeepCopy()
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUResources.
-func (in *CPUResources) DeepCopy() *CPUResources {
-	if in == nil {
-		return nil
+	if in.TargetAverageUtilization != nil {
+		in, out := &in.TargetAverageUtilization, &out.TargetAverageUtilization
+		*out = new(int32)
+		**out = **in
 	}
-	out := new(CPUResources)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ChildResourceKind) DeepCopyInto(out *ChildResourceKind) {
-	*out = *in
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.TargetAverageValue != nil {
+		in, out := &in.TargetAverageValue, &out.TargetAverageValue
+		x := (*in).DeepCopy()
+		*out = &x
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildResourceKind.
-func (in *ChildResourceKind) DeepCopy() *ChildResourceKind {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerMetric.
+func (in *AutoscalerMetric) DeepCopy() *AutoscalerMetric {
 	if in == nil {
 		return nil
 	}
-	out := new(ChildResourceKind)
+	out := new(AutoscalerMetric)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Component) DeepCopyInto(out *Component) {
+func (in *AutoscalerTrait) DeepCopyInto(out *AutoscalerTrait) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -228,18 +268,18 @@ func (in *Component) DeepCopyInto(out *Component) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Component.
-func (in *Component) DeepCopy() *Component {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerTrait.
+func (in *AutoscalerTrait) DeepCopy() *AutoscalerTrait {
 	if in == nil {
 		return nil
 	}
-	out := new(Component)
+	out := new(AutoscalerTrait)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Component) DeepCopyObject() runtime.Object {
+func (in *AutoscalerTrait) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -247,31 +287,31 @@ func (in *Component) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentList) DeepCopyInto(out *ComponentList) {
+func (in *AutoscalerTraitList) DeepCopyInto(out *AutoscalerTraitList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Component, len(*in))
+		*out = make([]AutoscalerTrait, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentList.
-func (in *ComponentList) DeepCopy() *ComponentList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerTraitList.
+func (in *AutoscalerTraitList) DeepCopy() *AutoscalerTraitList {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentList)
+	out := new(AutoscalerTraitList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ComponentList) DeepCopyObject() runtime.Object {
+func (in *AutoscalerTraitList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -279,384 +319,2937 @@ func (in *ComponentList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentParameter) DeepCopyInto(out *ComponentParameter) {
+func (in *AutoscalerTraitSpec) DeepCopyInto(out *AutoscalerTraitSpec) {
 	*out = *in
-	if in.FieldPaths != nil {
-		in, out := &in.FieldPaths, &out.FieldPaths
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Required != nil {
-		in, out := &in.Required, &out.Required
-		*out = new(bool)
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Description != nil {
-		in, out := &in.Description, &out.Description
-		*out = new(string)
-		**out = **in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AutoscalerMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
+	out.WorkloadReference = in.WorkloadReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameter.
-func (in *ComponentParameter) DeepCopy() *ComponentParameter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerTraitSpec.
+func (in *AutoscalerTraitSpec) DeepCopy() *AutoscalerTraitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentParameter)
+	out := new(AutoscalerTraitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentParameterValue) DeepCopyInto(out *ComponentParameterValue) {
+func (in *AutoscalerTraitStatus) DeepCopyInto(out *AutoscalerTraitStatus) {
 	*out = *in
-	out.Value = in.Value
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.ScaleTargetReference != nil {
+		in, out := &in.ScaleTargetReference, &out.ScaleTargetReference
+		*out = new(v1alpha1.TypedReference)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameterValue.
-func (in *ComponentParameterValue) DeepCopy() *ComponentParameterValue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalerTraitStatus.
+func (in *AutoscalerTraitStatus) DeepCopy() *AutoscalerTraitStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentParameterValue)
+	out := new(AutoscalerTraitStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentScope) DeepCopyInto(out *ComponentScope) {
+func (in *AuxiliaryWorkload) DeepCopyInto(out *AuxiliaryWorkload) {
 	*out = *in
-	out.ScopeReference = in.ScopeReference
+	in.Workload.DeepCopyInto(&out.Workload)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentScope.
-func (in *ComponentScope) DeepCopy() *ComponentScope {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuxiliaryWorkload.
+func (in *AuxiliaryWorkload) DeepCopy() *AuxiliaryWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentScope)
+	out := new(AuxiliaryWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
+func (in *AuxiliaryWorkloadStatus) DeepCopyInto(out *AuxiliaryWorkloadStatus) {
 	*out = *in
-	in.Workload.DeepCopyInto(&out.Workload)
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make([]ComponentParameter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.Reference = in.Reference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
-func (in *ComponentSpec) DeepCopy() *ComponentSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuxiliaryWorkloadStatus.
+func (in *AuxiliaryWorkloadStatus) DeepCopy() *AuxiliaryWorkloadStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentSpec)
+	out := new(AuxiliaryWorkloadStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+func (in *BackupTrait) DeepCopyInto(out *BackupTrait) {
 	*out = *in
-	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	if in.LatestRevision != nil {
-		in, out := &in.LatestRevision, &out.LatestRevision
-		*out = new(Revision)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
-func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTrait.
+func (in *BackupTrait) DeepCopy() *BackupTrait {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentStatus)
+	out := new(BackupTrait)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BackupTrait) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ComponentTrait) DeepCopyInto(out *ComponentTrait) {
+func (in *BackupTraitList) DeepCopyInto(out *BackupTraitList) {
 	*out = *in
-	in.Trait.DeepCopyInto(&out.Trait)
-	if in.DataOutputs != nil {
-		in, out := &in.DataOutputs, &out.DataOutputs
-		*out = make([]DataOutput, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.DataInputs != nil {
-		in, out := &in.DataInputs, &out.DataInputs
-		*out = make([]DataInput, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BackupTrait, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTrait.
-func (in *ComponentTrait) DeepCopy() *ComponentTrait {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTraitList.
+func (in *BackupTraitList) DeepCopy() *BackupTraitList {
 	if in == nil {
 		return nil
 	}
-	out := new(ComponentTrait)
+	out := new(BackupTraitList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BackupTraitList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConditionRequirement) DeepCopyInto(out *ConditionRequirement) {
+func (in *BackupTraitSpec) DeepCopyInto(out *BackupTraitSpec) {
 	*out = *in
-	out.ValueFrom = in.ValueFrom
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SnapshotVolumes != nil {
+		in, out := &in.SnapshotVolumes, &out.SnapshotVolumes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.VolumeSnapshotLocations != nil {
+		in, out := &in.VolumeSnapshotLocations, &out.VolumeSnapshotLocations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.WorkloadReference = in.WorkloadReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionRequirement.
-func (in *ConditionRequirement) DeepCopy() *ConditionRequirement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTraitSpec.
+func (in *BackupTraitSpec) DeepCopy() *BackupTraitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ConditionRequirement)
+	out := new(BackupTraitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Container) DeepCopyInto(out *Container) {
+func (in *BackupTraitStatus) DeepCopyInto(out *BackupTraitStatus) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(ContainerResources)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Arguments != nil {
-		in, out := &in.Arguments, &out.Arguments
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Environment != nil {
-		in, out := &in.Environment, &out.Environment
-		*out = make([]ContainerEnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ConfigFiles != nil {
-		in, out := &in.ConfigFiles, &out.ConfigFiles
-		*out = make([]ContainerConfigFile, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]ContainerPort, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.LivenessProbe != nil {
-		in, out := &in.LivenessProbe, &out.LivenessProbe
-		*out = new(ContainerHealthProbe)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ReadinessProbe != nil {
-		in, out := &in.ReadinessProbe, &out.ReadinessProbe
-		*out = new(ContainerHealthProbe)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ImagePullSecret != nil {
-		in, out := &in.ImagePullSecret, &out.ImagePullSecret
-		*out = new(string)
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.BackupReference != nil {
+		in, out := &in.BackupReference, &out.BackupReference
+		*out = new(v1alpha1.TypedReference)
 		**out = **in
 	}
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Container.
-func (in *Container) DeepCopy() *Container {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTraitStatus.
+func (in *BackupTraitStatus) DeepCopy() *BackupTraitStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Container)
+	out := new(BackupTraitStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerConfigFile) DeepCopyInto(out *ContainerConfigFile) {
+func (in *CPUResources) DeepCopyInto(out *CPUResources) {
 	*out = *in
-	if in.Value != nil {
-		in, out := &in.Value, &out.Value
-		*out = new(string)
-		**out = **in
-	}
-	if in.FromSecret != nil {
-		in, out := &in.FromSecret, &out.FromSecret
-		*out = new(SecretKeySelector)
-		**out = **in
-	}
+	out.Required = in.Required.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfigFile.
-func (in *ContainerConfigFile) DeepCopy() *ContainerConfigFile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUResources.
+func (in *CPUResources) DeepCopy() *CPUResources {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerConfigFile)
+	out := new(CPUResources)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerEnvVar) DeepCopyInto(out *ContainerEnvVar) {
+func (in *CUE) DeepCopyInto(out *CUE) {
 	*out = *in
-	if in.Value != nil {
-		in, out := &in.Value, &out.Value
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CUE.
+func (in *CUE) DeepCopy() *CUE {
+	if in == nil {
+		return nil
+	}
+	out := new(CUE)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogScopeDefinition) DeepCopyInto(out *CatalogScopeDefinition) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogScopeDefinition.
+func (in *CatalogScopeDefinition) DeepCopy() *CatalogScopeDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogScopeDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogTraitDefinition) DeepCopyInto(out *CatalogTraitDefinition) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogTraitDefinition.
+func (in *CatalogTraitDefinition) DeepCopy() *CatalogTraitDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogTraitDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogWorkloadDefinition) DeepCopyInto(out *CatalogWorkloadDefinition) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogWorkloadDefinition.
+func (in *CatalogWorkloadDefinition) DeepCopy() *CatalogWorkloadDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogWorkloadDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildResourceKind) DeepCopyInto(out *ChildResourceKind) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildResourceKind.
+func (in *ChildResourceKind) DeepCopy() *ChildResourceKind {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildResourceKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Component) DeepCopyInto(out *Component) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Component.
+func (in *Component) DeepCopy() *Component {
+	if in == nil {
+		return nil
+	}
+	out := new(Component)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Component) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentList) DeepCopyInto(out *ComponentList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Component, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentList.
+func (in *ComponentList) DeepCopy() *ComponentList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComponentList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentOverlay) DeepCopyInto(out *ComponentOverlay) {
+	*out = *in
+	if in.ParameterValues != nil {
+		in, out := &in.ParameterValues, &out.ParameterValues
+		*out = make([]ComponentParameterValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Traits != nil {
+		in, out := &in.Traits, &out.Traits
+		*out = make([]ComponentTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentOverlay.
+func (in *ComponentOverlay) DeepCopy() *ComponentOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentParameter) DeepCopyInto(out *ComponentParameter) {
+	*out = *in
+	if in.FieldPaths != nil {
+		in, out := &in.FieldPaths, &out.FieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
 		*out = new(string)
 		**out = **in
 	}
-	if in.FromSecret != nil {
-		in, out := &in.FromSecret, &out.FromSecret
-		*out = new(SecretKeySelector)
-		**out = **in
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]ParameterTransform, len(*in))
+		copy(*out, *in)
+	}
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]intstr.IntOrString, len(*in))
+		copy(*out, *in)
+	}
+	if in.Minimum != nil {
+		in, out := &in.Minimum, &out.Minimum
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Maximum != nil {
+		in, out := &in.Maximum, &out.Maximum
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameter.
+func (in *ComponentParameter) DeepCopy() *ComponentParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentParameterValue) DeepCopyInto(out *ComponentParameterValue) {
+	*out = *in
+	out.Value = in.Value
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ComponentParameterValueFrom)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameterValue.
+func (in *ComponentParameterValue) DeepCopy() *ComponentParameterValue {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentParameterValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentParameterValueFrom) DeepCopyInto(out *ComponentParameterValueFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameterValueFrom.
+func (in *ComponentParameterValueFrom) DeepCopy() *ComponentParameterValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentParameterValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentPatch) DeepCopyInto(out *ComponentPatch) {
+	*out = *in
+	in.Patch.DeepCopyInto(&out.Patch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentPatch.
+func (in *ComponentPatch) DeepCopy() *ComponentPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentScope) DeepCopyInto(out *ComponentScope) {
+	*out = *in
+	out.ScopeReference = in.ScopeReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentScope.
+func (in *ComponentScope) DeepCopy() *ComponentScope {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
+	*out = *in
+	in.Workload.DeepCopyInto(&out.Workload)
+	if in.AuxiliaryWorkloads != nil {
+		in, out := &in.AuxiliaryWorkloads, &out.AuxiliaryWorkloads
+		*out = make([]AuxiliaryWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]ComponentParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schematic != nil {
+		in, out := &in.Schematic, &out.Schematic
+		*out = new(Schematic)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
+func (in *ComponentSpec) DeepCopy() *ComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.LatestRevision != nil {
+		in, out := &in.LatestRevision, &out.LatestRevision
+		*out = new(Revision)
+		**out = **in
+	}
+	if in.RevisionDiff != nil {
+		in, out := &in.RevisionDiff, &out.RevisionDiff
+		*out = new(RevisionDiff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ParameterSchema != nil {
+		in, out := &in.ParameterSchema, &out.ParameterSchema
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTrait) DeepCopyInto(out *ComponentTrait) {
+	*out = *in
+	in.Trait.DeepCopyInto(&out.Trait)
+	if in.DataOutputs != nil {
+		in, out := &in.DataOutputs, &out.DataOutputs
+		*out = make([]DataOutput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DataInputs != nil {
+		in, out := &in.DataInputs, &out.DataInputs
+		*out = make([]DataInput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTrait.
+func (in *ComponentTrait) DeepCopy() *ComponentTrait {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTrait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionRequirement) DeepCopyInto(out *ConditionRequirement) {
+	*out = *in
+	out.ValueFrom = in.ValueFrom
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionRequirement.
+func (in *ConditionRequirement) DeepCopy() *ConditionRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Container) DeepCopyInto(out *Container) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ContainerResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Environment != nil {
+		in, out := &in.Environment, &out.Environment
+		*out = make([]ContainerEnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]ContainerEnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigFiles != nil {
+		in, out := &in.ConfigFiles, &out.ConfigFiles
+		*out = make([]ContainerConfigFile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]ContainerPort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LivenessProbe != nil {
+		in, out := &in.LivenessProbe, &out.LivenessProbe
+		*out = new(ContainerHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ContainerHealthProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(ImagePullPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Container.
+func (in *Container) DeepCopy() *Container {
+	if in == nil {
+		return nil
+	}
+	out := new(Container)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerConfigFile) DeepCopyInto(out *ContainerConfigFile) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.FromSecret != nil {
+		in, out := &in.FromSecret, &out.FromSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfigFile.
+func (in *ContainerConfigFile) DeepCopy() *ContainerConfigFile {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerConfigFile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerEnvFromSource) DeepCopyInto(out *ContainerEnvFromSource) {
+	*out = *in
+	if in.FromSecret != nil {
+		in, out := &in.FromSecret, &out.FromSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.FromConfigMap != nil {
+		in, out := &in.FromConfigMap, &out.FromConfigMap
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerEnvFromSource.
+func (in *ContainerEnvFromSource) DeepCopy() *ContainerEnvFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerEnvFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerEnvVar) DeepCopyInto(out *ContainerEnvVar) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.FromSecret != nil {
+		in, out := &in.FromSecret, &out.FromSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.FromConfigMap != nil {
+		in, out := &in.FromConfigMap, &out.FromConfigMap
+		*out = new(ConfigMapKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerEnvVar.
+func (in *ContainerEnvVar) DeepCopy() *ContainerEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerHealthProbe) DeepCopyInto(out *ContainerHealthProbe) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(HTTPGetProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TCPSocket != nil {
+		in, out := &in.TCPSocket, &out.TCPSocket
+		*out = new(TCPSocketProbe)
+		**out = **in
+	}
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerHealthProbe.
+func (in *ContainerHealthProbe) DeepCopy() *ContainerHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerPort) DeepCopyInto(out *ContainerPort) {
+	*out = *in
+	if in.Protocol != nil {
+		in, out := &in.Protocol, &out.Protocol
+		*out = new(TransportProtocol)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerPort.
+func (in *ContainerPort) DeepCopy() *ContainerPort {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerProbeStatus) DeepCopyInto(out *ContainerProbeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerProbeStatus.
+func (in *ContainerProbeStatus) DeepCopy() *ContainerProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerResources) DeepCopyInto(out *ContainerResources) {
+	*out = *in
+	in.CPU.DeepCopyInto(&out.CPU)
+	in.Memory.DeepCopyInto(&out.Memory)
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(GPUResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]VolumeResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Extended != nil {
+		in, out := &in.Extended, &out.Extended
+		*out = make([]ExtendedResource, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResources.
+func (in *ContainerResources) DeepCopy() *ContainerResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkload) DeepCopyInto(out *ContainerizedWorkload) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkload.
+func (in *ContainerizedWorkload) DeepCopy() *ContainerizedWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContainerizedWorkload) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkloadList) DeepCopyInto(out *ContainerizedWorkloadList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContainerizedWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadList.
+func (in *ContainerizedWorkloadList) DeepCopy() *ContainerizedWorkloadList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkloadList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContainerizedWorkloadList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkloadSpec) DeepCopyInto(out *ContainerizedWorkloadSpec) {
+	*out = *in
+	if in.OperatingSystem != nil {
+		in, out := &in.OperatingSystem, &out.OperatingSystem
+		*out = new(OperatingSystem)
+		**out = **in
+	}
+	if in.CPUArchitecture != nil {
+		in, out := &in.CPUArchitecture, &out.CPUArchitecture
+		*out = new(CPUArchitecture)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadSpec.
+func (in *ContainerizedWorkloadSpec) DeepCopy() *ContainerizedWorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerizedWorkloadStatus) DeepCopyInto(out *ContainerizedWorkloadStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerProbeStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadStatus.
+func (in *ContainerizedWorkloadStatus) DeepCopy() *ContainerizedWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerizedWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonWorkload) DeepCopyInto(out *DaemonWorkload) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonWorkload.
+func (in *DaemonWorkload) DeepCopy() *DaemonWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DaemonWorkload) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonWorkloadList) DeepCopyInto(out *DaemonWorkloadList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DaemonWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonWorkloadList.
+func (in *DaemonWorkloadList) DeepCopy() *DaemonWorkloadList {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonWorkloadList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DaemonWorkloadList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonWorkloadSpec) DeepCopyInto(out *DaemonWorkloadSpec) {
+	*out = *in
+	in.ContainerizedWorkloadSpec.DeepCopyInto(&out.ContainerizedWorkloadSpec)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonWorkloadSpec.
+func (in *DaemonWorkloadSpec) DeepCopy() *DaemonWorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonWorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonWorkloadStatus) DeepCopyInto(out *DaemonWorkloadStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonWorkloadStatus.
+func (in *DaemonWorkloadStatus) DeepCopy() *DaemonWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataInput) DeepCopyInto(out *DataInput) {
+	*out = *in
+	out.ValueFrom = in.ValueFrom
+	if in.ToFieldPaths != nil {
+		in, out := &in.ToFieldPaths, &out.ToFieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInput.
+func (in *DataInput) DeepCopy() *DataInput {
+	if in == nil {
+		return nil
+	}
+	out := new(DataInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataInputValueFrom) DeepCopyInto(out *DataInputValueFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInputValueFrom.
+func (in *DataInputValueFrom) DeepCopy() *DataInputValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(DataInputValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataOutput) DeepCopyInto(out *DataOutput) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ConditionRequirement, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataOutput.
+func (in *DataOutput) DeepCopy() *DataOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(DataOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionCatalog) DeepCopyInto(out *DefinitionCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionCatalog.
+func (in *DefinitionCatalog) DeepCopy() *DefinitionCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefinitionCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionCatalogList) DeepCopyInto(out *DefinitionCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DefinitionCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionCatalogList.
+func (in *DefinitionCatalogList) DeepCopy() *DefinitionCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefinitionCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionCatalogSpec) DeepCopyInto(out *DefinitionCatalogSpec) {
+	*out = *in
+	if in.WorkloadDefinitions != nil {
+		in, out := &in.WorkloadDefinitions, &out.WorkloadDefinitions
+		*out = make([]CatalogWorkloadDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TraitDefinitions != nil {
+		in, out := &in.TraitDefinitions, &out.TraitDefinitions
+		*out = make([]CatalogTraitDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScopeDefinitions != nil {
+		in, out := &in.ScopeDefinitions, &out.ScopeDefinitions
+		*out = make([]CatalogScopeDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionCatalogSpec.
+func (in *DefinitionCatalogSpec) DeepCopy() *DefinitionCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionReference) DeepCopyInto(out *DefinitionReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionReference.
+func (in *DefinitionReference) DeepCopy() *DefinitionReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionRevision) DeepCopyInto(out *DefinitionRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionRevision.
+func (in *DefinitionRevision) DeepCopy() *DefinitionRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefinitionRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionRevisionList) DeepCopyInto(out *DefinitionRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DefinitionRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionRevisionList.
+func (in *DefinitionRevisionList) DeepCopy() *DefinitionRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefinitionRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionRevisionSpec) DeepCopyInto(out *DefinitionRevisionSpec) {
+	*out = *in
+	if in.WorkloadDefinition != nil {
+		in, out := &in.WorkloadDefinition, &out.WorkloadDefinition
+		*out = new(WorkloadDefinitionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TraitDefinition != nil {
+		in, out := &in.TraitDefinition, &out.TraitDefinition
+		*out = new(TraitDefinitionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionRevisionSpec.
+func (in *DefinitionRevisionSpec) DeepCopy() *DefinitionRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionStatus) DeepCopyInto(out *DefinitionStatus) {
+	*out = *in
+	if in.UsedBy != nil {
+		in, out := &in.UsedBy, &out.UsedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LatestRevision != nil {
+		in, out := &in.LatestRevision, &out.LatestRevision
+		*out = new(Revision)
+		**out = **in
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionStatus.
+func (in *DefinitionStatus) DeepCopy() *DefinitionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionUsagePolicy) DeepCopyInto(out *DefinitionUsagePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionUsagePolicy.
+func (in *DefinitionUsagePolicy) DeepCopy() *DefinitionUsagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionUsagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefinitionUsagePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionUsagePolicyList) DeepCopyInto(out *DefinitionUsagePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DefinitionUsagePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionUsagePolicyList.
+func (in *DefinitionUsagePolicyList) DeepCopy() *DefinitionUsagePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionUsagePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefinitionUsagePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionUsagePolicySpec) DeepCopyInto(out *DefinitionUsagePolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadDefinitions != nil {
+		in, out := &in.WorkloadDefinitions, &out.WorkloadDefinitions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TraitDefinitions != nil {
+		in, out := &in.TraitDefinitions, &out.TraitDefinitions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionUsagePolicySpec.
+func (in *DefinitionUsagePolicySpec) DeepCopy() *DefinitionUsagePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionUsagePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyFromObject) DeepCopyInto(out *DependencyFromObject) {
+	*out = *in
+	out.TypedReference = in.TypedReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyFromObject.
+func (in *DependencyFromObject) DeepCopy() *DependencyFromObject {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyFromObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyStatus) DeepCopyInto(out *DependencyStatus) {
+	*out = *in
+	if in.Unsatisfied != nil {
+		in, out := &in.Unsatisfied, &out.Unsatisfied
+		*out = make([]UnstaifiedDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyStatus.
+func (in *DependencyStatus) DeepCopy() *DependencyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DependencyToObject) DeepCopyInto(out *DependencyToObject) {
+	*out = *in
+	out.TypedReference = in.TypedReference
+	if in.FieldPaths != nil {
+		in, out := &in.FieldPaths, &out.FieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyToObject.
+func (in *DependencyToObject) DeepCopy() *DependencyToObject {
+	if in == nil {
+		return nil
+	}
+	out := new(DependencyToObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskResource) DeepCopyInto(out *DiskResource) {
+	*out = *in
+	out.Required = in.Required.DeepCopy()
+	if in.Ephemeral != nil {
+		in, out := &in.Ephemeral, &out.Ephemeral
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskResource.
+func (in *DiskResource) DeepCopy() *DiskResource {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventScalerTrait) DeepCopyInto(out *EventScalerTrait) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventScalerTrait.
+func (in *EventScalerTrait) DeepCopy() *EventScalerTrait {
+	if in == nil {
+		return nil
+	}
+	out := new(EventScalerTrait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventScalerTrait) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventScalerTraitList) DeepCopyInto(out *EventScalerTraitList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EventScalerTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventScalerTraitList.
+func (in *EventScalerTraitList) DeepCopy() *EventScalerTraitList {
+	if in == nil {
+		return nil
+	}
+	out := new(EventScalerTraitList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EventScalerTraitList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventScalerTraitSpec) DeepCopyInto(out *EventScalerTraitSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PollingIntervalSeconds != nil {
+		in, out := &in.PollingIntervalSeconds, &out.PollingIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriodSeconds != nil {
+		in, out := &in.CooldownPeriodSeconds, &out.CooldownPeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]EventScalerTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.WorkloadReference = in.WorkloadReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventScalerTraitSpec.
+func (in *EventScalerTraitSpec) DeepCopy() *EventScalerTraitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EventScalerTraitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventScalerTraitStatus) DeepCopyInto(out *EventScalerTraitStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.ScaleTargetReference != nil {
+		in, out := &in.ScaleTargetReference, &out.ScaleTargetReference
+		*out = new(v1alpha1.TypedReference)
+		**out = **in
+	}
+	if in.ScaledObjectReference != nil {
+		in, out := &in.ScaledObjectReference, &out.ScaledObjectReference
+		*out = new(v1alpha1.TypedReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventScalerTraitStatus.
+func (in *EventScalerTraitStatus) DeepCopy() *EventScalerTraitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EventScalerTraitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventScalerTrigger) DeepCopyInto(out *EventScalerTrigger) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AuthenticationRef != nil {
+		in, out := &in.AuthenticationRef, &out.AuthenticationRef
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventScalerTrigger.
+func (in *EventScalerTrigger) DeepCopy() *EventScalerTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(EventScalerTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecProbe) DeepCopyInto(out *ExecProbe) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecProbe.
+func (in *ExecProbe) DeepCopy() *ExecProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtendedResource) DeepCopyInto(out *ExtendedResource) {
+	*out = *in
+	out.Required = in.Required
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedResource.
+func (in *ExtendedResource) DeepCopy() *ExtendedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtendedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUResources) DeepCopyInto(out *GPUResources) {
+	*out = *in
+	out.Required = in.Required.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUResources.
+func (in *GPUResources) DeepCopy() *GPUResources {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoTemplate) DeepCopyInto(out *GoTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoTemplate.
+func (in *GoTemplate) DeepCopy() *GoTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(GoTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGetProbe) DeepCopyInto(out *HTTPGetProbe) {
+	*out = *in
+	if in.HTTPHeaders != nil {
+		in, out := &in.HTTPHeaders, &out.HTTPHeaders
+		*out = make([]HTTPHeader, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGetProbe.
+func (in *HTTPGetProbe) DeepCopy() *HTTPGetProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGetProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
+func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCondition) DeepCopyInto(out *HealthCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCondition.
+func (in *HealthCondition) DeepCopy() *HealthCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthPolicy) DeepCopyInto(out *HealthPolicy) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]HealthCondition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthPolicy.
+func (in *HealthPolicy) DeepCopy() *HealthPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScope) DeepCopyInto(out *HealthScope) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScope.
+func (in *HealthScope) DeepCopy() *HealthScope {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealthScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScopeList) DeepCopyInto(out *HealthScopeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HealthScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeList.
+func (in *HealthScopeList) DeepCopy() *HealthScopeList {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScopeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealthScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScopeSpec) DeepCopyInto(out *HealthScopeSpec) {
+	*out = *in
+	if in.ProbeTimeout != nil {
+		in, out := &in.ProbeTimeout, &out.ProbeTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ProbeInterval != nil {
+		in, out := &in.ProbeInterval, &out.ProbeInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WorkloadReferences != nil {
+		in, out := &in.WorkloadReferences, &out.WorkloadReferences
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeSpec.
+func (in *HealthScopeSpec) DeepCopy() *HealthScopeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScopeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthScopeStatus) DeepCopyInto(out *HealthScopeStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	out.ScopeHealthCondition = in.ScopeHealthCondition
+	if in.WorkloadHealthConditions != nil {
+		in, out := &in.WorkloadHealthConditions, &out.WorkloadHealthConditions
+		*out = make([]*WorkloadHealthCondition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(WorkloadHealthCondition)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeStatus.
+func (in *HealthScopeStatus) DeepCopy() *HealthScopeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthScopeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryWorkload) DeepCopyInto(out *HistoryWorkload) {
+	*out = *in
+	out.Reference = in.Reference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryWorkload.
+func (in *HistoryWorkload) DeepCopy() *HistoryWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRule) DeepCopyInto(out *IngressRule) {
+	*out = *in
+	if in.ServicePort != nil {
+		in, out := &in.ServicePort, &out.ServicePort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRule.
+func (in *IngressRule) DeepCopy() *IngressRule {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTrait) DeepCopyInto(out *IngressTrait) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTrait.
+func (in *IngressTrait) DeepCopy() *IngressTrait {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTrait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IngressTrait) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTraitList) DeepCopyInto(out *IngressTraitList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IngressTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTraitList.
+func (in *IngressTraitList) DeepCopy() *IngressTraitList {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTraitList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IngressTraitList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTraitSpec) DeepCopyInto(out *IngressTraitSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]IngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLSSecretName != nil {
+		in, out := &in.TLSSecretName, &out.TLSSecretName
+		*out = new(string)
+		**out = **in
+	}
+	out.WorkloadReference = in.WorkloadReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTraitSpec.
+func (in *IngressTraitSpec) DeepCopy() *IngressTraitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTraitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTraitStatus) DeepCopyInto(out *IngressTraitStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.ServiceReference != nil {
+		in, out := &in.ServiceReference, &out.ServiceReference
+		*out = new(v1alpha1.TypedReference)
+		**out = **in
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTraitStatus.
+func (in *IngressTraitStatus) DeepCopy() *IngressTraitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTraitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManualScalerTrait) DeepCopyInto(out *ManualScalerTrait) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTrait.
+func (in *ManualScalerTrait) DeepCopy() *ManualScalerTrait {
+	if in == nil {
+		return nil
+	}
+	out := new(ManualScalerTrait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManualScalerTrait) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManualScalerTraitList) DeepCopyInto(out *ManualScalerTraitList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ManualScalerTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitList.
+func (in *ManualScalerTraitList) DeepCopy() *ManualScalerTraitList {
+	if in == nil {
+		return nil
+	}
+	out := new(ManualScalerTraitList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ManualScalerTraitList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManualScalerTraitSpec) DeepCopyInto(out *ManualScalerTraitSpec) {
+	*out = *in
+	out.WorkloadReference = in.WorkloadReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitSpec.
+func (in *ManualScalerTraitSpec) DeepCopy() *ManualScalerTraitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManualScalerTraitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManualScalerTraitStatus) DeepCopyInto(out *ManualScalerTraitStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitStatus.
+func (in *ManualScalerTraitStatus) DeepCopy() *ManualScalerTraitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManualScalerTraitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryResources) DeepCopyInto(out *MemoryResources) {
+	*out = *in
+	out.Required = in.Required.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryResources.
+func (in *MemoryResources) DeepCopy() *MemoryResources {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsTrait) DeepCopyInto(out *MetricsTrait) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsTrait.
+func (in *MetricsTrait) DeepCopy() *MetricsTrait {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsTrait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MetricsTrait) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsTraitList) DeepCopyInto(out *MetricsTraitList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MetricsTrait, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsTraitList.
+func (in *MetricsTraitList) DeepCopy() *MetricsTraitList {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsTraitList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MetricsTraitList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsTraitSpec) DeepCopyInto(out *MetricsTraitSpec) {
+	*out = *in
+	out.Port = in.Port
+	out.WorkloadReference = in.WorkloadReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsTraitSpec.
+func (in *MetricsTraitSpec) DeepCopy() *MetricsTraitSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsTraitSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsTraitStatus) DeepCopyInto(out *MetricsTraitStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.MonitorReference != nil {
+		in, out := &in.MonitorReference, &out.MonitorReference
+		*out = new(v1alpha1.TypedReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsTraitStatus.
+func (in *MetricsTraitStatus) DeepCopy() *MetricsTraitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsTraitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkScope) DeepCopyInto(out *NetworkScope) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkScope.
+func (in *NetworkScope) DeepCopy() *NetworkScope {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkScopeList) DeepCopyInto(out *NetworkScopeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NetworkScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkScopeList.
+func (in *NetworkScopeList) DeepCopy() *NetworkScopeList {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkScopeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NetworkScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkScopeSpec) DeepCopyInto(out *NetworkScopeSpec) {
+	*out = *in
+	if in.WorkloadReferences != nil {
+		in, out := &in.WorkloadReferences, &out.WorkloadReferences
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkScopeSpec.
+func (in *NetworkScopeSpec) DeepCopy() *NetworkScopeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkScopeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkScopeStatus) DeepCopyInto(out *NetworkScopeStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]NetworkScopeWorkload, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkScopeStatus.
+func (in *NetworkScopeStatus) DeepCopy() *NetworkScopeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkScopeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkScopeWorkload) DeepCopyInto(out *NetworkScopeWorkload) {
+	*out = *in
+	out.TargetWorkload = in.TargetWorkload
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkScopeWorkload.
+func (in *NetworkScopeWorkload) DeepCopy() *NetworkScopeWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkScopeWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterTransform) DeepCopyInto(out *ParameterTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterTransform.
+func (in *ParameterTransform) DeepCopy() *ParameterTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScope) DeepCopyInto(out *PlacementScope) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScope.
+func (in *PlacementScope) DeepCopy() *PlacementScope {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScopeList) DeepCopyInto(out *PlacementScopeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PlacementScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScopeList.
+func (in *PlacementScopeList) DeepCopy() *PlacementScopeList {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScopeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PlacementScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScopeSpec) DeepCopyInto(out *PlacementScopeSpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]PlacementZone, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadReferences != nil {
+		in, out := &in.WorkloadReferences, &out.WorkloadReferences
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScopeSpec.
+func (in *PlacementScopeSpec) DeepCopy() *PlacementScopeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScopeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScopeStatus) DeepCopyInto(out *PlacementScopeStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]PlacementScopeWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScopeStatus.
+func (in *PlacementScopeStatus) DeepCopy() *PlacementScopeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScopeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScopeWorkload) DeepCopyInto(out *PlacementScopeWorkload) {
+	*out = *in
+	out.TargetWorkload = in.TargetWorkload
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScopeWorkload.
+func (in *PlacementScopeWorkload) DeepCopy() *PlacementScopeWorkload {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScopeWorkload)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementZone) DeepCopyInto(out *PlacementZone) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementZone.
+func (in *PlacementZone) DeepCopy() *PlacementZone {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenderDiff) DeepCopyInto(out *RenderDiff) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceDiff, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenderDiff.
+func (in *RenderDiff) DeepCopy() *RenderDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(RenderDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDiff) DeepCopyInto(out *ResourceDiff) {
+	*out = *in
+	out.Reference = in.Reference
+	if in.FieldPaths != nil {
+		in, out := &in.FieldPaths, &out.FieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDiff.
+func (in *ResourceDiff) DeepCopy() *ResourceDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaScope) DeepCopyInto(out *ResourceQuotaScope) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaScope.
+func (in *ResourceQuotaScope) DeepCopy() *ResourceQuotaScope {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceQuotaScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaScopeList) DeepCopyInto(out *ResourceQuotaScopeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ResourceQuotaScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaScopeList.
+func (in *ResourceQuotaScopeList) DeepCopy() *ResourceQuotaScopeList {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaScopeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceQuotaScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaScopeSpec) DeepCopyInto(out *ResourceQuotaScopeSpec) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.WorkloadReferences != nil {
+		in, out := &in.WorkloadReferences, &out.WorkloadReferences
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerEnvVar.
-func (in *ContainerEnvVar) DeepCopy() *ContainerEnvVar {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaScopeSpec.
+func (in *ResourceQuotaScopeSpec) DeepCopy() *ResourceQuotaScopeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerEnvVar)
+	out := new(ResourceQuotaScopeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerHealthProbe) DeepCopyInto(out *ContainerHealthProbe) {
+func (in *ResourceQuotaScopeStatus) DeepCopyInto(out *ResourceQuotaScopeStatus) {
 	*out = *in
-	if in.Exec != nil {
-		in, out := &in.Exec, &out.Exec
-		*out = new(ExecProbe)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HTTPGet != nil {
-		in, out := &in.HTTPGet, &out.HTTPGet
-		*out = new(HTTPGetProbe)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TCPSocket != nil {
-		in, out := &in.TCPSocket, &out.TCPSocket
-		*out = new(TCPSocketProbe)
-		**out = **in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
 	}
-	if in.InitialDelaySeconds != nil {
-		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
-		*out = new(int32)
-		**out = **in
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
 	}
-	if in.PeriodSeconds != nil {
-		in, out := &in.PeriodSeconds, &out.PeriodSeconds
-		*out = new(int32)
-		**out = **in
+	if in.Exceeded != nil {
+		in, out := &in.Exceeded, &out.Exceeded
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
 	}
-	if in.TimeoutSeconds != nil {
-		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
-		*out = new(int32)
-		**out = **in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]ResourceQuotaScopeWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.SuccessThreshold != nil {
-		in, out := &in.SuccessThreshold, &out.SuccessThreshold
-		*out = new(int32)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaScopeStatus.
+func (in *ResourceQuotaScopeStatus) DeepCopy() *ResourceQuotaScopeStatus {
+	if in == nil {
+		return nil
 	}
-	if in.FailureThreshold != nil {
-		in, out := &in.FailureThreshold, &out.FailureThreshold
-		*out = new(int32)
-		**out = **in
+	out := new(ResourceQuotaScopeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaScopeWorkload) DeepCopyInto(out *ResourceQuotaScopeWorkload) {
+	*out = *in
+	out.TargetWorkload = in.TargetWorkload
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerHealthProbe.
-func (in *ContainerHealthProbe) DeepCopy() *ContainerHealthProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaScopeWorkload.
+func (in *ResourceQuotaScopeWorkload) DeepCopy() *ResourceQuotaScopeWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerHealthProbe)
+	out := new(ResourceQuotaScopeWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerPort) DeepCopyInto(out *ContainerPort) {
+func (in *Revision) DeepCopyInto(out *Revision) {
 	*out = *in
-	if in.Protocol != nil {
-		in, out := &in.Protocol, &out.Protocol
-		*out = new(TransportProtocol)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerPort.
-func (in *ContainerPort) DeepCopy() *ContainerPort {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Revision.
+func (in *Revision) DeepCopy() *Revision {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerPort)
+	out := new(Revision)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerResources) DeepCopyInto(out *ContainerResources) {
+func (in *RevisionDiff) DeepCopyInto(out *RevisionDiff) {
 	*out = *in
-	in.CPU.DeepCopyInto(&out.CPU)
-	in.Memory.DeepCopyInto(&out.Memory)
-	if in.GPU != nil {
-		in, out := &in.GPU, &out.GPU
-		*out = new(GPUResources)
-		(*in).DeepCopyInto(*out)
+	if in.FieldPaths != nil {
+		in, out := &in.FieldPaths, &out.FieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]VolumeResource, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevisionDiff.
+func (in *RevisionDiff) DeepCopy() *RevisionDiff {
+	if in == nil {
+		return nil
 	}
-	if in.Extended != nil {
-		in, out := &in.Extended, &out.Extended
-		*out = make([]ExtendedResource, len(*in))
-		copy(*out, *in)
+	out := new(RevisionDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutPromotion) DeepCopyInto(out *RolloutPromotion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPromotion.
+func (in *RolloutPromotion) DeepCopy() *RolloutPromotion {
+	if in == nil {
+		return nil
 	}
+	out := new(RolloutPromotion)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResources.
-func (in *ContainerResources) DeepCopy() *ContainerResources {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStep) DeepCopyInto(out *RolloutStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStep.
+func (in *RolloutStep) DeepCopy() *RolloutStep {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerResources)
+	out := new(RolloutStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkload) DeepCopyInto(out *ContainerizedWorkload) {
+func (in *RolloutTrait) DeepCopyInto(out *RolloutTrait) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -664,18 +3257,18 @@ func (in *ContainerizedWorkload) DeepCopyInto(out *ContainerizedWorkload) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkload.
-func (in *ContainerizedWorkload) DeepCopy() *ContainerizedWorkload {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTrait.
+func (in *RolloutTrait) DeepCopy() *RolloutTrait {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkload)
+	out := new(RolloutTrait)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContainerizedWorkload) DeepCopyObject() runtime.Object {
+func (in *RolloutTrait) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -683,31 +3276,31 @@ func (in *ContainerizedWorkload) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkloadList) DeepCopyInto(out *ContainerizedWorkloadList) {
+func (in *RolloutTraitList) DeepCopyInto(out *RolloutTraitList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ContainerizedWorkload, len(*in))
+		*out = make([]RolloutTrait, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadList.
-func (in *ContainerizedWorkloadList) DeepCopy() *ContainerizedWorkloadList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTraitList.
+func (in *RolloutTraitList) DeepCopy() *RolloutTraitList {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkloadList)
+	out := new(RolloutTraitList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ContainerizedWorkloadList) DeepCopyObject() runtime.Object {
+func (in *RolloutTraitList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -715,298 +3308,346 @@ func (in *ContainerizedWorkloadList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkloadSpec) DeepCopyInto(out *ContainerizedWorkloadSpec) {
+func (in *RolloutTraitSpec) DeepCopyInto(out *RolloutTraitSpec) {
 	*out = *in
-	if in.OperatingSystem != nil {
-		in, out := &in.OperatingSystem, &out.OperatingSystem
-		*out = new(OperatingSystem)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RolloutStep, len(*in))
+		copy(*out, *in)
+	}
+	if in.Promotion != nil {
+		in, out := &in.Promotion, &out.Promotion
+		*out = new(RolloutPromotion)
 		**out = **in
 	}
-	if in.CPUArchitecture != nil {
-		in, out := &in.CPUArchitecture, &out.CPUArchitecture
-		*out = new(CPUArchitecture)
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
 		**out = **in
 	}
-	if in.Containers != nil {
-		in, out := &in.Containers, &out.Containers
-		*out = make([]Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]WorkloadRevision, len(*in))
+		copy(*out, *in)
 	}
+	out.WorkloadReference = in.WorkloadReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadSpec.
-func (in *ContainerizedWorkloadSpec) DeepCopy() *ContainerizedWorkloadSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTraitSpec.
+func (in *RolloutTraitSpec) DeepCopy() *RolloutTraitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkloadSpec)
+	out := new(RolloutTraitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerizedWorkloadStatus) DeepCopyInto(out *ContainerizedWorkloadStatus) {
+func (in *RolloutTraitStatus) DeepCopyInto(out *RolloutTraitStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make([]v1alpha1.TypedReference, len(*in))
-		copy(*out, *in)
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerizedWorkloadStatus.
-func (in *ContainerizedWorkloadStatus) DeepCopy() *ContainerizedWorkloadStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTraitStatus.
+func (in *RolloutTraitStatus) DeepCopy() *RolloutTraitStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerizedWorkloadStatus)
+	out := new(RolloutTraitStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataInput) DeepCopyInto(out *DataInput) {
+func (in *Schematic) DeepCopyInto(out *Schematic) {
 	*out = *in
-	out.ValueFrom = in.ValueFrom
-	if in.ToFieldPaths != nil {
-		in, out := &in.ToFieldPaths, &out.ToFieldPaths
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.CUE != nil {
+		in, out := &in.CUE, &out.CUE
+		*out = new(CUE)
+		**out = **in
+	}
+	if in.GoTemplate != nil {
+		in, out := &in.GoTemplate, &out.GoTemplate
+		*out = new(GoTemplate)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInput.
-func (in *DataInput) DeepCopy() *DataInput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Schematic.
+func (in *Schematic) DeepCopy() *Schematic {
 	if in == nil {
 		return nil
 	}
-	out := new(DataInput)
+	out := new(Schematic)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataInputValueFrom) DeepCopyInto(out *DataInputValueFrom) {
+func (in *ScopeDefinition) DeepCopyInto(out *ScopeDefinition) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataInputValueFrom.
-func (in *DataInputValueFrom) DeepCopy() *DataInputValueFrom {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinition.
+func (in *ScopeDefinition) DeepCopy() *ScopeDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(DataInputValueFrom)
+	out := new(ScopeDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScopeDefinition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataOutput) DeepCopyInto(out *DataOutput) {
+func (in *ScopeDefinitionList) DeepCopyInto(out *ScopeDefinitionList) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ConditionRequirement, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScopeDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataOutput.
-func (in *DataOutput) DeepCopy() *DataOutput {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionList.
+func (in *ScopeDefinitionList) DeepCopy() *ScopeDefinitionList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataOutput)
+	out := new(ScopeDefinitionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScopeDefinitionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DefinitionReference) DeepCopyInto(out *DefinitionReference) {
+func (in *ScopeDefinitionSpec) DeepCopyInto(out *ScopeDefinitionSpec) {
 	*out = *in
+	out.Reference = in.Reference
+	if in.AppliesToWorkloads != nil {
+		in, out := &in.AppliesToWorkloads, &out.AppliesToWorkloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Extension != nil {
+		in, out := &in.Extension, &out.Extension
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionReference.
-func (in *DefinitionReference) DeepCopy() *DefinitionReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionSpec.
+func (in *ScopeDefinitionSpec) DeepCopy() *ScopeDefinitionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DefinitionReference)
+	out := new(ScopeDefinitionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DependencyFromObject) DeepCopyInto(out *DependencyFromObject) {
+func (in *ScopeHealthCondition) DeepCopyInto(out *ScopeHealthCondition) {
 	*out = *in
-	out.TypedReference = in.TypedReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyFromObject.
-func (in *DependencyFromObject) DeepCopy() *DependencyFromObject {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeHealthCondition.
+func (in *ScopeHealthCondition) DeepCopy() *ScopeHealthCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(DependencyFromObject)
+	out := new(ScopeHealthCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DependencyStatus) DeepCopyInto(out *DependencyStatus) {
+func (in *ScopeTraits) DeepCopyInto(out *ScopeTraits) {
 	*out = *in
-	if in.Unsatisfied != nil {
-		in, out := &in.Unsatisfied, &out.Unsatisfied
-		*out = make([]UnstaifiedDependency, len(*in))
+	out.ScopeReference = in.ScopeReference
+	if in.Traits != nil {
+		in, out := &in.Traits, &out.Traits
+		*out = make([]ComponentTrait, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyStatus.
-func (in *DependencyStatus) DeepCopy() *DependencyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeTraits.
+func (in *ScopeTraits) DeepCopy() *ScopeTraits {
 	if in == nil {
 		return nil
 	}
-	out := new(DependencyStatus)
+	out := new(ScopeTraits)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DependencyToObject) DeepCopyInto(out *DependencyToObject) {
+func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
-	out.TypedReference = in.TypedReference
-	if in.FieldPaths != nil {
-		in, out := &in.FieldPaths, &out.FieldPaths
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DependencyToObject.
-func (in *DependencyToObject) DeepCopy() *DependencyToObject {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
+func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
 	if in == nil {
 		return nil
 	}
-	out := new(DependencyToObject)
+	out := new(SecretKeySelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DiskResource) DeepCopyInto(out *DiskResource) {
+func (in *SecurityScope) DeepCopyInto(out *SecurityScope) {
 	*out = *in
-	out.Required = in.Required.DeepCopy()
-	if in.Ephemeral != nil {
-		in, out := &in.Ephemeral, &out.Ephemeral
-		*out = new(bool)
-		**out = **in
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskResource.
-func (in *DiskResource) DeepCopy() *DiskResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityScope.
+func (in *SecurityScope) DeepCopy() *SecurityScope {
 	if in == nil {
 		return nil
 	}
-	out := new(DiskResource)
+	out := new(SecurityScope)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityScope) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExecProbe) DeepCopyInto(out *ExecProbe) {
+func (in *SecurityScopeList) DeepCopyInto(out *SecurityScopeList) {
 	*out = *in
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecurityScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecProbe.
-func (in *ExecProbe) DeepCopy() *ExecProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityScopeList.
+func (in *SecurityScopeList) DeepCopy() *SecurityScopeList {
 	if in == nil {
 		return nil
 	}
-	out := new(ExecProbe)
+	out := new(SecurityScopeList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExtendedResource) DeepCopyInto(out *ExtendedResource) {
-	*out = *in
-	out.Required = in.Required
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtendedResource.
-func (in *ExtendedResource) DeepCopy() *ExtendedResource {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecurityScopeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(ExtendedResource)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GPUResources) DeepCopyInto(out *GPUResources) {
+func (in *SecurityScopeSpec) DeepCopyInto(out *SecurityScopeSpec) {
 	*out = *in
-	out.Required = in.Required.DeepCopy()
+	if in.DropCapabilities != nil {
+		in, out := &in.DropCapabilities, &out.DropCapabilities
+		*out = make([]corev1.Capability, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadReferences != nil {
+		in, out := &in.WorkloadReferences, &out.WorkloadReferences
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUResources.
-func (in *GPUResources) DeepCopy() *GPUResources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityScopeSpec.
+func (in *SecurityScopeSpec) DeepCopy() *SecurityScopeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GPUResources)
+	out := new(SecurityScopeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPGetProbe) DeepCopyInto(out *HTTPGetProbe) {
+func (in *SecurityScopeStatus) DeepCopyInto(out *SecurityScopeStatus) {
 	*out = *in
-	if in.HTTPHeaders != nil {
-		in, out := &in.HTTPHeaders, &out.HTTPHeaders
-		*out = make([]HTTPHeader, len(*in))
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]SecurityScopeWorkload, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGetProbe.
-func (in *HTTPGetProbe) DeepCopy() *HTTPGetProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityScopeStatus.
+func (in *SecurityScopeStatus) DeepCopy() *SecurityScopeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPGetProbe)
+	out := new(SecurityScopeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPHeader) DeepCopyInto(out *HTTPHeader) {
+func (in *SecurityScopeWorkload) DeepCopyInto(out *SecurityScopeWorkload) {
 	*out = *in
+	out.TargetWorkload = in.TargetWorkload
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeader.
-func (in *HTTPHeader) DeepCopy() *HTTPHeader {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityScopeWorkload.
+func (in *SecurityScopeWorkload) DeepCopy() *SecurityScopeWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPHeader)
+	out := new(SecurityScopeWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScope) DeepCopyInto(out *HealthScope) {
+func (in *SidecarTrait) DeepCopyInto(out *SidecarTrait) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1014,18 +3655,18 @@ func (in *HealthScope) DeepCopyInto(out *HealthScope) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScope.
-func (in *HealthScope) DeepCopy() *HealthScope {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarTrait.
+func (in *SidecarTrait) DeepCopy() *SidecarTrait {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScope)
+	out := new(SidecarTrait)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealthScope) DeepCopyObject() runtime.Object {
+func (in *SidecarTrait) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1033,31 +3674,31 @@ func (in *HealthScope) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScopeList) DeepCopyInto(out *HealthScopeList) {
+func (in *SidecarTraitList) DeepCopyInto(out *SidecarTraitList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HealthScope, len(*in))
+		*out = make([]SidecarTrait, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeList.
-func (in *HealthScopeList) DeepCopy() *HealthScopeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarTraitList.
+func (in *SidecarTraitList) DeepCopy() *SidecarTraitList {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScopeList)
+	out := new(SidecarTraitList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HealthScopeList) DeepCopyObject() runtime.Object {
+func (in *SidecarTraitList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1065,100 +3706,107 @@ func (in *HealthScopeList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScopeSpec) DeepCopyInto(out *HealthScopeSpec) {
+func (in *SidecarTraitSpec) DeepCopyInto(out *SidecarTraitSpec) {
 	*out = *in
-	if in.ProbeTimeout != nil {
-		in, out := &in.ProbeTimeout, &out.ProbeTimeout
-		*out = new(int32)
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(ImagePullPolicy)
 		**out = **in
 	}
-	if in.ProbeInterval != nil {
-		in, out := &in.ProbeInterval, &out.ProbeInterval
-		*out = new(int32)
-		**out = **in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.WorkloadReferences != nil {
-		in, out := &in.WorkloadReferences, &out.WorkloadReferences
-		*out = make([]v1alpha1.TypedReference, len(*in))
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Environment != nil {
+		in, out := &in.Environment, &out.Environment
+		*out = make([]ContainerEnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]ContainerEnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]SidecarVolumeMount, len(*in))
 		copy(*out, *in)
 	}
+	out.WorkloadReference = in.WorkloadReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeSpec.
-func (in *HealthScopeSpec) DeepCopy() *HealthScopeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarTraitSpec.
+func (in *SidecarTraitSpec) DeepCopy() *SidecarTraitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScopeSpec)
+	out := new(SidecarTraitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HealthScopeStatus) DeepCopyInto(out *HealthScopeStatus) {
+func (in *SidecarTraitStatus) DeepCopyInto(out *SidecarTraitStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.ScopeHealthCondition = in.ScopeHealthCondition
-	if in.WorkloadHealthConditions != nil {
-		in, out := &in.WorkloadHealthConditions, &out.WorkloadHealthConditions
-		*out = make([]*WorkloadHealthCondition, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(WorkloadHealthCondition)
-				**out = **in
-			}
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthScopeStatus.
-func (in *HealthScopeStatus) DeepCopy() *HealthScopeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarTraitStatus.
+func (in *SidecarTraitStatus) DeepCopy() *SidecarTraitStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HealthScopeStatus)
+	out := new(SidecarTraitStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HistoryWorkload) DeepCopyInto(out *HistoryWorkload) {
+func (in *SidecarVolumeMount) DeepCopyInto(out *SidecarVolumeMount) {
 	*out = *in
-	out.Reference = in.Reference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryWorkload.
-func (in *HistoryWorkload) DeepCopy() *HistoryWorkload {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarVolumeMount.
+func (in *SidecarVolumeMount) DeepCopy() *SidecarVolumeMount {
 	if in == nil {
 		return nil
 	}
-	out := new(HistoryWorkload)
+	out := new(SidecarVolumeMount)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTrait) DeepCopyInto(out *ManualScalerTrait) {
+func (in *StatefulWorkload) DeepCopyInto(out *StatefulWorkload) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTrait.
-func (in *ManualScalerTrait) DeepCopy() *ManualScalerTrait {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulWorkload.
+func (in *StatefulWorkload) DeepCopy() *StatefulWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTrait)
+	out := new(StatefulWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManualScalerTrait) DeepCopyObject() runtime.Object {
+func (in *StatefulWorkload) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1166,31 +3814,31 @@ func (in *ManualScalerTrait) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTraitList) DeepCopyInto(out *ManualScalerTraitList) {
+func (in *StatefulWorkloadList) DeepCopyInto(out *StatefulWorkloadList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ManualScalerTrait, len(*in))
+		*out = make([]StatefulWorkload, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitList.
-func (in *ManualScalerTraitList) DeepCopy() *ManualScalerTraitList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulWorkloadList.
+func (in *StatefulWorkloadList) DeepCopy() *StatefulWorkloadList {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTraitList)
+	out := new(StatefulWorkloadList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ManualScalerTraitList) DeepCopyObject() runtime.Object {
+func (in *StatefulWorkloadList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1198,88 +3846,237 @@ func (in *ManualScalerTraitList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTraitSpec) DeepCopyInto(out *ManualScalerTraitSpec) {
+func (in *StatefulWorkloadSpec) DeepCopyInto(out *StatefulWorkloadSpec) {
 	*out = *in
-	out.WorkloadReference = in.WorkloadReference
+	in.ContainerizedWorkloadSpec.DeepCopyInto(&out.ContainerizedWorkloadSpec)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodManagementPolicy != nil {
+		in, out := &in.PodManagementPolicy, &out.PodManagementPolicy
+		*out = new(PodManagementPolicy)
+		**out = **in
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitSpec.
-func (in *ManualScalerTraitSpec) DeepCopy() *ManualScalerTraitSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulWorkloadSpec.
+func (in *StatefulWorkloadSpec) DeepCopy() *StatefulWorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulWorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulWorkloadStatus) DeepCopyInto(out *StatefulWorkloadStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulWorkloadStatus.
+func (in *StatefulWorkloadStatus) DeepCopy() *StatefulWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusField) DeepCopyInto(out *StatusField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusField.
+func (in *StatusField) DeepCopy() *StatusField {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPSocketProbe) DeepCopyInto(out *TCPSocketProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPSocketProbe.
+func (in *TCPSocketProbe) DeepCopy() *TCPSocketProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPSocketProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskWorkload) DeepCopyInto(out *TaskWorkload) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskWorkload.
+func (in *TaskWorkload) DeepCopy() *TaskWorkload {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTraitSpec)
+	out := new(TaskWorkload)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskWorkload) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ManualScalerTraitStatus) DeepCopyInto(out *ManualScalerTraitStatus) {
+func (in *TaskWorkloadList) DeepCopyInto(out *TaskWorkloadList) {
 	*out = *in
-	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TaskWorkload, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualScalerTraitStatus.
-func (in *ManualScalerTraitStatus) DeepCopy() *ManualScalerTraitStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskWorkloadList.
+func (in *TaskWorkloadList) DeepCopy() *TaskWorkloadList {
 	if in == nil {
 		return nil
 	}
-	out := new(ManualScalerTraitStatus)
+	out := new(TaskWorkloadList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TaskWorkloadList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryResources) DeepCopyInto(out *MemoryResources) {
+func (in *TaskWorkloadSpec) DeepCopyInto(out *TaskWorkloadSpec) {
 	*out = *in
-	out.Required = in.Required.DeepCopy()
+	in.ContainerizedWorkloadSpec.DeepCopyInto(&out.ContainerizedWorkloadSpec)
+	if in.Completions != nil {
+		in, out := &in.Completions, &out.Completions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryResources.
-func (in *MemoryResources) DeepCopy() *MemoryResources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskWorkloadSpec.
+func (in *TaskWorkloadSpec) DeepCopy() *TaskWorkloadSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryResources)
+	out := new(TaskWorkloadSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Revision) DeepCopyInto(out *Revision) {
+func (in *TaskWorkloadStatus) DeepCopyInto(out *TaskWorkloadStatus) {
 	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Revision.
-func (in *Revision) DeepCopy() *Revision {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskWorkloadStatus.
+func (in *TaskWorkloadStatus) DeepCopy() *TaskWorkloadStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Revision)
+	out := new(TaskWorkloadStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScopeDefinition) DeepCopyInto(out *ScopeDefinition) {
+func (in *TraitDefinition) DeepCopyInto(out *TraitDefinition) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinition.
-func (in *ScopeDefinition) DeepCopy() *ScopeDefinition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinition.
+func (in *TraitDefinition) DeepCopy() *TraitDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(ScopeDefinition)
+	out := new(TraitDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScopeDefinition) DeepCopyObject() runtime.Object {
+func (in *TraitDefinition) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1287,31 +4084,31 @@ func (in *ScopeDefinition) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScopeDefinitionList) DeepCopyInto(out *ScopeDefinitionList) {
+func (in *TraitDefinitionList) DeepCopyInto(out *TraitDefinitionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ScopeDefinition, len(*in))
+		*out = make([]TraitDefinition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionList.
-func (in *ScopeDefinitionList) DeepCopy() *ScopeDefinitionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionList.
+func (in *TraitDefinitionList) DeepCopy() *TraitDefinitionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ScopeDefinitionList)
+	out := new(TraitDefinitionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ScopeDefinitionList) DeepCopyObject() runtime.Object {
+func (in *TraitDefinitionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1319,9 +4116,39 @@ func (in *ScopeDefinitionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScopeDefinitionSpec) DeepCopyInto(out *ScopeDefinitionSpec) {
+func (in *TraitDefinitionSpec) DeepCopyInto(out *TraitDefinitionSpec) {
 	*out = *in
 	out.Reference = in.Reference
+	if in.WorkloadRefPaths != nil {
+		in, out := &in.WorkloadRefPaths, &out.WorkloadRefPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliesToWorkloads != nil {
+		in, out := &in.AppliesToWorkloads, &out.AppliesToWorkloads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConflictsWith != nil {
+		in, out := &in.ConflictsWith, &out.ConflictsWith
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthPolicy != nil {
+		in, out := &in.HealthPolicy, &out.HealthPolicy
+		*out = new(HealthPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatusFields != nil {
+		in, out := &in.StatusFields, &out.StatusFields
+		*out = make([]StatusField, len(*in))
+		copy(*out, *in)
+	}
 	if in.Extension != nil {
 		in, out := &in.Extension, &out.Extension
 		*out = new(runtime.RawExtension)
@@ -1329,81 +4156,105 @@ func (in *ScopeDefinitionSpec) DeepCopyInto(out *ScopeDefinitionSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeDefinitionSpec.
-func (in *ScopeDefinitionSpec) DeepCopy() *ScopeDefinitionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionSpec.
+func (in *TraitDefinitionSpec) DeepCopy() *TraitDefinitionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ScopeDefinitionSpec)
+	out := new(TraitDefinitionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ScopeHealthCondition) DeepCopyInto(out *ScopeHealthCondition) {
+func (in *UnstaifiedDependency) DeepCopyInto(out *UnstaifiedDependency) {
 	*out = *in
+	out.From = in.From
+	in.To.DeepCopyInto(&out.To)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopeHealthCondition.
-func (in *ScopeHealthCondition) DeepCopy() *ScopeHealthCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnstaifiedDependency.
+func (in *UnstaifiedDependency) DeepCopy() *UnstaifiedDependency {
 	if in == nil {
 		return nil
 	}
-	out := new(ScopeHealthCondition)
+	out := new(UnstaifiedDependency)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
+func (in *ValueFrom) DeepCopyInto(out *ValueFrom) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeySelector.
-func (in *SecretKeySelector) DeepCopy() *SecretKeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueFrom.
+func (in *ValueFrom) DeepCopy() *ValueFrom {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeySelector)
+	out := new(ValueFrom)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TCPSocketProbe) DeepCopyInto(out *TCPSocketProbe) {
+func (in *VolumeClaim) DeepCopyInto(out *VolumeClaim) {
 	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessMode != nil {
+		in, out := &in.AccessMode, &out.AccessMode
+		*out = new(VolumeAccessMode)
+		**out = **in
+	}
+	if in.SharingPolicy != nil {
+		in, out := &in.SharingPolicy, &out.SharingPolicy
+		*out = new(VolumeSharingPolicy)
+		**out = **in
+	}
+	out.Required = in.Required.DeepCopy()
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPSocketProbe.
-func (in *TCPSocketProbe) DeepCopy() *TCPSocketProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaim.
+func (in *VolumeClaim) DeepCopy() *VolumeClaim {
 	if in == nil {
 		return nil
 	}
-	out := new(TCPSocketProbe)
+	out := new(VolumeClaim)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TraitDefinition) DeepCopyInto(out *TraitDefinition) {
+func (in *VolumeClaimTrait) DeepCopyInto(out *VolumeClaimTrait) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinition.
-func (in *TraitDefinition) DeepCopy() *TraitDefinition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTrait.
+func (in *VolumeClaimTrait) DeepCopy() *VolumeClaimTrait {
 	if in == nil {
 		return nil
 	}
-	out := new(TraitDefinition)
+	out := new(VolumeClaimTrait)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TraitDefinition) DeepCopyObject() runtime.Object {
+func (in *VolumeClaimTrait) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1411,31 +4262,31 @@ func (in *TraitDefinition) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TraitDefinitionList) DeepCopyInto(out *TraitDefinitionList) {
+func (in *VolumeClaimTraitList) DeepCopyInto(out *VolumeClaimTraitList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TraitDefinition, len(*in))
+		*out = make([]VolumeClaimTrait, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionList.
-func (in *TraitDefinitionList) DeepCopy() *TraitDefinitionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTraitList.
+func (in *VolumeClaimTraitList) DeepCopy() *VolumeClaimTraitList {
 	if in == nil {
 		return nil
 	}
-	out := new(TraitDefinitionList)
+	out := new(VolumeClaimTraitList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TraitDefinitionList) DeepCopyObject() runtime.Object {
+func (in *VolumeClaimTraitList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1443,59 +4294,65 @@ func (in *TraitDefinitionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TraitDefinitionSpec) DeepCopyInto(out *TraitDefinitionSpec) {
+func (in *VolumeClaimTraitSpec) DeepCopyInto(out *VolumeClaimTraitSpec) {
 	*out = *in
-	out.Reference = in.Reference
-	if in.AppliesToWorkloads != nil {
-		in, out := &in.AppliesToWorkloads, &out.AppliesToWorkloads
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Extension != nil {
-		in, out := &in.Extension, &out.Extension
-		*out = new(runtime.RawExtension)
-		(*in).DeepCopyInto(*out)
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make([]VolumeClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
+	out.WorkloadReference = in.WorkloadReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TraitDefinitionSpec.
-func (in *TraitDefinitionSpec) DeepCopy() *TraitDefinitionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTraitSpec.
+func (in *VolumeClaimTraitSpec) DeepCopy() *VolumeClaimTraitSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TraitDefinitionSpec)
+	out := new(VolumeClaimTraitSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UnstaifiedDependency) DeepCopyInto(out *UnstaifiedDependency) {
+func (in *VolumeClaimTraitStatus) DeepCopyInto(out *VolumeClaimTraitStatus) {
 	*out = *in
-	out.From = in.From
-	in.To.DeepCopyInto(&out.To)
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1alpha1.TypedReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnstaifiedDependency.
-func (in *UnstaifiedDependency) DeepCopy() *UnstaifiedDependency {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeClaimTraitStatus.
+func (in *VolumeClaimTraitStatus) DeepCopy() *VolumeClaimTraitStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(UnstaifiedDependency)
+	out := new(VolumeClaimTraitStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ValueFrom) DeepCopyInto(out *ValueFrom) {
+func (in *VolumeObjectSource) DeepCopyInto(out *VolumeObjectSource) {
 	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueFrom.
-func (in *ValueFrom) DeepCopy() *ValueFrom {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeObjectSource.
+func (in *VolumeObjectSource) DeepCopy() *VolumeObjectSource {
 	if in == nil {
 		return nil
 	}
-	out := new(ValueFrom)
+	out := new(VolumeObjectSource)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1518,6 +4375,21 @@ func (in *VolumeResource) DeepCopyInto(out *VolumeResource) {
 		*out = new(DiskResource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FromConfigMap != nil {
+		in, out := &in.FromConfigMap, &out.FromConfigMap
+		*out = new(VolumeObjectSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FromSecret != nil {
+		in, out := &in.FromSecret, &out.FromSecret
+		*out = new(VolumeObjectSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FromPersistentVolumeClaim != nil {
+		in, out := &in.FromPersistentVolumeClaim, &out.FromPersistentVolumeClaim
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeResource.
@@ -1536,6 +4408,7 @@ func (in *WorkloadDefinition) DeepCopyInto(out *WorkloadDefinition) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadDefinition.
@@ -1599,6 +4472,26 @@ func (in *WorkloadDefinitionSpec) DeepCopyInto(out *WorkloadDefinitionSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Schematic != nil {
+		in, out := &in.Schematic, &out.Schematic
+		*out = new(Schematic)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthPolicy != nil {
+		in, out := &in.HealthPolicy, &out.HealthPolicy
+		*out = new(HealthPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatusFields != nil {
+		in, out := &in.StatusFields, &out.StatusFields
+		*out = make([]StatusField, len(*in))
+		copy(*out, *in)
+	}
 	if in.Extension != nil {
 		in, out := &in.Extension, &out.Extension
 		*out = new(runtime.RawExtension)
@@ -1632,10 +4525,33 @@ func (in *WorkloadHealthCondition) DeepCopy() *WorkloadHealthCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadRevision) DeepCopyInto(out *WorkloadRevision) {
+	*out = *in
+	out.Reference = in.Reference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadRevision.
+func (in *WorkloadRevision) DeepCopy() *WorkloadRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadScope) DeepCopyInto(out *WorkloadScope) {
 	*out = *in
 	out.Reference = in.Reference
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1alpha1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadScope.
@@ -1652,16 +4568,37 @@ func (in *WorkloadScope) DeepCopy() *WorkloadScope {
 func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 	*out = *in
 	out.Reference = in.Reference
+	if in.AuxiliaryWorkloads != nil {
+		in, out := &in.AuxiliaryWorkloads, &out.AuxiliaryWorkloads
+		*out = make([]AuxiliaryWorkloadStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Traits != nil {
 		in, out := &in.Traits, &out.Traits
 		*out = make([]WorkloadTrait, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Scopes != nil {
 		in, out := &in.Scopes, &out.Scopes
 		*out = make([]WorkloadScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]WorkloadRevision, len(*in))
 		copy(*out, *in)
 	}
+	if in.StatusFields != nil {
+		in, out := &in.StatusFields, &out.StatusFields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
@@ -1678,6 +4615,13 @@ func (in *WorkloadStatus) DeepCopy() *WorkloadStatus {
 func (in *WorkloadTrait) DeepCopyInto(out *WorkloadTrait) {
 	*out = *in
 	out.Reference = in.Reference
+	if in.StatusFields != nil {
+		in, out := &in.StatusFields, &out.StatusFields
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadTrait.
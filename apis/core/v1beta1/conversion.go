@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const errFmtUnexpectedHub = "unexpected conversion hub %T"
+
+// ConvertTo converts this Component to the hub version.
+func (in *Component) ConvertTo(h conversion.Hub) error {
+	dst, ok := h.(*v1alpha2.Component)
+	if !ok {
+		return errors.Errorf(errFmtUnexpectedHub, h)
+	}
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	in.Spec.DeepCopyInto(&dst.Spec)
+	in.Status.DeepCopyInto(&dst.Status)
+	dst.SetGroupVersionKind(v1alpha2.ComponentGroupVersionKind)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Component.
+func (in *Component) ConvertFrom(h conversion.Hub) error {
+	src, ok := h.(*v1alpha2.Component)
+	if !ok {
+		return errors.Errorf(errFmtUnexpectedHub, h)
+	}
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	src.Spec.DeepCopyInto(&in.Spec)
+	src.Status.DeepCopyInto(&in.Status)
+	in.SetGroupVersionKind(ComponentGroupVersionKind)
+	return nil
+}
+
+// ConvertTo converts this ApplicationConfiguration to the hub version.
+func (in *ApplicationConfiguration) ConvertTo(h conversion.Hub) error {
+	dst, ok := h.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return errors.Errorf(errFmtUnexpectedHub, h)
+	}
+	in.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	in.Spec.DeepCopyInto(&dst.Spec)
+	in.Status.DeepCopyInto(&dst.Status)
+	dst.SetGroupVersionKind(v1alpha2.ApplicationConfigurationGroupVersionKind)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this ApplicationConfiguration.
+func (in *ApplicationConfiguration) ConvertFrom(h conversion.Hub) error {
+	src, ok := h.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return errors.Errorf(errFmtUnexpectedHub, h)
+	}
+	src.ObjectMeta.DeepCopyInto(&in.ObjectMeta)
+	src.Spec.DeepCopyInto(&in.Spec)
+	src.Status.DeepCopyInto(&in.Status)
+	in.SetGroupVersionKind(ApplicationConfigurationGroupVersionKind)
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// The v1beta1 resources share their schema with v1alpha2. Types that change
+// in this version should be declared here, rather than aliased, and converted
+// in conversion.go.
+
+// A ComponentSpec defines the desired state of a Component.
+type ComponentSpec = v1alpha2.ComponentSpec
+
+// A ComponentStatus represents the observed state of a Component.
+type ComponentStatus = v1alpha2.ComponentStatus
+
+// An ApplicationConfigurationSpec defines the desired state of a
+// ApplicationConfiguration.
+type ApplicationConfigurationSpec = v1alpha2.ApplicationConfigurationSpec
+
+// An ApplicationConfigurationStatus represents the observed state of a
+// ApplicationConfiguration.
+type ApplicationConfigurationStatus = v1alpha2.ApplicationConfigurationStatus
+
+// +kubebuilder:object:root=true
+
+// A Component describes how an OAM workload kind may be instantiated.
+// +kubebuilder:resource:categories={crossplane,oam}
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:JSONPath=".spec.workload.kind",name=WORKLOAD-KIND,type=string
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+type Component struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ComponentSpec   `json:"spec,omitempty"`
+	Status ComponentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComponentList contains a list of Component.
+type ComponentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Component `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+
+// An ApplicationConfiguration represents an OAM application.
+// +kubebuilder:resource:shortName=appconfig,categories={crossplane,oam}
+// +kubebuilder:subresource:status
+type ApplicationConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationConfigurationSpec   `json:"spec,omitempty"`
+	Status ApplicationConfigurationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationConfigurationList contains a list of ApplicationConfiguration.
+type ApplicationConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApplicationConfiguration `json:"items"`
+}
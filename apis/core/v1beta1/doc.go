@@ -0,0 +1,24 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1beta1 contains resources relating to the Open Application Model.
+// Its resources are converted to and from those of package v1alpha2, which is
+// the version they are stored as.
+// See https://github.com/oam-dev/spec for more details.
+// +kubebuilder:object:generate=true
+// +groupName=core.oam.dev
+// +versionName=v1beta1
+package v1beta1
@@ -1,24 +1,36 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"io"
 	"os"
 	"strconv"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/go-logr/logr"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
 	appController "github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/bootstrap"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/definition"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/policy"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/certificate"
 	webhook "github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/applicationconfiguration"
 )
 
 var scheme = runtime.NewScheme()
@@ -37,11 +49,42 @@ func main() {
 	var certDir string
 	var webhookPort int
 	var useWebhook bool
+	var healthAddr string
+	var certArgs certificate.Config
+	var manageCerts bool
+	var webhookConfiguration string
+	var policyDir string
+	var limits applicationconfiguration.Limits
+	var rejectDeprecated bool
+	var bootstrapDefinitions bool
 	var controllerArgs controller.Args
 
 	flag.BoolVar(&useWebhook, "use-webhook", false, "Enable Admission Webhook")
 	flag.StringVar(&certDir, "webhook-cert-dir", "/k8s-webhook-server/serving-certs", "Admission webhook cert/key dir.")
 	flag.IntVar(&webhookPort, "webhook-port", 9443, "admission webhook listen address")
+	flag.BoolVar(&manageCerts, "webhook-manage-certs", false,
+		"Generate and rotate the admission webhook serving certificate, and patch its CA into the webhook configurations and the conversion of the CRDs.")
+	flag.StringVar(&certArgs.SecretName, "webhook-cert-secret", "webhook-server-cert",
+		"Name of the secret the generated admission webhook certificate is stored in.")
+	flag.StringVar(&certArgs.ServiceName, "webhook-service-name", "oam-kubernetes-runtime-webhook",
+		"Name of the service that exposes the admission webhook.")
+	flag.StringVar(&certArgs.ServiceNamespace, "webhook-service-namespace", "oam-system",
+		"Namespace of the service that exposes the admission webhook, and of the certificate secret.")
+	flag.StringVar(&webhookConfiguration, "webhook-configuration-name", "oam-kubernetes-runtime",
+		"Name of the validating and mutating webhook configurations the CA is patched into.")
+	flag.StringVar(&policyDir, "admission-policy-dir", "",
+		"Directory of policies the resources ApplicationConfigurations render to must satisfy at admission, e.g. CUE or CEL files.")
+	flag.IntVar(&limits.MaxComponents, "max-components-per-appconfig", 0,
+		"Maximum number of components of an ApplicationConfiguration, enforced at admission. 0 means no limit.")
+	flag.IntVar(&limits.MaxTraitsPerComponent, "max-traits-per-component", 0,
+		"Maximum number of traits of a component of an ApplicationConfiguration, enforced at admission. 0 means no limit.")
+	flag.IntVar(&limits.MaxRenderedObjectSize, "max-rendered-object-size", 0,
+		"Maximum size in bytes of each object an ApplicationConfiguration renders to, enforced at admission. 0 means no limit.")
+	flag.BoolVar(&rejectDeprecated, "reject-deprecated-definitions", false,
+		"Reject Components and ApplicationConfigurations that start using deprecated definitions at admission, rather than warning about them.")
+	flag.BoolVar(&bootstrapDefinitions, "bootstrap-definitions", false,
+		"Create or update the definitions of the workloads, traits and scopes shipped with the runtime at startup.")
+	flag.StringVar(&healthAddr, "health-addr", "0", "The address the health probe endpoint binds to, 0 disables it.")
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
@@ -51,7 +94,11 @@ func main() {
 	flag.IntVar(&logRetainDate, "log-retain-date", 7, "The number of days of logs history to retain.")
 	flag.BoolVar(&logCompress, "log-compress", true, "Enable compression on the rotated logs.")
 	flag.IntVar(&controllerArgs.RevisionLimit, "revision-limit", 50,
-		"RevisionLimit is the maximum number of revisions that will be maintained. The default value is 50.")
+		"RevisionLimit is the maximum number of revisions that will be maintained, unless a Component specifies its revisionHistoryLimit. The default value is 50.")
+	flag.StringVar(&controllerArgs.ClusterName, "cluster-name", "",
+		"The name of the cluster the runtime runs in. Workloads in PlacementScopes that list clusters are only applied if it is listed.")
+	flag.StringVar(&controllerArgs.VeleroNamespace, "velero-namespace", "velero",
+		"The namespace Velero runs in, where BackupTraits create their Backups and Schedules.")
 	flag.Parse()
 
 	// setup logging
@@ -80,15 +127,86 @@ func main() {
 		LeaderElectionNamespace: leaderElectionNamespace,
 		Port:                    webhookPort,
 		CertDir:                 certDir,
+		HealthProbeBindAddress:  healthAddr,
 	})
 	if err != nil {
 		oamLog.Error(err, "unable to create a controller manager")
 		os.Exit(1)
 	}
 
+	if err = util.IndexAppConfigsByRevisionName(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		oamLog.Error(err, "unable to index application configurations by component revision")
+		os.Exit(1)
+	}
+	if err = util.IndexAppConfigsByComponentName(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		oamLog.Error(err, "unable to index application configurations by component")
+		os.Exit(1)
+	}
+	if err = util.IndexAppConfigsByConsumedRevisionName(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		oamLog.Error(err, "unable to index application configurations by consumed component revision")
+		os.Exit(1)
+	}
+	if err = util.IndexComponentsByWorkloadDefinition(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		oamLog.Error(err, "unable to index components by workload definition")
+		os.Exit(1)
+	}
+	if err = util.IndexAppConfigsByTraitDefinition(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		oamLog.Error(err, "unable to index application configurations by trait definition")
+		os.Exit(1)
+	}
+	if err = util.IndexAppConfigsByScopeDefinition(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		oamLog.Error(err, "unable to index application configurations by scope definition")
+		os.Exit(1)
+	}
+	if err = util.IndexAppConfigsByScopeReference(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		oamLog.Error(err, "unable to index application configurations by scope")
+		os.Exit(1)
+	}
+	for kind, s := range map[string]oam.Scope{
+		v1alpha2.HealthScopeKind:        &v1alpha2.HealthScope{},
+		v1alpha2.NetworkScopeKind:       &v1alpha2.NetworkScope{},
+		v1alpha2.ResourceQuotaScopeKind: &v1alpha2.ResourceQuotaScope{},
+		v1alpha2.SecurityScopeKind:      &v1alpha2.SecurityScope{},
+		v1alpha2.PlacementScopeKind:     &v1alpha2.PlacementScope{},
+	} {
+		if err = util.IndexScopesByWorkloadReference(context.Background(), mgr.GetFieldIndexer(), s); err != nil {
+			oamLog.Error(err, "unable to index scopes by workload", "kind", kind)
+			os.Exit(1)
+		}
+	}
+
+	if bootstrapDefinitions {
+		if err = installCoreDefinitions(mgr); err != nil {
+			oamLog.Error(err, "unable to install the core definitions")
+			os.Exit(1)
+		}
+	}
+
+	// definitions are read for every workload, trait and scope, by both the
+	// controllers and the webhook
+	definitions, err := definition.NewCache(context.Background(), mgr.GetCache(), mgr.GetClient())
+	if err != nil {
+		oamLog.Error(err, "unable to create the definition cache")
+		os.Exit(1)
+	}
+	controllerArgs.DefinitionClient = definitions
+
 	if useWebhook {
 		oamLog.Info("OAM webhook enabled, will serving at :" + strconv.Itoa(webhookPort))
-		if err = webhook.Add(mgr); err != nil {
+		if manageCerts {
+			if err = setupCertificate(mgr, certDir, certArgs, webhookConfiguration, oamLog); err != nil {
+				oamLog.Error(err, "unable to setup the webhook certificate")
+				os.Exit(1)
+			}
+		}
+		o := webhook.Options{Limits: limits, RejectDeprecatedDefinitions: rejectDeprecated, DefinitionClient: definitions}
+		if policyDir != "" {
+			if o.Policies, err = policy.LoadDir(policyDir, policy.DefaultEngines); err != nil {
+				oamLog.Error(err, "unable to load the admission policies")
+				os.Exit(1)
+			}
+		}
+		if err = webhook.AddWithOptions(mgr, o); err != nil {
 			oamLog.Error(err, "unable to setup the webhook for core controller")
 			os.Exit(1)
 		}
@@ -105,3 +223,38 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// installCoreDefinitions creates or updates the definitions shipped with the
+// runtime before the controllers start.
+func installCoreDefinitions(mgr ctrl.Manager) error {
+	// the cache of the manager hasn't started yet
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return err
+	}
+	return bootstrap.Install(context.Background(), resource.NewAPIPatchingApplicator(c), bootstrap.CoreDefinitions()...)
+}
+
+// setupCertificate ensures the webhook serving certificate before the webhook
+// server starts, and rotates it while the manager runs.
+func setupCertificate(mgr ctrl.Manager, certDir string, cfg certificate.Config, webhookConfiguration string, l logr.Logger) error {
+	// the cache of the manager hasn't started yet
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return err
+	}
+	cfg.CertDir = certDir
+	cfg.SecretNamespace = cfg.ServiceNamespace
+	cfg.ValidatingWebhookConfigurations = []string{webhookConfiguration}
+	cfg.MutatingWebhookConfigurations = []string{webhookConfiguration}
+	cfg.CustomResourceDefinitions = []string{"components.core.oam.dev", "applicationconfigurations.core.oam.dev"}
+	cfg.ConversionPath = webhook.ConversionPath
+	r := certificate.NewRotator(c, cfg, logging.NewLogrLogger(l.WithName("certificate")))
+	if err := r.Ensure(context.Background()); err != nil {
+		return err
+	}
+	if err := mgr.AddReadyzCheck("webhook-certificate", r.Ready); err != nil {
+		return err
+	}
+	return mgr.Add(r)
+}
@@ -3,15 +3,19 @@ module github.com/crossplane/oam-kubernetes-runtime
 go 1.13
 
 require (
+	cuelang.org/go v0.2.2
 	github.com/crossplane/crossplane-runtime v0.8.0
 	github.com/davecgh/go-spew v1.1.1
+	github.com/evanphx/json-patch v4.5.0+incompatible
 	github.com/ghodss/yaml v1.0.0
 	github.com/go-logr/logr v0.1.0
+	github.com/google/cel-go v0.5.1
 	github.com/google/go-cmp v0.4.0
 	github.com/json-iterator/go v1.1.8
 	github.com/onsi/ginkgo v1.11.0
 	github.com/onsi/gomega v1.8.1
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.1.0
 	github.com/stretchr/testify v1.4.0
 	go.uber.org/zap v1.10.0
 	golang.org/x/tools v0.0.0-20200630223951-c138986dd9b9 // indirect
@@ -13,9 +13,33 @@ limitations under the License.
 
 package controller
 
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/health"
+)
+
 // Args args used by controller
 type Args struct {
-	// RevisionLimit is the maximum number of revisions that will be maintained.
+	// RevisionLimit is the maximum number of revisions that will be maintained,
+	// unless a Component specifies its revisionHistoryLimit.
 	// The default value is 50.
 	RevisionLimit int
+
+	// DefinitionClient reads WorkloadDefinitions, TraitDefinitions and
+	// ScopeDefinitions, e.g. from a definition cache shared with the webhook.
+	// The client of the manager is used if it is nil.
+	DefinitionClient client.Client
+
+	// HealthCheckers check the health of workloads of the kinds they are
+	// registered for in HealthScopes, before the built-in checkers do.
+	HealthCheckers *health.Registry
+
+	// ClusterName is the name of the cluster the runtime runs in. Workloads
+	// in PlacementScopes that list clusters are only applied if it is listed.
+	ClusterName string
+
+	// VeleroNamespace is the namespace Velero runs in, where BackupTraits
+	// create their Backups and Schedules.
+	VeleroNamespace string
 }
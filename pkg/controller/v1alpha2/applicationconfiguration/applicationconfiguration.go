@@ -19,6 +19,7 @@ package applicationconfiguration
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -42,7 +43,8 @@ import (
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
-	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/render"
 )
 
 const (
@@ -71,12 +73,16 @@ const (
 	reasonExecutePosthook         = "ExecutePosthook"
 	reasonApplyComponents         = "AppliedComponents"
 	reasonGGComponent             = "GarbageCollectedComponent"
+	reasonRollbackComponent       = "RolledBackComponent"
 	reasonCannotExecutePrehooks   = "CannotExecutePrehooks"
 	reasonCannotExecutePosthooks  = "CannotExecutePosthooks"
 	reasonCannotRenderComponents  = "CannotRenderComponents"
 	reasonCannotApplyComponents   = "CannotApplyComponents"
 	reasonCannotGGComponents      = "CannotGarbageCollectComponents"
 	reasonCannotFinalizeWorkloads = "CannotFinalizeWorkloads"
+	reasonCannotComputeRenderDiff = "CannotComputeRenderDiff"
+	reasonCannotExtractStatus     = "CannotExtractStatusFields"
+	reasonCannotReadScopeStatus   = "CannotReadScopeStatus"
 )
 
 // Setup adds a controller that reconciles ApplicationConfigurations.
@@ -87,6 +93,18 @@ func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
 	}
 	name := "oam/" + strings.ToLower(v1alpha2.ApplicationConfigurationGroupKind)
 
+	o := []ReconcilerOption{
+		WithLogger(l.WithValues("controller", name)),
+		WithRecorder(metrics.NewRecorder(name, event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+	}
+	c := mgr.GetClient()
+	if args.DefinitionClient != nil {
+		c = args.DefinitionClient
+		o = append(o, WithRenderer(render.NewComponentRenderer(c, dm)))
+	}
+	o = append(o, WithApplicator(&workloads{client: resource.NewAPIPatchingApplicator(mgr.GetClient()), rawClient: c, dm: dm,
+		cluster: args.ClusterName}))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha2.ApplicationConfiguration{}).
@@ -95,16 +113,14 @@ func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
 			Logger:        l,
 			RevisionLimit: args.RevisionLimit,
 		}).
-		Complete(NewReconciler(mgr, dm,
-			WithLogger(l.WithValues("controller", name)),
-			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+		Complete(NewReconciler(mgr, dm, o...))
 }
 
 // An OAMApplicationReconciler reconciles OAM ApplicationConfigurations by rendering and
 // instantiating their Components and Traits.
 type OAMApplicationReconciler struct {
 	client     client.Client
-	components ComponentRenderer
+	components render.ComponentRenderer
 	workloads  WorkloadApplicator
 	gc         GarbageCollector
 	scheme     *runtime.Scheme
@@ -118,7 +134,7 @@ type OAMApplicationReconciler struct {
 type ReconcilerOption func(*OAMApplicationReconciler)
 
 // WithRenderer specifies how the Reconciler should render workloads and traits.
-func WithRenderer(r ComponentRenderer) ReconcilerOption {
+func WithRenderer(r render.ComponentRenderer) ReconcilerOption {
 	return func(rc *OAMApplicationReconciler) {
 		rc.components = r
 	}
@@ -172,15 +188,9 @@ func WithPosthook(name string, hook ControllerHooks) ReconcilerOption {
 // by rendering and instantiating their Components and Traits.
 func NewReconciler(m ctrl.Manager, dm discoverymapper.DiscoveryMapper, o ...ReconcilerOption) *OAMApplicationReconciler {
 	r := &OAMApplicationReconciler{
-		client: m.GetClient(),
-		scheme: m.GetScheme(),
-		components: &components{
-			client:   m.GetClient(),
-			dm:       dm,
-			params:   ParameterResolveFn(resolve),
-			workload: ResourceRenderFn(renderWorkload),
-			trait:    ResourceRenderFn(renderTrait),
-		},
+		client:     m.GetClient(),
+		scheme:     m.GetScheme(),
+		components: render.NewComponentRenderer(m.GetClient(), dm),
 		workloads: &workloads{
 			client:    resource.NewAPIPatchingApplicator(m.GetClient()),
 			rawClient: m.GetClient(),
@@ -277,7 +287,13 @@ func (r *OAMApplicationReconciler) Reconcile(req reconcile.Request) (result reco
 	log.Debug("Successfully rendered components", "workloads", len(workloads))
 	r.record.Event(ac, event.Normal(reasonRenderComponents, "Successfully rendered components", "workloads", strconv.Itoa(len(workloads))))
 
-	if err := r.workloads.Apply(ctx, ac.Status.Workloads, workloads, resource.MustBeControllableBy(ac.GetUID())); err != nil {
+	if dryRun := r.updateRenderDiff(ctx, ac, workloads); dryRun {
+		log.Debug("Skip applying components in render diff dry-run mode")
+		// the posthook function will do the final status update
+		return reconcile.Result{RequeueAfter: longWait}, nil
+	}
+
+	if err := r.workloads.Apply(ctx, ac, workloads, resource.MustBeControllableBy(ac.GetUID())); err != nil {
 		log.Debug("Cannot apply components", "error", err, "requeue-after", time.Now().Add(shortWait))
 		r.record.Event(ac, event.Warning(reasonCannotApplyComponents, err))
 		ac.SetConditions(v1alpha1.ReconcileError(errors.Wrap(err, errApplyComponents)))
@@ -286,6 +302,12 @@ func (r *OAMApplicationReconciler) Reconcile(req reconcile.Request) (result reco
 	log.Debug("Successfully applied components", "workloads", len(workloads))
 	r.record.Event(ac, event.Normal(reasonApplyComponents, "Successfully applied components", "workloads", strconv.Itoa(len(workloads))))
 
+	for _, rb := range rollbacks(ac.Status.Workloads, workloads) {
+		log.Debug("Rolled back component", "component", rb.ComponentName, "from", rb.From, "to", rb.To)
+		r.record.Event(ac, event.Normal(reasonRollbackComponent, "Rolled back component to a previous revision",
+			"component", rb.ComponentName, "from", rb.From, "to", rb.To))
+	}
+
 	// Kubernetes garbage collection will (by default) reap workloads and traits
 	// when the appconfig that controls them (in the controller reference sense)
 	// is deleted. Here we cover the case in which a component or one of its
@@ -307,8 +329,10 @@ func (r *OAMApplicationReconciler) Reconcile(req reconcile.Request) (result reco
 		record.Event(ac, event.Normal(reasonGGComponent, "Successfully garbage collected component"))
 	}
 
+	histories := r.pruneRevisions(ctx, ac.GetName(), workloads)
+
 	// patch the final status on the client side, k8s sever can't merge them
-	r.updateStatus(ctx, ac, acPatch, workloads)
+	r.updateStatus(ctx, ac, acPatch, workloads, histories)
 
 	ac.Status.Dependency = v1alpha2.DependencyStatus{}
 	waitTime := longWait
@@ -321,24 +345,63 @@ func (r *OAMApplicationReconciler) Reconcile(req reconcile.Request) (result reco
 	return reconcile.Result{RequeueAfter: waitTime}, nil
 }
 
-func (r *OAMApplicationReconciler) updateStatus(ctx context.Context, ac, acPatch *v1alpha2.ApplicationConfiguration, workloads []Workload) {
+// updateRenderDiff records how the applied resources differ from the rendered
+// workloads in the status if render diff is enabled. It returns true if the
+// rendered workloads should not be applied.
+func (r *OAMApplicationReconciler) updateRenderDiff(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, workloads []Workload) bool {
+	mode := renderDiffMode(ac)
+	if mode == "" {
+		ac.Status.RenderDiff = nil
+		return false
+	}
+	d, err := computeRenderDiff(ctx, r.client, workloads, r.gc.Eligible(ac.GetNamespace(), ac.Status.Workloads, workloads))
+	if err != nil {
+		r.log.Debug("Cannot compute render diff", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotComputeRenderDiff, err))
+		ac.Status.RenderDiff = nil
+	} else {
+		ac.Status.RenderDiff = d
+	}
+	return mode == oam.RenderDiffDryRun
+}
+
+// pruneRevisions deletes the workloads of old revisions of revisionEnabled
+// components beyond their revision history limit, and the traits of revisions
+// whose workloads no longer exist. It returns the retained workloads of old
+// revisions keyed by component name.
+func (r *OAMApplicationReconciler) pruneRevisions(ctx context.Context, acName string, workloads []Workload) map[string][]unstructured.Unstructured {
+	retained := make(map[string][]unstructured.Unstructured)
+	for _, w := range workloads {
+		if !w.RevisionEnabled {
+			continue
+		}
+		history, err := render.ListHistoryWorkloads(ctx, r.client, acName, w.ComponentName, w.ComponentRevisionName, w.Workload)
+		if err != nil {
+			r.log.Debug("Cannot list workloads of old revisions", "component", w.ComponentName, "error", err)
+			continue
+		}
+		history = r.pruneHistoryWorkloads(ctx, w.RevisionHistoryLimit, history)
+		r.pruneRevisionTraits(ctx, acName, w, history)
+		retained[w.ComponentName] = history
+	}
+	return retained
+}
+
+// updateStatus records the supplied workloads, and the running workloads of
+// old revisions of their components, in the status of the supplied
+// ApplicationConfiguration.
+func (r *OAMApplicationReconciler) updateStatus(ctx context.Context, ac, acPatch *v1alpha2.ApplicationConfiguration, workloads []Workload,
+	histories map[string][]unstructured.Unstructured) {
 	ac.Status.Workloads = make([]v1alpha2.WorkloadStatus, len(workloads))
 	historyWorkloads := make([]v1alpha2.HistoryWorkload, 0)
 	for i, w := range workloads {
 		ac.Status.Workloads[i] = workloads[i].Status()
-		if !w.RevisionEnabled {
-			continue
-		}
-		var ul unstructured.UnstructuredList
-		ul.SetKind(w.Workload.GetKind())
-		ul.SetAPIVersion(w.Workload.GetAPIVersion())
-		if err := r.client.List(ctx, &ul, client.MatchingLabels{oam.LabelAppName: ac.Name, oam.LabelAppComponent: w.ComponentName, oam.LabelOAMResourceType: oam.ResourceTypeWorkload}); err != nil {
+		history, ok := histories[w.ComponentName]
+		if !w.RevisionEnabled || !ok {
 			continue
 		}
-		for _, v := range ul.Items {
-			if v.GetName() == w.ComponentRevisionName {
-				continue
-			}
+		ac.Status.Workloads[i].Revisions = render.WorkloadRevisions(w.ComponentRevisionName, w.Workload, history)
+		for _, v := range history {
 			// These workload exists means the component is under progress of rollout
 			// Trait will not work for these remaining workload
 			historyWorkloads = append(historyWorkloads, v1alpha2.HistoryWorkload{
@@ -353,11 +416,78 @@ func (r *OAMApplicationReconciler) updateStatus(ctx context.Context, ac, acPatch
 		}
 	}
 	ac.Status.HistoryWorkloads = historyWorkloads
+	ac.Status.HealthStatus = updateHealth(ctx, r.client, ac.Status.Workloads, workloads)
+	if err := updateStatusFields(ctx, r.client, ac.Status.Workloads, workloads); err != nil {
+		r.log.Debug("Cannot extract status fields", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotExtractStatus, err))
+	}
+	if err := updateScopeStatus(ctx, r.client, ac.Status.Workloads, workloads); err != nil {
+		r.log.Debug("Cannot read scope status", "error", err)
+		r.record.Event(ac, event.Warning(reasonCannotReadScopeStatus, err))
+	}
 	// patch the extra fields in the status that is wiped by the Status() function
 	patchExtraStatusField(&ac.Status, acPatch.Status)
 	ac.SetConditions(v1alpha1.ReconcileSuccess())
 }
 
+// pruneHistoryWorkloads deletes the oldest of the supplied workloads of old
+// revisions beyond the supplied limit, and returns the retained ones.
+func (r *OAMApplicationReconciler) pruneHistoryWorkloads(ctx context.Context, limit *int32, history []unstructured.Unstructured) []unstructured.Unstructured {
+	if limit == nil || len(history) <= int(*limit) {
+		return history
+	}
+	// newest first
+	sort.SliceStable(history, func(i, j int) bool {
+		ci, cj := history[i].GetCreationTimestamp(), history[j].GetCreationTimestamp()
+		return cj.Before(&ci)
+	})
+	retained := append([]unstructured.Unstructured{}, history[:*limit]...)
+	for _, h := range history[*limit:] {
+		h := h
+		if err := r.client.Delete(ctx, &h); resource.IgnoreNotFound(err) != nil {
+			r.log.Debug("Cannot prune workload of old revision", "kind", h.GetKind(), "name", h.GetName(), "error", err)
+			retained = append(retained, h)
+			continue
+		}
+		r.log.Debug("Pruned workload of old revision", "kind", h.GetKind(), "name", h.GetName())
+	}
+	return retained
+}
+
+// pruneRevisionTraits deletes the traits of revisionEnabled TraitDefinitions
+// of the supplied workload that belong to revisions of its component whose
+// workloads no longer exist, e.g. because they were pruned.
+func (r *OAMApplicationReconciler) pruneRevisionTraits(ctx context.Context, acName string, w Workload, history []unstructured.Unstructured) {
+	live := map[string]bool{w.ComponentRevisionName: true}
+	for _, h := range history {
+		live[h.GetLabels()[oam.LabelAppComponentRevision]] = true
+	}
+	for _, t := range w.Traits {
+		if !t.Definition.Spec.RevisionEnabled {
+			continue
+		}
+		var l unstructured.UnstructuredList
+		l.SetAPIVersion(t.Object.GetAPIVersion())
+		l.SetKind(t.Object.GetKind() + "List")
+		if err := r.client.List(ctx, &l, client.InNamespace(w.Workload.GetNamespace()), client.MatchingLabels{oam.LabelAppName: acName,
+			oam.LabelAppComponent: w.ComponentName, oam.LabelOAMResourceType: oam.ResourceTypeTrait}); err != nil {
+			r.log.Debug("Cannot list traits of old revisions", "kind", t.Object.GetKind(), "error", err)
+			continue
+		}
+		for _, o := range l.Items {
+			o := o
+			if live[o.GetLabels()[oam.LabelAppComponentRevision]] {
+				continue
+			}
+			if err := r.client.Delete(ctx, &o); resource.IgnoreNotFound(err) != nil {
+				r.log.Debug("Cannot prune trait of old revision", "kind", o.GetKind(), "name", o.GetName(), "error", err)
+				continue
+			}
+			r.log.Debug("Pruned trait of old revision", "kind", o.GetKind(), "name", o.GetName())
+		}
+	}
+}
+
 func updateObservedGeneration(ac *v1alpha2.ApplicationConfiguration) {
 	if ac.Status.ObservedGeneration != ac.Generation {
 		ac.Status.ObservedGeneration = ac.Generation
@@ -409,73 +539,14 @@ func hasScope(ac *v1alpha2.ApplicationConfiguration) bool {
 }
 
 // A Workload produced by an OAM ApplicationConfiguration.
-type Workload struct {
-	// ComponentName that produced this workload.
-	ComponentName string
-
-	// ComponentRevisionName of current component
-	ComponentRevisionName string
-
-	// A Workload object.
-	Workload *unstructured.Unstructured
+type Workload = render.Workload
 
-	// HasDep indicates whether this resource has dependencies and unready to be applied.
-	HasDep bool
-
-	// Traits associated with this workload.
-	Traits []*Trait
-
-	// RevisionEnabled means multiple workloads of same component will possibly be alive.
-	RevisionEnabled bool
-
-	// Scopes associated with this workload.
-	Scopes []unstructured.Unstructured
-}
+// An AuxiliaryWorkload produced by an OAM ApplicationConfiguration alongside
+// a Workload.
+type AuxiliaryWorkload = render.AuxiliaryWorkload
 
 // A Trait produced by an OAM ApplicationConfiguration.
-type Trait struct {
-	Object unstructured.Unstructured
-
-	// HasDep indicates whether this resource has dependencies and unready to be applied.
-	HasDep bool
-
-	// Definition indicates the trait's definition
-	Definition v1alpha2.TraitDefinition
-}
-
-// Status produces the status of this workload and its traits, suitable for use
-// in the status of an ApplicationConfiguration.
-func (w Workload) Status() v1alpha2.WorkloadStatus {
-	acw := v1alpha2.WorkloadStatus{
-		ComponentName:         w.ComponentName,
-		ComponentRevisionName: w.ComponentRevisionName,
-		Reference: runtimev1alpha1.TypedReference{
-			APIVersion: w.Workload.GetAPIVersion(),
-			Kind:       w.Workload.GetKind(),
-			Name:       w.Workload.GetName(),
-		},
-		Traits: make([]v1alpha2.WorkloadTrait, len(w.Traits)),
-		Scopes: make([]v1alpha2.WorkloadScope, len(w.Scopes)),
-	}
-	for i, tr := range w.Traits {
-		if tr.Definition.Name == util.Dummy && tr.Definition.Spec.Reference.Name == util.Dummy {
-			acw.Traits[i].Message = util.DummyTraitMessage
-		}
-		acw.Traits[i].Reference = runtimev1alpha1.TypedReference{
-			APIVersion: w.Traits[i].Object.GetAPIVersion(),
-			Kind:       w.Traits[i].Object.GetKind(),
-			Name:       w.Traits[i].Object.GetName(),
-		}
-	}
-	for i, s := range w.Scopes {
-		acw.Scopes[i].Reference = runtimev1alpha1.TypedReference{
-			APIVersion: s.GetAPIVersion(),
-			Kind:       s.GetKind(),
-			Name:       s.GetName(),
-		}
-	}
-	return acw
-}
+type Trait = render.Trait
 
 // A GarbageCollector returns resource eligible for garbage collection. A
 // resource is considered eligible if a reference exists in the supplied slice
@@ -492,21 +563,72 @@ func (fn GarbageCollectorFn) Eligible(namespace string, ws []v1alpha2.WorkloadSt
 	return fn(namespace, ws, w)
 }
 
+// A revisionChange records that the active revision of a component changed.
+type revisionChange struct {
+	ComponentName string
+	From          string
+	To            string
+}
+
+// rollbacks returns the components whose supplied workloads are rendered from
+// a revision older than the active revision in the supplied workload statuses,
+// e.g. because they were pinned to a previous revision by revisionName.
+func rollbacks(ws []v1alpha2.WorkloadStatus, w []Workload) []revisionChange {
+	active := make(map[string]string, len(ws))
+	for _, s := range ws {
+		active[s.ComponentName] = s.ComponentRevisionName
+	}
+	changes := make([]revisionChange, 0)
+	for _, wl := range w {
+		from, ok := active[wl.ComponentName]
+		if !ok {
+			continue
+		}
+		fromRevision, okFrom := extractRevision(from)
+		toRevision, okTo := extractRevision(wl.ComponentRevisionName)
+		if okFrom && okTo && toRevision < fromRevision {
+			changes = append(changes, revisionChange{ComponentName: wl.ComponentName, From: from, To: wl.ComponentRevisionName})
+		}
+	}
+	return changes
+}
+
 // IsRevisionWorkload check is a workload is an old revision Workload which shouldn't be garbage collected.
 // TODO(wonderflow): Do we have a better way to recognise it's a revisionWorkload which can't be garbage collected by AppConfig?
 func IsRevisionWorkload(status v1alpha2.WorkloadStatus) bool {
 	return strings.HasPrefix(status.Reference.Name, status.ComponentName+"-")
 }
 
+// IsRevisionTrait checks if a trait is the trait of an old revision of its
+// component, which shouldn't be garbage collected because it lives as long as
+// the workload of its revision. Traits of revisionEnabled TraitDefinitions
+// are named after the revision of their component.
+func IsRevisionTrait(status v1alpha2.WorkloadStatus, trait v1alpha2.WorkloadTrait, revisionName string) bool {
+	if status.ComponentRevisionName == "" || status.ComponentRevisionName == revisionName {
+		return false
+	}
+	return strings.HasSuffix(trait.Reference.Name, "-"+render.RevisionSuffix(status.ComponentRevisionName))
+}
+
 func eligible(namespace string, ws []v1alpha2.WorkloadStatus, w []Workload) []unstructured.Unstructured {
 	applied := make(map[runtimev1alpha1.TypedReference]bool)
+	revisions := make(map[string]string, len(w))
 	for _, wl := range w {
+		revisions[wl.ComponentName] = wl.ComponentRevisionName
 		r := runtimev1alpha1.TypedReference{
 			APIVersion: wl.Workload.GetAPIVersion(),
 			Kind:       wl.Workload.GetKind(),
 			Name:       wl.Workload.GetName(),
 		}
 		applied[r] = true
+		for _, aw := range wl.AuxiliaryWorkloads {
+			r := runtimev1alpha1.TypedReference{
+				APIVersion: aw.Object.GetAPIVersion(),
+				Kind:       aw.Object.GetKind(),
+				Name:       aw.Object.GetName(),
+			}
+			applied[r] = true
+		}
 		for _, t := range wl.Traits {
 			r := runtimev1alpha1.TypedReference{
 				APIVersion: t.Object.GetAPIVersion(),
@@ -528,7 +650,22 @@ func eligible(namespace string, ws []v1alpha2.WorkloadStatus, w []Workload) []un
 			eligible = append(eligible, *w)
 		}
 
+		for _, as := range s.AuxiliaryWorkloads {
+			// auxiliary workloads live as long as their revision workload
+			if !applied[as.Reference] && !IsRevisionWorkload(s) {
+				a := &unstructured.Unstructured{}
+				a.SetAPIVersion(as.Reference.APIVersion)
+				a.SetKind(as.Reference.Kind)
+				a.SetNamespace(namespace)
+				a.SetName(as.Reference.Name)
+				eligible = append(eligible, *a)
+			}
+		}
+
 		for _, ts := range s.Traits {
+			if rev, ok := revisions[s.ComponentName]; ok && IsRevisionTrait(s, ts, rev) {
+				continue
+			}
 			if !applied[ts.Reference] {
 				t := &unstructured.Unstructured{}
 				t.SetAPIVersion(ts.Reference.APIVersion)
@@ -18,7 +18,6 @@ package applicationconfiguration
 
 import (
 	"context"
-	"encoding/json"
 	"testing"
 	"time"
 
@@ -30,9 +29,9 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -41,7 +40,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/render"
 )
 
 // OAMApplicationReconciler implements controller runtime Reconciler interface
@@ -178,7 +179,7 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return nil, &v1alpha2.DependencyStatus{}, errBoom
 					})),
 				},
@@ -204,10 +205,10 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return []Workload{{Workload: workload}}, &v1alpha2.DependencyStatus{}, nil
 					})),
-					WithApplicator(WorkloadApplyFns{ApplyFn: func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					WithApplicator(WorkloadApplyFns{ApplyFn: func(_ context.Context, _ *v1alpha2.ApplicationConfiguration, _ []Workload, _ ...resource.ApplyOption) error {
 						return errBoom
 					}}),
 				},
@@ -234,10 +235,10 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return []Workload{}, &v1alpha2.DependencyStatus{}, nil
 					})),
-					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ *v1alpha2.ApplicationConfiguration, _ []Workload, _ ...resource.ApplyOption) error {
 						return nil
 					})}),
 					WithGarbageCollector(GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
@@ -297,10 +298,10 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return []Workload{{ComponentName: componentName, Workload: workload}}, &depStatus, nil
 					})),
-					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ *v1alpha2.ApplicationConfiguration, _ []Workload, _ ...resource.ApplyOption) error {
 						return nil
 					})}),
 					WithGarbageCollector(GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
@@ -332,10 +333,10 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return []Workload{{ComponentName: componentName, Workload: workload}}, &v1alpha2.DependencyStatus{}, nil
 					})),
-					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ *v1alpha2.ApplicationConfiguration, _ []Workload, _ ...resource.ApplyOption) error {
 						return nil
 					})}),
 					WithGarbageCollector(GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
@@ -405,10 +406,10 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return []Workload{{ComponentName: componentName, Workload: workload}}, &v1alpha2.DependencyStatus{}, nil
 					})),
-					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ *v1alpha2.ApplicationConfiguration, _ []Workload, _ ...resource.ApplyOption) error {
 						return nil
 					})}),
 					WithGarbageCollector(GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
@@ -449,10 +450,10 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return []Workload{{ComponentName: componentName, Workload: workload}}, &v1alpha2.DependencyStatus{}, nil
 					})),
-					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ *v1alpha2.ApplicationConfiguration, _ []Workload, _ ...resource.ApplyOption) error {
 						return nil
 					})}),
 					WithGarbageCollector(GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
@@ -522,10 +523,10 @@ func TestReconciler(t *testing.T) {
 					},
 				},
 				o: []ReconcilerOption{
-					WithRenderer(ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
+					WithRenderer(render.ComponentRenderFn(func(_ context.Context, _ *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
 						return []Workload{{ComponentName: componentName, Workload: workload}}, &v1alpha2.DependencyStatus{}, nil
 					})),
-					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ []v1alpha2.WorkloadStatus, _ []Workload, _ ...resource.ApplyOption) error {
+					WithApplicator(WorkloadApplyFns{ApplyFn: (func(_ context.Context, _ *v1alpha2.ApplicationConfiguration, _ []Workload, _ ...resource.ApplyOption) error {
 						return nil
 					})}),
 					WithGarbageCollector(GarbageCollectorFn(func(_ string, _ []v1alpha2.WorkloadStatus, _ []Workload) []unstructured.Unstructured {
@@ -746,6 +747,12 @@ func TestEligible(t *testing.T) {
 	trait.SetNamespace(namespace)
 	trait.SetName("trait")
 
+	auxiliary := &unstructured.Unstructured{}
+	auxiliary.SetAPIVersion("v")
+	auxiliary.SetKind("auxiliary")
+	auxiliary.SetNamespace(namespace)
+	auxiliary.SetName("workload-aux")
+
 	type args struct {
 		namespace string
 		ws        []v1alpha2.WorkloadStatus
@@ -782,6 +789,60 @@ func TestEligible(t *testing.T) {
 			},
 			want: []unstructured.Unstructured{*trait},
 		},
+		"AuxiliaryWorkloadNotApplied": {
+			reason: "A referenced auxiliary workload is eligible for garbage collection if it was not applied",
+			args: args{
+				namespace: namespace,
+				ws: []v1alpha2.WorkloadStatus{
+					{
+						Reference: runtimev1alpha1.TypedReference{
+							APIVersion: workload.GetAPIVersion(),
+							Kind:       workload.GetKind(),
+							Name:       workload.GetName(),
+						},
+						AuxiliaryWorkloads: []v1alpha2.AuxiliaryWorkloadStatus{
+							{
+								Name: "aux",
+								Reference: runtimev1alpha1.TypedReference{
+									APIVersion: auxiliary.GetAPIVersion(),
+									Kind:       auxiliary.GetKind(),
+									Name:       auxiliary.GetName(),
+								},
+							},
+						},
+					},
+				},
+				w: []Workload{{Workload: workload}},
+			},
+			want: []unstructured.Unstructured{*auxiliary},
+		},
+		"AuxiliaryWorkloadApplied": {
+			reason: "A referenced auxiliary workload is not eligible for garbage collection if it was applied",
+			args: args{
+				namespace: namespace,
+				ws: []v1alpha2.WorkloadStatus{
+					{
+						Reference: runtimev1alpha1.TypedReference{
+							APIVersion: workload.GetAPIVersion(),
+							Kind:       workload.GetKind(),
+							Name:       workload.GetName(),
+						},
+						AuxiliaryWorkloads: []v1alpha2.AuxiliaryWorkloadStatus{
+							{
+								Name: "aux",
+								Reference: runtimev1alpha1.TypedReference{
+									APIVersion: auxiliary.GetAPIVersion(),
+									Kind:       auxiliary.GetKind(),
+									Name:       auxiliary.GetName(),
+								},
+							},
+						},
+					},
+				},
+				w: []Workload{{Workload: workload, AuxiliaryWorkloads: []AuxiliaryWorkload{{Name: "aux", Object: *auxiliary}}}},
+			},
+			want: []unstructured.Unstructured{},
+		},
 		"NeitherApplied": {
 			reason: "A referenced workload and its trait is eligible for garbage collection if they were not applied",
 			args: args{
@@ -833,6 +894,34 @@ func TestEligible(t *testing.T) {
 			},
 			want: []unstructured.Unstructured{},
 		},
+		"RevisionTraitNotApplied": {
+			reason: "A referenced trait of an old revision of its component is not eligible for garbage collection",
+			args: args{
+				namespace: namespace,
+				ws: []v1alpha2.WorkloadStatus{
+					{
+						ComponentName:         "web",
+						ComponentRevisionName: "web-v1",
+						Reference: runtimev1alpha1.TypedReference{
+							APIVersion: workload.GetAPIVersion(),
+							Kind:       workload.GetKind(),
+							Name:       "web-v1",
+						},
+						Traits: []v1alpha2.WorkloadTrait{
+							{
+								Reference: runtimev1alpha1.TypedReference{
+									APIVersion: trait.GetAPIVersion(),
+									Kind:       trait.GetKind(),
+									Name:       trait.GetName() + "-v1",
+								},
+							},
+						},
+					},
+				},
+				w: []Workload{{ComponentName: "web", ComponentRevisionName: "web-v2", Workload: workload}},
+			},
+			want: []unstructured.Unstructured{},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -850,561 +939,46 @@ func TestIsRevisionWorkload(t *testing.T) {
 	}
 }
 
-func TestDependency(t *testing.T) {
-	unreadyWorkload := &unstructured.Unstructured{}
-	unreadyWorkload.SetAPIVersion("v1")
-	unreadyWorkload.SetKind("Workload")
-	unreadyWorkload.SetNamespace("test-ns")
-	unreadyWorkload.SetName("unready-workload")
-
-	readyWorkload := unreadyWorkload.DeepCopy()
-	readyWorkload.SetName("ready-workload")
-	err := unstructured.SetNestedField(readyWorkload.Object, "test", "status", "key")
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	readyWorkloadArrayField := unreadyWorkload.DeepCopy()
-	err = unstructured.SetNestedStringSlice(readyWorkloadArrayField.Object, []string{"a"}, "spec", "key")
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = unstructured.SetNestedStringSlice(readyWorkloadArrayField.Object, []string{"b"}, "status", "key")
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	unreadyTrait := &unstructured.Unstructured{}
-	unreadyTrait.SetAPIVersion("v1")
-	unreadyTrait.SetKind("Trait")
-	unreadyTrait.SetNamespace("test-ns")
-	unreadyTrait.SetName("unready-trait")
-
-	readyTrait := unreadyTrait.DeepCopy()
-	readyTrait.SetName("ready-trait")
-	err = unstructured.SetNestedField(readyTrait.Object, "test", "status", "key")
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	mapper := mock.NewMockDiscoveryMapper()
-
-	type args struct {
-		components []v1alpha2.ApplicationConfigurationComponent
-		wl         *unstructured.Unstructured
-		trait      *unstructured.Unstructured
-	}
-	type want struct {
-		err             error
-		verifyWorkloads func([]Workload)
-		depStatus       *v1alpha2.DependencyStatus
-	}
+func TestIsRevisionTrait(t *testing.T) {
 	cases := map[string]struct {
-		args args
-		want want
+		reason   string
+		status   v1alpha2.WorkloadStatus
+		trait    v1alpha2.WorkloadTrait
+		revision string
+		want     bool
 	}{
-		"Workload depends on another Workload that's unready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					DataInputs: []v1alpha2.DataInput{{
-						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-						ToFieldPaths: []string{"spec.key"},
-					}},
-				}, {
-					ComponentName: "test-component-source",
-					DataOutputs: []v1alpha2.DataOutput{{
-						Name:      "test-output",
-						FieldPath: "status.key",
-					}},
-				}},
-				wl:    unreadyWorkload.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if !ws[0].HasDep {
-						t.Error("Workload should be unready to apply")
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{
-					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
-						Reason: "status.key not found in object",
-						From: v1alpha2.DependencyFromObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyWorkload.GetAPIVersion(),
-								Kind:       unreadyWorkload.GetKind(),
-								Name:       unreadyWorkload.GetName(),
-							},
-							FieldPath: "status.key",
-						},
-						To: v1alpha2.DependencyToObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyWorkload.GetAPIVersion(),
-								Kind:       unreadyWorkload.GetKind(),
-								Name:       unreadyWorkload.GetName(),
-							},
-							FieldPaths: []string{"spec.key"},
-						},
-					}},
-				},
-			},
-		},
-		"Workload depends on another Workload that's ready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					DataInputs: []v1alpha2.DataInput{{
-						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-						ToFieldPaths: []string{"spec.key"},
-					}},
-				}, {
-					ComponentName: "test-component-source",
-					DataOutputs: []v1alpha2.DataOutput{{
-						Name:      "test-output",
-						FieldPath: "status.key",
-					}},
-				}},
-				wl:    readyWorkload.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if ws[0].HasDep {
-						t.Error("Workload should be ready to apply")
-					}
-
-					s, _, err := unstructured.NestedString(ws[0].Workload.UnstructuredContent(), "spec", "key")
-					if err != nil {
-						t.Fatal(err)
-					}
-					if diff := cmp.Diff(s, "test"); diff != "" {
-						t.Fatal(diff)
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{},
-			},
-		},
-		"Workload depends on a Trait that's unready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					DataInputs: []v1alpha2.DataInput{{
-						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-						ToFieldPaths: []string{"spec.key"},
-					}},
-					Traits: []v1alpha2.ComponentTrait{{
-						Trait: runtime.RawExtension{},
-						DataOutputs: []v1alpha2.DataOutput{{
-							Name:      "test-output",
-							FieldPath: "status.key",
-						}},
-					}},
-				}},
-				wl:    unreadyWorkload.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if !ws[0].HasDep {
-						t.Error("Workload should be unready to apply")
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{
-					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
-						Reason: "status.key not found in object",
-						From: v1alpha2.DependencyFromObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyTrait.GetAPIVersion(),
-								Kind:       unreadyTrait.GetKind(),
-								Name:       unreadyTrait.GetName(),
-							},
-							FieldPath: "status.key",
-						},
-						To: v1alpha2.DependencyToObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyWorkload.GetAPIVersion(),
-								Kind:       unreadyWorkload.GetKind(),
-								Name:       unreadyWorkload.GetName(),
-							},
-							FieldPaths: []string{"spec.key"},
-						},
-					}},
-				},
-			},
-		},
-		"Workload depends on a Trait that's ready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					DataInputs: []v1alpha2.DataInput{{
-						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-						ToFieldPaths: []string{"spec.key"},
-					}},
-					Traits: []v1alpha2.ComponentTrait{{
-						Trait: runtime.RawExtension{},
-						DataOutputs: []v1alpha2.DataOutput{{
-							Name:      "test-output",
-							FieldPath: "status.key",
-						}},
-					}},
-				}},
-				wl:    unreadyWorkload.DeepCopy(),
-				trait: readyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if ws[0].HasDep {
-						t.Error("Workload should be ready to apply")
-					}
-
-					s, _, err := unstructured.NestedString(ws[0].Workload.UnstructuredContent(), "spec", "key")
-					if err != nil {
-						t.Fatal(err)
-					}
-					if diff := cmp.Diff(s, "test"); diff != "" {
-						t.Fatal(diff)
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{},
-			},
-		},
-		"Trait depends on a Workload that's unready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					Traits: []v1alpha2.ComponentTrait{{
-						Trait: runtime.RawExtension{},
-						DataInputs: []v1alpha2.DataInput{{
-							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-							ToFieldPaths: []string{"spec.key"},
-						}},
-					}},
-				}, {
-					ComponentName: "test-component-source",
-					DataOutputs: []v1alpha2.DataOutput{{
-						Name:      "test-output",
-						FieldPath: "status.key",
-					}},
-				}},
-				wl:    unreadyWorkload.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if !ws[0].Traits[0].HasDep {
-						t.Error("Trait should be unready to apply")
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{
-					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
-						Reason: "status.key not found in object",
-						From: v1alpha2.DependencyFromObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyWorkload.GetAPIVersion(),
-								Kind:       unreadyWorkload.GetKind(),
-								Name:       unreadyWorkload.GetName(),
-							},
-							FieldPath: "status.key",
-						},
-						To: v1alpha2.DependencyToObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyTrait.GetAPIVersion(),
-								Kind:       unreadyTrait.GetKind(),
-								Name:       unreadyTrait.GetName(),
-							},
-							FieldPaths: []string{"spec.key"},
-						},
-					}},
-				},
-			},
-		},
-		"Trait depends on a Workload that's ready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					Traits: []v1alpha2.ComponentTrait{{
-						Trait: runtime.RawExtension{},
-						DataInputs: []v1alpha2.DataInput{{
-							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-							ToFieldPaths: []string{"spec.key"},
-						}},
-					}},
-				}, {
-					ComponentName: "test-component-source",
-					DataOutputs: []v1alpha2.DataOutput{{
-						Name:      "test-output",
-						FieldPath: "status.key",
-					}},
-				}},
-				wl:    readyWorkload.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if ws[0].Traits[0].HasDep {
-						t.Error("Trait should be ready to apply")
-					}
-
-					s, _, err := unstructured.NestedString(ws[0].Traits[0].Object.UnstructuredContent(), "spec", "key")
-					if err != nil {
-						t.Fatal(err)
-					}
-					if diff := cmp.Diff(s, "test"); diff != "" {
-						t.Fatal(diff)
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{},
-			},
-		},
-		"Trait depends on another Trait that's unready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					Traits: []v1alpha2.ComponentTrait{{
-						Trait: runtime.RawExtension{},
-						DataInputs: []v1alpha2.DataInput{{
-							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-							ToFieldPaths: []string{"spec.key"},
-						}},
-					}, {
-						Trait: runtime.RawExtension{},
-						DataOutputs: []v1alpha2.DataOutput{{
-							Name:      "test-output",
-							FieldPath: "status.key",
-						}},
-					}},
-				}},
-				wl:    unreadyWorkload.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if !ws[0].Traits[0].HasDep {
-						t.Error("Trait should be unready to apply")
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{
-					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
-						Reason: "status.key not found in object",
-						From: v1alpha2.DependencyFromObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyTrait.GetAPIVersion(),
-								Kind:       unreadyTrait.GetKind(),
-								Name:       unreadyTrait.GetName(),
-							},
-							FieldPath: "status.key",
-						},
-						To: v1alpha2.DependencyToObject{
-							TypedReference: runtimev1alpha1.TypedReference{
-								APIVersion: unreadyTrait.GetAPIVersion(),
-								Kind:       unreadyTrait.GetKind(),
-								Name:       unreadyTrait.GetName(),
-							},
-							FieldPaths: []string{"spec.key"},
-						},
-					}},
-				},
-			},
+		"OldRevision": {
+			reason:   "A trait named with the suffix of an old revision is a revision trait",
+			status:   v1alpha2.WorkloadStatus{ComponentName: "web", ComponentRevisionName: "web-v1"},
+			trait:    v1alpha2.WorkloadTrait{Reference: runtimev1alpha1.TypedReference{Name: "route-v1"}},
+			revision: "web-v2",
+			want:     true,
 		},
-		"Trait depends on another Trait that's ready": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					Traits: []v1alpha2.ComponentTrait{{
-						Trait: runtime.RawExtension{},
-						DataInputs: []v1alpha2.DataInput{{
-							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-							ToFieldPaths: []string{"spec.key"},
-						}},
-					}, {
-						Trait: runtime.RawExtension{},
-						DataOutputs: []v1alpha2.DataOutput{{
-							Name:      "test-output",
-							FieldPath: "status.key",
-						}},
-					}},
-				}},
-				wl:    unreadyWorkload.DeepCopy(),
-				trait: readyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if ws[0].Traits[0].HasDep {
-						t.Error("Trait should be ready to apply")
-					}
-
-					s, _, err := unstructured.NestedString(ws[0].Traits[0].Object.UnstructuredContent(), "spec", "key")
-					if err != nil {
-						t.Fatal(err)
-					}
-					if diff := cmp.Diff(s, "test"); diff != "" {
-						t.Fatal(diff)
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{},
-			}},
-		"DataOutputName doesn't exist": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					DataInputs: []v1alpha2.DataInput{{
-						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "wrong-output"},
-						ToFieldPaths: []string{"spec.key"},
-					}},
-				}},
-				wl:    unreadyWorkload.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				err: ErrDataOutputNotExist,
-			},
+		"CurrentRevision": {
+			reason:   "A trait of the current revision is not a revision trait",
+			status:   v1alpha2.WorkloadStatus{ComponentName: "web", ComponentRevisionName: "web-v2"},
+			trait:    v1alpha2.WorkloadTrait{Reference: runtimev1alpha1.TypedReference{Name: "route-v2"}},
+			revision: "web-v2",
+			want:     false,
 		},
-		"DataInput of array type should append": {
-			args: args{
-				components: []v1alpha2.ApplicationConfigurationComponent{{
-					ComponentName: "test-component-sink",
-					DataInputs: []v1alpha2.DataInput{{
-						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
-						ToFieldPaths: []string{"spec.key"},
-					}},
-				}, {
-					ComponentName: "test-component-source",
-					DataOutputs: []v1alpha2.DataOutput{{
-						Name:      "test-output",
-						FieldPath: "status.key",
-					}},
-				}},
-				wl:    readyWorkloadArrayField.DeepCopy(),
-				trait: unreadyTrait.DeepCopy(),
-			},
-			want: want{
-				verifyWorkloads: func(ws []Workload) {
-					if ws[0].HasDep {
-						t.Error("Workload should be ready to apply")
-					}
-
-					l, _, err := unstructured.NestedStringSlice(ws[0].Workload.UnstructuredContent(), "spec", "key")
-					if err != nil {
-						t.Fatal(err)
-					}
-					if diff := cmp.Diff(l, []string{"a", "b"}); diff != "" {
-						t.Fatal(diff)
-					}
-				},
-				depStatus: &v1alpha2.DependencyStatus{}},
+		"NotNamedAfterRevision": {
+			reason:   "A trait that is not named with the suffix of its revision is not a revision trait",
+			status:   v1alpha2.WorkloadStatus{ComponentName: "web", ComponentRevisionName: "web-v1"},
+			trait:    v1alpha2.WorkloadTrait{Reference: runtimev1alpha1.TypedReference{Name: "route"}},
+			revision: "web-v2",
+			want:     false,
 		},
 	}
-
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			c := components{
-				dm: mapper,
-				client: &test.MockClient{
-					MockGet: test.MockGetFn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
-						if obj.GetObjectKind().GroupVersionKind().Kind == "Workload" {
-							b, err := json.Marshal(tc.args.wl)
-							if err != nil {
-								t.Fatal(err)
-							}
-							err = json.Unmarshal(b, obj)
-							if err != nil {
-								t.Fatal(err)
-							}
-						}
-						if obj.GetObjectKind().GroupVersionKind().Kind == "Trait" {
-							b, err := json.Marshal(tc.args.trait)
-							if err != nil {
-								t.Fatal(err)
-							}
-							err = json.Unmarshal(b, obj)
-							if err != nil {
-								t.Fatal(err)
-							}
-						}
-						return nil
-					}),
-				},
-				params: ParameterResolveFn(resolve),
-				workload: ResourceRenderFn(func(data []byte, p ...Parameter) (*unstructured.Unstructured, error) {
-					return tc.args.wl, nil
-				}),
-				trait: ResourceRenderFn(func(data []byte, p ...Parameter) (*unstructured.Unstructured, error) {
-					return tc.args.trait, nil
-				}),
-			}
-
-			ac := &v1alpha2.ApplicationConfiguration{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test-app",
-					Namespace: "test-ns",
-				},
-				Spec: v1alpha2.ApplicationConfigurationSpec{
-					Components: tc.args.components,
-				},
-			}
-
-			ws, ds, err := c.Render(context.Background(), ac)
-			if err != nil {
-				if errors.Is(err, tc.want.err) {
-					return
-				}
-				t.Error(err)
-				return
-			}
-			if diff := cmp.Diff(tc.want.err, err); diff != "" {
-				t.Error(diff)
-				return
-			}
-			tc.want.verifyWorkloads(ws)
-			if diff := cmp.Diff(tc.want.depStatus, ds); diff != "" {
-				t.Error(diff)
+			got := IsRevisionTrait(tc.status, tc.trait, tc.revision)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsRevisionTrait(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
 		})
 	}
 }
 
-func TestAddDataOutputsToDAG(t *testing.T) {
-	obj := &unstructured.Unstructured{}
-	obj.SetAPIVersion("v1")
-	obj.SetKind("TestKind")
-	obj.SetNamespace("test-ns")
-	obj.SetName("test-name")
-
-	dag := newDAG()
-	outs := []v1alpha2.DataOutput{{
-		Name:      "test-output",
-		FieldPath: "spec.replica",
-		Conditions: []v1alpha2.ConditionRequirement{{
-			Operator:  v1alpha2.ConditionEqual,
-			Value:     "abc",
-			FieldPath: "status.state",
-		}},
-	}}
-	addDataOutputsToDAG(dag, outs, obj)
-
-	s, ok := dag.Sources["test-output"]
-	if !ok {
-		t.Fatal("didn't add source correctly")
-	}
-
-	r := &corev1.ObjectReference{
-		APIVersion: obj.GetAPIVersion(),
-		Kind:       obj.GetKind(),
-		Name:       obj.GetName(),
-		Namespace:  obj.GetNamespace(),
-		FieldPath:  outs[0].FieldPath,
-	}
-
-	if diff := cmp.Diff(s.ObjectRef, r); diff != "" {
-		t.Errorf("didn't add objectRef to source correctly: %s", diff)
-	}
-
-	if diff := cmp.Diff(s.Conditions, outs[0].Conditions); diff != "" {
-		t.Errorf("didn't add conditions to source correctly: %s", diff)
-	}
-}
-
 func TestPatchExtraField(t *testing.T) {
 	tests := map[string]struct {
 		acStatus      *v1alpha2.ApplicationConfigurationStatus
@@ -1637,3 +1211,212 @@ func TestUpdateStatus(t *testing.T) {
 	assert.Equal(t, ac.Status.ObservedGeneration, int64(1))
 
 }
+
+func TestPruneHistoryWorkloads(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+	revision := func(name string, age time.Duration) unstructured.Unstructured {
+		w := unstructured.Unstructured{}
+		w.SetName(name)
+		w.SetCreationTimestamp(metav1.NewTime(now.Add(-age)))
+		return w
+	}
+	history := func() []unstructured.Unstructured {
+		return []unstructured.Unstructured{
+			revision("web-v1", 3*time.Hour),
+			revision("web-v3", 1*time.Hour),
+			revision("web-v2", 2*time.Hour),
+		}
+	}
+	limit := func(l int32) *int32 { return &l }
+
+	cases := map[string]struct {
+		reason  string
+		delete  test.MockDeleteFn
+		limit   *int32
+		want    []unstructured.Unstructured
+		deleted []string
+	}{
+		"NoLimit": {
+			reason: "All workloads of old revisions should be retained without limit",
+			want:   history(),
+		},
+		"WithinLimit": {
+			reason: "All workloads of old revisions should be retained within the limit",
+			limit:  limit(3),
+			want:   history(),
+		},
+		"BeyondLimit": {
+			reason:  "The oldest workloads beyond the limit should be deleted",
+			delete:  test.NewMockDeleteFn(nil),
+			limit:   limit(1),
+			want:    []unstructured.Unstructured{revision("web-v3", 1*time.Hour)},
+			deleted: []string{"web-v2", "web-v1"},
+		},
+		"DeleteError": {
+			reason: "Workloads that cannot be deleted should be retained",
+			delete: test.NewMockDeleteFn(errBoom),
+			limit:  limit(0),
+			want: []unstructured.Unstructured{
+				revision("web-v3", 1*time.Hour),
+				revision("web-v2", 2*time.Hour),
+				revision("web-v1", 3*time.Hour),
+			},
+			deleted: []string{"web-v3", "web-v2", "web-v1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			c := &test.MockClient{MockDelete: func(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+				deleted = append(deleted, obj.(*unstructured.Unstructured).GetName())
+				return tc.delete(ctx, obj, opts...)
+			}}
+			r := &OAMApplicationReconciler{client: c, log: logging.NewNopLogger()}
+			got := r.pruneHistoryWorkloads(context.Background(), tc.limit, history())
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.pruneHistoryWorkloads(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nr.pruneHistoryWorkloads(...): -want deleted, +got deleted:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPruneRevisionTraits(t *testing.T) {
+	errBoom := errors.New("boom")
+	trait := func(name, revision string) unstructured.Unstructured {
+		tr := unstructured.Unstructured{}
+		tr.SetAPIVersion("example.com/v1")
+		tr.SetKind("Route")
+		tr.SetName(name)
+		tr.SetLabels(map[string]string{oam.LabelAppComponentRevision: revision})
+		return tr
+	}
+	history := func(revision string) unstructured.Unstructured {
+		w := unstructured.Unstructured{}
+		w.SetName(revision)
+		w.SetLabels(map[string]string{oam.LabelAppComponentRevision: revision})
+		return w
+	}
+	workload := func(revisionEnabled bool) Workload {
+		w := &unstructured.Unstructured{}
+		w.SetNamespace("ns")
+		return Workload{ComponentName: "web", ComponentRevisionName: "web-v3", Workload: w, Traits: []*Trait{{
+			Object:     trait("route-v3", "web-v3"),
+			Definition: v1alpha2.TraitDefinition{Spec: v1alpha2.TraitDefinitionSpec{RevisionEnabled: revisionEnabled}},
+		}}}
+	}
+	list := func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+		l := obj.(*unstructured.UnstructuredList)
+		if l.GetKind() != "RouteList" {
+			return errors.Errorf("unexpected kind %q", l.GetKind())
+		}
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		if lo.Namespace != "ns" || !lo.LabelSelector.Matches(labels.Set{oam.LabelAppName: "app", oam.LabelAppComponent: "web",
+			oam.LabelOAMResourceType: oam.ResourceTypeTrait}) {
+			return errors.New("unexpected list options")
+		}
+		l.Items = []unstructured.Unstructured{trait("route-v1", "web-v1"), trait("route-v2", "web-v2"), trait("route-v3", "web-v3")}
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason  string
+		list    test.MockListFn
+		delete  test.MockDeleteFn
+		w       Workload
+		history []unstructured.Unstructured
+		deleted []string
+	}{
+		"NotRevisionEnabled": {
+			reason:  "Traits of definitions that are not revisionEnabled should not be pruned",
+			w:       workload(false),
+			history: []unstructured.Unstructured{},
+		},
+		"PruneOldRevisions": {
+			reason:  "Traits of revisions whose workloads no longer exist should be deleted",
+			list:    list,
+			delete:  test.NewMockDeleteFn(nil),
+			w:       workload(true),
+			history: []unstructured.Unstructured{history("web-v2")},
+			deleted: []string{"route-v1"},
+		},
+		"ListError": {
+			reason:  "Traits should not be pruned if they cannot be listed",
+			list:    test.NewMockListFn(errBoom),
+			w:       workload(true),
+			history: []unstructured.Unstructured{},
+		},
+		"DeleteError": {
+			reason:  "Traits that cannot be deleted should be skipped",
+			list:    list,
+			delete:  test.NewMockDeleteFn(errBoom),
+			w:       workload(true),
+			history: []unstructured.Unstructured{},
+			deleted: []string{"route-v1", "route-v2"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			c := &test.MockClient{
+				MockList: tc.list,
+				MockDelete: func(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+					deleted = append(deleted, obj.(*unstructured.Unstructured).GetName())
+					return tc.delete(ctx, obj, opts...)
+				},
+			}
+			r := &OAMApplicationReconciler{client: c, log: logging.NewNopLogger()}
+			r.pruneRevisionTraits(context.Background(), "app", tc.w, tc.history)
+			if diff := cmp.Diff(tc.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nr.pruneRevisionTraits(...): -want deleted, +got deleted:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRollbacks(t *testing.T) {
+	status := []v1alpha2.WorkloadStatus{
+		{ComponentName: "web", ComponentRevisionName: "web-v3"},
+		{ComponentName: "db", ComponentRevisionName: "db-v2"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      []Workload
+		want   []revisionChange
+	}{
+		"Rollback": {
+			reason: "A component rendered from an older revision should be rolled back",
+			w: []Workload{
+				{ComponentName: "web", ComponentRevisionName: "web-v1"},
+				{ComponentName: "db", ComponentRevisionName: "db-v2"},
+			},
+			want: []revisionChange{{ComponentName: "web", From: "web-v3", To: "web-v1"}},
+		},
+		"Upgrade": {
+			reason: "A component rendered from a newer revision should not be rolled back",
+			w:      []Workload{{ComponentName: "web", ComponentRevisionName: "web-v4"}},
+			want:   []revisionChange{},
+		},
+		"NewComponent": {
+			reason: "A component without active revision should not be rolled back",
+			w:      []Workload{{ComponentName: "cache", ComponentRevisionName: "cache-v1"}},
+			want:   []revisionChange{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := rollbacks(status, tc.w)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrollbacks(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package applicationconfiguration
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -30,29 +32,34 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/render"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
 // Reconcile error strings.
 const (
 	errFmtApplyWorkload            = "cannot apply workload %q"
-	errFmtSetWorkloadRef           = "cannot set trait %q reference to %q"
 	errFmtSetScopeWorkloadRef      = "cannot set scope %q reference to %q"
-	errFmtGetTraitDefinition       = "cannot find trait definition %q %q %q"
 	errFmtGetScopeDefinition       = "cannot find scope definition %q %q %q"
 	errFmtGetScopeWorkloadRef      = "cannot find scope workloadRef %q %q %q with workloadRefsPath %q"
 	errFmtGetScopeWorkloadRefsPath = "cannot get workloadRefsPath for scope to be dereferenced %q %q %q"
 	errFmtApplyTrait               = "cannot apply trait %q %q %q"
 	errFmtApplyScope               = "cannot apply scope %q %q %q"
+	errFmtScopeNotApplies          = "workload %q %q %q cannot join scope %q %q %q, scope definition %q only applies to %v"
+	errFmtScopeOverlap             = "workload %q %q %q cannot join scope %q %q %q, scope definition %q does not allow component overlap and it is already in scope %q"
+	errFmtListScopes               = "cannot list scopes %q %q"
+	errFmtDecodeScopeContributors  = "cannot decode the contributors of scope %q %q %q"
+	errFmtEncodeScopeContributors  = "cannot encode the contributors of scope %q %q %q"
 
 	workloadScopeFinalizer = "scope.finalizer.core.oam.dev"
 )
 
 // A WorkloadApplicator creates or updates or finalizes workloads and their traits.
 type WorkloadApplicator interface {
-	// Apply a workload and its traits.
-	Apply(ctx context.Context, status []v1alpha2.WorkloadStatus, w []Workload, ao ...resource.ApplyOption) error
+	// Apply the workloads of an AppConfig and their traits.
+	Apply(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, w []Workload, ao ...resource.ApplyOption) error
 
 	// Finalize implements pre-delete hooks on workloads
 	Finalize(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error
@@ -60,13 +67,13 @@ type WorkloadApplicator interface {
 
 // A WorkloadApplyFns creates or updates or finalizes workloads and their traits.
 type WorkloadApplyFns struct {
-	ApplyFn    func(ctx context.Context, status []v1alpha2.WorkloadStatus, w []Workload, ao ...resource.ApplyOption) error
+	ApplyFn    func(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, w []Workload, ao ...resource.ApplyOption) error
 	FinalizeFn func(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error
 }
 
-// Apply a workload and its traits.
-func (fn WorkloadApplyFns) Apply(ctx context.Context, status []v1alpha2.WorkloadStatus, w []Workload, ao ...resource.ApplyOption) error {
-	return fn.ApplyFn(ctx, status, w, ao...)
+// Apply the workloads of an AppConfig and their traits.
+func (fn WorkloadApplyFns) Apply(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, w []Workload, ao ...resource.ApplyOption) error {
+	return fn.ApplyFn(ctx, ac, w, ao...)
 }
 
 // Finalize workloads and its traits/scopes.
@@ -78,20 +85,63 @@ type workloads struct {
 	client    resource.Applicator
 	rawClient client.Client
 	dm        discoverymapper.DiscoveryMapper
+
+	// cluster is the name of the cluster the workloads are applied to
+	cluster string
 }
 
-func (a *workloads) Apply(ctx context.Context, status []v1alpha2.WorkloadStatus, w []Workload, ao ...resource.ApplyOption) error {
-	// they are all in the same namespace
-	var namespace = w[0].Workload.GetNamespace()
+func (a *workloads) Apply(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, w []Workload, ao ...resource.ApplyOption) error {
+	// scopes record the AppConfig as the contributor of its workloads by the
+	// name Finalize removes it by
+	status := ac.Status.Workloads
+	recorded := make(map[string][]v1alpha2.WorkloadScope, len(status))
+	for _, st := range status {
+		recorded[workloadKey(st.Reference)] = st.Scopes
+	}
 	for _, wl := range w {
-		if !wl.HasDep {
-			err := a.client.Apply(ctx, wl.Workload, ao...)
-			if err != nil {
-				return errors.Wrapf(err, errFmtApplyWorkload, wl.Workload.GetName())
+		placement, err := placementOf(wl.Scopes)
+		if err != nil {
+			return err
+		}
+		// workloads placed in other clusters join their scopes, but they and
+		// their traits are not applied to this one
+		placed := placement == nil || placedIn(placement.Spec, a.cluster)
+		if placed && placement != nil {
+			if err := place(wl.Workload, wl.PodSpecPath, placement.Spec); err != nil {
+				return errors.Wrapf(err, errFmtPlaceWorkload, wl.Workload.GetName(), placement.GetName())
+			}
+		}
+		if placed {
+			// sidecars are injected first, so that claims can be mounted into them
+			if err := injectSidecars(wl); err != nil {
+				return err
+			}
+			if err := mountVolumeClaims(wl); err != nil {
+				return err
+			}
+			if err := annotateScrapedPods(wl); err != nil {
+				return err
+			}
+		}
+		if !wl.HasDep && placed {
+			// workloads managed by a trait are applied by the trait
+			if !wl.SkipApply {
+				if err := a.client.Apply(ctx, wl.Workload, ao...); err != nil {
+					return errors.Wrapf(err, errFmtApplyWorkload, wl.Workload.GetName())
+				}
+			}
+			for i := range wl.AuxiliaryWorkloads {
+				if wl.AuxiliaryWorkloads[i].SkipApply {
+					continue
+				}
+				aw := &wl.AuxiliaryWorkloads[i].Object
+				if err := a.client.Apply(ctx, aw, ao...); err != nil {
+					return errors.Wrapf(err, errFmtApplyWorkload, aw.GetName())
+				}
 			}
 		}
 		for _, trait := range wl.Traits {
-			if trait.HasDep {
+			if trait.HasDep || !placed {
 				continue
 			}
 			t := trait.Object
@@ -104,21 +154,25 @@ func (a *workloads) Apply(ctx context.Context, status []v1alpha2.WorkloadStatus,
 			Kind:       wl.Workload.GetKind(),
 			Name:       wl.Workload.GetName(),
 		}
+		var leaving []v1alpha2.WorkloadScope
+		if scopes, ok := recorded[workloadKey(workloadRef)]; ok {
+			leaving = findDereferencedScopes(scopes, wl.Scopes)
+		}
 		for _, s := range wl.Scopes {
-			if err := a.applyScope(ctx, wl, s, workloadRef); err != nil {
+			if err := a.applyScope(ctx, ac.GetName(), wl, s, workloadRef, leaving); err != nil {
 				return err
 			}
 		}
 	}
 
-	return a.dereferenceScope(ctx, namespace, status, w)
+	return a.dereferenceScope(ctx, ac.GetNamespace(), ac.GetName(), status, w)
 }
 
 func (a *workloads) Finalize(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) error {
 	var namespace = ac.GetNamespace()
 
 	if meta.FinalizerExists(&ac.ObjectMeta, workloadScopeFinalizer) {
-		if err := a.dereferenceAllScopes(ctx, namespace, ac.Status.Workloads); err != nil {
+		if err := a.dereferenceAllScopes(ctx, namespace, ac.GetName(), ac.Status.Workloads); err != nil {
 			return err
 		}
 		meta.RemoveFinalizer(&ac.ObjectMeta, workloadScopeFinalizer)
@@ -128,19 +182,25 @@ func (a *workloads) Finalize(ctx context.Context, ac *v1alpha2.ApplicationConfig
 	return nil
 }
 
-func (a *workloads) dereferenceScope(ctx context.Context, namespace string, status []v1alpha2.WorkloadStatus, w []Workload) error {
+// dereferenceScope removes the contributions of the supplied AppConfig of the
+// recorded workloads from the scopes they are no longer rendered with.
+func (a *workloads) dereferenceScope(ctx context.Context, namespace, acName string, status []v1alpha2.WorkloadStatus, w []Workload) error {
+	rendered := make(map[string][]unstructured.Unstructured, len(w))
+	for _, wl := range w {
+		rendered[workloadKey(runtimev1alpha1.TypedReference{
+			APIVersion: wl.Workload.GetAPIVersion(),
+			Kind:       wl.Workload.GetKind(),
+			Name:       wl.Workload.GetName(),
+		})] = wl.Scopes
+	}
 	for _, st := range status {
 		toBeDeferenced := st.Scopes
-		for _, wl := range w {
-			if (st.Reference.APIVersion == wl.Workload.GetAPIVersion()) &&
-				(st.Reference.Kind == wl.Workload.GetKind()) &&
-				(st.Reference.Name == wl.Workload.GetName()) {
-				toBeDeferenced = findDereferencedScopes(st.Scopes, wl.Scopes)
-			}
+		if scopes, ok := rendered[workloadKey(st.Reference)]; ok {
+			toBeDeferenced = findDereferencedScopes(st.Scopes, scopes)
 		}
 
 		for _, s := range toBeDeferenced {
-			if err := a.applyScopeRemoval(ctx, namespace, st.Reference, s); err != nil {
+			if err := a.applyScopeRemoval(ctx, namespace, acName, st.Reference, s); err != nil {
 				return err
 			}
 		}
@@ -150,10 +210,10 @@ func (a *workloads) dereferenceScope(ctx context.Context, namespace string, stat
 }
 
 // dereferenceAllScope dereferences workloads owned by the appConfig being deleted from the scopes they belong to.
-func (a *workloads) dereferenceAllScopes(ctx context.Context, namespace string, status []v1alpha2.WorkloadStatus) error {
+func (a *workloads) dereferenceAllScopes(ctx context.Context, namespace, acName string, status []v1alpha2.WorkloadStatus) error {
 	for _, st := range status {
 		for _, sc := range st.Scopes {
-			if err := a.applyScopeRemoval(ctx, namespace, st.Reference, sc); err != nil {
+			if err := a.applyScopeRemoval(ctx, namespace, acName, st.Reference, sc); err != nil {
 				return err
 			}
 		}
@@ -162,6 +222,13 @@ func (a *workloads) dereferenceAllScopes(ctx context.Context, namespace string,
 	return nil
 }
 
+// workloadKey identifies the referenced workload by its apiVersion, kind and
+// name, so that workloads rendered and recorded in the status can be matched
+// without scanning either.
+func workloadKey(ref runtimev1alpha1.TypedReference) string {
+	return ref.APIVersion + "/" + ref.Kind + "/" + ref.Name
+}
+
 func findDereferencedScopes(statusScopes []v1alpha2.WorkloadScope, scopes []unstructured.Unstructured) []v1alpha2.WorkloadScope {
 	toBeDeferenced := []v1alpha2.WorkloadScope{}
 	for _, ss := range statusScopes {
@@ -183,14 +250,33 @@ func findDereferencedScopes(statusScopes []v1alpha2.WorkloadScope, scopes []unst
 	return toBeDeferenced
 }
 
-func (a *workloads) applyScope(ctx context.Context, wl Workload, s unstructured.Unstructured, workloadRef runtimev1alpha1.TypedReference) error {
+func (a *workloads) applyScope(ctx context.Context, acName string, wl Workload, s unstructured.Unstructured, workloadRef runtimev1alpha1.TypedReference,
+	leaving []v1alpha2.WorkloadScope) error {
 	// get ScopeDefinition
 	scopeDefinition, err := util.FetchScopeDefinition(ctx, a.rawClient, a.dm, &s)
 	if err != nil {
 		return errors.Wrapf(err, errFmtGetScopeDefinition, s.GetAPIVersion(), s.GetKind(), s.GetName())
 	}
+	applies, err := util.ScopeAppliesToWorkload(a.dm, scopeDefinition, wl.Workload)
+	if err != nil {
+		return errors.Wrapf(err, errFmtApplyScope, s.GetAPIVersion(), s.GetKind(), s.GetName())
+	}
+	if !applies {
+		return errors.Errorf(errFmtScopeNotApplies, wl.Workload.GetAPIVersion(), wl.Workload.GetKind(), wl.Workload.GetName(),
+			s.GetAPIVersion(), s.GetKind(), s.GetName(), scopeDefinition.GetName(), scopeDefinition.Spec.AppliesToWorkloads)
+	}
 	// checkout whether scope asks for workloadRef
 	workloadRefsPath := scopeDefinition.Spec.WorkloadRefsPath
+	if !scopeDefinition.Spec.AllowComponentOverlap {
+		other, err := a.overlappingScope(ctx, wl, s, workloadRefsPath, workloadRef, leaving)
+		if err != nil {
+			return err
+		}
+		if other != "" {
+			return errors.Errorf(errFmtScopeOverlap, wl.Workload.GetAPIVersion(), wl.Workload.GetKind(), wl.Workload.GetName(),
+				s.GetAPIVersion(), s.GetKind(), s.GetName(), scopeDefinition.GetName(), other)
+		}
+	}
 	if len(workloadRefsPath) == 0 {
 		// this scope does not ask for workloadRefs
 		return nil
@@ -199,23 +285,25 @@ func (a *workloads) applyScope(ctx context.Context, wl Workload, s unstructured.
 	var refs []interface{}
 	if value, err := fieldpath.Pave(s.UnstructuredContent()).GetValue(workloadRefsPath); err == nil {
 		refs = value.([]interface{})
-
-		for _, item := range refs {
-			ref := item.(map[string]interface{})
-			if (workloadRef.APIVersion == ref["apiVersion"]) &&
-				(workloadRef.Kind == ref["kind"]) &&
-				(workloadRef.Name == ref["name"]) {
-				// workloadRef is already present, so no need to add it.
-				return nil
-			}
-		}
 	} else {
 		return errors.Wrapf(err, errFmtGetScopeWorkloadRef, s.GetAPIVersion(), s.GetKind(), s.GetName(), workloadRefsPath)
 	}
 
-	refs = append(refs, workloadRef)
-	if err := fieldpath.Pave(s.UnstructuredContent()).SetValue(workloadRefsPath, refs); err != nil {
-		return errors.Wrapf(err, errFmtSetScopeWorkloadRef, s.GetName(), wl.Workload.GetName())
+	added := false
+	if !render.ContainsWorkloadRef(refs, workloadRef) {
+		refs = append(refs, workloadRef)
+		if err := fieldpath.Pave(s.UnstructuredContent()).SetValue(workloadRefsPath, refs); err != nil {
+			return errors.Wrapf(err, errFmtSetScopeWorkloadRef, s.GetName(), wl.Workload.GetName())
+		}
+		added = true
+	}
+	contributed, err := addScopeContributor(&s, acName, workloadRef)
+	if err != nil {
+		return err
+	}
+	if !added && !contributed {
+		// workloadRef is already present and contributed by this AppConfig
+		return nil
 	}
 
 	if err := a.rawClient.Update(ctx, &s); err != nil {
@@ -225,7 +313,60 @@ func (a *workloads) applyScope(ctx context.Context, wl Workload, s unstructured.
 	return nil
 }
 
-func (a *workloads) applyScopeRemoval(ctx context.Context, namespace string, wr runtimev1alpha1.TypedReference, s v1alpha2.WorkloadScope) error {
+// overlappingScope returns the name of another scope of the kind of the
+// supplied scope that the supplied workload is in, if any. The workload is in
+// the scopes it is rendered with, and in the scopes that refer to it at the
+// supplied workloadRefsPath, except those it is leaving.
+func (a *workloads) overlappingScope(ctx context.Context, wl Workload, s unstructured.Unstructured, workloadRefsPath string,
+	workloadRef runtimev1alpha1.TypedReference, leaving []v1alpha2.WorkloadScope) (string, error) {
+	gk := s.GroupVersionKind().GroupKind()
+	for _, o := range wl.Scopes {
+		if o.GroupVersionKind().GroupKind() == gk && o.GetName() != s.GetName() {
+			return o.GetName(), nil
+		}
+	}
+	if len(workloadRefsPath) == 0 {
+		return "", nil
+	}
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(s.GroupVersionKind().GroupVersion().WithKind(s.GetKind() + "List"))
+	if err := a.rawClient.List(ctx, l, client.InNamespace(s.GetNamespace())); err != nil {
+		return "", errors.Wrapf(err, errFmtListScopes, s.GetAPIVersion(), s.GetKind())
+	}
+	for _, o := range l.Items {
+		if o.GetName() == s.GetName() || isLeavingScope(leaving, o) {
+			continue
+		}
+		value, err := fieldpath.Pave(o.UnstructuredContent()).GetValue(workloadRefsPath)
+		if err != nil {
+			continue
+		}
+		if refs, ok := value.([]interface{}); ok && render.ContainsWorkloadRef(refs, workloadRef) {
+			return o.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+// isLeavingScope returns true if the supplied scope is one of the supplied
+// scopes a workload is leaving.
+func isLeavingScope(leaving []v1alpha2.WorkloadScope, s unstructured.Unstructured) bool {
+	for _, ls := range leaving {
+		if (s.GetAPIVersion() == ls.Reference.APIVersion) &&
+			(s.GetKind() == ls.Reference.Kind) &&
+			(s.GetName() == ls.Reference.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyScopeRemoval removes the contribution of the supplied AppConfig of the
+// referenced workload from the supplied scope. The workload is only removed
+// from the scope when no other AppConfig contributes it, or if its
+// contributors are not recorded, e.g. because it was added to the scope
+// before they were.
+func (a *workloads) applyScopeRemoval(ctx context.Context, namespace, acName string, wr runtimev1alpha1.TypedReference, s v1alpha2.WorkloadScope) error {
 	scopeObject := unstructured.Unstructured{}
 	scopeObject.SetAPIVersion(s.Reference.APIVersion)
 	scopeObject.SetKind(s.Reference.Kind)
@@ -250,9 +391,19 @@ func (a *workloads) applyScopeRemoval(ctx context.Context, namespace string, wr
 		return errors.Errorf(errFmtGetScopeWorkloadRefsPath, scopeObject.GetAPIVersion(), scopeObject.GetKind(), scopeObject.GetName())
 	}
 
-	if value, err := fieldpath.Pave(scopeObject.UnstructuredContent()).GetValue(workloadRefsPath); err == nil {
-		refs := value.([]interface{})
+	value, err := fieldpath.Pave(scopeObject.UnstructuredContent()).GetValue(workloadRefsPath)
+	if err != nil {
+		return errors.Wrapf(err, errFmtGetScopeWorkloadRef,
+			scopeObject.GetAPIVersion(), scopeObject.GetKind(), scopeObject.GetName(), workloadRefsPath)
+	}
+	refs := value.([]interface{})
 
+	remaining, removed, err := removeScopeContributor(&scopeObject, acName, wr)
+	if err != nil {
+		return err
+	}
+	changed := removed
+	if remaining == 0 {
 		workloadRefIndex := -1
 		for i, item := range refs {
 			ref := item.(map[string]interface{})
@@ -272,14 +423,96 @@ func (a *workloads) applyScopeRemoval(ctx context.Context, namespace string, wr
 			if err := fieldpath.Pave(scopeObject.UnstructuredContent()).SetValue(workloadRefsPath, refs); err != nil {
 				return errors.Wrapf(err, errFmtSetScopeWorkloadRef, s.Reference.Name, wr.Name)
 			}
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := a.rawClient.Update(ctx, &scopeObject); err != nil {
+		return errors.Wrapf(err, errFmtApplyScope, s.Reference.APIVersion, s.Reference.Kind, s.Reference.Name)
+	}
+	return nil
+}
 
-			if err := a.rawClient.Update(ctx, &scopeObject); err != nil {
-				return errors.Wrapf(err, errFmtApplyScope, s.Reference.APIVersion, s.Reference.Kind, s.Reference.Name)
-			}
+// scopeContributors returns the AppConfigs that contribute the workloads of
+// the supplied scope, keyed by the workloadKey of the workloads.
+func scopeContributors(s *unstructured.Unstructured) (map[string][]string, error) {
+	contributors := map[string][]string{}
+	raw, ok := s.GetAnnotations()[oam.AnnotationScopeContributors]
+	if !ok {
+		return contributors, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &contributors); err != nil {
+		return nil, errors.Wrapf(err, errFmtDecodeScopeContributors, s.GetAPIVersion(), s.GetKind(), s.GetName())
+	}
+	return contributors, nil
+}
+
+// setScopeContributors records the supplied contributors in the supplied
+// scope.
+func setScopeContributors(s *unstructured.Unstructured, contributors map[string][]string) error {
+	if len(contributors) == 0 {
+		meta.RemoveAnnotations(s, oam.AnnotationScopeContributors)
+		return nil
+	}
+	raw, err := json.Marshal(contributors)
+	if err != nil {
+		return errors.Wrapf(err, errFmtEncodeScopeContributors, s.GetAPIVersion(), s.GetKind(), s.GetName())
+	}
+	meta.AddAnnotations(s, map[string]string{oam.AnnotationScopeContributors: string(raw)})
+	return nil
+}
+
+// addScopeContributor records the supplied AppConfig as a contributor of the
+// referenced workload to the supplied scope. It returns true if it was not
+// recorded before.
+func addScopeContributor(s *unstructured.Unstructured, acName string, wr runtimev1alpha1.TypedReference) (bool, error) {
+	if acName == "" {
+		return false, nil
+	}
+	contributors, err := scopeContributors(s)
+	if err != nil {
+		return false, err
+	}
+	key := workloadKey(wr)
+	for _, name := range contributors[key] {
+		if name == acName {
+			return false, nil
 		}
+	}
+	contributors[key] = append(contributors[key], acName)
+	sort.Strings(contributors[key])
+	return true, setScopeContributors(s, contributors)
+}
+
+// removeScopeContributor removes the supplied AppConfig from the contributors
+// of the referenced workload to the supplied scope. It returns the number of
+// AppConfigs that still contribute the workload, and true if the AppConfig was
+// removed. A workload whose contributors are not recorded has none.
+func removeScopeContributor(s *unstructured.Unstructured, acName string, wr runtimev1alpha1.TypedReference) (int, bool, error) {
+	contributors, err := scopeContributors(s)
+	if err != nil {
+		return 0, false, err
+	}
+	key := workloadKey(wr)
+	names, ok := contributors[key]
+	if !ok {
+		return 0, false, nil
+	}
+	remaining := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != acName {
+			remaining = append(remaining, name)
+		}
+	}
+	if len(remaining) == len(names) {
+		return len(remaining), false, nil
+	}
+	if len(remaining) == 0 {
+		delete(contributors, key)
 	} else {
-		return errors.Wrapf(err, errFmtGetScopeWorkloadRef,
-			scopeObject.GetAPIVersion(), scopeObject.GetKind(), scopeObject.GetName(), workloadRefsPath)
+		contributors[key] = remaining
 	}
-	return nil
+	return len(remaining), true, setScopeContributors(s, contributors)
 }
@@ -34,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
@@ -99,6 +100,12 @@ func TestApplyWorkloads(t *testing.T) {
 		},
 	})
 
+	// another scope of the same kind
+	otherScope := scope.DeepCopy()
+	otherScope.SetName("other-scope-example")
+	otherScopeWithRef := scopeWithRef.DeepCopy()
+	otherScopeWithRef.SetName("other-scope-example")
+
 	scopeDefinition := v1alpha2.ScopeDefinition{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ScopeDefinition",
@@ -157,6 +164,19 @@ func TestApplyWorkloads(t *testing.T) {
 				ws: []v1alpha2.WorkloadStatus{}},
 			want: errors.Wrapf(errBoom, errFmtApplyTrait, trait.GetAPIVersion(), trait.GetKind(), trait.GetName()),
 		},
+		"SkipApplyManagedWorkload": {
+			reason: "Workloads managed by a trait should not be applied",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				if w, ok := o.(*unstructured.Unstructured); ok && w.GetUID() == workload.GetUID() {
+					return errBoom
+				}
+				return nil
+			}),
+			rawClient: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			args: args{
+				w:  []Workload{{Workload: workload, SkipApply: true, Traits: []*Trait{{Object: *trait}}}},
+				ws: []v1alpha2.WorkloadStatus{}},
+		},
 		"Success": {
 			reason: "Applied workloads and traits should be returned as a set of UIDs.",
 			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
@@ -189,6 +209,7 @@ func TestApplyWorkloads(t *testing.T) {
 				MockUpdate: func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
 					return nil
 				},
+				MockList: test.NewMockListFn(nil),
 			},
 			args: args{
 				w: []Workload{{
@@ -216,8 +237,62 @@ func TestApplyWorkloads(t *testing.T) {
 				},
 			},
 		},
+		"ContributorWithoutAppLabel": {
+			reason: "Scopes should record the AppConfig as a contributor even if the workload carries no app label.",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error { return nil }),
+			rawClient: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if scopeDef, ok := obj.(*v1alpha2.ScopeDefinition); ok {
+						*scopeDef = scopeDefinition
+						return nil
+					}
+					return nil
+				},
+				MockUpdate: func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					want := `{"workload.oam.dev/workloadKind/workload-example":["app"]}`
+					if got := obj.(*unstructured.Unstructured).GetAnnotations()[oam.AnnotationScopeContributors]; got != want {
+						return fmt.Errorf("want contributors %s, got %s", want, got)
+					}
+					return nil
+				},
+				MockList: test.NewMockListFn(nil),
+			},
+			args: args{
+				w: []Workload{{
+					Workload: workload,
+					Scopes:   []unstructured.Unstructured{*scope.DeepCopy()},
+				}},
+				ws: []v1alpha2.WorkloadStatus{},
+			},
+		},
+		"ScopeNotApplies": {
+			reason: "Workloads of kinds a scope does not apply to should not join it.",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error { return nil }),
+			rawClient: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if scopeDef, ok := obj.(*v1alpha2.ScopeDefinition); ok {
+						*scopeDef = scopeDefinition
+						scopeDef.Spec.AppliesToWorkloads = []string{"deployment.apps"}
+						return nil
+					}
+					return nil
+				},
+				MockUpdate: func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					return fmt.Errorf("update is not expected in this test")
+				},
+			},
+			args: args{
+				w: []Workload{{
+					Workload: workload,
+					Scopes:   []unstructured.Unstructured{*scope.DeepCopy()},
+				}},
+				ws: []v1alpha2.WorkloadStatus{},
+			},
+			want: errors.Errorf(errFmtScopeNotApplies, workload.GetAPIVersion(), workload.GetKind(), workload.GetName(),
+				scope.GetAPIVersion(), scope.GetKind(), scope.GetName(), scopeDefinition.GetName(), []string{"deployment.apps"}),
+		},
 		"SuccessWithScopeNoOp": {
-			reason: "Scope already has workloadRef.",
+			reason: "Scope already has workloadRef contributed by the AppConfig.",
 			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error { return nil }),
 			rawClient: &test.MockClient{
 				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
@@ -230,12 +305,21 @@ func TestApplyWorkloads(t *testing.T) {
 				MockUpdate: func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
 					return fmt.Errorf("update is not expected in this test")
 				},
+				MockList: test.NewMockListFn(nil),
 			},
 			args: args{
 				w: []Workload{{
 					Workload: workload,
 					Traits:   []*Trait{{Object: *trait.DeepCopy()}},
-					Scopes:   []unstructured.Unstructured{*scopeWithRef.DeepCopy()},
+					Scopes: []unstructured.Unstructured{func() unstructured.Unstructured {
+						s := scopeWithRef.DeepCopy()
+						_, _ = addScopeContributor(s, "app", v1alpha1.TypedReference{
+							APIVersion: workload.GetAPIVersion(),
+							Kind:       workload.GetKind(),
+							Name:       workload.GetName(),
+						})
+						return *s
+					}()},
 				}},
 				ws: []v1alpha2.WorkloadStatus{
 					{
@@ -257,6 +341,109 @@ func TestApplyWorkloads(t *testing.T) {
 				},
 			},
 		},
+		"ScopeOverlapRendered": {
+			reason: "Workloads should not join two scopes of a kind whose definition does not allow component overlap.",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error { return nil }),
+			rawClient: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if scopeDef, ok := obj.(*v1alpha2.ScopeDefinition); ok {
+						*scopeDef = scopeDefinition
+						return nil
+					}
+					return nil
+				},
+				MockUpdate: func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					return fmt.Errorf("update is not expected in this test")
+				},
+			},
+			args: args{
+				w: []Workload{{
+					Workload: workload,
+					Scopes:   []unstructured.Unstructured{*scope.DeepCopy(), *otherScope.DeepCopy()},
+				}},
+				ws: []v1alpha2.WorkloadStatus{},
+			},
+			want: errors.Errorf(errFmtScopeOverlap, workload.GetAPIVersion(), workload.GetKind(), workload.GetName(),
+				scope.GetAPIVersion(), scope.GetKind(), scope.GetName(), scopeDefinition.GetName(), otherScope.GetName()),
+		},
+		"ScopeOverlapExisting": {
+			reason: "Workloads should not join a scope while another scope of its kind refers to them, if its definition does not allow component overlap.",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error { return nil }),
+			rawClient: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if scopeDef, ok := obj.(*v1alpha2.ScopeDefinition); ok {
+						*scopeDef = scopeDefinition
+						return nil
+					}
+					return nil
+				},
+				MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+					l := obj.(*unstructured.UnstructuredList)
+					l.Items = []unstructured.Unstructured{*scope.DeepCopy(), *otherScopeWithRef.DeepCopy()}
+					return nil
+				},
+				MockUpdate: func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					return fmt.Errorf("update is not expected in this test")
+				},
+			},
+			args: args{
+				w: []Workload{{
+					Workload: workload,
+					Scopes:   []unstructured.Unstructured{*scope.DeepCopy()},
+				}},
+				ws: []v1alpha2.WorkloadStatus{},
+			},
+			want: errors.Errorf(errFmtScopeOverlap, workload.GetAPIVersion(), workload.GetKind(), workload.GetName(),
+				scope.GetAPIVersion(), scope.GetKind(), scope.GetName(), scopeDefinition.GetName(), otherScopeWithRef.GetName()),
+		},
+		"SuccessMovingScope": {
+			reason: "Workloads should join a scope while another scope of its kind refers to them, if they are leaving that scope.",
+			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error { return nil }),
+			rawClient: &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if scopeDef, ok := obj.(*v1alpha2.ScopeDefinition); ok {
+						*scopeDef = scopeDefinition
+						return nil
+					}
+					if key.Name == otherScopeWithRef.GetName() {
+						otherScopeWithRef.DeepCopyInto(obj.(*unstructured.Unstructured))
+					}
+					return nil
+				},
+				MockList: func(_ context.Context, obj runtime.Object, _ ...client.ListOption) error {
+					l := obj.(*unstructured.UnstructuredList)
+					l.Items = []unstructured.Unstructured{*scope.DeepCopy(), *otherScopeWithRef.DeepCopy()}
+					return nil
+				},
+				MockUpdate: func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					return nil
+				},
+			},
+			args: args{
+				w: []Workload{{
+					Workload: workload,
+					Scopes:   []unstructured.Unstructured{*scope.DeepCopy()},
+				}},
+				ws: []v1alpha2.WorkloadStatus{
+					{
+						Reference: v1alpha1.TypedReference{
+							APIVersion: workload.GetAPIVersion(),
+							Kind:       workload.GetKind(),
+							Name:       workload.GetName(),
+						},
+						Scopes: []v1alpha2.WorkloadScope{
+							{
+								Reference: v1alpha1.TypedReference{
+									APIVersion: otherScopeWithRef.GetAPIVersion(),
+									Kind:       otherScopeWithRef.GetKind(),
+									Name:       otherScopeWithRef.GetName(),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		"SuccessRemoving": {
 			reason: "Removes workload refs from scopes.",
 			client: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error { return nil }),
@@ -322,7 +509,11 @@ func TestApplyWorkloads(t *testing.T) {
 			mapper := mock.NewMockDiscoveryMapper()
 
 			w := workloads{client: tc.client, rawClient: tc.rawClient, dm: mapper}
-			err := w.Apply(tc.args.ctx, tc.args.ws, tc.args.w)
+			ac := &v1alpha2.ApplicationConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "app"},
+				Status:     v1alpha2.ApplicationConfigurationStatus{Workloads: tc.args.ws},
+			}
+			err := w.Apply(tc.args.ctx, ac, tc.args.w)
 
 			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nw.Apply(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -480,3 +671,130 @@ func TestFinalizeWorkloadScopes(t *testing.T) {
 	}
 
 }
+
+func TestApplyScopeRemoval(t *testing.T) {
+	wr := v1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+	refObj := map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"}
+	ws := v1alpha2.WorkloadScope{Reference: v1alpha1.TypedReference{
+		APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "shared"}}
+	scope := func(contributors string, refs ...interface{}) *unstructured.Unstructured {
+		s := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "core.oam.dev/v1alpha2",
+			"kind":       "HealthScope",
+			"metadata":   map[string]interface{}{"namespace": "ns", "name": "shared"},
+			"spec":       map[string]interface{}{"workloadRefs": append([]interface{}{}, refs...)},
+		}}
+		if contributors != "" {
+			s.SetAnnotations(map[string]string{oam.AnnotationScopeContributors: contributors})
+		}
+		return s
+	}
+	scopeDefinition := v1alpha2.ScopeDefinition{Spec: v1alpha2.ScopeDefinitionSpec{WorkloadRefsPath: "spec.workloadRefs"}}
+
+	cases := map[string]struct {
+		reason string
+		scope  *unstructured.Unstructured
+		want   *unstructured.Unstructured
+	}{
+		"UntrackedWorkload": {
+			reason: "Workloads whose contributors are not recorded should be removed from the scope",
+			scope:  scope("", refObj),
+			want:   scope(""),
+		},
+		"LastContributor": {
+			reason: "Workloads should be removed from the scope with their last contributor",
+			scope:  scope(`{"apps/v1/Deployment/web":["app"]}`, refObj),
+			want: func() *unstructured.Unstructured {
+				s := scope("")
+				s.SetAnnotations(map[string]string{})
+				return s
+			}(),
+		},
+		"SharedWorkload": {
+			reason: "Workloads other AppConfigs contribute should be kept in the scope",
+			scope:  scope(`{"apps/v1/Deployment/web":["app","other"]}`, refObj),
+			want:   scope(`{"apps/v1/Deployment/web":["other"]}`, refObj),
+		},
+		"OtherContributor": {
+			reason: "Workloads only other AppConfigs contribute should not be touched",
+			scope:  scope(`{"apps/v1/Deployment/web":["other"]}`, refObj),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated *unstructured.Unstructured
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					switch o := obj.(type) {
+					case *v1alpha2.ScopeDefinition:
+						*o = scopeDefinition
+					case *unstructured.Unstructured:
+						tc.scope.DeepCopyInto(o)
+					}
+					return nil
+				},
+				MockUpdate: func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+					updated = obj.(*unstructured.Unstructured).DeepCopy()
+					return nil
+				},
+			}
+			w := workloads{rawClient: c, dm: mock.NewMockDiscoveryMapper()}
+			if err := w.applyScopeRemoval(context.Background(), "ns", "app", wr, ws); err != nil {
+				t.Fatalf("\n%s\nw.applyScopeRemoval(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, updated); diff != "" {
+				t.Errorf("\n%s\nw.applyScopeRemoval(...): -want updated scope, +got updated scope:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAddScopeContributor(t *testing.T) {
+	wr := v1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+
+	cases := map[string]struct {
+		acName string
+		before map[string]string
+		want   map[string]string
+		added  bool
+	}{
+		"NoAppConfig": {
+			before: nil,
+			want:   nil,
+		},
+		"FirstContributor": {
+			acName: "app",
+			want:   map[string]string{oam.AnnotationScopeContributors: `{"apps/v1/Deployment/web":["app"]}`},
+			added:  true,
+		},
+		"OtherContributor": {
+			acName: "app",
+			before: map[string]string{oam.AnnotationScopeContributors: `{"apps/v1/Deployment/web":["other"]}`},
+			want:   map[string]string{oam.AnnotationScopeContributors: `{"apps/v1/Deployment/web":["app","other"]}`},
+			added:  true,
+		},
+		"AlreadyContributed": {
+			acName: "app",
+			before: map[string]string{oam.AnnotationScopeContributors: `{"apps/v1/Deployment/web":["app"]}`},
+			want:   map[string]string{oam.AnnotationScopeContributors: `{"apps/v1/Deployment/web":["app"]}`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &unstructured.Unstructured{}
+			s.SetAnnotations(tc.before)
+			added, err := addScopeContributor(s, tc.acName, wr)
+			if err != nil {
+				t.Fatalf("\naddScopeContributor(...): unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.added, added); diff != "" {
+				t.Errorf("\naddScopeContributor(...): -want added, +got added:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, s.GetAnnotations()); diff != "" {
+				t.Errorf("\naddScopeContributor(...): -want annotations, +got annotations:\n%s", diff)
+			}
+		})
+	}
+}
@@ -2,13 +2,15 @@ package applicationconfiguration
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
@@ -19,6 +21,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/render"
 	util "github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
@@ -26,6 +30,10 @@ import (
 // This label is to filter revision by client api
 const ControllerRevisionComponentLabel = "controller.oam.dev/component"
 
+// maxRevisionDiffFieldPaths limits the field paths recorded in the revision
+// diff, so that it can't blow up the status of a Component.
+const maxRevisionDiffFieldPaths = 10
+
 // ComponentHandler will watch component change and generate Revision automatically.
 type ComponentHandler struct {
 	Client        client.Client
@@ -121,12 +129,46 @@ func (c *ComponentHandler) IsRevisionDiff(mt metav1.Object, curComp *v1alpha2.Co
 		return true, oldRev.Revision
 	}
 
-	if reflect.DeepEqual(curComp.Spec, oldComp.Spec) {
+	if util.ComputeComponentRevisionHash(curComp) == util.ComputeComponentRevisionHash(oldComp) {
 		return false, oldRev.Revision
 	}
 	return true, oldRev.Revision
 }
 
+// revisionDiff summarizes how the supplied component differs from its latest
+// revision, which the supplied new revision succeeds. It returns nil if the
+// component has no latest revision or it can't be read.
+func (c *ComponentHandler) revisionDiff(comp *v1alpha2.Component, revisionName string) *v1alpha2.RevisionDiff {
+	if comp.Status.LatestRevision == nil {
+		return nil
+	}
+	from := comp.Status.LatestRevision.Name
+	rev := &appsv1.ControllerRevision{}
+	if err := c.Client.Get(context.TODO(), client.ObjectKey{Namespace: comp.GetNamespace(), Name: from}, rev); err != nil {
+		c.Logger.Info(fmt.Sprintf("get controllerRevision %s error %v, will not diff the new revision", from, err), "componentName", comp.GetName())
+		return nil
+	}
+	old, err := util.UnpackRevisionData(rev)
+	if err != nil {
+		c.Logger.Info(fmt.Sprintf("unmarshal controllerRevision %s error %v, will not diff the new revision", from, err), "componentName", comp.GetName())
+		return nil
+	}
+	paths := util.DiffComponentRevisions(old, comp)
+	if len(paths) > maxRevisionDiffFieldPaths {
+		paths = paths[:maxRevisionDiffFieldPaths]
+	}
+	return &v1alpha2.RevisionDiff{From: from, To: revisionName, FieldPaths: paths}
+}
+
+// revisionLimit returns the revision limit of the supplied component, or the
+// revision limit of the handler if the component doesn't specify one.
+func (c *ComponentHandler) revisionLimit(comp *v1alpha2.Component) int {
+	if comp.Spec.RevisionHistoryLimit != nil {
+		return int(*comp.Spec.RevisionHistoryLimit)
+	}
+	return c.RevisionLimit
+}
+
 func newTrue() *bool {
 	b := true
 	return &b
@@ -143,6 +185,7 @@ func (c *ComponentHandler) createControllerRevision(mt metav1.Object, obj runtim
 	nextRevision := curRevision + 1
 	revisionName := ConstructRevisionName(mt.GetName(), nextRevision)
 
+	comp.Status.RevisionDiff = c.revisionDiff(comp, revisionName)
 	comp.Status.LatestRevision = &v1alpha2.Revision{
 		Name:     revisionName,
 		Revision: nextRevision,
@@ -185,7 +228,7 @@ func (c *ComponentHandler) createControllerRevision(mt metav1.Object, obj runtim
 		return false
 	}
 	c.Logger.Info(fmt.Sprintf("ControllerRevision %s created", revisionName))
-	if int64(c.RevisionLimit) < nextRevision {
+	if int64(c.revisionLimit(comp)) < nextRevision {
 		if err := c.cleanupControllerRevision(comp); err != nil {
 			c.Logger.Info(fmt.Sprintf("failed to clean up revisions of Component %v.", err))
 		}
@@ -206,6 +249,12 @@ func sortedControllerRevision(appConfigs []v1alpha2.ApplicationConfiguration, re
 				liveHashes[component.RevisionName] = true
 			}
 		}
+		// revisions whose workloads are still active
+		for _, w := range appConfig.Status.Workloads {
+			if w.ComponentRevisionName != "" {
+				liveHashes[w.ComponentRevisionName] = true
+			}
+		}
 	}
 
 	toKeep := revisionLimit + len(liveHashes)
@@ -246,7 +295,7 @@ func (c *ComponentHandler) cleanupControllerRevision(curComp *v1alpha2.Component
 	}
 
 	// get sorted revisions
-	controllerRevisions, toKill, liveHashes := sortedControllerRevision(appConfigs.Items, revisions.Items, c.RevisionLimit)
+	controllerRevisions, toKill, liveHashes := sortedControllerRevision(appConfigs.Items, revisions.Items, c.revisionLimit(curComp))
 	for _, revision := range controllerRevisions {
 		if toKill <= 0 {
 			break
@@ -256,6 +305,9 @@ func (c *ComponentHandler) cleanupControllerRevision(curComp *v1alpha2.Component
 		}
 		// Clean up
 		revisionToClean := revision
+		if err := c.cleanupRevisionWorkload(&revisionToClean); err != nil {
+			return err
+		}
 		if err := c.Client.Delete(context.TODO(), &revisionToClean); err != nil {
 			return err
 		}
@@ -265,16 +317,66 @@ func (c *ComponentHandler) cleanupControllerRevision(curComp *v1alpha2.Component
 	return nil
 }
 
+// cleanupRevisionWorkload deletes the workload that is named after the supplied
+// revision, which is created for each revision of a revision enabled component.
+func (c *ComponentHandler) cleanupRevisionWorkload(rev *appsv1.ControllerRevision) error {
+	comp, err := util.UnpackRevisionData(rev)
+	if err != nil {
+		return err
+	}
+	w := &unstructured.Unstructured{}
+	switch {
+	case len(comp.Spec.Workload.Raw) > 0:
+		if err := json.Unmarshal(comp.Spec.Workload.Raw, w); err != nil {
+			return err
+		}
+	case comp.Spec.Workload.Object != nil:
+		if w, err = util.Object2Unstructured(comp.Spec.Workload.Object); err != nil {
+			return err
+		}
+	}
+	if w.GetKind() == "" {
+		return nil
+	}
+	key := client.ObjectKey{Namespace: rev.GetNamespace(), Name: rev.GetName()}
+	if err := c.Client.Get(context.TODO(), key, w); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// only delete the workload if it belongs to the revision
+	if w.GetLabels()[oam.LabelAppComponentRevision] != rev.GetName() {
+		return nil
+	}
+	if err := c.Client.Delete(context.TODO(), w); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	c.Logger.Info(fmt.Sprintf("Workload %s of revision %s deleted", w.GetKind(), rev.GetName()))
+	return nil
+}
+
 // ConstructRevisionName will generate revisionName from componentName
 // will be <componentName>-v<RevisionNumber>, for example: comp-v1
 func ConstructRevisionName(componentName string, revision int64) string {
 	return strings.Join([]string{componentName, fmt.Sprintf("v%d", revision)}, "-")
 }
 
+// extractRevision will extract the revision number from revisionName. It
+// returns false if revisionName is not constructed by ConstructRevisionName.
+func extractRevision(revisionName string) (int64, bool) {
+	splits := strings.Split(revisionName, "-")
+	last := splits[len(splits)-1]
+	if len(splits) < 2 || !strings.HasPrefix(last, "v") {
+		return 0, false
+	}
+	revision, err := strconv.ParseInt(strings.TrimPrefix(last, "v"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return revision, true
+}
+
 // ExtractComponentName will extract componentName from revisionName
 func ExtractComponentName(revisionName string) string {
-	splits := strings.Split(revisionName, "-")
-	return strings.Join(splits[0:len(splits)-1], "-")
+	return render.ExtractComponentName(revisionName)
 }
 
 // historiesByRevision sort controllerRevision by revision
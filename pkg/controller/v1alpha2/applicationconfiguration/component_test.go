@@ -12,8 +12,11 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -22,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 )
 
 func TestComponentHandler(t *testing.T) {
@@ -179,6 +183,12 @@ func TestComponentHandler(t *testing.T) {
 			// check component's status saved in corresponding controllerRevision
 			assert.Equal(t, gotComp.Status.LatestRevision.Name, v.Name)
 			assert.Equal(t, gotComp.Status.LatestRevision.Revision, v.Revision)
+			// check how the revision differs from the previous one
+			assert.Equal(t, &v1alpha2.RevisionDiff{
+				From:       comp2.Status.LatestRevision.Name,
+				To:         v.Name,
+				FieldPaths: []string{"workload.spec.template.spec.containers[0].image"},
+			}, gotComp.Status.RevisionDiff)
 		}
 	}
 	q.Done(item)
@@ -233,8 +243,17 @@ func TestConstructExtract(t *testing.T) {
 			if got != componentName {
 				t.Errorf("want to get %s from %s but got %s", componentName, revisionName, got)
 			}
+			revision, ok := extractRevision(revisionName)
+			if !ok || revision != revisionNum[idx] {
+				t.Errorf("want to get revision %d from %s but got %d", revisionNum[idx], revisionName, revision)
+			}
 		})
 	}
+	for _, name := range []string{"comp", "comp-vx", "comp-aa1111"} {
+		if _, ok := extractRevision(name); ok {
+			t.Errorf("want no revision from %s", name)
+		}
+	}
 }
 
 func TestIsMatch(t *testing.T) {
@@ -309,4 +328,77 @@ func TestSortedControllerRevision(t *testing.T) {
 	_, toKill, liveHashes := sortedControllerRevision(appconfigs, revisions, 2)
 	assert.Equal(t, 0, toKill, "Needn't to delete")
 	assert.Equal(t, 1, len(liveHashes), "LiveHashes worked")
+
+	activeAppconfigs := []v1alpha2.ApplicationConfiguration{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-app", Namespace: "test"},
+			Status: v1alpha2.ApplicationConfigurationStatus{
+				Workloads: []v1alpha2.WorkloadStatus{{ComponentName: "foo", ComponentRevisionName: "revision1"}},
+			},
+		},
+	}
+	_, toKill, liveHashes = sortedControllerRevision(activeAppconfigs, revisions, 2)
+	assert.Equal(t, 0, toKill, "Revisions of active workloads needn't to delete")
+	assert.Equal(t, true, liveHashes["revision1"], "Revisions of active workloads are live")
+}
+
+func TestRevisionLimit(t *testing.T) {
+	limit := int32(5)
+	h := &ComponentHandler{RevisionLimit: 50}
+	assert.Equal(t, 50, h.revisionLimit(&v1alpha2.Component{}), "Use the revision limit of the handler by default")
+	assert.Equal(t, 5, h.revisionLimit(&v1alpha2.Component{Spec: v1alpha2.ComponentSpec{RevisionHistoryLimit: &limit}}),
+		"The revision limit of the component takes precedence")
+}
+
+func TestCleanupRevisionWorkload(t *testing.T) {
+	rev := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "comp1-v1", Namespace: "biz"},
+		Data: runtime.RawExtension{Object: &v1alpha2.Component{
+			Spec: v1alpha2.ComponentSpec{Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)}},
+		}},
+	}
+	workload := func(revision string) func(obj runtime.Object) error {
+		return func(obj runtime.Object) error {
+			w := obj.(*unstructured.Unstructured)
+			w.SetName("comp1-v1")
+			w.SetLabels(map[string]string{oam.LabelAppComponentRevision: revision})
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		get     test.MockGetFn
+		deleted bool
+	}{
+		"RevisionWorkload": {
+			get:     test.NewMockGetFn(nil, workload("comp1-v1")),
+			deleted: true,
+		},
+		"WorkloadOfOtherRevision": {
+			get: test.NewMockGetFn(nil, workload("comp1-v2")),
+		},
+		"NoWorkload": {
+			get: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "comp1-v1")),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			deleted := false
+			h := &ComponentHandler{
+				Client: &test.MockClient{
+					MockGet: tc.get,
+					MockDelete: test.NewMockDeleteFn(nil, func(obj runtime.Object) error {
+						w := obj.(*unstructured.Unstructured)
+						assert.Equal(t, "Deployment", w.GetKind())
+						assert.Equal(t, "comp1-v1", w.GetName())
+						deleted = true
+						return nil
+					}),
+				},
+				Logger: logging.NewNopLogger(),
+			}
+			assert.NoError(t, h.cleanupRevisionWorkload(rev))
+			assert.Equal(t, tc.deleted, deleted)
+		})
+	}
 }
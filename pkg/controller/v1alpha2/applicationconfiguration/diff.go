@@ -0,0 +1,191 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+const errFmtGetAppliedResource = "cannot get applied resource %q %q %q"
+
+// maxDiffFieldPaths limits the field paths recorded for each resource, so that
+// the render diff can't blow up the status of an ApplicationConfiguration.
+const maxDiffFieldPaths = 10
+
+// renderDiffMode returns the render diff mode of the supplied
+// ApplicationConfiguration, or an empty string if render diff is disabled.
+func renderDiffMode(ac *v1alpha2.ApplicationConfiguration) string {
+	switch m := ac.GetAnnotations()[oam.AnnotationRenderDiff]; m {
+	case oam.RenderDiffEnabled, oam.RenderDiffDryRun:
+		return m
+	}
+	return ""
+}
+
+// computeRenderDiff summarizes how the applied resources differ from the
+// supplied rendered workloads and traits. Resources that are eligible for
+// garbage collection are reported to be deleted.
+func computeRenderDiff(ctx context.Context, c client.Reader, workloads []Workload,
+	eligible []unstructured.Unstructured) (*v1alpha2.RenderDiff, error) {
+	d := &v1alpha2.RenderDiff{}
+	for _, w := range workloads {
+		// workloads managed by a trait are not applied
+		var rendered []*unstructured.Unstructured
+		if !w.SkipApply {
+			rendered = append(rendered, w.Workload)
+		}
+		for i := range w.AuxiliaryWorkloads {
+			if !w.AuxiliaryWorkloads[i].SkipApply {
+				rendered = append(rendered, &w.AuxiliaryWorkloads[i].Object)
+			}
+		}
+		for _, t := range w.Traits {
+			rendered = append(rendered, &t.Object)
+		}
+		for _, r := range rendered {
+			rd, err := diffResource(ctx, c, r)
+			if err != nil {
+				return nil, err
+			}
+			if rd != nil {
+				d.Resources = append(d.Resources, *rd)
+			}
+		}
+	}
+	for i := range eligible {
+		d.Resources = append(d.Resources, v1alpha2.ResourceDiff{
+			Reference: typedReference(&eligible[i]),
+			Action:    v1alpha2.DiffActionDelete,
+		})
+	}
+	return d, nil
+}
+
+func diffResource(ctx context.Context, c client.Reader, rendered *unstructured.Unstructured) (*v1alpha2.ResourceDiff, error) {
+	applied := &unstructured.Unstructured{}
+	applied.SetGroupVersionKind(rendered.GroupVersionKind())
+	err := c.Get(ctx, types.NamespacedName{Namespace: rendered.GetNamespace(), Name: rendered.GetName()}, applied)
+	if apierrors.IsNotFound(err) {
+		return &v1alpha2.ResourceDiff{Reference: typedReference(rendered), Action: v1alpha2.DiffActionCreate}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetAppliedResource, rendered.GetAPIVersion(), rendered.GetKind(), rendered.GetName())
+	}
+
+	var paths []string
+	for k, v := range rendered.Object {
+		switch k {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			// only labels and annotations of the metadata are rendered
+			for _, f := range []string{"labels", "annotations"} {
+				rv, ok, _ := unstructured.NestedFieldNoCopy(rendered.Object, "metadata", f)
+				if !ok {
+					continue
+				}
+				av, _, _ := unstructured.NestedFieldNoCopy(applied.Object, "metadata", f)
+				paths = append(paths, diffFieldPaths("metadata."+f, av, rv)...)
+			}
+		default:
+			paths = append(paths, diffFieldPaths(k, applied.Object[k], v)...)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	sort.Strings(paths)
+	if len(paths) > maxDiffFieldPaths {
+		paths = paths[:maxDiffFieldPaths]
+	}
+	return &v1alpha2.ResourceDiff{Reference: typedReference(rendered), Action: v1alpha2.DiffActionUpdate, FieldPaths: paths}, nil
+}
+
+// diffFieldPaths returns the paths of the fields that are set in the rendered
+// value but differ in the applied value. Fields that are only set in the
+// applied value, e.g. defaulted by the API server, are ignored.
+func diffFieldPaths(path string, applied, rendered interface{}) []string {
+	switch r := rendered.(type) {
+	case map[string]interface{}:
+		a, ok := applied.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+		var paths []string
+		for k, v := range r {
+			paths = append(paths, diffFieldPaths(joinFieldPath(path, k), a[k], v)...)
+		}
+		return paths
+	case []interface{}:
+		a, ok := applied.([]interface{})
+		if !ok || len(a) != len(r) {
+			return []string{path}
+		}
+		var paths []string
+		for i := range r {
+			paths = append(paths, diffFieldPaths(fmt.Sprintf("%s[%d]", path, i), a[i], r[i])...)
+		}
+		return paths
+	}
+	if !reflect.DeepEqual(normalizeNumber(applied), normalizeNumber(rendered)) {
+		return []string{path}
+	}
+	return nil
+}
+
+func joinFieldPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	return path + "." + key
+}
+
+// normalizeNumber converts numbers to float64, since rendered numbers and
+// numbers read from the API server may be decoded into different types.
+func normalizeNumber(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return v
+}
+
+func typedReference(u *unstructured.Unstructured) runtimev1alpha1.TypedReference {
+	return runtimev1alpha1.TypedReference{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Name:       u.GetName(),
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestDiffFieldPaths(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		applied  interface{}
+		rendered interface{}
+		want     []string
+	}{
+		"Equal": {
+			reason:   "No path should be returned when the rendered value is applied",
+			applied:  map[string]interface{}{"replicas": int64(2), "image": "nginx"},
+			rendered: map[string]interface{}{"replicas": float64(2), "image": "nginx"},
+		},
+		"DefaultedField": {
+			reason:   "Fields only set in the applied value should be ignored",
+			applied:  map[string]interface{}{"replicas": int64(2), "strategy": "RollingUpdate"},
+			rendered: map[string]interface{}{"replicas": int64(2)},
+		},
+		"ChangedValue": {
+			reason:   "The path of a changed value should be returned",
+			applied:  map[string]interface{}{"containers": []interface{}{map[string]interface{}{"image": "nginx:1.18"}}},
+			rendered: map[string]interface{}{"containers": []interface{}{map[string]interface{}{"image": "nginx:1.19"}}},
+			want:     []string{"spec.containers[0].image"},
+		},
+		"ChangedLength": {
+			reason:   "The path of a list should be returned when its length changes",
+			applied:  map[string]interface{}{"args": []interface{}{"a"}},
+			rendered: map[string]interface{}{"args": []interface{}{"a", "b"}},
+			want:     []string{"spec.args"},
+		},
+		"KeyWithDot": {
+			reason:   "Keys containing dots should be enclosed in brackets",
+			applied:  map[string]interface{}{},
+			rendered: map[string]interface{}{"app.oam.dev/name": "app"},
+			want:     []string{"spec[app.oam.dev/name]"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := diffFieldPaths("spec", tc.applied, tc.rendered)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ndiffFieldPaths(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComputeRenderDiff(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	workload := func(replicas int64) *unstructured.Unstructured {
+		w := &unstructured.Unstructured{}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetName("web")
+		w.SetNamespace("ns")
+		w.SetLabels(map[string]string{oam.LabelAppName: "app"})
+		_ = unstructured.SetNestedField(w.Object, replicas, "spec", "replicas")
+		return w
+	}
+	trait := &unstructured.Unstructured{}
+	trait.SetAPIVersion("core.oam.dev/v1alpha2")
+	trait.SetKind("ManualScalerTrait")
+	trait.SetName("web-trait")
+	stale := unstructured.Unstructured{}
+	stale.SetAPIVersion("core.oam.dev/v1alpha2")
+	stale.SetKind("ManualScalerTrait")
+	stale.SetName("stale-trait")
+
+	applied := workload(1)
+	applied.SetUID("uid")
+	applied.SetResourceVersion("1")
+	_ = unstructured.SetNestedField(applied.Object, "Available", "status", "phase")
+
+	ws := []Workload{{Workload: workload(2), Traits: []*Trait{{Object: *trait}}}}
+
+	type args struct {
+		client   client.Reader
+		eligible []unstructured.Unstructured
+	}
+	type want struct {
+		d   *v1alpha2.RenderDiff
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CreateUpdateDelete": {
+			reason: "Changed, missing and garbage collected resources should be reported",
+			args: args{
+				client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if key.Name != "web" {
+						return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+					}
+					applied.DeepCopyInto(obj.(*unstructured.Unstructured))
+					return nil
+				}},
+				eligible: []unstructured.Unstructured{stale},
+			},
+			want: want{
+				d: &v1alpha2.RenderDiff{Resources: []v1alpha2.ResourceDiff{
+					{
+						Reference:  runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+						Action:     v1alpha2.DiffActionUpdate,
+						FieldPaths: []string{"spec.replicas"},
+					},
+					{
+						Reference: runtimev1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "ManualScalerTrait", Name: "web-trait"},
+						Action:    v1alpha2.DiffActionCreate,
+					},
+					{
+						Reference: runtimev1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "ManualScalerTrait", Name: "stale-trait"},
+						Action:    v1alpha2.DiffActionDelete,
+					},
+				}},
+			},
+		},
+		"GetError": {
+			reason: "Errors getting the applied resources should be returned",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetAppliedResource, "apps/v1", "Deployment", "web"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := computeRenderDiff(context.Background(), tc.args.client, ws, tc.args.eligible)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncomputeRenderDiff(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.d, got); diff != "" {
+				t.Errorf("\n%s\ncomputeRenderDiff(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderDiffMode(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"NoAnnotation": {},
+		"Enabled": {
+			annotations: map[string]string{oam.AnnotationRenderDiff: oam.RenderDiffEnabled},
+			want:        oam.RenderDiffEnabled,
+		},
+		"DryRun": {
+			annotations: map[string]string{oam.AnnotationRenderDiff: oam.RenderDiffDryRun},
+			want:        oam.RenderDiffDryRun,
+		},
+		"Unknown": {
+			annotations: map[string]string{oam.AnnotationRenderDiff: "false"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ac := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := renderDiffMode(ac); got != tc.want {
+				t.Errorf("renderDiffMode(...): want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
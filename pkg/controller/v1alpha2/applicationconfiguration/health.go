@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/health"
+)
+
+const errFmtGetHealthResource = "cannot get %s %q to evaluate its health"
+
+// updateHealth evaluates the health policies of the definitions of the
+// supplied workloads and their traits against the applied resources, and
+// records their health in the supplied statuses. It returns their rolled up
+// health, which is unset if no definition has a health policy.
+func updateHealth(ctx context.Context, c client.Reader, status []v1alpha2.WorkloadStatus, workloads []Workload) v1alpha2.HealthStatus {
+	var evaluated, unhealthy bool
+	record := func(s v1alpha2.HealthStatus) {
+		evaluated = true
+		unhealthy = unhealthy || s != v1alpha2.StatusHealthy
+	}
+	for i, w := range workloads {
+		if w.HealthPolicy != nil {
+			status[i].HealthStatus, status[i].HealthDiagnosis = evaluateHealth(ctx, c, w.HealthPolicy, w.Workload)
+			record(status[i].HealthStatus)
+		}
+		for j, t := range w.Traits {
+			if t.Definition.Spec.HealthPolicy == nil {
+				continue
+			}
+			tr := &status[i].Traits[j]
+			tr.HealthStatus, tr.HealthDiagnosis = evaluateHealth(ctx, c, t.Definition.Spec.HealthPolicy, &t.Object)
+			record(tr.HealthStatus)
+		}
+	}
+	switch {
+	case !evaluated:
+		return ""
+	case unhealthy:
+		return v1alpha2.StatusUnhealthy
+	default:
+		return v1alpha2.StatusHealthy
+	}
+}
+
+// evaluateHealth evaluates the supplied health policy against the applied
+// counterpart of the supplied rendered resource.
+func evaluateHealth(ctx context.Context, c client.Reader, p *v1alpha2.HealthPolicy, rendered *unstructured.Unstructured) (v1alpha2.HealthStatus, string) {
+	applied, err := getApplied(ctx, c, rendered)
+	if err != nil {
+		return v1alpha2.StatusUnknown, errors.Wrapf(err, errFmtGetHealthResource, rendered.GetKind(), rendered.GetName()).Error()
+	}
+	return health.Evaluate(p, applied)
+}
+
+// getApplied returns the applied counterpart of the supplied rendered
+// resource.
+func getApplied(ctx context.Context, c client.Reader, rendered *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	applied := &unstructured.Unstructured{}
+	applied.SetGroupVersionKind(rendered.GroupVersionKind())
+	nn := types.NamespacedName{Namespace: rendered.GetNamespace(), Name: rendered.GetName()}
+	if err := c.Get(ctx, nn, applied); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
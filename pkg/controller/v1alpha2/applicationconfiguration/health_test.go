@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestUpdateHealth(t *testing.T) {
+	ready := &v1alpha2.HealthPolicy{Conditions: []v1alpha2.HealthCondition{{FieldPath: "status.ready", Value: "true"}}}
+	object := func(kind, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("example.com/v1")
+		u.SetKind(kind)
+		u.SetNamespace("ns")
+		u.SetName(name)
+		return u
+	}
+	// applied objects are ready unless they are named "starting", and the
+	// object named "missing" doesn't exist
+	c := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		if key.Name == "missing" {
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		obj.(*unstructured.Unstructured).Object["status"] = map[string]interface{}{"ready": key.Name != "starting"}
+		return nil
+	}}
+	trait := func(name string, p *v1alpha2.HealthPolicy) *Trait {
+		tr := &Trait{Object: *object("Scaler", name)}
+		tr.Definition.Spec.HealthPolicy = p
+		return tr
+	}
+
+	type want struct {
+		health v1alpha2.HealthStatus
+		status []v1alpha2.WorkloadStatus
+	}
+
+	cases := map[string]struct {
+		reason    string
+		workloads []Workload
+		want      want
+	}{
+		"NoHealthPolicies": {
+			reason:    "The health of an ApplicationConfiguration without health policies is unset",
+			workloads: []Workload{{Workload: object("Server", "web"), Traits: []*Trait{trait("scaler", nil)}}},
+			want: want{
+				status: []v1alpha2.WorkloadStatus{{Traits: []v1alpha2.WorkloadTrait{{}}}},
+			},
+		},
+		"Healthy": {
+			reason: "An ApplicationConfiguration whose workloads and traits are healthy is healthy",
+			workloads: []Workload{
+				{Workload: object("Server", "web"), HealthPolicy: ready, Traits: []*Trait{trait("scaler", ready)}},
+				{Workload: object("Server", "db")},
+			},
+			want: want{
+				health: v1alpha2.StatusHealthy,
+				status: []v1alpha2.WorkloadStatus{
+					{HealthStatus: v1alpha2.StatusHealthy, Traits: []v1alpha2.WorkloadTrait{{HealthStatus: v1alpha2.StatusHealthy}}},
+					{},
+				},
+			},
+		},
+		"UnhealthyTrait": {
+			reason: "An ApplicationConfiguration with an unhealthy trait is unhealthy",
+			workloads: []Workload{
+				{Workload: object("Server", "web"), HealthPolicy: ready, Traits: []*Trait{trait("starting", ready)}},
+			},
+			want: want{
+				health: v1alpha2.StatusUnhealthy,
+				status: []v1alpha2.WorkloadStatus{{
+					HealthStatus: v1alpha2.StatusHealthy,
+					Traits: []v1alpha2.WorkloadTrait{{
+						HealthStatus:    v1alpha2.StatusUnhealthy,
+						HealthDiagnosis: "status.ready is false, not true",
+					}},
+				}},
+			},
+		},
+		"UnknownWorkload": {
+			reason: "An ApplicationConfiguration with a workload of unknown health is unhealthy",
+			workloads: []Workload{
+				{Workload: object("Server", "missing"), HealthPolicy: ready},
+			},
+			want: want{
+				health: v1alpha2.StatusUnhealthy,
+				status: []v1alpha2.WorkloadStatus{{
+					HealthStatus:    v1alpha2.StatusUnknown,
+					HealthDiagnosis: `cannot get Server "missing" to evaluate its health:  "missing" not found`,
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status := make([]v1alpha2.WorkloadStatus, len(tc.workloads))
+			for i, w := range tc.workloads {
+				if len(w.Traits) > 0 {
+					status[i].Traits = make([]v1alpha2.WorkloadTrait, len(w.Traits))
+				}
+			}
+			got := updateHealth(context.Background(), c, status, tc.workloads)
+			if diff := cmp.Diff(tc.want, want{health: got, status: status}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nupdateHealth(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	errFmtDecodeMetricsTrait = "cannot decode MetricsTrait %q"
+	errFmtAnnotatePods       = "cannot annotate the pods of workload %q for MetricsTrait %q"
+	errFmtNoNamedPort        = "no container declares a port named %q"
+)
+
+// Annotations of the pods scraped by Prometheus instances that discover them
+// by their annotations.
+const (
+	annotationScrape = "prometheus.io/scrape"
+	annotationPort   = "prometheus.io/port"
+	annotationPath   = "prometheus.io/path"
+	annotationScheme = "prometheus.io/scheme"
+)
+
+// annotateScrapedPods annotates the pods of the supplied workload for
+// scraping, for each of its MetricsTraits that asks to. The annotations are
+// added to the metadata of the pod template whose spec is at the podSpecPath
+// of the workload. The workload is left as is if its path is not that of the
+// spec of a pod template, i.e. if it does not end in spec.
+func annotateScrapedPods(wl Workload) error {
+	if wl.PodSpecPath != "spec" && !strings.HasSuffix(wl.PodSpecPath, ".spec") {
+		return nil
+	}
+	annotationsPath := strings.TrimSuffix(wl.PodSpecPath, "spec") + "metadata.annotations"
+	for _, t := range podTraitsOf(wl, v1alpha2.MetricsTraitGroupVersionKind) {
+		mt := &v1alpha2.MetricsTrait{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(t.Object, mt); err != nil {
+			return errors.Wrapf(err, errFmtDecodeMetricsTrait, t.GetName())
+		}
+		if !mt.Spec.AnnotatePods {
+			continue
+		}
+		podSpec, err := util.PavePodSpec(wl.Workload, wl.PodSpecPath)
+		if err == nil {
+			err = annotatePods(fieldpath.Pave(wl.Workload.Object), annotationsPath, podSpec, mt.Spec)
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtAnnotatePods, wl.Workload.GetName(), mt.GetName())
+		}
+	}
+	return nil
+}
+
+// annotatePods sets the scrape annotations of the supplied spec at the
+// supplied path of the supplied workload. Named ports are resolved to the
+// number of the port of that name the containers of the pod spec declare.
+// The path and scheme are left to the defaults of Prometheus if the spec does
+// not set them.
+func annotatePods(wl *fieldpath.Paved, path string, podSpec *fieldpath.Paved, spec v1alpha2.MetricsTraitSpec) error {
+	port := spec.Port.IntValue()
+	if spec.Port.Type == intstr.String {
+		var err error
+		if port, err = containerPort(podSpec, spec.Port.StrVal); err != nil {
+			return err
+		}
+	}
+	annotations := map[string]interface{}{}
+	v, err := wl.GetValue(path)
+	if err != nil && !fieldpath.IsNotFound(err) {
+		return err
+	}
+	if existing, ok := v.(map[string]interface{}); ok {
+		annotations = existing
+	}
+	annotations[annotationScrape] = "true"
+	annotations[annotationPort] = strconv.Itoa(port)
+	if spec.Path != "" {
+		annotations[annotationPath] = spec.Path
+	}
+	if spec.Scheme != "" {
+		annotations[annotationScheme] = spec.Scheme
+	}
+	return wl.SetValue(path, annotations)
+}
+
+// containerPort returns the number of the port of the supplied name that the
+// containers of the supplied pod spec declare.
+func containerPort(podSpec *fieldpath.Paved, name string) (int, error) {
+	containers, err := namedList(podSpec, "containers")
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ports, _ := container["ports"].([]interface{})
+		for _, p := range ports {
+			port, ok := p.(map[string]interface{})
+			if !ok || port["name"] != name {
+				continue
+			}
+			switch n := port["containerPort"].(type) {
+			case int64:
+				return int(n), nil
+			case float64:
+				return int(n), nil
+			}
+		}
+	}
+	return 0, errors.Errorf(errFmtNoNamedPort, name)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestAnnotateScrapedPods(t *testing.T) {
+	metricsTrait := func(spec v1alpha2.MetricsTraitSpec) *Trait {
+		u, _ := util.Object2Unstructured(&v1alpha2.MetricsTrait{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.MetricsTraitKind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "metrics"},
+			Spec:       spec,
+		})
+		return &Trait{Object: *u}
+	}
+	deployment := func(annotations map[string]interface{}) *unstructured.Unstructured {
+		template := map[string]interface{}{
+			"spec": map[string]interface{}{"containers": []interface{}{map[string]interface{}{
+				"name":  "web",
+				"ports": []interface{}{map[string]interface{}{"name": "metrics", "containerPort": int64(9090)}},
+			}}},
+		}
+		if annotations != nil {
+			template["metadata"] = map[string]interface{}{"annotations": annotations}
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web"},
+			"spec":       map[string]interface{}{"template": template},
+		}}
+	}
+
+	type want struct {
+		wl  *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		wl     Workload
+		want   want
+	}{
+		"NotAnnotated": {
+			reason: "Pods should not be annotated unless a trait asks to",
+			wl: Workload{
+				Workload:    deployment(nil),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{metricsTrait(v1alpha2.MetricsTraitSpec{Port: intstr.FromInt(9090)})},
+			},
+			want: want{wl: deployment(nil)},
+		},
+		"NoPodTemplate": {
+			reason: "Pods should not be annotated if the pod spec is not that of a pod template",
+			wl: Workload{
+				Workload:    deployment(nil),
+				PodSpecPath: "spec.template.podSpec",
+				Traits:      []*Trait{metricsTrait(v1alpha2.MetricsTraitSpec{Port: intstr.FromInt(9090), AnnotatePods: true})},
+			},
+			want: want{wl: deployment(nil)},
+		},
+		"Annotated": {
+			reason: "The pod template should be annotated with the port, path and scheme of the trait",
+			wl: Workload{
+				Workload:    deployment(map[string]interface{}{"team": "web"}),
+				PodSpecPath: "spec.template.spec",
+				Traits: []*Trait{metricsTrait(v1alpha2.MetricsTraitSpec{
+					Port: intstr.FromInt(8080), Path: "/stats", Scheme: "https", AnnotatePods: true,
+				})},
+			},
+			want: want{wl: deployment(map[string]interface{}{
+				"team":                 "web",
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   "8080",
+				"prometheus.io/path":   "/stats",
+				"prometheus.io/scheme": "https",
+			})},
+		},
+		"NamedPort": {
+			reason: "Named ports should be resolved to the port of that name of the containers",
+			wl: Workload{
+				Workload:    deployment(nil),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{metricsTrait(v1alpha2.MetricsTraitSpec{Port: intstr.FromString("metrics"), AnnotatePods: true})},
+			},
+			want: want{wl: deployment(map[string]interface{}{
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   "9090",
+			})},
+		},
+		"UnknownNamedPort": {
+			reason: "An error should be returned if no container declares the named port",
+			wl: Workload{
+				Workload:    deployment(nil),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{metricsTrait(v1alpha2.MetricsTraitSpec{Port: intstr.FromString("admin"), AnnotatePods: true})},
+			},
+			want: want{
+				wl:  deployment(nil),
+				err: errors.Wrapf(errors.Errorf(errFmtNoNamedPort, "admin"), errFmtAnnotatePods, "web", "metrics"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := annotateScrapedPods(tc.wl)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nannotateScrapedPods(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wl, tc.wl.Workload); diff != "" {
+				t.Errorf("\n%s\nannotateScrapedPods(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	errFmtDecodePlacement = "cannot decode PlacementScope %q"
+	errFmtPlaceWorkload   = "cannot place workload %q in the zones of PlacementScope %q"
+)
+
+// placementOf returns the PlacementScope among the supplied scopes of a
+// workload, or nil if the workload is in none.
+func placementOf(scopes []unstructured.Unstructured) (*v1alpha2.PlacementScope, error) {
+	for i := range scopes {
+		if scopes[i].GroupVersionKind() != v1alpha2.PlacementScopeGroupVersionKind {
+			continue
+		}
+		ps := &v1alpha2.PlacementScope{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(scopes[i].Object, ps); err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodePlacement, scopes[i].GetName())
+		}
+		return ps, nil
+	}
+	return nil, nil
+}
+
+// placedIn returns true if the supplied placement applies workloads to the
+// named cluster, i.e. if it lists no clusters or lists the named one.
+func placedIn(p v1alpha2.PlacementScopeSpec, cluster string) bool {
+	if len(p.Clusters) == 0 {
+		return true
+	}
+	for _, c := range p.Clusters {
+		if c == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// place restricts the pods of the supplied workload to the zones of the
+// supplied placement, by setting the node affinity of the pod spec at the
+// supplied path. Zones of higher weight are preferred. The workload is left
+// as is if the placement lists no zones, or the path is empty.
+func place(wl *unstructured.Unstructured, podSpecPath string, p v1alpha2.PlacementScopeSpec) error {
+	if len(p.Zones) == 0 || podSpecPath == "" {
+		return nil
+	}
+	key := p.TopologyKey
+	if key == "" {
+		key = corev1.LabelZoneFailureDomainStable
+	}
+	zones := make([]string, len(p.Zones))
+	affinity := &corev1.NodeAffinity{}
+	for i, z := range p.Zones {
+		zones[i] = z.Name
+		if z.Weight <= 0 {
+			continue
+		}
+		affinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.PreferredSchedulingTerm{
+				Weight:     z.Weight,
+				Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zoneIn(key, z.Name)}},
+			})
+	}
+	affinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+		NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zoneIn(key, zones...)}}},
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(affinity)
+	if err != nil {
+		return err
+	}
+	podSpec, err := util.PavePodSpec(wl, podSpecPath)
+	if err != nil {
+		return err
+	}
+	// the affinity is set as is, setting it through the paved pod spec
+	// round-trips it through JSON and turns its weights into floats
+	return unstructured.SetNestedField(podSpec.UnstructuredContent(), u, "affinity", "nodeAffinity")
+}
+
+func zoneIn(key string, zones ...string) corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{Key: key, Operator: corev1.NodeSelectorOpIn, Values: zones}
+}
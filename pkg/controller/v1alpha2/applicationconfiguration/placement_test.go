@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestPlacementOf(t *testing.T) {
+	placement := &v1alpha2.PlacementScope{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.PlacementScopeKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "east"},
+		Spec:       v1alpha2.PlacementScopeSpec{Clusters: []string{"east"}},
+	}
+	placementScope, _ := util.Object2Unstructured(placement)
+	healthScope, _ := util.Object2Unstructured(&v1alpha2.HealthScope{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.HealthScopeKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "health"},
+	})
+
+	type want struct {
+		placement *v1alpha2.PlacementScope
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		scopes []unstructured.Unstructured
+		want   want
+	}{
+		"NoPlacementScope": {
+			reason: "Workloads in no PlacementScope should have no placement",
+			scopes: []unstructured.Unstructured{*healthScope},
+		},
+		"PlacementScope": {
+			reason: "The PlacementScope among the scopes of a workload should be returned",
+			scopes: []unstructured.Unstructured{*healthScope, *placementScope},
+			want:   want{placement: placement},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := placementOf(tc.scopes)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nplacementOf(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.placement, got); diff != "" {
+				t.Errorf("\n%s\nplacementOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPlacedIn(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		placement v1alpha2.PlacementScopeSpec
+		cluster   string
+		want      bool
+	}{
+		"NoClusters": {
+			reason:  "Placements without clusters should apply workloads to every cluster",
+			cluster: "east",
+			want:    true,
+		},
+		"Listed": {
+			reason:    "Placements should apply workloads to the clusters they list",
+			placement: v1alpha2.PlacementScopeSpec{Clusters: []string{"west", "east"}},
+			cluster:   "east",
+			want:      true,
+		},
+		"NotListed": {
+			reason:    "Placements should not apply workloads to clusters they don't list",
+			placement: v1alpha2.PlacementScopeSpec{Clusters: []string{"west"}},
+			cluster:   "east",
+			want:      false,
+		},
+		"Unnamed": {
+			reason:    "Placements that list clusters should not apply workloads to an unnamed cluster",
+			placement: v1alpha2.PlacementScopeSpec{Clusters: []string{"west"}},
+			want:      false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := placedIn(tc.placement, tc.cluster); got != tc.want {
+				t.Errorf("\n%s\nplacedIn(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPlace(t *testing.T) {
+	deployment := func(podSpec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{"spec": podSpec},
+			},
+		}}
+	}
+	zoneIn := func(key string, zones ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"matchExpressions": []interface{}{map[string]interface{}{"key": key, "operator": "In", "values": zones}},
+		}
+	}
+
+	type args struct {
+		wl          *unstructured.Unstructured
+		podSpecPath string
+		placement   v1alpha2.PlacementScopeSpec
+	}
+	type want struct {
+		wl  *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoZones": {
+			reason: "Workloads should be left as is if the placement lists no zones",
+			args: args{
+				wl:          deployment(map[string]interface{}{}),
+				podSpecPath: "spec.template.spec",
+				placement:   v1alpha2.PlacementScopeSpec{Clusters: []string{"east"}},
+			},
+			want: want{wl: deployment(map[string]interface{}{})},
+		},
+		"NoPodSpecPath": {
+			reason: "Workloads should be left as is if their pod spec can't be determined",
+			args: args{
+				wl:        deployment(map[string]interface{}{}),
+				placement: v1alpha2.PlacementScopeSpec{Zones: []v1alpha2.PlacementZone{{Name: "zone-a"}}},
+			},
+			want: want{wl: deployment(map[string]interface{}{})},
+		},
+		"Zones": {
+			reason: "Pods should be restricted to the zones of the placement, preferring zones of higher weight",
+			args: args{
+				wl:          deployment(map[string]interface{}{"affinity": map[string]interface{}{}}),
+				podSpecPath: "spec.template.spec",
+				placement: v1alpha2.PlacementScopeSpec{Zones: []v1alpha2.PlacementZone{
+					{Name: "zone-a", Weight: 80}, {Name: "zone-b"},
+				}},
+			},
+			want: want{wl: deployment(map[string]interface{}{"affinity": map[string]interface{}{
+				"nodeAffinity": map[string]interface{}{
+					"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+						"nodeSelectorTerms": []interface{}{zoneIn("topology.kubernetes.io/zone", "zone-a", "zone-b")},
+					},
+					"preferredDuringSchedulingIgnoredDuringExecution": []interface{}{
+						map[string]interface{}{"weight": int64(80), "preference": zoneIn("topology.kubernetes.io/zone", "zone-a")},
+					},
+				},
+			}})},
+		},
+		"TopologyKey": {
+			reason: "Zones should be told apart by the topology key of the placement",
+			args: args{
+				wl:          deployment(map[string]interface{}{}),
+				podSpecPath: "spec.template.spec",
+				placement: v1alpha2.PlacementScopeSpec{
+					TopologyKey: "example.com/rack",
+					Zones:       []v1alpha2.PlacementZone{{Name: "rack-1"}},
+				},
+			},
+			want: want{wl: deployment(map[string]interface{}{"affinity": map[string]interface{}{
+				"nodeAffinity": map[string]interface{}{
+					"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+						"nodeSelectorTerms": []interface{}{zoneIn("example.com/rack", "rack-1")},
+					},
+				},
+			}})},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := place(tc.args.wl, tc.args.podSpecPath, tc.args.placement)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nplace(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wl, tc.args.wl); diff != "" {
+				t.Errorf("\n%s\nplace(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
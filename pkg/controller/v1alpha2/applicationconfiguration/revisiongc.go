@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"sort"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Revision garbage collector error strings.
+const (
+	errGetComponent          = "cannot get component"
+	errListRevisions         = "cannot list component revisions"
+	errFmtListRevisionUsers  = "cannot list application configurations that use component revision %q"
+	errFmtCleanupRevision    = "cannot clean up workload of component revision %q"
+	errFmtDeleteRevision     = "cannot delete component revision %q"
+	revisionGCControllerName = "oam/revisiongc"
+)
+
+// SetupRevisionGC adds a controller that garbage collects the revisions of
+// Components that are no longer used by any ApplicationConfiguration.
+func SetupRevisionGC(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(revisionGCControllerName).
+		For(&v1alpha2.Component{}).
+		Watches(&source.Kind{Type: &v1alpha2.ApplicationConfiguration{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(appConfigComponents),
+		}).
+		Complete(&RevisionGarbageCollector{
+			client: mgr.GetClient(),
+			log:    l.WithValues("controller", revisionGCControllerName),
+			components: &ComponentHandler{
+				Client:        mgr.GetClient(),
+				Logger:        l.WithValues("controller", revisionGCControllerName),
+				RevisionLimit: args.RevisionLimit,
+			},
+		})
+}
+
+// appConfigComponents returns a request for each Component that the supplied
+// ApplicationConfiguration uses or used, so that revisions it stops using are
+// garbage collected.
+func appConfigComponents(o handler.MapObject) []reconcile.Request {
+	ac, ok := o.Object.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return nil
+	}
+	names := map[string]bool{}
+	for _, acc := range ac.Spec.Components {
+		if acc.RevisionName != "" {
+			names[ExtractComponentName(acc.RevisionName)] = true
+			continue
+		}
+		names[acc.ComponentName] = true
+	}
+	for _, w := range ac.Status.Workloads {
+		names[w.ComponentName] = true
+	}
+	reqs := make([]reconcile.Request, 0, len(names))
+	for name := range names {
+		if name == "" {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ac.GetNamespace(), Name: name}})
+	}
+	return reqs
+}
+
+// A RevisionGarbageCollector deletes the revisions of a Component, and the
+// workloads named after them, once they fall outside the revision history
+// limit of the Component and no ApplicationConfiguration uses them.
+type RevisionGarbageCollector struct {
+	client     client.Client
+	log        logging.Logger
+	components *ComponentHandler
+}
+
+// Reconcile the revisions of a Component.
+func (r *RevisionGarbageCollector) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	comp := &v1alpha2.Component{}
+	if err := r.client.Get(ctx, req.NamespacedName, comp); err != nil {
+		// the revisions of a deleted component are garbage collected by
+		// their owner references
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetComponent)
+	}
+
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := r.client.List(ctx, revisions, client.InNamespace(comp.GetNamespace()),
+		client.MatchingLabels{ControllerRevisionComponentLabel: comp.GetName()}); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errListRevisions)
+	}
+	for _, rev := range expiredRevisions(comp, revisions.Items, r.components.revisionLimit(comp)) {
+		rev := rev
+		acs := &v1alpha2.ApplicationConfigurationList{}
+		if err := r.client.List(ctx, acs, client.InNamespace(rev.GetNamespace()),
+			client.MatchingFields{util.ConsumedRevisionNameIndex: rev.GetName()}); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, errFmtListRevisionUsers, rev.GetName())
+		}
+		if len(acs.Items) > 0 {
+			continue
+		}
+		if err := r.components.cleanupRevisionWorkload(&rev); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, errFmtCleanupRevision, rev.GetName())
+		}
+		if err := r.client.Delete(ctx, &rev); client.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, errors.Wrapf(err, errFmtDeleteRevision, rev.GetName())
+		}
+		r.log.Debug("Garbage collected component revision", "component", comp.GetName(), "revision", rev.GetName())
+	}
+	return reconcile.Result{}, nil
+}
+
+// expiredRevisions returns the supplied revisions of a component that fall
+// outside its revision history limit, oldest first. The latest revision of
+// the component never expires.
+func expiredRevisions(comp *v1alpha2.Component, revisions []appsv1.ControllerRevision, limit int) []appsv1.ControllerRevision {
+	sorted := append([]appsv1.ControllerRevision{}, revisions...)
+	sort.Sort(historiesByRevision(sorted))
+	if len(sorted) <= limit {
+		return nil
+	}
+	expired := make([]appsv1.ControllerRevision, 0, len(sorted)-limit)
+	for _, rev := range sorted[:len(sorted)-limit] {
+		if comp.Status.LatestRevision != nil && rev.GetName() == comp.Status.LatestRevision.Name {
+			continue
+		}
+		expired = append(expired, rev)
+	}
+	return expired
+}
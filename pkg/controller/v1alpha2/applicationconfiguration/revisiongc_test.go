@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestExpiredRevisions(t *testing.T) {
+	revision := func(name string, revision int64) appsv1.ControllerRevision {
+		return appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Name: name}, Revision: revision}
+	}
+	revisions := []appsv1.ControllerRevision{revision("comp-v3", 3), revision("comp-v1", 1), revision("comp-v2", 2)}
+	comp := &v1alpha2.Component{ObjectMeta: metav1.ObjectMeta{Name: "comp"}}
+
+	assert.Empty(t, expiredRevisions(comp, revisions, 3), "Revisions within the limit should not expire")
+	assert.Equal(t, []appsv1.ControllerRevision{revision("comp-v1", 1), revision("comp-v2", 2)},
+		expiredRevisions(comp, revisions, 1), "The oldest revisions outside the limit should expire first")
+
+	comp.Status.LatestRevision = &v1alpha2.Revision{Name: "comp-v3", Revision: 3}
+	assert.Equal(t, []appsv1.ControllerRevision{revision("comp-v1", 1), revision("comp-v2", 2)},
+		expiredRevisions(comp, revisions, 0), "The latest revision should never expire")
+}
+
+func TestAppConfigComponents(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "biz"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{
+				{ComponentName: "comp1"},
+				{RevisionName: "comp-two-v2"},
+			},
+		},
+		Status: v1alpha2.ApplicationConfigurationStatus{
+			Workloads: []v1alpha2.WorkloadStatus{{ComponentName: "comp1"}, {ComponentName: "comp3"}},
+		},
+	}
+	reqs := appConfigComponents(handler.MapObject{Meta: ac, Object: ac})
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "comp1"}},
+		{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "comp-two"}},
+		{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "comp3"}},
+	}, reqs, "Every component the application configuration uses or used should be enqueued")
+
+	comp := &v1alpha2.Component{}
+	assert.Nil(t, appConfigComponents(handler.MapObject{Meta: comp, Object: comp}))
+}
+
+func TestRevisionGarbageCollector(t *testing.T) {
+	errBoom := errors.New("boom")
+	limit := int32(1)
+	comp := v1alpha2.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "comp", Namespace: "biz"},
+		Spec:       v1alpha2.ComponentSpec{RevisionHistoryLimit: &limit},
+		Status:     v1alpha2.ComponentStatus{LatestRevision: &v1alpha2.Revision{Name: "comp-v3", Revision: 3}},
+	}
+	revision := func(name string, revision int64) appsv1.ControllerRevision {
+		return appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "biz"},
+			Revision:   revision,
+			Data:       runtime.RawExtension{Object: &v1alpha2.Component{}},
+		}
+	}
+	getComp := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		comp.DeepCopyInto(obj.(*v1alpha2.Component))
+		return nil
+	})
+	list := func(acErr error) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *appsv1.ControllerRevisionList:
+				l.Items = []appsv1.ControllerRevision{revision("comp-v1", 1), revision("comp-v2", 2), revision("comp-v3", 3)}
+			case *v1alpha2.ApplicationConfigurationList:
+				if acErr != nil {
+					return acErr
+				}
+				o := &client.ListOptions{}
+				o.ApplyOptions(opts)
+				// comp-v1 is still used by an application configuration
+				if o.FieldSelector.Matches(fields.Set{util.ConsumedRevisionNameIndex: "comp-v1"}) {
+					l.Items = []v1alpha2.ApplicationConfiguration{{}}
+				}
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		get     test.MockGetFn
+		list    test.MockListFn
+		want    error
+		deleted []string
+	}{
+		"ComponentNotFound": {
+			reason: "The revisions of a deleted component should be left to their owner references",
+			get:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "comp")),
+		},
+		"GetComponentError": {
+			reason: "Errors getting the component should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			want:   errors.Wrap(errBoom, errGetComponent),
+		},
+		"ListAppConfigsError": {
+			reason: "Errors listing the users of a revision should be returned",
+			get:    getComp,
+			list:   list(errBoom),
+			want:   errors.Wrapf(errBoom, errFmtListRevisionUsers, "comp-v1"),
+		},
+		"Success": {
+			reason:  "Expired revisions that are no longer used should be deleted",
+			get:     getComp,
+			list:    list(nil),
+			deleted: []string{"comp-v2"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			c := &test.MockClient{
+				MockGet:  tc.get,
+				MockList: tc.list,
+				MockDelete: test.NewMockDeleteFn(nil, func(obj runtime.Object) error {
+					deleted = append(deleted, obj.(*appsv1.ControllerRevision).GetName())
+					return nil
+				}),
+			}
+			r := &RevisionGarbageCollector{
+				client:     c,
+				log:        logging.NewNopLogger(),
+				components: &ComponentHandler{Client: c, Logger: logging.NewNopLogger()},
+			}
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "comp"}})
+			if tc.want == nil {
+				assert.NoError(t, err, tc.reason)
+			} else {
+				assert.EqualError(t, err, tc.want.Error(), tc.reason)
+			}
+			assert.Equal(t, tc.deleted, deleted, tc.reason)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	errFmtGetScopeStatus    = "cannot get %s %q to read its status"
+	errFmtDecodeScopeStatus = "cannot decode the status of %s %q"
+)
+
+// scopeStatus is the part of the status of a scope that is rolled up into
+// the status of ApplicationConfigurations. Scopes report their workloads in
+// status.healthConditions, like HealthScopes, or status.workloads, like
+// NetworkScopes and ResourceQuotaScopes.
+type scopeStatus struct {
+	runtimev1alpha1.ConditionedStatus `json:",inline"`
+
+	HealthConditions []scopeWorkloadStatus `json:"healthConditions,omitempty"`
+	Workloads        []scopeWorkloadStatus `json:"workloads,omitempty"`
+}
+
+// scopeWorkloadStatus is the status a scope reports for one of its workloads.
+type scopeWorkloadStatus struct {
+	TargetWorkload runtimev1alpha1.TypedReference `json:"targetWorkload"`
+	HealthStatus   v1alpha2.HealthStatus          `json:"healthStatus,omitempty"`
+	Diagnosis      string                         `json:"diagnosis,omitempty"`
+}
+
+// updateScopeStatus reads the status of the scopes of the supplied workloads,
+// and records their conditions, and the health and diagnosis they report for
+// the workloads, in the supplied statuses. Scopes that don't exist are
+// skipped.
+func updateScopeStatus(ctx context.Context, c client.Reader, status []v1alpha2.WorkloadStatus, workloads []Workload) error {
+	for i, w := range workloads {
+		for j := range w.Scopes {
+			s, err := readScopeStatus(ctx, c, &w.Scopes[j])
+			if err != nil {
+				return err
+			}
+			if s == nil {
+				continue
+			}
+			ws := &status[i].Scopes[j]
+			ws.Conditions = s.Conditions
+			for _, sw := range append(s.HealthConditions, s.Workloads...) {
+				if sameWorkload(sw.TargetWorkload, w.Workload) {
+					ws.HealthStatus, ws.Diagnosis = sw.HealthStatus, sw.Diagnosis
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// readScopeStatus returns the status of the applied counterpart of the
+// supplied scope, or nil if it doesn't exist.
+func readScopeStatus(ctx context.Context, c client.Reader, scope *unstructured.Unstructured) (*scopeStatus, error) {
+	applied, err := getApplied(ctx, c, scope)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetScopeStatus, scope.GetKind(), scope.GetName())
+	}
+	s := &scopeStatus{}
+	st, found, _ := unstructured.NestedMap(applied.Object, "status")
+	if !found {
+		return s, nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(st, s); err != nil {
+		return nil, errors.Wrapf(err, errFmtDecodeScopeStatus, scope.GetKind(), scope.GetName())
+	}
+	return s, nil
+}
+
+// sameWorkload returns true if the supplied reference refers to the supplied
+// workload, regardless of its API version.
+func sameWorkload(ref runtimev1alpha1.TypedReference, wl *unstructured.Unstructured) bool {
+	return ref.GroupVersionKind().GroupKind() == wl.GroupVersionKind().GroupKind() && ref.Name == wl.GetName()
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestUpdateScopeStatus(t *testing.T) {
+	errBoom := errors.New("boom")
+	object := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		u.SetNamespace("ns")
+		u.SetName(name)
+		return u
+	}
+	web := object("apps/v1", "Deployment", "web")
+	health := object("core.oam.dev/v1alpha2", "HealthScope", "health")
+	network := object("core.oam.dev/v1alpha2", "NetworkScope", "network")
+	missing := object("core.oam.dev/v1alpha2", "NetworkScope", "missing")
+
+	synced := map[string]interface{}{"type": "Synced", "status": "True", "reason": "ReconcileSuccess"}
+	// the HealthScope reports web as unhealthy, the NetworkScope can't
+	// determine the pods of web, and the scope named "missing" doesn't exist
+	get := func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		switch key.Name {
+		case "health":
+			u.Object["status"] = map[string]interface{}{
+				"conditions": []interface{}{synced},
+				"healthConditions": []interface{}{
+					map[string]interface{}{
+						"targetWorkload": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "db"},
+						"healthStatus":   "HEALTHY",
+					},
+					map[string]interface{}{
+						"targetWorkload": map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+						"healthStatus":   "UNHEALTHY",
+						"diagnosis":      "0/1 replicas are ready",
+					},
+				},
+			}
+		case "network":
+			u.Object["status"] = map[string]interface{}{
+				"workloads": []interface{}{
+					map[string]interface{}{
+						"targetWorkload": map[string]interface{}{"apiVersion": "apps/v1beta1", "kind": "Deployment", "name": "web"},
+						"diagnosis":      `cannot determine the pods of Deployment "web"`,
+					},
+				},
+			}
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		return nil
+	}
+
+	type want struct {
+		err    error
+		scopes []v1alpha2.WorkloadScope
+	}
+
+	cases := map[string]struct {
+		reason string
+		get    test.MockGetFn
+		scopes []*unstructured.Unstructured
+		want   want
+	}{
+		"ScopeStatus": {
+			reason: "The conditions of the scopes, and the health and diagnosis they report for the workload should be recorded",
+			get:    get,
+			scopes: []*unstructured.Unstructured{health, network, missing},
+			want: want{scopes: []v1alpha2.WorkloadScope{
+				{
+					Conditions:   []runtimev1alpha1.Condition{{Type: "Synced", Status: "True", Reason: "ReconcileSuccess"}},
+					HealthStatus: v1alpha2.StatusUnhealthy,
+					Diagnosis:    "0/1 replicas are ready",
+				},
+				{Diagnosis: `cannot determine the pods of Deployment "web"`},
+				{},
+			}},
+		},
+		"GetScopeError": {
+			reason: "Errors getting a scope should be returned",
+			get:    test.NewMockGetFn(errBoom),
+			scopes: []*unstructured.Unstructured{health},
+			want: want{
+				err:    errors.Wrapf(errBoom, errFmtGetScopeStatus, "HealthScope", "health"),
+				scopes: []v1alpha2.WorkloadScope{{}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := Workload{Workload: web}
+			for _, s := range tc.scopes {
+				w.Scopes = append(w.Scopes, *s)
+			}
+			status := []v1alpha2.WorkloadStatus{{Scopes: make([]v1alpha2.WorkloadScope, len(w.Scopes))}}
+			err := updateScopeStatus(context.Background(), &test.MockClient{MockGet: tc.get}, status, []Workload{w})
+			if diff := cmp.Diff(tc.want, want{err: err, scopes: status[0].Scopes}, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nupdateScopeStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
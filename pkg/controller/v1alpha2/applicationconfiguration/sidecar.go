@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	errFmtDecodeSidecarTrait = "cannot decode SidecarTrait %q"
+	errFmtInjectSidecar      = "cannot inject the sidecar of SidecarTrait %q into workload %q"
+)
+
+// injectSidecars injects the sidecar container of each SidecarTrait of the
+// supplied workload into the pod spec at its podSpecPath. Workloads are
+// rendered from their components before their sidecars are injected, so
+// sidecars are injected anew each time, and removed once their trait is
+// removed from the ApplicationConfiguration. The workload is left as is if
+// its path is empty; the SidecarTraits report that they cannot be injected.
+func injectSidecars(wl Workload) error {
+	if wl.PodSpecPath == "" {
+		return nil
+	}
+	for _, t := range podTraitsOf(wl, v1alpha2.SidecarTraitGroupVersionKind) {
+		st := &v1alpha2.SidecarTrait{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(t.Object, st); err != nil {
+			return errors.Wrapf(err, errFmtDecodeSidecarTrait, t.GetName())
+		}
+		podSpec, err := util.PavePodSpec(wl.Workload, wl.PodSpecPath)
+		if err == nil {
+			err = injectSidecar(podSpec, st)
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtInjectSidecar, st.GetName(), wl.Workload.GetName())
+		}
+	}
+	return nil
+}
+
+// injectSidecar adds the sidecar container of the supplied trait to the
+// supplied pod spec, replacing the container of the same name, and adds an
+// emptyDir volume for each volume the sidecar mounts that the pod spec lacks.
+func injectSidecar(podSpec *fieldpath.Paved, st *v1alpha2.SidecarTrait) error {
+	volumes, err := namedList(podSpec, "volumes")
+	if err != nil {
+		return err
+	}
+	containers, err := namedList(podSpec, "containers")
+	if err != nil {
+		return err
+	}
+	for _, m := range st.Spec.VolumeMounts {
+		if hasNamed(volumes, m.Name) {
+			continue
+		}
+		volume, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.Volume{
+			Name:         m.Name,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		if err != nil {
+			return err
+		}
+		volumes = append(volumes, volume)
+	}
+	sidecar, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sidecarContainer(st))
+	if err != nil {
+		return err
+	}
+	if len(volumes) > 0 {
+		if err := podSpec.SetValue("volumes", volumes); err != nil {
+			return err
+		}
+	}
+	return podSpec.SetValue("containers", setNamed(containers, sidecar))
+}
+
+// sidecarContainer returns the sidecar container of the supplied trait, which
+// is named after the trait unless the trait names it.
+func sidecarContainer(st *v1alpha2.SidecarTrait) *corev1.Container {
+	c := &corev1.Container{
+		Name:    st.Spec.Name,
+		Image:   st.Spec.Image,
+		Command: st.Spec.Command,
+		Args:    st.Spec.Arguments,
+		Env:     containerizedworkload.EnvVars(st.Spec.Environment),
+		EnvFrom: containerizedworkload.EnvFromSources(st.Spec.EnvFrom),
+	}
+	if c.Name == "" {
+		c.Name = st.GetName()
+	}
+	if st.Spec.ImagePullPolicy != nil {
+		c.ImagePullPolicy = corev1.PullPolicy(*st.Spec.ImagePullPolicy)
+	}
+	for _, m := range st.Spec.VolumeMounts {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      m.Name,
+			MountPath: m.MountPath,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+	return c
+}
+
+// hasNamed returns true if the supplied list has an object of the supplied
+// name.
+func hasNamed(l []interface{}, name string) bool {
+	for i := range l {
+		if o, ok := l[i].(map[string]interface{}); ok && o["name"] == name {
+			return true
+		}
+	}
+	return false
+}
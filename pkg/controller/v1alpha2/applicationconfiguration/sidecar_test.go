@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestInjectSidecars(t *testing.T) {
+	level := "debug"
+	st, _ := util.Object2Unstructured(&v1alpha2.SidecarTrait{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.SidecarTraitKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "proxy"},
+		Spec: v1alpha2.SidecarTraitSpec{
+			Image:       "cool/proxy:v1",
+			Arguments:   []string{"--upstream=localhost:8080"},
+			Environment: []v1alpha2.ContainerEnvVar{{Name: "LEVEL", Value: &level}},
+			VolumeMounts: []v1alpha2.SidecarVolumeMount{
+				{Name: "cache", MountPath: "/cache", ReadOnly: true},
+				{Name: "sockets", MountPath: "/var/run"},
+			},
+		},
+	})
+	deployment := func(volumes []interface{}, containers ...interface{}) *unstructured.Unstructured {
+		podSpec := map[string]interface{}{"containers": containers}
+		if volumes != nil {
+			podSpec["volumes"] = volumes
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{"spec": podSpec},
+			},
+		}}
+	}
+	container := func(name string) map[string]interface{} {
+		return map[string]interface{}{"name": name, "image": "cool/" + name}
+	}
+	sidecar, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.Container{
+		Name:  "proxy",
+		Image: "cool/proxy:v1",
+		Args:  []string{"--upstream=localhost:8080"},
+		Env:   []corev1.EnvVar{{Name: "LEVEL", Value: level}},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "cache", MountPath: "/cache", ReadOnly: true},
+			{Name: "sockets", MountPath: "/var/run"},
+		},
+	})
+	cache := map[string]interface{}{"name": "cache", "persistentVolumeClaim": map[string]interface{}{"claimName": "cache"}}
+	sockets := map[string]interface{}{"name": "sockets", "emptyDir": map[string]interface{}{}}
+
+	cases := map[string]struct {
+		reason string
+		wl     Workload
+		want   *unstructured.Unstructured
+	}{
+		"NoPodSpecPath": {
+			reason: "Workloads should be left as is if their pod spec can't be determined",
+			wl: Workload{
+				Workload: deployment(nil, container("web")),
+				Traits:   []*Trait{{Object: *st}},
+			},
+			want: deployment(nil, container("web")),
+		},
+		"SkippedTraits": {
+			reason: "The sidecars of traits that are not ready, or apply to auxiliary workloads, should not be injected",
+			wl: Workload{
+				Workload:    deployment(nil, container("web")),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{{Object: *st, HasDep: true}, {Object: *st, WorkloadName: "cache"}},
+			},
+			want: deployment(nil, container("web")),
+		},
+		"Inject": {
+			reason: "The sidecar should be added to the containers, and emptyDir volumes added for the volumes it mounts that the pod lacks",
+			wl: Workload{
+				Workload:    deployment([]interface{}{cache}, container("web")),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{{Object: *st}},
+			},
+			want: deployment([]interface{}{cache, sockets}, container("web"), sidecar),
+		},
+		"Reinject": {
+			reason: "A container of the name of the sidecar should be replaced rather than duplicated",
+			wl: Workload{
+				Workload:    deployment([]interface{}{cache, sockets}, container("web"), container("proxy")),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{{Object: *st}},
+			},
+			want: deployment([]interface{}{cache, sockets}, container("web"), sidecar),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := injectSidecars(tc.wl)
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninjectSidecars(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.wl.Workload); diff != "" {
+				t.Errorf("\n%s\ninjectSidecars(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	errFmtGetStatusResource  = "cannot get %s %q to extract its status fields"
+	errFmtGetStatusField     = "cannot get status field %q of %s %q"
+	errFmtParseStatusMessage = "cannot parse the status message of %s %q"
+	errFmtRenderStatusMsg    = "cannot render the status message of %s %q"
+)
+
+// updateStatusFields extracts the status fields of the definitions of the
+// supplied workloads and their traits from the applied resources, and records
+// them and the status messages rendered from them in the supplied statuses.
+// Resources that were not applied yet are skipped.
+func updateStatusFields(ctx context.Context, c client.Reader, status []v1alpha2.WorkloadStatus, workloads []Workload) error {
+	for i, w := range workloads {
+		fields, msg, err := extractStatus(ctx, c, w.StatusFields, w.StatusMessage, w.Workload)
+		if err != nil {
+			return err
+		}
+		status[i].StatusFields, status[i].StatusMessage = fields, msg
+		for j, t := range w.Traits {
+			fields, msg, err := extractStatus(ctx, c, t.Definition.Spec.StatusFields, t.Definition.Spec.StatusMessage, &t.Object)
+			if err != nil {
+				return err
+			}
+			status[i].Traits[j].StatusFields, status[i].Traits[j].StatusMessage = fields, msg
+		}
+	}
+	return nil
+}
+
+// extractStatus extracts the supplied status fields from the applied
+// counterpart of the supplied rendered resource, and renders the supplied
+// status message template from them.
+func extractStatus(ctx context.Context, c client.Reader, sf []v1alpha2.StatusField, msg string, rendered *unstructured.Unstructured) (map[string]string, string, error) {
+	if len(sf) == 0 && msg == "" {
+		return nil, "", nil
+	}
+	applied, err := getApplied(ctx, c, rendered)
+	if kerrors.IsNotFound(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", errors.Wrapf(err, errFmtGetStatusResource, rendered.GetKind(), rendered.GetName())
+	}
+
+	p := fieldpath.Pave(applied.UnstructuredContent())
+	var fields map[string]string
+	for _, f := range sf {
+		v, err := p.GetValue(f.FieldPath)
+		if fieldpath.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", errors.Wrapf(err, errFmtGetStatusField, f.Name, rendered.GetKind(), rendered.GetName())
+		}
+		s, err := statusFieldString(v)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, errFmtGetStatusField, f.Name, rendered.GetKind(), rendered.GetName())
+		}
+		if fields == nil {
+			fields = make(map[string]string, len(sf))
+		}
+		fields[f.Name] = s
+	}
+	if msg == "" {
+		return fields, "", nil
+	}
+
+	// missing fields render as empty strings, rather than as "<no value>"
+	tmpl, err := template.New("status").Option("missingkey=zero").Parse(msg)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, errFmtParseStatusMessage, rendered.GetKind(), rendered.GetName())
+	}
+	data := make(map[string]string, len(sf))
+	for _, f := range sf {
+		data[f.Name] = fields[f.Name]
+	}
+	b := &bytes.Buffer{}
+	if err := tmpl.Execute(b, data); err != nil {
+		return nil, "", errors.Wrapf(err, errFmtRenderStatusMsg, rendered.GetKind(), rendered.GetName())
+	}
+	return fields, b.String(), nil
+}
+
+// statusFieldString returns strings as they are, other scalars as they are
+// printed, and objects and arrays as JSON. Null values are empty.
+func statusFieldString(v interface{}) (string, error) {
+	switch tv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return tv, nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(tv)
+		return string(b), err
+	default:
+		return fmt.Sprint(tv), nil
+	}
+}
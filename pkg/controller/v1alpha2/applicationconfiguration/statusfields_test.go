@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestUpdateStatusFields(t *testing.T) {
+	errBoom := errors.New("boom")
+	object := func(kind, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("example.com/v1")
+		u.SetKind(kind)
+		u.SetNamespace("ns")
+		u.SetName(name)
+		return u
+	}
+	// the object named "missing" doesn't exist, and getting the object named
+	// "broken" fails
+	c := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		switch key.Name {
+		case "missing":
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		case "broken":
+			return errBoom
+		}
+		obj.(*unstructured.Unstructured).Object["status"] = map[string]interface{}{
+			"replicas":      int64(3),
+			"readyReplicas": int64(2),
+			"ingress":       []interface{}{map[string]interface{}{"ip": "10.0.0.1"}},
+		}
+		return nil
+	}}
+	replicas := []v1alpha2.StatusField{
+		{Name: "ready", FieldPath: "status.readyReplicas"},
+		{Name: "replicas", FieldPath: "status.replicas"},
+	}
+	trait := func(name string, sf []v1alpha2.StatusField, msg string) *Trait {
+		tr := &Trait{Object: *object("Route", name)}
+		tr.Definition.Spec.StatusFields = sf
+		tr.Definition.Spec.StatusMessage = msg
+		return tr
+	}
+
+	type want struct {
+		err    error
+		status []v1alpha2.WorkloadStatus
+	}
+
+	cases := map[string]struct {
+		reason    string
+		workloads []Workload
+		want      want
+	}{
+		"NoStatusFields": {
+			reason:    "Nothing is extracted from resources whose definitions have no status fields",
+			workloads: []Workload{{Workload: object("Server", "web"), Traits: []*Trait{trait("route", nil, "")}}},
+			want: want{
+				status: []v1alpha2.WorkloadStatus{{Traits: []v1alpha2.WorkloadTrait{{}}}},
+			},
+		},
+		"Extracted": {
+			reason: "Status fields and messages should be extracted from workloads and traits",
+			workloads: []Workload{{
+				Workload:      object("Server", "web"),
+				StatusFields:  replicas,
+				StatusMessage: "{{.ready}}/{{.replicas}} replicas ready",
+				Traits: []*Trait{trait("route", []v1alpha2.StatusField{
+					{Name: "ingress", FieldPath: "status.ingress"},
+					{Name: "ip", FieldPath: "status.ingress[0].ip"},
+					{Name: "host", FieldPath: "status.host"},
+				}, "reachable at {{.ip}}{{.host}}")},
+			}},
+			want: want{
+				status: []v1alpha2.WorkloadStatus{{
+					StatusFields:  map[string]string{"ready": "2", "replicas": "3"},
+					StatusMessage: "2/3 replicas ready",
+					Traits: []v1alpha2.WorkloadTrait{{
+						StatusFields:  map[string]string{"ingress": `[{"ip":"10.0.0.1"}]`, "ip": "10.0.0.1"},
+						StatusMessage: "reachable at 10.0.0.1",
+					}},
+				}},
+			},
+		},
+		"NotApplied": {
+			reason:    "Resources that were not applied yet should be skipped",
+			workloads: []Workload{{Workload: object("Server", "missing"), StatusFields: replicas}},
+			want: want{
+				status: []v1alpha2.WorkloadStatus{{}},
+			},
+		},
+		"GetError": {
+			reason:    "Errors getting the applied resources should be returned",
+			workloads: []Workload{{Workload: object("Server", "broken"), StatusFields: replicas}},
+			want: want{
+				err:    errors.Wrapf(errBoom, errFmtGetStatusResource, "Server", "broken"),
+				status: []v1alpha2.WorkloadStatus{{}},
+			},
+		},
+		"MessageParseError": {
+			reason:    "Errors parsing the status message should be returned",
+			workloads: []Workload{{Workload: object("Server", "web"), StatusFields: replicas, StatusMessage: "{{.ready"}},
+			want: want{
+				err:    errors.Wrapf(errors.New(`template: status:1: unclosed action`), errFmtParseStatusMessage, "Server", "web"),
+				status: []v1alpha2.WorkloadStatus{{}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status := make([]v1alpha2.WorkloadStatus, len(tc.workloads))
+			for i, w := range tc.workloads {
+				if len(w.Traits) > 0 {
+					status[i].Traits = make([]v1alpha2.WorkloadTrait, len(w.Traits))
+				}
+			}
+			err := updateStatusFields(context.Background(), c, status, tc.workloads)
+			if diff := cmp.Diff(tc.want, want{err: err, status: status}, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nupdateStatusFields(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
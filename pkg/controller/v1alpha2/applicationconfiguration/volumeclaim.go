@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	errFmtDecodeVolumeClaimTrait = "cannot decode VolumeClaimTrait %q"
+	errFmtMountVolumeClaims      = "cannot mount the volume claims of VolumeClaimTrait %q into workload %q"
+)
+
+// mountVolumeClaims mounts the claims of the VolumeClaimTraits of the
+// supplied workload into the pod spec at its podSpecPath. The claims of
+// traits that are not ready to be applied are not mounted, nor are those of
+// traits of auxiliary workloads. The workload is left as is if its path is
+// empty; the VolumeClaimTraits report that they cannot be mounted.
+func mountVolumeClaims(wl Workload) error {
+	if wl.PodSpecPath == "" {
+		return nil
+	}
+	for _, t := range podTraitsOf(wl, v1alpha2.VolumeClaimTraitGroupVersionKind) {
+		vct := &v1alpha2.VolumeClaimTrait{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(t.Object, vct); err != nil {
+			return errors.Wrapf(err, errFmtDecodeVolumeClaimTrait, t.GetName())
+		}
+		podSpec, err := util.PavePodSpec(wl.Workload, wl.PodSpecPath)
+		if err == nil {
+			err = mountClaims(podSpec, vct)
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtMountVolumeClaims, vct.GetName(), wl.Workload.GetName())
+		}
+	}
+	return nil
+}
+
+// podTraitsOf returns the traits of the supplied kind that modify the pod
+// spec of the supplied workload. Traits that are not ready to be applied are
+// left out, as are those of auxiliary workloads.
+func podTraitsOf(wl Workload, gvk schema.GroupVersionKind) []unstructured.Unstructured {
+	var traits []unstructured.Unstructured
+	for _, trait := range wl.Traits {
+		if trait.HasDep || trait.WorkloadName != "" || trait.Object.GroupVersionKind() != gvk {
+			continue
+		}
+		traits = append(traits, trait.Object)
+	}
+	return traits
+}
+
+// mountClaims adds a volume of the PersistentVolumeClaim of each claim of the
+// supplied trait to the supplied pod spec, and mounts it into the containers
+// the claim lists, or all containers if it lists none. Volumes and mounts of
+// the same name are replaced.
+func mountClaims(podSpec *fieldpath.Paved, vct *v1alpha2.VolumeClaimTrait) error {
+	volumes, err := namedList(podSpec, "volumes")
+	if err != nil {
+		return err
+	}
+	containers, err := namedList(podSpec, "containers")
+	if err != nil {
+		return err
+	}
+	for _, claim := range vct.Spec.Claims {
+		name := util.VolumeClaimName(vct.GetName(), claim.Name)
+		readOnly := claim.AccessMode != nil && *claim.AccessMode == v1alpha2.VolumeAccessModeRO
+		volume, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: name,
+				ReadOnly:  readOnly,
+			}},
+		})
+		if err != nil {
+			return err
+		}
+		volumes = setNamed(volumes, volume)
+
+		mount, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.VolumeMount{
+			Name:      name,
+			MountPath: claim.MountPath,
+			ReadOnly:  readOnly,
+		})
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok || !mountedInto(claim, container["name"]) {
+				continue
+			}
+			mounts, _ := container["volumeMounts"].([]interface{})
+			container["volumeMounts"] = setNamed(mounts, runtime.DeepCopyJSON(mount))
+		}
+	}
+	return podSpec.SetValue("volumes", volumes)
+}
+
+// namedList returns the list at the supplied path of the supplied pod spec,
+// or nil if there is none.
+func namedList(podSpec *fieldpath.Paved, path string) ([]interface{}, error) {
+	v, err := podSpec.GetValue(path)
+	if fieldpath.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l, _ := v.([]interface{})
+	return l, nil
+}
+
+// setNamed replaces the object of the same name as the supplied one in the
+// supplied list, or appends it if there is none.
+func setNamed(l []interface{}, obj map[string]interface{}) []interface{} {
+	for i := range l {
+		if o, ok := l[i].(map[string]interface{}); ok && o["name"] == obj["name"] {
+			l[i] = obj
+			return l
+		}
+	}
+	return append(l, obj)
+}
+
+// mountedInto returns true if the supplied claim is mounted into the named
+// container.
+func mountedInto(claim v1alpha2.VolumeClaim, container interface{}) bool {
+	if len(claim.Containers) == 0 {
+		return true
+	}
+	for _, c := range claim.Containers {
+		if c == container {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package applicationconfiguration
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestMountVolumeClaims(t *testing.T) {
+	ro := v1alpha2.VolumeAccessModeRO
+	vct, _ := util.Object2Unstructured(&v1alpha2.VolumeClaimTrait{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.VolumeClaimTraitKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "storage"},
+		Spec: v1alpha2.VolumeClaimTraitSpec{Claims: []v1alpha2.VolumeClaim{
+			{Name: "data", MountPath: "/data", Required: resource.MustParse("1Gi")},
+			{Name: "logs", MountPath: "/logs", Containers: []string{"sidecar"}, AccessMode: &ro, Required: resource.MustParse("1Gi")},
+		}},
+	})
+	deployment := func(volumes []interface{}, containers ...interface{}) *unstructured.Unstructured {
+		podSpec := map[string]interface{}{"containers": containers}
+		if volumes != nil {
+			podSpec["volumes"] = volumes
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{"spec": podSpec},
+			},
+		}}
+	}
+	container := func(name string, mounts ...interface{}) map[string]interface{} {
+		c := map[string]interface{}{"name": name}
+		if mounts != nil {
+			c["volumeMounts"] = mounts
+		}
+		return c
+	}
+	claimVolume := func(name string, readOnly bool) map[string]interface{} {
+		pvc := map[string]interface{}{"claimName": name}
+		if readOnly {
+			pvc["readOnly"] = true
+		}
+		return map[string]interface{}{"name": name, "persistentVolumeClaim": pvc}
+	}
+	mount := func(name, path string, readOnly bool) map[string]interface{} {
+		m := map[string]interface{}{"name": name, "mountPath": path}
+		if readOnly {
+			m["readOnly"] = true
+		}
+		return m
+	}
+	emptyDir := map[string]interface{}{"name": "cache", "emptyDir": map[string]interface{}{}}
+
+	cases := map[string]struct {
+		reason string
+		wl     Workload
+		want   *unstructured.Unstructured
+	}{
+		"NoPodSpecPath": {
+			reason: "Workloads should be left as is if their pod spec can't be determined",
+			wl: Workload{
+				Workload: deployment(nil, container("web")),
+				Traits:   []*Trait{{Object: *vct}},
+			},
+			want: deployment(nil, container("web")),
+		},
+		"SkippedTraits": {
+			reason: "The claims of traits that are not ready, or apply to auxiliary workloads, should not be mounted",
+			wl: Workload{
+				Workload:    deployment(nil, container("web")),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{{Object: *vct, HasDep: true}, {Object: *vct, WorkloadName: "cache"}},
+			},
+			want: deployment(nil, container("web")),
+		},
+		"Mount": {
+			reason: "Claims should be mounted into the containers they list, or all containers, replacing volumes and mounts of the same name",
+			wl: Workload{
+				Workload: deployment([]interface{}{emptyDir, map[string]interface{}{"name": "storage-data", "emptyDir": map[string]interface{}{}}},
+					container("web", mount("cache", "/cache", false)),
+					container("sidecar", mount("storage-data", "/tmp", false))),
+				PodSpecPath: "spec.template.spec",
+				Traits:      []*Trait{{Object: *vct}},
+			},
+			want: deployment([]interface{}{emptyDir, claimVolume("storage-data", false), claimVolume("storage-logs", true)},
+				container("web", mount("cache", "/cache", false), mount("storage-data", "/data", false)),
+				container("sidecar", mount("storage-data", "/data", false), mount("storage-logs", "/logs", true))),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := mountVolumeClaims(tc.wl)
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nmountVolumeClaims(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.wl.Workload); diff != "" {
+				t.Errorf("\n%s\nmountVolumeClaims(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
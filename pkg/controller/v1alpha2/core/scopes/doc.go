@@ -14,5 +14,6 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package scopes provides scope related controllers.
+// Package scopes provides scope related controllers, and a Reconciler that
+// controllers of other kinds of scopes can be built on.
 package scopes
@@ -34,6 +34,9 @@ import (
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	corev1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/health"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 )
@@ -42,6 +45,7 @@ const (
 	infoFmtUnknownWorkload = "APIVersion %v Kind %v workload is unknown for HealthScope "
 	infoFmtReady           = "Ready: %d/%d "
 	infoFmtNoChildRes      = "cannot get child resource references of workload %v"
+	infoFmtContainer       = "%scontainer %s ready: %d/%d restarts: %d %s "
 	errHealthCheck         = "error occurs in health check "
 
 	defaultTimeout = 10 * time.Second
@@ -110,13 +114,15 @@ func CheckContainerziedWorkloadHealth(ctx context.Context, c client.Client, ref
 
 	childRefs := cwObj.Status.Resources
 	updateChildResourcesCondition(ctx, c, namespace, r, ref, childRefs)
+	updateContainersCondition(r, cwObj.Status.Containers)
 	return r
 }
 
 func updateChildResourcesCondition(ctx context.Context, c client.Client, namespace string, r *WorkloadHealthCondition, ref runtimev1alpha1.TypedReference, childRefs []runtimev1alpha1.TypedReference) {
 	subConditions := []*WorkloadHealthCondition{}
-	if len(childRefs) != 2 {
-		// one deployment and one svc are required by containerizedworkload
+	if !hasKind(childRefs, kindDeployment) {
+		// a deployment is required by containerizedworkload, a service only
+		// if it declares ports
 		r.Diagnosis = fmt.Sprintf(infoFmtNoChildRes, ref.Name)
 		r.HealthStatus = StatusUnhealthy
 		return
@@ -151,6 +157,28 @@ func updateChildResourcesCondition(ctx context.Context, c client.Client, namespa
 	}
 }
 
+// updateContainersCondition marks the workload unhealthy if any of its
+// containers fails its readiness probe, and diagnoses restarted containers.
+func updateContainersCondition(r *WorkloadHealthCondition, containers []corev1alpha2.ContainerProbeStatus) {
+	for _, cs := range containers {
+		if cs.ReadyPods < cs.Pods {
+			r.HealthStatus = StatusUnhealthy
+		} else if cs.Restarts == 0 {
+			continue
+		}
+		r.Diagnosis = fmt.Sprintf(infoFmtContainer, r.Diagnosis, cs.Name, cs.ReadyPods, cs.Pods, cs.Restarts, cs.Message)
+	}
+}
+
+func hasKind(refs []runtimev1alpha1.TypedReference, kind string) bool {
+	for _, ref := range refs {
+		if ref.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckDeploymentHealth checks health condition of Deployment
 func CheckDeploymentHealth(ctx context.Context, client client.Client, ref runtimev1alpha1.TypedReference, namespace string) *WorkloadHealthCondition {
 	if ref.GroupVersionKind() != apps.SchemeGroupVersion.WithKind(kindDeployment) {
@@ -247,6 +275,74 @@ func CheckDaemonsetHealth(ctx context.Context, client client.Client, ref runtime
 	return r
 }
 
+// NewHealthPolicyChecker returns a checker that evaluates the health policy
+// of the WorkloadDefinition of a workload. It returns no condition for
+// workloads whose WorkloadDefinition has no health policy, so that other
+// checkers check them.
+func NewHealthPolicyChecker(dm discoverymapper.DiscoveryMapper) WorloadHealthChecker {
+	return WorkloadHealthCheckFn(func(ctx context.Context, c client.Client, ref runtimev1alpha1.TypedReference, namespace string) *WorkloadHealthCondition {
+		wl := &unstructured.Unstructured{}
+		wl.SetGroupVersionKind(ref.GroupVersionKind())
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, wl); err != nil {
+			return nil
+		}
+		wd, err := util.FetchWorkloadDefinition(ctx, c, dm, wl)
+		if err != nil || wd.Spec.HealthPolicy == nil {
+			return nil
+		}
+		r := &WorkloadHealthCondition{
+			ComponentName:  getComponentNameFromLabel(wl),
+			TargetWorkload: ref,
+		}
+		r.TargetWorkload.UID = wl.GetUID()
+		r.HealthStatus, r.Diagnosis = health.Evaluate(wd.Spec.HealthPolicy, wl)
+		return r
+	})
+}
+
+// CheckByRegisteredChecker checks the health condition of the referenced
+// workload with the supplied checker registered for its kind.
+func CheckByRegisteredChecker(ctx context.Context, c client.Client, checker health.Checker, ref runtimev1alpha1.TypedReference, namespace string) *WorkloadHealthCondition {
+	r := &WorkloadHealthCondition{
+		HealthStatus:   StatusUnhealthy,
+		TargetWorkload: ref,
+	}
+	wl := &unstructured.Unstructured{}
+	wl.SetGroupVersionKind(ref.GroupVersionKind())
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, wl); err != nil {
+		r.Diagnosis = errors.Wrap(err, errHealthCheck).Error()
+		return r
+	}
+	r.ComponentName = getComponentNameFromLabel(wl)
+	r.TargetWorkload.UID = wl.GetUID()
+	r.HealthStatus, r.Diagnosis = checker.Check(ctx, c, wl)
+	return r
+}
+
+// CheckWorkloadStatus checks health condition of workloads by the conditions
+// of their status, e.g. Ready. It returns no condition for workloads that
+// report none of the conditions it understands, so that they are handled as
+// unknown workloads.
+func CheckWorkloadStatus(ctx context.Context, c client.Client, ref runtimev1alpha1.TypedReference, namespace string) *WorkloadHealthCondition {
+	wl := &unstructured.Unstructured{}
+	wl.SetGroupVersionKind(ref.GroupVersionKind())
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, wl); err != nil {
+		return nil
+	}
+	status, diagnosis, ok := health.EvaluateStatus(wl)
+	if !ok {
+		return nil
+	}
+	r := &WorkloadHealthCondition{
+		ComponentName:  getComponentNameFromLabel(wl),
+		TargetWorkload: ref,
+		HealthStatus:   status,
+		Diagnosis:      diagnosis,
+	}
+	r.TargetWorkload.UID = wl.GetUID()
+	return r
+}
+
 // CheckByHealthCheckTrait checks health condition through HealthCheckTrait.
 func CheckByHealthCheckTrait(ctx context.Context, c client.Client, wlRef runtimev1alpha1.TypedReference, ns string) *WorkloadHealthCondition {
 	// TODO(roywang) implement HealthCheckTrait feature
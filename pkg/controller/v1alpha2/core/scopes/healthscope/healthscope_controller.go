@@ -34,11 +34,17 @@ import (
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/health"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
 )
 
 const (
 	reconcileTimeout = 1 * time.Minute
 	longWait         = 1 * time.Minute
+	// minInterval keeps scopes whose checks take longer than their
+	// interval from being checked in a hot loop
+	minInterval = 1 * time.Second
 )
 
 // Reconcile error strings.
@@ -55,13 +61,19 @@ const (
 // Setup adds a controller that reconciles HealthScope.
 func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
 	name := "oam/" + strings.ToLower(v1alpha2.HealthScopeGroupKind)
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1alpha2.HealthScope{}).
 		Complete(NewReconciler(mgr,
 			WithLogger(l.WithValues("controller", name)),
-			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			WithRecorder(metrics.NewRecorder(name, event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+			WithHealthPolicyChecker(NewHealthPolicyChecker(dm)),
+			WithCheckerRegistry(args.HealthCheckers),
 		))
 }
 
@@ -73,8 +85,17 @@ type Reconciler struct {
 	record event.Recorder
 	// traitChecker represents checker fetching health condition from HealthCheckTrait
 	traitChecker WorloadHealthChecker
+	// policyChecker represents checker evaluating the health policies of
+	// WorkloadDefinitions
+	policyChecker WorloadHealthChecker
+	// registry holds the checkers embedders registered for kinds of
+	// workloads, which take precedence over the built-in checkers
+	registry *health.Registry
 	// checkers represents a set of built-in checkers
 	checkers []WorloadHealthChecker
+	// statusChecker represents checker evaluating the conditions of
+	// workloads that cannot be handled by the built-in checkers
+	statusChecker WorloadHealthChecker
 	// unknownChecker represents checker handling workloads that
 	// cannot be hanlded by traitChecker nor built-in checkers
 	unknownChecker WorloadHealthChecker
@@ -104,6 +125,22 @@ func WithTraitChecker(c WorloadHealthChecker) ReconcilerOption {
 	}
 }
 
+// WithHealthPolicyChecker adds health checker based on the health policies
+// of WorkloadDefinitions, which takes precedence over the built-in checkers
+func WithHealthPolicyChecker(c WorloadHealthChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.policyChecker = c
+	}
+}
+
+// WithCheckerRegistry specifies the checkers of the kinds of workloads they
+// are registered for, which take precedence over the built-in checkers
+func WithCheckerRegistry(reg *health.Registry) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.registry = reg
+	}
+}
+
 // WithChecker adds workload health checker
 func WithChecker(c WorloadHealthChecker) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -128,6 +165,7 @@ func NewReconciler(m ctrl.Manager, o ...ReconcilerOption) *Reconciler {
 			WorkloadHealthCheckFn(CheckStatefulsetHealth),
 			WorkloadHealthCheckFn(CheckDaemonsetHealth),
 		},
+		statusChecker:  WorkloadHealthCheckFn(CheckWorkloadStatus),
 		unknownChecker: WorkloadHealthCheckFn(CheckUnknownWorkload),
 	}
 	for _, ro := range o {
@@ -142,23 +180,19 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
-	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
-	defer cancel()
+	getCtx, getCancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer getCancel()
 
 	hs := &v1alpha2.HealthScope{}
-	if err := r.client.Get(ctx, req.NamespacedName, hs); err != nil {
+	if err := r.client.Get(getCtx, req.NamespacedName, hs); err != nil {
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetHealthScope)
 	}
 
-	interval := longWait
-	if hs.Spec.ProbeInterval != nil {
-		interval = time.Duration(*hs.Spec.ProbeInterval) * time.Second
-	}
-
-	if interval <= 0 {
-		interval = longWait
-	}
+	// the checks may take as long as the probe timeout of the scope
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout+probeTimeout(hs))
+	defer cancel()
 
+	interval := probeInterval(hs)
 	start := time.Now()
 
 	log = log.WithValues("uid", hs.GetUID(), "version", hs.GetResourceVersion())
@@ -170,8 +204,50 @@ func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error)
 	elapsed := time.Since(start)
 	hs.Status.ScopeHealthCondition = scopeCondition
 	hs.Status.WorkloadHealthConditions = wlConditions
+	hs.SetConditions(runtimev1alpha1.ReconcileSuccess())
+
+	requeueAfter := interval - elapsed
+	if requeueAfter < minInterval {
+		requeueAfter = minInterval
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, errors.Wrap(r.client.Status().Update(ctx, hs), errUpdateHealthScopeStatus)
+}
+
+// probeInterval returns the interval between checks of the health of the
+// workloads of the supplied scope, i.e. its probeInterval or deprecated
+// probe-interval, and 1m by default.
+func probeInterval(hs *v1alpha2.HealthScope) time.Duration {
+	interval := longWait
+	switch {
+	case hs.Spec.Interval != nil:
+		interval = hs.Spec.Interval.Duration
+	case hs.Spec.ProbeInterval != nil:
+		interval = time.Duration(*hs.Spec.ProbeInterval) * time.Second
+	}
+	if interval <= 0 {
+		return longWait
+	}
+	if interval < minInterval {
+		return minInterval
+	}
+	return interval
+}
 
-	return reconcile.Result{RequeueAfter: interval - elapsed}, errors.Wrap(r.client.Status().Update(ctx, hs), errUpdateHealthScopeStatus)
+// probeTimeout returns the timeout of checking the health of the workloads of
+// the supplied scope, i.e. its probeTimeout or deprecated probe-timeout, and
+// 10s by default.
+func probeTimeout(hs *v1alpha2.HealthScope) time.Duration {
+	timeout := defaultTimeout
+	switch {
+	case hs.Spec.Timeout != nil:
+		timeout = hs.Spec.Timeout.Duration
+	case hs.Spec.ProbeTimeout != nil:
+		timeout = time.Duration(*hs.Spec.ProbeTimeout) * time.Second
+	}
+	if timeout <= 0 {
+		return defaultTimeout
+	}
+	return timeout
 }
 
 // GetScopeHealthStatus get the status of the healthscope based on workload resources.
@@ -185,55 +261,25 @@ func (r *Reconciler) GetScopeHealthStatus(ctx context.Context, healthScope *v1al
 		return scopeCondition, []*WorkloadHealthCondition{}
 	}
 
-	timeout := defaultTimeout
-	if healthScope.Spec.ProbeTimeout != nil {
-		timeout = time.Duration(*healthScope.Spec.ProbeTimeout) * time.Second
-	}
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, probeTimeout(healthScope))
 	defer cancel()
 
-	// process workloads concurrently
-	workloadHealthConditionsC := make(chan *WorkloadHealthCondition, len(scopeWLRefs))
+	// process workloads concurrently, keeping the health conditions of
+	// workloads in the order they are referenced
+	workloadHealthConditions := make([]*WorkloadHealthCondition, len(scopeWLRefs))
 	var wg sync.WaitGroup
 	wg.Add(len(scopeWLRefs))
 
-	for _, workloadRef := range scopeWLRefs {
-		go func(resRef runtimev1alpha1.TypedReference) {
+	for i, workloadRef := range scopeWLRefs {
+		go func(i int, resRef runtimev1alpha1.TypedReference) {
 			defer wg.Done()
-			var wlHealthCondition *WorkloadHealthCondition
-
-			wlHealthCondition = r.traitChecker.Check(ctx, r.client, resRef, healthScope.GetNamespace())
-			if wlHealthCondition != nil {
-				log.Debug("get health condition from health check trait ", "workload", resRef, "healthCondition", wlHealthCondition)
-				// get healthCondition from HealthCheckTrait
-				workloadHealthConditionsC <- wlHealthCondition
-				return
-			}
-
-			for _, checker := range r.checkers {
-				wlHealthCondition = checker.Check(ctxWithTimeout, r.client, resRef, healthScope.GetNamespace())
-				if wlHealthCondition != nil {
-					log.Debug("get health condition from built-in checker", "workload", resRef, "healthCondition", wlHealthCondition)
-					// found matched checker and get health condition
-					workloadHealthConditionsC <- wlHealthCondition
-					return
-				}
-			}
-			// handle unknown workload
-			log.Debug("get unknown workload", "workload", resRef)
-			workloadHealthConditionsC <- r.unknownChecker.Check(ctx, r.client, resRef, healthScope.GetNamespace())
-		}(workloadRef)
+			workloadHealthConditions[i] = r.checkWorkload(ctxWithTimeout, log, resRef, healthScope.GetNamespace())
+		}(i, workloadRef)
 	}
-
-	go func() {
-		wg.Wait()
-		close(workloadHealthConditionsC)
-	}()
+	wg.Wait()
 
 	var healthyCount, unhealthyCount, unknownCount int64
-	workloadHealthConditions := []*WorkloadHealthCondition{}
-	for wlC := range workloadHealthConditionsC {
-		workloadHealthConditions = append(workloadHealthConditions, wlC)
+	for _, wlC := range workloadHealthConditions {
 		switch wlC.HealthStatus { //nolint:exhaustive
 		case StatusHealthy:
 			healthyCount++
@@ -256,3 +302,40 @@ func (r *Reconciler) GetScopeHealthStatus(ctx context.Context, healthScope *v1al
 
 	return scopeCondition, workloadHealthConditions
 }
+
+// checkWorkload returns the health condition of the referenced workload
+// reported by the first checker that can check it.
+func (r *Reconciler) checkWorkload(ctx context.Context, log logging.Logger, resRef runtimev1alpha1.TypedReference, ns string) *WorkloadHealthCondition {
+	if c := r.traitChecker.Check(ctx, r.client, resRef, ns); c != nil {
+		log.Debug("get health condition from health check trait ", "workload", resRef, "healthCondition", c)
+		// get healthCondition from HealthCheckTrait
+		return c
+	}
+	if r.policyChecker != nil {
+		if c := r.policyChecker.Check(ctx, r.client, resRef, ns); c != nil {
+			log.Debug("get health condition from health policy", "workload", resRef, "healthCondition", c)
+			return c
+		}
+	}
+	if checker := r.registry.Checker(resRef.GroupVersionKind()); checker != nil {
+		c := CheckByRegisteredChecker(ctx, r.client, checker, resRef, ns)
+		log.Debug("get health condition from registered checker", "workload", resRef, "healthCondition", c)
+		return c
+	}
+	for _, checker := range r.checkers {
+		if c := checker.Check(ctx, r.client, resRef, ns); c != nil {
+			log.Debug("get health condition from built-in checker", "workload", resRef, "healthCondition", c)
+			// found matched checker and get health condition
+			return c
+		}
+	}
+	if r.statusChecker != nil {
+		if c := r.statusChecker.Check(ctx, r.client, resRef, ns); c != nil {
+			log.Debug("get health condition from workload status", "workload", resRef, "healthCondition", c)
+			return c
+		}
+	}
+	// handle unknown workload
+	log.Debug("get unknown workload", "workload", resRef)
+	return r.unknownChecker.Check(ctx, r.client, resRef, ns)
+}
@@ -114,6 +114,10 @@ var _ = Describe("HealthScope Controller Reconcile Test", func() {
 				return nil
 			},
 			MockStatusUpdate: func(_ context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+				o, _ := obj.(*v1alpha2.HealthScope)
+				Expect(o.Status.ScopeHealthCondition.HealthStatus).Should(Equal(StatusHealthy))
+				Expect(o.Status.WorkloadHealthConditions).Should(HaveLen(1))
+				Expect(o.GetCondition(v1alpha1.TypeSynced).Reason).Should(Equal(v1alpha1.ReasonReconcileSuccess))
 				return nil
 			},
 		}
@@ -301,4 +305,29 @@ var _ = Describe("Test GetScopeHealthStatus", func() {
 			Expect(result).Should(Equal(tc.wantScopeCondition))
 		}
 	})
+
+	It("Test workload health conditions follow workload references", func() {
+		reconciler.client = &test.MockClient{
+			MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+				switch o := obj.(type) {
+				case *corev1alpha2.ContainerizedWorkload:
+					*o = cw
+				case *appsv1.Deployment:
+					*o = hDeploy
+				case *unstructured.Unstructured:
+					*o = *unsupporttedWL
+				}
+				return nil
+			},
+		}
+		hs.Spec.WorkloadReferences = []v1alpha1.TypedReference{uhGeneralRef, cwRef, deployRef}
+		_, result := reconciler.GetScopeHealthStatus(ctx, &hs)
+		Expect(result).Should(HaveLen(3))
+		for i, ref := range hs.Spec.WorkloadReferences {
+			Expect(result[i].TargetWorkload.Kind).Should(Equal(ref.Kind))
+		}
+		Expect(result[0].HealthStatus).Should(Equal(HealthStatus(StatusUnknown)))
+		Expect(result[1].HealthStatus).Should(Equal(StatusHealthy))
+		Expect(result[2].HealthStatus).Should(Equal(StatusHealthy))
+	})
 })
@@ -20,12 +20,15 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -34,6 +37,8 @@ import (
 	"github.com/pkg/errors"
 
 	corev1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/health"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
 )
 
 const (
@@ -60,6 +65,27 @@ func TestCheckContainerziedWorkloadHealth(t *testing.T) {
 			Resources: []runtimev1alpha1.TypedReference{deployRef, svcRef},
 		},
 	}
+	cwWithoutService := corev1alpha2.ContainerizedWorkload{
+		Status: corev1alpha2.ContainerizedWorkloadStatus{
+			Resources: []runtimev1alpha1.TypedReference{deployRef},
+		},
+	}
+	cwNotReady := corev1alpha2.ContainerizedWorkload{
+		Status: corev1alpha2.ContainerizedWorkloadStatus{
+			Resources: []runtimev1alpha1.TypedReference{deployRef, svcRef},
+			Containers: []corev1alpha2.ContainerProbeStatus{
+				{Name: "app", Pods: 1, ReadyPods: 0, Restarts: 2, Message: "waiting: CrashLoopBackOff"},
+			},
+		},
+	}
+	healthyDeploy := apps.Deployment{
+		Spec: apps.DeploymentSpec{
+			Replicas: &varInt1,
+		},
+		Status: apps.DeploymentStatus{
+			ReadyReplicas: 1, // healthy
+		},
+	}
 
 	tests := []struct {
 		caseName  string
@@ -120,6 +146,38 @@ func TestCheckContainerziedWorkloadHealth(t *testing.T) {
 				HealthStatus: StatusUnhealthy,
 			},
 		},
+		{
+			caseName: "healthy workload without service",
+			wlRef:    cwRef,
+			mockGetFn: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+				switch o := obj.(type) {
+				case *corev1alpha2.ContainerizedWorkload:
+					*o = cwWithoutService
+				case *apps.Deployment:
+					*o = healthyDeploy
+				}
+				return nil
+			},
+			expect: &WorkloadHealthCondition{
+				HealthStatus: StatusHealthy,
+			},
+		},
+		{
+			caseName: "unhealthy for container failing its probes",
+			wlRef:    cwRef,
+			mockGetFn: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+				switch o := obj.(type) {
+				case *corev1alpha2.ContainerizedWorkload:
+					*o = cwNotReady
+				case *apps.Deployment:
+					*o = healthyDeploy
+				}
+				return nil
+			},
+			expect: &WorkloadHealthCondition{
+				HealthStatus: StatusUnhealthy,
+			},
+		},
 		{
 			caseName: "unhealthy for ContainerizedWorkload not found",
 			wlRef:    cwRef,
@@ -266,6 +324,171 @@ func TestCheckDeploymentHealth(t *testing.T) {
 	}
 }
 
+func TestHealthPolicyChecker(t *testing.T) {
+	mockClient := test.NewMockClient()
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = mock.NewMockRESTMapping("foos")
+	fooRef := runtimev1alpha1.TypedReference{APIVersion: "example.com/v1", Kind: "Foo", Name: "foo"}
+	policy := &corev1alpha2.HealthPolicy{Conditions: []corev1alpha2.HealthCondition{{FieldPath: "status.phase", Value: "Running"}}}
+	getFn := func(phase string, p *corev1alpha2.HealthPolicy) test.MockGetFn {
+		return func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *unstructured.Unstructured:
+				o.Object["status"] = map[string]interface{}{"phase": phase}
+			case *corev1alpha2.WorkloadDefinition:
+				o.Spec.HealthPolicy = p
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		caseName  string
+		mockGetFn test.MockGetFn
+		expect    *WorkloadHealthCondition
+	}{
+		{
+			caseName:  "no health policy",
+			mockGetFn: getFn("Running", nil),
+			expect:    nil,
+		},
+		{
+			caseName:  "workload not found",
+			mockGetFn: test.NewMockGetFn(errMockErr),
+			expect:    nil,
+		},
+		{
+			caseName:  "healthy workload",
+			mockGetFn: getFn("Running", policy),
+			expect: &WorkloadHealthCondition{
+				HealthStatus: StatusHealthy,
+			},
+		},
+		{
+			caseName:  "unhealthy workload",
+			mockGetFn: getFn("Pending", policy),
+			expect: &WorkloadHealthCondition{
+				HealthStatus: StatusUnhealthy,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		func(t *testing.T) {
+			mockClient.MockGet = tc.mockGetFn
+			result := NewHealthPolicyChecker(dm).Check(ctx, mockClient, fooRef, namespace)
+			if tc.expect == nil {
+				assert.Nil(t, result, tc.caseName)
+			} else {
+				assert.Equal(t, tc.expect.HealthStatus, result.HealthStatus, tc.caseName)
+			}
+		}(t)
+	}
+}
+
+func TestCheckByRegisteredChecker(t *testing.T) {
+	mockClient := test.NewMockClient()
+	dbRef := runtimev1alpha1.TypedReference{APIVersion: "example.com/v1", Kind: "Database", Name: "db"}
+	checker := health.CheckFn(func(_ context.Context, _ client.Reader, u *unstructured.Unstructured) (HealthStatus, string) {
+		if phase, _, _ := unstructured.NestedString(u.Object, "status", "phase"); phase != "Online" {
+			return StatusUnhealthy, "database is " + phase
+		}
+		return StatusHealthy, ""
+	})
+	getFn := func(phase string) test.MockGetFn {
+		return func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+			o, _ := obj.(*unstructured.Unstructured)
+			o.Object["status"] = map[string]interface{}{"phase": phase}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		caseName  string
+		mockGetFn test.MockGetFn
+		expect    *WorkloadHealthCondition
+	}{
+		{
+			caseName:  "healthy workload",
+			mockGetFn: getFn("Online"),
+			expect:    &WorkloadHealthCondition{HealthStatus: StatusHealthy, TargetWorkload: dbRef},
+		},
+		{
+			caseName:  "unhealthy workload",
+			mockGetFn: getFn("Provisioning"),
+			expect:    &WorkloadHealthCondition{HealthStatus: StatusUnhealthy, TargetWorkload: dbRef, Diagnosis: "database is Provisioning"},
+		},
+		{
+			caseName:  "workload not found",
+			mockGetFn: test.NewMockGetFn(errMockErr),
+			expect: &WorkloadHealthCondition{
+				HealthStatus:   StatusUnhealthy,
+				TargetWorkload: dbRef,
+				Diagnosis:      errors.Wrap(errMockErr, errHealthCheck).Error(),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		func(t *testing.T) {
+			mockClient.MockGet = tc.mockGetFn
+			result := CheckByRegisteredChecker(ctx, mockClient, checker, dbRef, namespace)
+			assert.Equal(t, tc.expect, result, tc.caseName)
+		}(t)
+	}
+}
+
+func TestCheckWorkloadStatus(t *testing.T) {
+	mockClient := test.NewMockClient()
+	dbRef := runtimev1alpha1.TypedReference{APIVersion: "example.com/v1", Kind: "Database", Name: "db"}
+	getFn := func(conditions ...interface{}) test.MockGetFn {
+		return func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+			o, _ := obj.(*unstructured.Unstructured)
+			o.Object["status"] = map[string]interface{}{"conditions": conditions}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		caseName  string
+		mockGetFn test.MockGetFn
+		expect    *WorkloadHealthCondition
+	}{
+		{
+			caseName:  "ready workload",
+			mockGetFn: getFn(map[string]interface{}{"type": "Ready", "status": "True"}),
+			expect:    &WorkloadHealthCondition{HealthStatus: StatusHealthy, TargetWorkload: dbRef, Diagnosis: "condition Ready is True"},
+		},
+		{
+			caseName:  "workload that is not ready",
+			mockGetFn: getFn(map[string]interface{}{"type": "Ready", "status": "False"}),
+			expect:    &WorkloadHealthCondition{HealthStatus: StatusUnhealthy, TargetWorkload: dbRef, Diagnosis: "condition Ready is False"},
+		},
+		{
+			caseName:  "workload without known conditions",
+			mockGetFn: getFn(),
+			expect:    nil,
+		},
+		{
+			caseName:  "workload not found",
+			mockGetFn: test.NewMockGetFn(errMockErr),
+			expect:    nil,
+		},
+	}
+
+	for _, tc := range tests {
+		func(t *testing.T) {
+			mockClient.MockGet = tc.mockGetFn
+			result := CheckWorkloadStatus(ctx, mockClient, dbRef, namespace)
+			if tc.expect == nil {
+				assert.Nil(t, result, tc.caseName)
+			} else {
+				assert.Equal(t, tc.expect, result, tc.caseName)
+			}
+		}(t)
+	}
+}
+
 func TestCheckStatefulsetHealth(t *testing.T) {
 	mockClient := test.NewMockClient()
 	stsRef := runtimev1alpha1.TypedReference{}
@@ -482,3 +705,87 @@ func TestCheckUnknownWorkload(t *testing.T) {
 		}(t)
 	}
 }
+
+func TestProbeInterval(t *testing.T) {
+	seconds := int32(30)
+	zero := int32(0)
+
+	tests := []struct {
+		caseName string
+		spec     corev1alpha2.HealthScopeSpec
+		expect   time.Duration
+	}{
+		{
+			caseName: "default interval",
+			expect:   longWait,
+		},
+		{
+			caseName: "probeInterval",
+			spec:     corev1alpha2.HealthScopeSpec{Interval: &metav1.Duration{Duration: 10 * time.Second}},
+			expect:   10 * time.Second,
+		},
+		{
+			caseName: "deprecated probe-interval",
+			spec:     corev1alpha2.HealthScopeSpec{ProbeInterval: &seconds},
+			expect:   30 * time.Second,
+		},
+		{
+			caseName: "probeInterval takes precedence",
+			spec: corev1alpha2.HealthScopeSpec{
+				Interval:      &metav1.Duration{Duration: 10 * time.Second},
+				ProbeInterval: &seconds,
+			},
+			expect: 10 * time.Second,
+		},
+		{
+			caseName: "interval below the minimum",
+			spec:     corev1alpha2.HealthScopeSpec{Interval: &metav1.Duration{Duration: time.Millisecond}},
+			expect:   minInterval,
+		},
+		{
+			caseName: "zero interval",
+			spec:     corev1alpha2.HealthScopeSpec{ProbeInterval: &zero},
+			expect:   longWait,
+		},
+	}
+
+	for _, tc := range tests {
+		result := probeInterval(&corev1alpha2.HealthScope{Spec: tc.spec})
+		assert.Equal(t, tc.expect, result, tc.caseName)
+	}
+}
+
+func TestProbeTimeout(t *testing.T) {
+	seconds := int32(30)
+
+	tests := []struct {
+		caseName string
+		spec     corev1alpha2.HealthScopeSpec
+		expect   time.Duration
+	}{
+		{
+			caseName: "default timeout",
+			expect:   defaultTimeout,
+		},
+		{
+			caseName: "probeTimeout",
+			spec:     corev1alpha2.HealthScopeSpec{Timeout: &metav1.Duration{Duration: 2 * time.Second}},
+			expect:   2 * time.Second,
+		},
+		{
+			caseName: "deprecated probe-timeout",
+			spec:     corev1alpha2.HealthScopeSpec{ProbeTimeout: &seconds},
+			expect:   30 * time.Second,
+		},
+		{
+			caseName: "negative timeout",
+			spec:     corev1alpha2.HealthScopeSpec{Timeout: &metav1.Duration{Duration: -time.Second}},
+			expect:   defaultTimeout,
+		},
+	}
+
+	for _, tc := range tests {
+		result := probeTimeout(&corev1alpha2.HealthScope{Spec: tc.spec})
+		assert.Equal(t, tc.expect, result, tc.caseName)
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkscope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+)
+
+const (
+	// pollInterval lets the NetworkPolicies follow changes of the pods
+	// selected by workloads
+	pollInterval = 1 * time.Minute
+)
+
+const (
+	errNotNetworkScope        = "scope is not a NetworkScope"
+	errFmtApplyNetworkPolicy  = "cannot apply network policy %q"
+	errFmtDeleteNetworkPolicy = "cannot delete network policy %q"
+	errFmtDecodeSelector      = "cannot decode the selector of %s %q"
+	errFmtDecodeChildResource = "cannot decode a child resource reference of %s %q"
+	errFmtGetChildResource    = "cannot get child resource %s %q"
+
+	reasonFmtNoPods = "cannot determine the pods of %s %q"
+)
+
+// Setup adds a controller that reconciles NetworkScopes.
+func Setup(mgr ctrl.Manager, _ controller.Args, l logging.Logger) error {
+	name := "oam/" + strings.ToLower(v1alpha2.NetworkScopeGroupKind)
+	r := scopes.NewReconciler(mgr, oam.ScopeKind(v1alpha2.NetworkScopeGroupVersionKind), NewPolicy(mgr.GetClient()),
+		scopes.WithLogger(l.WithValues("controller", name)),
+		scopes.WithRecorder(metrics.NewRecorder(name, event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		scopes.WithPollInterval(pollInterval),
+	)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.NetworkScope{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Complete(r)
+}
+
+// A Policy places the pods of the workloads in a NetworkScope into a shared
+// network boundary. It generates a NetworkPolicy for each workload that only
+// allows ingress traffic to the pods of the workload from the pods of the
+// workloads in the scope.
+type Policy struct {
+	client     client.Client
+	applicator resource.Applicator
+}
+
+// NewPolicy returns a Policy that generates NetworkPolicies using the
+// supplied client.
+func NewPolicy(c client.Client) *Policy {
+	return &Policy{client: c, applicator: resource.NewAPIPatchingApplicator(c)}
+}
+
+var _ scopes.Policy = &Policy{}
+
+// Add does nothing. The NetworkPolicies of all workloads are generated when
+// the scope is synced, because the NetworkPolicies of the workloads already
+// in the scope must allow ingress traffic from the added workload too.
+func (p *Policy) Add(_ context.Context, _ oam.Scope, _ *unstructured.Unstructured) error {
+	return nil
+}
+
+// Remove deletes the NetworkPolicy of the referenced workload.
+func (p *Policy) Remove(ctx context.Context, s oam.Scope, ref runtimev1alpha1.TypedReference) error {
+	np := &networkingv1.NetworkPolicy{}
+	np.SetNamespace(s.GetNamespace())
+	np.SetName(networkPolicyName(s, ref))
+	return errors.Wrapf(resource.IgnoreNotFound(p.client.Delete(ctx, np)), errFmtDeleteNetworkPolicy, np.GetName())
+}
+
+// Sync generates the NetworkPolicies of the supplied workloads, and records
+// them in the status of the supplied NetworkScope.
+func (p *Policy) Sync(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured) error {
+	ns, ok := s.(*v1alpha2.NetworkScope)
+	if !ok {
+		return errors.New(errNotNetworkScope)
+	}
+
+	status := make([]v1alpha2.NetworkScopeWorkload, len(workloads))
+	selectors := make([]*metav1.LabelSelector, len(workloads))
+	peers := make([]networkingv1.NetworkPolicyPeer, 0, len(workloads))
+	for i, wl := range workloads {
+		ref := runtimev1alpha1.TypedReference{
+			APIVersion: wl.GetAPIVersion(),
+			Kind:       wl.GetKind(),
+			Name:       wl.GetName(),
+			UID:        wl.GetUID(),
+		}
+		status[i].TargetWorkload = ref
+		sel, err := p.podSelector(ctx, wl)
+		if err != nil {
+			return err
+		}
+		if sel == nil {
+			// the pods the NetworkPolicy of the workload selected before,
+			// if any, are unknown now
+			status[i].Diagnosis = fmt.Sprintf(reasonFmtNoPods, ref.Kind, ref.Name)
+			if err := p.Remove(ctx, s, ref); err != nil {
+				return err
+			}
+			continue
+		}
+		selectors[i] = sel
+		peers = append(peers, networkingv1.NetworkPolicyPeer{PodSelector: sel})
+	}
+
+	for i := range workloads {
+		if selectors[i] == nil {
+			continue
+		}
+		np := networkPolicy(ns, status[i].TargetWorkload, selectors[i], peers)
+		if err := p.applicator.Apply(ctx, np, resource.MustBeControllableBy(ns.GetUID())); err != nil {
+			return errors.Wrapf(err, errFmtApplyNetworkPolicy, np.GetName())
+		}
+		status[i].NetworkPolicy = np.GetName()
+	}
+	ns.Status.Workloads = status
+	return nil
+}
+
+// podSelector returns the selector of the pods of the supplied workload. The
+// pods of workloads without a selector, e.g. ContainerizedWorkloads, are the
+// pods of the first child resource recorded in their status that has one. It
+// returns nil if the pods of the workload can't be determined.
+func (p *Policy) podSelector(ctx context.Context, wl *unstructured.Unstructured) (*metav1.LabelSelector, error) {
+	sel, err := selectorOf(wl)
+	if err != nil || sel != nil {
+		return sel, err
+	}
+	children, _, _ := unstructured.NestedSlice(wl.Object, "status", "resources")
+	for _, c := range children {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref := runtimev1alpha1.TypedReference{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &ref); err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeChildResource, wl.GetKind(), wl.GetName())
+		}
+		child := &unstructured.Unstructured{}
+		child.SetGroupVersionKind(ref.GroupVersionKind())
+		err := p.client.Get(ctx, types.NamespacedName{Namespace: wl.GetNamespace(), Name: ref.Name}, child)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetChildResource, ref.Kind, ref.Name)
+		}
+		if sel, err := selectorOf(child); err != nil || sel != nil {
+			return sel, err
+		}
+	}
+	return nil, nil
+}
+
+// selectorOf returns the label selector at spec.selector of the supplied
+// resource, if any. Selectors that are not label selectors, e.g. those of
+// Services, and empty selectors, which would select all pods, are ignored.
+func selectorOf(u *unstructured.Unstructured) (*metav1.LabelSelector, error) {
+	raw, found, err := unstructured.NestedFieldNoCopy(u.Object, "spec", "selector")
+	if err != nil || !found {
+		return nil, nil
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	_, hasLabels := m["matchLabels"]
+	_, hasExpressions := m["matchExpressions"]
+	if !hasLabels && !hasExpressions {
+		return nil, nil
+	}
+	sel := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, sel); err != nil {
+		return nil, errors.Wrapf(err, errFmtDecodeSelector, u.GetKind(), u.GetName())
+	}
+	if len(sel.MatchLabels) == 0 && len(sel.MatchExpressions) == 0 {
+		return nil, nil
+	}
+	return sel, nil
+}
+
+// networkPolicy returns the NetworkPolicy of the referenced workload of the
+// supplied scope, which allows ingress traffic to the pods it selects from
+// the supplied peers only.
+func networkPolicy(s *v1alpha2.NetworkScope, ref runtimev1alpha1.TypedReference, sel *metav1.LabelSelector,
+	peers []networkingv1.NetworkPolicyPeer) *networkingv1.NetworkPolicy {
+	np := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.GetNamespace(),
+			Name:      networkPolicyName(s, ref),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *sel,
+			Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+	meta.AddOwnerReference(np, meta.AsController(meta.ReferenceTo(s, v1alpha2.NetworkScopeGroupVersionKind)))
+	return np
+}
+
+// networkPolicyName returns the name of the NetworkPolicy of the referenced
+// workload of the supplied scope.
+func networkPolicyName(s oam.Scope, ref runtimev1alpha1.TypedReference) string {
+	return strings.ToLower(fmt.Sprintf("%s-%s-%s", s.GetName(), ref.Kind, ref.Name))
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkscope
+
+import (
+	"context"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestSync(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	scope := &v1alpha2.NetworkScope{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "net", UID: "scope-uid"}}
+
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+		},
+	}}
+	cw := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "core.oam.dev/v1alpha2",
+		"kind":       "ContainerizedWorkload",
+		"metadata":   map[string]interface{}{"namespace": "ns", "name": "api"},
+		"status": map[string]interface{}{
+			"resources": []interface{}{
+				map[string]interface{}{"apiVersion": "v1", "kind": "Service", "name": "api"},
+				map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "api"},
+			},
+		},
+	}}
+	unknown := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Function",
+		"metadata":   map[string]interface{}{"namespace": "ns", "name": "fn"},
+	}}
+
+	// getChildren returns the children of the ContainerizedWorkload
+	getChildren := func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+		u := obj.(*unstructured.Unstructured)
+		switch u.GetKind() {
+		case "Service":
+			// the selector of a Service is not a label selector
+			u.Object["spec"] = map[string]interface{}{"selector": map[string]interface{}{"app": "api"}}
+		case "Deployment":
+			u.Object["spec"] = map[string]interface{}{
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "api"}},
+			}
+		}
+		return nil
+	}
+
+	webSelector := metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	apiSelector := metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}
+	peers := []networkingv1.NetworkPolicyPeer{{PodSelector: &webSelector}, {PodSelector: &apiSelector}}
+	np := func(name string, sel metav1.LabelSelector) *networkingv1.NetworkPolicy {
+		np := &networkingv1.NetworkPolicy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "NetworkPolicy"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: sel,
+				Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+		meta.AddOwnerReference(np, meta.AsController(meta.ReferenceTo(scope, v1alpha2.NetworkScopeGroupVersionKind)))
+		return np
+	}
+
+	type args struct {
+		c         client.Client
+		apply     error
+		s         oam.Scope
+		workloads []*unstructured.Unstructured
+	}
+	type want struct {
+		err      error
+		applied  []*networkingv1.NetworkPolicy
+		deleted  []string
+		status   []v1alpha2.NetworkScopeWorkload
+		noStatus bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotNetworkScope": {
+			reason: "Scopes other than NetworkScopes should not be synced",
+			args:   args{s: &v1alpha2.HealthScope{}},
+			want:   want{err: errors.New(errNotNetworkScope), noStatus: true},
+		},
+		"Success": {
+			reason: "The pods of each workload should only accept ingress traffic from the pods of the workloads in the scope",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getChildren,
+					MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "net-function-fn")),
+				},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{deploy, unknown, cw},
+			},
+			want: want{
+				applied: []*networkingv1.NetworkPolicy{
+					np("net-deployment-web", webSelector),
+					np("net-containerizedworkload-api", apiSelector),
+				},
+				deleted: []string{"net-function-fn"},
+				status: []v1alpha2.NetworkScopeWorkload{
+					{
+						TargetWorkload: runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+						NetworkPolicy:  "net-deployment-web",
+					},
+					{
+						TargetWorkload: runtimev1alpha1.TypedReference{APIVersion: "example.com/v1", Kind: "Function", Name: "fn"},
+						Diagnosis:      `cannot determine the pods of Function "fn"`,
+					},
+					{
+						TargetWorkload: runtimev1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "ContainerizedWorkload", Name: "api"},
+						NetworkPolicy:  "net-containerizedworkload-api",
+					},
+				},
+			},
+		},
+		"GetChildResourceError": {
+			reason: "Errors getting the child resources of a workload should be returned",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{cw},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetChildResource, "Service", "api")},
+		},
+		"ApplyError": {
+			reason: "Errors applying a NetworkPolicy should be returned",
+			args: args{
+				apply:     errBoom,
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{deploy},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtApplyNetworkPolicy, "net-deployment-web"),
+				applied: []*networkingv1.NetworkPolicy{func() *networkingv1.NetworkPolicy {
+					np := np("net-deployment-web", webSelector)
+					np.Spec.Ingress[0].From = []networkingv1.NetworkPolicyPeer{{PodSelector: &webSelector}}
+					return np
+				}()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			c := tc.args.c
+			if mc, ok := c.(*test.MockClient); ok && mc.MockDelete != nil {
+				del := mc.MockDelete
+				mc.MockDelete = func(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+					got.deleted = append(got.deleted, obj.(metav1.Object).GetName())
+					return del(ctx, obj, opts...)
+				}
+			}
+			p := &Policy{
+				client: c,
+				applicator: resource.ApplyFn(func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+					got.applied = append(got.applied, o.(*networkingv1.NetworkPolicy))
+					return tc.args.apply
+				}),
+			}
+			got.err = p.Sync(context.Background(), tc.args.s, tc.args.workloads)
+			if ns, ok := tc.args.s.(*v1alpha2.NetworkScope); ok {
+				got.status = ns.Status.Workloads
+			} else {
+				got.noStatus = true
+			}
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	errBoom := errors.New("boom")
+	scope := &v1alpha2.NetworkScope{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "net"}}
+	ref := runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		want   error
+	}{
+		"Deleted": {
+			reason: "The NetworkPolicy of the workload should be deleted",
+			c: &test.MockClient{MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+				if obj.(metav1.Object).GetNamespace() != "ns" || obj.(metav1.Object).GetName() != "net-deployment-web" {
+					return errors.New("unexpected network policy")
+				}
+				return nil
+			}},
+		},
+		"NotFound": {
+			reason: "NetworkPolicies that don't exist should be ignored",
+			c:      &test.MockClient{MockDelete: test.NewMockDeleteFn(kerrors.NewNotFound(schema.GroupResource{}, "net-deployment-web"))},
+		},
+		"DeleteError": {
+			reason: "Errors deleting the NetworkPolicy should be returned",
+			c:      &test.MockClient{MockDelete: test.NewMockDeleteFn(errBoom)},
+			want:   errors.Wrapf(errBoom, errFmtDeleteNetworkPolicy, "net-deployment-web"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := NewPolicy(tc.c)
+			err := p.Remove(context.Background(), scope, ref)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Remove(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
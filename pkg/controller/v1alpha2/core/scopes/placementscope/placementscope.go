@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementscope
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	// pollInterval lets the status follow changes of the placement of the
+	// workloads, e.g. when their WorkloadDefinitions were changed
+	pollInterval = 1 * time.Minute
+)
+
+const (
+	errNotPlacementScope        = "scope is not a PlacementScope"
+	errFmtGetWorkloadDefinition = "cannot get the WorkloadDefinition of %s %q"
+)
+
+// Setup adds a controller that reconciles PlacementScopes.
+func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
+	name := "oam/" + strings.ToLower(v1alpha2.PlacementScopeGroupKind)
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	dc := client.Reader(mgr.GetClient())
+	if args.DefinitionClient != nil {
+		dc = args.DefinitionClient
+	}
+	r := scopes.NewReconciler(mgr, oam.ScopeKind(v1alpha2.PlacementScopeGroupVersionKind), NewPolicy(dc, dm, args.ClusterName),
+		scopes.WithLogger(l.WithValues("controller", name)),
+		scopes.WithRecorder(metrics.NewRecorder(name, event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		scopes.WithPollInterval(pollInterval),
+	)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.PlacementScope{}).
+		Complete(r)
+}
+
+// A Policy reports where the workloads in a PlacementScope are placed. The
+// placement is decided when ApplicationConfigurations apply the workloads.
+type Policy struct {
+	definitions client.Reader
+	dm          discoverymapper.DiscoveryMapper
+	cluster     string
+}
+
+// NewPolicy returns a Policy that reads the definitions of workloads using
+// the supplied reader, and reports them as placed in the named cluster.
+func NewPolicy(definitions client.Reader, dm discoverymapper.DiscoveryMapper, cluster string) *Policy {
+	return &Policy{definitions: definitions, dm: dm, cluster: cluster}
+}
+
+var _ scopes.Policy = &Policy{}
+
+// Add does nothing. Workloads are placed when they are applied.
+func (p *Policy) Add(_ context.Context, _ oam.Scope, _ *unstructured.Unstructured) error {
+	return nil
+}
+
+// Remove does nothing. Workloads that leave the scope keep their placement
+// until they are applied again.
+func (p *Policy) Remove(_ context.Context, _ oam.Scope, _ runtimev1alpha1.TypedReference) error {
+	return nil
+}
+
+// Sync records the cluster and zones the supplied workloads are placed in in
+// the status of the supplied PlacementScope. Only workloads that exist in
+// this cluster are supplied. Their pods are placed in the zones of the scope
+// only if their WorkloadDefinitions have a podSpecPath.
+func (p *Policy) Sync(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured) error {
+	ps, ok := s.(*v1alpha2.PlacementScope)
+	if !ok {
+		return errors.New(errNotPlacementScope)
+	}
+
+	zones := make([]string, len(ps.Spec.Zones))
+	for i, z := range ps.Spec.Zones {
+		zones[i] = z.Name
+	}
+	status := make([]v1alpha2.PlacementScopeWorkload, len(workloads))
+	for i, wl := range workloads {
+		status[i].TargetWorkload = runtimev1alpha1.TypedReference{
+			APIVersion: wl.GetAPIVersion(),
+			Kind:       wl.GetKind(),
+			Name:       wl.GetName(),
+			UID:        wl.GetUID(),
+		}
+		status[i].Cluster = p.cluster
+		if len(zones) == 0 {
+			continue
+		}
+		wd, err := util.FetchWorkloadDefinition(ctx, p.definitions, p.dm, wl)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetWorkloadDefinition, wl.GetKind(), wl.GetName())
+		}
+		if wd.Spec.PodSpecPath != "" {
+			status[i].Zones = zones
+		}
+	}
+	ps.Status.Workloads = status
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementscope
+
+import (
+	"context"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestSync(t *testing.T) {
+	errBoom := errors.New("boom")
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	scope := &v1alpha2.PlacementScope{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "spread"},
+		Spec: v1alpha2.PlacementScopeSpec{
+			Zones: []v1alpha2.PlacementZone{{Name: "zone-a", Weight: 80}, {Name: "zone-b", Weight: 20}},
+		},
+	}
+	web := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+	}}
+	webRef := runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+
+	getDefinition := func(podSpecPath string) test.MockGetFn {
+		return func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+			wd, ok := obj.(*v1alpha2.WorkloadDefinition)
+			if !ok {
+				return errBoom
+			}
+			wd.Spec.PodSpecPath = podSpecPath
+			return nil
+		}
+	}
+
+	type args struct {
+		c         client.Client
+		s         oam.Scope
+		workloads []*unstructured.Unstructured
+	}
+	type want struct {
+		err    error
+		status v1alpha2.PlacementScopeStatus
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotPlacementScope": {
+			reason: "Scopes other than PlacementScopes should not be synced",
+			args:   args{s: &v1alpha2.HealthScope{}},
+			want:   want{err: errors.New(errNotPlacementScope)},
+		},
+		"PlacedInZones": {
+			reason: "Workloads with a pod spec should be reported as placed in the cluster and zones of the scope",
+			args: args{
+				c:         &test.MockClient{MockGet: getDefinition("spec.template.spec")},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.PlacementScopeStatus{Workloads: []v1alpha2.PlacementScopeWorkload{
+					{TargetWorkload: webRef, Cluster: "east", Zones: []string{"zone-a", "zone-b"}},
+				}},
+			},
+		},
+		"NoZones": {
+			reason: "Workloads of scopes without zones should only be reported as placed in the cluster",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				s:         &v1alpha2.PlacementScope{},
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.PlacementScopeStatus{Workloads: []v1alpha2.PlacementScopeWorkload{
+					{TargetWorkload: webRef, Cluster: "east"},
+				}},
+			},
+		},
+		"NoPodSpecPath": {
+			reason: "Workloads whose pod spec can't be determined should not be reported as placed in zones",
+			args: args{
+				c:         &test.MockClient{MockGet: getDefinition("")},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.PlacementScopeStatus{Workloads: []v1alpha2.PlacementScopeWorkload{
+					{TargetWorkload: webRef, Cluster: "east"},
+				}},
+			},
+		},
+		"NoWorkloadDefinition": {
+			reason: "Workloads without a WorkloadDefinition should not be reported as placed in zones",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.PlacementScopeStatus{Workloads: []v1alpha2.PlacementScopeWorkload{
+					{TargetWorkload: webRef, Cluster: "east"},
+				}},
+			},
+		},
+		"GetWorkloadDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition of a workload should be returned",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetWorkloadDefinition, "Deployment", "web")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dm := mock.NewMockDiscoveryMapper()
+			dm.MockRESTMapping = mock.NewMockRESTMapping("deployments")
+			p := NewPolicy(tc.args.c, dm, "east")
+			err := p.Sync(context.Background(), tc.args.s, tc.args.workloads)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			ps, ok := tc.args.s.(*v1alpha2.PlacementScope)
+			if !ok || err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.status, ps.Status); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scopes
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+	shortWait        = 30 * time.Second
+
+	// workloadsFinalizer lets the Reconciler remove the workloads of a scope
+	// before the scope is deleted.
+	workloadsFinalizer = "workloads.scope.core.oam.dev"
+)
+
+// Reconcile error strings.
+const (
+	errGetScope          = "cannot get scope"
+	errUpdateScope       = "cannot update scope"
+	errUpdateScopeStatus = "cannot update scope status"
+	errDecodeWorkloads   = "cannot decode the workloads recorded on scope"
+	errRecordWorkloads   = "cannot record the workloads of scope"
+	errSyncScope         = "cannot sync scope"
+	errFmtGetWorkload    = "cannot get workload %s %q"
+	errFmtAddWorkload    = "cannot add workload %s %q to scope"
+	errFmtRemoveWorkload = "cannot remove workload %s %q from scope"
+)
+
+// Reconcile event reasons.
+const (
+	reasonAddWorkload            = "AddedWorkload"
+	reasonRemoveWorkload         = "RemovedWorkload"
+	reasonSyncScope              = "SyncedScope"
+	reasonCannotReconcileMembers = "CannotReconcileWorkloads"
+	reasonCannotSyncScope        = "CannotSyncScope"
+)
+
+// A Policy implements the logic of a kind of scope. The Reconciler calls it as
+// workloads join and leave scopes of the kind. Add and Remove must be
+// idempotent, because they are retried if the Reconciler fails to record
+// their effect.
+type Policy interface {
+	// Add the supplied workload to the supplied scope. It is called for each
+	// workload the scope references that it has not been added to yet.
+	Add(ctx context.Context, s oam.Scope, wl *unstructured.Unstructured) error
+
+	// Remove the referenced workload from the supplied scope. It is called
+	// for each workload that was added to the scope but is no longer
+	// referenced by it, and for all workloads of a deleted scope. The
+	// workload may no longer exist.
+	Remove(ctx context.Context, s oam.Scope, ref runtimev1alpha1.TypedReference) error
+
+	// Sync the supplied scope, e.g. its status, with the supplied workloads it
+	// references. It is called whenever the scope is reconciled.
+	Sync(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured) error
+}
+
+// PolicyFns implement a Policy by calling its functions. Functions that are
+// nil do nothing.
+type PolicyFns struct {
+	AddFn    func(ctx context.Context, s oam.Scope, wl *unstructured.Unstructured) error
+	RemoveFn func(ctx context.Context, s oam.Scope, ref runtimev1alpha1.TypedReference) error
+	SyncFn   func(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured) error
+}
+
+// Add the supplied workload to the supplied scope.
+func (fns PolicyFns) Add(ctx context.Context, s oam.Scope, wl *unstructured.Unstructured) error {
+	if fns.AddFn == nil {
+		return nil
+	}
+	return fns.AddFn(ctx, s, wl)
+}
+
+// Remove the referenced workload from the supplied scope.
+func (fns PolicyFns) Remove(ctx context.Context, s oam.Scope, ref runtimev1alpha1.TypedReference) error {
+	if fns.RemoveFn == nil {
+		return nil
+	}
+	return fns.RemoveFn(ctx, s, ref)
+}
+
+// Sync the supplied scope with the supplied workloads.
+func (fns PolicyFns) Sync(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured) error {
+	if fns.SyncFn == nil {
+		return nil
+	}
+	return fns.SyncFn(ctx, s, workloads)
+}
+
+// A Reconciler reconciles a kind of OAM scope. It tracks the workloads the
+// scopes reference, calls a Policy as workloads join and leave them, and
+// reports the outcome in the Synced condition of the scopes, so that scope
+// authors only implement the Policy.
+type Reconciler struct {
+	client   client.Client
+	newScope func() oam.Scope
+	policy   Policy
+
+	log          logging.Logger
+	record       event.Recorder
+	pollInterval time.Duration
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithPollInterval specifies how long the Reconciler should wait before
+// syncing a scope again, e.g. to observe changes of its workloads. Scopes are
+// only reconciled when they change if the interval is zero, which is the
+// default.
+func WithPollInterval(after time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pollInterval = after
+	}
+}
+
+// NewReconciler returns a Reconciler that reconciles scopes of the supplied
+// kind using the supplied Policy. The kind must be registered with the scheme
+// of the supplied manager.
+func NewReconciler(m ctrl.Manager, of oam.ScopeKind, p Policy, o ...ReconcilerOption) *Reconciler {
+	ns := func() oam.Scope {
+		return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(oam.Scope)
+	}
+
+	// panic early if we've been asked to reconcile a kind that is not a scope
+	_ = ns()
+
+	r := &Reconciler{
+		client:   m.GetClient(),
+		newScope: ns,
+		policy:   p,
+		log:      logging.NewNopLogger(),
+		record:   event.NewNopRecorder(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile a scope by adding the workloads it references to it, removing the
+// workloads it no longer references from it, and syncing it.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	s := r.newScope()
+	if err := r.client.Get(ctx, req.NamespacedName, s); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetScope)
+	}
+	log = log.WithValues("uid", s.GetUID(), "version", s.GetResourceVersion())
+
+	added, err := AddedWorkloads(s)
+	if err != nil {
+		log.Debug("Cannot decode recorded workloads", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(s, event.Warning(reasonCannotReconcileMembers, err))
+		s.SetConditions(runtimev1alpha1.ReconcileError(errors.Wrap(err, errDecodeWorkloads)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeStatus)
+	}
+
+	if meta.WasDeleted(s) {
+		if _, err := r.remove(ctx, s, added, nil); err != nil {
+			log.Debug("Cannot remove workloads of deleted scope", "error", err, "requeue-after", time.Now().Add(shortWait))
+			r.record.Event(s, event.Warning(reasonCannotReconcileMembers, err))
+			s.SetConditions(runtimev1alpha1.ReconcileError(err))
+			return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeStatus)
+		}
+		meta.RemoveFinalizer(s, workloadsFinalizer)
+		return reconcile.Result{}, errors.Wrap(r.client.Update(ctx, s), errUpdateScope)
+	}
+
+	workloads, err := r.fetchWorkloads(ctx, s)
+	members := added
+	if err == nil {
+		members, err = r.add(ctx, s, workloads, members)
+	}
+	if err == nil {
+		members, err = r.remove(ctx, s, members, workloads)
+	}
+
+	// record the workloads that were added or removed even if others could
+	// not be, so that they are not added or removed again
+	if rerr := r.recordWorkloads(ctx, s, members); rerr != nil {
+		log.Debug("Cannot record workloads", "error", rerr, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(s, event.Warning(reasonCannotReconcileMembers, rerr))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(rerr, errRecordWorkloads)
+	}
+	if err != nil {
+		log.Debug("Cannot reconcile workloads", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(s, event.Warning(reasonCannotReconcileMembers, err))
+		s.SetConditions(runtimev1alpha1.ReconcileError(err))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeStatus)
+	}
+
+	if err := r.policy.Sync(ctx, s, workloads); err != nil {
+		log.Debug("Cannot sync scope", "error", err, "requeue-after", time.Now().Add(shortWait))
+		r.record.Event(s, event.Warning(reasonCannotSyncScope, err))
+		s.SetConditions(runtimev1alpha1.ReconcileError(errors.Wrap(err, errSyncScope)))
+		return reconcile.Result{RequeueAfter: shortWait}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeStatus)
+	}
+	log.Debug("Successfully synced scope", "workloads", len(workloads))
+	r.record.Event(s, event.Normal(reasonSyncScope, "Successfully synced scope"))
+
+	s.SetConditions(runtimev1alpha1.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateScopeStatus)
+}
+
+// fetchWorkloads returns the workloads the supplied scope references that
+// exist. Workloads that don't exist yet are added once they do.
+func (r *Reconciler) fetchWorkloads(ctx context.Context, s oam.Scope) ([]*unstructured.Unstructured, error) {
+	refs := s.GetWorkloadReferences()
+	workloads := make([]*unstructured.Unstructured, 0, len(refs))
+	for _, ref := range refs {
+		wl := &unstructured.Unstructured{}
+		wl.SetGroupVersionKind(ref.GroupVersionKind())
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: s.GetNamespace(), Name: ref.Name}, wl)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetWorkload, ref.Kind, ref.Name)
+		}
+		workloads = append(workloads, wl)
+	}
+	return workloads, nil
+}
+
+// add the supplied workloads that are not members yet to the supplied scope.
+// It returns the members of the scope afterwards.
+func (r *Reconciler) add(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured,
+	members []runtimev1alpha1.TypedReference) ([]runtimev1alpha1.TypedReference, error) {
+	added := workloadKeys(members)
+	for _, wl := range workloads {
+		ref := referenceTo(wl)
+		if added[util.WorkloadReferenceIndexKey(ref)] {
+			continue
+		}
+		if err := r.policy.Add(ctx, s, wl); err != nil {
+			return members, errors.Wrapf(err, errFmtAddWorkload, ref.Kind, ref.Name)
+		}
+		members = append(members, ref)
+		added[util.WorkloadReferenceIndexKey(ref)] = true
+		r.record.Event(s, event.Normal(reasonAddWorkload, "Successfully added workload", "kind", ref.Kind, "name", ref.Name))
+	}
+	return members, nil
+}
+
+// remove the supplied members that are not among the supplied workloads from
+// the supplied scope. It returns the members of the scope afterwards.
+func (r *Reconciler) remove(ctx context.Context, s oam.Scope, members []runtimev1alpha1.TypedReference,
+	workloads []*unstructured.Unstructured) ([]runtimev1alpha1.TypedReference, error) {
+	current := workloadKeys(referencesTo(workloads))
+	remaining := make([]runtimev1alpha1.TypedReference, 0, len(members))
+	for i, ref := range members {
+		if current[util.WorkloadReferenceIndexKey(ref)] {
+			remaining = append(remaining, ref)
+			continue
+		}
+		if err := r.policy.Remove(ctx, s, ref); err != nil {
+			return append(remaining, members[i:]...), errors.Wrapf(err, errFmtRemoveWorkload, ref.Kind, ref.Name)
+		}
+		r.record.Event(s, event.Normal(reasonRemoveWorkload, "Successfully removed workload", "kind", ref.Kind, "name", ref.Name))
+	}
+	return remaining, nil
+}
+
+// recordWorkloads records the supplied workloads as added to the supplied
+// scope, which is finalized so that they can be removed when it is deleted.
+// The scope is only updated if the record changes.
+func (r *Reconciler) recordWorkloads(ctx context.Context, s oam.Scope, members []runtimev1alpha1.TypedReference) error {
+	if members == nil {
+		members = []runtimev1alpha1.TypedReference{}
+	}
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	if meta.FinalizerExists(s, workloadsFinalizer) && s.GetAnnotations()[oam.AnnotationScopeWorkloads] == string(raw) {
+		return nil
+	}
+	meta.AddFinalizer(s, workloadsFinalizer)
+	meta.AddAnnotations(s, map[string]string{oam.AnnotationScopeWorkloads: string(raw)})
+	return r.client.Update(ctx, s)
+}
+
+// AddedWorkloads returns the workloads the Reconciler has added to the
+// supplied scope.
+func AddedWorkloads(s oam.Scope) ([]runtimev1alpha1.TypedReference, error) {
+	raw, ok := s.GetAnnotations()[oam.AnnotationScopeWorkloads]
+	if !ok {
+		return nil, nil
+	}
+	var refs []runtimev1alpha1.TypedReference
+	err := json.Unmarshal([]byte(raw), &refs)
+	return refs, err
+}
+
+func referenceTo(wl *unstructured.Unstructured) runtimev1alpha1.TypedReference {
+	return runtimev1alpha1.TypedReference{
+		APIVersion: wl.GetAPIVersion(),
+		Kind:       wl.GetKind(),
+		Name:       wl.GetName(),
+		UID:        wl.GetUID(),
+	}
+}
+
+func referencesTo(workloads []*unstructured.Unstructured) []runtimev1alpha1.TypedReference {
+	refs := make([]runtimev1alpha1.TypedReference, 0, len(workloads))
+	for _, wl := range workloads {
+		refs = append(refs, referenceTo(wl))
+	}
+	return refs
+}
+
+// workloadKeys returns the set of the supplied references to workloads, keyed
+// by util.WorkloadReferenceIndexKey so that they match regardless of their
+// version.
+func workloadKeys(refs []runtimev1alpha1.TypedReference) map[string]bool {
+	keys := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		keys[util.WorkloadReferenceIndexKey(ref)] = true
+	}
+	return keys
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scopes
+
+import (
+	"context"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "health"}}
+	now := metav1.Now()
+
+	scheme := runtime.NewScheme()
+	if err := core.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := func(name string) runtimev1alpha1.TypedReference {
+		return runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: name}
+	}
+	scope := func(deleted bool, recorded string, refs ...runtimev1alpha1.TypedReference) *v1alpha2.HealthScope {
+		hs := &v1alpha2.HealthScope{}
+		hs.SetNamespace("ns")
+		hs.SetName("health")
+		if recorded != "" {
+			hs.SetAnnotations(map[string]string{oam.AnnotationScopeWorkloads: recorded})
+			hs.SetFinalizers([]string{workloadsFinalizer})
+		}
+		if deleted {
+			hs.SetDeletionTimestamp(&now)
+		}
+		hs.Spec.WorkloadReferences = refs
+		return hs
+	}
+	// get returns the supplied scope, and the supplied workloads as existing
+	get := func(hs *v1alpha2.HealthScope, existing ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.HealthScope:
+				hs.DeepCopyInto(o)
+				return nil
+			case *unstructured.Unstructured:
+				for _, name := range existing {
+					if name == key.Name {
+						o.SetName(name)
+						return nil
+					}
+				}
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+
+	type args struct {
+		c client.Client
+		p PolicyFns
+	}
+	type want struct {
+		result     reconcile.Result
+		err        error
+		added      []string
+		removed    []string
+		recorded   string
+		finalizers []string
+		condition  *runtimev1alpha1.Condition
+	}
+
+	synced := runtimev1alpha1.ReconcileSuccess()
+	failed := func(err error) *runtimev1alpha1.Condition {
+		c := runtimev1alpha1.ReconcileError(err)
+		return &c
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ScopeNotFound": {
+			reason: "Deleted scopes should not be requeued",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "health"))},
+			},
+		},
+		"GetScopeError": {
+			reason: "Errors getting the scope should be returned",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetScope)},
+		},
+		"AddWorkloads": {
+			reason: "Existing workloads that were not added to the scope should be added and recorded",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          get(scope(false, "", ref("web"), ref("db")), "web"),
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+			},
+			want: want{
+				added:      []string{"web"},
+				recorded:   `[{"apiVersion":"apps/v1","kind":"Deployment","name":"web"}]`,
+				finalizers: []string{workloadsFinalizer},
+				condition:  &synced,
+			},
+		},
+		"RemoveWorkloads": {
+			reason: "Recorded workloads that are no longer referenced by the scope should be removed",
+			args: args{
+				c: &test.MockClient{
+					MockGet: get(scope(false, `[{"apiVersion":"apps/v1","kind":"Deployment","name":"web"},{"apiVersion":"apps/v1","kind":"Deployment","name":"old"}]`,
+						ref("web")), "web", "old"),
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+			},
+			want: want{
+				removed:    []string{"old"},
+				recorded:   `[{"apiVersion":"apps/v1","kind":"Deployment","name":"web"}]`,
+				finalizers: []string{workloadsFinalizer},
+				condition:  &synced,
+			},
+		},
+		"WorkloadsUnchanged": {
+			reason: "A scope whose workloads did not change should only be synced",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          get(scope(false, `[{"apiVersion":"apps/v1","kind":"Deployment","name":"web"}]`, ref("web")), "web"),
+					MockUpdate:       test.NewMockUpdateFn(errBoom),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+			},
+			want: want{condition: &synced},
+		},
+		"AddWorkloadError": {
+			reason: "Workloads that could not be added should not be recorded",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          get(scope(false, "", ref("web")), "web"),
+					MockUpdate:       test.NewMockUpdateFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				p: PolicyFns{AddFn: func(context.Context, oam.Scope, *unstructured.Unstructured) error { return errBoom }},
+			},
+			want: want{
+				result:     reconcile.Result{RequeueAfter: shortWait},
+				recorded:   `[]`,
+				finalizers: []string{workloadsFinalizer},
+				condition:  failed(errors.Wrapf(errBoom, errFmtAddWorkload, "Deployment", "web")),
+			},
+		},
+		"RecordWorkloadsError": {
+			reason: "Errors recording the workloads of the scope should be returned",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    get(scope(false, "", ref("web")), "web"),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+			},
+			want: want{
+				result:     reconcile.Result{RequeueAfter: shortWait},
+				err:        errors.Wrap(errBoom, errRecordWorkloads),
+				added:      []string{"web"},
+				recorded:   `[{"apiVersion":"apps/v1","kind":"Deployment","name":"web"}]`,
+				finalizers: []string{workloadsFinalizer},
+			},
+		},
+		"SyncError": {
+			reason: "Errors syncing the scope should be reported in its status",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          get(scope(false, `[]`)),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				p: PolicyFns{SyncFn: func(context.Context, oam.Scope, []*unstructured.Unstructured) error { return errBoom }},
+			},
+			want: want{
+				result:    reconcile.Result{RequeueAfter: shortWait},
+				condition: failed(errors.Wrap(errBoom, errSyncScope)),
+			},
+		},
+		"ScopeDeleted": {
+			reason: "All workloads of a deleted scope should be removed before it is finalized",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    get(scope(true, `[{"apiVersion":"apps/v1","kind":"Deployment","name":"web"}]`, ref("web")), "web"),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			want: want{
+				removed:    []string{"web"},
+				recorded:   `[{"apiVersion":"apps/v1","kind":"Deployment","name":"web"}]`,
+				finalizers: []string{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := want{}
+			if mc, ok := tc.args.c.(*test.MockClient); ok {
+				if update := mc.MockUpdate; update != nil {
+					mc.MockUpdate = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+						hs := obj.(*v1alpha2.HealthScope)
+						got.recorded = hs.GetAnnotations()[oam.AnnotationScopeWorkloads]
+						got.finalizers = hs.GetFinalizers()
+						return update(ctx, obj, opts...)
+					}
+				}
+				if update := mc.MockStatusUpdate; update != nil {
+					mc.MockStatusUpdate = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+						c := obj.(*v1alpha2.HealthScope).GetCondition(runtimev1alpha1.TypeSynced)
+						got.condition = &c
+						return update(ctx, obj, opts...)
+					}
+				}
+			}
+			p := tc.args.p
+			if p.AddFn == nil {
+				p.AddFn = func(_ context.Context, _ oam.Scope, wl *unstructured.Unstructured) error {
+					got.added = append(got.added, wl.GetName())
+					return nil
+				}
+			}
+			p.RemoveFn = func(_ context.Context, _ oam.Scope, ref runtimev1alpha1.TypedReference) error {
+				got.removed = append(got.removed, ref.Name)
+				return nil
+			}
+
+			m := &mock.Manager{Client: tc.args.c, Scheme: scheme}
+			r := NewReconciler(m, oam.ScopeKind(v1alpha2.HealthScopeGroupVersionKind), p)
+			got.result, got.err = r.Reconcile(req)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), test.EquateConditions(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
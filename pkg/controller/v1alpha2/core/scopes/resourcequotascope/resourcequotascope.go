@@ -0,0 +1,141 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcequotascope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/quota"
+)
+
+const (
+	// pollInterval lets the utilization follow changes of the workloads,
+	// e.g. of their replicas
+	pollInterval = 1 * time.Minute
+)
+
+const (
+	errNotResourceQuotaScope = "scope is not a ResourceQuotaScope"
+	errFmtWorkloadUsage      = "cannot compute the resources used by %s %q"
+
+	reasonFmtNoPods = "cannot determine the pods of %s %q"
+)
+
+// Setup adds a controller that reconciles ResourceQuotaScopes.
+func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
+	name := "oam/" + strings.ToLower(v1alpha2.ResourceQuotaScopeGroupKind)
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	c := client.Reader(mgr.GetClient())
+	if args.DefinitionClient != nil {
+		c = args.DefinitionClient
+	}
+	r := scopes.NewReconciler(mgr, oam.ScopeKind(v1alpha2.ResourceQuotaScopeGroupVersionKind), NewPolicy(c, dm),
+		scopes.WithLogger(l.WithValues("controller", name)),
+		scopes.WithRecorder(metrics.NewRecorder(name, event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		scopes.WithPollInterval(pollInterval),
+	)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.ResourceQuotaScope{}).
+		Complete(r)
+}
+
+// A Policy reports the compute resources the workloads in a
+// ResourceQuotaScope use, and which hard limits of the scope they exceed.
+// The limits are enforced when ApplicationConfigurations are admitted.
+type Policy struct {
+	client client.Reader
+	dm     discoverymapper.DiscoveryMapper
+}
+
+// NewPolicy returns a Policy that reads workloads, their definitions and
+// their child resources using the supplied client.
+func NewPolicy(c client.Reader, dm discoverymapper.DiscoveryMapper) *Policy {
+	return &Policy{client: c, dm: dm}
+}
+
+var _ scopes.Policy = &Policy{}
+
+// Add does nothing. The utilization of the scope is computed when it is
+// synced.
+func (p *Policy) Add(_ context.Context, _ oam.Scope, _ *unstructured.Unstructured) error {
+	return nil
+}
+
+// Remove does nothing. The utilization of the scope is computed when it is
+// synced.
+func (p *Policy) Remove(_ context.Context, _ oam.Scope, _ runtimev1alpha1.TypedReference) error {
+	return nil
+}
+
+// Sync records the compute resources the supplied workloads use in the
+// status of the supplied ResourceQuotaScope.
+func (p *Policy) Sync(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured) error {
+	rs, ok := s.(*v1alpha2.ResourceQuotaScope)
+	if !ok {
+		return errors.New(errNotResourceQuotaScope)
+	}
+
+	used := quota.Zero()
+	status := make([]v1alpha2.ResourceQuotaScopeWorkload, len(workloads))
+	for i, wl := range workloads {
+		ref := runtimev1alpha1.TypedReference{
+			APIVersion: wl.GetAPIVersion(),
+			Kind:       wl.GetKind(),
+			Name:       wl.GetName(),
+			UID:        wl.GetUID(),
+		}
+		status[i].TargetWorkload = ref
+		u, err := quota.WorkloadUsage(ctx, p.client, p.dm, wl)
+		if err != nil {
+			return errors.Wrapf(err, errFmtWorkloadUsage, ref.Kind, ref.Name)
+		}
+		if u == nil {
+			status[i].Diagnosis = fmt.Sprintf(reasonFmtNoPods, ref.Kind, ref.Name)
+			continue
+		}
+		status[i].Used = u
+		used = quota.Add(used, u)
+	}
+
+	rs.Status.Hard = quota.Normalize(rs.Spec.Hard)
+	rs.Status.Used = used
+	rs.Status.Exceeded = quota.Exceeded(rs.Status.Hard, used)
+	rs.Status.Workloads = status
+	return nil
+}
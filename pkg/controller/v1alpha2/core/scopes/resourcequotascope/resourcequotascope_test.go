@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourcequotascope
+
+import (
+	"context"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/quota"
+)
+
+func TestSync(t *testing.T) {
+	errBoom := errors.New("boom")
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	scope := &v1alpha2.ResourceQuotaScope{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "budget"},
+		Spec: v1alpha2.ResourceQuotaScopeSpec{
+			Hard: corev1.ResourceList{
+				corev1.ResourceCPU:          resource.MustParse("1"),
+				corev1.ResourceLimitsMemory: resource.MustParse("1Gi"),
+			},
+		},
+	}
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name": "web",
+							"resources": map[string]interface{}{
+								"requests": map[string]interface{}{"cpu": "500m", "memory": "128Mi"},
+								"limits":   map[string]interface{}{"memory": "256Mi"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	unknown := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Function",
+		"metadata":   map[string]interface{}{"namespace": "ns", "name": "fn"},
+	}}
+
+	webUsed := corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    resource.MustParse("1500m"),
+		corev1.ResourceRequestsMemory: resource.MustParse("384Mi"),
+		corev1.ResourceLimitsCPU:      resource.MustParse("0"),
+		corev1.ResourceLimitsMemory:   resource.MustParse("768Mi"),
+	}
+
+	type args struct {
+		c         client.Reader
+		s         oam.Scope
+		workloads []*unstructured.Unstructured
+	}
+	type want struct {
+		err    error
+		status v1alpha2.ResourceQuotaScopeStatus
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotResourceQuotaScope": {
+			reason: "Scopes other than ResourceQuotaScopes should not be synced",
+			args:   args{s: &v1alpha2.HealthScope{}},
+			want:   want{err: errors.New(errNotResourceQuotaScope)},
+		},
+		"Success": {
+			reason: "The resources used by the workloads should be summed, and compared with the hard limits",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{deploy, unknown},
+			},
+			want: want{status: v1alpha2.ResourceQuotaScopeStatus{
+				Hard: corev1.ResourceList{
+					corev1.ResourceRequestsCPU:  resource.MustParse("1"),
+					corev1.ResourceLimitsMemory: resource.MustParse("1Gi"),
+				},
+				Used:     webUsed,
+				Exceeded: []corev1.ResourceName{corev1.ResourceRequestsCPU},
+				Workloads: []v1alpha2.ResourceQuotaScopeWorkload{
+					{
+						TargetWorkload: runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+						Used:           webUsed,
+					},
+					{
+						TargetWorkload: runtimev1alpha1.TypedReference{APIVersion: "example.com/v1", Kind: "Function", Name: "fn"},
+						Diagnosis:      `cannot determine the pods of Function "fn"`,
+					},
+				},
+			}},
+		},
+		"Empty": {
+			reason: "Scopes without workloads should use no resources",
+			args: args{
+				s: scope.DeepCopy(),
+			},
+			want: want{status: v1alpha2.ResourceQuotaScopeStatus{
+				Hard: corev1.ResourceList{
+					corev1.ResourceRequestsCPU:  resource.MustParse("1"),
+					corev1.ResourceLimitsMemory: resource.MustParse("1Gi"),
+				},
+				Used:      quota.Zero(),
+				Workloads: []v1alpha2.ResourceQuotaScopeWorkload{},
+			}},
+		},
+		"WorkloadUsageError": {
+			reason: "Errors computing the resources used by a workload should be returned",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{deploy},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errBoom, "cannot get the pod spec of %s %q", "Deployment", "web"),
+					errFmtWorkloadUsage, "Deployment", "web"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dm := mock.NewMockDiscoveryMapper()
+			dm.MockRESTMapping = mock.NewMockRESTMapping("deployments")
+			p := NewPolicy(tc.args.c, dm)
+			err := p.Sync(context.Background(), tc.args.s, tc.args.workloads)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			rs, ok := tc.args.s.(*v1alpha2.ResourceQuotaScope)
+			if !ok || err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.status, rs.Status); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
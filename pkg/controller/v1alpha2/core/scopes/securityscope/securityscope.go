@@ -0,0 +1,297 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securityscope
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	// pollInterval lets the scope enforce its profile on workloads whose
+	// pod templates were changed, e.g. when their components were updated
+	pollInterval = 1 * time.Minute
+)
+
+const (
+	// capabilityAll drops all capabilities
+	capabilityAll = "ALL"
+
+	// templateSpecSuffix is the suffix of paths to the spec of pod templates
+	templateSpecSuffix = ".spec"
+)
+
+const (
+	errNotSecurityScope         = "scope is not a SecurityScope"
+	errFmtGetWorkloadDefinition = "cannot get the WorkloadDefinition of %s %q"
+	errFmtSecureWorkload        = "cannot apply the security profile to %s %q"
+	errFmtPatchWorkload         = "cannot patch %s %q"
+	errFmtGetPodTemplate        = "cannot get the pod template at %q"
+
+	reasonNoPodSpec          = "the pod spec of the workload can't be determined, because its WorkloadDefinition has no podSpecPath"
+	reasonNoPodTemplate      = "the seccomp profile can't be set, because the pod spec of the workload is not part of a pod template"
+	reasonPodRunsAsRoot      = "the pod runs as user 0"
+	reasonFmtContainerAsRoot = "container %q runs as user 0"
+	reasonFmtAddsCapability  = "container %q adds capability %s"
+)
+
+// Setup adds a controller that reconciles SecurityScopes.
+func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
+	name := "oam/" + strings.ToLower(v1alpha2.SecurityScopeGroupKind)
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	dc := client.Reader(mgr.GetClient())
+	if args.DefinitionClient != nil {
+		dc = args.DefinitionClient
+	}
+	r := scopes.NewReconciler(mgr, oam.ScopeKind(v1alpha2.SecurityScopeGroupVersionKind), NewPolicy(mgr.GetClient(), dc, dm),
+		scopes.WithLogger(l.WithValues("controller", name)),
+		scopes.WithRecorder(metrics.NewRecorder(name, event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))),
+		scopes.WithPollInterval(pollInterval),
+	)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.SecurityScope{}).
+		Complete(r)
+}
+
+// A Policy enforces the security profile of a SecurityScope on the workloads
+// in it, by patching the pod spec at the podSpecPath of their
+// WorkloadDefinitions.
+type Policy struct {
+	client      client.Client
+	definitions client.Reader
+	dm          discoverymapper.DiscoveryMapper
+}
+
+// NewPolicy returns a Policy that patches workloads using the supplied
+// client, and reads their definitions using the supplied reader.
+func NewPolicy(c client.Client, definitions client.Reader, dm discoverymapper.DiscoveryMapper) *Policy {
+	return &Policy{client: c, definitions: definitions, dm: dm}
+}
+
+var _ scopes.Policy = &Policy{}
+
+// Add does nothing. The security profile is enforced when the scope is
+// synced.
+func (p *Policy) Add(_ context.Context, _ oam.Scope, _ *unstructured.Unstructured) error {
+	return nil
+}
+
+// Remove does nothing. The security settings of workloads that leave the
+// scope are kept, because it can't be told whether they were set by the
+// scope.
+func (p *Policy) Remove(_ context.Context, _ oam.Scope, _ runtimev1alpha1.TypedReference) error {
+	return nil
+}
+
+// Sync enforces the security profile of the supplied SecurityScope on the
+// supplied workloads, and records whether they comply with it in the status
+// of the scope.
+func (p *Policy) Sync(ctx context.Context, s oam.Scope, workloads []*unstructured.Unstructured) error {
+	ss, ok := s.(*v1alpha2.SecurityScope)
+	if !ok {
+		return errors.New(errNotSecurityScope)
+	}
+
+	status := make([]v1alpha2.SecurityScopeWorkload, len(workloads))
+	for i, wl := range workloads {
+		status[i].TargetWorkload = runtimev1alpha1.TypedReference{
+			APIVersion: wl.GetAPIVersion(),
+			Kind:       wl.GetKind(),
+			Name:       wl.GetName(),
+			UID:        wl.GetUID(),
+		}
+		violations, err := p.enforce(ctx, ss.Spec, wl)
+		if err != nil {
+			return err
+		}
+		status[i].Compliant = len(violations) == 0
+		status[i].Diagnosis = strings.Join(violations, "; ")
+	}
+	ss.Status.Workloads = status
+	return nil
+}
+
+// enforce patches the pod spec of the supplied workload to comply with the
+// supplied profile, and returns the violations of the profile that can't be
+// fixed by patching it.
+func (p *Policy) enforce(ctx context.Context, profile v1alpha2.SecurityScopeSpec, wl *unstructured.Unstructured) ([]string, error) {
+	wd, err := util.FetchWorkloadDefinition(ctx, p.definitions, p.dm, wl)
+	if kerrors.IsNotFound(err) {
+		return []string{reasonNoPodSpec}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetWorkloadDefinition, wl.GetKind(), wl.GetName())
+	}
+	if wd.Spec.PodSpecPath == "" {
+		return []string{reasonNoPodSpec}, nil
+	}
+	secured := wl.DeepCopy()
+	violations, err := Secure(secured, wd.Spec.PodSpecPath, profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtSecureWorkload, wl.GetKind(), wl.GetName())
+	}
+	if reflect.DeepEqual(secured.Object, wl.Object) {
+		return violations, nil
+	}
+	if err := p.client.Patch(ctx, secured, client.MergeFrom(wl)); err != nil {
+		return nil, errors.Wrapf(err, errFmtPatchWorkload, wl.GetKind(), wl.GetName())
+	}
+	return violations, nil
+}
+
+// Secure sets the security settings the supplied profile requires in the pod
+// spec at the supplied path of the supplied workload:
+//
+//  1. runAsNonRoot of the pod, and of containers that don't run as non-root.
+//  2. the capabilities containers must drop.
+//  3. the seccomp profile annotation of the pod template, if the pod spec is
+//     the spec of a pod template.
+//
+// It returns the violations of the profile it can't fix, i.e. pods and
+// containers that run as user 0, containers that add capabilities the profile
+// drops, and pod specs without a pod template for the seccomp profile.
+func Secure(wl *unstructured.Unstructured, podSpecPath string, profile v1alpha2.SecurityScopeSpec) ([]string, error) {
+	paved, err := util.PavePodSpec(wl, podSpecPath)
+	if err != nil {
+		return nil, err
+	}
+	podSpec := paved.UnstructuredContent()
+
+	var violations []string
+	if profile.RunAsNonRoot {
+		if err := unstructured.SetNestedField(podSpec, true, "securityContext", "runAsNonRoot"); err != nil {
+			return nil, err
+		}
+		if uid, found, _ := unstructured.NestedInt64(podSpec, "securityContext", "runAsUser"); found && uid == 0 {
+			violations = append(violations, reasonPodRunsAsRoot)
+		}
+	}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(podSpec, field)
+		if err != nil || !found {
+			continue
+		}
+		for i := range containers {
+			c, ok := containers[i].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			v, err := secureContainer(c, profile)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, v...)
+		}
+		if err := unstructured.SetNestedSlice(podSpec, containers, field); err != nil {
+			return nil, err
+		}
+	}
+	if profile.SeccompProfile != "" {
+		if !strings.HasSuffix(podSpecPath, templateSpecSuffix) {
+			return append(violations, reasonNoPodTemplate), nil
+		}
+		templatePath := strings.TrimSuffix(podSpecPath, templateSpecSuffix)
+		v, err := fieldpath.Pave(wl.Object).GetValue(templatePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetPodTemplate, templatePath)
+		}
+		template, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf(errFmtGetPodTemplate, templatePath)
+		}
+		if err := unstructured.SetNestedField(template, profile.SeccompProfile,
+			"metadata", "annotations", corev1.SeccompPodAnnotationKey); err != nil {
+			return nil, err
+		}
+	}
+	return violations, nil
+}
+
+// secureContainer sets the security settings the supplied profile requires in
+// the supplied container, and returns the violations of the profile it can't
+// fix.
+func secureContainer(c map[string]interface{}, profile v1alpha2.SecurityScopeSpec) ([]string, error) {
+	name, _, _ := unstructured.NestedString(c, "name")
+	var violations []string
+	if profile.RunAsNonRoot {
+		if nonRoot, found, _ := unstructured.NestedBool(c, "securityContext", "runAsNonRoot"); found && !nonRoot {
+			if err := unstructured.SetNestedField(c, true, "securityContext", "runAsNonRoot"); err != nil {
+				return nil, err
+			}
+		}
+		if uid, found, _ := unstructured.NestedInt64(c, "securityContext", "runAsUser"); found && uid == 0 {
+			violations = append(violations, fmt.Sprintf(reasonFmtContainerAsRoot, name))
+		}
+	}
+	if len(profile.DropCapabilities) == 0 {
+		return violations, nil
+	}
+	drop, _, _ := unstructured.NestedStringSlice(c, "securityContext", "capabilities", "drop")
+	dropped := make([]string, len(profile.DropCapabilities))
+	for i, capability := range profile.DropCapabilities {
+		dropped[i] = string(capability)
+		if !contains(drop, dropped[i]) {
+			drop = append(drop, dropped[i])
+		}
+	}
+	if err := unstructured.SetNestedStringSlice(c, drop, "securityContext", "capabilities", "drop"); err != nil {
+		return nil, err
+	}
+	add, _, _ := unstructured.NestedStringSlice(c, "securityContext", "capabilities", "add")
+	for _, capability := range add {
+		if contains(dropped, capabilityAll) || contains(dropped, capability) {
+			violations = append(violations, fmt.Sprintf(reasonFmtAddsCapability, name, capability))
+		}
+	}
+	return violations, nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package securityscope
+
+import (
+	"context"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func deployment(container map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{container},
+				},
+			},
+		},
+	}}
+}
+
+func TestSync(t *testing.T) {
+	errBoom := errors.New("boom")
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	scope := &v1alpha2.SecurityScope{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "restricted"},
+		Spec: v1alpha2.SecurityScopeSpec{
+			RunAsNonRoot:     true,
+			DropCapabilities: []corev1.Capability{"NET_RAW"},
+		},
+	}
+	web := deployment(map[string]interface{}{"name": "web"})
+	secured := deployment(map[string]interface{}{
+		"name": "web",
+		"securityContext": map[string]interface{}{
+			"capabilities": map[string]interface{}{"drop": []interface{}{"NET_RAW"}},
+		},
+	})
+	unstructured.SetNestedField(secured.Object, true, "spec", "template", "spec", "securityContext", "runAsNonRoot")
+	webRef := runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+
+	getDefinition := func(podSpecPath string) test.MockGetFn {
+		return func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+			wd, ok := obj.(*v1alpha2.WorkloadDefinition)
+			if !ok {
+				return errBoom
+			}
+			wd.Spec.PodSpecPath = podSpecPath
+			return nil
+		}
+	}
+
+	type args struct {
+		c         client.Client
+		s         oam.Scope
+		workloads []*unstructured.Unstructured
+	}
+	type want struct {
+		err     error
+		status  v1alpha2.SecurityScopeStatus
+		patched *unstructured.Unstructured
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotSecurityScope": {
+			reason: "Scopes other than SecurityScopes should not be synced",
+			args:   args{s: &v1alpha2.HealthScope{}},
+			want:   want{err: errors.New(errNotSecurityScope)},
+		},
+		"Secured": {
+			reason: "The pod spec of workloads should be patched to comply with the security profile",
+			args: args{
+				c:         &test.MockClient{MockGet: getDefinition("spec.template.spec")},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.SecurityScopeStatus{Workloads: []v1alpha2.SecurityScopeWorkload{
+					{TargetWorkload: webRef, Compliant: true},
+				}},
+				patched: secured,
+			},
+		},
+		"AlreadySecured": {
+			reason: "Workloads that already comply with the security profile should not be patched",
+			args: args{
+				c:         &test.MockClient{MockGet: getDefinition("spec.template.spec")},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{secured.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.SecurityScopeStatus{Workloads: []v1alpha2.SecurityScopeWorkload{
+					{TargetWorkload: webRef, Compliant: true},
+				}},
+			},
+		},
+		"NoPodSpecPath": {
+			reason: "Workloads whose pod spec can't be determined should be reported as non-compliant",
+			args: args{
+				c:         &test.MockClient{MockGet: getDefinition("")},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.SecurityScopeStatus{Workloads: []v1alpha2.SecurityScopeWorkload{
+					{TargetWorkload: webRef, Diagnosis: reasonNoPodSpec},
+				}},
+			},
+		},
+		"NoWorkloadDefinition": {
+			reason: "Workloads without a WorkloadDefinition should be reported as non-compliant",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{
+				status: v1alpha2.SecurityScopeStatus{Workloads: []v1alpha2.SecurityScopeWorkload{
+					{TargetWorkload: webRef, Diagnosis: reasonNoPodSpec},
+				}},
+			},
+		},
+		"GetWorkloadDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition of a workload should be returned",
+			args: args{
+				c:         &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetWorkloadDefinition, "Deployment", "web")},
+		},
+		"PatchError": {
+			reason: "Errors patching a workload should be returned",
+			args: args{
+				c: &test.MockClient{
+					MockGet:   getDefinition("spec.template.spec"),
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+				s:         scope.DeepCopy(),
+				workloads: []*unstructured.Unstructured{web.DeepCopy()},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtPatchWorkload, "Deployment", "web")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patched *unstructured.Unstructured
+			if mc, ok := tc.args.c.(*test.MockClient); ok && mc.MockPatch == nil {
+				mc.MockPatch = func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+					patched, _ = obj.(*unstructured.Unstructured)
+					return nil
+				}
+			}
+			dm := mock.NewMockDiscoveryMapper()
+			dm.MockRESTMapping = mock.NewMockRESTMapping("deployments")
+			p := NewPolicy(tc.args.c, tc.args.c, dm)
+			err := p.Sync(context.Background(), tc.args.s, tc.args.workloads)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			ss, ok := tc.args.s.(*v1alpha2.SecurityScope)
+			if !ok || err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.status, ss.Status); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patched, patched); diff != "" {
+				t.Errorf("\n%s\np.Sync(...): -want patched workload, +got patched workload:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSecure(t *testing.T) {
+	type args struct {
+		wl          *unstructured.Unstructured
+		podSpecPath string
+		profile     v1alpha2.SecurityScopeSpec
+	}
+	type want struct {
+		violations []string
+		wl         *unstructured.Unstructured
+	}
+
+	seccomp := deployment(map[string]interface{}{"name": "web"})
+	unstructured.SetNestedField(seccomp.Object, "runtime/default",
+		"spec", "template", "metadata", "annotations", corev1.SeccompPodAnnotationKey)
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"EmptyProfile": {
+			reason: "Workloads should not be changed by an empty profile",
+			args: args{
+				wl:          deployment(map[string]interface{}{"name": "web"}),
+				podSpecPath: "spec.template.spec",
+			},
+			want: want{wl: deployment(map[string]interface{}{"name": "web"})},
+		},
+		"RunAsNonRoot": {
+			reason: "Containers that run as root should be set to run as non-root, and those that run as user 0 reported",
+			args: args{
+				wl: deployment(map[string]interface{}{
+					"name":            "web",
+					"securityContext": map[string]interface{}{"runAsNonRoot": false, "runAsUser": int64(0)},
+				}),
+				podSpecPath: "spec.template.spec",
+				profile:     v1alpha2.SecurityScopeSpec{RunAsNonRoot: true},
+			},
+			want: want{
+				violations: []string{`container "web" runs as user 0`},
+				wl: func() *unstructured.Unstructured {
+					wl := deployment(map[string]interface{}{
+						"name":            "web",
+						"securityContext": map[string]interface{}{"runAsNonRoot": true, "runAsUser": int64(0)},
+					})
+					unstructured.SetNestedField(wl.Object, true, "spec", "template", "spec", "securityContext", "runAsNonRoot")
+					return wl
+				}(),
+			},
+		},
+		"DropCapabilities": {
+			reason: "Capabilities should be dropped, and containers that add dropped capabilities reported",
+			args: args{
+				wl: deployment(map[string]interface{}{
+					"name": "web",
+					"securityContext": map[string]interface{}{"capabilities": map[string]interface{}{
+						"add":  []interface{}{"NET_BIND_SERVICE"},
+						"drop": []interface{}{"SYS_ADMIN"},
+					}},
+				}),
+				podSpecPath: "spec.template.spec",
+				profile:     v1alpha2.SecurityScopeSpec{DropCapabilities: []corev1.Capability{"ALL"}},
+			},
+			want: want{
+				violations: []string{`container "web" adds capability NET_BIND_SERVICE`},
+				wl: deployment(map[string]interface{}{
+					"name": "web",
+					"securityContext": map[string]interface{}{"capabilities": map[string]interface{}{
+						"add":  []interface{}{"NET_BIND_SERVICE"},
+						"drop": []interface{}{"SYS_ADMIN", "ALL"},
+					}},
+				}),
+			},
+		},
+		"SeccompProfile": {
+			reason: "The seccomp profile should be annotated on the pod template",
+			args: args{
+				wl:          deployment(map[string]interface{}{"name": "web"}),
+				podSpecPath: "spec.template.spec",
+				profile:     v1alpha2.SecurityScopeSpec{SeccompProfile: "runtime/default"},
+			},
+			want: want{wl: seccomp},
+		},
+		"SeccompProfileWithoutPodTemplate": {
+			reason: "Pod specs that are not part of a pod template should be reported if a seccomp profile is required",
+			args: args{
+				wl:          deployment(map[string]interface{}{"name": "web"}),
+				podSpecPath: "spec.podSpec",
+				profile:     v1alpha2.SecurityScopeSpec{SeccompProfile: "runtime/default"},
+			},
+			want: want{
+				violations: []string{reasonNoPodTemplate},
+				wl: func() *unstructured.Unstructured {
+					wl := deployment(map[string]interface{}{"name": "web"})
+					unstructured.SetNestedMap(wl.Object, map[string]interface{}{}, "spec", "podSpec")
+					return wl
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			violations, err := Secure(tc.args.wl, tc.args.podSpecPath, tc.args.profile)
+			if err != nil {
+				t.Fatalf("\n%s\nSecure(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.violations, violations); diff != "" {
+				t.Errorf("\n%s\nSecure(...): -want violations, +got violations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wl, tc.args.wl); diff != "" {
+				t.Errorf("\n%s\nSecure(...): -want workload, +got workload:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
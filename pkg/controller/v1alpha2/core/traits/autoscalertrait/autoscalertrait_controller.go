@@ -0,0 +1,331 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalertrait
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errListConflictingTraits = "cannot list the traits that conflict with the autoscaler"
+	errConflictingTraits     = "conflicting traits scale the workload"
+	errFmtConflictingTraits  = "%s(s) %s also scale the workload"
+	errFindScaleTarget       = "cannot find the resource to scale"
+	errNoScaleTarget         = "the workload has no resource that serves the scale subresource"
+	errFmtReplicas           = "minReplicas %d must not exceed maxReplicas %d"
+	errRenderAutoscaler      = "cannot render the horizontal pod autoscaler"
+	errApplyAutoscaler       = "cannot apply the horizontal pod autoscaler"
+	errDeleteAutoscaler      = "cannot delete the horizontal pod autoscaler"
+
+	errFmtMapResource       = "cannot determine the resource of %s"
+	errFmtDiscoverResources = "cannot discover the resources of %s"
+)
+
+// defaultTargetCPUUtilization is the average CPU utilization workloads are
+// scaled by if their trait specifies no metrics.
+const defaultTargetCPUUtilization = 80
+
+var (
+	autoscalerKind       = reflect.TypeOf(autoscalingv2beta2.HorizontalPodAutoscaler{}).Name()
+	autoscalerAPIVersion = autoscalingv2beta2.SchemeGroupVersion.String()
+)
+
+// Setup adds a controller that reconciles AutoscalerTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler := Reconciler{
+		Client:    mgr.GetClient(),
+		dm:        dm,
+		discovery: dc,
+		log:       ctrl.Log.WithName("AutoscalerTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.AutoscalerTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("AutoscalerTrait"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles an AutoscalerTrait object
+type Reconciler struct {
+	client.Client
+	dm        discoverymapper.DiscoveryMapper
+	discovery discovery.ServerResourcesInterface
+	log       logr.Logger
+	record    event.Recorder
+	Scheme    *runtime.Scheme
+}
+
+// Reconcile an AutoscalerTrait by applying a HorizontalPodAutoscaler that
+// scales the first resource of its workload that serves the scale
+// subresource.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=autoscalertraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=autoscalertraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=manualscalertraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("autoscaler trait", req.NamespacedName)
+
+	mLog.Info("Reconcile autoscaler trait")
+
+	var trait oamv1alpha2.AutoscalerTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "autoscaler", trait.Name)
+		eventObj = &trait
+	}
+
+	// the autoscaler and manual scalers of a workload would fight over its
+	// replicas, so that the autoscaler stands back while they conflict
+	conflicts, err := conflictingTraits(ctx, r, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errListConflictingTraits, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errListConflictingTraits)))
+	}
+	if len(conflicts) > 0 {
+		err := errors.Errorf(errFmtConflictingTraits, oamv1alpha2.ManualScalerTraitKind, strings.Join(conflicts, ", "))
+		r.record.Event(eventObj, event.Warning(errConflictingTraits, err))
+		if derr := r.deleteAutoscaler(ctx, &trait); derr != nil {
+			mLog.Error(derr, "Failed to delete the horizontal pod autoscaler")
+			r.record.Event(eventObj, event.Warning(errDeleteAutoscaler, derr))
+		}
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	resources, err := util.FetchWorkloadChildResources(ctx, r, workload, wd)
+	if err != nil {
+		mLog.Error(err, "Error while fetching the workload child resources", "workload", workload.UnstructuredContent())
+		r.record.Event(eventObj, event.Warning(util.ErrFetchChildResources, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(fmt.Errorf(util.ErrFetchChildResources)))
+	}
+	// include the workload itself if there is no child resources
+	if len(resources) == 0 {
+		resources = append(resources, workload)
+	}
+	target, err := r.scaleTarget(resources)
+	if err == nil && target == nil {
+		err = errors.New(errNoScaleTarget)
+	}
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errFindScaleTarget, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errFindScaleTarget)))
+	}
+
+	hpa, err := renderAutoscaler(&trait, target)
+	if err == nil {
+		// the autoscaler is deleted with the trait
+		err = ctrl.SetControllerReference(&trait, hpa, r.Scheme)
+	}
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errRenderAutoscaler, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderAutoscaler)))
+	}
+	// server side apply, only the fields we set are touched
+	if err := r.Patch(ctx, hpa, client.Apply, client.ForceOwnership, client.FieldOwner(trait.GetUID())); err != nil {
+		mLog.Error(err, "Failed to apply the horizontal pod autoscaler")
+		r.record.Event(eventObj, event.Warning(errApplyAutoscaler, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyAutoscaler)))
+	}
+	r.record.Event(eventObj, event.Normal("Autoscaler applied",
+		fmt.Sprintf("Trait `%s` successfully server side patched a horizontal pod autoscaler `%s`",
+			trait.Name, hpa.GetName())))
+
+	trait.Status.ScaleTargetReference = &cpv1alpha1.TypedReference{
+		APIVersion: target.GetAPIVersion(),
+		Kind:       target.GetKind(),
+		Name:       target.GetName(),
+		UID:        target.GetUID(),
+	}
+	trait.Status.CurrentReplicas = hpa.Status.CurrentReplicas
+	trait.Status.DesiredReplicas = hpa.Status.DesiredReplicas
+	if err := r.Status().Update(ctx, &trait); err != nil {
+		return util.ReconcileWaitResult, err
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// scaleTarget returns the first of the supplied resources that serves the
+// scale subresource, or nil if none does.
+func (r *Reconciler) scaleTarget(resources []*unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	for _, res := range resources {
+		gvk := res.GroupVersionKind()
+		mapping, err := r.dm.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtMapResource, gvk)
+		}
+		scalable, err := util.HasScaleSubresource(r.discovery, mapping.Resource)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDiscoverResources, mapping.Resource.GroupVersion())
+		}
+		if scalable {
+			return res, nil
+		}
+	}
+	return nil, nil
+}
+
+// deleteAutoscaler deletes the HorizontalPodAutoscaler of the supplied trait,
+// if it exists.
+func (r *Reconciler) deleteAutoscaler(ctx context.Context, trait *oamv1alpha2.AutoscalerTrait) error {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: trait.GetNamespace(), Name: trait.GetName()}, hpa); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// don't delete autoscalers of the same name the trait doesn't control
+	if !metav1.IsControlledBy(hpa, trait) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, hpa))
+}
+
+// conflictingTraits returns the names of the ManualScalerTraits that apply to
+// the workload of the supplied trait.
+func conflictingTraits(ctx context.Context, c client.Reader, trait *oamv1alpha2.AutoscalerTrait) ([]string, error) {
+	l := &oamv1alpha2.ManualScalerTraitList{}
+	if err := c.List(ctx, l, client.InNamespace(trait.GetNamespace())); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, t := range l.Items {
+		if sameWorkload(t.Spec.WorkloadReference, trait.Spec.WorkloadReference) {
+			names = append(names, fmt.Sprintf("%q", t.GetName()))
+		}
+	}
+	return names, nil
+}
+
+// sameWorkload returns true if the supplied references refer to the same
+// workload, possibly through different versions of its API.
+func sameWorkload(a, b cpv1alpha1.TypedReference) bool {
+	if a.Kind != b.Kind || a.Name != b.Name {
+		return false
+	}
+	agv, aerr := schema.ParseGroupVersion(a.APIVersion)
+	bgv, berr := schema.ParseGroupVersion(b.APIVersion)
+	if aerr != nil || berr != nil {
+		return a.APIVersion == b.APIVersion
+	}
+	return agv.Group == bgv.Group
+}
+
+// renderAutoscaler returns the HorizontalPodAutoscaler of the supplied trait,
+// that scales the supplied target.
+func renderAutoscaler(trait *oamv1alpha2.AutoscalerTrait, target *unstructured.Unstructured) (*autoscalingv2beta2.HorizontalPodAutoscaler, error) {
+	if trait.Spec.MinReplicas != nil && *trait.Spec.MinReplicas > trait.Spec.MaxReplicas {
+		return nil, errors.Errorf(errFmtReplicas, *trait.Spec.MinReplicas, trait.Spec.MaxReplicas)
+	}
+	ms := trait.Spec.Metrics
+	if len(ms) == 0 {
+		ms = []oamv1alpha2.AutoscalerMetric{{Resource: corev1.ResourceCPU}}
+	}
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       autoscalerKind,
+			APIVersion: autoscalerAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      trait.GetName(),
+			Namespace: trait.GetNamespace(),
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: target.GetAPIVersion(),
+				Kind:       target.GetKind(),
+				Name:       target.GetName(),
+			},
+			MinReplicas: trait.Spec.MinReplicas,
+			MaxReplicas: trait.Spec.MaxReplicas,
+		},
+	}
+	for _, m := range ms {
+		t := autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.UtilizationMetricType}
+		switch {
+		case m.TargetAverageValue != nil:
+			v := m.TargetAverageValue.DeepCopy()
+			t = autoscalingv2beta2.MetricTarget{Type: autoscalingv2beta2.AverageValueMetricType, AverageValue: &v}
+		case m.TargetAverageUtilization != nil:
+			u := *m.TargetAverageUtilization
+			t.AverageUtilization = &u
+		default:
+			u := int32(defaultTargetCPUUtilization)
+			t.AverageUtilization = &u
+		}
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, autoscalingv2beta2.MetricSpec{
+			Type:     autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{Name: m.Resource, Target: t},
+		})
+	}
+	return hpa, nil
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.AutoscalerTraitKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.AutoscalerTrait{}).
+		// the replicas the autoscalers observe are reported by the traits
+		Owns(&autoscalingv2beta2.HorizontalPodAutoscaler{}).
+		Complete(r)
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalertrait
+
+import (
+	"context"
+	"testing"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func unstructuredOf(apiVersion, kind, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetName(name)
+	return u
+}
+
+func TestRenderAutoscaler(t *testing.T) {
+	two, five := int32(2), int32(5)
+	utilization := int32(60)
+	value := resource.MustParse("512Mi")
+	target := unstructuredOf("apps/v1", "Deployment", "web")
+
+	trait := func(min *int32, max int32, m ...oamv1alpha2.AutoscalerMetric) *oamv1alpha2.AutoscalerTrait {
+		return &oamv1alpha2.AutoscalerTrait{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-autoscaler"},
+			Spec:       oamv1alpha2.AutoscalerTraitSpec{MinReplicas: min, MaxReplicas: max, Metrics: m},
+		}
+	}
+	autoscaler := func(min *int32, max int32, m ...autoscalingv2beta2.MetricSpec) *autoscalingv2beta2.HorizontalPodAutoscaler {
+		return &autoscalingv2beta2.HorizontalPodAutoscaler{
+			TypeMeta:   metav1.TypeMeta{Kind: autoscalerKind, APIVersion: autoscalerAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-autoscaler"},
+			Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+				MinReplicas:    min,
+				MaxReplicas:    max,
+				Metrics:        m,
+			},
+		}
+	}
+	utilizationOf := func(r corev1.ResourceName, u int32) autoscalingv2beta2.MetricSpec {
+		return autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{Name: r, Target: autoscalingv2beta2.MetricTarget{
+				Type: autoscalingv2beta2.UtilizationMetricType, AverageUtilization: &u,
+			}},
+		}
+	}
+
+	type want struct {
+		hpa *autoscalingv2beta2.HorizontalPodAutoscaler
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		trait  *oamv1alpha2.AutoscalerTrait
+		want   want
+	}{
+		"DefaultMetrics": {
+			reason: "Traits without metrics should scale by an average CPU utilization of 80%.",
+			trait:  trait(nil, 5),
+			want:   want{hpa: autoscaler(nil, 5, utilizationOf(corev1.ResourceCPU, defaultTargetCPUUtilization))},
+		},
+		"Metrics": {
+			reason: "The metrics of the trait should target the average utilization or value of their resource.",
+			trait: trait(&two, 5,
+				oamv1alpha2.AutoscalerMetric{Resource: corev1.ResourceCPU, TargetAverageUtilization: &utilization},
+				oamv1alpha2.AutoscalerMetric{Resource: corev1.ResourceMemory, TargetAverageValue: &value},
+				oamv1alpha2.AutoscalerMetric{Resource: corev1.ResourceMemory},
+			),
+			want: want{hpa: autoscaler(&two, 5,
+				utilizationOf(corev1.ResourceCPU, utilization),
+				autoscalingv2beta2.MetricSpec{
+					Type: autoscalingv2beta2.ResourceMetricSourceType,
+					Resource: &autoscalingv2beta2.ResourceMetricSource{Name: corev1.ResourceMemory, Target: autoscalingv2beta2.MetricTarget{
+						Type: autoscalingv2beta2.AverageValueMetricType, AverageValue: &value,
+					}},
+				},
+				utilizationOf(corev1.ResourceMemory, defaultTargetCPUUtilization),
+			)},
+		},
+		"InvalidReplicas": {
+			reason: "Traits whose minReplicas exceed their maxReplicas should return an error.",
+			trait:  trait(&five, 2),
+			want:   want{err: errors.Errorf(errFmtReplicas, 5, 2)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hpa, err := renderAutoscaler(tc.trait, target)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrenderAutoscaler(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.hpa, hpa); diff != "" {
+				t.Errorf("\n%s\nrenderAutoscaler(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConflictingTraits(t *testing.T) {
+	errBoom := errors.New("boom")
+	trait := &oamv1alpha2.AutoscalerTrait{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-autoscaler"},
+		Spec: oamv1alpha2.AutoscalerTraitSpec{
+			WorkloadReference: cpv1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		},
+	}
+	scaler := func(name, apiVersion, kind, workload string) oamv1alpha2.ManualScalerTrait {
+		return oamv1alpha2.ManualScalerTrait{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: oamv1alpha2.ManualScalerTraitSpec{
+				WorkloadReference: cpv1alpha1.TypedReference{APIVersion: apiVersion, Kind: kind, Name: workload},
+			},
+		}
+	}
+
+	type want struct {
+		names []string
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "Errors listing the ManualScalerTraits should be returned.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want:   want{err: errBoom},
+		},
+		"Conflicts": {
+			reason: "ManualScalerTraits that apply to the workload through any version of its API should conflict.",
+			c: &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				if lo.Namespace != "ns" {
+					return errors.Errorf("unexpected namespace %q", lo.Namespace)
+				}
+				obj.(*oamv1alpha2.ManualScalerTraitList).Items = []oamv1alpha2.ManualScalerTrait{
+					scaler("same", "apps/v1", "Deployment", "web"),
+					scaler("other-version", "apps/v1beta2", "Deployment", "web"),
+					scaler("other-workload", "apps/v1", "Deployment", "api"),
+					scaler("other-kind", "apps/v1", "StatefulSet", "web"),
+					scaler("other-group", "example.com/v1", "Deployment", "web"),
+				}
+				return nil
+			}},
+			want: want{names: []string{`"same"`, `"other-version"`}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			names, err := conflictingTraits(context.Background(), tc.c, trait)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nconflictingTraits(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\n%s\nconflictingTraits(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestScaleTarget(t *testing.T) {
+	errBoom := errors.New("boom")
+	deploy := unstructuredOf("apps/v1", "Deployment", "web")
+	service := unstructuredOf("v1", "Service", "web")
+
+	dm := &mock.DiscoveryMapper{MockRESTMapping: func(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+		plural := map[string]string{"Deployment": "deployments", "Service": "services"}[gk.Kind]
+		return &meta.RESTMapping{Resource: schema.GroupVersionResource{Group: gk.Group, Version: versions[0], Resource: plural}}, nil
+	}}
+	dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "services"}, {Name: "services/status"}}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments"}, {Name: "deployments/scale"}}},
+	}}}
+
+	type want struct {
+		target *unstructured.Unstructured
+		err    error
+	}
+	cases := map[string]struct {
+		reason    string
+		r         *Reconciler
+		resources []*unstructured.Unstructured
+		want      want
+	}{
+		"MapError": {
+			reason: "Errors mapping the kind of a resource should be returned.",
+			r: &Reconciler{dm: &mock.DiscoveryMapper{MockRESTMapping: func(schema.GroupKind, ...string) (*meta.RESTMapping, error) {
+				return nil, errBoom
+			}}, discovery: dc},
+			resources: []*unstructured.Unstructured{deploy},
+			want:      want{err: errors.Wrapf(errBoom, errFmtMapResource, deploy.GroupVersionKind())},
+		},
+		"FirstScalable": {
+			reason:    "The first resource that serves the scale subresource should be the target.",
+			r:         &Reconciler{dm: dm, discovery: dc},
+			resources: []*unstructured.Unstructured{service, deploy},
+			want:      want{target: deploy},
+		},
+		"NoneScalable": {
+			reason:    "No resource should be the target if none serves the scale subresource.",
+			r:         &Reconciler{dm: dm, discovery: dc},
+			resources: []*unstructured.Unstructured{service},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			target, err := tc.r.scaleTarget(tc.resources)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nscaleTarget(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.target, target); diff != "" {
+				t.Errorf("\n%s\nscaleTarget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuptrait
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errFmtMapResource    = "cannot determine the resource of %s"
+	errFmtLabelResource  = "cannot label %s %q for the backup"
+	errFmtVolumesNotList = "%s.volumes is not a list"
+	errGetVolumes        = "cannot get the volumes of the workload"
+	errLabelResources    = "cannot label the resources of the workload for the backup"
+	errBackup            = "cannot back up the workload"
+	errApplySchedule     = "cannot apply the backup schedule"
+	errDeleteSchedule    = "cannot delete the backup schedule"
+	errGetBackup         = "cannot get the backup"
+	errCreateBackup      = "cannot create the backup"
+	errUpdateTrait       = "cannot update the backup trait"
+	errUpdateTraitStatus = "cannot update the status of the backup trait"
+)
+
+// The API version and kinds of the Backups and Schedules of Velero.
+const (
+	veleroAPIVersion = "velero.io/v1"
+	backupKind       = "Backup"
+	scheduleKind     = "Schedule"
+)
+
+// Labels of the Backups and Schedules of BackupTraits, that record the trait
+// they belong to. They live in the namespace of Velero, so that they cannot
+// be owned by the trait.
+const (
+	labelTraitNamespace = "backuptrait.core.oam.dev/namespace"
+	labelTraitName      = "backuptrait.core.oam.dev/name"
+)
+
+// labelTraitUID labels the resources a BackupTrait backs up with the UID of
+// the trait, so that its backups select them.
+const labelTraitUID = "backuptrait.core.oam.dev/uid"
+
+// The resources of the volume claims of pods and the volumes they are bound
+// to, which are backed up along with the claims.
+const (
+	claimResource  = "persistentvolumeclaims"
+	volumeResource = "persistentvolumes"
+)
+
+// scheduleFinalizer deletes the Schedule of a BackupTrait with the trait.
+const scheduleFinalizer = "schedule.backuptrait.core.oam.dev"
+
+// defaultVeleroNamespace is the namespace Velero runs in unless the runtime
+// is told otherwise.
+const defaultVeleroNamespace = "velero"
+
+// Setup adds a controller that reconciles BackupTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	namespace := args.VeleroNamespace
+	if namespace == "" {
+		namespace = defaultVeleroNamespace
+	}
+	reconciler := Reconciler{
+		Client:          mgr.GetClient(),
+		dm:              dm,
+		veleroNamespace: namespace,
+		log:             ctrl.Log.WithName("BackupTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.BackupTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("BackupTrait"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a BackupTrait object
+type Reconciler struct {
+	client.Client
+	dm              discoverymapper.DiscoveryMapper
+	veleroNamespace string
+	log             logr.Logger
+	record          event.Recorder
+	Scheme          *runtime.Scheme
+}
+
+// Reconcile a BackupTrait by applying a Velero Schedule that periodically
+// backs up the resources of its workload, or by creating a Velero Backup that
+// backs them up once if the trait has no schedule.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=backuptraits,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=backuptraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=velero.io,resources=backups;schedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;patch
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("backup trait", req.NamespacedName)
+
+	mLog.Info("Reconcile backup trait")
+
+	var trait oamv1alpha2.BackupTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if meta.WasDeleted(&trait) {
+		// backups are kept until they expire, so that the workload can be
+		// restored after the trait is gone
+		if err := r.deleteSchedule(ctx, &trait); err != nil {
+			return util.ReconcileWaitResult, errors.Wrap(err, errDeleteSchedule)
+		}
+		meta.RemoveFinalizer(&trait, scheduleFinalizer)
+		return ctrl.Result{}, errors.Wrap(r.Update(ctx, &trait), errUpdateTrait)
+	}
+	if !meta.FinalizerExists(&trait, scheduleFinalizer) {
+		meta.AddFinalizer(&trait, scheduleFinalizer)
+		if err := r.Update(ctx, &trait); err != nil {
+			return util.ReconcileWaitResult, errors.Wrap(err, errUpdateTrait)
+		}
+	}
+
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "backupTrait", trait.Name)
+		eventObj = &trait
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+
+	resources, err := r.labelResources(ctx, &trait, workload, wd)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errLabelResources, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errLabelResources)))
+	}
+
+	backup := renderBackup(&trait, resources, r.veleroNamespace)
+	if backup.GetKind() == scheduleKind {
+		// server side apply, only the fields we set are touched
+		err = errors.Wrap(r.Patch(ctx, backup, client.Apply, client.ForceOwnership, client.FieldOwner(trait.GetUID())),
+			errApplySchedule)
+	} else {
+		err = r.ensureBackup(ctx, &trait, backup)
+	}
+	if err != nil {
+		mLog.Error(err, "Failed to back up the workload")
+		r.record.Event(eventObj, event.Warning(errBackup, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errBackup)))
+	}
+	r.record.Event(eventObj, event.Normal("Backup applied",
+		fmt.Sprintf("Trait `%s` successfully applied a %s `%s`", trait.Name, backup.GetKind(), backup.GetName())))
+
+	trait.Status.BackupReference = &cpv1alpha1.TypedReference{
+		APIVersion: backup.GetAPIVersion(),
+		Kind:       backup.GetKind(),
+		Name:       backup.GetName(),
+		UID:        backup.GetUID(),
+	}
+	trait.Status.Phase, trait.Status.LastBackupTime = backupStatus(backup)
+	if err := r.Status().Update(ctx, &trait); err != nil {
+		return util.ReconcileWaitResult, errors.Wrap(err, errUpdateTraitStatus)
+	}
+	// the backups and schedules are not watched, so that their progress is
+	// polled
+	return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// ensureBackup creates the supplied Backup unless it exists, since Backups
+// are taken once. The Schedule of the trait, if it had one, is deleted.
+func (r *Reconciler) ensureBackup(ctx context.Context, trait *oamv1alpha2.BackupTrait, backup *unstructured.Unstructured) error {
+	if err := r.deleteSchedule(ctx, trait); err != nil {
+		return errors.Wrap(err, errDeleteSchedule)
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(backup.GroupVersionKind())
+	err := r.Get(ctx, types.NamespacedName{Namespace: backup.GetNamespace(), Name: backup.GetName()}, existing)
+	if err == nil {
+		*backup = *existing
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, errGetBackup)
+	}
+	return errors.Wrap(r.Create(ctx, backup), errCreateBackup)
+}
+
+// deleteSchedule deletes the Schedule of the supplied trait, if it exists.
+func (r *Reconciler) deleteSchedule(ctx context.Context, trait *oamv1alpha2.BackupTrait) error {
+	schedule := &unstructured.Unstructured{}
+	schedule.SetAPIVersion(veleroAPIVersion)
+	schedule.SetKind(scheduleKind)
+	schedule.SetNamespace(r.veleroNamespace)
+	schedule.SetName(backupName(trait))
+	return client.IgnoreNotFound(r.Delete(ctx, schedule))
+}
+
+// backupName returns the name of the Backup or Schedule of the supplied
+// trait, which is unique across the namespaces of the traits.
+func backupName(trait *oamv1alpha2.BackupTrait) string {
+	return trait.GetNamespace() + "-" + trait.GetName()
+}
+
+// labelResources labels the supplied workload, its child resources and the
+// volume claims of its pods with the UID of the supplied trait, so that its
+// backups select them. Claims that do not exist yet are left out. It returns
+// the sorted resources, e.g. deployments.apps, of the labelled resources, and
+// the persistent volumes of the claims.
+func (r *Reconciler) labelResources(ctx context.Context, trait *oamv1alpha2.BackupTrait,
+	workload *unstructured.Unstructured, wd *oamv1alpha2.WorkloadDefinition) ([]string, error) {
+	children, err := util.FetchWorkloadChildResources(ctx, r, workload, wd)
+	if err != nil {
+		return nil, errors.Wrap(err, util.ErrFetchChildResources)
+	}
+	included := map[string]bool{}
+	for _, res := range append([]*unstructured.Unstructured{workload}, children...) {
+		gvk := res.GroupVersionKind()
+		mapping, err := r.dm.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtMapResource, gvk)
+		}
+		if err := r.labelResource(ctx, trait, res); err != nil {
+			return nil, err
+		}
+		included[mapping.Resource.GroupResource().String()] = true
+	}
+
+	claims, err := claimNames(workload, wd.Spec.PodSpecPath)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetVolumes)
+	}
+	for _, name := range claims {
+		claim := &unstructured.Unstructured{}
+		claim.SetAPIVersion("v1")
+		claim.SetKind("PersistentVolumeClaim")
+		claim.SetNamespace(workload.GetNamespace())
+		claim.SetName(name)
+		if err := r.labelResource(ctx, trait, claim); err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				continue
+			}
+			return nil, err
+		}
+		included[claimResource], included[volumeResource] = true, true
+	}
+
+	resources := make([]string, 0, len(included))
+	for res := range included {
+		resources = append(resources, res)
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+// labelResource labels the supplied resource with the UID of the supplied
+// trait, unless it is labelled already.
+func (r *Reconciler) labelResource(ctx context.Context, trait *oamv1alpha2.BackupTrait, res *unstructured.Unstructured) error {
+	if res.GetLabels()[labelTraitUID] == string(trait.GetUID()) {
+		return nil
+	}
+	original := res.DeepCopy()
+	meta.AddLabels(res, map[string]string{labelTraitUID: string(trait.GetUID())})
+	return errors.Wrapf(r.Patch(ctx, res, client.MergeFrom(original)), errFmtLabelResource, res.GetKind(), res.GetName())
+}
+
+// claimNames returns the names of the PersistentVolumeClaims of the volumes
+// of the pod spec at the supplied path of the supplied workload. Workloads
+// without a pod spec have none.
+func claimNames(workload *unstructured.Unstructured, podSpecPath string) ([]string, error) {
+	if podSpecPath == "" {
+		return nil, nil
+	}
+	v, err := fieldpath.Pave(workload.Object).GetValue(podSpecPath + ".volumes")
+	if fieldpath.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	volumes, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.Errorf(errFmtVolumesNotList, podSpecPath)
+	}
+	var names []string
+	for _, vol := range volumes {
+		volume, ok := vol.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(volume, "persistentVolumeClaim", "claimName"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// renderBackup returns the Velero Schedule of the supplied trait in the
+// supplied namespace, or its Backup if it has no schedule. It backs up the
+// supplied resources, e.g. deployments.apps, in the namespace of the trait
+// that carry the UID label of the trait, i.e. the workload, its child
+// resources and the volume claims of its pods, and the volumes bound to the
+// claims. Settings the trait omits are left to the defaults of Velero.
+func renderBackup(trait *oamv1alpha2.BackupTrait, resources []string, namespace string) *unstructured.Unstructured {
+	included := make([]interface{}, 0, len(resources))
+	for _, res := range resources {
+		included = append(included, res)
+	}
+
+	spec := trait.Spec
+	backupSpec := map[string]interface{}{
+		"includedNamespaces": []interface{}{trait.GetNamespace()},
+		"includedResources":  included,
+		"labelSelector": map[string]interface{}{"matchLabels": map[string]interface{}{
+			labelTraitUID: string(trait.GetUID()),
+		}},
+	}
+	if spec.SnapshotVolumes != nil {
+		backupSpec["snapshotVolumes"] = *spec.SnapshotVolumes
+	}
+	if spec.TTL != nil {
+		backupSpec["ttl"] = spec.TTL.Duration.String()
+	}
+	if spec.StorageLocation != "" {
+		backupSpec["storageLocation"] = spec.StorageLocation
+	}
+	if len(spec.VolumeSnapshotLocations) > 0 {
+		locations := make([]interface{}, 0, len(spec.VolumeSnapshotLocations))
+		for _, l := range spec.VolumeSnapshotLocations {
+			locations = append(locations, l)
+		}
+		backupSpec["volumeSnapshotLocations"] = locations
+	}
+
+	backup := &unstructured.Unstructured{Object: map[string]interface{}{"spec": backupSpec}}
+	backup.SetKind(backupKind)
+	if spec.Schedule != "" {
+		backup.Object["spec"] = map[string]interface{}{
+			"schedule": spec.Schedule,
+			"template": backupSpec,
+		}
+		backup.SetKind(scheduleKind)
+	}
+	backup.SetAPIVersion(veleroAPIVersion)
+	backup.SetNamespace(namespace)
+	backup.SetName(backupName(trait))
+	backup.SetLabels(map[string]string{
+		labelTraitNamespace: trait.GetNamespace(),
+		labelTraitName:      trait.GetName(),
+	})
+	return backup
+}
+
+// backupStatus returns the phase of the supplied Backup or Schedule, and the
+// time its last backup completed or was started.
+func backupStatus(backup *unstructured.Unstructured) (string, *metav1.Time) {
+	phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+	field := "completionTimestamp"
+	if backup.GetKind() == scheduleKind {
+		field = "lastBackup"
+	}
+	ts, _, _ := unstructured.NestedString(backup.Object, "status", field)
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return phase, nil
+	}
+	return phase, &metav1.Time{Time: t}
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.BackupTraitKind)
+	// backups and schedules are not watched, so that the controller starts in
+	// clusters without Velero
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.BackupTrait{}).
+		Complete(r)
+}
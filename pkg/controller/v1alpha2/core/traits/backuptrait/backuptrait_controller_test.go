@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backuptrait
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestLabelResources(t *testing.T) {
+	errBoom := errors.New("boom")
+	trait := &oamv1alpha2.BackupTrait{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "db-backup", UID: "trait-uid"}}
+
+	workload := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+			"volumes": []interface{}{
+				map[string]interface{}{"name": "data", "persistentVolumeClaim": map[string]interface{}{"claimName": "db-data"}},
+				map[string]interface{}{"name": "logs", "persistentVolumeClaim": map[string]interface{}{"claimName": "db-logs"}},
+				map[string]interface{}{"name": "tmp", "emptyDir": map[string]interface{}{}},
+			},
+		}}},
+	}}
+	workload.SetAPIVersion("example.com/v1")
+	workload.SetKind("Database")
+	workload.SetNamespace("ns")
+	workload.SetName("db")
+	workload.SetUID("db-uid")
+
+	child := unstructured.Unstructured{}
+	child.SetAPIVersion("apps/v1")
+	child.SetKind("StatefulSet")
+	child.SetNamespace("ns")
+	child.SetName("db")
+	child.SetOwnerReferences([]metav1.OwnerReference{{UID: workload.GetUID()}})
+
+	wd := &oamv1alpha2.WorkloadDefinition{Spec: oamv1alpha2.WorkloadDefinitionSpec{
+		PodSpecPath:        "spec.template.spec",
+		ChildResourceKinds: []oamv1alpha2.ChildResourceKind{{APIVersion: "apps/v1", Kind: "StatefulSet"}},
+	}}
+
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = func(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+		return &meta.RESTMapping{Resource: schema.GroupVersionResource{
+			Group: gk.Group, Version: versions[0], Resource: strings.ToLower(gk.Kind) + "s",
+		}}, nil
+	}
+	list := func(_ context.Context, l runtime.Object, _ ...client.ListOption) error {
+		l.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{*child.DeepCopy()}
+		return nil
+	}
+
+	type want struct {
+		resources []string
+		labelled  []string
+		err       error
+	}
+	cases := map[string]struct {
+		reason   string
+		workload *unstructured.Unstructured
+		patch    func(obj runtime.Object) error
+		want     want
+	}{
+		"LabelResources": {
+			reason:   "The workload, its child resources and the claims of its volumes that exist should be labelled and backed up.",
+			workload: workload,
+			patch: func(obj runtime.Object) error {
+				if u := obj.(*unstructured.Unstructured); u.GetName() == "db-logs" {
+					return apierrors.NewNotFound(schema.GroupResource{Resource: "persistentvolumeclaims"}, u.GetName())
+				}
+				return nil
+			},
+			want: want{
+				resources: []string{"databases.example.com", "persistentvolumeclaims", "persistentvolumes", "statefulsets.apps"},
+				labelled:  []string{"Database/db", "StatefulSet/db", "PersistentVolumeClaim/db-data", "PersistentVolumeClaim/db-logs"},
+			},
+		},
+		"Labelled": {
+			reason: "Resources labelled by the trait already should not be patched.",
+			workload: func() *unstructured.Unstructured {
+				w := workload.DeepCopy()
+				w.SetLabels(map[string]string{labelTraitUID: "trait-uid"})
+				return w
+			}(),
+			patch: func(obj runtime.Object) error { return nil },
+			want: want{
+				resources: []string{"databases.example.com", "persistentvolumeclaims", "persistentvolumes", "statefulsets.apps"},
+				labelled:  []string{"StatefulSet/db", "PersistentVolumeClaim/db-data", "PersistentVolumeClaim/db-logs"},
+			},
+		},
+		"PatchError": {
+			reason:   "Errors labelling a resource should be returned.",
+			workload: workload,
+			patch:    func(obj runtime.Object) error { return errBoom },
+			want: want{
+				labelled: []string{"Database/db"},
+				err:      errors.Wrapf(errBoom, errFmtLabelResource, "Database", "db"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var labelled []string
+			r := &Reconciler{
+				Client: &test.MockClient{
+					MockList: list,
+					MockPatch: func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+						u := obj.(*unstructured.Unstructured)
+						if u.GetLabels()[labelTraitUID] != "trait-uid" {
+							return errors.Errorf("%s %q is not labelled", u.GetKind(), u.GetName())
+						}
+						labelled = append(labelled, u.GetKind()+"/"+u.GetName())
+						return tc.patch(obj)
+					},
+				},
+				dm: dm,
+			}
+			resources, err := r.labelResources(context.Background(), trait, tc.workload.DeepCopy(), wd)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.labelResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resources, resources); diff != "" {
+				t.Errorf("\n%s\nr.labelResources(...): -want resources, +got resources:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.labelled, labelled); diff != "" {
+				t.Errorf("\n%s\nr.labelResources(...): -want labelled, +got labelled:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderBackup(t *testing.T) {
+	snapshot := false
+	resources := []string{"deployments.apps", "persistentvolumeclaims", "persistentvolumes"}
+	trait := func(spec oamv1alpha2.BackupTraitSpec) *oamv1alpha2.BackupTrait {
+		return &oamv1alpha2.BackupTrait{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "db-backup", UID: "trait-uid"},
+			Spec:       spec,
+		}
+	}
+	backupSpec := func(extra map[string]interface{}) map[string]interface{} {
+		spec := map[string]interface{}{
+			"includedNamespaces": []interface{}{"ns"},
+			"includedResources":  []interface{}{"deployments.apps", "persistentvolumeclaims", "persistentvolumes"},
+			"labelSelector": map[string]interface{}{"matchLabels": map[string]interface{}{
+				labelTraitUID: "trait-uid",
+			}},
+		}
+		for k, v := range extra {
+			spec[k] = v
+		}
+		return spec
+	}
+	backup := func(kind string, spec map[string]interface{}) *unstructured.Unstructured {
+		b := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		b.SetAPIVersion(veleroAPIVersion)
+		b.SetKind(kind)
+		b.SetNamespace("velero")
+		b.SetName("ns-db-backup")
+		b.SetLabels(map[string]string{labelTraitNamespace: "ns", labelTraitName: "db-backup"})
+		return b
+	}
+
+	cases := map[string]struct {
+		reason string
+		trait  *oamv1alpha2.BackupTrait
+		want   *unstructured.Unstructured
+	}{
+		"Backup": {
+			reason: "Traits without a schedule should back up the resources of their workload once, leaving settings they omit to the defaults of Velero.",
+			trait:  trait(oamv1alpha2.BackupTraitSpec{}),
+			want:   backup(backupKind, backupSpec(nil)),
+		},
+		"Schedule": {
+			reason: "Traits with a schedule should back up the resources of their workload periodically, with the settings of the trait.",
+			trait: trait(oamv1alpha2.BackupTraitSpec{
+				Schedule:                "0 2 * * *",
+				TTL:                     &metav1.Duration{Duration: 720 * time.Hour},
+				SnapshotVolumes:         &snapshot,
+				StorageLocation:         "s3",
+				VolumeSnapshotLocations: []string{"ebs"},
+			}),
+			want: backup(scheduleKind, map[string]interface{}{
+				"schedule": "0 2 * * *",
+				"template": backupSpec(map[string]interface{}{
+					"ttl":                     "720h0m0s",
+					"snapshotVolumes":         false,
+					"storageLocation":         "s3",
+					"volumeSnapshotLocations": []interface{}{"ebs"},
+				}),
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := renderBackup(tc.trait, resources, "velero")
+			if diff := cmp.Diff(tc.want, b); diff != "" {
+				t.Errorf("\n%s\nrenderBackup(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBackupStatus(t *testing.T) {
+	completed := metav1.NewTime(time.Date(2020, 9, 1, 2, 0, 0, 0, time.UTC))
+	withStatus := func(kind string, status map[string]interface{}) *unstructured.Unstructured {
+		b := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+		b.SetKind(kind)
+		return b
+	}
+
+	type want struct {
+		phase string
+		last  *metav1.Time
+	}
+	cases := map[string]struct {
+		reason string
+		backup *unstructured.Unstructured
+		want   want
+	}{
+		"Backup": {
+			reason: "The last backup time of a Backup should be the time it completed.",
+			backup: withStatus(backupKind, map[string]interface{}{"phase": "Completed", "completionTimestamp": "2020-09-01T02:00:00Z"}),
+			want:   want{phase: "Completed", last: &completed},
+		},
+		"Schedule": {
+			reason: "The last backup time of a Schedule should be the time it last started a backup.",
+			backup: withStatus(scheduleKind, map[string]interface{}{"phase": "Enabled", "lastBackup": "2020-09-01T02:00:00Z"}),
+			want:   want{phase: "Enabled", last: &completed},
+		},
+		"InProgress": {
+			reason: "Backups that have not completed should have no last backup time.",
+			backup: withStatus(backupKind, map[string]interface{}{"phase": "InProgress"}),
+			want:   want{phase: "InProgress"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			phase, last := backupStatus(tc.backup)
+			if diff := cmp.Diff(tc.want, want{phase: phase, last: last}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nbackupStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventscalertrait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errFindScaleTarget     = "cannot find the resource to scale"
+	errNoScaleTarget       = "the workload has no resource that serves the scale subresource"
+	errFmtReplicas         = "minReplicas %d must not exceed maxReplicas %d"
+	errRenderScaledObject  = "cannot render the scaled object"
+	errApplyScaledObject   = "cannot apply the scaled object"
+	errUpdateTraitStatus   = "cannot update the status of the event scaler trait"
+	errFmtMapResource      = "cannot determine the resource of %s"
+	errFmtDiscoverResource = "cannot discover the resources of %s"
+)
+
+// The API version and kind of the ScaledObjects of KEDA.
+const (
+	scaledObjectAPIVersion = "keda.sh/v1alpha1"
+	scaledObjectKind       = "ScaledObject"
+)
+
+// conditionActive is the type of the condition of ScaledObjects that is true
+// while their triggers have events.
+const conditionActive = "Active"
+
+// Setup adds a controller that reconciles EventScalerTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler := Reconciler{
+		Client:    mgr.GetClient(),
+		dm:        dm,
+		discovery: dc,
+		log:       ctrl.Log.WithName("EventScalerTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.EventScalerTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("EventScalerTrait"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles an EventScalerTrait object
+type Reconciler struct {
+	client.Client
+	dm        discoverymapper.DiscoveryMapper
+	discovery discovery.ServerResourcesInterface
+	log       logr.Logger
+	record    event.Recorder
+	Scheme    *runtime.Scheme
+}
+
+// Reconcile an EventScalerTrait by applying a KEDA ScaledObject that scales
+// the first resource of its workload that serves the scale subresource.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=eventscalertraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=eventscalertraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;patch;delete
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("event scaler trait", req.NamespacedName)
+
+	mLog.Info("Reconcile event scaler trait")
+
+	var trait oamv1alpha2.EventScalerTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "eventScalerTrait", trait.Name)
+		eventObj = &trait
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	resources, err := util.FetchWorkloadChildResources(ctx, r, workload, wd)
+	if err != nil {
+		mLog.Error(err, "Error while fetching the workload child resources", "workload", workload.UnstructuredContent())
+		r.record.Event(eventObj, event.Warning(util.ErrFetchChildResources, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(fmt.Errorf(util.ErrFetchChildResources)))
+	}
+	// include the workload itself if there is no child resources
+	if len(resources) == 0 {
+		resources = append(resources, workload)
+	}
+	target, err := r.scaleTarget(resources)
+	if err == nil && target == nil {
+		err = errors.New(errNoScaleTarget)
+	}
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errFindScaleTarget, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errFindScaleTarget)))
+	}
+
+	so, err := renderScaledObject(&trait, target)
+	if err == nil {
+		// the scaled object is deleted with the trait
+		err = ctrl.SetControllerReference(&trait, so, r.Scheme)
+	}
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errRenderScaledObject, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderScaledObject)))
+	}
+	// server side apply, only the fields we set are touched
+	if err := r.Patch(ctx, so, client.Apply, client.ForceOwnership, client.FieldOwner(trait.GetUID())); err != nil {
+		mLog.Error(err, "Failed to apply the scaled object")
+		r.record.Event(eventObj, event.Warning(errApplyScaledObject, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyScaledObject)))
+	}
+	r.record.Event(eventObj, event.Normal("Scaled object applied",
+		fmt.Sprintf("Trait `%s` successfully server side patched a scaled object `%s`", trait.Name, so.GetName())))
+
+	trait.Status.ScaleTargetReference = &cpv1alpha1.TypedReference{
+		APIVersion: target.GetAPIVersion(),
+		Kind:       target.GetKind(),
+		Name:       target.GetName(),
+		UID:        target.GetUID(),
+	}
+	trait.Status.ScaledObjectReference = &cpv1alpha1.TypedReference{
+		APIVersion: so.GetAPIVersion(),
+		Kind:       so.GetKind(),
+		Name:       so.GetName(),
+		UID:        so.GetUID(),
+	}
+	trait.Status.Active = active(so)
+	if err := r.Status().Update(ctx, &trait); err != nil {
+		return util.ReconcileWaitResult, errors.Wrap(err, errUpdateTraitStatus)
+	}
+	// KEDA doesn't change the scaled object when the triggers become active,
+	// so that their activity is polled
+	return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// scaleTarget returns the first of the supplied resources that serves the
+// scale subresource, or nil if none does.
+func (r *Reconciler) scaleTarget(resources []*unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	for _, res := range resources {
+		gvk := res.GroupVersionKind()
+		mapping, err := r.dm.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtMapResource, gvk)
+		}
+		scalable, err := util.HasScaleSubresource(r.discovery, mapping.Resource)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtDiscoverResource, mapping.Resource.GroupVersion())
+		}
+		if scalable {
+			return res, nil
+		}
+	}
+	return nil, nil
+}
+
+// renderScaledObject returns the KEDA ScaledObject of the supplied trait,
+// that scales the supplied target. Settings the trait omits are left to the
+// defaults of KEDA.
+func renderScaledObject(trait *oamv1alpha2.EventScalerTrait, target *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	spec := trait.Spec
+	if spec.MinReplicas != nil && spec.MaxReplicas != nil && *spec.MinReplicas > *spec.MaxReplicas {
+		return nil, errors.Errorf(errFmtReplicas, *spec.MinReplicas, *spec.MaxReplicas)
+	}
+	triggers := make([]interface{}, 0, len(spec.Triggers))
+	for _, t := range spec.Triggers {
+		metadata := make(map[string]interface{}, len(t.Metadata))
+		for k, v := range t.Metadata {
+			metadata[k] = v
+		}
+		trigger := map[string]interface{}{"type": t.Type, "metadata": metadata}
+		if t.Name != "" {
+			trigger["name"] = t.Name
+		}
+		if t.AuthenticationRef != nil {
+			trigger["authenticationRef"] = map[string]interface{}{"name": *t.AuthenticationRef}
+		}
+		triggers = append(triggers, trigger)
+	}
+	soSpec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": target.GetAPIVersion(),
+			"kind":       target.GetKind(),
+			"name":       target.GetName(),
+		},
+		"triggers": triggers,
+	}
+	for field, v := range map[string]*int32{
+		"minReplicaCount": spec.MinReplicas,
+		"maxReplicaCount": spec.MaxReplicas,
+		"pollingInterval": spec.PollingIntervalSeconds,
+		"cooldownPeriod":  spec.CooldownPeriodSeconds,
+	} {
+		if v != nil {
+			soSpec[field] = int64(*v)
+		}
+	}
+
+	so := &unstructured.Unstructured{Object: map[string]interface{}{"spec": soSpec}}
+	so.SetAPIVersion(scaledObjectAPIVersion)
+	so.SetKind(scaledObjectKind)
+	so.SetNamespace(trait.GetNamespace())
+	so.SetName(trait.GetName())
+	return so, nil
+}
+
+// active returns true if the supplied ScaledObject reports that its triggers
+// have events.
+func active(so *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(so.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == conditionActive {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.EventScalerTraitKind)
+	// scaled objects are not owned, so that the controller starts in clusters
+	// without KEDA
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.EventScalerTrait{}).
+		Complete(r)
+}
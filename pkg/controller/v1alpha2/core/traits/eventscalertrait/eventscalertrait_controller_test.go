@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventscalertrait
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRenderScaledObject(t *testing.T) {
+	zero, two, ten := int32(0), int32(2), int32(10)
+	auth := "rabbitmq-auth"
+	target := &unstructured.Unstructured{}
+	target.SetAPIVersion("apps/v1")
+	target.SetKind("Deployment")
+	target.SetName("worker")
+
+	queue := oamv1alpha2.EventScalerTrigger{Type: "rabbitmq", Metadata: map[string]string{"queueName": "jobs", "queueLength": "20"}}
+	trait := func(spec oamv1alpha2.EventScalerTraitSpec) *oamv1alpha2.EventScalerTrait {
+		return &oamv1alpha2.EventScalerTrait{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "worker-scaler"},
+			Spec:       spec,
+		}
+	}
+	scaledObject := func(spec map[string]interface{}) *unstructured.Unstructured {
+		spec["scaleTargetRef"] = map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "worker"}
+		so := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		so.SetAPIVersion(scaledObjectAPIVersion)
+		so.SetKind(scaledObjectKind)
+		so.SetNamespace("ns")
+		so.SetName("worker-scaler")
+		return so
+	}
+	queueTrigger := map[string]interface{}{
+		"type":     "rabbitmq",
+		"metadata": map[string]interface{}{"queueName": "jobs", "queueLength": "20"},
+	}
+
+	type want struct {
+		so  *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		trait  *oamv1alpha2.EventScalerTrait
+		want   want
+	}{
+		"Defaults": {
+			reason: "Settings the trait omits should be left to the defaults of KEDA.",
+			trait:  trait(oamv1alpha2.EventScalerTraitSpec{Triggers: []oamv1alpha2.EventScalerTrigger{queue}}),
+			want:   want{so: scaledObject(map[string]interface{}{"triggers": []interface{}{queueTrigger}})},
+		},
+		"Settings": {
+			reason: "The replicas, intervals and triggers of the trait should be passed to the scaled object.",
+			trait: trait(oamv1alpha2.EventScalerTraitSpec{
+				MinReplicas:            &zero,
+				MaxReplicas:            &ten,
+				PollingIntervalSeconds: &two,
+				CooldownPeriodSeconds:  &ten,
+				Triggers: []oamv1alpha2.EventScalerTrigger{
+					queue,
+					{Type: "cron", Name: "office-hours", Metadata: map[string]string{"start": "0 8 * * *"}, AuthenticationRef: &auth},
+				},
+			}),
+			want: want{so: scaledObject(map[string]interface{}{
+				"minReplicaCount": int64(0),
+				"maxReplicaCount": int64(10),
+				"pollingInterval": int64(2),
+				"cooldownPeriod":  int64(10),
+				"triggers": []interface{}{
+					queueTrigger,
+					map[string]interface{}{
+						"type":              "cron",
+						"name":              "office-hours",
+						"metadata":          map[string]interface{}{"start": "0 8 * * *"},
+						"authenticationRef": map[string]interface{}{"name": auth},
+					},
+				},
+			})},
+		},
+		"InvalidReplicas": {
+			reason: "Traits whose minReplicas exceed their maxReplicas should return an error.",
+			trait: trait(oamv1alpha2.EventScalerTraitSpec{
+				MinReplicas: &ten,
+				MaxReplicas: &two,
+				Triggers:    []oamv1alpha2.EventScalerTrigger{queue},
+			}),
+			want: want{err: errors.Errorf(errFmtReplicas, 10, 2)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			so, err := renderScaledObject(tc.trait, target)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrenderScaledObject(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.so, so); diff != "" {
+				t.Errorf("\n%s\nrenderScaledObject(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestActive(t *testing.T) {
+	scaledObject := func(conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"conditions": conditions},
+		}}
+	}
+	condition := func(typ, status string) map[string]interface{} {
+		return map[string]interface{}{"type": typ, "status": status}
+	}
+
+	cases := map[string]struct {
+		reason string
+		so     *unstructured.Unstructured
+		want   bool
+	}{
+		"NoStatus": {
+			reason: "Scaled objects KEDA has not reconciled yet should not be active.",
+			so:     &unstructured.Unstructured{Object: map[string]interface{}{}},
+			want:   false,
+		},
+		"Active": {
+			reason: "Scaled objects whose Active condition is true should be active.",
+			so:     scaledObject(condition("Ready", "True"), condition(conditionActive, "True")),
+			want:   true,
+		},
+		"Inactive": {
+			reason: "Scaled objects whose Active condition is not true should not be active.",
+			so:     scaledObject(condition("Ready", "True"), condition(conditionActive, "False")),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := active(tc.so); got != tc.want {
+				t.Errorf("\n%s\nactive(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
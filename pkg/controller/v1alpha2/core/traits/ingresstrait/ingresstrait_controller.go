@@ -0,0 +1,263 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingresstrait
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errNoService     = "the workload has no Service"
+	errFmtNoPorts    = "Service %q exposes no ports"
+	errFmtGetPorts   = "cannot get the ports of Service %q"
+	errFindService   = "cannot find the Service of the workload"
+	errRenderIngress = "cannot render the ingress"
+	errApplyIngress  = "cannot apply the ingress"
+)
+
+// defaultPath is the path whose requests rules without one route.
+const defaultPath = "/"
+
+var (
+	serviceKind       = reflect.TypeOf(corev1.Service{}).Name()
+	serviceAPIVersion = corev1.SchemeGroupVersion.String()
+
+	ingressKind       = reflect.TypeOf(networkingv1beta1.Ingress{}).Name()
+	ingressAPIVersion = networkingv1beta1.SchemeGroupVersion.String()
+)
+
+// Setup adds a controller that reconciles IngressTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		dm:     dm,
+		log:    ctrl.Log.WithName("IngressTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.IngressTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("IngressTrait"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles an IngressTrait object
+type Reconciler struct {
+	client.Client
+	dm     discoverymapper.DiscoveryMapper
+	log    logr.Logger
+	record event.Recorder
+	Scheme *runtime.Scheme
+}
+
+// Reconcile an IngressTrait by applying an Ingress that routes the requests
+// for its rules to the Service of its workload.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=ingresstraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=ingresstraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("ingress trait", req.NamespacedName)
+
+	mLog.Info("Reconcile ingress trait")
+
+	var trait oamv1alpha2.IngressTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "ingress", trait.Name)
+		eventObj = &trait
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	resources, err := util.FetchWorkloadChildResources(ctx, r, workload, wd)
+	if err != nil {
+		mLog.Error(err, "Error while fetching the workload child resources", "workload", workload.UnstructuredContent())
+		r.record.Event(eventObj, event.Warning(util.ErrFetchChildResources, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(fmt.Errorf(util.ErrFetchChildResources)))
+	}
+	// the workload may be a Service itself
+	resources = append(resources, workload)
+	svc, ports, err := backendService(resources)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errFindService, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errFindService)))
+	}
+
+	ingress := renderIngress(&trait, svc.GetName(), ports[0])
+	// the ingress is deleted with the trait
+	if err := ctrl.SetControllerReference(&trait, ingress, r.Scheme); err != nil {
+		r.record.Event(eventObj, event.Warning(errRenderIngress, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderIngress)))
+	}
+	// server side apply, only the fields we set are touched
+	if err := r.Patch(ctx, ingress, client.Apply, client.ForceOwnership, client.FieldOwner(trait.GetUID())); err != nil {
+		mLog.Error(err, "Failed to apply the ingress")
+		r.record.Event(eventObj, event.Warning(errApplyIngress, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyIngress)))
+	}
+	r.record.Event(eventObj, event.Normal("Ingress applied",
+		fmt.Sprintf("Trait `%s` successfully server side patched an ingress `%s`", trait.Name, ingress.GetName())))
+
+	trait.Status.ServiceReference = &cpv1alpha1.TypedReference{
+		APIVersion: svc.GetAPIVersion(),
+		Kind:       svc.GetKind(),
+		Name:       svc.GetName(),
+		UID:        svc.GetUID(),
+	}
+	trait.Status.Addresses = nil
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		addr := lb.IP
+		if addr == "" {
+			addr = lb.Hostname
+		}
+		trait.Status.Addresses = append(trait.Status.Addresses, addr)
+	}
+	if err := r.Status().Update(ctx, &trait); err != nil {
+		return util.ReconcileWaitResult, err
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// backendService returns the first of the supplied resources that is a
+// Service, and the ports it exposes.
+func backendService(resources []*unstructured.Unstructured) (*unstructured.Unstructured, []int32, error) {
+	for _, res := range resources {
+		if res.GetKind() != serviceKind || res.GetAPIVersion() != serviceAPIVersion {
+			continue
+		}
+		declared, _, err := unstructured.NestedSlice(res.Object, "spec", "ports")
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtGetPorts, res.GetName())
+		}
+		ports := make([]int32, 0, len(declared))
+		for _, p := range declared {
+			port, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if n, found, err := unstructured.NestedInt64(port, "port"); err == nil && found {
+				ports = append(ports, int32(n))
+			}
+		}
+		if len(ports) == 0 {
+			return nil, nil, errors.Errorf(errFmtNoPorts, res.GetName())
+		}
+		return res, ports, nil
+	}
+	return nil, nil, errors.New(errNoService)
+}
+
+// renderIngress returns the Ingress of the supplied trait, that routes the
+// requests for its rules to the named Service, at the supplied port unless a
+// rule specifies its own.
+func renderIngress(trait *oamv1alpha2.IngressTrait, service string, port int32) *networkingv1beta1.Ingress {
+	ingress := &networkingv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       ingressKind,
+			APIVersion: ingressAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      trait.GetName(),
+			Namespace: trait.GetNamespace(),
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			IngressClassName: trait.Spec.IngressClassName,
+		},
+	}
+	var hosts []string
+	seen := map[string]bool{}
+	for _, rule := range trait.Spec.Rules {
+		path := rule.Path
+		if path == "" {
+			path = defaultPath
+		}
+		servicePort := port
+		if rule.ServicePort != nil {
+			servicePort = *rule.ServicePort
+		}
+		ingress.Spec.Rules = append(ingress.Spec.Rules, networkingv1beta1.IngressRule{
+			Host: rule.Host,
+			IngressRuleValue: networkingv1beta1.IngressRuleValue{HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+				Paths: []networkingv1beta1.HTTPIngressPath{{
+					Path: path,
+					Backend: networkingv1beta1.IngressBackend{
+						ServiceName: service,
+						ServicePort: intstr.FromInt(int(servicePort)),
+					},
+				}},
+			}},
+		})
+		if rule.Host != "" && !seen[rule.Host] {
+			seen[rule.Host] = true
+			hosts = append(hosts, rule.Host)
+		}
+	}
+	if trait.Spec.TLSSecretName != nil {
+		ingress.Spec.TLS = []networkingv1beta1.IngressTLS{{Hosts: hosts, SecretName: *trait.Spec.TLSSecretName}}
+	}
+	return ingress
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.IngressTraitKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.IngressTrait{}).
+		// the addresses the ingresses are reachable at are reported by the traits
+		Owns(&networkingv1beta1.Ingress{}).
+		Complete(r)
+}
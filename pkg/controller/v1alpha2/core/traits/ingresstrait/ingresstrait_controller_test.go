@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingresstrait
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestBackendService(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web"},
+	}}
+	service := func(ports ...int64) *unstructured.Unstructured {
+		declared := []interface{}{}
+		for _, p := range ports {
+			declared = append(declared, map[string]interface{}{"port": p, "protocol": "TCP"})
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "web"},
+			"spec":       map[string]interface{}{"ports": declared},
+		}}
+	}
+
+	type want struct {
+		svc   *unstructured.Unstructured
+		ports []int32
+		err   error
+	}
+	cases := map[string]struct {
+		reason    string
+		resources []*unstructured.Unstructured
+		want      want
+	}{
+		"NoService": {
+			reason:    "An error should be returned if none of the resources is a Service.",
+			resources: []*unstructured.Unstructured{deploy},
+			want:      want{err: errors.New(errNoService)},
+		},
+		"NoPorts": {
+			reason:    "An error should be returned if the Service exposes no ports.",
+			resources: []*unstructured.Unstructured{deploy, service()},
+			want:      want{err: errors.Errorf(errFmtNoPorts, "web")},
+		},
+		"Ports": {
+			reason:    "The first Service and the ports it exposes should be returned.",
+			resources: []*unstructured.Unstructured{deploy, service(8080, 9090)},
+			want:      want{svc: service(8080, 9090), ports: []int32{8080, 9090}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc, ports, err := backendService(tc.resources)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nbackendService(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.svc, svc); diff != "" {
+				t.Errorf("\n%s\nbackendService(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ports, ports); diff != "" {
+				t.Errorf("\n%s\nbackendService(...): -want ports, +got ports:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderIngress(t *testing.T) {
+	class, secret := "nginx", "web-tls"
+	adminPort := int32(9090)
+
+	trait := func(spec oamv1alpha2.IngressTraitSpec) *oamv1alpha2.IngressTrait {
+		return &oamv1alpha2.IngressTrait{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-ingress"},
+			Spec:       spec,
+		}
+	}
+	rule := func(host, path string, port int) networkingv1beta1.IngressRule {
+		return networkingv1beta1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1beta1.IngressRuleValue{HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+				Paths: []networkingv1beta1.HTTPIngressPath{{
+					Path:    path,
+					Backend: networkingv1beta1.IngressBackend{ServiceName: "web", ServicePort: intstr.FromInt(port)},
+				}},
+			}},
+		}
+	}
+	ingress := func(spec networkingv1beta1.IngressSpec) *networkingv1beta1.Ingress {
+		return &networkingv1beta1.Ingress{
+			TypeMeta:   metav1.TypeMeta{Kind: ingressKind, APIVersion: ingressAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-ingress"},
+			Spec:       spec,
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		trait  *oamv1alpha2.IngressTrait
+		want   *networkingv1beta1.Ingress
+	}{
+		"Defaults": {
+			reason: "Rules should route the requests for / to the supplied port by default.",
+			trait:  trait(oamv1alpha2.IngressTraitSpec{Rules: []oamv1alpha2.IngressRule{{Host: "example.com"}}}),
+			want:   ingress(networkingv1beta1.IngressSpec{Rules: []networkingv1beta1.IngressRule{rule("example.com", "/", 8080)}}),
+		},
+		"Rules": {
+			reason: "Rules should route the requests for their host and path to their port, terminating TLS for their hosts.",
+			trait: trait(oamv1alpha2.IngressTraitSpec{
+				Rules: []oamv1alpha2.IngressRule{
+					{Host: "example.com", Path: "/api"},
+					{Host: "example.com", Path: "/admin", ServicePort: &adminPort},
+					{Path: "/health"},
+				},
+				IngressClassName: &class,
+				TLSSecretName:    &secret,
+			}),
+			want: ingress(networkingv1beta1.IngressSpec{
+				IngressClassName: &class,
+				TLS:              []networkingv1beta1.IngressTLS{{Hosts: []string{"example.com"}, SecretName: secret}},
+				Rules: []networkingv1beta1.IngressRule{
+					rule("example.com", "/api", 8080),
+					rule("example.com", "/admin", 9090),
+					rule("", "/health", 8080),
+				},
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := renderIngress(tc.trait, "web", 8080)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrenderIngress(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -29,24 +29,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/discovery"
-	"k8s.io/kube-openapi/pkg/util/proto"
-	"k8s.io/kubectl/pkg/explain"
-	"k8s.io/kubectl/pkg/util/openapi"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
 // Reconcile error strings.
 const (
-	errQueryOpenAPI            = "failed to query openAPI"
-	errPatchTobeScaledResource = "cannot patch the resource for scale"
+	errPatchTobeScaledResource = "cannot patch the scaled resource"
 	errScaleResource           = "cannot scale the resource"
 )
 
@@ -56,13 +52,18 @@ func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
 	if err != nil {
 		return err
 	}
+	scaler, err := NewSubresourceScaler(mgr.GetConfig(), dm)
+	if err != nil {
+		return err
+	}
 	reconciler := Reconciler{
-		Client:          mgr.GetClient(),
-		DiscoveryClient: *discovery.NewDiscoveryClientForConfigOrDie(mgr.GetConfig()),
-		dm:              dm,
-		log:             ctrl.Log.WithName("ManualScalarTrait"),
-		record:          event.NewAPIRecorder(mgr.GetEventRecorderFor("ManualScalarTrait")),
-		Scheme:          mgr.GetScheme(),
+		Client: mgr.GetClient(),
+		dm:     dm,
+		scaler: scaler,
+		log:    ctrl.Log.WithName("ManualScalarTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.ManualScalerTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("ManualScalarTrait"))),
+		Scheme: mgr.GetScheme(),
 	}
 	return reconciler.SetupWithManager(mgr)
 }
@@ -70,8 +71,8 @@ func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
 // Reconciler reconciles a ManualScalarTrait object
 type Reconciler struct {
 	client.Client
-	discovery.DiscoveryClient
 	dm     discoverymapper.DiscoveryMapper
+	scaler Scaler
 	log    logr.Logger
 	record event.Recorder
 	Scheme *runtime.Scheme
@@ -84,6 +85,7 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=core.oam.dev,resources=containerizedworkloads/status,verbs=get;
 // +kubebuilder:rbac:groups=core.oam.dev,resources=workloaddefinition,verbs=get;list;
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments/scale;statefulsets/scale,verbs=get;update
 func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 	mLog := r.log.WithValues("manualscalar trait", req.NamespacedName)
@@ -104,15 +106,14 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		eventObj = &manualScalar
 	}
 	// Fetch the workload instance this trait is referring to
-	workload, err := util.FetchWorkload(ctx, r, mLog, &manualScalar)
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &manualScalar)
 	if err != nil {
 		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
-		return util.ReconcileWaitResult, util.PatchCondition(
-			ctx, r, &manualScalar, cpv1alpha1.ReconcileError(errors.Wrap(err, util.ErrLocateWorkload)))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &manualScalar, cpv1alpha1.ReconcileError(err))
 	}
 
 	// Fetch the child resources list from the corresponding workload
-	resources, err := util.FetchWorkloadChildResources(ctx, mLog, r, r.dm, workload)
+	resources, err := util.FetchWorkloadChildResources(ctx, r, workload, wd)
 	if err != nil {
 		mLog.Error(err, "Error while fetching the workload child resources", "workload", workload.UnstructuredContent())
 		r.record.Event(eventObj, event.Warning(util.ErrFetchChildResources, err))
@@ -151,38 +152,31 @@ func (r *Reconciler) scaleResources(ctx context.Context, mLog logr.Logger,
 		Controller:         &isController,
 		BlockOwnerDeletion: &bod,
 	}
-	// prepare for openApi schema check
-	schemaDoc, err := r.DiscoveryClient.OpenAPISchema()
-	if err != nil {
-		return util.ReconcileWaitResult,
-			util.PatchCondition(ctx, r, &manualScalar, cpv1alpha1.ReconcileError(errors.Wrap(err, errQueryOpenAPI)))
-	}
-	document, err := openapi.NewOpenAPIData(schemaDoc)
-	if err != nil {
-		return util.ReconcileWaitResult,
-			util.PatchCondition(ctx, r, &manualScalar, cpv1alpha1.ReconcileError(errors.Wrap(err, errQueryOpenAPI)))
-	}
 	for _, res := range resources {
-		if locateReplicaField(document, res) {
-			found = true
-			resPatch := client.MergeFrom(res.DeepCopyObject())
-			mLog.Info("Get the resource the trait is going to modify",
-				"resource name", res.GetName(), "UID", res.GetUID())
-			cpmeta.AddOwnerReference(res, ownerRef)
-			err := unstructured.SetNestedField(res.Object, int64(manualScalar.Spec.ReplicaCount), "spec", "replicas")
-			if err != nil {
-				mLog.Error(err, "Failed to patch a resource for scaling")
-				return util.ReconcileWaitResult,
-					util.PatchCondition(ctx, r, &manualScalar, cpv1alpha1.ReconcileError(errors.Wrap(err, errPatchTobeScaledResource)))
-			}
-			// merge patch to scale the resource
-			if err := r.Patch(ctx, res, resPatch, client.FieldOwner(manualScalar.GetUID())); err != nil {
-				mLog.Error(err, "Failed to scale a resource")
-				return util.ReconcileWaitResult,
-					util.PatchCondition(ctx, r, &manualScalar, cpv1alpha1.ReconcileError(errors.Wrap(err, errScaleResource)))
-			}
-			mLog.Info("Successfully scaled a resource", "resource GVK", res.GroupVersionKind().String(),
-				"res UID", res.GetUID(), "target replica", manualScalar.Spec.ReplicaCount)
+		// set the replicas through the scale subresource, so that resources
+		// of any kind that serves one are scaled
+		scaled, err := r.scaler.Scale(ctx, res, manualScalar.Spec.ReplicaCount)
+		if err != nil {
+			mLog.Error(err, "Failed to scale a resource")
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &manualScalar, cpv1alpha1.ReconcileError(errors.Wrap(err, errScaleResource)))
+		}
+		if !scaled {
+			continue
+		}
+		found = true
+		mLog.Info("Successfully scaled a resource", "resource GVK", res.GroupVersionKind().String(),
+			"res UID", res.GetUID(), "target replica", manualScalar.Spec.ReplicaCount)
+		if hasOwnerReference(res, manualScalar.GetUID()) {
+			continue
+		}
+		resPatch := client.MergeFrom(res.DeepCopyObject())
+		cpmeta.AddOwnerReference(res, ownerRef)
+		// merge patch to own the resource
+		if err := r.Patch(ctx, res, resPatch, client.FieldOwner(manualScalar.GetUID())); err != nil {
+			mLog.Error(err, "Failed to patch a scaled resource")
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &manualScalar, cpv1alpha1.ReconcileError(errors.Wrap(err, errPatchTobeScaledResource)))
 		}
 	}
 	if !found {
@@ -193,35 +187,15 @@ func (r *Reconciler) scaleResources(ctx context.Context, mLog logr.Logger,
 	return ctrl.Result{}, nil
 }
 
-// locateReplicaField call openapi RESTFUL end point to fetch the schema of a given resource and try to see
-// 	if it has a spec.replicas filed that is of type integer. We will apply duck typing to modify the fields there
-//  assuming that the fields is used to control the number of instances of this resource
-//  NOTE: This only works if the resource CRD has a structural schema, all `apiextensions.k8s.io/v1` CRDs do
-// https://kubernetes.io/docs/tasks/extend-kubernetes/custom-resources/custom-resource-definitions/#specifying-a-structural-schema
-func locateReplicaField(document openapi.Resources, res *unstructured.Unstructured) bool {
-	// this is the most common path for replicas fields
-	replicaFieldPath := []string{"spec", "replicas"}
-	gv, err := schema.ParseGroupVersion(res.GetAPIVersion())
-	if err != nil {
-		return false
-	}
-	// we look up the resource schema definition by its GVK
-	schema := document.LookupResource(schema.GroupVersionKind{
-		Group:   gv.Group,
-		Version: gv.Version,
-		Kind:    res.GetKind(),
-	})
-	// we try to see if there is a spec.replicas fields in its definition
-	field, err := explain.LookupSchemaForField(schema, replicaFieldPath)
-	if err != nil || field == nil {
-		return false
-	}
-	// we also verify that it is of type integer to further narrow down the candidates
-	replicaField, ok := field.(*proto.Primitive)
-	if !ok || replicaField.Type != "integer" {
-		return false
+// hasOwnerReference returns true if the supplied resource is owned by the
+// object of the supplied UID.
+func hasOwnerReference(res *unstructured.Unstructured, uid types.UID) bool {
+	for _, ref := range res.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
 	}
-	return true
+	return false
 }
 
 // SetupWithManager to setup k8s controller.
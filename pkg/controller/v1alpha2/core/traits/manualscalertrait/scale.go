@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manualscalertrait
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/scale"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	errFmtMapResource       = "cannot determine the resource of %s"
+	errFmtDiscoverResources = "cannot discover the resources of %s"
+	errFmtGetScale          = "cannot get the scale of %s %q"
+	errFmtUpdateScale       = "cannot update the scale of %s %q"
+)
+
+// A Scaler sets the replicas of resources.
+type Scaler interface {
+	// Scale the supplied resource to the supplied number of replicas. It
+	// returns false if resources of its kind can't be scaled.
+	Scale(ctx context.Context, res *unstructured.Unstructured, replicas int32) (bool, error)
+}
+
+// A ScaleFn sets the replicas of resources.
+type ScaleFn func(ctx context.Context, res *unstructured.Unstructured, replicas int32) (bool, error)
+
+// Scale the supplied resource to the supplied number of replicas.
+func (fn ScaleFn) Scale(ctx context.Context, res *unstructured.Unstructured, replicas int32) (bool, error) {
+	return fn(ctx, res, replicas)
+}
+
+// A SubresourceScaler sets the replicas of resources through their scale
+// subresource, so that it scales resources of any kind that serves one, like
+// Deployments, StatefulSets and custom resources whose CRD enables it,
+// without knowing where their replicas are specified.
+type SubresourceScaler struct {
+	dm        discoverymapper.DiscoveryMapper
+	discovery discovery.ServerResourcesInterface
+	scales    scale.ScalesGetter
+}
+
+// NewSubresourceScaler returns a SubresourceScaler of the API server of the
+// supplied config.
+func NewSubresourceScaler(cfg *rest.Config, dm discoverymapper.DiscoveryMapper) (*SubresourceScaler, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// the scale client modifies the config it is created from
+	scales, err := scale.NewForConfig(rest.CopyConfig(cfg), resourceMapper{dm: dm},
+		dynamic.LegacyAPIPathResolverFunc, scale.NewDiscoveryScaleKindResolver(dc))
+	if err != nil {
+		return nil, err
+	}
+	return &SubresourceScaler{dm: dm, discovery: dc, scales: scales}, nil
+}
+
+// Scale the supplied resource to the supplied number of replicas. It returns
+// false if resources of its kind serve no scale subresource.
+func (s *SubresourceScaler) Scale(ctx context.Context, res *unstructured.Unstructured, replicas int32) (bool, error) {
+	gvk := res.GroupVersionKind()
+	mapping, err := s.dm.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtMapResource, gvk)
+	}
+	scalable, err := util.HasScaleSubresource(s.discovery, mapping.Resource)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtDiscoverResources, mapping.Resource.GroupVersion())
+	}
+	if !scalable {
+		return false, nil
+	}
+	gr := mapping.Resource.GroupResource()
+	scales := s.scales.Scales(res.GetNamespace())
+	current, err := scales.Get(ctx, gr, res.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return true, errors.Wrapf(err, errFmtGetScale, gvk.Kind, res.GetName())
+	}
+	if current.Spec.Replicas == replicas {
+		return true, nil
+	}
+	current.Spec.Replicas = replicas
+	if _, err := scales.Update(ctx, gr, current, metav1.UpdateOptions{}); err != nil {
+		return true, errors.Wrapf(err, errFmtUpdateScale, gvk.Kind, res.GetName())
+	}
+	return true, nil
+}
+
+// resourceMapper resolves the resources the scale client is asked for by the
+// RESTMapper of a DiscoveryMapper.
+type resourceMapper struct {
+	dm discoverymapper.DiscoveryMapper
+}
+
+func (m resourceMapper) ResourceFor(gvr schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	mapper, err := m.dm.GetMapper()
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return mapper.ResourceFor(gvr)
+}
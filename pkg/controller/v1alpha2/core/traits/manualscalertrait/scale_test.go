@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manualscalertrait
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/scale"
+	fakescale "k8s.io/client-go/scale/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestSubresourceScaler(t *testing.T) {
+	errBoom := errors.New("boom")
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	deploy := &unstructured.Unstructured{}
+	deploy.SetAPIVersion("apps/v1")
+	deploy.SetKind("Deployment")
+	deploy.SetNamespace("ns")
+	deploy.SetName("web")
+
+	dm := &mock.DiscoveryMapper{MockRESTMapping: func(schema.GroupKind, ...string) (*meta.RESTMapping, error) {
+		return &meta.RESTMapping{Resource: deployments}, nil
+	}}
+	discovery := func(resources ...string) *fakediscovery.FakeDiscovery {
+		list := &metav1.APIResourceList{GroupVersion: "apps/v1"}
+		for _, r := range resources {
+			list.APIResources = append(list.APIResources, metav1.APIResource{Name: r})
+		}
+		return &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{list}}}
+	}
+	scales := func(replicas int32, getErr, updateErr error) scale.ScalesGetter {
+		s := &fakescale.FakeScaleClient{}
+		s.AddReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &autoscalingv1.Scale{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web"},
+				Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+			}, getErr
+		})
+		s.AddReactor("update", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, action.(clienttesting.UpdateAction).GetObject(), updateErr
+		})
+		return s
+	}
+
+	type fields struct {
+		s *SubresourceScaler
+	}
+	type want struct {
+		scaled  bool
+		err     error
+		actions []string
+	}
+	cases := map[string]struct {
+		reason string
+		fields fields
+		want   want
+	}{
+		"MapError": {
+			reason: "Errors mapping the kind of the resource should be returned.",
+			fields: fields{s: &SubresourceScaler{
+				dm: &mock.DiscoveryMapper{MockRESTMapping: func(schema.GroupKind, ...string) (*meta.RESTMapping, error) {
+					return nil, errBoom
+				}},
+			}},
+			want: want{err: errors.Wrapf(errBoom, errFmtMapResource, deploy.GroupVersionKind())},
+		},
+		"DiscoveryError": {
+			reason: "Errors discovering the resources of the group version should be returned.",
+			fields: fields{s: &SubresourceScaler{
+				dm:        dm,
+				discovery: &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}},
+			}},
+			want: want{err: errors.Wrapf(errors.New(`GroupVersion "apps/v1" not found`), errFmtDiscoverResources, deployments.GroupVersion())},
+		},
+		"NotScalable": {
+			reason: "Resources that serve no scale subresource should not be scaled.",
+			fields: fields{s: &SubresourceScaler{
+				dm:        dm,
+				discovery: discovery("deployments", "deployments/status"),
+				scales:    scales(1, nil, nil),
+			}},
+			want: want{scaled: false},
+		},
+		"GetScaleError": {
+			reason: "Errors getting the scale of the resource should be returned.",
+			fields: fields{s: &SubresourceScaler{
+				dm:        dm,
+				discovery: discovery("deployments", "deployments/scale"),
+				scales:    scales(1, errBoom, nil),
+			}},
+			want: want{scaled: true, err: errors.Wrapf(errBoom, errFmtGetScale, "Deployment", "web"), actions: []string{"get"}},
+		},
+		"AlreadyScaled": {
+			reason: "Resources that have the desired replicas should not be updated.",
+			fields: fields{s: &SubresourceScaler{
+				dm:        dm,
+				discovery: discovery("deployments", "deployments/scale"),
+				scales:    scales(3, nil, nil),
+			}},
+			want: want{scaled: true, actions: []string{"get"}},
+		},
+		"UpdateScaleError": {
+			reason: "Errors updating the scale of the resource should be returned.",
+			fields: fields{s: &SubresourceScaler{
+				dm:        dm,
+				discovery: discovery("deployments", "deployments/scale"),
+				scales:    scales(1, nil, errBoom),
+			}},
+			want: want{scaled: true, err: errors.Wrapf(errBoom, errFmtUpdateScale, "Deployment", "web"), actions: []string{"get", "update"}},
+		},
+		"Scaled": {
+			reason: "The replicas of resources that serve a scale subresource should be updated.",
+			fields: fields{s: &SubresourceScaler{
+				dm:        dm,
+				discovery: discovery("deployments", "deployments/scale"),
+				scales:    scales(1, nil, nil),
+			}},
+			want: want{scaled: true, actions: []string{"get", "update"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			scaled, err := tc.fields.s.Scale(context.Background(), deploy, 3)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nScale(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.scaled, scaled); diff != "" {
+				t.Errorf("\n%s\nScale(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			var actions []string
+			if f, ok := tc.fields.s.scales.(*fakescale.FakeScaleClient); ok {
+				for _, a := range f.Actions() {
+					if a.GetResource().Resource != "deployments" {
+						t.Errorf("\n%s\nScale(...): want the scale of deployments, got %q", tc.reason, a.GetResource().Resource)
+					}
+					// the fake scale client only records the subresource of
+					// get actions
+					if a.GetVerb() == "get" && a.GetSubresource() != "scale" {
+						t.Errorf("\n%s\nScale(...): want the scale subresource, got %q", tc.reason, a.GetSubresource())
+					}
+					if u, ok := a.(clienttesting.UpdateAction); ok {
+						if got := u.GetObject().(*autoscalingv1.Scale).Spec.Replicas; got != 3 {
+							t.Errorf("\n%s\nScale(...): want 3 replicas, got %d", tc.reason, got)
+						}
+					}
+					actions = append(actions, a.GetVerb())
+				}
+			}
+			if diff := cmp.Diff(tc.want.actions, actions); diff != "" {
+				t.Errorf("\n%s\nScale(...): -want actions, +got actions:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricstrait
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errNoService          = "the workload has no Service"
+	errFmtNoServiceLabels = "Service %q has no labels to select it by"
+	errNoPodSelector      = "the workload has no resource that selects its pods by labels"
+	errFmtGetSelector     = "cannot get the selector of %s %q"
+	errSelectTargets      = "cannot select the targets of the monitor"
+	errRenderMonitor      = "cannot render the monitor"
+	errApplyMonitor       = "cannot apply the monitor"
+)
+
+// monitorAPIVersion is the API version of the monitors of the Prometheus
+// Operator.
+const monitorAPIVersion = "monitoring.coreos.com/v1"
+
+var (
+	serviceKind       = reflect.TypeOf(corev1.Service{}).Name()
+	serviceAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// Setup adds a controller that reconciles MetricsTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		dm:     dm,
+		log:    ctrl.Log.WithName("MetricsTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.MetricsTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("MetricsTrait"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a MetricsTrait object
+type Reconciler struct {
+	client.Client
+	dm     discoverymapper.DiscoveryMapper
+	log    logr.Logger
+	record event.Recorder
+	Scheme *runtime.Scheme
+}
+
+// Reconcile a MetricsTrait by applying a ServiceMonitor that scrapes the pods
+// behind the Service of its workload, or a PodMonitor that scrapes the pods of
+// its workload directly. The pods are annotated for scraping by the
+// ApplicationConfiguration controller.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=metricstraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=metricstraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("metrics trait", req.NamespacedName)
+
+	mLog.Info("Reconcile metrics trait")
+
+	var trait oamv1alpha2.MetricsTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "metricsTrait", trait.Name)
+		eventObj = &trait
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	resources, err := util.FetchWorkloadChildResources(ctx, r, workload, wd)
+	if err != nil {
+		mLog.Error(err, "Error while fetching the workload child resources", "workload", workload.UnstructuredContent())
+		r.record.Event(eventObj, event.Warning(util.ErrFetchChildResources, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(fmt.Errorf(util.ErrFetchChildResources)))
+	}
+	// the workload may be a Service or select its pods itself
+	resources = append(resources, workload)
+	selector, err := targetSelector(monitorKind(&trait), resources)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errSelectTargets, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errSelectTargets)))
+	}
+
+	monitor := renderMonitor(&trait, selector)
+	// the monitor is deleted with the trait
+	if err := ctrl.SetControllerReference(&trait, monitor, r.Scheme); err != nil {
+		r.record.Event(eventObj, event.Warning(errRenderMonitor, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderMonitor)))
+	}
+	// server side apply, only the fields we set are touched
+	if err := r.Patch(ctx, monitor, client.Apply, client.ForceOwnership, client.FieldOwner(trait.GetUID())); err != nil {
+		mLog.Error(err, "Failed to apply the monitor")
+		r.record.Event(eventObj, event.Warning(errApplyMonitor, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyMonitor)))
+	}
+	r.record.Event(eventObj, event.Normal("Monitor applied",
+		fmt.Sprintf("Trait `%s` successfully server side patched a %s `%s`", trait.Name, monitor.GetKind(), monitor.GetName())))
+
+	trait.Status.MonitorReference = &cpv1alpha1.TypedReference{
+		APIVersion: monitor.GetAPIVersion(),
+		Kind:       monitor.GetKind(),
+		Name:       monitor.GetName(),
+		UID:        monitor.GetUID(),
+	}
+	if err := r.Status().Update(ctx, &trait); err != nil {
+		return util.ReconcileWaitResult, err
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// monitorKind returns the kind of the monitor of the supplied trait.
+func monitorKind(trait *oamv1alpha2.MetricsTrait) oamv1alpha2.MonitorKind {
+	if trait.Spec.MonitorKind == "" {
+		return oamv1alpha2.MonitorKindServiceMonitor
+	}
+	return trait.Spec.MonitorKind
+}
+
+// targetSelector returns the labels a monitor of the supplied kind selects
+// its targets by. ServiceMonitors select the first of the supplied resources
+// that is a Service by its labels. PodMonitors select pods by the labels the
+// first of the supplied resources with a label selector, e.g. a Deployment,
+// selects them by.
+func targetSelector(kind oamv1alpha2.MonitorKind, resources []*unstructured.Unstructured) (map[string]string, error) {
+	for _, res := range resources {
+		if kind == oamv1alpha2.MonitorKindPodMonitor {
+			labels, found, err := unstructured.NestedStringMap(res.Object, "spec", "selector", "matchLabels")
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtGetSelector, res.GetKind(), res.GetName())
+			}
+			if found && len(labels) > 0 {
+				return labels, nil
+			}
+			continue
+		}
+		if res.GetKind() != serviceKind || res.GetAPIVersion() != serviceAPIVersion {
+			continue
+		}
+		if len(res.GetLabels()) == 0 {
+			return nil, errors.Errorf(errFmtNoServiceLabels, res.GetName())
+		}
+		return res.GetLabels(), nil
+	}
+	if kind == oamv1alpha2.MonitorKindPodMonitor {
+		return nil, errors.New(errNoPodSelector)
+	}
+	return nil, errors.New(errNoService)
+}
+
+// renderMonitor returns the ServiceMonitor or PodMonitor of the supplied
+// trait, that scrapes the targets of the supplied labels in the namespace of
+// the trait. ServiceMonitors scrape the target port of the trait, since its
+// name may differ from the name of the port of the Service. PodMonitors
+// scrape the port of that name, or the target port of that number.
+func renderMonitor(trait *oamv1alpha2.MetricsTrait, selector map[string]string) *unstructured.Unstructured {
+	kind := monitorKind(trait)
+	endpoint := map[string]interface{}{}
+	switch {
+	case kind == oamv1alpha2.MonitorKindPodMonitor && trait.Spec.Port.Type == intstr.String:
+		endpoint["port"] = trait.Spec.Port.StrVal
+	case trait.Spec.Port.Type == intstr.String:
+		endpoint["targetPort"] = trait.Spec.Port.StrVal
+	default:
+		endpoint["targetPort"] = int64(trait.Spec.Port.IntVal)
+	}
+	if trait.Spec.Path != "" {
+		endpoint["path"] = trait.Spec.Path
+	}
+	if trait.Spec.Scheme != "" {
+		endpoint["scheme"] = trait.Spec.Scheme
+	}
+	if trait.Spec.Interval != "" {
+		endpoint["interval"] = trait.Spec.Interval
+	}
+	matchLabels := make(map[string]interface{}, len(selector))
+	for k, v := range selector {
+		matchLabels[k] = v
+	}
+	endpoints := "endpoints"
+	if kind == oamv1alpha2.MonitorKindPodMonitor {
+		endpoints = "podMetricsEndpoints"
+	}
+
+	monitor := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector":          map[string]interface{}{"matchLabels": matchLabels},
+			"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{trait.GetNamespace()}},
+			endpoints:           []interface{}{endpoint},
+		},
+	}}
+	monitor.SetAPIVersion(monitorAPIVersion)
+	monitor.SetKind(string(kind))
+	monitor.SetNamespace(trait.GetNamespace())
+	monitor.SetName(trait.GetName())
+	return monitor
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.MetricsTraitKind)
+	// monitors are not owned, so that the controller starts in clusters
+	// without the Prometheus Operator
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.MetricsTrait{}).
+		Complete(r)
+}
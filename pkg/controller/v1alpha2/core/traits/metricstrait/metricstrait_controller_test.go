@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricstrait
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestTargetSelector(t *testing.T) {
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"pod": "web"}},
+		},
+	}}
+	service := func(labels map[string]interface{}) *unstructured.Unstructured {
+		metadata := map[string]interface{}{"name": "web"}
+		if labels != nil {
+			metadata["labels"] = labels
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   metadata,
+			"spec":       map[string]interface{}{"selector": map[string]interface{}{"pod": "web"}},
+		}}
+	}
+
+	type want struct {
+		selector map[string]string
+		err      error
+	}
+	cases := map[string]struct {
+		reason    string
+		kind      oamv1alpha2.MonitorKind
+		resources []*unstructured.Unstructured
+		want      want
+	}{
+		"NoService": {
+			reason:    "An error should be returned if none of the resources is a Service.",
+			kind:      oamv1alpha2.MonitorKindServiceMonitor,
+			resources: []*unstructured.Unstructured{deploy},
+			want:      want{err: errors.New(errNoService)},
+		},
+		"NoServiceLabels": {
+			reason:    "An error should be returned if the Service has no labels to select it by.",
+			kind:      oamv1alpha2.MonitorKindServiceMonitor,
+			resources: []*unstructured.Unstructured{deploy, service(nil)},
+			want:      want{err: errors.Errorf(errFmtNoServiceLabels, "web")},
+		},
+		"ServiceMonitor": {
+			reason:    "ServiceMonitors should select the Service by its labels.",
+			kind:      oamv1alpha2.MonitorKindServiceMonitor,
+			resources: []*unstructured.Unstructured{deploy, service(map[string]interface{}{"svc": "web"})},
+			want:      want{selector: map[string]string{"svc": "web"}},
+		},
+		"NoPodSelector": {
+			reason:    "An error should be returned if none of the resources selects pods by labels.",
+			kind:      oamv1alpha2.MonitorKindPodMonitor,
+			resources: []*unstructured.Unstructured{service(map[string]interface{}{"svc": "web"})},
+			want:      want{err: errors.New(errNoPodSelector)},
+		},
+		"PodMonitor": {
+			reason:    "PodMonitors should select the pods by the labels their controller selects them by.",
+			kind:      oamv1alpha2.MonitorKindPodMonitor,
+			resources: []*unstructured.Unstructured{service(map[string]interface{}{"svc": "web"}), deploy},
+			want:      want{selector: map[string]string{"pod": "web"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := targetSelector(tc.kind, tc.resources)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntargetSelector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.selector, got); diff != "" {
+				t.Errorf("\n%s\ntargetSelector(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderMonitor(t *testing.T) {
+	trait := func(spec oamv1alpha2.MetricsTraitSpec) *oamv1alpha2.MetricsTrait {
+		return &oamv1alpha2.MetricsTrait{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-metrics"},
+			Spec:       spec,
+		}
+	}
+	monitor := func(kind, endpoints string, endpoint map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": monitorAPIVersion,
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"namespace": "ns", "name": "web-metrics"},
+			"spec": map[string]interface{}{
+				"selector":          map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+				"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{"ns"}},
+				endpoints:           []interface{}{endpoint},
+			},
+		}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		trait  *oamv1alpha2.MetricsTrait
+		want   *unstructured.Unstructured
+	}{
+		"ServiceMonitor": {
+			reason: "A ServiceMonitor scraping the target port should be rendered by default.",
+			trait:  trait(oamv1alpha2.MetricsTraitSpec{Port: intstr.FromInt(9090)}),
+			want:   monitor("ServiceMonitor", "endpoints", map[string]interface{}{"targetPort": int64(9090)}),
+		},
+		"NamedTargetPort": {
+			reason: "ServiceMonitors should scrape named ports as target ports.",
+			trait: trait(oamv1alpha2.MetricsTraitSpec{
+				Port:     intstr.FromString("metrics"),
+				Path:     "/stats",
+				Scheme:   "https",
+				Interval: "30s",
+			}),
+			want: monitor("ServiceMonitor", "endpoints", map[string]interface{}{
+				"targetPort": "metrics",
+				"path":       "/stats",
+				"scheme":     "https",
+				"interval":   "30s",
+			}),
+		},
+		"PodMonitor": {
+			reason: "PodMonitors should scrape named ports by name.",
+			trait: trait(oamv1alpha2.MetricsTraitSpec{
+				Port:        intstr.FromString("metrics"),
+				MonitorKind: oamv1alpha2.MonitorKindPodMonitor,
+			}),
+			want: monitor("PodMonitor", "podMetricsEndpoints", map[string]interface{}{"port": "metrics"}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := renderMonitor(tc.trait, map[string]string{"app": "web"})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrenderMonitor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
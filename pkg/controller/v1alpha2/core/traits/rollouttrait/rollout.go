@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollouttrait
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/health"
+)
+
+const (
+	// defaultProgressDeadlineSeconds a step may take to become healthy.
+	defaultProgressDeadlineSeconds = 600
+
+	// progressWait is how long a rollout waits before it evaluates the
+	// health of a step again.
+	progressWait = 10 * time.Second
+)
+
+const (
+	msgFmtAwaitPromotion     = "step %d awaits its promotion"
+	msgFmtRolledBack         = "step %d did not become healthy within %ds: %s"
+	reasonFmtNotReady        = "%s %q has %d of %d replicas ready"
+	reasonFmtNotObserved     = "%s %q has not observed its latest generation"
+	reasonFmtUnhealthyStatus = "%s %q is unhealthy: %s"
+)
+
+// rolloutSteps returns the steps of the supplied rollout.
+func rolloutSteps(spec oamv1alpha2.RolloutTraitSpec) []oamv1alpha2.RolloutStep {
+	if len(spec.Steps) == 0 {
+		return []oamv1alpha2.RolloutStep{{Percent: 100}}
+	}
+	return spec.Steps
+}
+
+// progressDeadline returns how long a step of the supplied rollout may take
+// to become healthy.
+func progressDeadline(spec oamv1alpha2.RolloutTraitSpec) time.Duration {
+	if spec.ProgressDeadlineSeconds == nil {
+		return defaultProgressDeadlineSeconds * time.Second
+	}
+	return time.Duration(*spec.ProgressDeadlineSeconds) * time.Second
+}
+
+// replicasOf returns the replicas of the workloads of the target and source
+// revisions of the supplied rollout in the supplied state. The target
+// revision runs the share of the replicas of its current step, rounded up.
+func replicasOf(spec oamv1alpha2.RolloutTraitSpec, status oamv1alpha2.RolloutTraitStatus) (target, source int32) {
+	switch status.Phase {
+	case oamv1alpha2.RolloutPhaseSucceeded:
+		return spec.Replicas, 0
+	case oamv1alpha2.RolloutPhaseRolledBack:
+		return 0, spec.Replicas
+	}
+	steps := rolloutSteps(spec)
+	step := steps[currentStep(steps, status)]
+	target = (spec.Replicas*step.Percent + 99) / 100
+	if target > spec.Replicas {
+		target = spec.Replicas
+	}
+	return target, spec.Replicas - target
+}
+
+// currentStep returns the index of the current step of the supplied state,
+// which is the last of the supplied steps if they were shortened since.
+func currentStep(steps []oamv1alpha2.RolloutStep, status oamv1alpha2.RolloutTraitStatus) int32 {
+	if int(status.CurrentStep) >= len(steps) {
+		return int32(len(steps) - 1)
+	}
+	return status.CurrentStep
+}
+
+// promoted returns true if the step of the supplied index of the rollout of
+// the supplied revision is promoted by the promotion of the supplied rollout.
+func promoted(spec oamv1alpha2.RolloutTraitSpec, revision string, step int32) bool {
+	p := spec.Promotion
+	return p != nil && p.RevisionName == revision && p.Steps > step
+}
+
+// advance returns the state of the supplied rollout once the health of the
+// workload of its target revision at its current step was observed at the
+// supplied time, and how long to wait before it is observed again. A healthy
+// step is promoted to the next once it paused for its pause seconds and, if
+// its gate is Manual, it is promoted by hand. The rollout succeeds once its
+// last step is promoted, and is rolled back if a step doesn't become healthy
+// within the progress deadline.
+func advance(spec oamv1alpha2.RolloutTraitSpec, status oamv1alpha2.RolloutTraitStatus, healthy bool,
+	diagnosis string, now time.Time) (oamv1alpha2.RolloutTraitStatus, time.Duration) {
+	steps := rolloutSteps(spec)
+	status.CurrentStep = currentStep(steps, status)
+	step := steps[status.CurrentStep]
+	started := now
+	if status.StepStartTime != nil {
+		started = status.StepStartTime.Time
+	}
+	elapsed := now.Sub(started)
+
+	if !healthy {
+		if deadline := progressDeadline(spec); elapsed >= deadline {
+			status.Phase = oamv1alpha2.RolloutPhaseRolledBack
+			status.Message = fmt.Sprintf(msgFmtRolledBack, status.CurrentStep, int64(deadline.Seconds()), diagnosis)
+			return status, 0
+		}
+		status.Phase = oamv1alpha2.RolloutPhaseProgressing
+		status.Message = diagnosis
+		return status, progressWait
+	}
+	if pause := time.Duration(step.PauseSeconds) * time.Second; elapsed < pause {
+		status.Phase = oamv1alpha2.RolloutPhaseProgressing
+		status.Message = ""
+		return status, pause - elapsed
+	}
+	if step.Gate == oamv1alpha2.RolloutGateManual && !promoted(spec, status.TargetRevision, status.CurrentStep) {
+		status.Phase = oamv1alpha2.RolloutPhasePaused
+		status.Message = fmt.Sprintf(msgFmtAwaitPromotion, status.CurrentStep)
+		return status, 0
+	}
+	status.Message = ""
+	if int(status.CurrentStep) == len(steps)-1 {
+		status.Phase = oamv1alpha2.RolloutPhaseSucceeded
+		return status, 0
+	}
+	status.Phase = oamv1alpha2.RolloutPhaseProgressing
+	status.CurrentStep++
+	t := metav1.NewTime(now)
+	status.StepStartTime = &t
+	return status, progressWait
+}
+
+// ready returns true if the supplied resource runs the supplied number of
+// ready replicas. The health of resources without replicas is evaluated by
+// the conventions of their status, and resources whose health can't be told
+// are considered ready.
+func ready(res *unstructured.Unstructured, replicas int32) (bool, string) {
+	if _, scaled, _ := unstructured.NestedFieldNoCopy(res.Object, "spec", "replicas"); scaled {
+		if observed, found, _ := unstructured.NestedInt64(res.Object, "status", "observedGeneration"); found && observed < res.GetGeneration() {
+			return false, fmt.Sprintf(reasonFmtNotObserved, res.GetKind(), res.GetName())
+		}
+		// readyReplicas are omitted while there are none
+		readyReplicas, _, _ := unstructured.NestedInt64(res.Object, "status", "readyReplicas")
+		if readyReplicas < int64(replicas) {
+			return false, fmt.Sprintf(reasonFmtNotReady, res.GetKind(), res.GetName(), readyReplicas, replicas)
+		}
+		return true, ""
+	}
+	if status, diagnosis, known := health.EvaluateStatus(res); known && status != oamv1alpha2.StatusHealthy {
+		return false, fmt.Sprintf(reasonFmtUnhealthyStatus, res.GetKind(), res.GetName(), diagnosis)
+	}
+	return true, ""
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollouttrait
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestReplicasOf(t *testing.T) {
+	spec := oamv1alpha2.RolloutTraitSpec{
+		Replicas: 5,
+		Steps:    []oamv1alpha2.RolloutStep{{Percent: 10}, {Percent: 50}, {Percent: 100}},
+	}
+
+	type want struct {
+		target int32
+		source int32
+	}
+	cases := map[string]struct {
+		reason string
+		spec   oamv1alpha2.RolloutTraitSpec
+		status oamv1alpha2.RolloutTraitStatus
+		want   want
+	}{
+		"RoundUp": {
+			reason: "The share of the target revision should be rounded up.",
+			spec:   spec,
+			status: oamv1alpha2.RolloutTraitStatus{Phase: oamv1alpha2.RolloutPhaseProgressing, CurrentStep: 1},
+			want:   want{target: 3, source: 2},
+		},
+		"AtLeastOne": {
+			reason: "The target revision should run a replica from the first step on.",
+			spec:   spec,
+			status: oamv1alpha2.RolloutTraitStatus{Phase: oamv1alpha2.RolloutPhaseProgressing},
+			want:   want{target: 1, source: 4},
+		},
+		"ShortenedSteps": {
+			reason: "A rollout past its last step should run the replicas of its last step.",
+			spec:   spec,
+			status: oamv1alpha2.RolloutTraitStatus{Phase: oamv1alpha2.RolloutPhasePaused, CurrentStep: 7},
+			want:   want{target: 5, source: 0},
+		},
+		"DefaultSteps": {
+			reason: "A rollout without steps should shift all the replicas at once.",
+			spec:   oamv1alpha2.RolloutTraitSpec{Replicas: 5},
+			status: oamv1alpha2.RolloutTraitStatus{Phase: oamv1alpha2.RolloutPhaseProgressing},
+			want:   want{target: 5, source: 0},
+		},
+		"Succeeded": {
+			reason: "A succeeded rollout should run all the replicas of the target revision.",
+			spec:   spec,
+			status: oamv1alpha2.RolloutTraitStatus{Phase: oamv1alpha2.RolloutPhaseSucceeded},
+			want:   want{target: 5, source: 0},
+		},
+		"RolledBack": {
+			reason: "A rolled back rollout should run all the replicas of the source revision.",
+			spec:   spec,
+			status: oamv1alpha2.RolloutTraitStatus{Phase: oamv1alpha2.RolloutPhaseRolledBack, CurrentStep: 1},
+			want:   want{target: 0, source: 5},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			target, source := replicasOf(tc.spec, tc.status)
+			if diff := cmp.Diff(tc.want, want{target: target, source: source}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nreplicasOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	now := time.Now()
+	started := metav1.NewTime(now.Add(-time.Minute))
+	nowTime := metav1.NewTime(now)
+	deadline := int32(30)
+	spec := oamv1alpha2.RolloutTraitSpec{
+		Replicas: 4,
+		Steps: []oamv1alpha2.RolloutStep{
+			{Percent: 25},
+			{Percent: 50, Gate: oamv1alpha2.RolloutGateManual},
+			{Percent: 100, PauseSeconds: 120},
+		},
+		ProgressDeadlineSeconds: &deadline,
+	}
+	status := func(phase oamv1alpha2.RolloutPhase, step int32, start *metav1.Time, msg string) oamv1alpha2.RolloutTraitStatus {
+		return oamv1alpha2.RolloutTraitStatus{
+			Phase:          phase,
+			TargetRevision: "web-v2",
+			SourceRevision: "web-v1",
+			CurrentStep:    step,
+			StepStartTime:  start,
+			Message:        msg,
+		}
+	}
+	manual := spec.DeepCopy()
+	manual.Promotion = &oamv1alpha2.RolloutPromotion{RevisionName: "web-v2", Steps: 2}
+	stale := spec.DeepCopy()
+	stale.Promotion = &oamv1alpha2.RolloutPromotion{RevisionName: "web-v1", Steps: 2}
+
+	type want struct {
+		status oamv1alpha2.RolloutTraitStatus
+		wait   time.Duration
+	}
+	cases := map[string]struct {
+		reason    string
+		spec      oamv1alpha2.RolloutTraitSpec
+		status    oamv1alpha2.RolloutTraitStatus
+		healthy   bool
+		diagnosis string
+		want      want
+	}{
+		"Unhealthy": {
+			reason:    "An unhealthy step within its deadline should be observed again.",
+			spec:      spec,
+			status:    status(oamv1alpha2.RolloutPhaseProgressing, 0, &nowTime, ""),
+			diagnosis: "not ready",
+			want: want{
+				status: status(oamv1alpha2.RolloutPhaseProgressing, 0, &nowTime, "not ready"),
+				wait:   progressWait,
+			},
+		},
+		"RolledBack": {
+			reason:    "A step that is unhealthy past its deadline should be rolled back.",
+			spec:      spec,
+			status:    status(oamv1alpha2.RolloutPhaseProgressing, 0, &started, ""),
+			diagnosis: "not ready",
+			want: want{
+				status: status(oamv1alpha2.RolloutPhaseRolledBack, 0, &started, fmt.Sprintf(msgFmtRolledBack, 0, 30, "not ready")),
+			},
+		},
+		"Promoted": {
+			reason:  "A healthy step with an Auto gate should be promoted to the next.",
+			spec:    spec,
+			status:  status(oamv1alpha2.RolloutPhaseProgressing, 0, &started, ""),
+			healthy: true,
+			want: want{
+				status: status(oamv1alpha2.RolloutPhaseProgressing, 1, &nowTime, ""),
+				wait:   progressWait,
+			},
+		},
+		"AwaitPromotion": {
+			reason:  "A healthy step with a Manual gate should pause until it is promoted.",
+			spec:    spec,
+			status:  status(oamv1alpha2.RolloutPhaseProgressing, 1, &started, ""),
+			healthy: true,
+			want: want{
+				status: status(oamv1alpha2.RolloutPhasePaused, 1, &started, fmt.Sprintf(msgFmtAwaitPromotion, 1)),
+			},
+		},
+		"StalePromotion": {
+			reason:  "The promotion of another revision should not promote a step.",
+			spec:    *stale,
+			status:  status(oamv1alpha2.RolloutPhasePaused, 1, &started, fmt.Sprintf(msgFmtAwaitPromotion, 1)),
+			healthy: true,
+			want: want{
+				status: status(oamv1alpha2.RolloutPhasePaused, 1, &started, fmt.Sprintf(msgFmtAwaitPromotion, 1)),
+			},
+		},
+		"ManuallyPromoted": {
+			reason:  "A healthy step with a Manual gate should be promoted once it is promoted by hand.",
+			spec:    *manual,
+			status:  status(oamv1alpha2.RolloutPhasePaused, 1, &started, fmt.Sprintf(msgFmtAwaitPromotion, 1)),
+			healthy: true,
+			want: want{
+				status: status(oamv1alpha2.RolloutPhaseProgressing, 2, &nowTime, ""),
+				wait:   progressWait,
+			},
+		},
+		"Pause": {
+			reason:  "A healthy step should not be promoted before it paused for its pause seconds.",
+			spec:    spec,
+			status:  status(oamv1alpha2.RolloutPhaseProgressing, 2, &started, ""),
+			healthy: true,
+			want: want{
+				status: status(oamv1alpha2.RolloutPhaseProgressing, 2, &started, ""),
+				wait:   time.Minute,
+			},
+		},
+		"Succeeded": {
+			reason:  "A rollout whose last step is promoted should succeed.",
+			spec:    oamv1alpha2.RolloutTraitSpec{Replicas: 4},
+			status:  status(oamv1alpha2.RolloutPhaseProgressing, 0, &started, ""),
+			healthy: true,
+			want: want{
+				status: status(oamv1alpha2.RolloutPhaseSucceeded, 0, &started, ""),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, wait := advance(tc.spec, tc.status, tc.healthy, tc.diagnosis, now)
+			if diff := cmp.Diff(tc.want.status, got); diff != "" {
+				t.Errorf("\n%s\nadvance(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.wait, wait); diff != "" {
+				t.Errorf("\n%s\nadvance(...): -want wait, +got wait:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReady(t *testing.T) {
+	deploy := func(generation, observed, readyReplicas int64) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "generation": generation},
+			"spec":       map[string]interface{}{"replicas": int64(3)},
+			"status":     map[string]interface{}{"observedGeneration": observed},
+		}}
+		if readyReplicas > 0 {
+			_ = unstructured.SetNestedField(u.Object, readyReplicas, "status", "readyReplicas")
+		}
+		return u
+	}
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "web"},
+	}}
+
+	type want struct {
+		ready     bool
+		diagnosis string
+	}
+	cases := map[string]struct {
+		reason   string
+		res      *unstructured.Unstructured
+		replicas int32
+		want     want
+	}{
+		"NotObserved": {
+			reason:   "A resource whose controller has not observed its latest generation should not be ready.",
+			res:      deploy(2, 1, 3),
+			replicas: 3,
+			want:     want{diagnosis: fmt.Sprintf(reasonFmtNotObserved, "Deployment", "web")},
+		},
+		"NoReadyReplicas": {
+			reason:   "A resource that reports no ready replicas should not be ready.",
+			res:      deploy(1, 1, 0),
+			replicas: 3,
+			want:     want{diagnosis: fmt.Sprintf(reasonFmtNotReady, "Deployment", "web", 0, 3)},
+		},
+		"Ready": {
+			reason:   "A resource that runs the supplied number of ready replicas should be ready.",
+			res:      deploy(1, 1, 3),
+			replicas: 3,
+			want:     want{ready: true},
+		},
+		"Unknown": {
+			reason: "A resource whose health can't be told should be ready.",
+			res:    service,
+			want:   want{ready: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ok, diagnosis := ready(tc.res, tc.replicas)
+			if diff := cmp.Diff(tc.want, want{ready: ok, diagnosis: diagnosis}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nready(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollouttrait
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/manualscalertrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/health"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errFmtGetSourceWorkload = "cannot get the workload %q of the source revision"
+	errFmtNotScalable       = "%s %q has no resource that can be scaled"
+	errScaleWorkloads       = "cannot scale the workloads of the rollout"
+	errUpdateStatus         = "cannot update the status of the rollout"
+)
+
+// Setup adds a controller that reconciles RolloutTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	scaler, err := manualscalertrait.NewSubresourceScaler(mgr.GetConfig(), dm)
+	if err != nil {
+		return err
+	}
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		dm:     dm,
+		scaler: scaler,
+		log:    ctrl.Log.WithName("RolloutTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.RolloutTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("RolloutTrait"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a RolloutTrait object
+type Reconciler struct {
+	client.Client
+	dm     discoverymapper.DiscoveryMapper
+	scaler manualscalertrait.Scaler
+	log    logr.Logger
+	record event.Recorder
+	Scheme *runtime.Scheme
+}
+
+// Reconcile a RolloutTrait by shifting the replicas of its component from
+// the workload of the previous revision to the workload of the revision it
+// applies to, step by step, as long as the latter is healthy. A RolloutTrait
+// of a revision that was superseded by a newer one leaves the workloads to
+// the RolloutTrait of the newer revision.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=rollouttraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=rollouttraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments/scale;statefulsets/scale,verbs=get;update
+// nolint:gocyclo
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("rollout trait", req.NamespacedName)
+
+	mLog.Info("Reconcile rollout trait")
+
+	var trait oamv1alpha2.RolloutTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "rolloutTrait", trait.Name)
+		eventObj = &trait
+	}
+	if ac, ok := eventObj.(*oamv1alpha2.ApplicationConfiguration); ok && superseded(ac, &trait) {
+		mLog.Info("Skip the rollout of a superseded revision")
+		return ctrl.Result{}, nil
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	target := &revisionWorkload{workload: workload, wd: wd}
+	var source *revisionWorkload
+	targetRevision, sourceRef := revisions(&trait)
+	if sourceRef != nil {
+		if source, err = r.fetchSource(ctx, mLog, trait.GetNamespace(), sourceRef); err != nil {
+			r.record.Event(eventObj, event.Warning(errScaleWorkloads, err))
+			return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+				cpv1alpha1.ReconcileError(errors.Wrap(err, errScaleWorkloads)))
+		}
+	}
+
+	status := trait.Status
+	if status.TargetRevision != targetRevision {
+		// a new revision is rolled out from the first step
+		now := metav1.Now()
+		status = oamv1alpha2.RolloutTraitStatus{
+			ConditionedStatus: trait.Status.ConditionedStatus,
+			Phase:             oamv1alpha2.RolloutPhaseProgressing,
+			TargetRevision:    targetRevision,
+			StepStartTime:     &now,
+		}
+		if sourceRef != nil {
+			status.SourceRevision = sourceRef.RevisionName
+		}
+	}
+	if source == nil {
+		// there is nothing to roll out from
+		status.Phase = oamv1alpha2.RolloutPhaseSucceeded
+	}
+
+	resources, err := r.scale(ctx, trait.Spec, &status, target, source)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(errScaleWorkloads, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+			cpv1alpha1.ReconcileError(errors.Wrap(err, errScaleWorkloads)))
+	}
+
+	var wait time.Duration
+	if phase := status.Phase; phase != oamv1alpha2.RolloutPhaseSucceeded && phase != oamv1alpha2.RolloutPhaseRolledBack {
+		healthy, diagnosis := evaluate(target, resources, status.TargetReplicas)
+		step := status.CurrentStep
+		status, wait = advance(trait.Spec, status, healthy, diagnosis, time.Now())
+		if status.CurrentStep != step || status.Phase != phase {
+			// apply the replicas of the next step, or the rollback, right away
+			if _, err := r.scale(ctx, trait.Spec, &status, target, source); err != nil {
+				r.record.Event(eventObj, event.Warning(errScaleWorkloads, err))
+				return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+					cpv1alpha1.ReconcileError(errors.Wrap(err, errScaleWorkloads)))
+			}
+		}
+		switch status.Phase {
+		case oamv1alpha2.RolloutPhaseRolledBack:
+			r.record.Event(eventObj, event.Warning("Rollout rolled back", errors.New(status.Message)))
+		case oamv1alpha2.RolloutPhaseSucceeded:
+			r.record.Event(eventObj, event.Normal("Rollout succeeded",
+				fmt.Sprintf("Trait `%s` successfully rolled out revision `%s`", trait.Name, status.TargetRevision)))
+		}
+	}
+
+	trait.Status = status
+	if err := r.Status().Update(ctx, &trait); err != nil {
+		return util.ReconcileWaitResult, errors.Wrap(err, errUpdateStatus)
+	}
+	return ctrl.Result{RequeueAfter: wait}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// revisions returns the name of the revision the supplied trait rolls out,
+// and the workload of the revision it rolls out from, if any.
+func revisions(trait *oamv1alpha2.RolloutTrait) (string, *oamv1alpha2.WorkloadRevision) {
+	revs := trait.Spec.Revisions
+	if len(revs) == 0 {
+		return trait.Spec.WorkloadReference.Name, nil
+	}
+	if len(revs) == 1 {
+		return revs[0].RevisionName, nil
+	}
+	return revs[0].RevisionName, &revs[1]
+}
+
+// superseded returns true if the supplied ApplicationConfiguration runs a
+// newer revision of the workload of the supplied trait.
+func superseded(ac *oamv1alpha2.ApplicationConfiguration, trait *oamv1alpha2.RolloutTrait) bool {
+	ref := trait.GetWorkloadReference()
+	for _, w := range ac.Status.Workloads {
+		for i, rev := range w.Revisions {
+			if rev.Reference.Name == ref.Name && rev.Reference.Kind == ref.Kind {
+				return i > 0
+			}
+		}
+	}
+	return false
+}
+
+// A revisionWorkload is the workload of a revision of a rollout, and its
+// WorkloadDefinition.
+type revisionWorkload struct {
+	workload *unstructured.Unstructured
+	wd       *oamv1alpha2.WorkloadDefinition
+}
+
+// fetchSource returns the workload of the supplied source revision, or nil if
+// it no longer exists.
+func (r *Reconciler) fetchSource(ctx context.Context, mLog logr.Logger, namespace string,
+	rev *oamv1alpha2.WorkloadRevision) (*revisionWorkload, error) {
+	source := &unstructured.Unstructured{}
+	source.SetAPIVersion(rev.Reference.APIVersion)
+	source.SetKind(rev.Reference.Kind)
+	nn := types.NamespacedName{Namespace: namespace, Name: rev.Reference.Name}
+	if err := r.Get(ctx, nn, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, errFmtGetSourceWorkload, rev.Reference.Name)
+	}
+	wd, err := util.FetchWorkloadDefinitionOrDummy(ctx, r, r.dm, mLog, source)
+	if err != nil {
+		return nil, err
+	}
+	return &revisionWorkload{workload: source, wd: wd}, nil
+}
+
+// scale the supplied target and source workloads to the replicas of the
+// supplied state of a rollout, and record them in it. It returns the scaled
+// resources of the target workload.
+func (r *Reconciler) scale(ctx context.Context, spec oamv1alpha2.RolloutTraitSpec,
+	status *oamv1alpha2.RolloutTraitStatus, target, source *revisionWorkload) ([]*unstructured.Unstructured, error) {
+	targetReplicas, sourceReplicas := replicasOf(spec, *status)
+	resources, err := r.scaleWorkload(ctx, target, targetReplicas)
+	if err != nil {
+		return nil, err
+	}
+	if source != nil {
+		if _, err := r.scaleWorkload(ctx, source, sourceReplicas); err != nil {
+			return nil, err
+		}
+	}
+	status.TargetReplicas, status.SourceReplicas = targetReplicas, sourceReplicas
+	return resources, nil
+}
+
+// scaleWorkload scales the child resources of the supplied workload, or the
+// workload itself if it has none, that can be scaled to the supplied number
+// of replicas. Workloads without a WorkloadDefinition have a dummy one that
+// lists no child resource kinds, so that they are scaled themselves. It
+// returns the scaled resources.
+func (r *Reconciler) scaleWorkload(ctx context.Context, rw *revisionWorkload,
+	replicas int32) ([]*unstructured.Unstructured, error) {
+	workload := rw.workload
+	resources, err := util.FetchWorkloadChildResources(ctx, r, workload, rw.wd)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) == 0 {
+		resources = append(resources, workload)
+	}
+	scaled := make([]*unstructured.Unstructured, 0, len(resources))
+	for _, res := range resources {
+		ok, err := r.scaler.Scale(ctx, res, replicas)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			scaled = append(scaled, res)
+		}
+	}
+	if len(scaled) == 0 {
+		return nil, errors.Errorf(errFmtNotScalable, workload.GetKind(), workload.GetName())
+	}
+	return scaled, nil
+}
+
+// evaluate the health of the supplied workload by the health policy of its
+// WorkloadDefinition, if it has one, and the readiness of its supplied scaled
+// resources.
+func evaluate(rw *revisionWorkload, resources []*unstructured.Unstructured, replicas int32) (bool, string) {
+	if policy := rw.wd.Spec.HealthPolicy; policy != nil {
+		if status, diagnosis := health.Evaluate(policy, rw.workload); status != oamv1alpha2.StatusHealthy {
+			return false, diagnosis
+		}
+	}
+	for _, res := range resources {
+		if ok, diagnosis := ready(res, replicas); !ok {
+			return false, diagnosis
+		}
+	}
+	return true, ""
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.RolloutTraitKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.RolloutTrait{}).
+		Complete(r)
+}
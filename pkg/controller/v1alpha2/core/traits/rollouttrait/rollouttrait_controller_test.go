@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollouttrait
+
+import (
+	"context"
+	"testing"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/manualscalertrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestSuperseded(t *testing.T) {
+	ref := func(name string) cpv1alpha1.TypedReference {
+		return cpv1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: name}
+	}
+	trait := func(workload string) *oamv1alpha2.RolloutTrait {
+		return &oamv1alpha2.RolloutTrait{Spec: oamv1alpha2.RolloutTraitSpec{WorkloadReference: ref(workload)}}
+	}
+	ac := &oamv1alpha2.ApplicationConfiguration{Status: oamv1alpha2.ApplicationConfigurationStatus{
+		Workloads: []oamv1alpha2.WorkloadStatus{{
+			ComponentName: "web",
+			Revisions: []oamv1alpha2.WorkloadRevision{
+				{RevisionName: "web-v3", Reference: ref("web-v3")},
+				{RevisionName: "web-v2", Reference: ref("web-v2")},
+			},
+		}},
+	}}
+
+	cases := map[string]struct {
+		reason string
+		trait  *oamv1alpha2.RolloutTrait
+		want   bool
+	}{
+		"Current": {
+			reason: "The trait of the newest revision should not be superseded.",
+			trait:  trait("web-v3"),
+			want:   false,
+		},
+		"Superseded": {
+			reason: "The trait of an older revision should be superseded.",
+			trait:  trait("web-v2"),
+			want:   true,
+		},
+		"NotRecorded": {
+			reason: "The trait of a revision that is not recorded yet should not be superseded.",
+			trait:  trait("web-v4"),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := superseded(ac, tc.trait)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nsuperseded(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestScaleWorkload(t *testing.T) {
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion("core.oam.dev/v1alpha2")
+	workload.SetKind("ContainerizedWorkload")
+	workload.SetNamespace("ns")
+	workload.SetName("web-v2")
+	workload.SetUID("web-v2-uid")
+
+	child := unstructured.Unstructured{}
+	child.SetAPIVersion("apps/v1")
+	child.SetKind("Deployment")
+	child.SetNamespace("ns")
+	child.SetName("web-v2")
+	child.SetOwnerReferences([]metav1.OwnerReference{{UID: workload.GetUID()}})
+
+	wd := &oamv1alpha2.WorkloadDefinition{Spec: oamv1alpha2.WorkloadDefinitionSpec{
+		ChildResourceKinds: []oamv1alpha2.ChildResourceKind{{APIVersion: "apps/v1", Kind: "Deployment"}},
+	}}
+	listChild := func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+		list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{*child.DeepCopy()}
+		return nil
+	}
+	scalable := manualscalertrait.ScaleFn(func(_ context.Context, _ *unstructured.Unstructured, _ int32) (bool, error) {
+		return true, nil
+	})
+
+	type want struct {
+		scaled []*unstructured.Unstructured
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		list   test.MockListFn
+		scaler manualscalertrait.Scaler
+		wd     *oamv1alpha2.WorkloadDefinition
+		want   want
+	}{
+		"ChildResources": {
+			reason: "The child resources of a workload should be scaled instead of the workload.",
+			list:   listChild,
+			scaler: scalable,
+			wd:     wd,
+			want:   want{scaled: []*unstructured.Unstructured{child.DeepCopy()}},
+		},
+		"NoWorkloadDefinition": {
+			reason: "A workload with the dummy WorkloadDefinition of workloads without one should be scaled itself.",
+			list:   test.NewMockListFn(errors.New("no child resources are expected")),
+			scaler: scalable,
+			wd:     util.GetDummyWorkloadDefinition(workload),
+			want:   want{scaled: []*unstructured.Unstructured{workload}},
+		},
+		"NotScalable": {
+			reason: "A workload without a resource that can be scaled should be reported.",
+			list:   listChild,
+			scaler: manualscalertrait.ScaleFn(func(_ context.Context, _ *unstructured.Unstructured, _ int32) (bool, error) {
+				return false, nil
+			}),
+			wd:   wd,
+			want: want{err: errors.Errorf(errFmtNotScalable, workload.GetKind(), workload.GetName())},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{Client: &test.MockClient{MockList: tc.list}, scaler: tc.scaler}
+			got, err := r.scaleWorkload(context.Background(), &revisionWorkload{workload: workload, wd: tc.wd}, 3)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.scaleWorkload(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.scaled, got); diff != "" {
+				t.Errorf("\n%s\nr.scaleWorkload(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
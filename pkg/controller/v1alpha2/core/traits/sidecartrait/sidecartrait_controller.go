@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecartrait
+
+import (
+	"context"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errFmtNoPodSpecPath = "the WorkloadDefinition of %s has no podSpecPath to inject the sidecar at"
+	errInjectSidecar    = "cannot inject the sidecar"
+)
+
+// Setup adds a controller that reconciles SidecarTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		dm:     dm,
+		log:    ctrl.Log.WithName("SidecarTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.SidecarTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("SidecarTrait"))),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a SidecarTrait object
+type Reconciler struct {
+	client.Client
+	dm     discoverymapper.DiscoveryMapper
+	log    logr.Logger
+	record event.Recorder
+}
+
+// Reconcile a SidecarTrait by checking that its sidecar can be injected, i.e.
+// that the podSpecPath of the WorkloadDefinition of the workload leads to a
+// pod spec. The sidecar is injected into the pod spec of the workload by the
+// ApplicationConfiguration controller, at that path.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=sidecartraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=sidecartraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=workloaddefinitions,verbs=get;list;watch
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("sidecar trait", req.NamespacedName)
+
+	mLog.Info("Reconcile sidecar trait")
+
+	var trait oamv1alpha2.SidecarTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "sidecarTrait", trait.Name)
+		eventObj = &trait
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	if wd.Spec.PodSpecPath == "" {
+		err := errors.Errorf(errFmtNoPodSpecPath, workload.GetKind())
+		r.record.Event(eventObj, event.Warning(errInjectSidecar, err))
+		return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	// the workload is not updated, the pod spec is only checked
+	if _, err := util.PavePodSpec(workload, wd.Spec.PodSpecPath); err != nil {
+		r.record.Event(eventObj, event.Warning(errInjectSidecar, err))
+		return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.SidecarTraitKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.SidecarTrait{}).
+		Complete(r)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecartrait
+
+import (
+	"context"
+	"testing"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestReconcile(t *testing.T) {
+	trait := oamv1alpha2.SidecarTrait{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "proxy"},
+		Spec: oamv1alpha2.SidecarTraitSpec{
+			WorkloadReference: cpv1alpha1.TypedReference{APIVersion: "batch/v1beta1", Kind: "CronJob", Name: "report"},
+			Image:             "envoyproxy/envoy:v1.15.0",
+		},
+	}
+	podSpecPath := "spec.jobTemplate.spec.template.spec"
+	workload := func(containers ...interface{}) unstructured.Unstructured {
+		w := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"schedule": "0 2 * * *",
+				"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{
+					"spec": map[string]interface{}{"containers": containers},
+				}}},
+			},
+		}}
+		w.SetAPIVersion("batch/v1beta1")
+		w.SetKind("CronJob")
+		w.SetNamespace("ns")
+		w.SetName("report")
+		return w
+	}
+	report := map[string]interface{}{"name": "report", "image": "report:v1"}
+
+	cases := map[string]struct {
+		reason   string
+		workload unstructured.Unstructured
+		wd       *oamv1alpha2.WorkloadDefinition
+		want     cpv1alpha1.Condition
+	}{
+		"CustomPodSpecPath": {
+			reason:   "A sidecar should be injectable at the podSpecPath of the WorkloadDefinition of the workload.",
+			workload: workload(report),
+			wd:       &oamv1alpha2.WorkloadDefinition{Spec: oamv1alpha2.WorkloadDefinitionSpec{PodSpecPath: podSpecPath}},
+			want:     cpv1alpha1.ReconcileSuccess(),
+		},
+		"ExistingContainer": {
+			reason:   "A workload the sidecar was injected into already should be reconciled as is.",
+			workload: workload(report, map[string]interface{}{"name": "proxy", "image": "envoyproxy/envoy:v1.15.0"}),
+			wd:       &oamv1alpha2.WorkloadDefinition{Spec: oamv1alpha2.WorkloadDefinitionSpec{PodSpecPath: podSpecPath}},
+			want:     cpv1alpha1.ReconcileSuccess(),
+		},
+		"InvalidPodSpecPath": {
+			reason:   "A podSpecPath that does not lead to a pod spec should be reported.",
+			workload: workload(report),
+			wd:       &oamv1alpha2.WorkloadDefinition{Spec: oamv1alpha2.WorkloadDefinitionSpec{PodSpecPath: "spec.schedule"}},
+			want:     cpv1alpha1.ReconcileError(errors.New(`the pod spec at "spec.schedule" is not an object`)),
+		},
+		"NoPodSpecPath": {
+			reason:   "A workload without a WorkloadDefinition has no pod spec to inject the sidecar into.",
+			workload: workload(report),
+			want:     cpv1alpha1.ReconcileError(errors.Errorf(errFmtNoPodSpecPath, "CronJob")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got cpv1alpha1.Condition
+			c := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					switch o := obj.(type) {
+					case *oamv1alpha2.SidecarTrait:
+						*o = *trait.DeepCopy()
+					case *unstructured.Unstructured:
+						*o = *tc.workload.DeepCopy()
+					case *oamv1alpha2.WorkloadDefinition:
+						if tc.wd == nil {
+							return apierrors.NewNotFound(schema.GroupResource{Resource: "workloaddefinitions"}, "cronjobs.batch")
+						}
+						*o = *tc.wd.DeepCopy()
+					}
+					return nil
+				},
+				MockUpdate: test.NewMockUpdateFn(errors.New("the workload should not be updated")),
+				MockPatch:  test.NewMockPatchFn(errors.New("the workload should not be patched")),
+				MockStatusPatch: func(_ context.Context, obj runtime.Object, _ client.Patch, _ ...client.PatchOption) error {
+					got = obj.(*oamv1alpha2.SidecarTrait).GetCondition(cpv1alpha1.TypeSynced)
+					return nil
+				},
+			}
+			dm := mock.NewMockDiscoveryMapper()
+			dm.MockRESTMapping = mock.NewMockRESTMapping("cronjobs")
+			r := &Reconciler{Client: c, dm: dm, log: ctrl.Log.WithName("TestReconcile"), record: event.NewNopRecorder()}
+
+			result, err := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "proxy"}})
+			if err != nil {
+				t.Errorf("\n%s\nr.Reconcile(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(ctrl.Result{}, result); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaimtrait
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errFmtNoPodSpecPath  = "the WorkloadDefinition of %s has no podSpecPath to mount the volume claims at"
+	errRenderClaim       = "cannot render the persistent volume claim"
+	errMountClaims       = "cannot mount the persistent volume claims"
+	errApplyClaim        = "cannot apply the persistent volume claim"
+	errUpdateTraitStatus = "cannot update the status of the volume claim trait"
+)
+
+var (
+	claimKind       = reflect.TypeOf(corev1.PersistentVolumeClaim{}).Name()
+	claimAPIVersion = corev1.SchemeGroupVersion.String()
+)
+
+// Setup adds a controller that reconciles VolumeClaimTraits.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		dm:     dm,
+		log:    ctrl.Log.WithName("VolumeClaimTrait"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(oamv1alpha2.VolumeClaimTraitKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("VolumeClaimTrait"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a VolumeClaimTrait object
+type Reconciler struct {
+	client.Client
+	dm     discoverymapper.DiscoveryMapper
+	log    logr.Logger
+	record event.Recorder
+	Scheme *runtime.Scheme
+}
+
+// Reconcile a VolumeClaimTrait by applying a PersistentVolumeClaim for each of
+// its claims. The claims are mounted into the pod spec of the workload by the
+// ApplicationConfiguration controller, at the podSpecPath of the
+// WorkloadDefinition of the workload.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=volumeclaimtraits,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=volumeclaimtraits/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=workloaddefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	mLog := r.log.WithValues("volume claim trait", req.NamespacedName)
+
+	mLog.Info("Reconcile volume claim trait")
+
+	var trait oamv1alpha2.VolumeClaimTrait
+	if err := r.Get(ctx, req.NamespacedName, &trait); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &trait)
+	if eventObj == nil {
+		// fallback to the trait itself
+		mLog.Error(err, "Failed to find the parent resource", "volumeClaimTrait", trait.Name)
+		eventObj = &trait
+	}
+
+	workload, wd, err := util.FetchTraitWorkload(ctx, r, r.dm, mLog, &trait)
+	if err != nil {
+		r.record.Event(eventObj, event.Warning(util.ErrLocateWorkload, err))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+	// the claims could be applied, but they would never be mounted
+	if wd.Spec.PodSpecPath == "" {
+		err := errors.Errorf(errFmtNoPodSpecPath, workload.GetKind())
+		r.record.Event(eventObj, event.Warning(errMountClaims, err))
+		return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileError(err))
+	}
+
+	resources := make([]cpv1alpha1.TypedReference, 0, len(trait.Spec.Claims))
+	for _, claim := range trait.Spec.Claims {
+		pvc := renderClaim(&trait, claim)
+		// the claims are deleted with the trait
+		if err := ctrl.SetControllerReference(&trait, pvc, r.Scheme); err != nil {
+			r.record.Event(eventObj, event.Warning(errRenderClaim, err))
+			return ctrl.Result{}, util.PatchCondition(ctx, r, &trait,
+				cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderClaim)))
+		}
+		// server side apply, only the fields we set are touched
+		if err := r.Patch(ctx, pvc, client.Apply, client.ForceOwnership, client.FieldOwner(trait.GetUID())); err != nil {
+			mLog.Error(err, "Failed to apply the persistent volume claim", "name", pvc.GetName())
+			r.record.Event(eventObj, event.Warning(errApplyClaim, err))
+			return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &trait,
+				cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyClaim)))
+		}
+		resources = append(resources, cpv1alpha1.TypedReference{
+			APIVersion: claimAPIVersion,
+			Kind:       claimKind,
+			Name:       pvc.GetName(),
+			UID:        pvc.GetUID(),
+		})
+	}
+	r.record.Event(eventObj, event.Normal("Persistent volume claims applied",
+		fmt.Sprintf("Trait `%s` successfully server side patched %d persistent volume claims", trait.Name, len(resources))))
+
+	trait.Status.Resources = resources
+	if err := r.Status().Update(ctx, &trait); err != nil {
+		return util.ReconcileWaitResult, errors.Wrap(err, errUpdateTraitStatus)
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &trait, cpv1alpha1.ReconcileSuccess())
+}
+
+// renderClaim returns the PersistentVolumeClaim of the supplied claim of the
+// supplied trait. Shared claims may be mounted by the pods of several nodes,
+// exclusive ones only by those of a single node.
+func renderClaim(trait *oamv1alpha2.VolumeClaimTrait, claim oamv1alpha2.VolumeClaim) *corev1.PersistentVolumeClaim {
+	accessMode := corev1.ReadWriteOnce
+	if claim.SharingPolicy != nil && *claim.SharingPolicy == oamv1alpha2.VolumeSharingPolicyShared {
+		accessMode = corev1.ReadWriteMany
+		if claim.AccessMode != nil && *claim.AccessMode == oamv1alpha2.VolumeAccessModeRO {
+			accessMode = corev1.ReadOnlyMany
+		}
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       claimKind,
+			APIVersion: claimAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      util.VolumeClaimName(trait.GetName(), claim.Name),
+			Namespace: trait.GetNamespace(),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{accessMode},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: claim.Required},
+			},
+			StorageClassName: claim.StorageClass,
+		},
+	}
+	util.PassLabelAndAnnotation(trait, pvc)
+	return pvc
+}
+
+// SetupWithManager to setup k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(oamv1alpha2.VolumeClaimTraitKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&oamv1alpha2.VolumeClaimTrait{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Complete(r)
+}
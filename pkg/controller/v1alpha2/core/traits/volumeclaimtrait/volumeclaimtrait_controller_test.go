@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaimtrait
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	oamv1alpha2 "github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRenderClaim(t *testing.T) {
+	ro := oamv1alpha2.VolumeAccessModeRO
+	shared := oamv1alpha2.VolumeSharingPolicyShared
+	class := "ssd"
+
+	trait := &oamv1alpha2.VolumeClaimTrait{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "storage", Labels: map[string]string{"app": "web"}},
+	}
+	claim := func(mode corev1.PersistentVolumeAccessMode, class *string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			TypeMeta:   metav1.TypeMeta{Kind: claimKind, APIVersion: claimAPIVersion},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "storage-data", Labels: map[string]string{"app": "web"}},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{mode},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+				StorageClassName: class,
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		claim  oamv1alpha2.VolumeClaim
+		want   *corev1.PersistentVolumeClaim
+	}{
+		"Exclusive": {
+			reason: "Claims should be mounted by the pods of a single node by default",
+			claim:  oamv1alpha2.VolumeClaim{Name: "data", AccessMode: &ro, Required: resource.MustParse("1Gi")},
+			want:   claim(corev1.ReadWriteOnce, nil),
+		},
+		"Shared": {
+			reason: "Shared claims should be mounted by the pods of several nodes",
+			claim:  oamv1alpha2.VolumeClaim{Name: "data", SharingPolicy: &shared, StorageClass: &class, Required: resource.MustParse("1Gi")},
+			want:   claim(corev1.ReadWriteMany, &class),
+		},
+		"SharedReadOnly": {
+			reason: "Shared read only claims should be mounted read only by the pods of several nodes",
+			claim:  oamv1alpha2.VolumeClaim{Name: "data", AccessMode: &ro, SharingPolicy: &shared, Required: resource.MustParse("1Gi")},
+			want:   claim(corev1.ReadOnlyMany, nil),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := renderClaim(trait, tc.claim)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrenderClaim(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,6 +37,7 @@ import (
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
@@ -43,8 +45,18 @@ import (
 const (
 	errRenderWorkload  = "cannot render workload"
 	errRenderService   = "cannot render service"
+	errRenderClaims    = "cannot render persistent volume claims"
 	errApplyDeployment = "cannot apply the deployment"
 	errApplyService    = "cannot apply the service"
+	errApplyClaim      = "cannot apply a persistent volume claim"
+
+	errCleanupResources  = "cannot clean up resources"
+	errObserveContainers = "cannot observe the containers of the deployment"
+
+	msgFmtWaiting            = "waiting: %s"
+	msgFmtTerminated         = "last terminated with exit code %d: %s"
+	msgDeploymentNotObserved = "the deployment has not observed its latest spec yet"
+	msgFmtReplicasNotReady   = "%d/%d replicas ready"
 )
 
 // Setup adds a controller that reconciles ContainerizedWorkload.
@@ -52,7 +64,8 @@ func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
 	reconciler := Reconciler{
 		Client: mgr.GetClient(),
 		log:    ctrl.Log.WithName("ContainerizedWorkload"),
-		record: event.NewAPIRecorder(mgr.GetEventRecorderFor("ContainerizedWorkload")),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(v1alpha2.ContainerizedWorkloadKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("ContainerizedWorkload"))),
 		Scheme: mgr.GetScheme(),
 	}
 	return reconciler.SetupWithManager(mgr)
@@ -71,6 +84,8 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=core.oam.dev,resources=containerizedworkloads/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 	log := r.log.WithValues("containerizedworkload", req.NamespacedName)
@@ -91,6 +106,13 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		log.Error(err, "workload", "name", workload.Name)
 		eventObj = &workload
 	}
+	claims, err := r.renderClaims(ctx, &workload)
+	if err != nil {
+		log.Error(err, "Failed to render persistent volume claims")
+		r.record.Event(eventObj, event.Warning(errRenderClaims, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderClaims)))
+	}
 	deploy, err := r.renderDeployment(ctx, &workload)
 	if err != nil {
 		log.Error(err, "Failed to render a deployment")
@@ -100,6 +122,14 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	}
 	// server side apply, only the fields we set are touched
 	applyOpts := []client.PatchOption{client.ForceOwnership, client.FieldOwner(workload.GetUID())}
+	for _, claim := range claims {
+		if err := r.Patch(ctx, claim, client.Apply, applyOpts...); err != nil {
+			log.Error(err, "Failed to apply a persistent volume claim")
+			r.record.Event(eventObj, event.Warning(errApplyClaim, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyClaim)))
+		}
+	}
 	if err := r.Patch(ctx, deploy, client.Apply, applyOpts...); err != nil {
 		log.Error(err, "Failed to apply to a deployment")
 		r.record.Event(eventObj, event.Warning(errApplyDeployment, err))
@@ -110,7 +140,7 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		fmt.Sprintf("Workload `%s` successfully server side patched a deployment `%s`",
 			workload.Name, deploy.Name)))
 
-	// create a service for the workload
+	// create a service for the ports the workload declares, if any
 	// TODO(rz): remove this after we have service trait
 	service, err := r.renderService(ctx, &workload, deploy)
 	if err != nil {
@@ -119,21 +149,33 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return util.ReconcileWaitResult,
 			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderService)))
 	}
-	// server side apply the service
-	if err := r.Patch(ctx, service, client.Apply, applyOpts...); err != nil {
-		log.Error(err, "Failed to apply a service")
-		r.record.Event(eventObj, event.Warning(errApplyDeployment, err))
-		return util.ReconcileWaitResult,
-			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyService)))
+	var serviceUID *types.UID
+	if service != nil {
+		// server side apply the service
+		if err := r.Patch(ctx, service, client.Apply, applyOpts...); err != nil {
+			log.Error(err, "Failed to apply a service")
+			r.record.Event(eventObj, event.Warning(errApplyService, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyService)))
+		}
+		r.record.Event(eventObj, event.Normal("Service created",
+			fmt.Sprintf("Workload `%s` successfully server side patched a service `%s`",
+				workload.Name, service.Name)))
+		serviceUID = &service.UID
 	}
-	r.record.Event(eventObj, event.Normal("Service created",
-		fmt.Sprintf("Workload `%s` successfully server side patched a service `%s`",
-			workload.Name, service.Name)))
 	// garbage collect the service/deployments that we created but not needed
-	if err := r.cleanupResources(ctx, &workload, &deploy.UID, &service.UID); err != nil {
+	if err := r.cleanupResources(ctx, &workload, &deploy.UID, serviceUID); err != nil {
 		log.Error(err, "Failed to clean up resources")
-		r.record.Event(eventObj, event.Warning(errApplyDeployment, err))
+		r.record.Event(eventObj, event.Warning(errCleanupResources, err))
+	}
+	containers, err := r.observeContainers(ctx, &workload, deploy)
+	if err != nil {
+		log.Error(err, "Failed to observe the containers of the deployment")
+		r.record.Event(eventObj, event.Warning(errObserveContainers, err))
 	}
+	workload.Status.Containers = containers
+	available := rollUpStatus(&workload, deploy, service)
+	workload.SetConditions(available)
 	workload.Status.Resources = nil
 	// record the new deployment, new service
 	workload.Status.Resources = append(workload.Status.Resources,
@@ -142,18 +184,34 @@ func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			Kind:       deploy.GetObjectKind().GroupVersionKind().Kind,
 			Name:       deploy.GetName(),
 			UID:        deploy.UID,
-		},
-		cpv1alpha1.TypedReference{
-			APIVersion: service.GetObjectKind().GroupVersionKind().GroupVersion().String(),
-			Kind:       service.GetObjectKind().GroupVersionKind().Kind,
-			Name:       service.GetName(),
-			UID:        service.UID,
-		},
-	)
+		})
+	for _, claim := range claims {
+		workload.Status.Resources = append(workload.Status.Resources,
+			cpv1alpha1.TypedReference{
+				APIVersion: claim.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+				Kind:       claim.GetObjectKind().GroupVersionKind().Kind,
+				Name:       claim.GetName(),
+				UID:        claim.UID,
+			})
+	}
+	if service != nil {
+		workload.Status.Resources = append(workload.Status.Resources,
+			cpv1alpha1.TypedReference{
+				APIVersion: service.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+				Kind:       service.GetObjectKind().GroupVersionKind().Kind,
+				Name:       service.GetName(),
+				UID:        service.UID,
+			})
+	}
 
 	if err := r.Status().Update(ctx, &workload); err != nil {
 		return util.ReconcileWaitResult, err
 	}
+	if available.Reason != cpv1alpha1.ReasonAvailable || !containersReady(containers) {
+		// neither pods nor the status of the deployment are watched, so that
+		// they are polled until the workload is available
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileSuccess())
+	}
 	return ctrl.Result{}, util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileSuccess())
 }
 
@@ -165,6 +223,9 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Named(name).
 		For(src).
 		Owns(&appsv1.Deployment{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Owns(&corev1.Service{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// services have no generation, so that their drift is only noticed
+		// if all of their updates are reconciled
+		Owns(&corev1.Service{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
 		Complete(r)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -49,7 +50,23 @@ func (r *Reconciler) renderDeployment(ctx context.Context,
 	return deploy, nil
 }
 
-// create a service for the deployment
+// create the persistent volume claims of the volumes that require an external disk
+func (r *Reconciler) renderClaims(ctx context.Context,
+	workload *v1alpha2.ContainerizedWorkload) ([]*corev1.PersistentVolumeClaim, error) {
+	claims, err := PersistentVolumeClaims(ctx, workload)
+	if err != nil {
+		return nil, err
+	}
+	for _, claim := range claims {
+		// the claims are deleted with the workload
+		if err := ctrl.SetControllerReference(workload, claim, r.Scheme); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// create a service for the deployment, or nil if it declares no ports
 func (r *Reconciler) renderService(ctx context.Context,
 	workload *v1alpha2.ContainerizedWorkload, deploy *appsv1.Deployment) (*corev1.Service, error) {
 	// create a service for the workload
@@ -57,6 +74,9 @@ func (r *Reconciler) renderService(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if len(resources) < 2 {
+		return nil, nil
+	}
 	service, ok := resources[1].(*corev1.Service)
 	if !ok {
 		return nil, fmt.Errorf("internal error, service is not rendered correctly")
@@ -66,7 +86,9 @@ func (r *Reconciler) renderService(ctx context.Context,
 	service.Spec.Type = corev1.ServiceTypeNodePort
 	// k8s server-side patch complains if the protocol is not set
 	for i := 0; i < len(service.Spec.Ports); i++ {
-		service.Spec.Ports[i].Protocol = corev1.ProtocolTCP
+		if len(service.Spec.Ports[i].Protocol) == 0 {
+			service.Spec.Ports[i].Protocol = corev1.ProtocolTCP
+		}
 	}
 	// always set the controller reference so that we can watch this service and
 	if err := ctrl.SetControllerReference(workload, service, r.Scheme); err != nil {
@@ -75,7 +97,109 @@ func (r *Reconciler) renderService(ctx context.Context,
 	return service, nil
 }
 
-// delete deployments/services that are not the same as the existing
+// observe the probe results of the containers in the pods of the deployment
+func (r *Reconciler) observeContainers(ctx context.Context,
+	workload *v1alpha2.ContainerizedWorkload, deploy *appsv1.Deployment) ([]v1alpha2.ContainerProbeStatus, error) {
+	if deploy.Spec.Selector == nil {
+		return nil, nil
+	}
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(workload.Namespace),
+		client.MatchingLabels(deploy.Spec.Selector.MatchLabels)); err != nil {
+		return nil, err
+	}
+	return probeStatuses(workload, pods.Items), nil
+}
+
+// probeStatuses summarises, for each container of the workload that has a
+// liveness or readiness probe, in how many of the supplied pods it is ready
+// and how often it was restarted.
+func probeStatuses(workload *v1alpha2.ContainerizedWorkload, pods []corev1.Pod) []v1alpha2.ContainerProbeStatus {
+	var statuses []v1alpha2.ContainerProbeStatus
+	for _, c := range workload.Spec.Containers {
+		if c.LivenessProbe == nil && c.ReadinessProbe == nil {
+			continue
+		}
+		s := v1alpha2.ContainerProbeStatus{Name: c.Name}
+		for _, pod := range pods {
+			// terminating pods are about to be replaced
+			if pod.GetDeletionTimestamp() != nil {
+				continue
+			}
+			s.Pods++
+			for _, cs := range pod.Status.ContainerStatuses {
+				if cs.Name != c.Name {
+					continue
+				}
+				if cs.Ready {
+					s.ReadyPods++
+				}
+				s.Restarts += cs.RestartCount
+				if m := containerMessage(cs); m != "" {
+					s.Message = m
+				}
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
+
+// containerMessage explains why the container is not ready or was restarted,
+// or returns an empty string if it is ready and never failed.
+func containerMessage(cs corev1.ContainerStatus) string {
+	if w := cs.State.Waiting; w != nil && !cs.Ready {
+		return fmt.Sprintf(msgFmtWaiting, w.Reason)
+	}
+	if t := cs.LastTerminationState.Terminated; t != nil && cs.RestartCount > 0 {
+		return fmt.Sprintf(msgFmtTerminated, t.ExitCode, t.Reason)
+	}
+	return ""
+}
+
+// containersReady returns true if the supplied containers are ready in all
+// of their pods.
+func containersReady(statuses []v1alpha2.ContainerProbeStatus) bool {
+	for _, s := range statuses {
+		if s.ReadyPods < s.Pods {
+			return false
+		}
+	}
+	return true
+}
+
+// rollUpStatus records the replicas of the deployment and the cluster IP of
+// the service, if any, in the status of the workload, and returns whether the
+// workload is available.
+func rollUpStatus(workload *v1alpha2.ContainerizedWorkload, deploy *appsv1.Deployment,
+	service *corev1.Service) cpv1alpha1.Condition {
+	workload.Status.Replicas = deploy.Status.Replicas
+	if deploy.Spec.Replicas != nil {
+		workload.Status.Replicas = *deploy.Spec.Replicas
+	}
+	workload.Status.ReadyReplicas = deploy.Status.ReadyReplicas
+	workload.Status.ServiceIP = ""
+	if service != nil {
+		workload.Status.ServiceIP = service.Spec.ClusterIP
+	}
+
+	if deploy.Status.ObservedGeneration < deploy.GetGeneration() {
+		return cpv1alpha1.Unavailable().WithMessage(msgDeploymentNotObserved)
+	}
+	for _, c := range deploy.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable && c.Status != corev1.ConditionTrue {
+			return cpv1alpha1.Unavailable().WithMessage(c.Message)
+		}
+	}
+	if workload.Status.ReadyReplicas < workload.Status.Replicas {
+		return cpv1alpha1.Unavailable().WithMessage(
+			fmt.Sprintf(msgFmtReplicasNotReady, workload.Status.ReadyReplicas, workload.Status.Replicas))
+	}
+	return cpv1alpha1.Available()
+}
+
+// delete deployments/services that are not the same as the existing. All
+// services are deleted if serviceUID is nil.
 // nolint:gocyclo
 func (r *Reconciler) cleanupResources(ctx context.Context,
 	workload *v1alpha2.ContainerizedWorkload, deployUID, serviceUID *types.UID) error {
@@ -100,7 +224,7 @@ func (r *Reconciler) cleanupResources(ctx context.Context,
 				log.Info("Removed an orphaned deployment", "deployment UID", *deployUID, "orphaned UID", uid)
 			}
 		} else if res.Kind == util.KindService && res.APIVersion == corev1.SchemeGroupVersion.String() {
-			if uid != *serviceUID {
+			if serviceUID == nil || uid != *serviceUID {
 				log.Info("Found an orphaned service", "orphaned  UID", uid)
 				sn := client.ObjectKey{Name: res.Name, Namespace: workload.Namespace}
 				if err := r.Get(ctx, sn, &service); err != nil {
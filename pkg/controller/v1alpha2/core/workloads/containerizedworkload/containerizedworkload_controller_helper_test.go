@@ -2,11 +2,16 @@ package containerizedworkload
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -16,7 +21,19 @@ import (
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 )
 
+func workloadWithResources(deploymentUID, serviceUID types.UID) *v1alpha2.ContainerizedWorkload {
+	w := containerizedWorkload()
+	w.Status.Resources = []cpv1alpha1.TypedReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: workloadName, UID: deploymentUID},
+		{APIVersion: "v1", Kind: "Service", Name: workloadName, UID: serviceUID},
+	}
+	return w
+}
+
 func TestContainerizedWorkloadReconciler_cleanupResources(t *testing.T) {
+	deploymentUID := types.UID("deployment-uid")
+	serviceUID := types.UID("service-uid")
+
 	type args struct {
 		ctx        context.Context
 		workload   *v1alpha2.ContainerizedWorkload
@@ -28,7 +45,44 @@ func TestContainerizedWorkloadReconciler_cleanupResources(t *testing.T) {
 		args       args
 		wantErr    bool
 	}{
-		// TODO: Add test cases.
+		"KeepCurrentResources": {
+			reconciler: Reconciler{Client: &test.MockClient{}, log: ctrl.Log.WithName("ContainerizedWorkload")},
+			args: args{
+				ctx:        context.Background(),
+				workload:   workloadWithResources(deploymentUID, serviceUID),
+				deployUID:  &deploymentUID,
+				serviceUID: &serviceUID,
+			},
+		},
+		"DeleteServiceWithoutPorts": {
+			reconciler: Reconciler{
+				Client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(nil),
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+				log: ctrl.Log.WithName("ContainerizedWorkload"),
+			},
+			args: args{
+				ctx:       context.Background(),
+				workload:  workloadWithResources(deploymentUID, serviceUID),
+				deployUID: &deploymentUID,
+			},
+		},
+		"DeleteServiceError": {
+			reconciler: Reconciler{
+				Client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(nil),
+					MockDelete: test.NewMockDeleteFn(errors.New("boom")),
+				},
+				log: ctrl.Log.WithName("ContainerizedWorkload"),
+			},
+			args: args{
+				ctx:       context.Background(),
+				workload:  workloadWithResources(deploymentUID, serviceUID),
+				deployUID: &deploymentUID,
+			},
+			wantErr: true,
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -96,3 +150,179 @@ func TestRenderDeployment(t *testing.T) {
 	}
 
 }
+
+func TestProbeStatuses(t *testing.T) {
+	probe := &v1alpha2.ContainerHealthProbe{TCPSocket: &v1alpha2.TCPSocketProbe{Port: 8080}}
+	now := metav1.Now()
+
+	type args struct {
+		w    *v1alpha2.ContainerizedWorkload
+		pods []corev1.Pod
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []v1alpha2.ContainerProbeStatus
+	}{
+		"NoProbes": {
+			reason: "Containers without probes should not be reported",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(v1alpha2.Container{Name: "app"})),
+				pods: []corev1.Pod{{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "app", Ready: true},
+				}}}},
+			},
+		},
+		"Ready": {
+			reason: "Containers that are ready in all pods should be reported as such",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(v1alpha2.Container{Name: "app", ReadinessProbe: probe})),
+				pods: []corev1.Pod{
+					{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}}}},
+					{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}}}},
+				},
+			},
+			want: []v1alpha2.ContainerProbeStatus{{Name: "app", Pods: 2, ReadyPods: 2}},
+		},
+		"LivenessFailed": {
+			reason: "Restarts of containers and why they were restarted should be reported",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(v1alpha2.Container{Name: "app", LivenessProbe: probe})),
+				pods: []corev1.Pod{
+					{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "app",
+						RestartCount: 3,
+						State: corev1.ContainerState{
+							Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+						},
+					}}}},
+					{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+						Name:         "app",
+						Ready:        true,
+						RestartCount: 1,
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "Error"},
+						},
+					}}}},
+				},
+			},
+			want: []v1alpha2.ContainerProbeStatus{{
+				Name:      "app",
+				Pods:      2,
+				ReadyPods: 1,
+				Restarts:  4,
+				Message:   "last terminated with exit code 137: Error",
+			}},
+		},
+		"PendingAndTerminatingPods": {
+			reason: "Pending pods should count as not ready, and terminating pods should be ignored",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(v1alpha2.Container{Name: "app", ReadinessProbe: probe})),
+				pods: []corev1.Pod{
+					{},
+					{
+						ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+						Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}}},
+					},
+				},
+			},
+			want: []v1alpha2.ContainerProbeStatus{{Name: "app", Pods: 1}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := probeStatuses(tc.args.w, tc.args.pods)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nprobeStatuses(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRollUpStatus(t *testing.T) {
+	two := int32(2)
+
+	type args struct {
+		deploy  *appsv1.Deployment
+		service *corev1.Service
+	}
+	type want struct {
+		status v1alpha2.ContainerizedWorkloadStatus
+		cond   cpv1alpha1.Condition
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Available": {
+			reason: "The replicas and service IP should be recorded, and the workload available if all replicas are ready",
+			args: args{
+				deploy: &appsv1.Deployment{
+					Spec:   appsv1.DeploymentSpec{Replicas: &two},
+					Status: appsv1.DeploymentStatus{Replicas: 2, ReadyReplicas: 2},
+				},
+				service: &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "10.0.0.1"}},
+			},
+			want: want{
+				status: v1alpha2.ContainerizedWorkloadStatus{Replicas: 2, ReadyReplicas: 2, ServiceIP: "10.0.0.1"},
+				cond:   cpv1alpha1.Available(),
+			},
+		},
+		"NotObserved": {
+			reason: "The workload should be unavailable while the deployment has not observed its latest generation",
+			args: args{
+				deploy: &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Generation: 2},
+					Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, ReadyReplicas: 1},
+				},
+			},
+			want: want{
+				status: v1alpha2.ContainerizedWorkloadStatus{Replicas: 1, ReadyReplicas: 1},
+				cond:   cpv1alpha1.Unavailable().WithMessage(msgDeploymentNotObserved),
+			},
+		},
+		"DeploymentUnavailable": {
+			reason: "The workload should be unavailable with the message of the deployment if it is unavailable",
+			args: args{
+				deploy: &appsv1.Deployment{
+					Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{{
+						Type:    appsv1.DeploymentAvailable,
+						Status:  corev1.ConditionFalse,
+						Message: "Deployment does not have minimum availability.",
+					}}},
+				},
+			},
+			want: want{
+				cond: cpv1alpha1.Unavailable().WithMessage("Deployment does not have minimum availability."),
+			},
+		},
+		"ReplicasNotReady": {
+			reason: "The workload should be unavailable while some replicas are not ready",
+			args: args{
+				deploy: &appsv1.Deployment{
+					Spec:   appsv1.DeploymentSpec{Replicas: &two},
+					Status: appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: 1},
+				},
+			},
+			want: want{
+				status: v1alpha2.ContainerizedWorkloadStatus{Replicas: 2, ReadyReplicas: 1},
+				cond:   cpv1alpha1.Unavailable().WithMessage("1/2 replicas ready"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := containerizedWorkload()
+			cond := rollUpStatus(w, tc.args.deploy, tc.args.service)
+			if diff := cmp.Diff(tc.want.cond, cond); diff != "" {
+				t.Errorf("\nReason: %s\nrollUpStatus(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, w.Status); diff != "" {
+				t.Errorf("\nReason: %s\nrollUpStatus(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
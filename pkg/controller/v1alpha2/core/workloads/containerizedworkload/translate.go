@@ -19,7 +19,9 @@ package containerizedworkload
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +38,8 @@ var (
 	deploymentAPIVersion = appsv1.SchemeGroupVersion.String()
 	serviceKind          = reflect.TypeOf(corev1.Service{}).Name()
 	serviceAPIVersion    = corev1.SchemeGroupVersion.String()
+	claimKind            = reflect.TypeOf(corev1.PersistentVolumeClaim{}).Name()
+	claimAPIVersion      = corev1.SchemeGroupVersion.String()
 )
 
 // Reconcile error strings.
@@ -46,7 +50,6 @@ const (
 )
 
 // TranslateContainerWorkload translates a ContainerizedWorkload into a Deployment.
-// nolint:gocyclo
 func TranslateContainerWorkload(ctx context.Context, w oam.Workload) ([]oam.Object, error) {
 	cw, ok := w.(*v1alpha2.ContainerizedWorkload)
 	if !ok {
@@ -78,25 +81,35 @@ func TranslateContainerWorkload(ctx context.Context, w oam.Workload) ([]oam.Obje
 		},
 	}
 
-	if cw.Spec.OperatingSystem != nil {
-		if d.Spec.Template.Spec.NodeSelector == nil {
-			d.Spec.Template.Spec.NodeSelector = map[string]string{}
-		}
-		d.Spec.Template.Spec.NodeSelector["beta.kubernetes.io/os"] = string(*cw.Spec.OperatingSystem)
-	}
+	d.Spec.Template.Spec = PodSpec(cw.GetName(), cw.Spec)
 
-	if cw.Spec.CPUArchitecture != nil {
-		if d.Spec.Template.Spec.NodeSelector == nil {
-			d.Spec.Template.Spec.NodeSelector = map[string]string{}
+	// pass through label and annotation from the workload to the deployment
+	util.PassLabelAndAnnotation(w, d)
+	// pass through label and annotation from the workload to the pod template too
+	util.PassLabelAndAnnotation(w, &d.Spec.Template)
+
+	return []oam.Object{d}, nil
+}
+
+// PodSpec translates the containers of the named workload into a pod spec.
+// nolint:gocyclo
+func PodSpec(workloadName string, spec v1alpha2.ContainerizedWorkloadSpec) corev1.PodSpec {
+	ps := corev1.PodSpec{NodeSelector: nodeSelector(spec)}
+
+	volumes := map[string]bool{}
+	pullSecrets := map[string]bool{}
+	pullSecret := func(name string) {
+		if !pullSecrets[name] {
+			pullSecrets[name] = true
+			ps.ImagePullSecrets = append(ps.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
 		}
-		d.Spec.Template.Spec.NodeSelector["kubernetes.io/arch"] = string(*cw.Spec.CPUArchitecture)
 	}
-
-	for _, container := range cw.Spec.Containers {
+	for _, name := range spec.ImagePullSecrets {
+		pullSecret(name)
+	}
+	translate := func(container v1alpha2.Container) corev1.Container {
 		if container.ImagePullSecret != nil {
-			d.Spec.Template.Spec.ImagePullSecrets = append(d.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{
-				Name: *container.ImagePullSecret,
-			})
+			pullSecret(*container.ImagePullSecret)
 		}
 		kubernetesContainer := corev1.Container{
 			Name:    container.Name,
@@ -104,6 +117,9 @@ func TranslateContainerWorkload(ctx context.Context, w oam.Workload) ([]oam.Obje
 			Command: container.Command,
 			Args:    container.Arguments,
 		}
+		if container.ImagePullPolicy != nil {
+			kubernetesContainer.ImagePullPolicy = corev1.PullPolicy(*container.ImagePullPolicy)
+		}
 
 		if container.Resources != nil {
 			kubernetesContainer.Resources = corev1.ResourceRequirements{
@@ -121,7 +137,10 @@ func TranslateContainerWorkload(ctx context.Context, w oam.Workload) ([]oam.Obje
 					mount.ReadOnly = true
 				}
 				kubernetesContainer.VolumeMounts = append(kubernetesContainer.VolumeMounts, mount)
-
+				if !volumes[v.Name] {
+					volumes[v.Name] = true
+					ps.Volumes = append(ps.Volumes, podVolume(workloadName, v))
+				}
 			}
 		}
 
@@ -136,28 +155,8 @@ func TranslateContainerWorkload(ctx context.Context, w oam.Workload) ([]oam.Obje
 			kubernetesContainer.Ports = append(kubernetesContainer.Ports, port)
 		}
 
-		for _, e := range container.Environment {
-			if e.Value != nil {
-				kubernetesContainer.Env = append(kubernetesContainer.Env, corev1.EnvVar{
-					Name:  e.Name,
-					Value: *e.Value,
-				})
-				continue
-			}
-			if e.FromSecret != nil {
-				kubernetesContainer.Env = append(kubernetesContainer.Env, corev1.EnvVar{
-					Name: e.Name,
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							Key: e.FromSecret.Key,
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: e.FromSecret.Name,
-							},
-						},
-					},
-				})
-			}
-		}
+		kubernetesContainer.Env = EnvVars(container.Environment)
+		kubernetesContainer.EnvFrom = EnvFromSources(container.EnvFrom)
 
 		if container.LivenessProbe != nil {
 			kubernetesContainer.LivenessProbe = &corev1.Probe{}
@@ -253,19 +252,249 @@ func TranslateContainerWorkload(ctx context.Context, w oam.Workload) ([]oam.Obje
 			}
 		}
 
-		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, kubernetesContainer)
+		return kubernetesContainer
 	}
 
-	// pass through label and annotation from the workload to the deployment
-	util.PassLabelAndAnnotation(w, d)
-	// pass through label and annotation from the workload to the pod template too
-	util.PassLabelAndAnnotation(w, &d.Spec.Template)
+	// init containers run in the order they are declared, and must neither
+	// be probed nor expose ports
+	for _, container := range spec.InitContainers {
+		kubernetesContainer := translate(container)
+		kubernetesContainer.LivenessProbe, kubernetesContainer.ReadinessProbe = nil, nil
+		kubernetesContainer.Ports = nil
+		ps.InitContainers = append(ps.InitContainers, kubernetesContainer)
+	}
+	for _, container := range spec.Containers {
+		ps.Containers = append(ps.Containers, translate(container))
+	}
 
-	return []oam.Object{d}, nil
+	return ps
+}
+
+// EnvVars returns the environment variables of a Kubernetes container that
+// set the supplied OAM container environment variables.
+func EnvVars(vars []v1alpha2.ContainerEnvVar) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, e := range vars {
+		if e.Value != nil {
+			env = append(env, corev1.EnvVar{
+				Name:  e.Name,
+				Value: *e.Value,
+			})
+			continue
+		}
+		if e.FromSecret != nil {
+			env = append(env, corev1.EnvVar{
+				Name: e.Name,
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						Key: e.FromSecret.Key,
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: e.FromSecret.Name,
+						},
+					},
+				},
+			})
+			continue
+		}
+		if e.FromConfigMap != nil {
+			env = append(env, corev1.EnvVar{
+				Name: e.Name,
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						Key: e.FromConfigMap.Key,
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: e.FromConfigMap.Name,
+						},
+					},
+				},
+			})
+		}
+	}
+	return env
+}
+
+// EnvFromSources returns the sources of the environment variables of a
+// Kubernetes container that set the keys of the Secrets or ConfigMaps of the
+// supplied OAM sources.
+func EnvFromSources(sources []v1alpha2.ContainerEnvFromSource) []corev1.EnvFromSource {
+	var envFrom []corev1.EnvFromSource
+	for _, e := range sources {
+		if e.FromSecret != nil {
+			envFrom = append(envFrom, corev1.EnvFromSource{
+				Prefix: e.Prefix,
+				SecretRef: &corev1.SecretEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: *e.FromSecret},
+				},
+			})
+		}
+		if e.FromConfigMap != nil {
+			envFrom = append(envFrom, corev1.EnvFromSource{
+				Prefix: e.Prefix,
+				ConfigMapRef: &corev1.ConfigMapEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: *e.FromConfigMap},
+				},
+			})
+		}
+	}
+	return envFrom
+}
+
+// nodeSelector selects the nodes of the operating system and CPU architecture
+// the workload requires, or returns nil if it requires neither.
+func nodeSelector(spec v1alpha2.ContainerizedWorkloadSpec) map[string]string {
+	var selector map[string]string
+	if spec.OperatingSystem != nil {
+		selector = map[string]string{corev1.LabelOSStable: string(*spec.OperatingSystem)}
+	}
+	if spec.CPUArchitecture != nil {
+		if selector == nil {
+			selector = map[string]string{}
+		}
+		selector[corev1.LabelArchStable] = nodeArchitecture(*spec.CPUArchitecture)
+	}
+	return selector
+}
+
+// nodeArchitecture returns the architecture label of nodes of the supplied CPU
+// architecture. Nodes are labelled with the GOARCH of the kubelet, which
+// names i386 386.
+func nodeArchitecture(a v1alpha2.CPUArchitecture) string {
+	if a == v1alpha2.CPUArchitectureI386 {
+		return "386"
+	}
+	return string(a)
 }
 
-// ServiceInjector adds a Service object for the first Port on the first
-// Container for the first Deployment observed in a workload translation.
+// podVolume returns the pod volume backing the supplied volume of the named
+// workload.
+func podVolume(workloadName string, v v1alpha2.VolumeResource) corev1.Volume {
+	vol := corev1.Volume{Name: v.Name}
+	switch {
+	case v.FromConfigMap != nil:
+		vol.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: v.FromConfigMap.Name},
+			Items:                keysToPaths(v.FromConfigMap.Keys),
+		}
+	case v.FromSecret != nil:
+		vol.Secret = &corev1.SecretVolumeSource{
+			SecretName: v.FromSecret.Name,
+			Items:      keysToPaths(v.FromSecret.Keys),
+		}
+	case v.FromPersistentVolumeClaim != nil:
+		vol.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: *v.FromPersistentVolumeClaim}
+	case persistent(v):
+		vol.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: ClaimName(workloadName, v.Name)}
+	default:
+		vol.EmptyDir = &corev1.EmptyDirVolumeSource{}
+		if v.Disk != nil && !v.Disk.Required.IsZero() {
+			limit := v.Disk.Required.DeepCopy()
+			vol.EmptyDir.SizeLimit = &limit
+		}
+	}
+	return vol
+}
+
+// persistent returns true if a PersistentVolumeClaim must be generated for
+// the supplied volume, i.e. if it requires an external disk and is not read
+// from elsewhere.
+func persistent(v v1alpha2.VolumeResource) bool {
+	if v.FromConfigMap != nil || v.FromSecret != nil || v.FromPersistentVolumeClaim != nil {
+		return false
+	}
+	return v.Disk != nil && (v.Disk.Ephemeral == nil || !*v.Disk.Ephemeral)
+}
+
+func keysToPaths(keys []string) []corev1.KeyToPath {
+	if len(keys) == 0 {
+		return nil
+	}
+	paths := make([]corev1.KeyToPath, len(keys))
+	for i, k := range keys {
+		paths[i] = corev1.KeyToPath{Key: k, Path: k}
+	}
+	return paths
+}
+
+// ClaimName returns the name of the PersistentVolumeClaim generated for the
+// named volume of the named workload.
+func ClaimName(workloadName, volumeName string) string {
+	return workloadName + "-" + volumeName
+}
+
+// PersistentVolumeClaims returns the PersistentVolumeClaims generated for the
+// volumes of a ContainerizedWorkload that require an external disk.
+func PersistentVolumeClaims(ctx context.Context, w oam.Workload) ([]*corev1.PersistentVolumeClaim, error) {
+	cw, ok := w.(*v1alpha2.ContainerizedWorkload)
+	if !ok {
+		return nil, errors.New(errNotContainerizedWorkload)
+	}
+	return VolumeClaims(cw, cw.Spec), nil
+}
+
+// VolumeClaims returns the PersistentVolumeClaims generated for the volumes of
+// the containers of the supplied workload that require an external disk.
+func VolumeClaims(w metav1.Object, spec v1alpha2.ContainerizedWorkloadSpec) []*corev1.PersistentVolumeClaim {
+	var claims []*corev1.PersistentVolumeClaim
+	for _, v := range PersistentVolumes(spec) {
+		claims = append(claims, &corev1.PersistentVolumeClaim{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       claimKind,
+				APIVersion: claimAPIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ClaimName(w.GetName(), v.Name),
+				Namespace: w.GetNamespace(),
+				Labels: map[string]string{
+					labelKey: string(w.GetUID()),
+				},
+			},
+			Spec: ClaimSpec(v),
+		})
+	}
+	return claims
+}
+
+// PersistentVolumes returns the distinct volumes of the containers of the
+// supplied spec that require an external disk.
+func PersistentVolumes(spec v1alpha2.ContainerizedWorkloadSpec) []v1alpha2.VolumeResource {
+	var volumes []v1alpha2.VolumeResource
+	seen := map[string]bool{}
+	containers := make([]v1alpha2.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(append(containers, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		if container.Resources == nil {
+			continue
+		}
+		for _, v := range container.Resources.Volumes {
+			if seen[v.Name] || !persistent(v) {
+				continue
+			}
+			seen[v.Name] = true
+			volumes = append(volumes, v)
+		}
+	}
+	return volumes
+}
+
+// ClaimSpec returns the spec of the PersistentVolumeClaim of the supplied
+// volume that requires an external disk.
+func ClaimSpec(v v1alpha2.VolumeResource) corev1.PersistentVolumeClaimSpec {
+	accessMode := corev1.ReadWriteOnce
+	if v.SharingPolicy != nil && *v.SharingPolicy == v1alpha2.VolumeSharingPolicyShared {
+		accessMode = corev1.ReadWriteMany
+	}
+	return corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{accessMode},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceStorage: v.Disk.Required},
+		},
+		StorageClassName: v.Disk.StorageClass,
+	}
+}
+
+// ServiceInjector adds a Service object exposing the ports declared by the
+// containers of the first Deployment observed in a workload translation. No
+// Service is added if the Deployment declares no ports.
 func ServiceInjector(ctx context.Context, w oam.Workload, objs []oam.Object) ([]oam.Object, error) {
 	if objs == nil {
 		return nil, nil
@@ -277,12 +506,10 @@ func ServiceInjector(ctx context.Context, w oam.Workload, objs []oam.Object) ([]
 			continue
 		}
 
-		// We don't add a Service if there are no containers for the Deployment.
-		// This should never happen in practice.
-		if len(d.Spec.Template.Spec.Containers) < 1 {
-			continue
+		ports := servicePorts(d)
+		if len(ports) == 0 {
+			break
 		}
-
 		s := &corev1.Service{
 			TypeMeta: metav1.TypeMeta{
 				Kind:       serviceKind,
@@ -297,26 +524,66 @@ func ServiceInjector(ctx context.Context, w oam.Workload, objs []oam.Object) ([]
 			},
 			Spec: corev1.ServiceSpec{
 				Selector: d.Spec.Selector.MatchLabels,
-				Ports:    []corev1.ServicePort{},
+				Ports:    ports,
 				Type:     corev1.ServiceTypeLoadBalancer,
 			},
 		}
-
-		// We only add a single Service for the Deployment, even if multiple
-		// ports or no ports are defined on the first container. This is to
-		// exclude the need for implementing garbage collection in the
-		// short-term in the case that ports are modified after creation.
-		if len(d.Spec.Template.Spec.Containers[0].Ports) > 0 {
-			s.Spec.Ports = []corev1.ServicePort{
-				{
-					Name:       d.GetName(),
-					Port:       d.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort,
-					TargetPort: intstr.FromInt(int(d.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort)),
-				},
-			}
-		}
 		objs = append(objs, s)
 		break
 	}
 	return objs, nil
 }
+
+// servicePorts returns a port of a Service for each distinct port declared
+// by the containers of the supplied Deployment.
+func servicePorts(d *appsv1.Deployment) []corev1.ServicePort {
+	return ServicePorts(d.GetName(), d.Spec.Template.Spec.Containers)
+}
+
+// ServicePorts returns a port of a Service for each distinct port declared by
+// the supplied containers. A single port is named after the Service. Several
+// ports are named after their container ports, or after their protocol and
+// number if those are unnamed or their names clash.
+func ServicePorts(serviceName string, containers []corev1.Container) []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	var keys []string
+	declared := map[string]bool{}
+	for _, c := range containers {
+		for _, cp := range c.Ports {
+			key := portKey(cp)
+			if declared[key] {
+				continue
+			}
+			declared[key] = true
+			keys = append(keys, key)
+			ports = append(ports, corev1.ServicePort{
+				Name:       cp.Name,
+				Protocol:   cp.Protocol,
+				Port:       cp.ContainerPort,
+				TargetPort: intstr.FromInt(int(cp.ContainerPort)),
+			})
+		}
+	}
+	if len(ports) == 1 {
+		ports[0].Name = serviceName
+		return ports
+	}
+	names := map[string]bool{}
+	for i := range ports {
+		if ports[i].Name == "" || names[ports[i].Name] {
+			ports[i].Name = keys[i]
+		}
+		names[ports[i].Name] = true
+	}
+	return ports
+}
+
+// portKey identifies the supplied container port by its protocol and number,
+// e.g. tcp-8080.
+func portKey(cp corev1.ContainerPort) string {
+	protocol := cp.Protocol
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	return fmt.Sprintf("%s-%d", strings.ToLower(string(protocol)), cp.ContainerPort)
+}
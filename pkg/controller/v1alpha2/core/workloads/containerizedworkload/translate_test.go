@@ -25,6 +25,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -64,7 +65,16 @@ func dmWithOS(os string) deploymentModifier {
 		if d.Spec.Template.Spec.NodeSelector == nil {
 			d.Spec.Template.Spec.NodeSelector = map[string]string{}
 		}
-		d.Spec.Template.Spec.NodeSelector["beta.kubernetes.io/os"] = os
+		d.Spec.Template.Spec.NodeSelector[corev1.LabelOSStable] = os
+	}
+}
+
+func dmWithArch(arch string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		if d.Spec.Template.Spec.NodeSelector == nil {
+			d.Spec.Template.Spec.NodeSelector = map[string]string{}
+		}
+		d.Spec.Template.Spec.NodeSelector[corev1.LabelArchStable] = arch
 	}
 }
 
@@ -138,6 +148,18 @@ func cwWithOS(os string) cwModifier {
 	}
 }
 
+func cwWithArch(arch v1alpha2.CPUArchitecture) cwModifier {
+	return func(cw *v1alpha2.ContainerizedWorkload) {
+		cw.Spec.CPUArchitecture = &arch
+	}
+}
+
+func cwWithInitContainer(c v1alpha2.Container) cwModifier {
+	return func(cw *v1alpha2.ContainerizedWorkload) {
+		cw.Spec.InitContainers = append(cw.Spec.InitContainers, c)
+	}
+}
+
 func cwWithContainer(c v1alpha2.Container) cwModifier {
 	return func(cw *v1alpha2.ContainerizedWorkload) {
 		cw.Spec.Containers = append(cw.Spec.Containers, c)
@@ -150,6 +172,18 @@ func dmWithContainer(c corev1.Container) deploymentModifier {
 	}
 }
 
+func dmWithInitContainer(c corev1.Container) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.InitContainers = append(d.Spec.Template.Spec.InitContainers, c)
+	}
+}
+
+func dmWithVolumes(v ...corev1.Volume) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, v...)
+	}
+}
+
 func containerizedWorkload(mod ...cwModifier) *v1alpha2.ContainerizedWorkload {
 	cw := &v1alpha2.ContainerizedWorkload{
 		ObjectMeta: metav1.ObjectMeta{
@@ -169,6 +203,13 @@ func containerizedWorkload(mod ...cwModifier) *v1alpha2.ContainerizedWorkload {
 func TestContainerizedWorkloadTranslator(t *testing.T) {
 
 	envVarSecretVal := "nicesecretvalue"
+	claimName := "cool-claim"
+	diskSize := resource.MustParse("1Gi")
+	ephemeral := true
+	pullSecret := "cool-registry"
+	pullPolicy := v1alpha2.ImagePullPolicyAlways
+	credentials := "cool-credentials"
+	settings := "cool-settings"
 	cwLabel := map[string]string{
 		"oam.dev/enabled": "true",
 	}
@@ -220,6 +261,20 @@ func TestContainerizedWorkloadTranslator(t *testing.T) {
 			},
 			want: want{result: []oam.Object{deployment(dmWithOS("test"))}},
 		},
+		"SuccessfulOSAndArch": {
+			reason: "A ContainerizedWorkload should be scheduled onto nodes of its operating system and architecture.",
+			args: args{
+				w: containerizedWorkload(cwWithOS("windows"), cwWithArch(v1alpha2.CPUArchitectureARM64)),
+			},
+			want: want{result: []oam.Object{deployment(dmWithOS("windows"), dmWithArch("arm64"))}},
+		},
+		"SuccessfulI386": {
+			reason: "A ContainerizedWorkload requiring i386 should be scheduled onto nodes labelled with the GOARCH 386.",
+			args: args{
+				w: containerizedWorkload(cwWithArch(v1alpha2.CPUArchitectureI386)),
+			},
+			want: want{result: []oam.Object{deployment(dmWithArch("386"))}},
+		},
 		"SuccessfulContainers": {
 			reason: "A ContainerizedWorkload should be successfully translated into a deployment.",
 			args: args{
@@ -318,6 +373,181 @@ func TestContainerizedWorkloadTranslator(t *testing.T) {
 						Value: envVarSecretVal,
 					},
 				},
+			}), dmWithVolumes(corev1.Volume{
+				Name:         "cool-volume",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}))}},
+		},
+		"SuccessfulVolumeSources": {
+			reason: "Volumes should be backed by their ConfigMap, Secret, claim or a generated claim, and added to the pod once.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithContainer(v1alpha2.Container{
+						Name: "cool-container",
+						Resources: &v1alpha2.ContainerResources{
+							Volumes: []v1alpha2.VolumeResource{
+								{
+									Name:          "config",
+									MountPath:     "/etc/config",
+									FromConfigMap: &v1alpha2.VolumeObjectSource{Name: "cool-config", Keys: []string{"app.yaml"}},
+								},
+								{
+									Name:       "creds",
+									MountPath:  "/etc/creds",
+									FromSecret: &v1alpha2.VolumeObjectSource{Name: "cool-secret"},
+								},
+								{
+									Name:                      "claimed",
+									MountPath:                 "/var/claimed",
+									FromPersistentVolumeClaim: &claimName,
+								},
+								{
+									Name:      "data",
+									MountPath: "/var/data",
+									Disk:      &v1alpha2.DiskResource{Required: diskSize},
+								},
+								{
+									Name:      "scratch",
+									MountPath: "/tmp",
+									Disk:      &v1alpha2.DiskResource{Required: diskSize, Ephemeral: &ephemeral},
+								},
+							},
+						},
+					}),
+					cwWithContainer(v1alpha2.Container{
+						Name: "sidecar",
+						Resources: &v1alpha2.ContainerResources{
+							Volumes: []v1alpha2.VolumeResource{
+								{
+									Name:      "data",
+									MountPath: "/data",
+									Disk:      &v1alpha2.DiskResource{Required: diskSize},
+								},
+							},
+						},
+					}),
+				),
+			},
+			want: want{result: []oam.Object{deployment(
+				dmWithContainer(corev1.Container{
+					Name: "cool-container",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							"cpu":    {},
+							"memory": {},
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "config", MountPath: "/etc/config"},
+						{Name: "creds", MountPath: "/etc/creds"},
+						{Name: "claimed", MountPath: "/var/claimed"},
+						{Name: "data", MountPath: "/var/data"},
+						{Name: "scratch", MountPath: "/tmp"},
+					},
+				}),
+				dmWithContainer(corev1.Container{
+					Name: "sidecar",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							"cpu":    {},
+							"memory": {},
+						},
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/data"},
+					},
+				}),
+				dmWithVolumes(
+					corev1.Volume{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "cool-config"},
+						Items:                []corev1.KeyToPath{{Key: "app.yaml", Path: "app.yaml"}},
+					}}},
+					corev1.Volume{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "cool-secret"}}},
+					corev1.Volume{Name: "claimed", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName}}},
+					corev1.Volume{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: workloadName + "-data"}}},
+					corev1.Volume{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &diskSize}}},
+				),
+			)}},
+		},
+		"SuccessfulInitContainers": {
+			reason: "Init containers should be translated in order without probes and ports, and share image pull secrets with containers.",
+			args: args{
+				w: containerizedWorkload(
+					cwWithInitContainer(v1alpha2.Container{
+						Name:            "migrate",
+						Image:           "cool/migrate:latest",
+						ImagePullSecret: &pullSecret,
+						Ports:           []v1alpha2.ContainerPort{{Name: "http", Port: 8080}},
+						ReadinessProbe:  &v1alpha2.ContainerHealthProbe{TCPSocket: &v1alpha2.TCPSocketProbe{Port: 8080}},
+					}),
+					cwWithInitContainer(v1alpha2.Container{Name: "seed", Image: "cool/seed:latest"}),
+					cwWithContainer(v1alpha2.Container{Name: "app", Image: "cool/app:latest", ImagePullSecret: &pullSecret}),
+				),
+			},
+			want: want{result: []oam.Object{deployment(
+				dmWithInitContainer(corev1.Container{Name: "migrate", Image: "cool/migrate:latest"}),
+				dmWithInitContainer(corev1.Container{Name: "seed", Image: "cool/seed:latest"}),
+				dmWithContainer(corev1.Container{Name: "app", Image: "cool/app:latest"}),
+				func(d *appsv1.Deployment) {
+					d.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: pullSecret}}
+				},
+			)}},
+		},
+		"SuccessfulImagePullSecrets": {
+			reason: "The image pull secrets of the workload should precede those of its containers, without duplicates, and pull policies should be passed through.",
+			args: args{
+				w: containerizedWorkload(
+					func(cw *v1alpha2.ContainerizedWorkload) {
+						cw.Spec.ImagePullSecrets = []string{"registry", pullSecret}
+					},
+					cwWithContainer(v1alpha2.Container{
+						Name:            "app",
+						Image:           "cool/app:latest",
+						ImagePullSecret: &pullSecret,
+						ImagePullPolicy: &pullPolicy,
+					}),
+				),
+			},
+			want: want{result: []oam.Object{deployment(
+				dmWithContainer(corev1.Container{Name: "app", Image: "cool/app:latest", ImagePullPolicy: corev1.PullAlways}),
+				func(d *appsv1.Deployment) {
+					d.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}, {Name: pullSecret}}
+				},
+			)}},
+		},
+		"SuccessfulEnvFromReferences": {
+			reason: "Environment variables referencing Secret or ConfigMap keys, and whole Secrets or ConfigMaps, should be translated.",
+			args: args{
+				w: containerizedWorkload(cwWithContainer(v1alpha2.Container{
+					Name:  "app",
+					Image: "cool/app:latest",
+					Environment: []v1alpha2.ContainerEnvVar{
+						{Name: "PASSWORD", FromSecret: &v1alpha2.SecretKeySelector{Name: credentials, Key: "password"}},
+						{Name: "LEVEL", FromConfigMap: &v1alpha2.ConfigMapKeySelector{Name: settings, Key: "level"}},
+					},
+					EnvFrom: []v1alpha2.ContainerEnvFromSource{
+						{Prefix: "DB_", FromSecret: &credentials},
+						{FromConfigMap: &settings},
+					},
+				})),
+			},
+			want: want{result: []oam.Object{deployment(dmWithContainer(corev1.Container{
+				Name:  "app",
+				Image: "cool/app:latest",
+				Env: []corev1.EnvVar{
+					{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: credentials},
+						Key:                  "password",
+					}}},
+					{Name: "LEVEL", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: settings},
+						Key:                  "level",
+					}}},
+				},
+				EnvFrom: []corev1.EnvFromSource{
+					{Prefix: "DB_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: credentials}}},
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: settings}}},
+				},
 			}))}},
 		},
 	}
@@ -337,6 +567,130 @@ func TestContainerizedWorkloadTranslator(t *testing.T) {
 	}
 }
 
+func TestPersistentVolumeClaims(t *testing.T) {
+	diskSize := resource.MustParse("10Gi")
+	ephemeral := true
+	shared := v1alpha2.VolumeSharingPolicyShared
+	storageClass := "fast"
+	claimName := "cool-claim"
+
+	type args struct {
+		w oam.Workload
+	}
+	type want struct {
+		result []*corev1.PersistentVolumeClaim
+		err    error
+	}
+
+	claim := func(volume string, mode corev1.PersistentVolumeAccessMode, class *string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       claimKind,
+				APIVersion: claimAPIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ClaimName(workloadName, volume),
+				Namespace: workloadNamespace,
+				Labels: map[string]string{
+					labelKey: workloadUID,
+				},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{mode},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: diskSize},
+				},
+				StorageClassName: class,
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotAContainerizedWorkload": {
+			reason: "Only ContainerizedWorkloads have claims generated.",
+			args:   args{w: &mock.Workload{}},
+			want:   want{err: errors.New(errNotContainerizedWorkload)},
+		},
+		"NoDisks": {
+			reason: "No claims should be generated for volumes without a disk.",
+			args: args{w: containerizedWorkload(cwWithContainer(v1alpha2.Container{
+				Name: "cool-container",
+				Resources: &v1alpha2.ContainerResources{
+					Volumes: []v1alpha2.VolumeResource{{Name: "cool-volume", MountPath: "/my/cool/path"}},
+				},
+			}))},
+		},
+		"Claims": {
+			reason: "A claim should be generated once for each volume requiring an external disk.",
+			args: args{w: containerizedWorkload(
+				cwWithContainer(v1alpha2.Container{
+					Name: "cool-container",
+					Resources: &v1alpha2.ContainerResources{
+						Volumes: []v1alpha2.VolumeResource{
+							{
+								Name:      "data",
+								MountPath: "/var/data",
+								Disk:      &v1alpha2.DiskResource{Required: diskSize, StorageClass: &storageClass},
+							},
+							{
+								Name:          "shared",
+								MountPath:     "/var/shared",
+								SharingPolicy: &shared,
+								Disk:          &v1alpha2.DiskResource{Required: diskSize},
+							},
+							{
+								Name:      "scratch",
+								MountPath: "/tmp",
+								Disk:      &v1alpha2.DiskResource{Required: diskSize, Ephemeral: &ephemeral},
+							},
+							{
+								Name:                      "claimed",
+								MountPath:                 "/var/claimed",
+								FromPersistentVolumeClaim: &claimName,
+								Disk:                      &v1alpha2.DiskResource{Required: diskSize},
+							},
+						},
+					},
+				}),
+				cwWithContainer(v1alpha2.Container{
+					Name: "sidecar",
+					Resources: &v1alpha2.ContainerResources{
+						Volumes: []v1alpha2.VolumeResource{
+							{
+								Name:      "data",
+								MountPath: "/data",
+								Disk:      &v1alpha2.DiskResource{Required: diskSize, StorageClass: &storageClass},
+							},
+						},
+					},
+				}),
+			)},
+			want: want{result: []*corev1.PersistentVolumeClaim{
+				claim("data", corev1.ReadWriteOnce, &storageClass),
+				claim("shared", corev1.ReadWriteMany, nil),
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := PersistentVolumeClaims(context.Background(), tc.args.w)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nPersistentVolumeClaims(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, r); diff != "" {
+				t.Errorf("\nReason: %s\nPersistentVolumeClaims(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 type serviceModifier func(*corev1.Service)
 
 func sWithContainerPort(target int) serviceModifier {
@@ -349,6 +703,16 @@ func sWithContainerPort(target int) serviceModifier {
 	}
 }
 
+func sWithNamedPort(name string, target int) serviceModifier {
+	return func(s *corev1.Service) {
+		s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{
+			Name:       name,
+			Port:       int32(target),
+			TargetPort: intstr.FromInt(target),
+		})
+	}
+}
+
 func service(mod ...serviceModifier) *corev1.Service {
 	s := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -437,8 +801,41 @@ func TestServiceInjector(t *testing.T) {
 				service(sWithContainerPort(3000)),
 			}},
 		},
+		"NoPorts": {
+			reason: "A Deployment without ports should have no Service injected.",
+			args: args{
+				w: &mock.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []oam.Object{deployment(dmWithContainerPorts())},
+			},
+			want: want{result: []oam.Object{
+				deployment(dmWithContainerPorts()),
+			}},
+		},
+		"SuccessfulInjectService_1D_2C_SamePort": {
+			reason: "A port declared by several containers should be exposed once.",
+			args: args{
+				w: &mock.Workload{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      workloadName,
+						Namespace: workloadNamespace,
+						UID:       types.UID(workloadUID),
+					},
+				},
+				o: []oam.Object{deployment(dmWithContainerPorts(3000), dmWithContainerPorts(3000))},
+			},
+			want: want{result: []oam.Object{
+				deployment(dmWithContainerPorts(3000), dmWithContainerPorts(3000)),
+				service(sWithContainerPort(3000)),
+			}},
+		},
 		"SuccessfulInjectService_1D_1C_2P": {
-			reason: "A Deployment with a port(s) should have a Service injected for first defined port on the first container.",
+			reason: "A Deployment with several ports should have a Service injected for all of them, named uniquely.",
 			args: args{
 				w: &mock.Workload{
 					ObjectMeta: metav1.ObjectMeta{
@@ -451,7 +848,7 @@ func TestServiceInjector(t *testing.T) {
 			},
 			want: want{result: []oam.Object{
 				deployment(dmWithContainerPorts(3000, 3001)),
-				service(sWithContainerPort(3000)),
+				service(sWithNamedPort(portName, 3000), sWithNamedPort("tcp-3001", 3001)),
 			}},
 		},
 		"SuccessfulInjectService_2D_1C_1P": {
@@ -476,7 +873,7 @@ func TestServiceInjector(t *testing.T) {
 			}},
 		},
 		"SuccessfulInjectService_2D_2C_2P": {
-			reason: "The first Deployment with a port(s) should have a Service injected for first defined port on the first container.",
+			reason: "The first Deployment should have a Service injected for the ports of all of its containers.",
 			args: args{
 				w: &mock.Workload{
 					ObjectMeta: metav1.ObjectMeta{
@@ -493,7 +890,8 @@ func TestServiceInjector(t *testing.T) {
 			want: want{result: []oam.Object{
 				deployment(dmWithContainerPorts(3000, 3001), dmWithContainerPorts(4000, 4001)),
 				deployment(dmWithContainerPorts(5000, 5001), dmWithContainerPorts(6000, 6001)),
-				service(sWithContainerPort(3000)),
+				service(sWithNamedPort(portName, 3000), sWithNamedPort("tcp-3001", 3001),
+					sWithNamedPort("tcp-4000", 4000), sWithNamedPort("tcp-4001", 4001)),
 			}},
 		},
 	}
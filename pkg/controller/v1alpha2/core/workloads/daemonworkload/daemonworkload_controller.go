@@ -0,0 +1,299 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonworkload
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errRenderDaemonSet = "cannot render the daemon set"
+	errRenderService   = "cannot render the service"
+	errApplyDaemonSet  = "cannot apply the daemon set"
+	errApplyService    = "cannot apply the service"
+	errDeleteService   = "cannot delete the service"
+
+	msgDaemonSetNotObserved = "the daemon set has not observed its latest spec yet"
+	msgFmtNodesNotReady     = "%d/%d nodes ready"
+	msgFmtRollingOut        = "rolling out: %d/%d nodes updated"
+)
+
+const labelKey = "daemonworkload.oam.crossplane.io"
+
+var (
+	daemonSetKind       = reflect.TypeOf(appsv1.DaemonSet{}).Name()
+	daemonSetAPIVersion = appsv1.SchemeGroupVersion.String()
+	serviceKind         = reflect.TypeOf(corev1.Service{}).Name()
+	serviceAPIVersion   = corev1.SchemeGroupVersion.String()
+)
+
+// Setup adds a controller that reconciles DaemonWorkloads.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		log:    ctrl.Log.WithName("DaemonWorkload"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(v1alpha2.DaemonWorkloadKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("DaemonWorkload"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a DaemonWorkload object
+type Reconciler struct {
+	client.Client
+	log    logr.Logger
+	record event.Recorder
+	Scheme *runtime.Scheme
+}
+
+// Reconcile a DaemonWorkload by running its containers in a DaemonSet, and
+// exposing the ports they declare, if any, through a Service.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=daemonworkloads,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=daemonworkloads/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// nolint:gocyclo
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.log.WithValues("daemonworkload", req.NamespacedName)
+	log.Info("Reconcile daemon workload")
+
+	var workload v1alpha2.DaemonWorkload
+	if err := r.Get(ctx, req.NamespacedName, &workload); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &workload)
+	if eventObj == nil {
+		// fallback to the workload itself
+		log.Error(err, "Failed to find the parent resource", "workload", workload.Name)
+		eventObj = &workload
+	}
+
+	ds := renderDaemonSet(&workload)
+	if err := ctrl.SetControllerReference(&workload, ds, r.Scheme); err != nil {
+		r.record.Event(eventObj, event.Warning(errRenderDaemonSet, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderDaemonSet)))
+	}
+	// server side apply, only the fields we set are touched
+	applyOpts := []client.PatchOption{client.ForceOwnership, client.FieldOwner(workload.GetUID())}
+	if err := r.Patch(ctx, ds, client.Apply, applyOpts...); err != nil {
+		log.Error(err, "Failed to apply the daemon set")
+		r.record.Event(eventObj, event.Warning(errApplyDaemonSet, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyDaemonSet)))
+	}
+	r.record.Event(eventObj, event.Normal("DaemonSet created",
+		fmt.Sprintf("Workload `%s` successfully server side patched a daemon set `%s`", workload.Name, ds.Name)))
+
+	service := renderService(&workload, ds)
+	if service != nil {
+		if err := ctrl.SetControllerReference(&workload, service, r.Scheme); err != nil {
+			r.record.Event(eventObj, event.Warning(errRenderService, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderService)))
+		}
+		if err := r.Patch(ctx, service, client.Apply, applyOpts...); err != nil {
+			log.Error(err, "Failed to apply the service")
+			r.record.Event(eventObj, event.Warning(errApplyService, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyService)))
+		}
+	} else if err := r.deleteService(ctx, &workload); err != nil {
+		// the containers no longer declare ports
+		log.Error(err, "Failed to delete the service")
+		r.record.Event(eventObj, event.Warning(errDeleteService, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errDeleteService)))
+	}
+
+	available := rollUpStatus(&workload, ds, service)
+	workload.SetConditions(available)
+	workload.Status.Resources = []cpv1alpha1.TypedReference{
+		{APIVersion: daemonSetAPIVersion, Kind: daemonSetKind, Name: ds.GetName(), UID: ds.GetUID()},
+	}
+	if service != nil {
+		workload.Status.Resources = append(workload.Status.Resources, cpv1alpha1.TypedReference{
+			APIVersion: serviceAPIVersion, Kind: serviceKind, Name: service.GetName(), UID: service.GetUID(),
+		})
+	}
+	if err := r.Status().Update(ctx, &workload); err != nil {
+		return util.ReconcileWaitResult, err
+	}
+	if available.Reason != cpv1alpha1.ReasonAvailable {
+		// the status of the daemon set is not watched, so that it is polled
+		// until the workload is available
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileSuccess())
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileSuccess())
+}
+
+// SetupWithManager setups up k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(v1alpha2.DaemonWorkloadKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.DaemonWorkload{}).
+		Owns(&appsv1.DaemonSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.Service{}).
+		Complete(r)
+}
+
+// deleteService deletes the service of the supplied workload, if it has one.
+func (r *Reconciler) deleteService(ctx context.Context, w *v1alpha2.DaemonWorkload) error {
+	service := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: w.GetNamespace(), Name: w.GetName()}, service); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(service, w) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, service))
+}
+
+// renderDaemonSet renders the daemon set that runs the containers of the
+// supplied workload on every node it selects.
+func renderDaemonSet(w *v1alpha2.DaemonWorkload) *appsv1.DaemonSet {
+	ps := containerizedworkload.PodSpec(w.GetName(), w.Spec.ContainerizedWorkloadSpec)
+	// the operating system and CPU architecture of the workload take precedence
+	ps.NodeSelector = util.MergeMap(w.Spec.NodeSelector, ps.NodeSelector)
+	// k8s server-side patch complains if the protocol is not set
+	for i := range ps.Containers {
+		for j := range ps.Containers[i].Ports {
+			if ps.Containers[i].Ports[j].Protocol == "" {
+				ps.Containers[i].Ports[j].Protocol = corev1.ProtocolTCP
+			}
+		}
+	}
+
+	ds := &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       daemonSetKind,
+			APIVersion: daemonSetAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.GetName(),
+			Namespace: w.GetNamespace(),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					labelKey: string(w.GetUID()),
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						labelKey: string(w.GetUID()),
+					},
+				},
+				Spec: ps,
+			},
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+					MaxUnavailable: w.Spec.MaxUnavailable,
+				},
+			},
+		},
+	}
+	// pass through label and annotation from the workload to the daemon set and its pods
+	util.PassLabelAndAnnotation(w, ds)
+	util.PassLabelAndAnnotation(w, &ds.Spec.Template)
+	return ds
+}
+
+// renderService renders the service that exposes the ports declared by the
+// containers of the supplied daemon set, or nil if they declare none.
+func renderService(w *v1alpha2.DaemonWorkload, ds *appsv1.DaemonSet) *corev1.Service {
+	ports := containerizedworkload.ServicePorts(ds.GetName(), ds.Spec.Template.Spec.Containers)
+	if len(ports) == 0 {
+		return nil
+	}
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       serviceKind,
+			APIVersion: serviceAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ds.GetName(),
+			Namespace: w.GetNamespace(),
+			Labels: map[string]string{
+				labelKey: string(w.GetUID()),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: ds.Spec.Selector.MatchLabels,
+			Ports:    ports,
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+// rollUpStatus records the nodes of the daemon set and the cluster IP of the
+// service, if any, in the status of the workload, and returns whether the
+// workload is available, i.e. runs a ready pod of its latest spec on all of
+// its nodes.
+func rollUpStatus(w *v1alpha2.DaemonWorkload, ds *appsv1.DaemonSet, service *corev1.Service) cpv1alpha1.Condition {
+	w.Status.DesiredNodes = ds.Status.DesiredNumberScheduled
+	w.Status.ReadyNodes = ds.Status.NumberReady
+	w.Status.UpdatedNodes = ds.Status.UpdatedNumberScheduled
+	w.Status.ServiceIP = ""
+	if service != nil {
+		w.Status.ServiceIP = service.Spec.ClusterIP
+	}
+
+	if ds.Status.ObservedGeneration < ds.GetGeneration() {
+		return cpv1alpha1.Unavailable().WithMessage(msgDaemonSetNotObserved)
+	}
+	if w.Status.ReadyNodes < w.Status.DesiredNodes {
+		return cpv1alpha1.Unavailable().WithMessage(
+			fmt.Sprintf(msgFmtNodesNotReady, w.Status.ReadyNodes, w.Status.DesiredNodes))
+	}
+	if w.Status.UpdatedNodes < w.Status.DesiredNodes {
+		return cpv1alpha1.Unavailable().WithMessage(
+			fmt.Sprintf(msgFmtRollingOut, w.Status.UpdatedNodes, w.Status.DesiredNodes))
+	}
+	return cpv1alpha1.Available()
+}
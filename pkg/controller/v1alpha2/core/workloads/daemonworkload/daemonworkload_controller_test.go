@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonworkload
+
+import (
+	"testing"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+)
+
+const (
+	workloadName      = "node-exporter"
+	workloadNamespace = "ns"
+	workloadUID       = "node-exporter-uid"
+)
+
+func daemonWorkload(ports ...v1alpha2.ContainerPort) *v1alpha2.DaemonWorkload {
+	linux := v1alpha2.OperatingSystem("linux")
+	maxUnavailable := intstr.FromString("10%")
+	return &v1alpha2.DaemonWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+		Spec: v1alpha2.DaemonWorkloadSpec{
+			ContainerizedWorkloadSpec: v1alpha2.ContainerizedWorkloadSpec{
+				OperatingSystem: &linux,
+				Containers: []v1alpha2.Container{{
+					Name:  "exporter",
+					Image: "prom/node-exporter:v1.0.1",
+					Ports: ports,
+				}},
+			},
+			NodeSelector:   map[string]string{corev1.LabelOSStable: "windows", "role": "edge"},
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+}
+
+func TestRenderDaemonSet(t *testing.T) {
+	w := daemonWorkload(v1alpha2.ContainerPort{Name: "metrics", Port: 9100})
+	maxUnavailable := intstr.FromString("10%")
+
+	ps := containerizedworkload.PodSpec(workloadName, w.Spec.ContainerizedWorkloadSpec)
+	ps.NodeSelector = map[string]string{corev1.LabelOSStable: "linux", "role": "edge"}
+	ps.Containers[0].Ports[0].Protocol = corev1.ProtocolTCP
+
+	want := &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{Kind: daemonSetKind, APIVersion: daemonSetAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{labelKey: workloadUID}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{labelKey: workloadUID}},
+				Spec:       ps,
+			},
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, renderDaemonSet(w)); diff != "" {
+		t.Errorf("renderDaemonSet(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRenderService(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		w      *v1alpha2.DaemonWorkload
+		want   *corev1.Service
+	}{
+		"NoPorts": {
+			reason: "No service should be rendered for containers that declare no ports",
+			w:      daemonWorkload(),
+		},
+		"Ports": {
+			reason: "A service should expose the ports the containers declare",
+			w:      daemonWorkload(v1alpha2.ContainerPort{Name: "metrics", Port: 9100}),
+			want: &corev1.Service{
+				TypeMeta: metav1.TypeMeta{Kind: serviceKind, APIVersion: serviceAPIVersion},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      workloadName,
+					Namespace: workloadNamespace,
+					Labels:    map[string]string{labelKey: workloadUID},
+				},
+				Spec: corev1.ServiceSpec{
+					Selector: map[string]string{labelKey: workloadUID},
+					Ports: []corev1.ServicePort{{
+						Name:       workloadName,
+						Protocol:   corev1.ProtocolTCP,
+						Port:       9100,
+						TargetPort: intstr.FromInt(9100),
+					}},
+					Type: corev1.ServiceTypeClusterIP,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := renderService(tc.w, renderDaemonSet(tc.w))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nrenderService(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRollUpStatus(t *testing.T) {
+	type want struct {
+		condition cpv1alpha1.Condition
+		status    v1alpha2.DaemonWorkloadStatus
+	}
+	cases := map[string]struct {
+		reason  string
+		ds      *appsv1.DaemonSet
+		service *corev1.Service
+		want    want
+	}{
+		"NotObserved": {
+			reason: "A workload should be unavailable while its daemon set has not observed its latest spec",
+			ds: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3},
+			},
+			want: want{
+				condition: cpv1alpha1.Unavailable().WithMessage(msgDaemonSetNotObserved),
+				status:    v1alpha2.DaemonWorkloadStatus{DesiredNodes: 3, ReadyNodes: 3, UpdatedNodes: 3},
+			},
+		},
+		"NodesNotReady": {
+			reason: "A workload should be unavailable while it is not ready on some of its nodes",
+			ds: &appsv1.DaemonSet{
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2, UpdatedNumberScheduled: 3},
+			},
+			want: want{
+				condition: cpv1alpha1.Unavailable().WithMessage("2/3 nodes ready"),
+				status:    v1alpha2.DaemonWorkloadStatus{DesiredNodes: 3, ReadyNodes: 2, UpdatedNodes: 3},
+			},
+		},
+		"RollingOut": {
+			reason: "A workload should be unavailable while its latest spec is rolled out",
+			ds: &appsv1.DaemonSet{
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 1},
+			},
+			want: want{
+				condition: cpv1alpha1.Unavailable().WithMessage("rolling out: 1/3 nodes updated"),
+				status:    v1alpha2.DaemonWorkloadStatus{DesiredNodes: 3, ReadyNodes: 3, UpdatedNodes: 1},
+			},
+		},
+		"Available": {
+			reason: "A workload should be available once it runs a ready pod of its latest spec on all of its nodes",
+			ds: &appsv1.DaemonSet{
+				Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3},
+			},
+			service: &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "10.0.0.1"}},
+			want: want{
+				condition: cpv1alpha1.Available(),
+				status:    v1alpha2.DaemonWorkloadStatus{DesiredNodes: 3, ReadyNodes: 3, UpdatedNodes: 3, ServiceIP: "10.0.0.1"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &v1alpha2.DaemonWorkload{}
+			got := rollUpStatus(w, tc.ds, tc.service)
+			if diff := cmp.Diff(tc.want.condition, got); diff != "" {
+				t.Errorf("\nReason: %s\nrollUpStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, w.Status); diff != "" {
+				t.Errorf("\nReason: %s\nrollUpStatus(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
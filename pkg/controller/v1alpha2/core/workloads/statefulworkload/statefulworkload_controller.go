@@ -0,0 +1,359 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulworkload
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errRenderStatefulSet = "cannot render the stateful set"
+	errRenderService     = "cannot render the service"
+	errGetStatefulSet    = "cannot get the stateful set"
+	errDeleteStatefulSet = "cannot delete the stateful set to recreate it"
+	errApplyStatefulSet  = "cannot apply the stateful set"
+	errApplyService      = "cannot apply the service"
+	errFmtNotControlled  = "stateful set %q is not controlled by the workload"
+
+	msgStatefulSetNotObserved = "the stateful set has not observed its latest spec yet"
+	msgFmtReplicasNotReady    = "%d/%d replicas ready"
+	msgFmtRollingOut          = "rolling out revision %s: %d/%d replicas updated"
+)
+
+const labelKey = "statefulworkload.oam.crossplane.io"
+
+var (
+	statefulSetKind       = reflect.TypeOf(appsv1.StatefulSet{}).Name()
+	statefulSetAPIVersion = appsv1.SchemeGroupVersion.String()
+	serviceKind           = reflect.TypeOf(corev1.Service{}).Name()
+	serviceAPIVersion     = corev1.SchemeGroupVersion.String()
+)
+
+// Setup adds a controller that reconciles StatefulWorkloads.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		log:    ctrl.Log.WithName("StatefulWorkload"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(v1alpha2.StatefulWorkloadKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("StatefulWorkload"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a StatefulWorkload object
+type Reconciler struct {
+	client.Client
+	log    logr.Logger
+	record event.Recorder
+	Scheme *runtime.Scheme
+}
+
+// Reconcile a StatefulWorkload by running its containers in a StatefulSet
+// that is governed by a headless Service. The volume claim templates and the
+// pod management policy of a StatefulSet are immutable, so that it is deleted,
+// orphaning its pods and claims, and created again when they change.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=statefulworkloads,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=statefulworkloads/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// nolint:gocyclo
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.log.WithValues("statefulworkload", req.NamespacedName)
+	log.Info("Reconcile stateful workload")
+
+	var workload v1alpha2.StatefulWorkload
+	if err := r.Get(ctx, req.NamespacedName, &workload); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &workload)
+	if eventObj == nil {
+		// fallback to the workload itself
+		log.Error(err, "Failed to find the parent resource", "workload", workload.Name)
+		eventObj = &workload
+	}
+
+	ss := renderStatefulSet(&workload)
+	if err := ctrl.SetControllerReference(&workload, ss, r.Scheme); err != nil {
+		r.record.Event(eventObj, event.Warning(errRenderStatefulSet, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderStatefulSet)))
+	}
+	service := renderService(&workload, ss)
+	if err := ctrl.SetControllerReference(&workload, service, r.Scheme); err != nil {
+		r.record.Event(eventObj, event.Warning(errRenderService, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderService)))
+	}
+
+	existing := &appsv1.StatefulSet{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: ss.GetNamespace(), Name: ss.GetName()}, existing)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		r.record.Event(eventObj, event.Warning(errGetStatefulSet, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errGetStatefulSet)))
+	case !metav1.IsControlledBy(existing, &workload):
+		err := errors.Errorf(errFmtNotControlled, existing.GetName())
+		r.record.Event(eventObj, event.Warning(errGetStatefulSet, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(err))
+	case immutableChanged(existing, ss):
+		// the pods and claims are adopted by the stateful set created next
+		if err := r.Delete(ctx, existing, client.PropagationPolicy(metav1.DeletePropagationOrphan)); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Failed to delete the stateful set")
+			r.record.Event(eventObj, event.Warning(errDeleteStatefulSet, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errDeleteStatefulSet)))
+		}
+		r.record.Event(eventObj, event.Normal("StatefulSet deleted",
+			fmt.Sprintf("Workload `%s` deleted the stateful set `%s` to recreate it", workload.Name, ss.Name)))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileSuccess())
+	}
+
+	// server side apply, only the fields we set are touched
+	applyOpts := []client.PatchOption{client.ForceOwnership, client.FieldOwner(workload.GetUID())}
+	// the service governs the network identity of the pods of the stateful set
+	if err := r.Patch(ctx, service, client.Apply, applyOpts...); err != nil {
+		log.Error(err, "Failed to apply the service")
+		r.record.Event(eventObj, event.Warning(errApplyService, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyService)))
+	}
+	if err := r.Patch(ctx, ss, client.Apply, applyOpts...); err != nil {
+		log.Error(err, "Failed to apply the stateful set")
+		r.record.Event(eventObj, event.Warning(errApplyStatefulSet, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyStatefulSet)))
+	}
+	r.record.Event(eventObj, event.Normal("StatefulSet created",
+		fmt.Sprintf("Workload `%s` successfully server side patched a stateful set `%s`", workload.Name, ss.Name)))
+
+	available := rollUpStatus(&workload, ss)
+	workload.SetConditions(available)
+	workload.Status.Resources = []cpv1alpha1.TypedReference{
+		{APIVersion: statefulSetAPIVersion, Kind: statefulSetKind, Name: ss.GetName(), UID: ss.GetUID()},
+		{APIVersion: serviceAPIVersion, Kind: serviceKind, Name: service.GetName(), UID: service.GetUID()},
+	}
+	if err := r.Status().Update(ctx, &workload); err != nil {
+		return util.ReconcileWaitResult, err
+	}
+	if available.Reason != cpv1alpha1.ReasonAvailable {
+		// the status of the stateful set is not watched, so that it is
+		// polled until the workload is available
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileSuccess())
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &workload, cpv1alpha1.ReconcileSuccess())
+}
+
+// SetupWithManager setups up k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(v1alpha2.StatefulWorkloadKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.StatefulWorkload{}).
+		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.Service{}).
+		Complete(r)
+}
+
+// renderStatefulSet renders the stateful set that runs the containers of the
+// supplied workload. Each replica gets its own claims of the volumes that
+// require an external disk.
+func renderStatefulSet(w *v1alpha2.StatefulWorkload) *appsv1.StatefulSet {
+	ps := containerizedworkload.PodSpec(w.GetName(), w.Spec.ContainerizedWorkloadSpec)
+
+	var templates []corev1.PersistentVolumeClaim
+	claimed := map[string]bool{}
+	for _, v := range containerizedworkload.PersistentVolumes(w.Spec.ContainerizedWorkloadSpec) {
+		claimed[v.Name] = true
+		templates = append(templates, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: v.Name},
+			Spec:       containerizedworkload.ClaimSpec(v),
+		})
+	}
+	// the stateful set adds the volumes of the claims of each replica
+	var volumes []corev1.Volume
+	for _, v := range ps.Volumes {
+		if !claimed[v.Name] {
+			volumes = append(volumes, v)
+		}
+	}
+	ps.Volumes = volumes
+	// k8s server-side patch complains if the protocol is not set
+	for i := range ps.Containers {
+		for j := range ps.Containers[i].Ports {
+			if ps.Containers[i].Ports[j].Protocol == "" {
+				ps.Containers[i].Ports[j].Protocol = corev1.ProtocolTCP
+			}
+		}
+	}
+
+	policy := appsv1.OrderedReadyPodManagement
+	if w.Spec.PodManagementPolicy != nil {
+		policy = appsv1.PodManagementPolicyType(*w.Spec.PodManagementPolicy)
+	}
+
+	ss := &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       statefulSetKind,
+			APIVersion: statefulSetAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.GetName(),
+			Namespace: w.GetNamespace(),
+		},
+		Spec: appsv1.StatefulSetSpec{
+			// replicas are left to scaler traits unless the workload sets them
+			Replicas: w.Spec.Replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					labelKey: string(w.GetUID()),
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						labelKey: string(w.GetUID()),
+					},
+				},
+				Spec: ps,
+			},
+			VolumeClaimTemplates: templates,
+			ServiceName:          w.GetName(),
+			PodManagementPolicy:  policy,
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+					Partition: w.Spec.Partition,
+				},
+			},
+		},
+	}
+	// pass through label and annotation from the workload to the stateful set and its pods
+	util.PassLabelAndAnnotation(w, ss)
+	util.PassLabelAndAnnotation(w, &ss.Spec.Template)
+	return ss
+}
+
+// renderService renders the headless service that governs the network
+// identity of the pods of the supplied stateful set, e.g. db-0.db.
+func renderService(w *v1alpha2.StatefulWorkload, ss *appsv1.StatefulSet) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       serviceKind,
+			APIVersion: serviceAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ss.Spec.ServiceName,
+			Namespace: w.GetNamespace(),
+			Labels: map[string]string{
+				labelKey: string(w.GetUID()),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  ss.Spec.Selector.MatchLabels,
+			Ports:     containerizedworkload.ServicePorts(ss.Spec.ServiceName, ss.Spec.Template.Spec.Containers),
+		},
+	}
+}
+
+// immutableChanged returns true if the supplied stateful set must be created
+// again to apply the desired one, i.e. if their pod management policies or
+// the names of their volume claim templates differ.
+func immutableChanged(existing, desired *appsv1.StatefulSet) bool {
+	if existing.Spec.PodManagementPolicy != desired.Spec.PodManagementPolicy ||
+		len(existing.Spec.VolumeClaimTemplates) != len(desired.Spec.VolumeClaimTemplates) {
+		return true
+	}
+	names := map[string]bool{}
+	for _, t := range existing.Spec.VolumeClaimTemplates {
+		names[t.GetName()] = true
+	}
+	for _, t := range desired.Spec.VolumeClaimTemplates {
+		if !names[t.GetName()] {
+			return true
+		}
+	}
+	return false
+}
+
+// rollUpStatus records the replicas and revisions of the stateful set in the
+// status of the workload, and returns whether the workload is available, i.e.
+// all of its replicas are ready and those at or above the partition run the
+// update revision.
+func rollUpStatus(w *v1alpha2.StatefulWorkload, ss *appsv1.StatefulSet) cpv1alpha1.Condition {
+	w.Status.Replicas = ss.Status.Replicas
+	if ss.Spec.Replicas != nil {
+		w.Status.Replicas = *ss.Spec.Replicas
+	}
+	w.Status.ReadyReplicas = ss.Status.ReadyReplicas
+	w.Status.CurrentReplicas = ss.Status.CurrentReplicas
+	w.Status.UpdatedReplicas = ss.Status.UpdatedReplicas
+	w.Status.CurrentRevision = ss.Status.CurrentRevision
+	w.Status.UpdateRevision = ss.Status.UpdateRevision
+
+	if ss.Status.ObservedGeneration < ss.GetGeneration() {
+		return cpv1alpha1.Unavailable().WithMessage(msgStatefulSetNotObserved)
+	}
+	if w.Status.ReadyReplicas < w.Status.Replicas {
+		return cpv1alpha1.Unavailable().WithMessage(
+			fmt.Sprintf(msgFmtReplicasNotReady, w.Status.ReadyReplicas, w.Status.Replicas))
+	}
+	updating := w.Status.Replicas
+	if u := ss.Spec.UpdateStrategy.RollingUpdate; u != nil && u.Partition != nil {
+		updating -= *u.Partition
+	}
+	if updating < 0 {
+		updating = 0
+	}
+	if w.Status.CurrentRevision != w.Status.UpdateRevision && w.Status.UpdatedReplicas < updating {
+		return cpv1alpha1.Unavailable().WithMessage(
+			fmt.Sprintf(msgFmtRollingOut, w.Status.UpdateRevision, w.Status.UpdatedReplicas, updating))
+	}
+	return cpv1alpha1.Available()
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulworkload
+
+import (
+	"testing"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+)
+
+const (
+	workloadName      = "db"
+	workloadNamespace = "ns"
+	workloadUID       = "db-uid"
+)
+
+func statefulWorkload() *v1alpha2.StatefulWorkload {
+	three := int32(3)
+	one := int32(1)
+	parallel := v1alpha2.PodManagementPolicyParallel
+	return &v1alpha2.StatefulWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			UID:       types.UID(workloadUID),
+		},
+		Spec: v1alpha2.StatefulWorkloadSpec{
+			ContainerizedWorkloadSpec: v1alpha2.ContainerizedWorkloadSpec{
+				Containers: []v1alpha2.Container{{
+					Name:  "postgres",
+					Image: "postgres:12",
+					Ports: []v1alpha2.ContainerPort{{Name: "sql", Port: 5432}},
+					Resources: &v1alpha2.ContainerResources{
+						Volumes: []v1alpha2.VolumeResource{
+							{
+								Name:      "data",
+								MountPath: "/var/lib/postgresql/data",
+								Disk:      &v1alpha2.DiskResource{Required: resource.MustParse("10Gi")},
+							},
+							{
+								Name:      "scratch",
+								MountPath: "/tmp",
+							},
+						},
+					},
+				}},
+			},
+			Replicas:            &three,
+			PodManagementPolicy: &parallel,
+			Partition:           &one,
+		},
+	}
+}
+
+func TestRenderStatefulSet(t *testing.T) {
+	three := int32(3)
+	one := int32(1)
+	w := statefulWorkload()
+
+	ps := containerizedworkload.PodSpec(workloadName, w.Spec.ContainerizedWorkloadSpec)
+	ps.Volumes = []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	ps.Containers[0].Ports[0].Protocol = corev1.ProtocolTCP
+
+	want := &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{Kind: statefulSetKind, APIVersion: statefulSetAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &three,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{labelKey: workloadUID}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{labelKey: workloadUID}},
+				Spec:       ps,
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}},
+			ServiceName:         workloadName,
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &one},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, renderStatefulSet(w)); diff != "" {
+		t.Errorf("renderStatefulSet(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestRenderService(t *testing.T) {
+	w := statefulWorkload()
+	want := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: serviceKind, APIVersion: serviceAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workloadName,
+			Namespace: workloadNamespace,
+			Labels:    map[string]string{labelKey: workloadUID},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{labelKey: workloadUID},
+			Ports: []corev1.ServicePort{{
+				Name:       workloadName,
+				Protocol:   corev1.ProtocolTCP,
+				Port:       5432,
+				TargetPort: intstr.FromInt(5432),
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, renderService(w, renderStatefulSet(w))); diff != "" {
+		t.Errorf("renderService(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestImmutableChanged(t *testing.T) {
+	claims := func(names ...string) []corev1.PersistentVolumeClaim {
+		c := make([]corev1.PersistentVolumeClaim, len(names))
+		for i, n := range names {
+			c[i].SetName(n)
+		}
+		return c
+	}
+
+	cases := map[string]struct {
+		reason   string
+		existing appsv1.StatefulSetSpec
+		desired  appsv1.StatefulSetSpec
+		want     bool
+	}{
+		"Unchanged": {
+			reason:   "A stateful set whose claim templates are only reordered can be updated",
+			existing: appsv1.StatefulSetSpec{PodManagementPolicy: appsv1.OrderedReadyPodManagement, VolumeClaimTemplates: claims("data", "wal")},
+			desired:  appsv1.StatefulSetSpec{PodManagementPolicy: appsv1.OrderedReadyPodManagement, VolumeClaimTemplates: claims("wal", "data")},
+			want:     false,
+		},
+		"PodManagementPolicyChanged": {
+			reason:   "A stateful set whose pod management policy changed must be created again",
+			existing: appsv1.StatefulSetSpec{PodManagementPolicy: appsv1.OrderedReadyPodManagement},
+			desired:  appsv1.StatefulSetSpec{PodManagementPolicy: appsv1.ParallelPodManagement},
+			want:     true,
+		},
+		"ClaimTemplateAdded": {
+			reason:   "A stateful set that gains a claim template must be created again",
+			existing: appsv1.StatefulSetSpec{VolumeClaimTemplates: claims("data")},
+			desired:  appsv1.StatefulSetSpec{VolumeClaimTemplates: claims("data", "wal")},
+			want:     true,
+		},
+		"ClaimTemplateRenamed": {
+			reason:   "A stateful set whose claim template was renamed must be created again",
+			existing: appsv1.StatefulSetSpec{VolumeClaimTemplates: claims("data")},
+			desired:  appsv1.StatefulSetSpec{VolumeClaimTemplates: claims("pgdata")},
+			want:     true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := immutableChanged(&appsv1.StatefulSet{Spec: tc.existing}, &appsv1.StatefulSet{Spec: tc.desired})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nimmutableChanged(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRollUpStatus(t *testing.T) {
+	three := int32(3)
+	one := int32(1)
+
+	type want struct {
+		condition cpv1alpha1.Condition
+		status    v1alpha2.StatefulWorkloadStatus
+	}
+	cases := map[string]struct {
+		reason string
+		ss     *appsv1.StatefulSet
+		want   want
+	}{
+		"NotObserved": {
+			reason: "A workload should be unavailable while its stateful set has not observed its latest spec",
+			ss: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &three},
+				Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3},
+			},
+			want: want{
+				condition: cpv1alpha1.Unavailable().WithMessage(msgStatefulSetNotObserved),
+				status:    v1alpha2.StatefulWorkloadStatus{Replicas: 3, ReadyReplicas: 3},
+			},
+		},
+		"ReplicasNotReady": {
+			reason: "A workload should be unavailable while some of its replicas are not ready",
+			ss: &appsv1.StatefulSet{
+				Spec:   appsv1.StatefulSetSpec{Replicas: &three},
+				Status: appsv1.StatefulSetStatus{ReadyReplicas: 1, CurrentRevision: "db-1", UpdateRevision: "db-1"},
+			},
+			want: want{
+				condition: cpv1alpha1.Unavailable().WithMessage("1/3 replicas ready"),
+				status:    v1alpha2.StatefulWorkloadStatus{Replicas: 3, ReadyReplicas: 1, CurrentRevision: "db-1", UpdateRevision: "db-1"},
+			},
+		},
+		"RollingOut": {
+			reason: "A workload should be unavailable while its update revision is rolled out",
+			ss: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: &three},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas: 3, CurrentReplicas: 2, UpdatedReplicas: 1,
+					CurrentRevision: "db-1", UpdateRevision: "db-2",
+				},
+			},
+			want: want{
+				condition: cpv1alpha1.Unavailable().WithMessage("rolling out revision db-2: 1/3 replicas updated"),
+				status: v1alpha2.StatefulWorkloadStatus{
+					Replicas: 3, ReadyReplicas: 3, CurrentReplicas: 2, UpdatedReplicas: 1,
+					CurrentRevision: "db-1", UpdateRevision: "db-2",
+				},
+			},
+		},
+		"PartitionRolledOut": {
+			reason: "A workload should be available once the replicas at or above its partition run the update revision",
+			ss: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: &three,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &one},
+					},
+				},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas: 3, CurrentReplicas: 1, UpdatedReplicas: 2,
+					CurrentRevision: "db-1", UpdateRevision: "db-2",
+				},
+			},
+			want: want{
+				condition: cpv1alpha1.Available(),
+				status: v1alpha2.StatefulWorkloadStatus{
+					Replicas: 3, ReadyReplicas: 3, CurrentReplicas: 1, UpdatedReplicas: 2,
+					CurrentRevision: "db-1", UpdateRevision: "db-2",
+				},
+			},
+		},
+		"Available": {
+			reason: "A workload should be available once all of its replicas are ready and run the same revision",
+			ss: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{Replicas: &three},
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas: 3, CurrentReplicas: 3, UpdatedReplicas: 3,
+					CurrentRevision: "db-2", UpdateRevision: "db-2",
+				},
+			},
+			want: want{
+				condition: cpv1alpha1.Available(),
+				status: v1alpha2.StatefulWorkloadStatus{
+					Replicas: 3, ReadyReplicas: 3, CurrentReplicas: 3, UpdatedReplicas: 3,
+					CurrentRevision: "db-2", UpdateRevision: "db-2",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &v1alpha2.StatefulWorkload{}
+			got := rollUpStatus(w, tc.ss)
+			if diff := cmp.Diff(tc.want.condition, got); diff != "" {
+				t.Errorf("\nReason: %s\nrollUpStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, w.Status); diff != "" {
+				t.Errorf("\nReason: %s\nrollUpStatus(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskworkload
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Reconcile error strings.
+const (
+	errRenderClaims     = "cannot render persistent volume claims"
+	errApplyClaim       = "cannot apply a persistent volume claim"
+	errGetJob           = "cannot get the job"
+	errRenderJob        = "cannot render the job"
+	errCreateJob        = "cannot create the job"
+	errDeleteJob        = "cannot delete the outdated job"
+	errFmtNotControlled = "job %q is not controlled by the task"
+
+	msgFmtRunning = "%d/%d pods succeeded"
+	msgFmtFailed  = "%s: %s"
+)
+
+const (
+	labelKey = "taskworkload.oam.crossplane.io"
+
+	// generationAnnotation records the generation of the task a job runs.
+	generationAnnotation = "taskworkload.oam.crossplane.io/generation"
+)
+
+var (
+	jobKind       = reflect.TypeOf(batchv1.Job{}).Name()
+	jobAPIVersion = batchv1.SchemeGroupVersion.String()
+)
+
+// Setup adds a controller that reconciles TaskWorkloads.
+func Setup(mgr ctrl.Manager, args controller.Args, log logging.Logger) error {
+	reconciler := Reconciler{
+		Client: mgr.GetClient(),
+		log:    ctrl.Log.WithName("TaskWorkload"),
+		record: metrics.NewRecorder("oam/"+strings.ToLower(v1alpha2.TaskWorkloadKind),
+			event.NewAPIRecorder(mgr.GetEventRecorderFor("TaskWorkload"))),
+		Scheme: mgr.GetScheme(),
+	}
+	return reconciler.SetupWithManager(mgr)
+}
+
+// Reconciler reconciles a TaskWorkload object
+type Reconciler struct {
+	client.Client
+	log    logr.Logger
+	record event.Recorder
+	Scheme *runtime.Scheme
+}
+
+// Reconcile a TaskWorkload by running its containers to completion in a Job.
+// The pod template of a Job is immutable, so that a new Job is created for
+// each generation of the task. Jobs that were deleted after they finished,
+// e.g. because of their TTL, are not created again.
+// +kubebuilder:rbac:groups=core.oam.dev,resources=taskworkloads,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.oam.dev,resources=taskworkloads/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// nolint:gocyclo
+func (r *Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.log.WithValues("taskworkload", req.NamespacedName)
+	log.Info("Reconcile task workload")
+
+	var task v1alpha2.TaskWorkload
+	if err := r.Get(ctx, req.NamespacedName, &task); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// find the resource object to record the event to, default is the parent appConfig.
+	eventObj, err := util.LocateParentAppConfig(ctx, r.Client, &task)
+	if eventObj == nil {
+		// fallback to the task itself
+		log.Error(err, "Failed to find the parent resource", "task", task.Name)
+		eventObj = &task
+	}
+
+	claims := containerizedworkload.VolumeClaims(&task, task.Spec.ContainerizedWorkloadSpec)
+	for _, claim := range claims {
+		// the claims are deleted with the task
+		if err := ctrl.SetControllerReference(&task, claim, r.Scheme); err != nil {
+			r.record.Event(eventObj, event.Warning(errRenderClaims, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderClaims)))
+		}
+		// server side apply, only the fields we set are touched
+		if err := r.Patch(ctx, claim, client.Apply, client.ForceOwnership, client.FieldOwner(task.GetUID())); err != nil {
+			log.Error(err, "Failed to apply a persistent volume claim")
+			r.record.Event(eventObj, event.Warning(errApplyClaim, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileError(errors.Wrap(err, errApplyClaim)))
+		}
+	}
+
+	job := &batchv1.Job{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: task.GetNamespace(), Name: task.GetName()}, job)
+	switch {
+	case apierrors.IsNotFound(err) && finished(&task):
+		// tasks run once per generation
+		log.Info("The job of the task was deleted after it finished")
+		return ctrl.Result{}, nil
+	case apierrors.IsNotFound(err):
+		job = renderJob(&task)
+		if err := ctrl.SetControllerReference(&task, job, r.Scheme); err != nil {
+			r.record.Event(eventObj, event.Warning(errRenderJob, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileError(errors.Wrap(err, errRenderJob)))
+		}
+		if err := r.Create(ctx, job); err != nil {
+			log.Error(err, "Failed to create the job")
+			r.record.Event(eventObj, event.Warning(errCreateJob, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileError(errors.Wrap(err, errCreateJob)))
+		}
+		r.record.Event(eventObj, event.Normal("Job created",
+			fmt.Sprintf("Workload `%s` successfully created a job `%s`", task.Name, job.Name)))
+	case err != nil:
+		r.record.Event(eventObj, event.Warning(errGetJob, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileError(errors.Wrap(err, errGetJob)))
+	case !metav1.IsControlledBy(job, &task):
+		err := errors.Errorf(errFmtNotControlled, job.GetName())
+		r.record.Event(eventObj, event.Warning(errGetJob, err))
+		return util.ReconcileWaitResult,
+			util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileError(err))
+	case job.GetAnnotations()[generationAnnotation] != strconv.FormatInt(task.GetGeneration(), 10):
+		// the job runs an outdated task, the task is run again once it is gone
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Failed to delete the outdated job")
+			r.record.Event(eventObj, event.Warning(errDeleteJob, err))
+			return util.ReconcileWaitResult,
+				util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileError(errors.Wrap(err, errDeleteJob)))
+		}
+		r.record.Event(eventObj, event.Normal("Job deleted",
+			fmt.Sprintf("Workload `%s` deleted the outdated job `%s`", task.Name, job.Name)))
+		return util.ReconcileWaitResult, util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileSuccess())
+	}
+
+	task.SetConditions(observeJob(&task, job))
+	task.Status.Resources = []cpv1alpha1.TypedReference{{
+		APIVersion: jobAPIVersion,
+		Kind:       jobKind,
+		Name:       job.GetName(),
+		UID:        job.GetUID(),
+	}}
+	for _, claim := range claims {
+		task.Status.Resources = append(task.Status.Resources, cpv1alpha1.TypedReference{
+			APIVersion: claim.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:       claim.GetObjectKind().GroupVersionKind().Kind,
+			Name:       claim.GetName(),
+			UID:        claim.GetUID(),
+		})
+	}
+	if err := r.Status().Update(ctx, &task); err != nil {
+		return util.ReconcileWaitResult, err
+	}
+	return ctrl.Result{}, util.PatchCondition(ctx, r, &task, cpv1alpha1.ReconcileSuccess())
+}
+
+// SetupWithManager setups up k8s controller.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	name := "oam/" + strings.ToLower(v1alpha2.TaskWorkloadKind)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.TaskWorkload{}).
+		Owns(&batchv1.Job{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Complete(r)
+}
+
+// renderJob renders the job that runs the containers of the supplied task.
+func renderJob(task *v1alpha2.TaskWorkload) *batchv1.Job {
+	ps := containerizedworkload.PodSpec(task.GetName(), task.Spec.ContainerizedWorkloadSpec)
+	// failed pods are replaced rather than restarted, up to the backoff limit
+	ps.RestartPolicy = corev1.RestartPolicyNever
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       jobKind,
+			APIVersion: jobAPIVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      task.GetName(),
+			Namespace: task.GetNamespace(),
+		},
+		Spec: batchv1.JobSpec{
+			Completions:             task.Spec.Completions,
+			Parallelism:             task.Spec.Parallelism,
+			BackoffLimit:            task.Spec.BackoffLimit,
+			ActiveDeadlineSeconds:   task.Spec.ActiveDeadlineSeconds,
+			TTLSecondsAfterFinished: task.Spec.TTLSecondsAfterFinished,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						labelKey: string(task.GetUID()),
+					},
+				},
+				Spec: ps,
+			},
+		},
+	}
+	// pass through label and annotation from the task to the job and its pods
+	util.PassLabelAndAnnotation(task, job)
+	util.PassLabelAndAnnotation(task, &job.Spec.Template)
+	job.SetAnnotations(util.MergeMap(job.GetAnnotations(),
+		map[string]string{generationAnnotation: strconv.FormatInt(task.GetGeneration(), 10)}))
+	return job
+}
+
+// observeJob records the status of the supplied job in the status of the
+// supplied task, and returns the availability condition of the task: Available
+// once the job completed, Unavailable if it failed, and Creating while it runs.
+func observeJob(task *v1alpha2.TaskWorkload, job *batchv1.Job) cpv1alpha1.Condition {
+	task.Status.ObservedGeneration = task.GetGeneration()
+	task.Status.Active = job.Status.Active
+	task.Status.Succeeded = job.Status.Succeeded
+	task.Status.Failed = job.Status.Failed
+	task.Status.StartTime = job.Status.StartTime
+	task.Status.CompletionTime = job.Status.CompletionTime
+
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return cpv1alpha1.Available()
+		case batchv1.JobFailed:
+			return cpv1alpha1.Unavailable().WithMessage(fmt.Sprintf(msgFmtFailed, c.Reason, c.Message))
+		}
+	}
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	return cpv1alpha1.Creating().WithMessage(fmt.Sprintf(msgFmtRunning, job.Status.Succeeded, completions))
+}
+
+// finished returns true if the job of the current generation of the supplied
+// task completed or failed.
+func finished(task *v1alpha2.TaskWorkload) bool {
+	if task.Status.ObservedGeneration != task.GetGeneration() {
+		return false
+	}
+	switch task.GetCondition(cpv1alpha1.TypeReady).Reason {
+	case cpv1alpha1.ReasonAvailable, cpv1alpha1.ReasonUnavailable:
+		return true
+	default:
+		return false
+	}
+}
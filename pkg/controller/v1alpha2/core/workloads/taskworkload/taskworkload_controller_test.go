@@ -0,0 +1,283 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package taskworkload
+
+import (
+	"context"
+	"testing"
+
+	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	taskName      = "migrate"
+	taskNamespace = "ns"
+	taskUID       = "task-uid"
+)
+
+type taskModifier func(*v1alpha2.TaskWorkload)
+
+func taskWithGeneration(g int64) taskModifier {
+	return func(t *v1alpha2.TaskWorkload) { t.SetGeneration(g) }
+}
+
+func taskWithStatus(observed int64, c cpv1alpha1.Condition) taskModifier {
+	return func(t *v1alpha2.TaskWorkload) {
+		t.Status.ObservedGeneration = observed
+		t.SetConditions(c)
+	}
+}
+
+func task(mod ...taskModifier) *v1alpha2.TaskWorkload {
+	t := &v1alpha2.TaskWorkload{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       taskName,
+			Namespace:  taskNamespace,
+			UID:        types.UID(taskUID),
+			Generation: 1,
+			Labels:     map[string]string{"app": "db"},
+		},
+		Spec: v1alpha2.TaskWorkloadSpec{
+			ContainerizedWorkloadSpec: v1alpha2.ContainerizedWorkloadSpec{
+				Containers: []v1alpha2.Container{{Name: "migrate", Image: "cool/migrate:latest"}},
+			},
+		},
+	}
+	for _, m := range mod {
+		m(t)
+	}
+	return t
+}
+
+func TestRenderJob(t *testing.T) {
+	two := int32(2)
+	ttl := int32(60)
+
+	tw := task()
+	tw.Spec.Completions = &two
+	tw.Spec.TTLSecondsAfterFinished = &ttl
+
+	want := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{Kind: jobKind, APIVersion: jobAPIVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        taskName,
+			Namespace:   taskNamespace,
+			Labels:      map[string]string{"app": "db"},
+			Annotations: map[string]string{generationAnnotation: "1"},
+		},
+		Spec: batchv1.JobSpec{
+			Completions:             &two,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{labelKey: taskUID, "app": "db"},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    []corev1.Container{{Name: "migrate", Image: "cool/migrate:latest"}},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, renderJob(tw)); diff != "" {
+		t.Errorf("renderJob(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestObserveJob(t *testing.T) {
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		reason string
+		job    *batchv1.Job
+		want   cpv1alpha1.Condition
+	}{
+		"Running": {
+			reason: "A task should be creating while its job runs",
+			job:    &batchv1.Job{Status: batchv1.JobStatus{Active: 1, StartTime: &now}},
+			want:   cpv1alpha1.Creating().WithMessage("0/1 pods succeeded"),
+		},
+		"Complete": {
+			reason: "A task should be available once its job completed",
+			job: &batchv1.Job{Status: batchv1.JobStatus{
+				Succeeded:      1,
+				CompletionTime: &now,
+				Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+			}},
+			want: cpv1alpha1.Available(),
+		},
+		"Failed": {
+			reason: "A task should be unavailable with the reason its job failed",
+			job: &batchv1.Job{Status: batchv1.JobStatus{
+				Failed: 7,
+				Conditions: []batchv1.JobCondition{{
+					Type:    batchv1.JobFailed,
+					Status:  corev1.ConditionTrue,
+					Reason:  "BackoffLimitExceeded",
+					Message: "Job has reached the specified backoff limit",
+				}},
+			}},
+			want: cpv1alpha1.Unavailable().WithMessage("BackoffLimitExceeded: Job has reached the specified backoff limit"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tw := task()
+			got := observeJob(tw, tc.job)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\nReason: %s\nobserveJob(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			want := v1alpha2.TaskWorkloadStatus{
+				ObservedGeneration: 1,
+				Active:             tc.job.Status.Active,
+				Succeeded:          tc.job.Status.Succeeded,
+				Failed:             tc.job.Status.Failed,
+				StartTime:          tc.job.Status.StartTime,
+				CompletionTime:     tc.job.Status.CompletionTime,
+			}
+			if diff := cmp.Diff(want, tw.Status); diff != "" {
+				t.Errorf("\nReason: %s\nobserveJob(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = core.AddToScheme(scheme)
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	controlled := func(generation string) *batchv1.Job {
+		j := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:        taskName,
+			Namespace:   taskNamespace,
+			Annotations: map[string]string{generationAnnotation: generation},
+		}}
+		j.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(task(), v1alpha2.TaskWorkloadGroupVersionKind)})
+		return j
+	}
+	getFn := func(tw *v1alpha2.TaskWorkload, job *batchv1.Job) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.TaskWorkload:
+				tw.DeepCopyInto(o)
+			case *batchv1.Job:
+				if job == nil {
+					return notFound
+				}
+				job.DeepCopyInto(o)
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		result  ctrl.Result
+		err     error
+		created bool
+		deleted bool
+	}
+	cases := map[string]struct {
+		reason string
+		task   *v1alpha2.TaskWorkload
+		job    *batchv1.Job
+		want   want
+	}{
+		"CreateJob": {
+			reason: "A job should be created for a task that has none",
+			task:   task(),
+			want:   want{created: true},
+		},
+		"ObserveJob": {
+			reason: "The job of the current generation of a task should only be observed",
+			task:   task(),
+			job:    controlled("1"),
+		},
+		"FinishedJobDeleted": {
+			reason: "A job that was deleted after it finished should not be created again",
+			task:   task(taskWithStatus(1, cpv1alpha1.Available())),
+		},
+		"OutdatedJob": {
+			reason: "The job of an outdated generation of a task should be deleted",
+			task:   task(taskWithGeneration(2), taskWithStatus(1, cpv1alpha1.Available())),
+			job:    controlled("1"),
+			want:   want{result: util.ReconcileWaitResult, deleted: true},
+		},
+		"NotControlled": {
+			reason: "A job that is not controlled by the task should be neither observed nor deleted",
+			task:   task(),
+			job:    &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: taskName, Namespace: taskNamespace}},
+			want:   want{result: util.ReconcileWaitResult},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var created, deleted bool
+			r := Reconciler{
+				Client: &test.MockClient{
+					MockGet: getFn(tc.task, tc.job),
+					MockCreate: func(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+						created = true
+						return nil
+					},
+					MockDelete: func(_ context.Context, obj runtime.Object, _ ...client.DeleteOption) error {
+						deleted = true
+						return nil
+					},
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+					MockStatusPatch:  test.NewMockStatusPatchFn(nil),
+				},
+				log:    ctrl.Log.WithName("TaskWorkload"),
+				record: event.NewNopRecorder(),
+				Scheme: scheme,
+			}
+			result, err := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: taskNamespace, Name: taskName}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, result); diff != "" {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if created != tc.want.created {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): created a job: want %t, got %t", tc.reason, tc.want.created, created)
+			}
+			if deleted != tc.want.deleted {
+				t.Errorf("\nReason: %s\nr.Reconcile(...): deleted a job: want %t, got %t", tc.reason, tc.want.deleted, deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definitionregistration generates WorkloadDefinitions from the
+// CustomResourceDefinitions that opt in to it, so that new workload kinds can
+// be used by Components without writing their definition by hand.
+package definitionregistration
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetCRD                     = "cannot get CustomResourceDefinition"
+	errFmtGetDefinition           = "cannot get WorkloadDefinition %q"
+	errFmtCreateDefinition        = "cannot create WorkloadDefinition %q"
+	errFmtUpdateDefinition        = "cannot update WorkloadDefinition %q"
+	errFmtDeleteDefinition        = "cannot delete WorkloadDefinition %q"
+	errFmtParseChildResourceKinds = "cannot parse annotation %q of CustomResourceDefinition %q"
+)
+
+// Setup adds a controller that generates WorkloadDefinitions from the
+// CustomResourceDefinitions labeled oam.dev/workload=true.
+func Setup(mgr ctrl.Manager, _ controller.Args, l logging.Logger) error {
+	name := "oam/" + strings.ToLower(v1alpha2.WorkloadDefinitionKind) + "-registration"
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&crdv1.CustomResourceDefinition{}).
+		Owns(&v1alpha2.WorkloadDefinition{}).
+		Complete(NewReconciler(mgr.GetClient(), WithLogger(l.WithValues("controller", name))))
+}
+
+// A Reconciler generates the WorkloadDefinition of a CustomResourceDefinition
+// that is labeled oam.dev/workload=true. The WorkloadDefinition is named after
+// the CRD, refers to it, and is controlled by it, so that it is deleted with
+// it. Its childResourceKinds are those of the oam.dev/child-resource-kinds
+// annotation of the CRD. Other fields of generated WorkloadDefinitions may be
+// edited; they are preserved. WorkloadDefinitions that were not generated are
+// never modified.
+type Reconciler struct {
+	client client.Client
+	log    logging.Logger
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// NewReconciler returns a Reconciler that generates WorkloadDefinitions
+// through the supplied client.
+func NewReconciler(c client.Client, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: c,
+		log:    logging.NewNopLogger(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile the WorkloadDefinition of a CustomResourceDefinition.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	crd := &crdv1.CustomResourceDefinition{}
+	if err := r.client.Get(ctx, req.NamespacedName, crd); err != nil {
+		// the definitions of deleted CRDs are garbage collected
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetCRD)
+	}
+	log := r.log.WithValues("crd", crd.GetName())
+
+	wd := &v1alpha2.WorkloadDefinition{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: crd.GetName()}, wd)
+	if client.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtGetDefinition, crd.GetName())
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(wd, crd) {
+		log.Debug("Skipping WorkloadDefinition that was not generated from the CustomResourceDefinition")
+		return reconcile.Result{}, nil
+	}
+
+	if crd.GetLabels()[oam.LabelWorkloadDefinition] != "true" {
+		if !exists {
+			return reconcile.Result{}, nil
+		}
+		log.Debug("Deleting WorkloadDefinition of CustomResourceDefinition that opted out")
+		return reconcile.Result{}, errors.Wrapf(client.IgnoreNotFound(r.client.Delete(ctx, wd)), errFmtDeleteDefinition, wd.GetName())
+	}
+
+	kinds, err := childResourceKinds(crd)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	ref := v1alpha2.DefinitionReference{Name: crd.GetName()}
+
+	if !exists {
+		wd = &v1alpha2.WorkloadDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: crd.GetName()},
+			Spec:       v1alpha2.WorkloadDefinitionSpec{Reference: ref, ChildResourceKinds: kinds},
+		}
+		or := meta.AsController(meta.ReferenceTo(crd, crdv1.SchemeGroupVersion.WithKind("CustomResourceDefinition")))
+		meta.AddOwnerReference(wd, or)
+		log.Debug("Creating WorkloadDefinition")
+		return reconcile.Result{}, errors.Wrapf(r.client.Create(ctx, wd), errFmtCreateDefinition, wd.GetName())
+	}
+
+	if reflect.DeepEqual(wd.Spec.Reference, ref) && reflect.DeepEqual(wd.Spec.ChildResourceKinds, kinds) {
+		return reconcile.Result{}, nil
+	}
+	wd.Spec.Reference = ref
+	wd.Spec.ChildResourceKinds = kinds
+	log.Debug("Updating WorkloadDefinition")
+	return reconcile.Result{}, errors.Wrapf(r.client.Update(ctx, wd), errFmtUpdateDefinition, wd.GetName())
+}
+
+// childResourceKinds returns the childResourceKinds of the
+// oam.dev/child-resource-kinds annotation of the supplied CRD, or nil if it
+// has none.
+func childResourceKinds(crd *crdv1.CustomResourceDefinition) ([]v1alpha2.ChildResourceKind, error) {
+	a, ok := crd.GetAnnotations()[oam.AnnotationChildResourceKinds]
+	if !ok {
+		return nil, nil
+	}
+	var kinds []v1alpha2.ChildResourceKind
+	if err := json.Unmarshal([]byte(a), &kinds); err != nil {
+		return nil, errors.Wrapf(err, errFmtParseChildResourceKinds, oam.AnnotationChildResourceKinds, crd.GetName())
+	}
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+	return kinds, nil
+}
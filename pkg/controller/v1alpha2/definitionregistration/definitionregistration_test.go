@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definitionregistration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	errUnexpected := errors.New("unexpected object")
+	crdName := "webservices.example.com"
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: crdName}}
+	uid := types.UID("crd-uid")
+
+	crd := func(labels, annotations map[string]string) *crdv1.CustomResourceDefinition {
+		return &crdv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{
+			Name: crdName, UID: uid, Labels: labels, Annotations: annotations,
+		}}
+	}
+	labeled := map[string]string{oam.LabelWorkloadDefinition: "true"}
+	deployments := map[string]string{oam.AnnotationChildResourceKinds: `[{"apiVersion":"apps/v1","kind":"Deployment"}]`}
+	controller := metav1.OwnerReference{
+		APIVersion: crdv1.SchemeGroupVersion.String(),
+		Kind:       "CustomResourceDefinition",
+		Name:       crdName,
+		UID:        uid,
+		Controller: pointer.BoolPtr(true),
+	}
+	wd := func(owners []metav1.OwnerReference, kinds ...v1alpha2.ChildResourceKind) *v1alpha2.WorkloadDefinition {
+		return &v1alpha2.WorkloadDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: crdName, OwnerReferences: owners},
+			Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference:          v1alpha2.DefinitionReference{Name: crdName},
+				ChildResourceKinds: kinds,
+				PodSpecPath:        "spec.template.spec",
+			},
+		}
+	}
+	deployment := v1alpha2.ChildResourceKind{APIVersion: "apps/v1", Kind: "Deployment"}
+	get := func(c *crdv1.CustomResourceDefinition, d *v1alpha2.WorkloadDefinition) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *crdv1.CustomResourceDefinition:
+				c.DeepCopyInto(o)
+			case *v1alpha2.WorkloadDefinition:
+				if d == nil {
+					return kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "workloaddefinitions"}, crdName)
+				}
+				d.DeepCopyInto(o)
+			}
+			return nil
+		}
+	}
+	expect := func(want runtime.Object) test.ObjectFn {
+		return func(obj runtime.Object) error {
+			if diff := cmp.Diff(want, obj); diff != "" {
+				t.Errorf("-want, +got:\n%s", diff)
+				return errUnexpected
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		want   error
+	}{
+		"CRDNotFound": {
+			reason: "The definitions of deleted CRDs are garbage collected",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(
+				kerrors.NewNotFound(schema.GroupResource{Resource: "customresourcedefinitions"}, crdName))},
+		},
+		"GetCRDError": {
+			reason: "Errors getting the CRD should be returned",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   errors.Wrap(errBoom, errGetCRD),
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition should be returned",
+			c: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				if c, ok := obj.(*crdv1.CustomResourceDefinition); ok {
+					crd(labeled, nil).DeepCopyInto(c)
+					return nil
+				}
+				return errBoom
+			}},
+			want: errors.Wrapf(errBoom, errFmtGetDefinition, crdName),
+		},
+		"NotGenerated": {
+			reason: "WorkloadDefinitions that were not generated from the CRD should not be modified",
+			c:      &test.MockClient{MockGet: get(crd(labeled, deployments), wd(nil))},
+		},
+		"NotLabeled": {
+			reason: "No WorkloadDefinition should be generated from CRDs that did not opt in",
+			c:      &test.MockClient{MockGet: get(crd(nil, deployments), nil)},
+		},
+		"OptedOut": {
+			reason: "The WorkloadDefinition of a CRD that is no longer labeled should be deleted",
+			c: &test.MockClient{
+				MockGet:    get(crd(map[string]string{oam.LabelWorkloadDefinition: "false"}, nil), wd([]metav1.OwnerReference{controller})),
+				MockDelete: test.NewMockDeleteFn(nil, expect(wd([]metav1.OwnerReference{controller}))),
+			},
+		},
+		"InvalidAnnotation": {
+			reason: "Errors parsing the childResourceKinds annotation should be returned",
+			c:      &test.MockClient{MockGet: get(crd(labeled, map[string]string{oam.AnnotationChildResourceKinds: "deployments"}), nil)},
+			want: errors.Wrapf(errors.New("invalid character 'd' looking for beginning of value"),
+				errFmtParseChildResourceKinds, oam.AnnotationChildResourceKinds, crdName),
+		},
+		"Create": {
+			reason: "The WorkloadDefinition of a labeled CRD should be created, controlled by the CRD",
+			c: &test.MockClient{
+				MockGet: get(crd(labeled, deployments), nil),
+				MockCreate: test.NewMockCreateFn(nil, expect(&v1alpha2.WorkloadDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: crdName, OwnerReferences: []metav1.OwnerReference{controller}},
+					Spec: v1alpha2.WorkloadDefinitionSpec{
+						Reference:          v1alpha2.DefinitionReference{Name: crdName},
+						ChildResourceKinds: []v1alpha2.ChildResourceKind{deployment},
+					},
+				})),
+			},
+		},
+		"CreateError": {
+			reason: "Errors creating the WorkloadDefinition should be returned",
+			c: &test.MockClient{
+				MockGet:    get(crd(labeled, nil), nil),
+				MockCreate: test.NewMockCreateFn(errBoom),
+			},
+			want: errors.Wrapf(errBoom, errFmtCreateDefinition, crdName),
+		},
+		"UpToDate": {
+			reason: "WorkloadDefinitions that are up to date should not be updated",
+			c:      &test.MockClient{MockGet: get(crd(labeled, deployments), wd([]metav1.OwnerReference{controller}, deployment))},
+		},
+		"Update": {
+			reason: "The childResourceKinds of the WorkloadDefinition should be updated, preserving its other fields",
+			c: &test.MockClient{
+				MockGet:    get(crd(labeled, deployments), wd([]metav1.OwnerReference{controller})),
+				MockUpdate: test.NewMockUpdateFn(nil, expect(wd([]metav1.OwnerReference{controller}, deployment))),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.c)
+			got, err := r.Reconcile(req)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(reconcile.Result{}, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definitionrevision snapshots the specs of WorkloadDefinitions and
+// TraitDefinitions into DefinitionRevisions whenever they change, so that
+// ApplicationConfigurations can record the revisions of the definitions they
+// were rendered against.
+package definitionrevision
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errFmtGetDefinition  = "cannot get %s"
+	errFmtListRevisions  = "cannot list the revisions of %s %q"
+	errFmtCreateRevision = "cannot create revision %q"
+	errFmtDeleteRevision = "cannot delete revision %q"
+	errFmtUpdateStatus   = "cannot update the status of %s %q"
+	errFmtUnexpectedKind = "unexpected definition kind %q"
+)
+
+// Setup adds controllers that snapshot WorkloadDefinitions and
+// TraitDefinitions into DefinitionRevisions.
+func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
+	for _, k := range []struct {
+		kind string
+		def  resource.Object
+	}{
+		{v1alpha2.WorkloadDefinitionKind, &v1alpha2.WorkloadDefinition{}},
+		{v1alpha2.TraitDefinitionKind, &v1alpha2.TraitDefinition{}},
+	} {
+		name := "oam/" + strings.ToLower(k.kind) + "-revision"
+		if err := ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			For(k.def).
+			Owns(&v1alpha2.DefinitionRevision{}).
+			Complete(NewReconciler(mgr.GetClient(), k.kind,
+				WithLogger(l.WithValues("controller", name)),
+				WithRevisionLimit(args.RevisionLimit))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A Reconciler snapshots the spec of a WorkloadDefinition or TraitDefinition
+// into a new DefinitionRevision whenever it changes, and records the latest
+// revision in its status.
+type Reconciler struct {
+	client        client.Client
+	kind          string
+	revisionLimit int
+	log           logging.Logger
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithRevisionLimit specifies the maximum number of revisions of a definition
+// the Reconciler retains. The oldest ones are deleted first. All of them are
+// retained if it is not positive.
+func WithRevisionLimit(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.revisionLimit = n
+	}
+}
+
+// NewReconciler returns a Reconciler of the definitions of the supplied kind,
+// i.e. WorkloadDefinition or TraitDefinition.
+func NewReconciler(c client.Client, kind string, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: c,
+		kind:   kind,
+		log:    logging.NewNopLogger(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile the revisions of a definition.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	var (
+		def      resource.Object
+		status   *v1alpha2.DefinitionStatus
+		spec     func() interface{}
+		snapshot func(*v1alpha2.DefinitionRevisionSpec)
+	)
+	switch r.kind {
+	case v1alpha2.WorkloadDefinitionKind:
+		wd := &v1alpha2.WorkloadDefinition{}
+		def, status = wd, &wd.Status
+		spec = func() interface{} { return wd.Spec }
+		snapshot = func(s *v1alpha2.DefinitionRevisionSpec) { s.WorkloadDefinition = wd.Spec.DeepCopy() }
+	case v1alpha2.TraitDefinitionKind:
+		td := &v1alpha2.TraitDefinition{}
+		def, status = td, &td.Status
+		spec = func() interface{} { return td.Spec }
+		snapshot = func(s *v1alpha2.DefinitionRevisionSpec) { s.TraitDefinition = td.Spec.DeepCopy() }
+	default:
+		return reconcile.Result{}, errors.Errorf(errFmtUnexpectedKind, r.kind)
+	}
+	if err := r.client.Get(ctx, req.NamespacedName, def); err != nil {
+		// the revisions of deleted definitions are garbage collected
+		return reconcile.Result{}, errors.Wrapf(client.IgnoreNotFound(err), errFmtGetDefinition, r.kind)
+	}
+
+	l := &v1alpha2.DefinitionRevisionList{}
+	if err := r.client.List(ctx, l, client.MatchingLabels{
+		oam.LabelDefinitionKind: r.kind,
+		oam.LabelDefinitionName: req.Name,
+	}); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtListRevisions, r.kind, req.Name)
+	}
+	revs := l.Items
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Spec.Revision < revs[j].Spec.Revision })
+
+	hash := util.ComputeDefinitionRevisionHash(spec())
+	if len(revs) == 0 || revs[len(revs)-1].Spec.RevisionHash != hash {
+		next := int64(1)
+		if len(revs) > 0 {
+			next = revs[len(revs)-1].Spec.Revision + 1
+		}
+		rev := v1alpha2.DefinitionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: util.DefinitionRevisionName(r.kind, req.Name, next),
+				Labels: map[string]string{
+					oam.LabelDefinitionKind: r.kind,
+					oam.LabelDefinitionName: req.Name,
+				},
+			},
+			Spec: v1alpha2.DefinitionRevisionSpec{
+				DefinitionKind: r.kind,
+				DefinitionName: req.Name,
+				Revision:       next,
+				RevisionHash:   hash,
+			},
+		}
+		snapshot(&rev.Spec)
+		meta.AddOwnerReference(&rev, meta.AsController(meta.ReferenceTo(def, v1alpha2.SchemeGroupVersion.WithKind(r.kind))))
+		r.log.Debug("Creating definition revision", "kind", r.kind, "name", req.Name, "revision", next)
+		if err := r.client.Create(ctx, &rev); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, errFmtCreateRevision, rev.GetName())
+		}
+		revs = append(revs, rev)
+	}
+
+	if r.revisionLimit > 0 && len(revs) > r.revisionLimit {
+		for i := range revs[:len(revs)-r.revisionLimit] {
+			if err := r.client.Delete(ctx, &revs[i]); client.IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, errors.Wrapf(err, errFmtDeleteRevision, revs[i].GetName())
+			}
+		}
+	}
+
+	latest := revs[len(revs)-1]
+	want := &v1alpha2.Revision{Name: latest.GetName(), Revision: latest.Spec.Revision}
+	if reflect.DeepEqual(status.LatestRevision, want) {
+		return reconcile.Result{}, nil
+	}
+	status.LatestRevision = want
+	return reconcile.Result{}, errors.Wrapf(r.client.Status().Update(ctx, def), errFmtUpdateStatus, r.kind, req.Name)
+}
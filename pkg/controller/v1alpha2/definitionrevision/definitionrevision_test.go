@@ -0,0 +1,254 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definitionrevision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "scaler"}}
+
+	spec := v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "scalers.example.com"}}
+	hash := util.ComputeDefinitionRevisionHash(spec)
+	latest := func(revision int64) *v1alpha2.Revision {
+		return &v1alpha2.Revision{Name: util.DefinitionRevisionName(v1alpha2.TraitDefinitionKind, "scaler", revision), Revision: revision}
+	}
+	getTraitDefinition := func(s v1alpha2.DefinitionStatus) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			td := obj.(*v1alpha2.TraitDefinition)
+			td.SetName("scaler")
+			td.SetUID("td-uid")
+			td.Spec = spec
+			td.Status = s
+			return nil
+		}
+	}
+	revision := func(revision int64, hash string) v1alpha2.DefinitionRevision {
+		return v1alpha2.DefinitionRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: util.DefinitionRevisionName(v1alpha2.TraitDefinitionKind, "scaler", revision),
+				Labels: map[string]string{
+					oam.LabelDefinitionKind: v1alpha2.TraitDefinitionKind,
+					oam.LabelDefinitionName: "scaler",
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: v1alpha2.SchemeGroupVersion.String(),
+					Kind:       v1alpha2.TraitDefinitionKind,
+					Name:       "scaler",
+					UID:        "td-uid",
+					Controller: pointer.BoolPtr(true),
+				}},
+			},
+			Spec: v1alpha2.DefinitionRevisionSpec{
+				DefinitionKind:  v1alpha2.TraitDefinitionKind,
+				DefinitionName:  "scaler",
+				Revision:        revision,
+				RevisionHash:    hash,
+				TraitDefinition: spec.DeepCopy(),
+			},
+		}
+	}
+	listRevisions := func(revs ...v1alpha2.DefinitionRevision) test.MockListFn {
+		return func(_ context.Context, list runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			want := oam.LabelDefinitionKind + "=" + v1alpha2.TraitDefinitionKind + "," + oam.LabelDefinitionName + "=scaler"
+			if lo.LabelSelector.String() != want {
+				return errors.Errorf("unexpected label selector %q", lo.LabelSelector)
+			}
+			list.(*v1alpha2.DefinitionRevisionList).Items = revs
+			return nil
+		}
+	}
+
+	type args struct {
+		c client.Client
+		o []ReconcilerOption
+	}
+	type want struct {
+		err     error
+		created *v1alpha2.DefinitionRevision
+		deleted []string
+		status  *v1alpha2.DefinitionStatus
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DefinitionNotFound": {
+			reason: "The revisions of deleted definitions are garbage collected",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "scaler"))},
+			},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the definition should be returned",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetDefinition, v1alpha2.TraitDefinitionKind)},
+		},
+		"ListRevisionsError": {
+			reason: "Errors listing the revisions of the definition should be returned",
+			args: args{
+				c: &test.MockClient{
+					MockGet:  getTraitDefinition(v1alpha2.DefinitionStatus{}),
+					MockList: test.NewMockListFn(errBoom),
+				},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtListRevisions, v1alpha2.TraitDefinitionKind, "scaler")},
+		},
+		"FirstRevision": {
+			reason: "The first revision of a definition should be created and recorded in its status",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          getTraitDefinition(v1alpha2.DefinitionStatus{UsageCount: 1}),
+					MockList:         listRevisions(),
+					MockCreate:       test.NewMockCreateFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+			},
+			want: want{
+				created: func() *v1alpha2.DefinitionRevision { r := revision(1, hash); return &r }(),
+				status:  &v1alpha2.DefinitionStatus{UsageCount: 1, LatestRevision: latest(1)},
+			},
+		},
+		"CreateRevisionError": {
+			reason: "Errors creating a revision should be returned",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    getTraitDefinition(v1alpha2.DefinitionStatus{}),
+					MockList:   listRevisions(),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtCreateRevision, latest(1).Name)},
+		},
+		"Unchanged": {
+			reason: "No revision should be created for a definition whose spec did not change",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          getTraitDefinition(v1alpha2.DefinitionStatus{LatestRevision: latest(1)}),
+					MockList:         listRevisions(revision(1, hash)),
+					MockCreate:       test.NewMockCreateFn(errBoom),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+				},
+			},
+		},
+		"Changed": {
+			reason: "A new revision should be created for a definition whose spec changed",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          getTraitDefinition(v1alpha2.DefinitionStatus{LatestRevision: latest(2)}),
+					MockList:         listRevisions(revision(2, "old"), revision(1, "older")),
+					MockCreate:       test.NewMockCreateFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+			},
+			want: want{
+				created: func() *v1alpha2.DefinitionRevision { r := revision(3, hash); return &r }(),
+				status:  &v1alpha2.DefinitionStatus{LatestRevision: latest(3)},
+			},
+		},
+		"RevisionLimit": {
+			reason: "The oldest revisions beyond the revision limit should be deleted",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          getTraitDefinition(v1alpha2.DefinitionStatus{LatestRevision: latest(2)}),
+					MockList:         listRevisions(revision(1, "older"), revision(2, "old")),
+					MockCreate:       test.NewMockCreateFn(nil),
+					MockDelete:       test.NewMockDeleteFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				o: []ReconcilerOption{WithRevisionLimit(2)},
+			},
+			want: want{
+				created: func() *v1alpha2.DefinitionRevision { r := revision(3, hash); return &r }(),
+				deleted: []string{latest(1).Name},
+				status:  &v1alpha2.DefinitionStatus{LatestRevision: latest(3)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var (
+				created *v1alpha2.DefinitionRevision
+				deleted []string
+				status  *v1alpha2.DefinitionStatus
+			)
+			mc := tc.args.c.(*test.MockClient)
+			if create := mc.MockCreate; create != nil {
+				mc.MockCreate = func(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+					if err := create(ctx, obj, opts...); err != nil {
+						return err
+					}
+					created = obj.(*v1alpha2.DefinitionRevision).DeepCopy()
+					return nil
+				}
+			}
+			if del := mc.MockDelete; del != nil {
+				mc.MockDelete = func(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+					deleted = append(deleted, obj.(*v1alpha2.DefinitionRevision).GetName())
+					return del(ctx, obj, opts...)
+				}
+			}
+			if update := mc.MockStatusUpdate; update != nil {
+				mc.MockStatusUpdate = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					status = obj.(*v1alpha2.TraitDefinition).Status.DeepCopy()
+					return update(ctx, obj, opts...)
+				}
+			}
+
+			r := NewReconciler(tc.args.c, v1alpha2.TraitDefinitionKind, tc.args.o...)
+			_, err := r.Reconcile(req)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, created); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want created revision, +got created revision:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want deleted revisions, +got deleted revisions:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, status); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definitionusage records in the status of WorkloadDefinitions and
+// TraitDefinitions how many Components and ApplicationConfigurations use
+// them, so that platform operators know which definitions are safe to
+// deprecate.
+package definitionusage
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+
+	// DefaultMaxUsedBy is the default maximum number of users a definition
+	// lists in its status.
+	DefaultMaxUsedBy = 10
+)
+
+// Reconcile error strings.
+const (
+	errFmtGetDefinition  = "cannot get %s"
+	errFmtIndexKeys      = "cannot determine the resources of %s %q"
+	errFmtListUsers      = "cannot list the users of %s %q"
+	errFmtUpdateStatus   = "cannot update the status of %s %q"
+	errFmtUnexpectedKind = "unexpected definition kind %q"
+)
+
+// Setup adds controllers that record the usage of WorkloadDefinitions and
+// TraitDefinitions in their status.
+func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	defs := client.Reader(mgr.GetClient())
+	if args.DefinitionClient != nil {
+		defs = args.DefinitionClient
+	}
+	for _, k := range []struct {
+		kind  string
+		def   runtime.Object
+		users runtime.Object
+		toDef handler.ToRequestsFunc
+	}{
+		{v1alpha2.WorkloadDefinitionKind, &v1alpha2.WorkloadDefinition{}, &v1alpha2.Component{}, componentDefinitions(defs, dm)},
+		{v1alpha2.TraitDefinitionKind, &v1alpha2.TraitDefinition{}, &v1alpha2.ApplicationConfiguration{}, appConfigDefinitions(defs, dm)},
+	} {
+		name := "oam/" + strings.ToLower(k.kind) + "-usage"
+		if err := ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			For(k.def).
+			Watches(&source.Kind{Type: k.users}, &handler.EnqueueRequestsFromMapFunc{ToRequests: k.toDef}).
+			Complete(NewReconciler(mgr.GetClient(), defs, dm, k.kind,
+				WithLogger(l.WithValues("controller", name)))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// componentDefinitions returns a mapper of Components to requests for the
+// WorkloadDefinitions of their workloads. A Component that is updated maps to
+// the definitions it used before and after the update.
+func componentDefinitions(defs client.Reader, dm discoverymapper.DiscoveryMapper) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		comp, ok := o.Object.(*v1alpha2.Component)
+		if !ok {
+			return nil
+		}
+		raws := []runtime.RawExtension{comp.Spec.Workload}
+		for _, aw := range comp.Spec.AuxiliaryWorkloads {
+			raws = append(raws, aw.Workload)
+		}
+		return definitionRequests(defs, dm, v1alpha2.WorkloadDefinitionKind, raws)
+	}
+}
+
+// appConfigDefinitions returns a mapper of ApplicationConfigurations to
+// requests for the TraitDefinitions of their traits.
+func appConfigDefinitions(defs client.Reader, dm discoverymapper.DiscoveryMapper) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		ac, ok := o.Object.(*v1alpha2.ApplicationConfiguration)
+		if !ok {
+			return nil
+		}
+		var raws []runtime.RawExtension
+		for _, acc := range ac.Spec.Components {
+			for _, ct := range acc.Traits {
+				raws = append(raws, ct.Trait)
+			}
+		}
+		return definitionRequests(defs, dm, v1alpha2.TraitDefinitionKind, raws)
+	}
+}
+
+// definitionRequests returns a request for the definition of the supplied
+// kind of each of the supplied raw objects. Objects that can't be decoded or
+// resolved to a definition are skipped; their definitions have no users.
+func definitionRequests(defs client.Reader, dm discoverymapper.DiscoveryMapper, kind string, raws []runtime.RawExtension) []reconcile.Request {
+	seen := map[string]bool{}
+	var reqs []reconcile.Request
+	for _, raw := range raws {
+		u, err := decode(raw)
+		if err != nil || u.GetKind() == "" {
+			continue
+		}
+		name, err := util.ResolveDefinitionName(defs, dm, kind, u)
+		if err != nil || seen[name] {
+			continue
+		}
+		seen[name] = true
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return reqs
+}
+
+func decode(raw runtime.RawExtension) (*unstructured.Unstructured, error) {
+	if raw.Object != nil {
+		return util.Object2Unstructured(raw.Object)
+	}
+	u := &unstructured.Unstructured{}
+	err := json.Unmarshal(raw.Raw, &u.Object)
+	return u, err
+}
+
+// A Reconciler records the number of Components or ApplicationConfigurations
+// that use a WorkloadDefinition or TraitDefinition in its status.
+type Reconciler struct {
+	client    client.Client
+	defs      client.Reader
+	dm        discoverymapper.DiscoveryMapper
+	kind      string
+	maxUsedBy int
+	log       logging.Logger
+}
+
+// A ReconcilerOption configures a Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(l logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = l
+	}
+}
+
+// WithMaxUsedBy specifies the maximum number of users the Reconciler lists
+// in the status of a definition. Zero lists none of them; they are counted
+// nonetheless.
+func WithMaxUsedBy(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxUsedBy = n
+	}
+}
+
+// NewReconciler returns a Reconciler of the definitions of the supplied kind,
+// i.e. WorkloadDefinition or TraitDefinition. Definitions are updated through
+// the supplied client, and resolved to the resources they refer to through
+// the supplied definition reader, e.g. a definition cache.
+func NewReconciler(c client.Client, defs client.Reader, dm discoverymapper.DiscoveryMapper, kind string, o ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:    c,
+		defs:      defs,
+		dm:        dm,
+		kind:      kind,
+		maxUsedBy: DefaultMaxUsedBy,
+		log:       logging.NewNopLogger(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile the usage status of a definition.
+func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	var (
+		def     runtime.Object
+		status  *v1alpha2.DefinitionStatus
+		index   string
+		newList func() runtime.Object
+	)
+	switch r.kind {
+	case v1alpha2.WorkloadDefinitionKind:
+		wd := &v1alpha2.WorkloadDefinition{}
+		def, status, index = wd, &wd.Status, util.WorkloadDefinitionIndex
+		newList = func() runtime.Object { return &v1alpha2.ComponentList{} }
+	case v1alpha2.TraitDefinitionKind:
+		td := &v1alpha2.TraitDefinition{}
+		def, status, index = td, &td.Status, util.TraitDefinitionIndex
+		newList = func() runtime.Object { return &v1alpha2.ApplicationConfigurationList{} }
+	default:
+		return reconcile.Result{}, errors.Errorf(errFmtUnexpectedKind, r.kind)
+	}
+	if err := r.client.Get(ctx, req.NamespacedName, def); err != nil {
+		return reconcile.Result{}, errors.Wrapf(client.IgnoreNotFound(err), errFmtGetDefinition, r.kind)
+	}
+
+	keys, err := util.DefinitionIndexKeys(r.defs, r.dm, r.kind, req.Name, reference(def))
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtIndexKeys, r.kind, req.Name)
+	}
+	users, err := util.DefinitionUsers(ctx, r.client, newList, index, keys)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtListUsers, r.kind, req.Name)
+	}
+
+	// other fields of the status, e.g. the latest revision, are recorded by
+	// other controllers
+	usage := *status
+	usage.UsageCount, usage.UsedBy = int32(len(users)), nil
+	if len(users) > r.maxUsedBy {
+		users = users[:r.maxUsedBy]
+	}
+	if len(users) > 0 {
+		usage.UsedBy = users
+	}
+	if reflect.DeepEqual(*status, usage) {
+		return reconcile.Result{}, nil
+	}
+	*status = usage
+	r.log.Debug("Updating definition usage", "kind", r.kind, "name", req.Name, "usageCount", usage.UsageCount)
+	return reconcile.Result{}, errors.Wrapf(r.client.Status().Update(ctx, def), errFmtUpdateStatus, r.kind, req.Name)
+}
+
+// reference returns the name of the resource the supplied definition refers
+// to, e.g. deployments.apps.
+func reference(def runtime.Object) string {
+	switch d := def.(type) {
+	case *v1alpha2.WorkloadDefinition:
+		return d.Spec.Reference.Name
+	case *v1alpha2.TraitDefinition:
+		return d.Spec.Reference.Name
+	}
+	return ""
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definitionusage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "webservice"}}
+
+	// the definitions are not named after the resources they refer to, so
+	// they are indexed by their name only
+	getWorkloadDefinition := func(s v1alpha2.DefinitionStatus) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			wd := obj.(*v1alpha2.WorkloadDefinition)
+			wd.SetName("webservice")
+			wd.Spec.Reference.Name = "deployments.apps"
+			wd.Status = s
+			return nil
+		}
+	}
+	listComponents := func(names ...string) test.MockListFn {
+		return func(_ context.Context, list runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.FieldSelector.String() != util.WorkloadDefinitionIndex+"=webservice" {
+				return errors.Errorf("unexpected field selector %q", lo.FieldSelector)
+			}
+			l := list.(*v1alpha2.ComponentList)
+			for _, name := range names {
+				l.Items = append(l.Items, v1alpha2.Component{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}})
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		c    client.Client
+		kind string
+		o    []ReconcilerOption
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+		status *v1alpha2.DefinitionStatus
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DefinitionNotFound": {
+			reason: "Deleted definitions should not be requeued",
+			args: args{
+				c:    &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "webservice"))},
+				kind: v1alpha2.WorkloadDefinitionKind,
+			},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the definition should be returned",
+			args: args{
+				c:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				kind: v1alpha2.WorkloadDefinitionKind,
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetDefinition, v1alpha2.WorkloadDefinitionKind)},
+		},
+		"ListUsersError": {
+			reason: "Errors listing the users of the definition should be returned",
+			args: args{
+				c: &test.MockClient{
+					MockGet:  getWorkloadDefinition(v1alpha2.DefinitionStatus{}),
+					MockList: test.NewMockListFn(errBoom),
+				},
+				kind: v1alpha2.WorkloadDefinitionKind,
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtListUsers, v1alpha2.WorkloadDefinitionKind, "webservice")},
+		},
+		"UsageUpdated": {
+			reason: "All users of the definition should be counted, and the first of them listed",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          getWorkloadDefinition(v1alpha2.DefinitionStatus{}),
+					MockList:         listComponents("web", "api", "db"),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				kind: v1alpha2.WorkloadDefinitionKind,
+				o:    []ReconcilerOption{WithMaxUsedBy(2)},
+			},
+			want: want{status: &v1alpha2.DefinitionStatus{UsageCount: 3, UsedBy: []string{"ns/web", "ns/api"}}},
+		},
+		"LatestRevisionPreserved": {
+			reason: "Fields of the status that are recorded by other controllers should be preserved",
+			args: args{
+				c: &test.MockClient{
+					MockGet: getWorkloadDefinition(v1alpha2.DefinitionStatus{
+						LatestRevision: &v1alpha2.Revision{Name: "workloaddefinition-webservice-v1", Revision: 1},
+					}),
+					MockList:         listComponents("web"),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				kind: v1alpha2.WorkloadDefinitionKind,
+			},
+			want: want{status: &v1alpha2.DefinitionStatus{
+				UsageCount:     1,
+				UsedBy:         []string{"ns/web"},
+				LatestRevision: &v1alpha2.Revision{Name: "workloaddefinition-webservice-v1", Revision: 1},
+			}},
+		},
+		"UsageUnchanged": {
+			reason: "The status of a definition whose usage did not change should not be updated",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          getWorkloadDefinition(v1alpha2.DefinitionStatus{UsageCount: 1, UsedBy: []string{"ns/web"}}),
+					MockList:         listComponents("web"),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+				},
+				kind: v1alpha2.WorkloadDefinitionKind,
+			},
+		},
+		"UnusedTraitDefinition": {
+			reason: "A TraitDefinition that is no longer used should record that",
+			args: args{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+						td := obj.(*v1alpha2.TraitDefinition)
+						td.Spec.Reference.Name = "scalers.example.com"
+						td.Status = v1alpha2.DefinitionStatus{UsageCount: 1, UsedBy: []string{"ns/app"}}
+						return nil
+					},
+					MockList:         test.NewMockListFn(nil),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+				},
+				kind: v1alpha2.TraitDefinitionKind,
+			},
+			want: want{status: &v1alpha2.DefinitionStatus{}},
+		},
+		"UpdateStatusError": {
+			reason: "Errors updating the status of the definition should be returned",
+			args: args{
+				c: &test.MockClient{
+					MockGet:          getWorkloadDefinition(v1alpha2.DefinitionStatus{}),
+					MockList:         listComponents("web"),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+				},
+				kind: v1alpha2.WorkloadDefinitionKind,
+			},
+			want: want{
+				err:    errors.Wrapf(errBoom, errFmtUpdateStatus, v1alpha2.WorkloadDefinitionKind, "webservice"),
+				status: &v1alpha2.DefinitionStatus{UsageCount: 1, UsedBy: []string{"ns/web"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var status *v1alpha2.DefinitionStatus
+			if mc, ok := tc.args.c.(*test.MockClient); ok && mc.MockStatusUpdate != nil {
+				update := mc.MockStatusUpdate
+				mc.MockStatusUpdate = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					switch d := obj.(type) {
+					case *v1alpha2.WorkloadDefinition:
+						status = &d.Status
+					case *v1alpha2.TraitDefinition:
+						status = &d.Status
+					}
+					return update(ctx, obj, opts...)
+				}
+			}
+			r := NewReconciler(tc.args.c, tc.args.c, mock.NewMockDiscoveryMapper(), tc.args.kind, tc.args.o...)
+			result, err := r.Reconcile(req)
+			if diff := cmp.Diff(tc.want, want{result: result, err: err, status: status}, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComponentDefinitions(t *testing.T) {
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = mock.NewMockRESTMapping("deployments")
+
+	comp := &v1alpha2.Component{}
+	comp.Spec.Workload = runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)}
+	comp.Spec.AuxiliaryWorkloads = []v1alpha2.AuxiliaryWorkload{
+		{Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"labels":{"` + oam.WorkloadTypeLabel + `":"webservice"}}}`)}},
+		{Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)}},
+		{Workload: runtime.RawExtension{Raw: []byte(`{}`)}},
+	}
+
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "deployments.apps"}},
+		{NamespacedName: types.NamespacedName{Name: "webservice"}},
+	}
+	got := componentDefinitions(&test.MockClient{}, dm)(handler.MapObject{Object: comp})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("componentDefinitions(...): -want, +got:\n%s", diff)
+	}
+}
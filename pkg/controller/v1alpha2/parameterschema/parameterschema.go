@@ -0,0 +1,336 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package parameterschema publishes the JSON schemas of the resources of
+// WorkloadDefinitions and TraitDefinitions, and of the parameters of
+// Components, in their status, so that UIs and CLIs can offer form based
+// authoring and validation of them.
+package parameterschema
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reconcileTimeout = 1 * time.Minute
+)
+
+// Reconcile error strings.
+const (
+	errGetComponent       = "cannot get Component"
+	errDecodeWorkload     = "cannot decode the workload of Component"
+	errFmtGetDefinition   = "cannot get %s"
+	errFmtGetCRD          = "cannot get CustomResourceDefinition %q"
+	errFmtFetchDefinition = "cannot fetch the WorkloadDefinition of Component %q"
+	errFmtDecodeSchema    = "cannot decode the schema of WorkloadDefinition %q"
+	errFmtParameterSchema = "cannot derive the parameter schema of Component %q"
+	errFmtMarshalSchema   = "cannot marshal the schema of %s %q"
+	errFmtUpdateStatus    = "cannot update the status of %s %q"
+	errFmtUnexpectedKind  = "unexpected definition kind %q"
+)
+
+// Setup adds controllers that publish the schemas of WorkloadDefinitions,
+// TraitDefinitions and Components in their status.
+func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
+	dm, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	defs := client.Reader(mgr.GetClient())
+	if args.DefinitionClient != nil {
+		defs = args.DefinitionClient
+	}
+	for _, k := range []struct {
+		kind string
+		def  runtime.Object
+	}{
+		{v1alpha2.WorkloadDefinitionKind, &v1alpha2.WorkloadDefinition{}},
+		{v1alpha2.TraitDefinitionKind, &v1alpha2.TraitDefinition{}},
+	} {
+		name := "oam/" + strings.ToLower(k.kind) + "-schema"
+		if err := ctrl.NewControllerManagedBy(mgr).
+			Named(name).
+			For(k.def).
+			Watches(&source.Kind{Type: &crdv1.CustomResourceDefinition{}},
+				&handler.EnqueueRequestsFromMapFunc{ToRequests: crdDefinitions(mgr.GetClient(), k.kind)}).
+			Complete(NewDefinitionReconciler(mgr.GetClient(), k.kind,
+				WithDefinitionLogger(l.WithValues("controller", name)))); err != nil {
+			return err
+		}
+	}
+	name := "oam/" + strings.ToLower(v1alpha2.ComponentKind) + "-schema"
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha2.Component{}).
+		Watches(&source.Kind{Type: &v1alpha2.WorkloadDefinition{}},
+			&handler.EnqueueRequestsFromMapFunc{ToRequests: definitionComponents(mgr.GetClient(), defs, dm)}).
+		Complete(NewComponentReconciler(mgr.GetClient(), defs, dm,
+			WithComponentLogger(l.WithValues("controller", name))))
+}
+
+// crdDefinitions returns a mapper of CustomResourceDefinitions to requests
+// for the definitions of the supplied kind that refer to them.
+func crdDefinitions(c client.Reader, kind string) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		var refs []v1alpha2.DefinitionReference
+		var names []string
+		switch kind {
+		case v1alpha2.WorkloadDefinitionKind:
+			l := &v1alpha2.WorkloadDefinitionList{}
+			if err := c.List(context.Background(), l); err != nil {
+				return nil
+			}
+			for _, wd := range l.Items {
+				refs, names = append(refs, wd.Spec.Reference), append(names, wd.GetName())
+			}
+		case v1alpha2.TraitDefinitionKind:
+			l := &v1alpha2.TraitDefinitionList{}
+			if err := c.List(context.Background(), l); err != nil {
+				return nil
+			}
+			for _, td := range l.Items {
+				refs, names = append(refs, td.Spec.Reference), append(names, td.GetName())
+			}
+		}
+		var reqs []reconcile.Request
+		for i, ref := range refs {
+			if ref.Name == o.Meta.GetName() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: names[i]}})
+			}
+		}
+		return reqs
+	}
+}
+
+// definitionComponents returns a mapper of WorkloadDefinitions to requests
+// for the Components that use them.
+func definitionComponents(c client.Reader, defs client.Reader, dm discoverymapper.DiscoveryMapper) handler.ToRequestsFunc {
+	return func(o handler.MapObject) []reconcile.Request {
+		wd, ok := o.Object.(*v1alpha2.WorkloadDefinition)
+		if !ok {
+			return nil
+		}
+		keys, err := util.DefinitionIndexKeys(defs, dm, v1alpha2.WorkloadDefinitionKind, wd.GetName(), wd.Spec.Reference.Name)
+		if err != nil {
+			return nil
+		}
+		seen := map[types.NamespacedName]bool{}
+		var reqs []reconcile.Request
+		for _, key := range keys {
+			l := &v1alpha2.ComponentList{}
+			if err := c.List(context.Background(), l, client.MatchingFields{util.WorkloadDefinitionIndex: key}); err != nil {
+				return nil
+			}
+			for _, comp := range l.Items {
+				nn := types.NamespacedName{Namespace: comp.GetNamespace(), Name: comp.GetName()}
+				if !seen[nn] {
+					seen[nn] = true
+					reqs = append(reqs, reconcile.Request{NamespacedName: nn})
+				}
+			}
+		}
+		return reqs
+	}
+}
+
+// A DefinitionReconciler publishes the schema of the resources of a
+// WorkloadDefinition or TraitDefinition in its status. It is read from the
+// CustomResourceDefinition the definition refers to; definitions of built-in
+// resources, e.g. deployments.apps, have none.
+type DefinitionReconciler struct {
+	client client.Client
+	kind   string
+	log    logging.Logger
+}
+
+// A DefinitionReconcilerOption configures a DefinitionReconciler.
+type DefinitionReconcilerOption func(*DefinitionReconciler)
+
+// WithDefinitionLogger specifies how the DefinitionReconciler should log
+// messages.
+func WithDefinitionLogger(l logging.Logger) DefinitionReconcilerOption {
+	return func(r *DefinitionReconciler) {
+		r.log = l
+	}
+}
+
+// NewDefinitionReconciler returns a DefinitionReconciler of the definitions
+// of the supplied kind, i.e. WorkloadDefinition or TraitDefinition.
+func NewDefinitionReconciler(c client.Client, kind string, o ...DefinitionReconcilerOption) *DefinitionReconciler {
+	r := &DefinitionReconciler{
+		client: c,
+		kind:   kind,
+		log:    logging.NewNopLogger(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile the schema of a definition.
+func (r *DefinitionReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	var (
+		def    runtime.Object
+		status *v1alpha2.DefinitionStatus
+		ref    *v1alpha2.DefinitionReference
+	)
+	switch r.kind {
+	case v1alpha2.WorkloadDefinitionKind:
+		wd := &v1alpha2.WorkloadDefinition{}
+		def, status, ref = wd, &wd.Status, &wd.Spec.Reference
+	case v1alpha2.TraitDefinitionKind:
+		td := &v1alpha2.TraitDefinition{}
+		def, status, ref = td, &td.Status, &td.Spec.Reference
+	default:
+		return reconcile.Result{}, errors.Errorf(errFmtUnexpectedKind, r.kind)
+	}
+	if err := r.client.Get(ctx, req.NamespacedName, def); err != nil {
+		return reconcile.Result{}, errors.Wrapf(client.IgnoreNotFound(err), errFmtGetDefinition, r.kind)
+	}
+
+	var s *crdv1.JSONSchemaProps
+	crd := &crdv1.CustomResourceDefinition{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: ref.Name}, crd)
+	if client.IgnoreNotFound(err) != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtGetCRD, ref.Name)
+	}
+	if err == nil {
+		if s, err = ResourceSchema(crd, *ref); err != nil {
+			// the definition webhook rejects references to versions that
+			// are not served; the definition has no schema until it is fixed
+			r.log.Debug("Cannot read the schema of the definition", "kind", r.kind, "name", req.Name, "error", err)
+		}
+	}
+	raw, err := marshalSchema(s)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtMarshalSchema, r.kind, req.Name)
+	}
+	if equalSchemas(status.Schema, raw) {
+		return reconcile.Result{}, nil
+	}
+	status.Schema = raw
+	r.log.Debug("Updating definition schema", "kind", r.kind, "name", req.Name)
+	return reconcile.Result{}, errors.Wrapf(r.client.Status().Update(ctx, def), errFmtUpdateStatus, r.kind, req.Name)
+}
+
+// A ComponentReconciler publishes the schema of the parameters of a Component
+// in its status. It is derived from the declared parameters and from the
+// schema the WorkloadDefinition of the workload of the Component publishes.
+type ComponentReconciler struct {
+	client client.Client
+	defs   client.Reader
+	dm     discoverymapper.DiscoveryMapper
+	log    logging.Logger
+}
+
+// A ComponentReconcilerOption configures a ComponentReconciler.
+type ComponentReconcilerOption func(*ComponentReconciler)
+
+// WithComponentLogger specifies how the ComponentReconciler should log
+// messages.
+func WithComponentLogger(l logging.Logger) ComponentReconcilerOption {
+	return func(r *ComponentReconciler) {
+		r.log = l
+	}
+}
+
+// NewComponentReconciler returns a ComponentReconciler that updates
+// Components through the supplied client, and resolves their workloads to
+// their definitions through the supplied definition reader, e.g. a
+// definition cache.
+func NewComponentReconciler(c client.Client, defs client.Reader, dm discoverymapper.DiscoveryMapper, o ...ComponentReconcilerOption) *ComponentReconciler {
+	r := &ComponentReconciler{
+		client: c,
+		defs:   defs,
+		dm:     dm,
+		log:    logging.NewNopLogger(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile the parameter schema of a Component.
+func (r *ComponentReconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancel()
+
+	comp := &v1alpha2.Component{}
+	if err := r.client.Get(ctx, req.NamespacedName, comp); err != nil {
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetComponent)
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(comp.Spec.Workload.Raw, &u.Object); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errDecodeWorkload)
+	}
+	u.SetNamespace(comp.GetNamespace())
+	var workload *crdv1.JSONSchemaProps
+	wd, err := util.FetchWorkloadDefinition(ctx, r.defs, r.dm, u)
+	switch {
+	case err == nil:
+		if workload, err = unmarshalSchema(wd.Status.Schema); err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, errFmtDecodeSchema, wd.GetName())
+		}
+	case kerrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		// parameters of workloads without a definition are described only
+		// by their declaration
+	default:
+		return reconcile.Result{}, errors.Wrapf(err, errFmtFetchDefinition, req.Name)
+	}
+
+	s, err := ParameterSchema(comp.Spec.Parameters, workload)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtParameterSchema, req.Name)
+	}
+	raw, err := marshalSchema(s)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, errFmtMarshalSchema, v1alpha2.ComponentKind, req.Name)
+	}
+	if equalSchemas(comp.Status.ParameterSchema, raw) {
+		return reconcile.Result{}, nil
+	}
+	comp.Status.ParameterSchema = raw
+	r.log.Debug("Updating parameter schema", "namespace", req.Namespace, "name", req.Name)
+	return reconcile.Result{}, errors.Wrapf(r.client.Status().Update(ctx, comp), errFmtUpdateStatus, v1alpha2.ComponentKind, req.Name)
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parameterschema
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func rawSchema(t *testing.T, s *crdv1.JSONSchemaProps) *runtime.RawExtension {
+	t.Helper()
+	raw, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &runtime.RawExtension{Raw: raw}
+}
+
+func TestDefinitionReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "webservice"}}
+	crd := func(obj *crdv1.CustomResourceDefinition) {
+		obj.SetName("webservices.example.com")
+		obj.Spec.Versions = []crdv1.CustomResourceDefinitionVersion{
+			{Name: "v1", Served: true, Storage: true, Schema: &crdv1.CustomResourceValidation{OpenAPIV3Schema: webserviceSchema}},
+		}
+	}
+	get := func(status v1alpha2.DefinitionStatus, crdErr error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.WorkloadDefinition:
+				o.SetName("webservice")
+				o.Spec.Reference.Name = "webservices.example.com"
+				o.Status = status
+			case *crdv1.CustomResourceDefinition:
+				if crdErr != nil {
+					return crdErr
+				}
+				crd(o)
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		err    error
+		schema *crdv1.JSONSchemaProps
+		update bool
+	}
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		want   want
+	}{
+		"DefinitionNotFound": {
+			reason: "Deleted definitions should be ignored",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "webservice"))},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the definition should be returned",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetDefinition, v1alpha2.WorkloadDefinitionKind)},
+		},
+		"GetCRDError": {
+			reason: "Errors getting the CustomResourceDefinition should be returned",
+			c:      &test.MockClient{MockGet: get(v1alpha2.DefinitionStatus{}, errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetCRD, "webservices.example.com")},
+		},
+		"PublishSchema": {
+			reason: "The schema of the CustomResourceDefinition should be published",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.DefinitionStatus{UsageCount: 1}, nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			want: want{schema: webserviceSchema, update: true},
+		},
+		"SchemaUpToDate": {
+			reason: "The status should not be updated if the published schema is up to date",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.DefinitionStatus{Schema: rawSchema(t, webserviceSchema)}, nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+		},
+		"CRDNotFound": {
+			reason: "The schema of a definition of a resource without a CustomResourceDefinition should be removed",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.DefinitionStatus{Schema: rawSchema(t, webserviceSchema)}, kerrors.NewNotFound(schema.GroupResource{}, "webservices.example.com")),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			want: want{update: true},
+		},
+		"UpdateStatusError": {
+			reason: "Errors updating the status of the definition should be returned",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.DefinitionStatus{}, nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			want: want{
+				err:    errors.Wrapf(errBoom, errFmtUpdateStatus, v1alpha2.WorkloadDefinitionKind, "webservice"),
+				schema: webserviceSchema,
+				update: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			if mc := tc.c.(*test.MockClient); mc.MockStatusUpdate != nil {
+				update := mc.MockStatusUpdate
+				mc.MockStatusUpdate = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					s, err := unmarshalSchema(obj.(*v1alpha2.WorkloadDefinition).Status.Schema)
+					if err != nil {
+						t.Fatal(err)
+					}
+					got.schema, got.update = s, true
+					return update(ctx, obj, opts...)
+				}
+			}
+			r := NewDefinitionReconciler(tc.c, v1alpha2.WorkloadDefinitionKind)
+			_, got.err = r.Reconcile(req)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComponentReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "web"}}
+	params := []v1alpha2.ComponentParameter{
+		{Name: "replicas", FieldPaths: []string{"spec.replicas"}},
+	}
+	withDefinition := &crdv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]crdv1.JSONSchemaProps{
+			"replicas": {Type: "integer", Format: "int32", Description: "Number of replicas."},
+		},
+	}
+	withoutDefinition := &crdv1.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]crdv1.JSONSchemaProps{"replicas": {}},
+	}
+	get := func(status v1alpha2.ComponentStatus, wdErr error) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.Component:
+				o.SetNamespace("ns")
+				o.SetName("web")
+				o.Spec.Workload = runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.com/v1","kind":"WebService","metadata":{"labels":{"workload.oam.dev/type":"webservice"}}}`)}
+				o.Spec.Parameters = params
+				o.Status = status
+			case *v1alpha2.WorkloadDefinition:
+				if wdErr != nil {
+					return wdErr
+				}
+				o.SetName("webservice")
+				o.Status.Schema = rawSchema(t, webserviceSchema)
+			}
+			return nil
+		}
+	}
+
+	type want struct {
+		err    error
+		schema *crdv1.JSONSchemaProps
+		update bool
+	}
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		want   want
+	}{
+		"ComponentNotFound": {
+			reason: "Deleted Components should be ignored",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "web"))},
+		},
+		"GetComponentError": {
+			reason: "Errors getting the Component should be returned",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   want{err: errors.Wrap(errBoom, errGetComponent)},
+		},
+		"FetchDefinitionError": {
+			reason: "Errors fetching the WorkloadDefinition should be returned",
+			c:      &test.MockClient{MockGet: get(v1alpha2.ComponentStatus{}, errBoom)},
+			want:   want{err: errors.Wrapf(errBoom, errFmtFetchDefinition, "web")},
+		},
+		"PublishSchema": {
+			reason: "The parameter schema derived from the schema of the WorkloadDefinition should be published",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.ComponentStatus{}, nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			want: want{schema: withDefinition, update: true},
+		},
+		"DefinitionNotFound": {
+			reason: "Parameters of workloads without a definition should be described by their declaration",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.ComponentStatus{}, kerrors.NewNotFound(schema.GroupResource{}, "webservice")),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			},
+			want: want{schema: withoutDefinition, update: true},
+		},
+		"SchemaUpToDate": {
+			reason: "The status should not be updated if the published schema is up to date",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.ComponentStatus{ParameterSchema: rawSchema(t, withDefinition)}, nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+		},
+		"UpdateStatusError": {
+			reason: "Errors updating the status of the Component should be returned",
+			c: &test.MockClient{
+				MockGet:          get(v1alpha2.ComponentStatus{}, nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+			},
+			want: want{
+				err:    errors.Wrapf(errBoom, errFmtUpdateStatus, v1alpha2.ComponentKind, "web"),
+				schema: withDefinition,
+				update: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got want
+			if mc := tc.c.(*test.MockClient); mc.MockStatusUpdate != nil {
+				update := mc.MockStatusUpdate
+				mc.MockStatusUpdate = func(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+					s, err := unmarshalSchema(obj.(*v1alpha2.Component).Status.ParameterSchema)
+					if err != nil {
+						t.Fatal(err)
+					}
+					got.schema, got.update = s, true
+					return update(ctx, obj, opts...)
+				}
+			}
+			r := NewComponentReconciler(tc.c, tc.c, mock.NewMockDiscoveryMapper())
+			_, got.err = r.Reconcile(req)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parameterschema
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// ResourceSchema returns the JSON schema of the objects of a definition with
+// the supplied reference, read from the version of the supplied CRD they are
+// created at. It returns nil if that version has no schema.
+func ResourceSchema(crd *crdv1.CustomResourceDefinition, ref v1alpha2.DefinitionReference) (*crdv1.JSONSchemaProps, error) {
+	version, err := util.DefinitionVersion(crd, ref)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version && v.Schema != nil {
+			return v.Schema.OpenAPIV3Schema, nil
+		}
+	}
+	return nil, nil
+}
+
+// ParameterSchema returns the JSON schema of the parameter values that may
+// be supplied to a Component with the supplied parameters, whose workload
+// has the supplied schema, if any. Parameter values are strings or integers.
+// The schema of a parameter is that of the first of its field paths the
+// workload schema describes as a string or integer, unless the value of the
+// parameter is transformed before it is written. The type, enum, range,
+// description and default value the parameter declares take precedence.
+func ParameterSchema(params []v1alpha2.ComponentParameter, workload *crdv1.JSONSchemaProps) (*crdv1.JSONSchemaProps, error) {
+	s := &crdv1.JSONSchemaProps{Type: "object"}
+	for _, p := range params {
+		ps := crdv1.JSONSchemaProps{}
+		if len(p.Transforms) == 0 {
+			for _, path := range p.FieldPaths {
+				if f := fieldSchema(workload, path); f != nil && (f.Type == "string" || f.Type == "integer") {
+					ps = *f.DeepCopy()
+					break
+				}
+			}
+		}
+		switch p.Type {
+		case v1alpha2.StringParameterType, v1alpha2.IntegerParameterType:
+			ps.Type = string(p.Type)
+		case v1alpha2.BooleanParameterType:
+			ps.Type, ps.Enum = "string", []crdv1.JSON{{Raw: []byte(`"true"`)}, {Raw: []byte(`"false"`)}}
+		}
+		if len(p.Enum) > 0 {
+			ps.Enum = make([]crdv1.JSON, 0, len(p.Enum))
+			for _, e := range p.Enum {
+				raw, err := json.Marshal(e)
+				if err != nil {
+					return nil, err
+				}
+				ps.Enum = append(ps.Enum, crdv1.JSON{Raw: raw})
+			}
+		}
+		if p.Minimum != nil {
+			min := float64(*p.Minimum)
+			ps.Minimum = &min
+		}
+		if p.Maximum != nil {
+			max := float64(*p.Maximum)
+			ps.Maximum = &max
+		}
+		if p.Description != nil {
+			ps.Description = *p.Description
+		}
+		if p.Default != nil {
+			raw, err := json.Marshal(p.Default)
+			if err != nil {
+				return nil, err
+			}
+			ps.Default = &crdv1.JSON{Raw: raw}
+		}
+		if p.Required != nil && *p.Required {
+			s.Required = append(s.Required, p.Name)
+		}
+		if s.Properties == nil {
+			s.Properties = map[string]crdv1.JSONSchemaProps{}
+		}
+		s.Properties[p.Name] = ps
+	}
+	return s, nil
+}
+
+// fieldSchema returns the schema of the field at the supplied path of objects
+// of the supplied schema, or nil if the schema does not describe it.
+func fieldSchema(s *crdv1.JSONSchemaProps, path string) *crdv1.JSONSchemaProps {
+	segments, err := fieldpath.Parse(path)
+	if err != nil {
+		return nil
+	}
+	for _, seg := range segments {
+		if s == nil {
+			return nil
+		}
+		switch seg.Type {
+		case fieldpath.SegmentField:
+			if p, ok := s.Properties[seg.Field]; ok {
+				s = &p
+				continue
+			}
+			if s.AdditionalProperties == nil {
+				return nil
+			}
+			s = s.AdditionalProperties.Schema
+		case fieldpath.SegmentIndex:
+			if s.Items == nil {
+				return nil
+			}
+			s = s.Items.Schema
+		}
+	}
+	return s
+}
+
+// marshalSchema returns the supplied schema as a raw extension, or nil if
+// there is none.
+func marshalSchema(s *crdv1.JSONSchemaProps) (*runtime.RawExtension, error) {
+	if s == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
+// unmarshalSchema returns the schema of the supplied raw extension, or nil if
+// there is none.
+func unmarshalSchema(raw *runtime.RawExtension) (*crdv1.JSONSchemaProps, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil, nil
+	}
+	s := &crdv1.JSONSchemaProps{}
+	if err := json.Unmarshal(raw.Raw, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// equalSchemas returns true if the supplied raw extensions are the same JSON
+// document, regardless of the order of their keys. The API server may
+// reorder the keys of the schemas it stores.
+func equalSchemas(a, b *runtime.RawExtension) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	var x, y interface{}
+	if json.Unmarshal(a.Raw, &x) != nil || json.Unmarshal(b.Raw, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parameterschema
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+var webserviceSchema = &crdv1.JSONSchemaProps{
+	Type: "object",
+	Properties: map[string]crdv1.JSONSchemaProps{
+		"spec": {
+			Type: "object",
+			Properties: map[string]crdv1.JSONSchemaProps{
+				"replicas": {Type: "integer", Format: "int32", Description: "Number of replicas."},
+				"paused":   {Type: "boolean"},
+				"containers": {
+					Type: "array",
+					Items: &crdv1.JSONSchemaPropsOrArray{Schema: &crdv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]crdv1.JSONSchemaProps{
+							"image": {Type: "string"},
+						},
+					}},
+				},
+				"env": {
+					Type:                 "object",
+					AdditionalProperties: &crdv1.JSONSchemaPropsOrBool{Allows: true, Schema: &crdv1.JSONSchemaProps{Type: "string"}},
+				},
+			},
+		},
+	},
+}
+
+func float64Ptr(f float64) *float64 { return &f }
+
+func TestResourceSchema(t *testing.T) {
+	v1 := &crdv1.JSONSchemaProps{Type: "object", Description: "v1"}
+	v2 := &crdv1.JSONSchemaProps{Type: "object", Description: "v2"}
+	crd := &crdv1.CustomResourceDefinition{
+		Spec: crdv1.CustomResourceDefinitionSpec{
+			Versions: []crdv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true, Schema: &crdv1.CustomResourceValidation{OpenAPIV3Schema: v1}},
+				{Name: "v2", Served: true, Schema: &crdv1.CustomResourceValidation{OpenAPIV3Schema: v2}},
+				{Name: "v3", Served: true},
+				{Name: "v4"},
+			},
+		},
+	}
+	crd.SetName("webservices.example.com")
+
+	type want struct {
+		s   *crdv1.JSONSchemaProps
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		ref    v1alpha2.DefinitionReference
+		want   want
+	}{
+		"StorageVersion": {
+			reason: "The schema of the storage version should be returned if the reference has no version",
+			ref:    v1alpha2.DefinitionReference{Name: crd.GetName()},
+			want:   want{s: v1},
+		},
+		"ReferencedVersion": {
+			reason: "The schema of the version of the reference should be returned",
+			ref:    v1alpha2.DefinitionReference{Name: crd.GetName(), Version: "v2"},
+			want:   want{s: v2},
+		},
+		"NoSchema": {
+			reason: "Nil should be returned if the version has no schema",
+			ref:    v1alpha2.DefinitionReference{Name: crd.GetName(), Version: "v3"},
+		},
+		"NotServed": {
+			reason: "An error should be returned if the version is not served",
+			ref:    v1alpha2.DefinitionReference{Name: crd.GetName(), Version: "v4"},
+			want:   want{err: errors.Errorf(`version "v4" of CustomResourceDefinition "webservices.example.com" is not served, served versions are [v1 v2 v3]`)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := ResourceSchema(crd, tc.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResourceSchema(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.s, s); diff != "" {
+				t.Errorf("\n%s\nResourceSchema(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParameterSchema(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		params   []v1alpha2.ComponentParameter
+		workload *crdv1.JSONSchemaProps
+		want     *crdv1.JSONSchemaProps
+	}{
+		"NoParameters": {
+			reason:   "A Component without parameters accepts no values",
+			workload: webserviceSchema,
+			want:     &crdv1.JSONSchemaProps{Type: "object"},
+		},
+		"FieldSchemas": {
+			reason: "The schema of a parameter should be that of the first of its fields the workload schema describes",
+			params: []v1alpha2.ComponentParameter{
+				{Name: "replicas", FieldPaths: []string{"spec.replicas"}, Required: pointer.BoolPtr(true)},
+				{Name: "image", FieldPaths: []string{"spec.unknown", "spec.containers[0].image"}},
+				{Name: "level", FieldPaths: []string{"spec.env.LEVEL"}},
+				{Name: "unknown", FieldPaths: []string{"spec.unknown"}},
+				{Name: "paused", FieldPaths: []string{"spec.paused"}},
+			},
+			workload: webserviceSchema,
+			want: &crdv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]crdv1.JSONSchemaProps{
+					"replicas": {Type: "integer", Format: "int32", Description: "Number of replicas."},
+					"image":    {Type: "string"},
+					"level":    {Type: "string"},
+					"unknown":  {},
+					"paused":   {},
+				},
+				Required: []string{"replicas"},
+			},
+		},
+		"Declarations": {
+			reason: "The declared type, enum, range, description and default value of a parameter should override those of its fields",
+			params: []v1alpha2.ComponentParameter{
+				{
+					Name:        "replicas",
+					FieldPaths:  []string{"spec.replicas"},
+					Description: pointer.StringPtr("How many instances to run."),
+					Default:     func() *intstr.IntOrString { d := intstr.FromInt(3); return &d }(),
+				},
+				{
+					Name:       "instances",
+					FieldPaths: []string{"spec.replicas"},
+					Minimum:    pointer.Int64Ptr(1),
+					Maximum:    pointer.Int64Ptr(5),
+				},
+				{Name: "version", Type: v1alpha2.StringParameterType, Enum: []intstr.IntOrString{intstr.FromString("v1"), intstr.FromString("v2")}},
+				{Name: "debug", Type: v1alpha2.BooleanParameterType},
+			},
+			workload: webserviceSchema,
+			want: &crdv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]crdv1.JSONSchemaProps{
+					"replicas":  {Type: "integer", Format: "int32", Description: "How many instances to run.", Default: &crdv1.JSON{Raw: []byte("3")}},
+					"instances": {Type: "integer", Format: "int32", Description: "Number of replicas.", Minimum: float64Ptr(1), Maximum: float64Ptr(5)},
+					"version":   {Type: "string", Enum: []crdv1.JSON{{Raw: []byte(`"v1"`)}, {Raw: []byte(`"v2"`)}}},
+					"debug":     {Type: "string", Enum: []crdv1.JSON{{Raw: []byte(`"true"`)}, {Raw: []byte(`"false"`)}}},
+				},
+			},
+		},
+		"Transformed": {
+			reason: "The schema of the fields of a parameter whose value is transformed should not describe the parameter",
+			params: []v1alpha2.ComponentParameter{
+				{
+					Name:       "replicas",
+					FieldPaths: []string{"spec.replicas"},
+					Transforms: []v1alpha2.ParameterTransform{{Type: v1alpha2.ToIntTransformType}},
+				},
+			},
+			workload: webserviceSchema,
+			want: &crdv1.JSONSchemaProps{
+				Type:       "object",
+				Properties: map[string]crdv1.JSONSchemaProps{"replicas": {}},
+			},
+		},
+		"NoWorkloadSchema": {
+			reason: "Parameters of workloads without a schema should be described by their declaration only",
+			params: []v1alpha2.ComponentParameter{
+				{Name: "replicas", FieldPaths: []string{"spec.replicas"}, Type: v1alpha2.IntegerParameterType},
+			},
+			want: &crdv1.JSONSchemaProps{
+				Type:       "object",
+				Properties: map[string]crdv1.JSONSchemaProps{"replicas": {Type: "integer"}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParameterSchema(tc.params, tc.workload)
+			if err != nil {
+				t.Fatalf("\n%s\nParameterSchema(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nParameterSchema(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
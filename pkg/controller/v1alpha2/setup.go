@@ -24,14 +24,38 @@ import (
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/applicationconfiguration"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes/healthscope"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes/networkscope"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes/placementscope"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes/resourcequotascope"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/scopes/securityscope"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/autoscalertrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/backuptrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/eventscalertrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/ingresstrait"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/manualscalertrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/metricstrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/rollouttrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/sidecartrait"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/traits/volumeclaimtrait"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/containerizedworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/daemonworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/statefulworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/core/workloads/taskworkload"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/definitionregistration"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/definitionrevision"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/definitionusage"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/parameterschema"
 )
 
 // Setup workload controllers.
 func Setup(mgr ctrl.Manager, args controller.Args, l logging.Logger) error {
 	for _, setup := range []func(ctrl.Manager, controller.Args, logging.Logger) error{
-		applicationconfiguration.Setup, containerizedworkload.Setup, manualscalertrait.Setup, healthscope.Setup,
+		applicationconfiguration.Setup, applicationconfiguration.SetupRevisionGC,
+		containerizedworkload.Setup, taskworkload.Setup, statefulworkload.Setup, daemonworkload.Setup,
+		manualscalertrait.Setup, autoscalertrait.Setup, ingresstrait.Setup, volumeclaimtrait.Setup, metricstrait.Setup,
+		rollouttrait.Setup, sidecartrait.Setup, eventscalertrait.Setup, backuptrait.Setup,
+		healthscope.Setup, networkscope.Setup, resourcequotascope.Setup, securityscope.Setup, placementscope.Setup,
+		definitionusage.Setup, definitionregistration.Setup, definitionrevision.Setup, parameterschema.Setup,
 	} {
 		if err := setup(mgr, args, l); err != nil {
 			return err
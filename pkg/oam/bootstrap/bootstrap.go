@@ -0,0 +1,274 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrap installs the core definitions shipped with the runtime.
+package bootstrap
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	errFmtApplyDefinition = "cannot apply %s %q"
+)
+
+// Names of the definitions of the workloads, traits and scopes shipped with
+// the runtime. They are named after the CRDs they reference.
+const (
+	ContainerizedWorkloadDefinitionName = "containerizedworkloads.core.oam.dev"
+	TaskWorkloadDefinitionName          = "taskworkloads.core.oam.dev"
+	StatefulWorkloadDefinitionName      = "statefulworkloads.core.oam.dev"
+	DaemonWorkloadDefinitionName        = "daemonworkloads.core.oam.dev"
+	ManualScalerTraitDefinitionName     = "manualscalertraits.core.oam.dev"
+	AutoscalerTraitDefinitionName       = "autoscalertraits.core.oam.dev"
+	IngressTraitDefinitionName          = "ingresstraits.core.oam.dev"
+	VolumeClaimTraitDefinitionName      = "volumeclaimtraits.core.oam.dev"
+	MetricsTraitDefinitionName          = "metricstraits.core.oam.dev"
+	RolloutTraitDefinitionName          = "rollouttraits.core.oam.dev"
+	SidecarTraitDefinitionName          = "sidecartraits.core.oam.dev"
+	EventScalerTraitDefinitionName      = "eventscalertraits.core.oam.dev"
+	BackupTraitDefinitionName           = "backuptraits.core.oam.dev"
+	HealthScopeDefinitionName           = "healthscopes.core.oam.dev"
+	NetworkScopeDefinitionName          = "networkscopes.core.oam.dev"
+	ResourceQuotaScopeDefinitionName    = "resourcequotascopes.core.oam.dev"
+	SecurityScopeDefinitionName         = "securityscopes.core.oam.dev"
+	PlacementScopeDefinitionName        = "placementscopes.core.oam.dev"
+)
+
+// ContainerizedWorkloadStatusMessage is the status message of
+// ContainerizedWorkloads in the status of ApplicationConfigurations.
+const ContainerizedWorkloadStatusMessage = "{{.ready}}/{{.replicas}} replicas ready{{if .serviceIP}}, service IP {{.serviceIP}}{{end}}"
+
+// StatefulWorkloadStatusMessage is the status message of StatefulWorkloads
+// in the status of ApplicationConfigurations.
+const StatefulWorkloadStatusMessage = "{{.ready}}/{{.replicas}} replicas ready{{if .revision}}, revision {{.revision}}{{end}}"
+
+// DaemonWorkloadStatusMessage is the status message of DaemonWorkloads in
+// the status of ApplicationConfigurations.
+const DaemonWorkloadStatusMessage = "{{.ready}}/{{.desired}} nodes ready{{if .serviceIP}}, service IP {{.serviceIP}}{{end}}"
+
+// CoreDefinitions returns the WorkloadDefinitions, TraitDefinitions and
+// ScopeDefinitions of the workloads, traits and scopes shipped with the
+// runtime.
+func CoreDefinitions() []runtime.Object {
+	return []runtime.Object{
+		&v1alpha2.WorkloadDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.WorkloadDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: ContainerizedWorkloadDefinitionName},
+			Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference: v1alpha2.DefinitionReference{Name: ContainerizedWorkloadDefinitionName},
+				ChildResourceKinds: []v1alpha2.ChildResourceKind{
+					{APIVersion: "apps/v1", Kind: "Deployment"},
+					{APIVersion: "v1", Kind: "Service"},
+				},
+				StatusFields: []v1alpha2.StatusField{
+					{Name: "replicas", FieldPath: "status.replicas"},
+					{Name: "ready", FieldPath: "status.readyReplicas"},
+					{Name: "serviceIP", FieldPath: "status.serviceIP"},
+				},
+				StatusMessage: ContainerizedWorkloadStatusMessage,
+			},
+		},
+		&v1alpha2.WorkloadDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.WorkloadDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: TaskWorkloadDefinitionName},
+			Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference: v1alpha2.DefinitionReference{Name: TaskWorkloadDefinitionName},
+				ChildResourceKinds: []v1alpha2.ChildResourceKind{
+					{APIVersion: "batch/v1", Kind: "Job"},
+				},
+			},
+		},
+		&v1alpha2.WorkloadDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.WorkloadDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: StatefulWorkloadDefinitionName},
+			Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference: v1alpha2.DefinitionReference{Name: StatefulWorkloadDefinitionName},
+				ChildResourceKinds: []v1alpha2.ChildResourceKind{
+					{APIVersion: "apps/v1", Kind: "StatefulSet"},
+					{APIVersion: "v1", Kind: "Service"},
+				},
+				StatusFields: []v1alpha2.StatusField{
+					{Name: "replicas", FieldPath: "status.replicas"},
+					{Name: "ready", FieldPath: "status.readyReplicas"},
+					{Name: "revision", FieldPath: "status.updateRevision"},
+				},
+				StatusMessage: StatefulWorkloadStatusMessage,
+			},
+		},
+		&v1alpha2.WorkloadDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.WorkloadDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: DaemonWorkloadDefinitionName},
+			Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference: v1alpha2.DefinitionReference{Name: DaemonWorkloadDefinitionName},
+				ChildResourceKinds: []v1alpha2.ChildResourceKind{
+					{APIVersion: "apps/v1", Kind: "DaemonSet"},
+					{APIVersion: "v1", Kind: "Service"},
+				},
+				StatusFields: []v1alpha2.StatusField{
+					{Name: "desired", FieldPath: "status.desiredNodes"},
+					{Name: "ready", FieldPath: "status.readyNodes"},
+					{Name: "serviceIP", FieldPath: "status.serviceIP"},
+				},
+				StatusMessage: DaemonWorkloadStatusMessage,
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: ManualScalerTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: ManualScalerTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: AutoscalerTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: AutoscalerTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+				// autoscalers and manual scalers would fight over the replicas
+				ConflictsWith: []string{ManualScalerTraitDefinitionName},
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: IngressTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: IngressTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: VolumeClaimTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: VolumeClaimTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: MetricsTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: MetricsTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: RolloutTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: RolloutTraitDefinitionName},
+				RevisionEnabled: true,
+				RevisionsPath:   "spec.revisions",
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: SidecarTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: SidecarTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: EventScalerTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: EventScalerTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.TraitDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.TraitDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: BackupTraitDefinitionName},
+			Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:       v1alpha2.DefinitionReference{Name: BackupTraitDefinitionName},
+				WorkloadRefPath: "spec.workloadRef",
+			},
+		},
+		&v1alpha2.ScopeDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ScopeDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: HealthScopeDefinitionName},
+			Spec: v1alpha2.ScopeDefinitionSpec{
+				Reference:             v1alpha2.DefinitionReference{Name: HealthScopeDefinitionName},
+				WorkloadRefsPath:      "spec.workloadRefs",
+				AllowComponentOverlap: true,
+			},
+		},
+		&v1alpha2.ScopeDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ScopeDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: NetworkScopeDefinitionName},
+			Spec: v1alpha2.ScopeDefinitionSpec{
+				Reference:        v1alpha2.DefinitionReference{Name: NetworkScopeDefinitionName},
+				WorkloadRefsPath: "spec.workloadRefs",
+			},
+		},
+		&v1alpha2.ScopeDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ScopeDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: ResourceQuotaScopeDefinitionName},
+			Spec: v1alpha2.ScopeDefinitionSpec{
+				Reference:             v1alpha2.DefinitionReference{Name: ResourceQuotaScopeDefinitionName},
+				WorkloadRefsPath:      "spec.workloadRefs",
+				AllowComponentOverlap: true,
+			},
+		},
+		&v1alpha2.ScopeDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ScopeDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: SecurityScopeDefinitionName},
+			Spec: v1alpha2.ScopeDefinitionSpec{
+				Reference:        v1alpha2.DefinitionReference{Name: SecurityScopeDefinitionName},
+				WorkloadRefsPath: "spec.workloadRefs",
+			},
+		},
+		&v1alpha2.ScopeDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ScopeDefinitionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: PlacementScopeDefinitionName},
+			Spec: v1alpha2.ScopeDefinitionSpec{
+				Reference:        v1alpha2.DefinitionReference{Name: PlacementScopeDefinitionName},
+				WorkloadRefsPath: "spec.workloadRefs",
+			},
+		},
+	}
+}
+
+// Install creates the supplied definitions, or updates them if they already
+// exist. Fields of existing definitions that the supplied definitions don't
+// set, e.g. labels added by users, are kept.
+func Install(ctx context.Context, a resource.Applicator, defs ...runtime.Object) error {
+	for _, d := range defs {
+		// the applicator overwrites the supplied object with the applied one
+		d = d.DeepCopyObject()
+		kind := d.GetObjectKind().GroupVersionKind().Kind
+		name := ""
+		if m, ok := d.(metav1.Object); ok {
+			name = m.GetName()
+		}
+		if err := a.Apply(ctx, d); err != nil {
+			return errors.Wrapf(err, errFmtApplyDefinition, kind, name)
+		}
+	}
+	return nil
+}
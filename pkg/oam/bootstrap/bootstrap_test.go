@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestInstall(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		applied []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		apply  func(context.Context, runtime.Object, ...resource.ApplyOption) error
+		want   want
+	}{
+		"ApplyAll": {
+			reason: "All core definitions should be applied in order",
+			apply:  func(context.Context, runtime.Object, ...resource.ApplyOption) error { return nil },
+			want: want{
+				applied: []string{
+					v1alpha2.WorkloadDefinitionKind + "/" + ContainerizedWorkloadDefinitionName,
+					v1alpha2.WorkloadDefinitionKind + "/" + TaskWorkloadDefinitionName,
+					v1alpha2.WorkloadDefinitionKind + "/" + StatefulWorkloadDefinitionName,
+					v1alpha2.WorkloadDefinitionKind + "/" + DaemonWorkloadDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + ManualScalerTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + AutoscalerTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + IngressTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + VolumeClaimTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + MetricsTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + RolloutTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + SidecarTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + EventScalerTraitDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + BackupTraitDefinitionName,
+					v1alpha2.ScopeDefinitionKind + "/" + HealthScopeDefinitionName,
+					v1alpha2.ScopeDefinitionKind + "/" + NetworkScopeDefinitionName,
+					v1alpha2.ScopeDefinitionKind + "/" + ResourceQuotaScopeDefinitionName,
+					v1alpha2.ScopeDefinitionKind + "/" + SecurityScopeDefinitionName,
+					v1alpha2.ScopeDefinitionKind + "/" + PlacementScopeDefinitionName,
+				},
+			},
+		},
+		"ApplyError": {
+			reason: "Errors applying a definition should be returned, and stop the installation",
+			apply: func(_ context.Context, o runtime.Object, _ ...resource.ApplyOption) error {
+				if o.GetObjectKind().GroupVersionKind().Kind == v1alpha2.TraitDefinitionKind {
+					return errBoom
+				}
+				return nil
+			},
+			want: want{
+				applied: []string{
+					v1alpha2.WorkloadDefinitionKind + "/" + ContainerizedWorkloadDefinitionName,
+					v1alpha2.WorkloadDefinitionKind + "/" + TaskWorkloadDefinitionName,
+					v1alpha2.WorkloadDefinitionKind + "/" + StatefulWorkloadDefinitionName,
+					v1alpha2.WorkloadDefinitionKind + "/" + DaemonWorkloadDefinitionName,
+					v1alpha2.TraitDefinitionKind + "/" + ManualScalerTraitDefinitionName,
+				},
+				err: errors.Wrapf(errBoom, errFmtApplyDefinition, v1alpha2.TraitDefinitionKind, ManualScalerTraitDefinitionName),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			defs := CoreDefinitions()
+			applied := []string{}
+			a := resource.ApplyFn(func(ctx context.Context, o runtime.Object, ao ...resource.ApplyOption) error {
+				applied = append(applied, o.GetObjectKind().GroupVersionKind().Kind+"/"+o.(metav1.Object).GetName())
+				// the applicator overwrites the object it is passed
+				o.(metav1.Object).SetResourceVersion("1")
+				return tc.apply(ctx, o, ao...)
+			})
+			err := Install(context.Background(), a, defs...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInstall(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\n%s\nInstall(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(CoreDefinitions(), defs); diff != "" {
+				t.Errorf("\n%s\nInstall(...): -want definitions unchanged, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle exports Components together with the definitions of their
+// workloads as portable bundles, and imports them into another cluster or
+// namespace, e.g. to promote a component from staging to production.
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Bundle type metadata.
+var (
+	APIVersion = v1alpha2.SchemeGroupVersion.String()
+	Kind       = "ComponentBundle"
+)
+
+const (
+	errGetComponent            = "cannot get component"
+	errFmtUnmarshalWorkload    = "cannot unmarshal workload %d of component"
+	errFmtGetDefinition        = "cannot get workload definition of kind %q"
+	errMarshalBundle           = "cannot marshal bundle"
+	errUnmarshalBundle         = "cannot unmarshal bundle"
+	errFmtInvalidBundleType    = "invalid bundle type %q %q, want %q %q"
+	errFmtRevisionHashMismatch = "revision hash %q of component %q does not match the revision hash %q of the bundle"
+	errFmtApply                = "cannot apply %s %q"
+)
+
+// A Bundle is a portable snapshot of a Component and the definitions of its
+// workloads. The revision hash of the component is recorded, so importing a
+// bundle into another cluster results in a revision with the same hash.
+type Bundle struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// RevisionHash of the exported component.
+	RevisionHash string `json:"revisionHash"`
+
+	// Component that is exported, without status and the metadata maintained
+	// by the API server.
+	Component v1alpha2.Component `json:"component"`
+
+	// WorkloadDefinitions of the workload and auxiliary workloads of the
+	// component. Workloads without a definition, e.g. built-in Kubernetes
+	// resources, have none.
+	WorkloadDefinitions []v1alpha2.WorkloadDefinition `json:"workloadDefinitions,omitempty"`
+}
+
+// Export the Component with the supplied key as a Bundle.
+func Export(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper, key client.ObjectKey) (*Bundle, error) {
+	comp := &v1alpha2.Component{}
+	if err := r.Get(ctx, key, comp); err != nil {
+		return nil, errors.Wrap(err, errGetComponent)
+	}
+	b := &Bundle{
+		TypeMeta:     metav1.TypeMeta{APIVersion: APIVersion, Kind: Kind},
+		RevisionHash: util.ComputeComponentRevisionHash(comp),
+	}
+
+	workloads := []runtime.RawExtension{comp.Spec.Workload}
+	for _, aw := range comp.Spec.AuxiliaryWorkloads {
+		workloads = append(workloads, aw.Workload)
+	}
+	seen := map[string]bool{}
+	for i, raw := range workloads {
+		w, err := unmarshalWorkload(raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtUnmarshalWorkload, i)
+		}
+		if w.GetKind() == "" {
+			continue
+		}
+		wd, err := util.FetchWorkloadDefinition(ctx, r, dm, w)
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetDefinition, w.GetKind())
+		}
+		if seen[wd.GetName()] {
+			continue
+		}
+		seen[wd.GetName()] = true
+		b.WorkloadDefinitions = append(b.WorkloadDefinitions, v1alpha2.WorkloadDefinition{
+			ObjectMeta: portableMeta(wd.ObjectMeta),
+			Spec:       wd.Spec,
+		})
+	}
+
+	b.Component = v1alpha2.Component{
+		ObjectMeta: portableMeta(comp.ObjectMeta),
+		Spec:       comp.Spec,
+	}
+	return b, nil
+}
+
+// Import the supplied Bundle, creating or updating its definitions and its
+// component. The component is imported into the supplied namespace, or into
+// the namespace it was exported from if the supplied namespace is empty. An
+// error is returned if the component doesn't match the revision hash of the
+// bundle, e.g. because the bundle was modified.
+func Import(ctx context.Context, c client.Client, b *Bundle, namespace string) error {
+	if err := Verify(b); err != nil {
+		return err
+	}
+	for i := range b.WorkloadDefinitions {
+		wd := b.WorkloadDefinitions[i].DeepCopy()
+		if err := apply(ctx, c, wd, &v1alpha2.WorkloadDefinition{}); err != nil {
+			return errors.Wrapf(err, errFmtApply, v1alpha2.WorkloadDefinitionKind, wd.GetName())
+		}
+	}
+	comp := b.Component.DeepCopy()
+	if namespace != "" {
+		comp.SetNamespace(namespace)
+	}
+	return errors.Wrapf(apply(ctx, c, comp, &v1alpha2.Component{}), errFmtApply, v1alpha2.ComponentKind, comp.GetName())
+}
+
+// Verify that the component of the supplied Bundle matches its revision hash.
+func Verify(b *Bundle) error {
+	if b.APIVersion != APIVersion || b.Kind != Kind {
+		return errors.Errorf(errFmtInvalidBundleType, b.APIVersion, b.Kind, APIVersion, Kind)
+	}
+	if h := util.ComputeComponentRevisionHash(&b.Component); h != b.RevisionHash {
+		return errors.Errorf(errFmtRevisionHashMismatch, h, b.Component.GetName(), b.RevisionHash)
+	}
+	return nil
+}
+
+// Marshal the supplied Bundle to YAML.
+func Marshal(b *Bundle) ([]byte, error) {
+	data, err := yaml.Marshal(b)
+	return data, errors.Wrap(err, errMarshalBundle)
+}
+
+// Unmarshal a Bundle from YAML or JSON and verify it.
+func Unmarshal(data []byte) (*Bundle, error) {
+	b := &Bundle{}
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalBundle)
+	}
+	return b, Verify(b)
+}
+
+// apply creates the desired object, or replaces the existing object with the
+// same name and namespace, which is read into the supplied existing object.
+func apply(ctx context.Context, c client.Client, desired, existing resource.Object) error {
+	key := types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}
+	if err := c.Get(ctx, key, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return c.Create(ctx, desired)
+	}
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	desired.SetUID(existing.GetUID())
+	return c.Update(ctx, desired)
+}
+
+// portableMeta returns the supplied object metadata without the fields that
+// are maintained by the API server and other controllers.
+func portableMeta(om metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        om.Name,
+		Namespace:   om.Namespace,
+		Labels:      om.Labels,
+		Annotations: om.Annotations,
+	}
+}
+
+func unmarshalWorkload(raw runtime.RawExtension) (*unstructured.Unstructured, error) {
+	if raw.Object != nil {
+		return util.Object2Unstructured(raw.Object)
+	}
+	w := &unstructured.Unstructured{}
+	if len(raw.Raw) == 0 {
+		return w, nil
+	}
+	return w, json.Unmarshal(raw.Raw, w)
+}
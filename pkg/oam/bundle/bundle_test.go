@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func newClient(t *testing.T, objs ...runtime.Object) client.Client {
+	s := runtime.NewScheme()
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatalf("core.AddToScheme(...): %v", err)
+	}
+	return fake.NewFakeClientWithScheme(s, objs...)
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	comp := &v1alpha2.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "comp", Namespace: "staging", UID: "staging-uid", Generation: 3},
+		Spec: v1alpha2.ComponentSpec{
+			Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.com/v1","kind":"Foo","spec":{"image":"nginx:1.19"}}`)},
+			AuxiliaryWorkloads: []v1alpha2.AuxiliaryWorkload{{
+				Name:     "config",
+				Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)},
+			}},
+			Parameters: []v1alpha2.ComponentParameter{{Name: "image", FieldPaths: []string{"spec.image"}}},
+		},
+		Status: v1alpha2.ComponentStatus{LatestRevision: &v1alpha2.Revision{Name: "comp-v3", Revision: 3}},
+	}
+	wd := &v1alpha2.WorkloadDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com", ResourceVersion: "42"},
+		Spec:       v1alpha2.WorkloadDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "foos.example.com"}},
+	}
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = mock.NewMockRESTMapping("foos")
+
+	b, err := Export(ctx, newClient(t, comp, wd), dm, types.NamespacedName{Namespace: "staging", Name: "comp"})
+	if err != nil {
+		t.Fatalf("Export(...): %v", err)
+	}
+	if b.RevisionHash != util.ComputeComponentRevisionHash(comp) {
+		t.Errorf("Export(...): want revision hash %q, got %q", util.ComputeComponentRevisionHash(comp), b.RevisionHash)
+	}
+	if b.Component.GetUID() != "" || b.Component.Status.LatestRevision != nil {
+		t.Errorf("Export(...): cluster specific metadata and status of the component should not be exported")
+	}
+	if len(b.WorkloadDefinitions) != 1 || b.WorkloadDefinitions[0].GetName() != wd.GetName() {
+		t.Errorf("Export(...): want only workload definition %q, got %v", wd.GetName(), b.WorkloadDefinitions)
+	}
+
+	data, err := Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal(...): %v", err)
+	}
+	imported, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal(...): %v", err)
+	}
+
+	c := newClient(t)
+	if err := Import(ctx, c, imported, "prod"); err != nil {
+		t.Fatalf("Import(...): %v", err)
+	}
+	got := &v1alpha2.Component{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "prod", Name: "comp"}, got); err != nil {
+		t.Fatalf("c.Get(...): %v", err)
+	}
+	if h := util.ComputeComponentRevisionHash(got); h != b.RevisionHash {
+		t.Errorf("Import(...): want revision hash %q, got %q", b.RevisionHash, h)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: wd.GetName()}, &v1alpha2.WorkloadDefinition{}); err != nil {
+		t.Errorf("Import(...): workload definition was not imported: %v", err)
+	}
+
+	// importing the same bundle again updates the existing objects
+	if err := Import(ctx, c, imported, "prod"); err != nil {
+		t.Errorf("Import(...): want no error re-importing a bundle, got %v", err)
+	}
+}
+
+func TestUnmarshalModifiedBundle(t *testing.T) {
+	b := &Bundle{
+		TypeMeta: metav1.TypeMeta{APIVersion: APIVersion, Kind: Kind},
+		Component: v1alpha2.Component{
+			ObjectMeta: metav1.ObjectMeta{Name: "comp"},
+			Spec:       v1alpha2.ComponentSpec{Workload: runtime.RawExtension{Raw: []byte(`{"kind":"Foo","spec":{"replicas":1}}`)}},
+		},
+	}
+	b.RevisionHash = util.ComputeComponentRevisionHash(&b.Component)
+	data, err := Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal(...): %v", err)
+	}
+	modified := strings.Replace(string(data), "replicas: 1", "replicas: 2", 1)
+	if modified == string(data) {
+		t.Fatalf("Marshal(...): unexpected bundle:\n%s", data)
+	}
+	if _, err := Unmarshal([]byte(modified)); err == nil {
+		t.Errorf("Unmarshal(...): want error for a modified bundle, got nil")
+	}
+	if _, err := Unmarshal([]byte("kind: Foo")); err == nil {
+		t.Errorf("Unmarshal(...): want error for a bundle of the wrong type, got nil")
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definition caches WorkloadDefinitions, TraitDefinitions and
+// ScopeDefinitions in memory, so that reconcilers and admission handlers that
+// read them for every workload, trait and scope share a single copy that is
+// kept up to date by informers.
+package definition
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	errFmtGetInformer   = "cannot get informer of %s"
+	errFmtNotDefinition = "%T is not a definition"
+	errFmtListCatalogs  = "cannot list DefinitionCatalogs of namespace %q"
+)
+
+// A store of the definitions of one kind, keyed by name.
+type store struct {
+	resource schema.GroupResource
+	synced   func() bool
+	objects  map[string]runtime.Object
+
+	// refs are the resources the definitions refer to, keyed by name.
+	refs map[string]string
+}
+
+// A Cache is a client.Client that reads WorkloadDefinitions,
+// TraitDefinitions and ScopeDefinitions from memory, and everything else
+// through the client it wraps. Definitions are added, updated and removed by
+// informers as they change, and are read through the wrapped client until the
+// informers have synced.
+type Cache struct {
+	client.Client
+
+	mu     sync.RWMutex
+	stores map[reflect.Type]*store
+	kinds  map[string]*store
+}
+
+// NewCache returns a Cache of the definitions watched by the supplied
+// informers, e.g. the cache of a manager, that wraps the supplied client.
+func NewCache(ctx context.Context, i cache.Informers, c client.Client) (*Cache, error) {
+	dc := &Cache{Client: c, stores: map[reflect.Type]*store{}, kinds: map[string]*store{}}
+	for _, k := range []struct {
+		kind     string
+		obj      runtime.Object
+		list     runtime.Object
+		resource string
+	}{
+		{v1alpha2.WorkloadDefinitionKind, &v1alpha2.WorkloadDefinition{}, &v1alpha2.WorkloadDefinitionList{}, "workloaddefinitions"},
+		{v1alpha2.TraitDefinitionKind, &v1alpha2.TraitDefinition{}, &v1alpha2.TraitDefinitionList{}, "traitdefinitions"},
+		{v1alpha2.ScopeDefinitionKind, &v1alpha2.ScopeDefinition{}, &v1alpha2.ScopeDefinitionList{}, "scopedefinitions"},
+	} {
+		inf, err := i.GetInformer(ctx, k.obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetInformer, k.resource)
+		}
+		s := &store{
+			resource: schema.GroupResource{Group: v1alpha2.Group, Resource: k.resource},
+			synced:   inf.HasSynced,
+			objects:  map[string]runtime.Object{},
+			refs:     map[string]string{},
+		}
+		dc.kinds[k.kind] = s
+		dc.stores[reflect.TypeOf(k.obj)] = s
+		dc.stores[reflect.TypeOf(k.list)] = s
+		inf.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { dc.set(s, obj) },
+			UpdateFunc: func(_, obj interface{}) { dc.set(s, obj) },
+			DeleteFunc: func(obj interface{}) { dc.delete(s, obj) },
+		})
+	}
+	return dc, nil
+}
+
+func (c *Cache) set(s *store, obj interface{}) {
+	o, ok := obj.(runtime.Object)
+	if !ok {
+		return
+	}
+	m, err := meta.Accessor(o)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s.objects[m.GetName()] = o
+	s.refs[m.GetName()] = reference(o)
+}
+
+// reference returns the resource the supplied definition refers to.
+func reference(o runtime.Object) string {
+	switch d := o.(type) {
+	case *v1alpha2.WorkloadDefinition:
+		return d.Spec.Reference.Name
+	case *v1alpha2.TraitDefinition:
+		return d.Spec.Reference.Name
+	case *v1alpha2.ScopeDefinition:
+		return d.Spec.Reference.Name
+	}
+	return ""
+}
+
+func (c *Cache) delete(s *store, obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(s.objects, m.GetName())
+	delete(s.refs, m.GetName())
+}
+
+// storeFor returns the store of the supplied object or list, or nil if it is
+// not a definition or its informer has not synced yet.
+func (c *Cache) storeFor(obj runtime.Object) *store {
+	s, ok := c.stores[reflect.TypeOf(obj)]
+	if !ok || !s.synced() {
+		return nil
+	}
+	return s
+}
+
+// Get the definition of the supplied key from memory. Other objects are read
+// through the wrapped client.
+func (c *Cache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	s := c.storeFor(obj)
+	if s == nil {
+		return c.Client.Get(ctx, key, obj)
+	}
+	c.mu.RLock()
+	o, ok := s.objects[key.Name]
+	c.mu.RUnlock()
+	if !ok {
+		return kerrors.NewNotFound(s.resource, key.Name)
+	}
+	// callers may modify the object they read, but not the cached one
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(o.DeepCopyObject()).Elem())
+	return nil
+}
+
+// List the definitions matching the supplied label selector from memory,
+// sorted by name. Other objects, and definitions listed by field selector,
+// are listed through the wrapped client.
+func (c *Cache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	lo := &client.ListOptions{}
+	lo.ApplyOptions(opts)
+	s := c.storeFor(list)
+	if s == nil || lo.FieldSelector != nil {
+		return c.Client.List(ctx, list, opts...)
+	}
+	c.mu.RLock()
+	names := make([]string, 0, len(s.objects))
+	for name, o := range s.objects {
+		if lo.LabelSelector != nil {
+			m, err := meta.Accessor(o)
+			if err != nil || !lo.LabelSelector.Matches(labels.Set(m.GetLabels())) {
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]runtime.Object, 0, len(names))
+	for _, name := range names {
+		items = append(items, s.objects[name].DeepCopyObject())
+	}
+	c.mu.RUnlock()
+	return meta.SetList(list, items)
+}
+
+var _ util.NamespacedDefinitionGetter = &Cache{}
+
+// GetNamespacedDefinition gets the definition of the supplied name that is
+// registered by the DefinitionCatalogs of the supplied namespace. Catalogs are
+// read through the wrapped client, in the order of their names.
+func (c *Cache) GetNamespacedDefinition(ctx context.Context, namespace, name string, obj runtime.Object) error {
+	s, ok := c.stores[reflect.TypeOf(obj)]
+	if !ok {
+		return errors.Errorf(errFmtNotDefinition, obj)
+	}
+	l := &v1alpha2.DefinitionCatalogList{}
+	err := c.Client.List(ctx, l, client.InNamespace(namespace))
+	if meta.IsNoMatchError(err) {
+		// there are no catalogs if their CRD is not installed
+		return kerrors.NewNotFound(s.resource, name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, errFmtListCatalogs, namespace)
+	}
+	sort.Slice(l.Items, func(i, j int) bool { return l.Items[i].GetName() < l.Items[j].GetName() })
+	for _, cat := range l.Items {
+		if catalogDefinition(cat.Spec, name, obj) {
+			if m, err := meta.Accessor(obj); err == nil {
+				m.SetName(name)
+				m.SetNamespace(namespace)
+			}
+			return nil
+		}
+	}
+	return kerrors.NewNotFound(s.resource, name)
+}
+
+// catalogDefinition sets the spec of the supplied definition to that of the
+// definition of the supplied name in the supplied catalog, and returns false
+// if the catalog has none.
+func catalogDefinition(cat v1alpha2.DefinitionCatalogSpec, name string, obj runtime.Object) bool {
+	switch d := obj.(type) {
+	case *v1alpha2.WorkloadDefinition:
+		for _, e := range cat.WorkloadDefinitions {
+			if e.Name == name {
+				*d = v1alpha2.WorkloadDefinition{Spec: *e.Spec.DeepCopy()}
+				return true
+			}
+		}
+	case *v1alpha2.TraitDefinition:
+		for _, e := range cat.TraitDefinitions {
+			if e.Name == name {
+				*d = v1alpha2.TraitDefinition{Spec: *e.Spec.DeepCopy()}
+				return true
+			}
+		}
+	case *v1alpha2.ScopeDefinition:
+		for _, e := range cat.ScopeDefinitions {
+			if e.Name == name {
+				*d = v1alpha2.ScopeDefinition{Spec: *e.Spec.DeepCopy()}
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var _ util.DefinitionFinder = &Cache{}
+
+// FindDefinitions returns the names of the definitions of the supplied kind,
+// e.g. TraitDefinition, that refer to the supplied resource, sorted by name.
+// It returns nil until the informer of the kind has synced.
+func (c *Cache) FindDefinitions(kind, resource string) []string {
+	s, ok := c.kinds[kind]
+	if !ok || !s.synced() {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var names []string
+	for name, ref := range s.refs {
+		if ref == resource {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestCache(t *testing.T) {
+	errBoom := errors.New("boom")
+	s := runtime.NewScheme()
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	informers := &informertest.FakeInformers{Scheme: s}
+	wrapped := &test.MockClient{
+		MockGet:  test.NewMockGetFn(errBoom),
+		MockList: test.NewMockListFn(errBoom),
+	}
+	c, err := NewCache(context.Background(), informers, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := func(obj runtime.Object) *controllertest.FakeInformer {
+		i, err := informers.FakeInformerFor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i
+	}
+	td := func(name string, labels map[string]string) *v1alpha2.TraitDefinition {
+		return &v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	// definitions are read through the wrapped client until their informer
+	// has synced
+	got := &v1alpha2.TraitDefinition{}
+	if diff := cmp.Diff(errBoom, c.Get(context.Background(), types.NamespacedName{Name: "scaler"}, got), test.EquateErrors()); diff != "" {
+		t.Errorf("Get before sync: -want error, +got error:\n%s", diff)
+	}
+
+	traits := fake(&v1alpha2.TraitDefinition{})
+	traits.Synced = true
+	traits.Add(td("scaler", nil))
+	traits.Add(td("route", map[string]string{"tier": "web"}))
+
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "scaler"}, got); err != nil {
+		t.Errorf("Get: %v", err)
+	}
+	if diff := cmp.Diff(td("scaler", nil), got); diff != "" {
+		t.Errorf("Get: -want, +got:\n%s", diff)
+	}
+
+	traits.Update(td("scaler", nil), td("scaler", map[string]string{"tier": "web"}))
+	l := &v1alpha2.TraitDefinitionList{}
+	if err := c.List(context.Background(), l, client.MatchingLabels{"tier": "web"}); err != nil {
+		t.Errorf("List: %v", err)
+	}
+	want := []v1alpha2.TraitDefinition{*td("route", map[string]string{"tier": "web"}), *td("scaler", map[string]string{"tier": "web"})}
+	if diff := cmp.Diff(want, l.Items); diff != "" {
+		t.Errorf("List: -want, +got:\n%s", diff)
+	}
+
+	traits.Delete(td("scaler", nil))
+	err = c.Get(context.Background(), types.NamespacedName{Name: "scaler"}, got)
+	wantErr := kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "traitdefinitions"}, "scaler")
+	if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+		t.Errorf("Get deleted: -want error, +got error:\n%s", diff)
+	}
+
+	// other objects, and definitions listed by field, are read through the
+	// wrapped client
+	if diff := cmp.Diff(errBoom, c.Get(context.Background(), types.NamespacedName{Name: "comp"}, &v1alpha2.Component{}), test.EquateErrors()); diff != "" {
+		t.Errorf("Get component: -want error, +got error:\n%s", diff)
+	}
+	if diff := cmp.Diff(errBoom, c.List(context.Background(), l, client.MatchingFields{"metadata.name": "route"}), test.EquateErrors()); diff != "" {
+		t.Errorf("List by field: -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestCacheFindDefinitions(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	informers := &informertest.FakeInformers{Scheme: s}
+	c, err := NewCache(context.Background(), informers, &test.MockClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workloads, err := informers.FakeInformerFor(&v1alpha2.WorkloadDefinition{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd := func(name, resource string) *v1alpha2.WorkloadDefinition {
+		return &v1alpha2.WorkloadDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha2.WorkloadDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: resource}},
+		}
+	}
+	workloads.Add(wd("worker", "deployments.apps"))
+	workloads.Add(wd("webservice", "deployments.apps"))
+	workloads.Add(wd("statefulsets.apps", "statefulsets.apps"))
+
+	if got := c.FindDefinitions(v1alpha2.WorkloadDefinitionKind, "deployments.apps"); got != nil {
+		t.Errorf("FindDefinitions before sync: want nil, got %v", got)
+	}
+	workloads.Synced = true
+	want := []string{"webservice", "worker"}
+	if diff := cmp.Diff(want, c.FindDefinitions(v1alpha2.WorkloadDefinitionKind, "deployments.apps")); diff != "" {
+		t.Errorf("FindDefinitions: -want, +got:\n%s", diff)
+	}
+
+	workloads.Update(wd("worker", "deployments.apps"), wd("worker", "statefulsets.apps"))
+	workloads.Delete(wd("webservice", "deployments.apps"))
+	if got := c.FindDefinitions(v1alpha2.WorkloadDefinitionKind, "deployments.apps"); got != nil {
+		t.Errorf("FindDefinitions after updates: want nil, got %v", got)
+	}
+	want = []string{"statefulsets.apps", "worker"}
+	if diff := cmp.Diff(want, c.FindDefinitions(v1alpha2.WorkloadDefinitionKind, "statefulsets.apps")); diff != "" {
+		t.Errorf("FindDefinitions after updates: -want, +got:\n%s", diff)
+	}
+}
+
+func TestCacheGetNamespacedDefinition(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	catalog := func(name, definition, resource string) v1alpha2.DefinitionCatalog {
+		return v1alpha2.DefinitionCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+			Spec: v1alpha2.DefinitionCatalogSpec{TraitDefinitions: []v1alpha2.CatalogTraitDefinition{{
+				Name: definition,
+				Spec: v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: resource}},
+			}}},
+		}
+	}
+	var namespace string
+	wrapped := &test.MockClient{MockList: func(_ context.Context, list runtime.Object, opts ...client.ListOption) error {
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		namespace = lo.Namespace
+		// the first catalog by name wins, whatever the order they are listed in
+		list.(*v1alpha2.DefinitionCatalogList).Items = []v1alpha2.DefinitionCatalog{
+			catalog("b", "scaler", "autoscalers.example.com"),
+			catalog("a", "scaler", "manualscalertraits.core.oam.dev"),
+		}
+		return nil
+	}}
+	c, err := NewCache(context.Background(), &informertest.FakeInformers{Scheme: s}, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &v1alpha2.TraitDefinition{}
+	if err := c.GetNamespacedDefinition(context.Background(), "team", "scaler", got); err != nil {
+		t.Errorf("GetNamespacedDefinition: %v", err)
+	}
+	want := &v1alpha2.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "scaler", Namespace: "team"},
+		Spec:       v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "manualscalertraits.core.oam.dev"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetNamespacedDefinition: -want, +got:\n%s", diff)
+	}
+	if namespace != "team" {
+		t.Errorf("GetNamespacedDefinition: want catalogs of namespace %q, got %q", "team", namespace)
+	}
+
+	err = c.GetNamespacedDefinition(context.Background(), "team", "route", got)
+	wantErr := kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "traitdefinitions"}, "route")
+	if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+		t.Errorf("GetNamespacedDefinition not registered: -want error, +got error:\n%s", diff)
+	}
+	err = c.GetNamespacedDefinition(context.Background(), "team", "scaler", &v1alpha2.WorkloadDefinition{})
+	wantErr = kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "workloaddefinitions"}, "scaler")
+	if diff := cmp.Diff(wantErr, err, test.EquateErrors()); diff != "" {
+		t.Errorf("GetNamespacedDefinition of other kind: -want error, +got error:\n%s", diff)
+	}
+}
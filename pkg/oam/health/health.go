@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health evaluates the health policies of definitions against the
+// resources of their kind, so that the runtime can report the health of
+// workloads and traits without a bespoke controller for each kind.
+package health
+
+import (
+	"fmt"
+
+	"cuelang.org/go/cue"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	// ResourceField is the field of a CUE health policy that is filled with
+	// the resource being evaluated.
+	ResourceField = "resource"
+	// HealthyField is the field of a CUE health policy that is true if the
+	// resource is healthy.
+	HealthyField = "healthy"
+	// MessageField is the optional field of a CUE health policy that
+	// describes the health of the resource.
+	MessageField = "message"
+)
+
+const (
+	errCompileCUE         = "cannot compile CUE health policy"
+	errFillResource       = "cannot fill the resource into CUE health policy"
+	errEvaluateHealthy    = "cannot evaluate healthy of CUE health policy"
+	errEvaluateMessage    = "cannot evaluate message of CUE health policy"
+	errFmtGetField        = "cannot get %s"
+	errFmtUnknownOperator = "unknown health condition operator %q"
+
+	reasonFmtNotSet           = "%s is not set"
+	reasonFmtNotEqual         = "%s is %v, not %s"
+	reasonFmtEqual            = "%s is %s"
+	reasonFmtFieldsNotEqual   = "%s is %v, not %v like %s"
+	reasonFmtNotConditions    = "%s is not a list of conditions"
+	reasonFmtConditionNotTrue = "condition %s of %s is not True"
+	reasonCUEUnhealthy        = "CUE health policy is not met"
+)
+
+// Evaluate the supplied health policy against the supplied resource. It
+// returns the health status of the resource and a diagnosis. The health of a
+// resource whose policy can't be evaluated is unknown.
+func Evaluate(p *v1alpha2.HealthPolicy, u *unstructured.Unstructured) (v1alpha2.HealthStatus, string) {
+	if p == nil {
+		return v1alpha2.StatusUnknown, ""
+	}
+	paved := fieldpath.Pave(u.UnstructuredContent())
+	for _, c := range p.Conditions {
+		met, diagnosis, err := evaluateCondition(paved, c)
+		if err != nil {
+			return v1alpha2.StatusUnknown, err.Error()
+		}
+		if !met {
+			return v1alpha2.StatusUnhealthy, diagnosis
+		}
+	}
+	if p.CUE == "" {
+		return v1alpha2.StatusHealthy, ""
+	}
+	healthy, message, err := evaluateCUE(p.CUE, u)
+	if err != nil {
+		return v1alpha2.StatusUnknown, err.Error()
+	}
+	if !healthy {
+		if message == "" {
+			message = reasonCUEUnhealthy
+		}
+		return v1alpha2.StatusUnhealthy, message
+	}
+	return v1alpha2.StatusHealthy, message
+}
+
+// evaluateCondition returns whether the supplied resource meets the supplied
+// condition, and why not.
+func evaluateCondition(p *fieldpath.Paved, c v1alpha2.HealthCondition) (bool, string, error) {
+	v, err := p.GetValue(c.FieldPath)
+	if fieldpath.IsNotFound(err) {
+		return false, fmt.Sprintf(reasonFmtNotSet, c.FieldPath), nil
+	}
+	if err != nil {
+		return false, "", errors.Wrapf(err, errFmtGetField, c.FieldPath)
+	}
+	switch c.Operator {
+	case v1alpha2.HealthConditionExists:
+		return true, "", nil
+	case v1alpha2.HealthConditionEqual, "":
+		if fmt.Sprint(v) != c.Value {
+			return false, fmt.Sprintf(reasonFmtNotEqual, c.FieldPath, v, c.Value), nil
+		}
+		return true, "", nil
+	case v1alpha2.HealthConditionNotEqual:
+		if fmt.Sprint(v) == c.Value {
+			return false, fmt.Sprintf(reasonFmtEqual, c.FieldPath, c.Value), nil
+		}
+		return true, "", nil
+	case v1alpha2.HealthConditionEqualField:
+		other, err := p.GetValue(c.Value)
+		if fieldpath.IsNotFound(err) {
+			return false, fmt.Sprintf(reasonFmtNotSet, c.Value), nil
+		}
+		if err != nil {
+			return false, "", errors.Wrapf(err, errFmtGetField, c.Value)
+		}
+		if fmt.Sprint(v) != fmt.Sprint(other) {
+			return false, fmt.Sprintf(reasonFmtFieldsNotEqual, c.FieldPath, v, other, c.Value), nil
+		}
+		return true, "", nil
+	case v1alpha2.HealthConditionTrue:
+		conditions, ok := v.([]interface{})
+		if !ok {
+			return false, fmt.Sprintf(reasonFmtNotConditions, c.FieldPath), nil
+		}
+		for _, item := range conditions {
+			cond, ok := item.(map[string]interface{})
+			if ok && cond["type"] == c.Value && cond["status"] == "True" {
+				return true, "", nil
+			}
+		}
+		return false, fmt.Sprintf(reasonFmtConditionNotTrue, c.Value, c.FieldPath), nil
+	}
+	return false, "", errors.Errorf(errFmtUnknownOperator, c.Operator)
+}
+
+// evaluateCUE returns whether the supplied resource is healthy according to
+// the supplied CUE health policy, and the message of the policy. The policy
+// need not declare the resource.
+func evaluateCUE(source string, u *unstructured.Unstructured) (bool, string, error) {
+	var r cue.Runtime
+	inst, err := r.Compile("health", source+"\n"+ResourceField+": _\n")
+	if err != nil {
+		return false, "", errors.Wrap(err, errCompileCUE)
+	}
+	if inst, err = inst.Fill(u.Object, ResourceField); err != nil {
+		return false, "", errors.Wrap(err, errFillResource)
+	}
+	healthy, err := inst.Lookup(HealthyField).Bool()
+	if err != nil {
+		return false, "", errors.Wrap(err, errEvaluateHealthy)
+	}
+	var message string
+	if m := inst.Lookup(MessageField); m.Exists() {
+		if message, err = m.String(); err != nil {
+			return false, "", errors.Wrap(err, errEvaluateMessage)
+		}
+	}
+	return healthy, message, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const readyReplicasPolicy = `
+healthy: resource.status.readyReplicas == resource.spec.replicas
+message: "Ready: \(resource.status.readyReplicas)/\(resource.spec.replicas)"
+`
+
+func deployment(replicas, readyReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec":       map[string]interface{}{"replicas": replicas},
+		"status": map[string]interface{}{
+			"readyReplicas": readyReplicas,
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": "Progressing", "status": "False"},
+			},
+		},
+	}}
+}
+
+func TestEvaluate(t *testing.T) {
+	type want struct {
+		status    v1alpha2.HealthStatus
+		diagnosis string
+	}
+
+	cases := map[string]struct {
+		reason string
+		policy *v1alpha2.HealthPolicy
+		u      *unstructured.Unstructured
+		want   want
+	}{
+		"NoPolicy": {
+			reason: "The health of a resource without health policy is unknown",
+			u:      deployment(3, 3),
+			want:   want{status: v1alpha2.StatusUnknown},
+		},
+		"ConditionsMet": {
+			reason: "A resource that meets all conditions is healthy",
+			policy: &v1alpha2.HealthPolicy{Conditions: []v1alpha2.HealthCondition{
+				{FieldPath: "status.readyReplicas", Value: "3"},
+				{FieldPath: "status.readyReplicas", Operator: v1alpha2.HealthConditionEqualField, Value: "spec.replicas"},
+				{FieldPath: "status.conditions", Operator: v1alpha2.HealthConditionTrue, Value: "Available"},
+				{FieldPath: "metadata.name", Operator: v1alpha2.HealthConditionNotEqual, Value: "db"},
+				{FieldPath: "status", Operator: v1alpha2.HealthConditionExists},
+			}},
+			u:    deployment(3, 3),
+			want: want{status: v1alpha2.StatusHealthy},
+		},
+		"FieldNotSet": {
+			reason: "A resource without the field of a condition is unhealthy",
+			policy: &v1alpha2.HealthPolicy{Conditions: []v1alpha2.HealthCondition{
+				{FieldPath: "status.phase", Operator: v1alpha2.HealthConditionExists},
+			}},
+			u:    deployment(3, 3),
+			want: want{status: v1alpha2.StatusUnhealthy, diagnosis: "status.phase is not set"},
+		},
+		"FieldsNotEqual": {
+			reason: "A resource whose fields differ is unhealthy",
+			policy: &v1alpha2.HealthPolicy{Conditions: []v1alpha2.HealthCondition{
+				{FieldPath: "status.readyReplicas", Operator: v1alpha2.HealthConditionEqualField, Value: "spec.replicas"},
+			}},
+			u:    deployment(3, 1),
+			want: want{status: v1alpha2.StatusUnhealthy, diagnosis: "status.readyReplicas is 1, not 3 like spec.replicas"},
+		},
+		"ConditionNotTrue": {
+			reason: "A resource whose condition is not true is unhealthy",
+			policy: &v1alpha2.HealthPolicy{Conditions: []v1alpha2.HealthCondition{
+				{FieldPath: "status.conditions", Operator: v1alpha2.HealthConditionTrue, Value: "Progressing"},
+			}},
+			u:    deployment(3, 3),
+			want: want{status: v1alpha2.StatusUnhealthy, diagnosis: "condition Progressing of status.conditions is not True"},
+		},
+		"UnknownOperator": {
+			reason: "The health of a resource is unknown if its policy can't be evaluated",
+			policy: &v1alpha2.HealthPolicy{Conditions: []v1alpha2.HealthCondition{
+				{FieldPath: "status.readyReplicas", Operator: "GreaterThan", Value: "1"},
+			}},
+			u:    deployment(3, 3),
+			want: want{status: v1alpha2.StatusUnknown, diagnosis: `unknown health condition operator "GreaterThan"`},
+		},
+		"CUEHealthy": {
+			reason: "A resource that meets the CUE policy is healthy",
+			policy: &v1alpha2.HealthPolicy{CUE: readyReplicasPolicy},
+			u:      deployment(3, 3),
+			want:   want{status: v1alpha2.StatusHealthy, diagnosis: "Ready: 3/3"},
+		},
+		"CUEUnhealthy": {
+			reason: "A resource that doesn't meet the CUE policy is unhealthy",
+			policy: &v1alpha2.HealthPolicy{CUE: readyReplicasPolicy},
+			u:      deployment(3, 1),
+			want:   want{status: v1alpha2.StatusUnhealthy, diagnosis: "Ready: 1/3"},
+		},
+		"CUEWithoutMessage": {
+			reason: "A resource that doesn't meet a CUE policy without message is unhealthy",
+			policy: &v1alpha2.HealthPolicy{CUE: "healthy: resource.status.readyReplicas > 1"},
+			u:      deployment(3, 1),
+			want:   want{status: v1alpha2.StatusUnhealthy, diagnosis: reasonCUEUnhealthy},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status, diagnosis := Evaluate(tc.policy, tc.u)
+			if diff := cmp.Diff(tc.want, want{status: status, diagnosis: diagnosis}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
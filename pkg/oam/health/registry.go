@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const errFmtCheckerRegistered = "a health checker of %s is already registered"
+
+// A Checker checks the health of resources of a kind, e.g. by the conditions
+// a custom resource reports.
+type Checker interface {
+	// Check the health of the supplied resource. It returns the health
+	// status of the resource and a diagnosis.
+	Check(ctx context.Context, c client.Reader, u *unstructured.Unstructured) (v1alpha2.HealthStatus, string)
+}
+
+// A CheckFn checks the health of resources of a kind.
+type CheckFn func(ctx context.Context, c client.Reader, u *unstructured.Unstructured) (v1alpha2.HealthStatus, string)
+
+// Check the health of the supplied resource.
+func (fn CheckFn) Check(ctx context.Context, c client.Reader, u *unstructured.Unstructured) (v1alpha2.HealthStatus, string) {
+	return fn(ctx, c, u)
+}
+
+// A Registry of the Checkers of kinds of resources. Embedders of the runtime
+// register Checkers for the kinds of workloads they know how to check, which
+// take precedence over the checkers built into the runtime. It is safe for
+// concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	checkers map[schema.GroupVersionKind]Checker
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{checkers: map[schema.GroupVersionKind]Checker{}}
+}
+
+// Register the supplied Checker for resources of the supplied kind. A kind
+// has at most one Checker.
+func (r *Registry) Register(gvk schema.GroupVersionKind, c Checker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.checkers[gvk]; ok {
+		return errors.Errorf(errFmtCheckerRegistered, gvk)
+	}
+	r.checkers[gvk] = c
+	return nil
+}
+
+// Checker returns the Checker registered for resources of the supplied kind,
+// or nil if there is none.
+func (r *Registry) Checker(gvk schema.GroupVersionKind) Checker {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.checkers[gvk]
+}
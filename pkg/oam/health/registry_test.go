@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRegistry(t *testing.T) {
+	database := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Database"}
+	healthy := CheckFn(func(context.Context, client.Reader, *unstructured.Unstructured) (v1alpha2.HealthStatus, string) {
+		return v1alpha2.StatusHealthy, "healthy"
+	})
+
+	r := NewRegistry()
+	if err := r.Register(database, healthy); err != nil {
+		t.Errorf("r.Register(...): %s", err)
+	}
+	want := errors.Errorf(errFmtCheckerRegistered, database)
+	if diff := cmp.Diff(want, r.Register(database, healthy), test.EquateErrors()); diff != "" {
+		t.Errorf("r.Register(...): a kind should have at most one checker: -want error, +got error:\n%s\n", diff)
+	}
+
+	c := r.Checker(database)
+	if c == nil {
+		t.Fatalf("r.Checker(...): the checker of a registered kind should be returned")
+	}
+	if status, _ := c.Check(context.Background(), nil, &unstructured.Unstructured{}); status != v1alpha2.StatusHealthy {
+		t.Errorf("c.Check(...): want %s, got %s", v1alpha2.StatusHealthy, status)
+	}
+	if c := r.Checker(database.GroupVersion().WithKind("Cache")); c != nil {
+		t.Errorf("r.Checker(...): no checker should be returned for a kind that is not registered")
+	}
+	var nilRegistry *Registry
+	if c := nilRegistry.Checker(database); c != nil {
+		t.Errorf("nilRegistry.Checker(...): a nil registry should have no checkers")
+	}
+}
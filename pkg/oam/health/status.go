@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Conditions of the status of a resource that EvaluateStatus understands.
+// Stalled and Reconciling are abnormal-true conditions, i.e. the resource is
+// unhealthy while they are True, while Ready and Available are normal-true.
+const (
+	conditionStalled     = "Stalled"
+	conditionReconciling = "Reconciling"
+	conditionReady       = "Ready"
+	conditionAvailable   = "Available"
+)
+
+const (
+	reasonFmtGenerationNotObserved = "observedGeneration %d is behind generation %d"
+	reasonFmtStatusCondition       = "condition %s is %v"
+	reasonFmtConditionMessage      = "%s: %s"
+)
+
+// EvaluateStatus evaluates the health of the supplied resource by the
+// conventions of the status of Kubernetes resources, like kstatus does: a
+// resource is unhealthy while its controller has not observed its latest
+// generation, or while its Stalled or Reconciling condition is True, and it is
+// as healthy as its Ready, or else its Available, condition. It returns false
+// if the resource reports none of these conditions, in which case its health
+// can't be told.
+func EvaluateStatus(u *unstructured.Unstructured) (v1alpha2.HealthStatus, string, bool) {
+	conditions := map[string]map[string]interface{}{}
+	raw, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range raw {
+		if m, ok := c.(map[string]interface{}); ok {
+			if t, ok := m["type"].(string); ok {
+				conditions[t] = m
+			}
+		}
+	}
+	for _, t := range []string{conditionStalled, conditionReconciling} {
+		if c, ok := conditions[t]; ok && c["status"] == "True" {
+			return v1alpha2.StatusUnhealthy, withMessage(fmt.Sprintf(reasonFmtStatusCondition, t, c["status"]), c), true
+		}
+	}
+	for _, t := range []string{conditionReady, conditionAvailable} {
+		c, ok := conditions[t]
+		if !ok {
+			continue
+		}
+		if observed, ok, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration"); ok && observed < u.GetGeneration() {
+			return v1alpha2.StatusUnhealthy, fmt.Sprintf(reasonFmtGenerationNotObserved, observed, u.GetGeneration()), true
+		}
+		diagnosis := withMessage(fmt.Sprintf(reasonFmtStatusCondition, t, c["status"]), c)
+		if c["status"] != "True" {
+			return v1alpha2.StatusUnhealthy, diagnosis, true
+		}
+		return v1alpha2.StatusHealthy, diagnosis, true
+	}
+	return v1alpha2.StatusUnknown, "", false
+}
+
+// withMessage appends the message of the supplied condition, if any, to the
+// supplied diagnosis.
+func withMessage(diagnosis string, c map[string]interface{}) string {
+	if m, ok := c["message"].(string); ok && m != "" {
+		return fmt.Sprintf(reasonFmtConditionMessage, diagnosis, m)
+	}
+	return diagnosis
+}
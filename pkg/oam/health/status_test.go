@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestEvaluateStatus(t *testing.T) {
+	type want struct {
+		status    v1alpha2.HealthStatus
+		diagnosis string
+		ok        bool
+	}
+	resource := func(generation, observedGeneration int64, conditions ...map[string]interface{}) *unstructured.Unstructured {
+		cs := make([]interface{}, 0, len(conditions))
+		for _, c := range conditions {
+			cs = append(cs, c)
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Database",
+			"metadata":   map[string]interface{}{"name": "db", "generation": generation},
+			"status":     map[string]interface{}{"observedGeneration": observedGeneration, "conditions": cs},
+		}}
+	}
+	condition := func(t, status, message string) map[string]interface{} {
+		return map[string]interface{}{"type": t, "status": status, "message": message}
+	}
+
+	cases := map[string]struct {
+		reason string
+		u      *unstructured.Unstructured
+		want   want
+	}{
+		"Ready": {
+			reason: "A resource whose Ready condition is True should be healthy",
+			u:      resource(2, 2, condition("Ready", "True", "")),
+			want:   want{status: v1alpha2.StatusHealthy, diagnosis: "condition Ready is True", ok: true},
+		},
+		"NotReady": {
+			reason: "A resource whose Ready condition is not True should be unhealthy",
+			u:      resource(2, 2, condition("Ready", "False", "waiting for volume")),
+			want:   want{status: v1alpha2.StatusUnhealthy, diagnosis: "condition Ready is False: waiting for volume", ok: true},
+		},
+		"Available": {
+			reason: "The Available condition should be evaluated if there is no Ready condition",
+			u:      resource(2, 2, condition("Available", "True", ""), condition("Progressing", "False", "")),
+			want:   want{status: v1alpha2.StatusHealthy, diagnosis: "condition Available is True", ok: true},
+		},
+		"Stalled": {
+			reason: "A resource whose Stalled condition is True should be unhealthy even if it is Ready",
+			u:      resource(2, 2, condition("Ready", "True", ""), condition("Stalled", "True", "quota exceeded")),
+			want:   want{status: v1alpha2.StatusUnhealthy, diagnosis: "condition Stalled is True: quota exceeded", ok: true},
+		},
+		"GenerationNotObserved": {
+			reason: "A resource whose latest generation was not observed should be unhealthy",
+			u:      resource(3, 2, condition("Ready", "True", "")),
+			want:   want{status: v1alpha2.StatusUnhealthy, diagnosis: "observedGeneration 2 is behind generation 3", ok: true},
+		},
+		"NoConditions": {
+			reason: "The health of a resource without known conditions can't be told",
+			u:      resource(1, 1, condition("Progressing", "True", "")),
+			want:   want{status: v1alpha2.StatusUnknown},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			status, diagnosis, ok := EvaluateStatus(tc.u)
+			if diff := cmp.Diff(tc.want, want{status: status, diagnosis: diagnosis, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nEvaluateStatus(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -25,6 +25,10 @@ const (
 	LabelAppComponent = "app.oam.dev/component"
 	// LabelAppComponentRevision records the revision name of Component
 	LabelAppComponentRevision = "app.oam.dev/revision"
+	// LabelAppAuxiliaryWorkload records the name of an auxiliary workload within its Component
+	LabelAppAuxiliaryWorkload = "app.oam.dev/auxiliary-workload"
+	// LabelAppTrait records the name of the TraitDefinition of a trait
+	LabelAppTrait = "app.oam.dev/trait"
 	// LabelOAMResourceType whether a CR is workload or trait
 	LabelOAMResourceType = "app.oam.dev/resourceType"
 
@@ -32,6 +36,71 @@ const (
 	WorkloadTypeLabel = "workload.oam.dev/type"
 	// TraitTypeLabel indicates the type of the traitDefinition
 	TraitTypeLabel = "trait.oam.dev/type"
+
+	// LabelWorkloadDefinition opts a CustomResourceDefinition in to the
+	// generation of a WorkloadDefinition of its kind, if its value is "true"
+	LabelWorkloadDefinition = "oam.dev/workload"
+
+	// LabelDefinitionKind records the kind of the definition a
+	// DefinitionRevision snapshots
+	LabelDefinitionKind = "definition.oam.dev/kind"
+	// LabelDefinitionName records the name of the definition a
+	// DefinitionRevision snapshots
+	LabelDefinitionName = "definition.oam.dev/name"
+)
+
+// Annotation key strings.
+const (
+	// AnnotationRevisionHash records the hash of the Component revision a
+	// workload is rendered from
+	AnnotationRevisionHash = "app.oam.dev/revision-hash"
+
+	// AnnotationRenderDiff enables computing the difference between the applied
+	// and the rendered resources of an AppConfig. If its value is "true" the
+	// difference is computed before the rendered resources are applied, if its
+	// value is "dry-run" the rendered resources are not applied at all.
+	AnnotationRenderDiff = "app.oam.dev/render-diff"
+
+	// AnnotationPinRevisions enables pinning the components of an AppConfig
+	// to the revisions that are their latest when they are first admitted
+	// with the AppConfig.
+	// If its value is "true" the pinned revisions are recorded in the
+	// AnnotationPinnedRevisions annotation.
+	AnnotationPinRevisions = "app.oam.dev/pin-revisions"
+
+	// AnnotationPinnedRevisions records the revisions the components of an
+	// AppConfig are pinned to, as a JSON object mapping component names to
+	// revision names.
+	AnnotationPinnedRevisions = "app.oam.dev/pinned-revisions"
+
+	// AnnotationChildResourceKinds records the childResourceKinds of the
+	// WorkloadDefinition generated from a CustomResourceDefinition, as a JSON
+	// array, e.g. [{"apiVersion":"apps/v1","kind":"Deployment"}].
+	AnnotationChildResourceKinds = "oam.dev/child-resource-kinds"
+
+	// AnnotationManagedWorkload records the rendered workload a trait whose
+	// TraitDefinition sets manageWorkload creates and updates, as JSON. The
+	// AppConfig controller does not apply the workload itself.
+	AnnotationManagedWorkload = "app.oam.dev/managed-workload"
+
+	// AnnotationScopeWorkloads records the workloads a scope reconciler has
+	// added to a scope, as a JSON array of typed references. Workloads that
+	// are recorded but no longer referenced by the scope are removed from it.
+	AnnotationScopeWorkloads = "scope.oam.dev/workloads"
+
+	// AnnotationScopeContributors records the AppConfigs that added each
+	// workload to a scope, as a JSON object mapping the apiVersion, kind and
+	// name of the workloads, separated by slashes, to the names of the
+	// AppConfigs. A workload is only removed from a scope when no AppConfig
+	// contributes it anymore.
+	AnnotationScopeContributors = "scope.oam.dev/contributors"
+)
+
+const (
+	// RenderDiffEnabled computes the render diff and applies the rendered resources
+	RenderDiffEnabled = "true"
+	// RenderDiffDryRun computes the render diff without applying the rendered resources
+	RenderDiffDryRun = "dry-run"
 )
 
 const (
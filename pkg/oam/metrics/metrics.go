@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exposes Prometheus metrics of the OAM admission webhook and
+// controllers. They are registered with the controller-runtime registry, and
+// thus served at the metrics endpoint of the manager.
+package metrics
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Labels of the metrics.
+const (
+	LabelHandler    = "handler"
+	LabelOperation  = "operation"
+	LabelResult     = "result"
+	LabelReason     = "reason"
+	LabelController = "controller"
+	LabelType       = "type"
+)
+
+// Results of admission requests.
+const (
+	ResultAllowed = "allowed"
+	ResultDenied  = "denied"
+	ResultErrored = "errored"
+)
+
+// maxReasonLength is the maximum length of a reason label.
+const maxReasonLength = 128
+
+var (
+	// AdmissionRequests counts the admission requests handled, by handler,
+	// operation and result.
+	AdmissionRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "oam",
+		Subsystem: "admission",
+		Name:      "requests_total",
+		Help:      "Total number of admission requests handled, by handler, operation and result.",
+	}, []string{LabelHandler, LabelOperation, LabelResult})
+
+	// AdmissionDuration observes how long admission requests take to be
+	// handled, by handler.
+	AdmissionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "oam",
+		Subsystem: "admission",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle admission requests, by handler.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{LabelHandler})
+
+	// AdmissionDenials counts the admission requests denied, by handler and
+	// reason.
+	AdmissionDenials = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "oam",
+		Subsystem: "admission",
+		Name:      "denials_total",
+		Help:      "Total number of admission requests denied, by handler and reason.",
+	}, []string{LabelHandler, LabelReason})
+
+	// ReconcileEvents counts the events controllers record while they
+	// reconcile, by controller, event type and reason.
+	ReconcileEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "oam",
+		Subsystem: "reconcile",
+		Name:      "events_total",
+		Help:      "Total number of events recorded by controllers, by controller, type and reason.",
+	}, []string{LabelController, LabelType, LabelReason})
+)
+
+func init() {
+	metrics.Registry.MustRegister(AdmissionRequests, AdmissionDuration, AdmissionDenials, ReconcileEvents)
+}
+
+// ObserveAdmission records an admission request of the supplied operation
+// that the named handler handled with the supplied result in the supplied
+// duration. The reason a request is denied for is recorded as returned by
+// Reason.
+func ObserveAdmission(handler, operation, result, reason string, d time.Duration) {
+	AdmissionRequests.WithLabelValues(handler, operation, result).Inc()
+	AdmissionDuration.WithLabelValues(handler).Observe(d.Seconds())
+	if result == ResultDenied {
+		AdmissionDenials.WithLabelValues(handler, Reason(reason)).Inc()
+	}
+}
+
+var (
+	quoted = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	index  = regexp.MustCompile(`\[\d+\]`)
+)
+
+// Reason returns the supplied reason an admission request was denied for
+// without the names, indexes and details it contains, so that requests
+// denied for the same reason share a label value. Quoted strings and indexes
+// are replaced by %q and [%d], and only the first sentence is kept.
+func Reason(reason string) string {
+	r := quoted.ReplaceAllString(reason, "%q")
+	r = index.ReplaceAllString(r, "[%d]")
+	if i := strings.Index(r, ". "); i >= 0 {
+		r = r[:i+1]
+	}
+	if len(r) > maxReasonLength {
+		r = r[:maxReasonLength]
+	}
+	return r
+}
+
+// A recorder counts the events it records.
+type recorder struct {
+	event.Recorder
+	controller string
+}
+
+// NewRecorder returns an event.Recorder that counts the events the named
+// controller records before recording them with the supplied recorder.
+func NewRecorder(controller string, r event.Recorder) event.Recorder {
+	return &recorder{Recorder: r, controller: controller}
+}
+
+// Event counts and records the supplied event.
+func (r *recorder) Event(obj runtime.Object, e event.Event) {
+	ReconcileEvents.WithLabelValues(r.controller, string(e.Type), string(e.Reason)).Inc()
+	r.Recorder.Event(obj, e)
+}
+
+// WithAnnotations returns a recorder that counts the events it records and
+// includes the supplied annotations with them.
+func (r *recorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	return NewRecorder(r.controller, r.Recorder.WithAnnotations(keysAndValues...))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestReason(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		want   string
+	}{
+		"NamesAndIndexes": {
+			reason: `Trait "example.com/v1" "Foo" of spec.components[0].traits[12] MUST have a TraitDefinition.`,
+			want:   `Trait %q %q of spec.components[%d].traits[%d] MUST have a TraitDefinition.`,
+		},
+		"EscapedQuotes": {
+			reason: `ApplicationConfiguration MUST render successfully. "cannot render \"web\""`,
+			want:   `ApplicationConfiguration MUST render successfully.`,
+		},
+		"FirstSentence": {
+			reason: `ApplicationConfiguration MUST satisfy the admission policies. Deployment web violates policy "replicas": too many`,
+			want:   `ApplicationConfiguration MUST satisfy the admission policies.`,
+		},
+		"Truncated": {
+			reason: strings.Repeat("a", 2*maxReasonLength),
+			want:   strings.Repeat("a", maxReasonLength),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, Reason(tc.reason)); diff != "" {
+				t.Errorf("Reason(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveAdmission(t *testing.T) {
+	reason := `Component "web" of spec.components[1] MUST NOT be listed more than once, it is also listed by spec.components[0].`
+	ObserveAdmission("test-handler", "CREATE", ResultAllowed, "", time.Millisecond)
+	ObserveAdmission("test-handler", "CREATE", ResultDenied, reason, time.Millisecond)
+	ObserveAdmission("test-handler", "UPDATE", ResultErrored, "boom", time.Millisecond)
+
+	for _, r := range []string{ResultAllowed, ResultDenied} {
+		if got := testutil.ToFloat64(AdmissionRequests.WithLabelValues("test-handler", "CREATE", r)); got != 1 {
+			t.Errorf("AdmissionRequests %s: want 1, got %v", r, got)
+		}
+	}
+	if got := testutil.ToFloat64(AdmissionDenials.WithLabelValues("test-handler", Reason(reason))); got != 1 {
+		t.Errorf("AdmissionDenials: want 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(AdmissionDenials.WithLabelValues("test-handler", "boom")); got != 0 {
+		t.Errorf("AdmissionDenials: want errored requests not counted as denials, got %v", got)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder("test-controller", event.NewNopRecorder()).WithAnnotations("key", "value")
+	r.Event(nil, event.Warning("CannotRenderComponents", errors.New("boom")))
+	r.Event(nil, event.Normal("RenderedComponents", "rendered"))
+	r.Event(nil, event.Normal("RenderedComponents", "rendered"))
+
+	if got := testutil.ToFloat64(ReconcileEvents.WithLabelValues("test-controller", "Warning", "CannotRenderComponents")); got != 1 {
+		t.Errorf("ReconcileEvents: want 1 warning, got %v", got)
+	}
+	if got := testutil.ToFloat64(ReconcileEvents.WithLabelValues("test-controller", "Normal", "RenderedComponents")); got != 2 {
+		t.Errorf("ReconcileEvents: want 2 normal events, got %v", got)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration migrates the objects of a custom resource to the version
+// it is stored as.
+package migration
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errFmtGetCRD           = "cannot get CustomResourceDefinition %q"
+	errFmtNoStorageVersion = "CustomResourceDefinition %q has no storage version"
+	errFmtListObjects      = "cannot list %s"
+	errFmtUpdateObject     = "cannot update %s %q"
+	errFmtUpdateCRDStatus  = "cannot update stored versions of CustomResourceDefinition %q"
+)
+
+// DefaultPageSize is the number of objects listed at a time.
+const DefaultPageSize = 500
+
+// StorageVersion returns the version the objects of the supplied CRD are
+// stored as.
+func StorageVersion(crd *crdv1.CustomResourceDefinition) (string, bool) {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name, true
+		}
+	}
+	return "", false
+}
+
+// Migrate rewrites all objects of the named CRD so that they are stored as
+// its storage version, then records the storage version as the only version
+// the objects are stored as. Versions that are no longer stored may then be
+// removed from the CRD.
+//
+// Objects are rewritten by updating them unchanged. Objects that are deleted
+// or updated by someone else in the meantime are skipped, as they no longer
+// need to be rewritten.
+func Migrate(ctx context.Context, c client.Client, crdName string) error {
+	crd := &crdv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, types.NamespacedName{Name: crdName}, crd); err != nil {
+		return errors.Wrapf(err, errFmtGetCRD, crdName)
+	}
+	version, ok := StorageVersion(crd)
+	if !ok {
+		return errors.Errorf(errFmtNoStorageVersion, crdName)
+	}
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version, Kind: crd.Spec.Names.ListKind}
+
+	opts := []client.ListOption{client.Limit(DefaultPageSize)}
+	for {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk)
+		if err := c.List(ctx, l, opts...); err != nil {
+			return errors.Wrapf(err, errFmtListObjects, crd.Spec.Names.Plural)
+		}
+		for i := range l.Items {
+			o := &l.Items[i]
+			err := c.Update(ctx, o)
+			if err != nil && !kerrors.IsNotFound(err) && !kerrors.IsConflict(err) {
+				return errors.Wrapf(err, errFmtUpdateObject, crd.Spec.Names.Kind, o.GetName())
+			}
+		}
+		if l.GetContinue() == "" {
+			break
+		}
+		opts = []client.ListOption{client.Limit(DefaultPageSize), client.Continue(l.GetContinue())}
+	}
+
+	crd.Status.StoredVersions = []string{version}
+	return errors.Wrapf(c.Status().Update(ctx, crd), errFmtUpdateCRDStatus, crdName)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMigrate(t *testing.T) {
+	crdName := "foos.example.com"
+	errBoom := errors.New("boom")
+
+	crd := func(storedVersions ...string) *crdv1.CustomResourceDefinition {
+		return &crdv1.CustomResourceDefinition{
+			Spec: crdv1.CustomResourceDefinitionSpec{
+				Group: "example.com",
+				Names: crdv1.CustomResourceDefinitionNames{Kind: "Foo", ListKind: "FooList", Plural: "foos"},
+				Versions: []crdv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true},
+					{Name: "v1", Served: true, Storage: true},
+				},
+			},
+			Status: crdv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+		}
+	}
+	getCRD := test.NewMockGetFn(nil, func(obj runtime.Object) error {
+		crd("v1alpha1", "v1").DeepCopyInto(obj.(*crdv1.CustomResourceDefinition))
+		return nil
+	})
+	foo := func(name string) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetName(name)
+		return u
+	}
+	// list returns two pages of objects
+	list := func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+		l := obj.(*unstructured.UnstructuredList)
+		if l.GroupVersionKind() != (schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "FooList"}) {
+			return errors.Errorf("unexpected GroupVersionKind %s", l.GroupVersionKind())
+		}
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+		if lo.Continue == "" {
+			l.Items = []unstructured.Unstructured{foo("a"), foo("b")}
+			l.SetContinue("next")
+			return nil
+		}
+		l.Items = []unstructured.Unstructured{foo("c")}
+		return nil
+	}
+
+	type args struct {
+		c client.Client
+	}
+	type want struct {
+		err     error
+		updated []string
+		stored  []string
+	}
+	var updated []string
+	var stored []string
+	update := func(err error) test.MockUpdateFn {
+		return func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+			updated = append(updated, obj.(*unstructured.Unstructured).GetName())
+			return err
+		}
+	}
+	statusUpdate := func(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+		stored = obj.(*crdv1.CustomResourceDefinition).Status.StoredVersions
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Migrated": {
+			reason: "All objects should be updated and the storage version should be the only stored version",
+			args: args{c: &test.MockClient{
+				MockGet:          getCRD,
+				MockList:         list,
+				MockUpdate:       update(nil),
+				MockStatusUpdate: statusUpdate,
+			}},
+			want: want{updated: []string{"a", "b", "c"}, stored: []string{"v1"}},
+		},
+		"Conflict": {
+			reason: "Objects updated by someone else in the meantime should be skipped",
+			args: args{c: &test.MockClient{
+				MockGet:          getCRD,
+				MockList:         list,
+				MockUpdate:       update(kerrors.NewConflict(schema.GroupResource{}, "a", errBoom)),
+				MockStatusUpdate: statusUpdate,
+			}},
+			want: want{updated: []string{"a", "b", "c"}, stored: []string{"v1"}},
+		},
+		"GetCRDError": {
+			reason: "Errors getting the CRD should be returned",
+			args:   args{c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}},
+			want:   want{err: errors.Wrapf(errBoom, errFmtGetCRD, crdName)},
+		},
+		"NoStorageVersion": {
+			reason: "A CRD without a storage version cannot be migrated",
+			args: args{c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+				c := crd()
+				c.Spec.Versions[1].Storage = false
+				c.DeepCopyInto(obj.(*crdv1.CustomResourceDefinition))
+				return nil
+			})}},
+			want: want{err: errors.Errorf(errFmtNoStorageVersion, crdName)},
+		},
+		"ListError": {
+			reason: "Errors listing objects should be returned",
+			args:   args{c: &test.MockClient{MockGet: getCRD, MockList: test.NewMockListFn(errBoom)}},
+			want:   want{err: errors.Wrapf(errBoom, errFmtListObjects, "foos")},
+		},
+		"UpdateError": {
+			reason: "Errors updating objects should be returned and the stored versions should be kept",
+			args: args{c: &test.MockClient{
+				MockGet:          getCRD,
+				MockList:         list,
+				MockUpdate:       update(errBoom),
+				MockStatusUpdate: statusUpdate,
+			}},
+			want: want{err: errors.Wrapf(errBoom, errFmtUpdateObject, "Foo", "a"), updated: []string{"a"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated, stored = nil, nil
+			err := Migrate(context.Background(), tc.args.c, crdName)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMigrate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("\n%s\nMigrate(...): -want updated, +got updated:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.stored, stored); diff != "" {
+				t.Errorf("\n%s\nMigrate(...): -want stored versions, +got stored versions:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// CELExtension is the file extension of CEL policies.
+const CELExtension = ".cel"
+
+// msgNotSatisfied is the message of the violation of a CEL policy that
+// evaluates to false.
+const msgNotSatisfied = "resource does not satisfy the policy"
+
+const (
+	errCreateCELEnv        = "cannot create CEL environment"
+	errCompileCELPolicy    = "cannot compile CEL policy"
+	errFmtEvaluateCEL      = "cannot evaluate CEL policy %q for %s"
+	errFmtUnsupportedCEL   = "CEL policy %q evaluates to %s for %s, want a bool or a list of strings"
+	errFmtConvertCELDenied = "cannot convert the messages of CEL policy %q for %s"
+)
+
+type celPolicy struct {
+	name string
+	prg  cel.Program
+}
+
+// NewCELPolicy returns an Evaluator of the supplied CEL policy. The policy is
+// an expression that is evaluated against each rendered resource, which is
+// the 'resource' variable. The ApplicationConfiguration is available as
+// context.appConfigName and context.namespace. A resource violates the policy
+// if the expression evaluates to false, e.g.
+//
+//	!has(resource.spec.replicas) || resource.spec.replicas <= 10
+//
+// or to a list of messages that are not empty, e.g.
+//
+//	resource.kind != "Deployment" ? [] :
+//	  resource.spec.template.spec.containers
+//	    .filter(c, !c.image.startsWith("registry.example.com/"))
+//	    .map(c, "image " + c.image + " is not from registry.example.com")
+func NewCELPolicy(name, source string) (Evaluator, error) {
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewVar(ResourceField, decls.NewMapType(decls.String, decls.Dyn)),
+		decls.NewVar(ContextField, decls.NewMapType(decls.String, decls.Dyn)),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateCELEnv)
+	}
+	ast, iss := env.Compile(source)
+	if iss != nil && iss.Err() != nil {
+		return nil, errors.Wrap(iss.Err(), errCompileCELPolicy)
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, errors.Wrap(err, errCompileCELPolicy)
+	}
+	return &celPolicy{name: name, prg: prg}, nil
+}
+
+// Evaluate the supplied resources against the policy.
+func (p *celPolicy) Evaluate(_ context.Context, ac *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) ([]Violation, error) {
+	c := contextOf(ac)
+	var violations []Violation
+	for _, r := range resources {
+		name := resourceName(r)
+		out, _, err := p.prg.Eval(map[string]interface{}{ResourceField: r.Object, ContextField: c})
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtEvaluateCEL, p.name, name)
+		}
+		switch v := out.(type) {
+		case types.Bool:
+			if !v {
+				violations = append(violations, Violation{Policy: p.name, Resource: name, Message: msgNotSatisfied})
+			}
+		case traits.Lister:
+			msgs, err := v.ConvertToNative(reflect.TypeOf([]string{}))
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtConvertCELDenied, p.name, name)
+			}
+			for _, m := range msgs.([]string) {
+				violations = append(violations, Violation{Policy: p.name, Resource: name, Message: m})
+			}
+		default:
+			return nil, errors.Errorf(errFmtUnsupportedCEL, p.name, out.Type().TypeName(), name)
+		}
+	}
+	return violations, nil
+}
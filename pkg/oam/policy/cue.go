@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+
+	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// CUEExtension is the file extension of CUE policies.
+const CUEExtension = ".cue"
+
+const (
+	// ResourceField is the field of a CUE policy, or the variable of a CEL
+	// policy, that is filled with the rendered resource being evaluated.
+	ResourceField = "resource"
+	// ContextField is the field of a CUE policy, or the variable of a CEL
+	// policy, that is filled with the ApplicationConfiguration the resource
+	// was rendered from, i.e. its appConfigName and namespace.
+	ContextField = "context"
+	// DenyField is the list of messages of a CUE policy. Each message is a
+	// violation of the policy.
+	DenyField = "deny"
+)
+
+const (
+	errCompilePolicy   = "cannot compile CUE policy"
+	errFmtFillResource = "cannot fill %s into CUE policy %q"
+	errFmtEvaluateDeny = "cannot evaluate deny of CUE policy %q for %s"
+)
+
+type cuePolicy struct {
+	name string
+	inst *cue.Instance
+}
+
+// NewCUEPolicy returns an Evaluator of the supplied CUE policy. The policy is
+// evaluated against each rendered resource, which it declares as 'resource'.
+// A resource violates the policy if it doesn't satisfy the constraints the
+// policy places on it, e.g.
+//
+//	resource: spec: replicas?: <=10
+//
+// or if the policy denies it, e.g.
+//
+//	import "strings"
+//
+//	resource: {...}
+//	if resource.kind == "Deployment" {
+//	  deny: [ for c in resource.spec.template.spec.containers
+//	    if !strings.HasPrefix(c.image, "registry.example.com/") {
+//	      "image \(c.image) is not from registry.example.com"
+//	    } ]
+//	}
+func NewCUEPolicy(name, source string) (Evaluator, error) {
+	var r cue.Runtime
+	inst, err := r.Compile(name, source)
+	if err != nil {
+		return nil, errors.Wrap(err, errCompilePolicy)
+	}
+	return &cuePolicy{name: name, inst: inst}, nil
+}
+
+// Evaluate the supplied resources against the policy.
+func (p *cuePolicy) Evaluate(_ context.Context, ac *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) ([]Violation, error) {
+	c := contextOf(ac)
+	var violations []Violation
+	for _, r := range resources {
+		name := resourceName(r)
+		inst, err := p.inst.Fill(r.Object, ResourceField)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtFillResource, name, p.name)
+		}
+		inst, err = inst.Fill(c, ContextField)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtFillResource, name, p.name)
+		}
+		// the resource conflicts with the constraints of the policy
+		if err := inst.Value().Validate(); err != nil {
+			violations = append(violations, Violation{Policy: p.name, Resource: name, Message: err.Error()})
+			continue
+		}
+		msgs, err := denied(inst.Lookup(DenyField))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtEvaluateDeny, p.name, name)
+		}
+		for _, m := range msgs {
+			violations = append(violations, Violation{Policy: p.name, Resource: name, Message: m})
+		}
+	}
+	return violations, nil
+}
+
+// denied returns the messages of the supplied deny list, if any.
+func denied(deny cue.Value) ([]string, error) {
+	if !deny.Exists() {
+		return nil, nil
+	}
+	it, err := deny.List()
+	if err != nil {
+		return nil, err
+	}
+	var msgs []string
+	for it.Next() {
+		m, err := it.Value().String()
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, nil
+}
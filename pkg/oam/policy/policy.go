@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates operator supplied policies against the resources
+// an ApplicationConfiguration renders to, e.g. to require that all images come
+// from a trusted registry or to limit the number of replicas.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const (
+	errFmtReadPolicyDir     = "cannot read policy directory %q"
+	errFmtReadPolicy        = "cannot read policy %q"
+	errFmtUnsupportedEngine = "no policy engine evaluates policy %q, supported extensions are %v"
+	errFmtLoadPolicy        = "cannot load policy %q"
+)
+
+// A Violation of a policy by a rendered resource.
+type Violation struct {
+	// Policy that is violated.
+	Policy string
+
+	// Resource that violates the policy, e.g. Deployment "web".
+	Resource string
+
+	// Message describing the violation.
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s violates policy %q: %s", v.Resource, v.Policy, v.Message)
+}
+
+// An Evaluator evaluates policies against the resources an
+// ApplicationConfiguration renders to.
+type Evaluator interface {
+	Evaluate(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) ([]Violation, error)
+}
+
+// An EvaluateFn evaluates policies against the resources an
+// ApplicationConfiguration renders to.
+type EvaluateFn func(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) ([]Violation, error)
+
+// Evaluate the supplied resources.
+func (fn EvaluateFn) Evaluate(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) ([]Violation, error) {
+	return fn(ctx, ac, resources)
+}
+
+// Evaluators evaluate all of their policies.
+type Evaluators []Evaluator
+
+// Evaluate the supplied resources against all policies, returning the
+// violations of all of them.
+func (e Evaluators) Evaluate(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) ([]Violation, error) {
+	var violations []Violation
+	for _, ev := range e {
+		v, err := ev.Evaluate(ctx, ac, resources)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, v...)
+	}
+	return violations, nil
+}
+
+// A NewEvaluatorFn returns an Evaluator of the policy with the supplied name
+// and source.
+type NewEvaluatorFn func(name, source string) (Evaluator, error)
+
+// DefaultEngines evaluate policies by the extension of their file. Evaluators
+// of other policy languages, e.g. Rego, may be added by extension.
+var DefaultEngines = map[string]NewEvaluatorFn{
+	CUEExtension: NewCUEPolicy,
+	CELExtension: NewCELPolicy,
+}
+
+// LoadDir loads the policies in the supplied directory. Each file is a policy
+// named after the file, evaluated by the engine of its extension. Hidden files
+// and directories, e.g. those of a mounted ConfigMap, are ignored.
+func LoadDir(dir string, engines map[string]NewEvaluatorFn) (Evaluators, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtReadPolicyDir, dir)
+	}
+	exts := make([]string, 0, len(engines))
+	for ext := range engines {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+
+	var e Evaluators
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		// mounted ConfigMaps link their files
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadPolicy, path)
+		}
+		if fi.IsDir() {
+			continue
+		}
+		newEvaluator, ok := engines[filepath.Ext(f.Name())]
+		if !ok {
+			return nil, errors.Errorf(errFmtUnsupportedEngine, f.Name(), exts)
+		}
+		source, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtReadPolicy, path)
+		}
+		ev, err := newEvaluator(f.Name(), string(source))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtLoadPolicy, f.Name())
+		}
+		e = append(e, ev)
+	}
+	return e, nil
+}
+
+// resourceName describes the supplied resource, e.g. Deployment "web".
+func resourceName(r *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %q", r.GetKind(), r.GetName())
+}
+
+// contextOf returns the context policies are evaluated in, i.e. the name and
+// namespace of the supplied ApplicationConfiguration.
+func contextOf(ac *v1alpha2.ApplicationConfiguration) map[string]interface{} {
+	return map[string]interface{}{
+		"appConfigName": ac.GetName(),
+		"namespace":     ac.GetNamespace(),
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const maxReplicasPolicy = `
+resource: spec: replicas?: <=10
+`
+
+const trustedRegistryPolicy = `
+import "strings"
+
+resource: {...}
+if resource.kind == "Deployment" {
+	deny: [ for c in resource.spec.template.spec.containers
+		if !strings.HasPrefix(c.image, "registry.example.com/") {
+			"image \(c.image) is not from registry.example.com"
+		}]
+}
+`
+
+const namespacePolicy = `
+context: {...}
+if context.namespace == "kube-system" {
+	deny: ["applications MUST NOT be deployed to \(context.namespace)"]
+}
+`
+
+const maxReplicasCELPolicy = `!has(resource.spec) || !has(resource.spec.replicas) || resource.spec.replicas <= 10`
+
+const trustedRegistryCELPolicy = `
+resource.kind != "Deployment" ? [] :
+  resource.spec.template.spec.containers
+    .filter(c, !c.image.startsWith("registry.example.com/"))
+    .map(c, "image " + c.image + " is not from registry.example.com")
+`
+
+const namespaceCELPolicy = `
+context.namespace == "kube-system" ? ["applications MUST NOT be deployed to " + context.namespace] : []
+`
+
+func deployment(name, image string, replicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "main", "image": image}},
+			}},
+		},
+	}}
+}
+
+func TestCUEPolicy(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{}
+	ac.SetName("example-appconfig")
+	ac.SetNamespace("default")
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "web"},
+	}}
+
+	type want struct {
+		violations []Violation
+		err        bool
+	}
+	cases := map[string]struct {
+		reason    string
+		policy    string
+		ns        string
+		resources []*unstructured.Unstructured
+		want      want
+	}{
+		"SatisfiedConstraint": {
+			reason:    "Resources that satisfy the constraints of a policy should not violate it",
+			policy:    maxReplicasPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "nginx", 3), service},
+		},
+		"ViolatedConstraint": {
+			reason:    "Resources that don't satisfy the constraints of a policy should violate it",
+			policy:    maxReplicasPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "nginx", 3), deployment("db", "mysql", 12)},
+			want: want{violations: []Violation{
+				{Policy: "policy.cue", Resource: `Deployment "db"`},
+			}},
+		},
+		"NotDenied": {
+			reason:    "Resources a policy doesn't deny should not violate it",
+			policy:    trustedRegistryPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "registry.example.com/nginx", 3), service},
+		},
+		"Denied": {
+			reason:    "Resources a policy denies should violate it once per message",
+			policy:    trustedRegistryPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "nginx", 3), service},
+			want: want{violations: []Violation{
+				{Policy: "policy.cue", Resource: `Deployment "web"`, Message: "image nginx is not from registry.example.com"},
+			}},
+		},
+		"DeniedByContext": {
+			reason:    "Policies should be able to deny resources by the ApplicationConfiguration they are rendered from",
+			policy:    namespacePolicy,
+			ns:        "kube-system",
+			resources: []*unstructured.Unstructured{service},
+			want: want{violations: []Violation{
+				{Policy: "policy.cue", Resource: `Service "web"`, Message: "applications MUST NOT be deployed to kube-system"},
+			}},
+		},
+		"InvalidDeny": {
+			reason:    "An error should be returned if the deny list of a policy is not a list of strings",
+			policy:    `deny: [1]`,
+			resources: []*unstructured.Unstructured{service},
+			want:      want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewCUEPolicy("policy.cue", tc.policy)
+			if err != nil {
+				t.Fatalf("NewCUEPolicy(...): %v", err)
+			}
+			a := ac.DeepCopy()
+			if tc.ns != "" {
+				a.SetNamespace(tc.ns)
+			}
+			got, err := p.Evaluate(context.Background(), a, tc.resources)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			// the messages of constraint violations are CUE errors
+			for i := range got {
+				if i < len(tc.want.violations) && tc.want.violations[i].Message == "" {
+					got[i].Message = ""
+				}
+			}
+			if diff := cmp.Diff(tc.want.violations, got); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewCUEPolicy(t *testing.T) {
+	if _, err := NewCUEPolicy("policy.cue", "resource: {"); err == nil {
+		t.Errorf("NewCUEPolicy(...): want error for a policy that doesn't compile")
+	}
+}
+
+func TestCELPolicy(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{}
+	ac.SetName("example-appconfig")
+	ac.SetNamespace("default")
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "web"},
+	}}
+
+	type want struct {
+		violations []Violation
+		err        bool
+	}
+	cases := map[string]struct {
+		reason    string
+		policy    string
+		ns        string
+		resources []*unstructured.Unstructured
+		want      want
+	}{
+		"Satisfied": {
+			reason:    "Resources a policy evaluates to true for should not violate it",
+			policy:    maxReplicasCELPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "nginx", 3), service},
+		},
+		"NotSatisfied": {
+			reason:    "Resources a policy evaluates to false for should violate it",
+			policy:    maxReplicasCELPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "nginx", 3), deployment("db", "mysql", 12)},
+			want: want{violations: []Violation{
+				{Policy: "policy.cel", Resource: `Deployment "db"`, Message: msgNotSatisfied},
+			}},
+		},
+		"NotDenied": {
+			reason:    "Resources a policy evaluates to no messages for should not violate it",
+			policy:    trustedRegistryCELPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "registry.example.com/nginx", 3), service},
+		},
+		"Denied": {
+			reason:    "Resources a policy evaluates to messages for should violate it once per message",
+			policy:    trustedRegistryCELPolicy,
+			resources: []*unstructured.Unstructured{deployment("web", "nginx", 3), service},
+			want: want{violations: []Violation{
+				{Policy: "policy.cel", Resource: `Deployment "web"`, Message: "image nginx is not from registry.example.com"},
+			}},
+		},
+		"DeniedByContext": {
+			reason:    "Policies should be able to deny resources by the ApplicationConfiguration they are rendered from",
+			policy:    namespaceCELPolicy,
+			ns:        "kube-system",
+			resources: []*unstructured.Unstructured{service},
+			want: want{violations: []Violation{
+				{Policy: "policy.cel", Resource: `Service "web"`, Message: "applications MUST NOT be deployed to kube-system"},
+			}},
+		},
+		"UnsupportedResult": {
+			reason:    "An error should be returned if a policy evaluates to neither a bool nor a list",
+			policy:    `resource.kind`,
+			resources: []*unstructured.Unstructured{service},
+			want:      want{err: true},
+		},
+		"InvalidMessages": {
+			reason:    "An error should be returned if a policy evaluates to a list that is not a list of strings",
+			policy:    `[1]`,
+			resources: []*unstructured.Unstructured{service},
+			want:      want{err: true},
+		},
+		"EvaluationError": {
+			reason:    "An error should be returned if a policy cannot be evaluated for a resource",
+			policy:    `resource.spec.replicas <= 10`,
+			resources: []*unstructured.Unstructured{service},
+			want:      want{err: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewCELPolicy("policy.cel", tc.policy)
+			if err != nil {
+				t.Fatalf("NewCELPolicy(...): %v", err)
+			}
+			a := ac.DeepCopy()
+			if tc.ns != "" {
+				a.SetNamespace(tc.ns)
+			}
+			got, err := p.Evaluate(context.Background(), a, tc.resources)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.violations, got); diff != "" {
+				t.Errorf("\n%s\nEvaluate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewCELPolicy(t *testing.T) {
+	if _, err := NewCELPolicy("policy.cel", "resource.spec.replicas <="); err == nil {
+		t.Errorf("NewCELPolicy(...): want error for a policy that doesn't compile")
+	}
+	if _, err := NewCELPolicy("policy.cel", "replicas <= 10"); err == nil {
+		t.Errorf("NewCELPolicy(...): want error for a policy that refers to undeclared variables")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policies")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(...): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, content string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("ioutil.WriteFile(...): %v", err)
+		}
+	}
+	write("replicas.cue", maxReplicasPolicy)
+	write("registry.cue", trustedRegistryPolicy)
+	write("registry.cel", trustedRegistryCELPolicy)
+	// hidden files and directories are ignored
+	write(".hidden.rego", "package hidden")
+	if err := os.Mkdir(filepath.Join(dir, "..data"), 0700); err != nil {
+		t.Fatalf("os.Mkdir(...): %v", err)
+	}
+
+	e, err := LoadDir(dir, DefaultEngines)
+	if err != nil {
+		t.Fatalf("LoadDir(...): %v", err)
+	}
+	if len(e) != 3 {
+		t.Errorf("LoadDir(...): want 3 policies, got %d", len(e))
+	}
+	ac := &v1alpha2.ApplicationConfiguration{}
+	violations, err := e.Evaluate(context.Background(), ac, []*unstructured.Unstructured{deployment("web", "nginx", 12)})
+	if err != nil {
+		t.Fatalf("Evaluate(...): %v", err)
+	}
+	if len(violations) != 3 {
+		t.Errorf("Evaluate(...): want a violation of each policy, got %v", violations)
+	}
+
+	write("unsupported.rego", "package unsupported")
+	if _, err := LoadDir(dir, DefaultEngines); err == nil {
+		t.Errorf("LoadDir(...): want error for a policy no engine evaluates")
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota computes the compute resources workloads request, so that
+// they can be limited in aggregate, e.g. by a ResourceQuotaScope.
+package quota
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	errFmtDecodePodSpec       = "cannot decode the pod spec of %s %q"
+	errFmtDecodeWorkload      = "cannot decode %s %q"
+	errFmtDecodeChildResource = "cannot decode a child resource reference of %s %q"
+	errFmtGetChildResource    = "cannot get child resource %s %q"
+	errFmtGetPodSpec          = "cannot get the pod spec of %s %q"
+)
+
+// Resources whose usage is computed. Like in a ResourceQuota, cpu and memory
+// are synonyms of requests.cpu and requests.memory.
+var Resources = []corev1.ResourceName{
+	corev1.ResourceRequestsCPU,
+	corev1.ResourceRequestsMemory,
+	corev1.ResourceLimitsCPU,
+	corev1.ResourceLimitsMemory,
+}
+
+// Normalize returns the supplied resource list with cpu and memory replaced
+// by requests.cpu and requests.memory, and resources whose usage is not
+// computed omitted.
+func Normalize(l corev1.ResourceList) corev1.ResourceList {
+	n := corev1.ResourceList{}
+	for name, q := range l {
+		switch name { //nolint:exhaustive
+		case corev1.ResourceCPU:
+			name = corev1.ResourceRequestsCPU
+		case corev1.ResourceMemory:
+			name = corev1.ResourceRequestsMemory
+		}
+		if isComputed(name) {
+			n[name] = q.DeepCopy()
+		}
+	}
+	return n
+}
+
+// Zero returns a resource list with no usage of the computed resources.
+func Zero() corev1.ResourceList {
+	l := corev1.ResourceList{}
+	for _, name := range Resources {
+		l[name] = resource.Quantity{}
+	}
+	return l
+}
+
+// Add returns the sum of the supplied resource lists.
+func Add(a, b corev1.ResourceList) corev1.ResourceList {
+	sum := corev1.ResourceList{}
+	for name, q := range a {
+		sum[name] = q.DeepCopy()
+	}
+	for name, q := range b {
+		s := sum[name]
+		s.Add(q)
+		sum[name] = s
+	}
+	return sum
+}
+
+// Max returns the greater usage of each resource of the supplied resource
+// lists.
+func Max(a, b corev1.ResourceList) corev1.ResourceList {
+	m := corev1.ResourceList{}
+	for name, q := range a {
+		m[name] = q.DeepCopy()
+	}
+	for name, q := range b {
+		if existing, ok := m[name]; !ok || q.Cmp(existing) > 0 {
+			m[name] = q.DeepCopy()
+		}
+	}
+	return m
+}
+
+// Subtract returns the supplied resource list a less the supplied resource
+// list b. Resources are not reduced below zero.
+func Subtract(a, b corev1.ResourceList) corev1.ResourceList {
+	diff := corev1.ResourceList{}
+	for name, q := range a {
+		d := q.DeepCopy()
+		if s, ok := b[name]; ok {
+			d.Sub(s)
+		}
+		if d.Sign() < 0 {
+			d = resource.Quantity{Format: d.Format}
+		}
+		diff[name] = d
+	}
+	return diff
+}
+
+// Exceeded returns the resources of the supplied hard limits the supplied
+// usage exceeds, in the order of Resources.
+func Exceeded(hard, used corev1.ResourceList) []corev1.ResourceName {
+	var exceeded []corev1.ResourceName
+	for _, name := range Resources {
+		h, ok := hard[name]
+		if !ok {
+			continue
+		}
+		if u, ok := used[name]; ok && u.Cmp(h) > 0 {
+			exceeded = append(exceeded, name)
+		}
+	}
+	return exceeded
+}
+
+// WorkloadUsage returns the compute resources the pods of the supplied
+// workload request, multiplied by its replicas. The pods of a workload are
+// determined by the first of
+//
+//  1. the pod spec at the podSpecPath of its WorkloadDefinition.
+//  2. the pod template at spec.template.
+//  3. the pod template of the first child resource recorded in its
+//     status.resources that has one.
+//  4. the containers of a ContainerizedWorkload, which has one replica.
+//
+// Replicas are read from spec.replicas of the resource the pods are
+// determined from, and default to one. It returns nil if the pods of the
+// workload can't be determined.
+func WorkloadUsage(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
+	wl *unstructured.Unstructured) (corev1.ResourceList, error) {
+	paved, err := util.FetchWorkloadPodSpec(ctx, r, dm, wl.DeepCopy())
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetPodSpec, wl.GetKind(), wl.GetName())
+	}
+	if paved != nil {
+		return podUsage(wl, paved.UnstructuredContent())
+	}
+	if u, err := templateUsage(wl); err != nil || u != nil {
+		return u, err
+	}
+
+	children, _, _ := unstructured.NestedSlice(wl.Object, "status", "resources")
+	for _, c := range children {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref := runtimev1alpha1.TypedReference{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &ref); err != nil {
+			return nil, errors.Wrapf(err, errFmtDecodeChildResource, wl.GetKind(), wl.GetName())
+		}
+		child := &unstructured.Unstructured{}
+		child.SetGroupVersionKind(ref.GroupVersionKind())
+		err := r.Get(ctx, types.NamespacedName{Namespace: wl.GetNamespace(), Name: ref.Name}, child)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetChildResource, ref.Kind, ref.Name)
+		}
+		if u, err := templateUsage(child); err != nil || u != nil {
+			return u, err
+		}
+	}
+
+	if wl.GroupVersionKind() == v1alpha2.ContainerizedWorkloadGroupVersionKind {
+		return containerizedWorkloadUsage(wl)
+	}
+	return nil, nil
+}
+
+// templateUsage returns the usage of the pod template at spec.template of the
+// supplied resource, or nil if it has none.
+func templateUsage(u *unstructured.Unstructured) (corev1.ResourceList, error) {
+	spec, found, err := unstructured.NestedMap(u.Object, "spec", "template", "spec")
+	if err != nil || !found {
+		return nil, nil
+	}
+	return podUsage(u, spec)
+}
+
+// podUsage returns the usage of the supplied pod spec of the supplied
+// resource, multiplied by the replicas of the resource.
+func podUsage(u *unstructured.Unstructured, spec map[string]interface{}) (corev1.ResourceList, error) {
+	ps := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, ps); err != nil {
+		return nil, errors.Wrapf(err, errFmtDecodePodSpec, u.GetKind(), u.GetName())
+	}
+	used := Zero()
+	for _, c := range ps.Containers {
+		used = Add(used, Normalize(prefixed(c.Resources)))
+	}
+	// init containers run one at a time before the containers, so that like
+	// ResourceQuotas the greater of their and the containers' usage counts
+	for _, c := range ps.InitContainers {
+		used = Max(used, Normalize(prefixed(c.Resources)))
+	}
+	return multiply(used, replicas(u)), nil
+}
+
+// containerizedWorkloadUsage returns the usage of the containers of the
+// supplied ContainerizedWorkload. Containers only request resources.
+func containerizedWorkloadUsage(u *unstructured.Unstructured) (corev1.ResourceList, error) {
+	cw := &v1alpha2.ContainerizedWorkload{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cw); err != nil {
+		return nil, errors.Wrapf(err, errFmtDecodeWorkload, u.GetKind(), u.GetName())
+	}
+	used := Zero()
+	for _, c := range cw.Spec.Containers {
+		used = Add(used, containerRequests(c))
+	}
+	for _, c := range cw.Spec.InitContainers {
+		used = Max(used, containerRequests(c))
+	}
+	return used, nil
+}
+
+// containerRequests returns the resources the supplied container of a
+// ContainerizedWorkload requests.
+func containerRequests(c v1alpha2.Container) corev1.ResourceList {
+	if c.Resources == nil {
+		return nil
+	}
+	return corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    c.Resources.CPU.Required,
+		corev1.ResourceRequestsMemory: c.Resources.Memory.Required,
+	}
+}
+
+// prefixed returns the requests and limits of the supplied requirements as a
+// single resource list, e.g. with requests.cpu and limits.cpu.
+func prefixed(rr corev1.ResourceRequirements) corev1.ResourceList {
+	l := corev1.ResourceList{}
+	for name, q := range rr.Requests {
+		l[corev1.ResourceName("requests."+string(name))] = q
+	}
+	for name, q := range rr.Limits {
+		l[corev1.ResourceName("limits."+string(name))] = q
+	}
+	return l
+}
+
+func replicas(u *unstructured.Unstructured) int64 {
+	n, found, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if err != nil || !found {
+		return 1
+	}
+	return n
+}
+
+func multiply(l corev1.ResourceList, n int64) corev1.ResourceList {
+	m := corev1.ResourceList{}
+	for name, q := range l {
+		m[name] = *resource.NewMilliQuantity(q.MilliValue()*n, q.Format)
+	}
+	return m
+}
+
+func isComputed(name corev1.ResourceName) bool {
+	for _, r := range Resources {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func usage(requestsCPU, requestsMemory, limitsCPU, limitsMemory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    resource.MustParse(requestsCPU),
+		corev1.ResourceRequestsMemory: resource.MustParse(requestsMemory),
+		corev1.ResourceLimitsCPU:      resource.MustParse(limitsCPU),
+		corev1.ResourceLimitsMemory:   resource.MustParse(limitsMemory),
+	}
+}
+
+func TestWorkloadUsage(t *testing.T) {
+	errBoom := errors.New("boom")
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	containers := []interface{}{
+		map[string]interface{}{
+			"name": "a",
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "250m", "memory": "64Mi"},
+				"limits":   map[string]interface{}{"cpu": "500m", "memory": "128Mi"},
+			},
+		},
+		map[string]interface{}{
+			"name": "b",
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "250m"},
+			},
+		},
+	}
+	deploy := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{"containers": containers},
+				},
+			},
+		}}
+	}
+
+	type args struct {
+		r  client.Reader
+		wl *unstructured.Unstructured
+	}
+	type want struct {
+		used corev1.ResourceList
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"GetWorkloadDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition of the workload should be returned",
+			args: args{
+				r:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				wl: deploy(),
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetPodSpec, "Deployment", "web")},
+		},
+		"PodSpecPath": {
+			reason: "The pod spec at the podSpecPath of the WorkloadDefinition should be used",
+			args: args{
+				r: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					if wd, ok := obj.(*v1alpha2.WorkloadDefinition); ok {
+						wd.Spec.PodSpecPath = "spec.pod"
+					}
+					return nil
+				}},
+				wl: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "example.org/v1",
+					"kind":       "Server",
+					"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+					"spec": map[string]interface{}{
+						"replicas": int64(2),
+						"pod":      map[string]interface{}{"containers": containers},
+					},
+				}},
+			},
+			want: want{used: usage("1", "128Mi", "1", "256Mi")},
+		},
+		"PodTemplate": {
+			reason: "The pod template of the workload should be multiplied by its replicas",
+			args: args{
+				r:  &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				wl: deploy(),
+			},
+			want: want{used: usage("1500m", "192Mi", "1500m", "384Mi")},
+		},
+		"ChildResource": {
+			reason: "The pod template of a child resource should be used if the workload has none",
+			args: args{
+				r: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					u, ok := obj.(*unstructured.Unstructured)
+					if !ok {
+						return notFound
+					}
+					d := deploy()
+					d.SetGroupVersionKind(u.GroupVersionKind())
+					*u = *d
+					return nil
+				}},
+				wl: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "example.org/v1",
+					"kind":       "Server",
+					"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+					"status": map[string]interface{}{
+						"resources": []interface{}{
+							map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+						},
+					},
+				}},
+			},
+			want: want{used: usage("1500m", "192Mi", "1500m", "384Mi")},
+		},
+		"GetChildResourceError": {
+			reason: "Errors getting a child resource should be returned",
+			args: args{
+				r: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+					if _, ok := obj.(*unstructured.Unstructured); ok {
+						return errBoom
+					}
+					return notFound
+				}},
+				wl: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "example.org/v1",
+					"kind":       "Server",
+					"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+					"status": map[string]interface{}{
+						"resources": []interface{}{
+							map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"},
+						},
+					},
+				}},
+			},
+			want: want{err: errors.Wrapf(errBoom, errFmtGetChildResource, "Deployment", "web")},
+		},
+		"ContainerizedWorkload": {
+			reason: "The containers of a ContainerizedWorkload should only request resources",
+			args: args{
+				r: &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				wl: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": v1alpha2.SchemeGroupVersion.String(),
+					"kind":       v1alpha2.ContainerizedWorkloadKind,
+					"metadata":   map[string]interface{}{"namespace": "ns", "name": "api"},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "api",
+								"image": "api",
+								"resources": map[string]interface{}{
+									"cpu":    map[string]interface{}{"required": "500m"},
+									"memory": map[string]interface{}{"required": "1Gi"},
+								},
+							},
+						},
+					},
+				}},
+			},
+			want: want{used: usage("500m", "1Gi", "0", "0")},
+		},
+		"ContainerizedWorkloadInitContainers": {
+			reason: "Init containers of a ContainerizedWorkload should only count if they request more than its containers",
+			args: args{
+				r: &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				wl: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": v1alpha2.SchemeGroupVersion.String(),
+					"kind":       v1alpha2.ContainerizedWorkloadKind,
+					"metadata":   map[string]interface{}{"namespace": "ns", "name": "api"},
+					"spec": map[string]interface{}{
+						"initContainers": []interface{}{
+							map[string]interface{}{
+								"name":  "migrate",
+								"image": "migrate",
+								"resources": map[string]interface{}{
+									"cpu":    map[string]interface{}{"required": "2"},
+									"memory": map[string]interface{}{"required": "256Mi"},
+								},
+							},
+						},
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":  "api",
+								"image": "api",
+								"resources": map[string]interface{}{
+									"cpu":    map[string]interface{}{"required": "500m"},
+									"memory": map[string]interface{}{"required": "1Gi"},
+								},
+							},
+						},
+					},
+				}},
+			},
+			want: want{used: usage("2", "1Gi", "0", "0")},
+		},
+		"Unknown": {
+			reason: "The usage of workloads whose pods can't be determined should be nil",
+			args: args{
+				r: &test.MockClient{MockGet: test.NewMockGetFn(notFound)},
+				wl: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "example.org/v1",
+					"kind":       "Server",
+					"metadata":   map[string]interface{}{"namespace": "ns", "name": "web"},
+				}},
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dm := mock.NewMockDiscoveryMapper()
+			dm.MockRESTMapping = mock.NewMockRESTMapping("foos")
+			used, err := WorkloadUsage(context.Background(), tc.args.r, dm, tc.args.wl)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWorkloadUsage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.used, used); diff != "" {
+				t.Errorf("\n%s\nWorkloadUsage(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	hard := corev1.ResourceList{
+		corev1.ResourceCPU:          resource.MustParse("2"),
+		corev1.ResourceLimitsCPU:    resource.MustParse("4"),
+		corev1.ResourcePods:         resource.MustParse("10"),
+		corev1.ResourceMemory:       resource.MustParse("1Gi"),
+		corev1.ResourceLimitsMemory: resource.MustParse("2Gi"),
+	}
+	want := usage("2", "1Gi", "4", "2Gi")
+	if diff := cmp.Diff(want, Normalize(hard)); diff != "" {
+		t.Errorf("Normalize(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestExceeded(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		hard   corev1.ResourceList
+		used   corev1.ResourceList
+		want   []corev1.ResourceName
+	}{
+		"WithinBudget": {
+			reason: "Usage that does not exceed the hard limits should not be reported",
+			hard:   usage("2", "1Gi", "4", "2Gi"),
+			used:   usage("2", "512Mi", "1", "2Gi"),
+		},
+		"ExceedsBudget": {
+			reason: "Resources whose usage exceeds the hard limits should be reported in order",
+			hard:   usage("2", "1Gi", "4", "2Gi"),
+			used:   usage("2500m", "512Mi", "1", "3Gi"),
+			want:   []corev1.ResourceName{corev1.ResourceRequestsCPU, corev1.ResourceLimitsMemory},
+		},
+		"Unlimited": {
+			reason: "Resources without a hard limit should never be reported",
+			hard:   corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+			used:   usage("1", "1Ti", "100", "1Ti"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Exceeded(tc.hard, tc.used)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nExceeded(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	got := Subtract(usage("2", "1Gi", "1", "1Gi"), usage("500m", "2Gi", "1", "0"))
+	want := usage("1500m", "0", "0", "1Gi")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Subtract(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,597 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestDependency(t *testing.T) {
+	unreadyWorkload := &unstructured.Unstructured{}
+	unreadyWorkload.SetAPIVersion("v1")
+	unreadyWorkload.SetKind("Workload")
+	unreadyWorkload.SetNamespace("test-ns")
+	unreadyWorkload.SetName("unready-workload")
+
+	readyWorkload := unreadyWorkload.DeepCopy()
+	readyWorkload.SetName("ready-workload")
+	err := unstructured.SetNestedField(readyWorkload.Object, "test", "status", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readyWorkloadArrayField := unreadyWorkload.DeepCopy()
+	err = unstructured.SetNestedStringSlice(readyWorkloadArrayField.Object, []string{"a"}, "spec", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = unstructured.SetNestedStringSlice(readyWorkloadArrayField.Object, []string{"b"}, "status", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unreadyTrait := &unstructured.Unstructured{}
+	unreadyTrait.SetAPIVersion("v1")
+	unreadyTrait.SetKind("Trait")
+	unreadyTrait.SetNamespace("test-ns")
+	unreadyTrait.SetName("unready-trait")
+
+	readyTrait := unreadyTrait.DeepCopy()
+	readyTrait.SetName("ready-trait")
+	err = unstructured.SetNestedField(readyTrait.Object, "test", "status", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapper := mock.NewMockDiscoveryMapper()
+
+	type args struct {
+		components []v1alpha2.ApplicationConfigurationComponent
+		wl         *unstructured.Unstructured
+		trait      *unstructured.Unstructured
+	}
+	type want struct {
+		err             error
+		verifyWorkloads func([]Workload)
+		depStatus       *v1alpha2.DependencyStatus
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Workload depends on another Workload that's unready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					DataInputs: []v1alpha2.DataInput{{
+						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+						ToFieldPaths: []string{"spec.key"},
+					}},
+				}, {
+					ComponentName: "test-component-source",
+					DataOutputs: []v1alpha2.DataOutput{{
+						Name:      "test-output",
+						FieldPath: "status.key",
+					}},
+				}},
+				wl:    unreadyWorkload.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if !ws[0].HasDep {
+						t.Error("Workload should be unready to apply")
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{
+					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
+						Reason: "status.key not found in object",
+						From: v1alpha2.DependencyFromObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyWorkload.GetAPIVersion(),
+								Kind:       unreadyWorkload.GetKind(),
+								Name:       unreadyWorkload.GetName(),
+							},
+							FieldPath: "status.key",
+						},
+						To: v1alpha2.DependencyToObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyWorkload.GetAPIVersion(),
+								Kind:       unreadyWorkload.GetKind(),
+								Name:       unreadyWorkload.GetName(),
+							},
+							FieldPaths: []string{"spec.key"},
+						},
+					}},
+				},
+			},
+		},
+		"Workload depends on another Workload that's ready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					DataInputs: []v1alpha2.DataInput{{
+						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+						ToFieldPaths: []string{"spec.key"},
+					}},
+				}, {
+					ComponentName: "test-component-source",
+					DataOutputs: []v1alpha2.DataOutput{{
+						Name:      "test-output",
+						FieldPath: "status.key",
+					}},
+				}},
+				wl:    readyWorkload.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if ws[0].HasDep {
+						t.Error("Workload should be ready to apply")
+					}
+
+					s, _, err := unstructured.NestedString(ws[0].Workload.UnstructuredContent(), "spec", "key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if diff := cmp.Diff(s, "test"); diff != "" {
+						t.Fatal(diff)
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{},
+			},
+		},
+		"Workload depends on a Trait that's unready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					DataInputs: []v1alpha2.DataInput{{
+						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+						ToFieldPaths: []string{"spec.key"},
+					}},
+					Traits: []v1alpha2.ComponentTrait{{
+						Trait: runtime.RawExtension{},
+						DataOutputs: []v1alpha2.DataOutput{{
+							Name:      "test-output",
+							FieldPath: "status.key",
+						}},
+					}},
+				}},
+				wl:    unreadyWorkload.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if !ws[0].HasDep {
+						t.Error("Workload should be unready to apply")
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{
+					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
+						Reason: "status.key not found in object",
+						From: v1alpha2.DependencyFromObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyTrait.GetAPIVersion(),
+								Kind:       unreadyTrait.GetKind(),
+								Name:       unreadyTrait.GetName(),
+							},
+							FieldPath: "status.key",
+						},
+						To: v1alpha2.DependencyToObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyWorkload.GetAPIVersion(),
+								Kind:       unreadyWorkload.GetKind(),
+								Name:       unreadyWorkload.GetName(),
+							},
+							FieldPaths: []string{"spec.key"},
+						},
+					}},
+				},
+			},
+		},
+		"Workload depends on a Trait that's ready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					DataInputs: []v1alpha2.DataInput{{
+						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+						ToFieldPaths: []string{"spec.key"},
+					}},
+					Traits: []v1alpha2.ComponentTrait{{
+						Trait: runtime.RawExtension{},
+						DataOutputs: []v1alpha2.DataOutput{{
+							Name:      "test-output",
+							FieldPath: "status.key",
+						}},
+					}},
+				}},
+				wl:    unreadyWorkload.DeepCopy(),
+				trait: readyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if ws[0].HasDep {
+						t.Error("Workload should be ready to apply")
+					}
+
+					s, _, err := unstructured.NestedString(ws[0].Workload.UnstructuredContent(), "spec", "key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if diff := cmp.Diff(s, "test"); diff != "" {
+						t.Fatal(diff)
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{},
+			},
+		},
+		"Trait depends on a Workload that's unready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					Traits: []v1alpha2.ComponentTrait{{
+						Trait: runtime.RawExtension{},
+						DataInputs: []v1alpha2.DataInput{{
+							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+							ToFieldPaths: []string{"spec.key"},
+						}},
+					}},
+				}, {
+					ComponentName: "test-component-source",
+					DataOutputs: []v1alpha2.DataOutput{{
+						Name:      "test-output",
+						FieldPath: "status.key",
+					}},
+				}},
+				wl:    unreadyWorkload.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if !ws[0].Traits[0].HasDep {
+						t.Error("Trait should be unready to apply")
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{
+					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
+						Reason: "status.key not found in object",
+						From: v1alpha2.DependencyFromObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyWorkload.GetAPIVersion(),
+								Kind:       unreadyWorkload.GetKind(),
+								Name:       unreadyWorkload.GetName(),
+							},
+							FieldPath: "status.key",
+						},
+						To: v1alpha2.DependencyToObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyTrait.GetAPIVersion(),
+								Kind:       unreadyTrait.GetKind(),
+								Name:       unreadyTrait.GetName(),
+							},
+							FieldPaths: []string{"spec.key"},
+						},
+					}},
+				},
+			},
+		},
+		"Trait depends on a Workload that's ready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					Traits: []v1alpha2.ComponentTrait{{
+						Trait: runtime.RawExtension{},
+						DataInputs: []v1alpha2.DataInput{{
+							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+							ToFieldPaths: []string{"spec.key"},
+						}},
+					}},
+				}, {
+					ComponentName: "test-component-source",
+					DataOutputs: []v1alpha2.DataOutput{{
+						Name:      "test-output",
+						FieldPath: "status.key",
+					}},
+				}},
+				wl:    readyWorkload.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if ws[0].Traits[0].HasDep {
+						t.Error("Trait should be ready to apply")
+					}
+
+					s, _, err := unstructured.NestedString(ws[0].Traits[0].Object.UnstructuredContent(), "spec", "key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if diff := cmp.Diff(s, "test"); diff != "" {
+						t.Fatal(diff)
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{},
+			},
+		},
+		"Trait depends on another Trait that's unready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					Traits: []v1alpha2.ComponentTrait{{
+						Trait: runtime.RawExtension{},
+						DataInputs: []v1alpha2.DataInput{{
+							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+							ToFieldPaths: []string{"spec.key"},
+						}},
+					}, {
+						Trait: runtime.RawExtension{},
+						DataOutputs: []v1alpha2.DataOutput{{
+							Name:      "test-output",
+							FieldPath: "status.key",
+						}},
+					}},
+				}},
+				wl:    unreadyWorkload.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if !ws[0].Traits[0].HasDep {
+						t.Error("Trait should be unready to apply")
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{
+					Unsatisfied: []v1alpha2.UnstaifiedDependency{{
+						Reason: "status.key not found in object",
+						From: v1alpha2.DependencyFromObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyTrait.GetAPIVersion(),
+								Kind:       unreadyTrait.GetKind(),
+								Name:       unreadyTrait.GetName(),
+							},
+							FieldPath: "status.key",
+						},
+						To: v1alpha2.DependencyToObject{
+							TypedReference: runtimev1alpha1.TypedReference{
+								APIVersion: unreadyTrait.GetAPIVersion(),
+								Kind:       unreadyTrait.GetKind(),
+								Name:       unreadyTrait.GetName(),
+							},
+							FieldPaths: []string{"spec.key"},
+						},
+					}},
+				},
+			},
+		},
+		"Trait depends on another Trait that's ready": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					Traits: []v1alpha2.ComponentTrait{{
+						Trait: runtime.RawExtension{},
+						DataInputs: []v1alpha2.DataInput{{
+							ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+							ToFieldPaths: []string{"spec.key"},
+						}},
+					}, {
+						Trait: runtime.RawExtension{},
+						DataOutputs: []v1alpha2.DataOutput{{
+							Name:      "test-output",
+							FieldPath: "status.key",
+						}},
+					}},
+				}},
+				wl:    unreadyWorkload.DeepCopy(),
+				trait: readyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if ws[0].Traits[0].HasDep {
+						t.Error("Trait should be ready to apply")
+					}
+
+					s, _, err := unstructured.NestedString(ws[0].Traits[0].Object.UnstructuredContent(), "spec", "key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if diff := cmp.Diff(s, "test"); diff != "" {
+						t.Fatal(diff)
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{},
+			}},
+		"DataOutputName doesn't exist": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					DataInputs: []v1alpha2.DataInput{{
+						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "wrong-output"},
+						ToFieldPaths: []string{"spec.key"},
+					}},
+				}},
+				wl:    unreadyWorkload.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				err: ErrDataOutputNotExist,
+			},
+		},
+		"DataInput of array type should append": {
+			args: args{
+				components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "test-component-sink",
+					DataInputs: []v1alpha2.DataInput{{
+						ValueFrom:    v1alpha2.DataInputValueFrom{DataOutputName: "test-output"},
+						ToFieldPaths: []string{"spec.key"},
+					}},
+				}, {
+					ComponentName: "test-component-source",
+					DataOutputs: []v1alpha2.DataOutput{{
+						Name:      "test-output",
+						FieldPath: "status.key",
+					}},
+				}},
+				wl:    readyWorkloadArrayField.DeepCopy(),
+				trait: unreadyTrait.DeepCopy(),
+			},
+			want: want{
+				verifyWorkloads: func(ws []Workload) {
+					if ws[0].HasDep {
+						t.Error("Workload should be ready to apply")
+					}
+
+					l, _, err := unstructured.NestedStringSlice(ws[0].Workload.UnstructuredContent(), "spec", "key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if diff := cmp.Diff(l, []string{"a", "b"}); diff != "" {
+						t.Fatal(diff)
+					}
+				},
+				depStatus: &v1alpha2.DependencyStatus{}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := components{
+				dm: mapper,
+				client: &test.MockClient{
+					MockGet: test.MockGetFn(func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+						if obj.GetObjectKind().GroupVersionKind().Kind == "Workload" {
+							b, err := json.Marshal(tc.args.wl)
+							if err != nil {
+								t.Fatal(err)
+							}
+							err = json.Unmarshal(b, obj)
+							if err != nil {
+								t.Fatal(err)
+							}
+						}
+						if obj.GetObjectKind().GroupVersionKind().Kind == "Trait" {
+							b, err := json.Marshal(tc.args.trait)
+							if err != nil {
+								t.Fatal(err)
+							}
+							err = json.Unmarshal(b, obj)
+							if err != nil {
+								t.Fatal(err)
+							}
+						}
+						return nil
+					}),
+				},
+				params: ParameterResolveFn(resolve),
+				workload: ResourceRenderFn(func(data []byte, p ...Parameter) (*unstructured.Unstructured, error) {
+					return tc.args.wl, nil
+				}),
+				trait: ResourceRenderFn(func(data []byte, p ...Parameter) (*unstructured.Unstructured, error) {
+					return tc.args.trait, nil
+				}),
+				schematic: SchematicRenderFn(func(_ context.Context, _ *v1alpha2.Component, w *unstructured.Unstructured, _ ...Parameter) (*unstructured.Unstructured, error) {
+					return w, nil
+				}),
+				validator: ResourceValidateFn(func(context.Context, *unstructured.Unstructured) error {
+					return nil
+				}),
+			}
+
+			ac := &v1alpha2.ApplicationConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-app",
+					Namespace: "test-ns",
+				},
+				Spec: v1alpha2.ApplicationConfigurationSpec{
+					Components: tc.args.components,
+				},
+			}
+
+			ws, ds, err := c.Render(context.Background(), ac)
+			if err != nil {
+				if errors.Is(err, tc.want.err) {
+					return
+				}
+				t.Error(err)
+				return
+			}
+			if diff := cmp.Diff(tc.want.err, err); diff != "" {
+				t.Error(diff)
+				return
+			}
+			tc.want.verifyWorkloads(ws)
+			if diff := cmp.Diff(tc.want.depStatus, ds); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestAddDataOutputsToDAG(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("TestKind")
+	obj.SetNamespace("test-ns")
+	obj.SetName("test-name")
+
+	dag := newDAG()
+	outs := []v1alpha2.DataOutput{{
+		Name:      "test-output",
+		FieldPath: "spec.replica",
+		Conditions: []v1alpha2.ConditionRequirement{{
+			Operator:  v1alpha2.ConditionEqual,
+			Value:     "abc",
+			FieldPath: "status.state",
+		}},
+	}}
+	addDataOutputsToDAG(dag, outs, obj)
+
+	s, ok := dag.Sources["test-output"]
+	if !ok {
+		t.Fatal("didn't add source correctly")
+	}
+
+	r := &corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+		FieldPath:  outs[0].FieldPath,
+	}
+
+	if diff := cmp.Diff(s.ObjectRef, r); diff != "" {
+		t.Errorf("didn't add objectRef to source correctly: %s", diff)
+	}
+
+	if diff := cmp.Diff(s.Conditions, outs[0].Conditions); diff != "" {
+		t.Errorf("didn't add conditions to source correctly: %s", diff)
+	}
+}
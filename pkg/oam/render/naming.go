@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Naming error strings.
+const (
+	errParseNameTemplate      = "cannot parse workload name template"
+	errExecuteNameTemplate    = "cannot execute workload name template"
+	errFmtInvalidWorkloadName = "invalid workload name %q: %s"
+)
+
+// workloadNameValues are the values a workload name template is executed with.
+type workloadNameValues struct {
+	AppConfigName string
+	ComponentName string
+	RevisionName  string
+}
+
+// nameWorkload names the supplied workload after the workload name template
+// of the supplied ApplicationConfiguration, or else of the WorkloadDefinition
+// of the workload. Workloads that specify a name or have no template are left
+// unnamed, so they are named after their component or its revision.
+func (r *components) nameWorkload(ctx context.Context, ac *v1alpha2.ApplicationConfiguration, v workloadNameValues, w *unstructured.Unstructured) error {
+	if w.GetName() != "" {
+		return nil
+	}
+	tmpl := ac.Spec.WorkloadNameTemplate
+	if tmpl == "" {
+		wd, err := r.workloadDefinition(ctx, w)
+		if err != nil {
+			return err
+		}
+		tmpl = wd.Spec.WorkloadNameTemplate
+	}
+	if tmpl == "" {
+		return nil
+	}
+	name, err := renderWorkloadName(tmpl, v)
+	if err != nil {
+		return err
+	}
+	w.SetName(name)
+	return nil
+}
+
+func renderWorkloadName(tmpl string, v workloadNameValues) (string, error) {
+	t, err := template.New("name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, errParseNameTemplate)
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, v); err != nil {
+		return "", errors.Wrap(err, errExecuteNameTemplate)
+	}
+	name := buf.String()
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", errors.Errorf(errFmtInvalidWorkloadName, name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
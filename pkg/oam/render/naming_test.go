@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestNameWorkload(t *testing.T) {
+	errBoom := errors.New("boom")
+	values := workloadNameValues{AppConfigName: "app", ComponentName: "web", RevisionName: "web-v1"}
+	definitionTemplate := func(tmpl string) client.Reader {
+		return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+			if wd, ok := obj.(*v1alpha2.WorkloadDefinition); ok {
+				wd.Spec.WorkloadNameTemplate = tmpl
+			}
+			return nil
+		})}
+	}
+	named := func(name string) *unstructured.Unstructured {
+		w := &unstructured.Unstructured{}
+		w.SetName(name)
+		return w
+	}
+
+	type args struct {
+		client client.Reader
+		ac     *v1alpha2.ApplicationConfiguration
+		w      *unstructured.Unstructured
+	}
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SpecifiedName": {
+			reason: "A workload that specifies a name should keep it",
+			args: args{
+				client: definitionTemplate("{{.AppConfigName}}-{{.ComponentName}}"),
+				ac:     &v1alpha2.ApplicationConfiguration{},
+				w:      named("cool"),
+			},
+			want: want{w: named("cool")},
+		},
+		"AppConfigTemplate": {
+			reason: "The template of the AppConfig should take precedence over the template of the WorkloadDefinition",
+			args: args{
+				client: definitionTemplate("{{.ComponentName}}"),
+				ac:     &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{WorkloadNameTemplate: "{{.AppConfigName}}-{{.RevisionName}}"}},
+				w:      named(""),
+			},
+			want: want{w: named("app-web-v1")},
+		},
+		"DefinitionTemplate": {
+			reason: "The template of the WorkloadDefinition should be used if the AppConfig has none",
+			args: args{
+				client: definitionTemplate("{{.AppConfigName}}-{{.ComponentName}}"),
+				ac:     &v1alpha2.ApplicationConfiguration{},
+				w:      named(""),
+			},
+			want: want{w: named("app-web")},
+		},
+		"NoWorkloadDefinition": {
+			reason: "A workload without WorkloadDefinition and template should be left unnamed",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				ac:     &v1alpha2.ApplicationConfiguration{},
+				w:      named(""),
+			},
+			want: want{w: named("")},
+		},
+		"GetWorkloadDefinitionError": {
+			reason: "Errors getting the WorkloadDefinition should be returned",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				ac:     &v1alpha2.ApplicationConfiguration{},
+				w:      named(""),
+			},
+			want: want{w: named(""), err: errors.Wrapf(errBoom, errFmtGetWorkloadDefinition, "")},
+		},
+		"InvalidName": {
+			reason: "Names that are not valid resource names should be rejected",
+			args: args{
+				client: definitionTemplate("{{.AppConfigName}}_{{.ComponentName}}"),
+				ac:     &v1alpha2.ApplicationConfiguration{},
+				w:      named(""),
+			},
+			want: want{
+				w:   named(""),
+				err: errors.Errorf(errFmtInvalidWorkloadName, "app_web", strings.Join(validation.IsDNS1123Subdomain("app_web"), ", ")),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &components{client: tc.args.client, dm: mock.NewMockDiscoveryMapper()}
+			err := r.nameWorkload(context.Background(), tc.args.ac, values, tc.args.w)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.nameWorkload(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.w, tc.args.w); diff != "" {
+				t.Errorf("\n%s\nr.nameWorkload(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
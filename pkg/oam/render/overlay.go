@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Overlay error strings.
+const (
+	errFmtUnknownOverlayComponent = "overlay of environment %q refers to unknown component %q"
+	errFmtMergeOverlayTrait       = "cannot merge trait %d of overlay of environment %q into component %q"
+	errUnmarshalOverlayTrait      = "cannot unmarshal trait"
+)
+
+// applyOverlays returns a copy of the supplied ApplicationConfiguration with
+// the overlays of its environment applied in order to its components. The
+// supplied ApplicationConfiguration is returned as is when it has no overlays
+// for its environment.
+func applyOverlays(ac *v1alpha2.ApplicationConfiguration) (*v1alpha2.ApplicationConfiguration, error) {
+	if ac.Spec.Environment == "" {
+		return ac, nil
+	}
+	var out *v1alpha2.ApplicationConfiguration
+	for _, o := range ac.Spec.Overlays {
+		if o.Environment != ac.Spec.Environment {
+			continue
+		}
+		if out == nil {
+			out = ac.DeepCopy()
+		}
+		for _, co := range o.Components {
+			idx := -1
+			for i, acc := range out.Spec.Components {
+				if accComponentName(acc) == co.ComponentName {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				return nil, errors.Errorf(errFmtUnknownOverlayComponent, o.Environment, co.ComponentName)
+			}
+			acc := &out.Spec.Components[idx]
+			acc.ParameterValues = overlayParameterValues(acc.ParameterValues, co.ParameterValues)
+			for i, ct := range co.Traits {
+				traits, err := overlayTrait(acc.Traits, ct)
+				if err != nil {
+					return nil, errors.Wrapf(err, errFmtMergeOverlayTrait, i, o.Environment, co.ComponentName)
+				}
+				acc.Traits = traits
+			}
+		}
+	}
+	if out == nil {
+		return ac, nil
+	}
+	return out, nil
+}
+
+// overlayParameterValues replaces the base parameter values that have the
+// same name as an overlay parameter value, and appends the others.
+func overlayParameterValues(base, overlay []v1alpha2.ComponentParameterValue) []v1alpha2.ComponentParameterValue {
+	for _, ov := range overlay {
+		replaced := false
+		for i := range base {
+			if base[i].Name == ov.Name {
+				base[i] = *ov.DeepCopy()
+				replaced = true
+			}
+		}
+		if !replaced {
+			base = append(base, *ov.DeepCopy())
+		}
+	}
+	return base
+}
+
+// overlayTrait merges the supplied overlay trait into the first base trait
+// of the same apiVersion, kind and name, or appends it when there is none.
+func overlayTrait(base []v1alpha2.ComponentTrait, overlay v1alpha2.ComponentTrait) ([]v1alpha2.ComponentTrait, error) {
+	ot := &unstructured.Unstructured{}
+	if err := ot.UnmarshalJSON(overlay.Trait.Raw); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalOverlayTrait)
+	}
+	for i := range base {
+		bt := &unstructured.Unstructured{}
+		if err := bt.UnmarshalJSON(base[i].Trait.Raw); err != nil {
+			return nil, errors.Wrap(err, errUnmarshalOverlayTrait)
+		}
+		if bt.GetAPIVersion() != ot.GetAPIVersion() || bt.GetKind() != ot.GetKind() || bt.GetName() != ot.GetName() {
+			continue
+		}
+		merged, err := jsonpatch.MergePatch(base[i].Trait.Raw, overlay.Trait.Raw)
+		if err != nil {
+			return nil, err
+		}
+		base[i].Trait = runtime.RawExtension{Raw: merged}
+		return base, nil
+	}
+	return append(base, *overlay.DeepCopy()), nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestApplyOverlays(t *testing.T) {
+	scaler := func(raw string) v1alpha2.ComponentTrait {
+		return v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: []byte(raw)}}
+	}
+	appConfig := func(env string, accs []v1alpha2.ApplicationConfigurationComponent, overlays ...v1alpha2.ApplicationConfigurationOverlay) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components:  accs,
+			Environment: env,
+			Overlays:    overlays,
+		}}
+	}
+	base := func() []v1alpha2.ApplicationConfigurationComponent {
+		return []v1alpha2.ApplicationConfigurationComponent{{
+			ComponentName:   "web",
+			ParameterValues: []v1alpha2.ComponentParameterValue{{Name: "image", Value: intstr.FromString("nginx:dev")}},
+			Traits:          []v1alpha2.ComponentTrait{scaler(`{"apiVersion":"core.oam.dev/v1alpha2","kind":"ManualScalerTrait","spec":{"replicaCount":1}}`)},
+		}}
+	}
+	prod := v1alpha2.ApplicationConfigurationOverlay{
+		Environment: "prod",
+		Components: []v1alpha2.ComponentOverlay{{
+			ComponentName: "web",
+			ParameterValues: []v1alpha2.ComponentParameterValue{
+				{Name: "image", Value: intstr.FromString("nginx:1.19")},
+				{Name: "region", Value: intstr.FromString("eu")},
+			},
+			Traits: []v1alpha2.ComponentTrait{
+				scaler(`{"apiVersion":"core.oam.dev/v1alpha2","kind":"ManualScalerTrait","spec":{"replicaCount":3}}`),
+				scaler(`{"apiVersion":"example.com/v1","kind":"Ingress","spec":{"host":"example.com"}}`),
+			},
+		}},
+	}
+
+	cases := map[string]struct {
+		reason string
+		ac     *v1alpha2.ApplicationConfiguration
+		want   *v1alpha2.ApplicationConfiguration
+		err    error
+	}{
+		"NoEnvironment": {
+			reason: "Overlays should not be applied to an ApplicationConfiguration without environment",
+			ac:     appConfig("", base(), prod),
+			want:   appConfig("", base(), prod),
+		},
+		"OtherEnvironment": {
+			reason: "Overlays of other environments should not be applied",
+			ac:     appConfig("dev", base(), prod),
+			want:   appConfig("dev", base(), prod),
+		},
+		"Overlay": {
+			reason: "Parameter values and traits of the overlay should be merged into the components",
+			ac:     appConfig("prod", base(), prod),
+			want: appConfig("prod", []v1alpha2.ApplicationConfigurationComponent{{
+				ComponentName: "web",
+				ParameterValues: []v1alpha2.ComponentParameterValue{
+					{Name: "image", Value: intstr.FromString("nginx:1.19")},
+					{Name: "region", Value: intstr.FromString("eu")},
+				},
+				Traits: []v1alpha2.ComponentTrait{
+					scaler(`{"apiVersion":"core.oam.dev/v1alpha2","kind":"ManualScalerTrait","spec":{"replicaCount":3}}`),
+					scaler(`{"apiVersion":"example.com/v1","kind":"Ingress","spec":{"host":"example.com"}}`),
+				},
+			}}, prod),
+		},
+		"OverlaysInOrder": {
+			reason: "Overlays of the same environment should be applied in order",
+			ac: appConfig("prod", base(), prod, v1alpha2.ApplicationConfigurationOverlay{
+				Environment: "prod",
+				Components: []v1alpha2.ComponentOverlay{{
+					ComponentName:   "web",
+					ParameterValues: []v1alpha2.ComponentParameterValue{{Name: "image", Value: intstr.FromString("nginx:1.20")}},
+				}},
+			}),
+			want: appConfig("prod", []v1alpha2.ApplicationConfigurationComponent{{
+				ComponentName: "web",
+				ParameterValues: []v1alpha2.ComponentParameterValue{
+					{Name: "image", Value: intstr.FromString("nginx:1.20")},
+					{Name: "region", Value: intstr.FromString("eu")},
+				},
+				Traits: []v1alpha2.ComponentTrait{
+					scaler(`{"apiVersion":"core.oam.dev/v1alpha2","kind":"ManualScalerTrait","spec":{"replicaCount":3}}`),
+					scaler(`{"apiVersion":"example.com/v1","kind":"Ingress","spec":{"host":"example.com"}}`),
+				},
+			}}, prod, v1alpha2.ApplicationConfigurationOverlay{
+				Environment: "prod",
+				Components: []v1alpha2.ComponentOverlay{{
+					ComponentName:   "web",
+					ParameterValues: []v1alpha2.ComponentParameterValue{{Name: "image", Value: intstr.FromString("nginx:1.20")}},
+				}},
+			}),
+		},
+		"UnknownComponent": {
+			reason: "An overlay that refers to an unknown component should be rejected",
+			ac: appConfig("prod", base(), v1alpha2.ApplicationConfigurationOverlay{
+				Environment: "prod",
+				Components:  []v1alpha2.ComponentOverlay{{ComponentName: "db"}},
+			}),
+			err: errors.Errorf(errFmtUnknownOverlayComponent, "prod", "db"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			original := tc.ac.DeepCopy()
+			got, err := applyOverlays(tc.ac)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napplyOverlays(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\napplyOverlays(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(original, tc.ac); diff != "" {
+				t.Errorf("\n%s\napplyOverlays(...): must not modify the supplied ApplicationConfiguration:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Patch error strings.
+const (
+	errMarshalPatchedWorkload   = "cannot marshal workload to patch"
+	errUnmarshalPatchedWorkload = "cannot unmarshal patched workload"
+	errFmtApplyPatch            = "cannot apply patch %d"
+	errFmtUnknownPatchType      = "unknown patch type %q"
+)
+
+// patchWorkload applies the supplied patches in order to the supplied
+// workload.
+func patchWorkload(w *unstructured.Unstructured, patches []v1alpha2.ComponentPatch) (*unstructured.Unstructured, error) {
+	if len(patches) == 0 {
+		return w, nil
+	}
+	data, err := json.Marshal(w.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalPatchedWorkload)
+	}
+	for i, p := range patches {
+		switch p.Type {
+		case v1alpha2.StrategicMergePatchType:
+			data, err = strategicMergePatch(w.GroupVersionKind(), data, p.Patch.Raw)
+		case v1alpha2.JSON6902PatchType:
+			data, err = json6902Patch(data, p.Patch.Raw)
+		default:
+			err = errors.Errorf(errFmtUnknownPatchType, p.Type)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtApplyPatch, i)
+		}
+	}
+	patched := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &patched.Object); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalPatchedWorkload)
+	}
+	return patched, nil
+}
+
+func strategicMergePatch(gvk schema.GroupVersionKind, data, patch []byte) ([]byte, error) {
+	obj, err := clientgoscheme.Scheme.New(gvk)
+	if err != nil {
+		// custom resources have no patch strategy, so fall back to a JSON
+		// merge patch like kubectl does.
+		return jsonpatch.MergePatch(data, patch)
+	}
+	return strategicpatch.StrategicMergePatch(data, patch, obj)
+}
+
+func json6902Patch(data, patch []byte) ([]byte, error) {
+	p, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return p.Apply(data)
+}
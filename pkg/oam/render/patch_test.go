@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestPatchWorkload(t *testing.T) {
+	deployment := func(containers ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": containers,
+					},
+				},
+			},
+		}}
+	}
+	container := func(name, image string) interface{} {
+		return map[string]interface{}{"name": name, "image": image}
+	}
+	custom := func(replicas float64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Foo",
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"image":    "nginx",
+			},
+		}}
+	}
+
+	type args struct {
+		w       *unstructured.Unstructured
+		patches []v1alpha2.ComponentPatch
+	}
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoPatches": {
+			reason: "The workload should be returned as is without patches",
+			args: args{
+				w: custom(1),
+			},
+			want: want{
+				w: custom(1),
+			},
+		},
+		"StrategicMerge": {
+			reason: "Containers of a deployment should be merged by name",
+			args: args{
+				w: deployment(container("main", "nginx:1.18"), container("sidecar", "envoy")),
+				patches: []v1alpha2.ComponentPatch{{
+					Type:  v1alpha2.StrategicMergePatchType,
+					Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"spec":{"containers":[{"name":"main","image":"nginx:1.19"}]}}}}`)},
+				}},
+			},
+			want: want{
+				w: deployment(container("main", "nginx:1.19"), container("sidecar", "envoy")),
+			},
+		},
+		"MergeCustomResource": {
+			reason: "Custom resources should be patched with a JSON merge patch",
+			args: args{
+				w: custom(1),
+				patches: []v1alpha2.ComponentPatch{{
+					Type:  v1alpha2.StrategicMergePatchType,
+					Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"replicas":3}}`)},
+				}},
+			},
+			want: want{
+				w: custom(3),
+			},
+		},
+		"JSON6902": {
+			reason: "JSON patch operations should be applied in order",
+			args: args{
+				w: custom(1),
+				patches: []v1alpha2.ComponentPatch{
+					{
+						Type:  v1alpha2.JSON6902PatchType,
+						Patch: runtime.RawExtension{Raw: []byte(`[{"op":"replace","path":"/spec/replicas","value":2}]`)},
+					},
+					{
+						Type:  v1alpha2.JSON6902PatchType,
+						Patch: runtime.RawExtension{Raw: []byte(`[{"op":"replace","path":"/spec/replicas","value":5}]`)},
+					},
+				},
+			},
+			want: want{
+				w: custom(5),
+			},
+		},
+		"UnknownType": {
+			reason: "An error should be returned for an unknown patch type",
+			args: args{
+				w: custom(1),
+				patches: []v1alpha2.ComponentPatch{{
+					Type:  "Unknown",
+					Patch: runtime.RawExtension{Raw: []byte(`{}`)},
+				}},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtUnknownPatchType, "Unknown"), errFmtApplyPatch, 0),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := patchWorkload(tc.args.w, tc.args.patches)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\npatchWorkload(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.w, got); diff != "" {
+				t.Errorf("\n%s\npatchWorkload(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
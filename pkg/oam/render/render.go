@@ -14,13 +14,14 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package applicationconfiguration
+package render
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
 	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
@@ -28,15 +29,18 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/schematic"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 )
 
@@ -53,10 +57,28 @@ const (
 	errFmtResolveParams    = "cannot resolve parameter values for component %q"
 	errFmtRenderWorkload   = "cannot render workload for component %q"
 	errFmtRenderTrait      = "cannot render trait for component %q"
+	errFmtPatchWorkload    = "cannot patch workload for component %q"
+	errFmtRenderAuxiliary  = "cannot render auxiliary workload %q for component %q"
+	errFmtUnknownWorkload  = "trait %q targets unknown workload %q of component %q"
+	errFmtValidateWorkload = "invalid workload for component %q"
+	errFmtValidateTraits   = "invalid traits for component %q"
+	errFmtTraitConflict    = "trait definitions %q and %q conflict and cannot apply to the same workload"
+	errFmtTraitNotApplies  = "trait %q of trait definition %q does not apply to workload %q %q of component %q"
+	errFmtNameWorkload     = "cannot name workload for component %q"
 	errFmtSetParam         = "cannot set parameter %q"
 	errFmtUnsupportedParam = "unsupported parameter %q"
 	errFmtRequiredParam    = "required parameter %q not specified"
+	errFmtInvalidParam     = "invalid value of parameter %q"
 	errSetValueForField    = "can not set value %q for fieldPath %q"
+
+	errFmtGetWorkloadDefinition = "cannot get workload definition %q"
+	errFmtGetTraitDefinition    = "cannot find trait definition %q %q %q"
+	errFmtSetWorkloadRef        = "cannot set trait %q reference to %q"
+	errFmtRenderSchematic       = "cannot render schematic of workload %q"
+	errFmtListHistoryWorkloads  = "cannot list workloads of old revisions of component %q"
+	errFmtSetRevisions          = "cannot set revisions of trait %q"
+	errFmtHandOverWorkload      = "cannot hand workload over to trait for component %q"
+	errFmtWorkloadManagedTwice  = "workload %q cannot be managed by both trait %q and trait %q"
 )
 
 var (
@@ -81,27 +103,62 @@ func (fn ComponentRenderFn) Render(ctx context.Context, ac *v1alpha2.Application
 	return fn(ctx, ac)
 }
 
+// NewComponentRenderer returns a ComponentRenderer that reads Components,
+// ControllerRevisions, definitions and scopes from the supplied reader. It
+// renders workloads and traits the same way the ApplicationConfiguration
+// controller does, without applying them.
+func NewComponentRenderer(c client.Reader, dm discoverymapper.DiscoveryMapper) ComponentRenderer {
+	return &components{
+		client:    c,
+		dm:        dm,
+		params:    ParameterResolveFn(resolve),
+		workload:  ResourceRenderFn(renderWorkload),
+		trait:     ResourceRenderFn(renderTrait),
+		schematic: &schematics{client: c},
+		validator: &crdValidator{client: c, dm: dm},
+	}
+}
+
 var _ ComponentRenderer = &components{}
 
 type components struct {
-	client   client.Reader
-	dm       discoverymapper.DiscoveryMapper
-	params   ParameterResolver
-	workload ResourceRenderer
-	trait    ResourceRenderer
+	client    client.Reader
+	dm        discoverymapper.DiscoveryMapper
+	params    ParameterResolver
+	workload  ResourceRenderer
+	trait     ResourceRenderer
+	schematic SchematicRenderer
+	validator ResourceValidator
 }
 
 func (r *components) Render(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) ([]Workload, *v1alpha2.DependencyStatus, error) {
-	workloads := make([]*Workload, 0, len(ac.Spec.Components))
+	ac, err := applyOverlays(ac)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ac, err = applyScopeTraits(ac); err != nil {
+		return nil, nil, err
+	}
+	order, err := renderOrder(ac.Spec.Components)
+	if err != nil {
+		return nil, nil, err
+	}
+	workloads := make([]*Workload, len(ac.Spec.Components))
+	rendered := make(map[string]*Workload, len(ac.Spec.Components))
 	dag := newDAG()
 
-	for _, acc := range ac.Spec.Components {
+	for _, i := range order {
+		acc := ac.Spec.Components[i]
+		if acc.ParameterValues, err = resolveValueFrom(acc, rendered); err != nil {
+			return nil, nil, err
+		}
 		w, err := r.renderComponent(ctx, acc, ac, dag)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		workloads = append(workloads, w)
+		workloads[i] = w
+		rendered[w.ComponentName] = w
 	}
 
 	ds := &v1alpha2.DependencyStatus{}
@@ -112,6 +169,11 @@ func (r *components) Render(ctx context.Context, ac *v1alpha2.ApplicationConfigu
 			return nil, nil, err
 		}
 		ds.Unsatisfied = append(ds.Unsatisfied, unsatisfied...)
+		// workloads are handed over to the traits that manage them once
+		// their data inputs are filled in
+		if err := handOverWorkloads(workloads[i]); err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtHandOverWorkload, acc.ComponentName)
+		}
 		res = append(res, *workloads[i])
 	}
 
@@ -122,7 +184,17 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 	if acc.RevisionName != "" {
 		acc.ComponentName = ExtractComponentName(acc.RevisionName)
 	}
-	c, componentRevisionName, err := util.GetComponent(ctx, r.client, acc, ac.GetNamespace())
+	pinned, err := util.PinnedRevisionNames(ac)
+	if err != nil {
+		return nil, err
+	}
+	// components pinned at admission are rendered from their pinned revision
+	// rather than the latest one
+	get := acc
+	if rev, ok := pinned[acc.ComponentName]; ok && acc.RevisionName == "" {
+		get.RevisionName = rev
+	}
+	c, componentRevisionName, err := util.GetComponent(ctx, r.client, get, ac.GetNamespace())
 	if err != nil {
 		return nil, err
 	}
@@ -135,6 +207,17 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtRenderWorkload, acc.ComponentName)
 	}
+	// workloads are passed through as is when no schematic renderer is set
+	if r.schematic != nil {
+		w, err = r.schematic.Render(ctx, c, w, p...)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRenderWorkload, acc.ComponentName)
+		}
+	}
+	w, err = patchWorkload(w, acc.Patches)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtPatchWorkload, acc.ComponentName)
+	}
 
 	compInfoLabels := map[string]string{
 		oam.LabelAppName:              ac.Name,
@@ -143,6 +226,9 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 		oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
 	}
 	util.AddLabels(w, compInfoLabels)
+	if componentRevisionName != "" {
+		addRevisionHash(w, c)
+	}
 
 	// pass through labels and annotation from app-config to workload
 	util.PassLabelAndAnnotation(ac, w)
@@ -156,34 +242,91 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 	compInfoLabels[oam.LabelOAMResourceType] = oam.ResourceTypeTrait
 
 	for _, ct := range acc.Traits {
-		t, traitDef, err := r.renderTrait(ctx, ct, ac, acc.ComponentName, ref, dag)
+		t, traitDef, err := r.renderTrait(ctx, ct, ac, acc.ComponentName, componentRevisionName, ref, dag)
 		if err != nil {
 			return nil, err
 		}
 		util.AddLabels(t, compInfoLabels)
+		if traitDef.Name != "" && traitDef.Name != util.Dummy {
+			util.AddLabels(t, map[string]string{oam.LabelAppTrait: traitDef.Name})
+		}
 
 		// pass through labels and annotation from app-config to trait
 		util.PassLabelAndAnnotation(ac, t)
-		traits = append(traits, &Trait{Object: *t, Definition: *traitDef})
+		traits = append(traits, &Trait{Object: *t, Definition: *traitDef, WorkloadName: ct.WorkloadName})
 		traitDefs = append(traitDefs, *traitDef)
 	}
+	if err := checkTraitConflicts(acc.Traits, traitDefs); err != nil {
+		return nil, errors.Wrapf(err, errFmtValidateTraits, acc.ComponentName)
+	}
+	wd, err := r.workloadDefinition(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	nv := workloadNameValues{AppConfigName: ac.Name, ComponentName: acc.ComponentName, RevisionName: componentRevisionName}
+	if err := r.nameWorkload(ctx, ac, nv, w); err != nil {
+		return nil, errors.Wrapf(err, errFmtNameWorkload, acc.ComponentName)
+	}
+	if wd.Spec.RevisionEnabled && w.GetName() == "" && componentRevisionName != "" {
+		// each revision of the component creates a new workload
+		w.SetName(componentRevisionName)
+	}
 	if err := SetWorkloadInstanceName(traitDefs, w, c); err != nil {
 		return nil, err
 	}
-	// create the ref after the workload name is set
-	workloadRef := runtimev1alpha1.TypedReference{
-		APIVersion: w.GetAPIVersion(),
-		Kind:       w.GetKind(),
-		Name:       w.GetName(),
+	// render the auxiliary workloads after the workload name is set, they are
+	// named after it by default.
+	auxiliaries, err := r.renderAuxiliaryWorkloads(c, acc.ComponentName, componentRevisionName, ac, w, ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.validator.Validate(ctx, w); err != nil {
+		return nil, errors.Wrapf(err, errFmtValidateWorkload, acc.ComponentName)
+	}
+	for i := range auxiliaries {
+		if err := r.validator.Validate(ctx, &auxiliaries[i].Object); err != nil {
+			return nil, errors.Wrapf(err, errFmtValidateWorkload, acc.ComponentName)
+		}
+	}
+	// create the refs after the workload names are set
+	targets := map[string]*unstructured.Unstructured{"": w}
+	for i := range auxiliaries {
+		targets[auxiliaries[i].Name] = &auxiliaries[i].Object
 	}
 	//  We only patch a TypedReference object to the trait if it asks for it
-	for i := range acc.Traits {
+	var revisions []v1alpha2.WorkloadRevision
+	for i, ct := range acc.Traits {
 		traitDef := traitDefs[i]
 		trait := traits[i]
-		workloadRefPath := traitDef.Spec.WorkloadRefPath
-		if len(workloadRefPath) != 0 {
-			if err := fieldpath.Pave(trait.Object.UnstructuredContent()).SetValue(workloadRefPath, workloadRef); err != nil {
-				return nil, errors.Wrapf(err, errFmtSetWorkloadRef, trait.Object.GetName(), w.GetName())
+		target, ok := targets[ct.WorkloadName]
+		if !ok {
+			return nil, errors.Errorf(errFmtUnknownWorkload, trait.Object.GetName(), ct.WorkloadName, acc.ComponentName)
+		}
+		applies, err := util.TraitAppliesToWorkload(r.dm, &traitDef, target)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtValidateTraits, acc.ComponentName)
+		}
+		if !applies {
+			return nil, errors.Errorf(errFmtTraitNotApplies, trait.Object.GetName(), traitDef.GetName(),
+				target.GetAPIVersion(), target.GetKind(), acc.ComponentName)
+		}
+		workloadRef := typedReference(target)
+		workloadRefPaths := append([]string{traitDef.Spec.WorkloadRefPath}, traitDef.Spec.WorkloadRefPaths...)
+		if err := setWorkloadRef(&trait.Object, workloadRefPaths, workloadRef); err != nil {
+			return nil, errors.Wrapf(err, errFmtSetWorkloadRef, trait.Object.GetName(), workloadRef.Name)
+		}
+		// Traffic traits ask for the running revisions of the component to
+		// split traffic between them
+		if revisionsPath := traitDef.Spec.RevisionsPath; len(revisionsPath) != 0 {
+			if revisions == nil {
+				history, err := ListHistoryWorkloads(ctx, r.client, ac.Name, acc.ComponentName, componentRevisionName, w)
+				if err != nil {
+					return nil, errors.Wrapf(err, errFmtListHistoryWorkloads, acc.ComponentName)
+				}
+				revisions = WorkloadRevisions(componentRevisionName, w, history)
+			}
+			if err := fieldpath.Pave(trait.Object.UnstructuredContent()).SetValue(revisionsPath, revisions); err != nil {
+				return nil, errors.Wrapf(err, errFmtSetRevisions, trait.Object.GetName())
 			}
 		}
 	}
@@ -199,17 +342,75 @@ func (r *components) renderComponent(ctx context.Context, acc v1alpha2.Applicati
 
 	addDataOutputsToDAG(dag, acc.DataOutputs, w)
 
+	var definitionRevisionName string
+	if rev := wd.Status.LatestRevision; rev != nil {
+		definitionRevisionName = rev.Name
+	}
 	return &Workload{ComponentName: acc.ComponentName, ComponentRevisionName: componentRevisionName,
-		Workload: w, Traits: traits, RevisionEnabled: isRevisionEnabled(traitDefs), Scopes: scopes}, nil
+		Workload: w, AuxiliaryWorkloads: auxiliaries, Traits: traits,
+		RevisionEnabled: wd.Spec.RevisionEnabled || isRevisionEnabled(traitDefs), RevisionHistoryLimit: wd.Spec.RevisionHistoryLimit,
+		Scopes: scopes, HealthPolicy: wd.Spec.HealthPolicy,
+		StatusFields: wd.Spec.StatusFields, StatusMessage: wd.Spec.StatusMessage,
+		DefinitionRevisionName: definitionRevisionName, PodSpecPath: wd.Spec.PodSpecPath}, nil
+}
+
+// workloadDefinition returns the WorkloadDefinition of the supplied workload,
+// or an empty WorkloadDefinition if the workload has none.
+func (r *components) workloadDefinition(ctx context.Context, w *unstructured.Unstructured) (*v1alpha2.WorkloadDefinition, error) {
+	wd, err := util.FetchWorkloadDefinition(ctx, r.client, r.dm, w)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return &v1alpha2.WorkloadDefinition{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetWorkloadDefinition, w.GetKind())
+	}
+	return wd, nil
+}
+
+// addRevisionHash annotates the supplied workload with the revision hash of
+// the supplied component, so trait controllers can tell revisions apart.
+func addRevisionHash(w *unstructured.Unstructured, c *v1alpha2.Component) {
+	w.SetAnnotations(util.MergeMap(w.GetAnnotations(), map[string]string{oam.AnnotationRevisionHash: util.ComputeComponentRevisionHash(c)}))
+}
+
+func (r *components) renderAuxiliaryWorkloads(c *v1alpha2.Component, componentName, componentRevisionName string,
+	ac *v1alpha2.ApplicationConfiguration, w *unstructured.Unstructured, ref *metav1.OwnerReference) ([]AuxiliaryWorkload, error) {
+	var auxiliaries []AuxiliaryWorkload
+	for _, aux := range c.Spec.AuxiliaryWorkloads {
+		aw, err := r.workload.Render(aux.Workload.Raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtRenderAuxiliary, aux.Name, componentName)
+		}
+		if aw.GetName() == "" {
+			aw.SetName(w.GetName() + "-" + aux.Name)
+		}
+		util.AddLabels(aw, map[string]string{
+			oam.LabelAppName:              ac.Name,
+			oam.LabelAppComponent:         componentName,
+			oam.LabelAppComponentRevision: componentRevisionName,
+			oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
+			oam.LabelAppAuxiliaryWorkload: aux.Name,
+		})
+		if componentRevisionName != "" {
+			addRevisionHash(aw, c)
+		}
+		// pass through labels and annotation from app-config to auxiliary workload
+		util.PassLabelAndAnnotation(ac, aw)
+		aw.SetOwnerReferences([]metav1.OwnerReference{*ref})
+		aw.SetNamespace(ac.GetNamespace())
+		auxiliaries = append(auxiliaries, AuxiliaryWorkload{Name: aux.Name, Object: *aw})
+	}
+	return auxiliaries, nil
 }
 
 func (r *components) renderTrait(ctx context.Context, ct v1alpha2.ComponentTrait, ac *v1alpha2.ApplicationConfiguration,
-	componentName string, ref *metav1.OwnerReference, dag *dag) (*unstructured.Unstructured, *v1alpha2.TraitDefinition, error) {
+	componentName, componentRevisionName string, ref *metav1.OwnerReference, dag *dag) (*unstructured.Unstructured, *v1alpha2.TraitDefinition, error) {
 	t, err := r.trait.Render(ct.Trait.Raw)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, errFmtRenderTrait, componentName)
 	}
 
+	generated := t.GetName() == ""
 	traitName := getTraitName(ac, componentName, &ct, t)
 
 	setTraitProperties(t, traitName, ac.GetNamespace(), ref)
@@ -221,6 +422,10 @@ func (r *components) renderTrait(ctx context.Context, ct v1alpha2.ComponentTrait
 		}
 		return nil, nil, errors.Wrapf(err, errFmtGetTraitDefinition, t.GetAPIVersion(), t.GetKind(), t.GetName())
 	}
+	if traitDef.Spec.RevisionEnabled && componentRevisionName != "" {
+		// each revision of the component creates a new trait
+		t.SetName(revisionTraitName(ac, componentName, componentRevisionName, t.GetName(), generated))
+	}
 
 	addDataOutputsToDAG(dag, ct.DataOutputs, t)
 
@@ -239,6 +444,60 @@ func (r *components) renderScope(ctx context.Context, cs v1alpha2.ComponentScope
 	return scopeObject, nil
 }
 
+// checkTraitConflicts returns an error if two of the supplied traits apply to
+// the same workload but their definitions conflict with each other.
+func checkTraitConflicts(cts []v1alpha2.ComponentTrait, traitDefs []v1alpha2.TraitDefinition) error {
+	for i := range traitDefs {
+		for j := i + 1; j < len(traitDefs); j++ {
+			if cts[i].WorkloadName != cts[j].WorkloadName {
+				continue
+			}
+			conflict, err := util.TraitsConflict(&traitDefs[i], &traitDefs[j])
+			if err != nil {
+				return err
+			}
+			if conflict {
+				return errors.Errorf(errFmtTraitConflict, traitDefs[i].GetName(), traitDefs[j].GetName())
+			}
+		}
+	}
+	return nil
+}
+
+// handOverWorkloads hands the workload and auxiliary workloads of the
+// supplied rendered component that are managed by one of its traits, i.e.
+// traits whose definition sets manageWorkload, over to that trait. The trait
+// is annotated with the rendered workload, which is skipped when the
+// component is applied. A workload can be managed by one trait only.
+func handOverWorkloads(w *Workload) error {
+	managedBy := map[string]string{}
+	for _, t := range w.Traits {
+		if !t.Definition.Spec.ManageWorkload {
+			continue
+		}
+		target := w.Workload
+		if t.WorkloadName == "" {
+			w.SkipApply = true
+		}
+		for i := range w.AuxiliaryWorkloads {
+			if t.WorkloadName != "" && w.AuxiliaryWorkloads[i].Name == t.WorkloadName {
+				target = &w.AuxiliaryWorkloads[i].Object
+				w.AuxiliaryWorkloads[i].SkipApply = true
+			}
+		}
+		if other, ok := managedBy[t.WorkloadName]; ok {
+			return errors.Errorf(errFmtWorkloadManagedTwice, target.GetName(), other, t.Object.GetName())
+		}
+		managedBy[t.WorkloadName] = t.Object.GetName()
+		raw, err := json.Marshal(target.Object)
+		if err != nil {
+			return err
+		}
+		t.Object.SetAnnotations(util.MergeMap(t.Object.GetAnnotations(), map[string]string{oam.AnnotationManagedWorkload: string(raw)}))
+	}
+	return nil
+}
+
 func setTraitProperties(t *unstructured.Unstructured, traitName, namespace string, ref *metav1.OwnerReference) {
 	// Set metadata name for `Trait` if the metadata name is NOT set.
 	if t.GetName() == "" {
@@ -249,6 +508,34 @@ func setTraitProperties(t *unstructured.Unstructured, traitName, namespace strin
 	t.SetNamespace(namespace)
 }
 
+// RevisionSuffix returns the suffix of the supplied component revision name
+// that identifies the revision, e.g. v2 for example-component-v2.
+func RevisionSuffix(componentRevisionName string) string {
+	return componentRevisionName[strings.LastIndex(componentRevisionName, "-")+1:]
+}
+
+// ExtractComponentName will extract componentName from revisionName
+func ExtractComponentName(revisionName string) string {
+	splits := strings.Split(revisionName, "-")
+	return strings.Join(splits[0:len(splits)-1], "-")
+}
+
+// revisionTraitName returns the name of the instance of a trait of a
+// revisionEnabled TraitDefinition for the supplied component revision, i.e.
+// the supplied name suffixed with the revision. Generated names are reused
+// from the status of the ApplicationConfiguration, so the suffix of the
+// revision recorded there is replaced.
+func revisionTraitName(ac *v1alpha2.ApplicationConfiguration, componentName, componentRevisionName, name string, generated bool) string {
+	if generated {
+		for _, w := range ac.Status.Workloads {
+			if w.ComponentName == componentName && w.ComponentRevisionName != "" {
+				name = strings.TrimSuffix(name, "-"+RevisionSuffix(w.ComponentRevisionName))
+			}
+		}
+	}
+	return name + "-" + RevisionSuffix(componentRevisionName)
+}
+
 // SetWorkloadInstanceName will set metadata.name for workload CR according to createRevision flag in traitDefinition
 func SetWorkloadInstanceName(traitDefs []v1alpha2.TraitDefinition, w *unstructured.Unstructured, c *v1alpha2.Component) error {
 	// Don't override the specified name
@@ -331,6 +618,81 @@ func renderTrait(data []byte, _ ...Parameter) (*unstructured.Unstructured, error
 	return u, nil
 }
 
+// A SchematicRenderer renders a workload from the template defined in the
+// schematic of its Component or WorkloadDefinition.
+type SchematicRenderer interface {
+	Render(ctx context.Context, c *v1alpha2.Component, w *unstructured.Unstructured, p ...Parameter) (*unstructured.Unstructured, error)
+}
+
+// A SchematicRenderFn renders a workload from the template defined in the
+// schematic of its Component or WorkloadDefinition.
+type SchematicRenderFn func(ctx context.Context, c *v1alpha2.Component, w *unstructured.Unstructured, p ...Parameter) (*unstructured.Unstructured, error)
+
+// Render the supplied workload from its schematic.
+func (fn SchematicRenderFn) Render(ctx context.Context, c *v1alpha2.Component, w *unstructured.Unstructured, p ...Parameter) (*unstructured.Unstructured, error) {
+	return fn(ctx, c, w, p...)
+}
+
+var _ SchematicRenderer = &schematics{}
+
+type schematics struct {
+	client client.Reader
+}
+
+// Render the workload from the schematic of the supplied Component, or from
+// the schematic of the WorkloadDefinition the workload type label refers to.
+// CUE templates are filled with the spec of the workload, while Go templates
+// are executed with the supplied parameters as data. Workloads without a
+// templated schematic are returned as is.
+func (s *schematics) Render(ctx context.Context, c *v1alpha2.Component, w *unstructured.Unstructured, p ...Parameter) (*unstructured.Unstructured, error) {
+	sc := c.Spec.Schematic
+	if !schematic.IsTemplated(sc) {
+		// templated workload kinds have no CRD, so their definition can only
+		// be referred to by the workload type label.
+		typ, ok := w.GetLabels()[oam.WorkloadTypeLabel]
+		if !ok {
+			return w, nil
+		}
+		wd := &v1alpha2.WorkloadDefinition{}
+		if err := s.client.Get(ctx, types.NamespacedName{Name: typ}, wd); err != nil {
+			if apierrors.IsNotFound(err) {
+				return w, nil
+			}
+			return nil, errors.Wrapf(err, errFmtGetWorkloadDefinition, typ)
+		}
+		sc = wd.Spec.Schematic
+	}
+	if !schematic.IsTemplated(sc) {
+		return w, nil
+	}
+
+	sctx := schematic.Context{ComponentName: c.GetName(), RevisionHash: util.ComputeComponentRevisionHash(c)}
+	if c.Status.LatestRevision != nil {
+		sctx.RevisionName = c.Status.LatestRevision.Name
+	}
+	spec, _ := w.Object["spec"].(map[string]interface{})
+	values := make(map[string]interface{}, len(p))
+	for _, param := range p {
+		switch param.Value.Type {
+		case intstr.String:
+			values[param.Name] = param.Value.StrVal
+		case intstr.Int:
+			values[param.Name] = param.Value.IntVal
+		}
+	}
+	rw, err := schematic.Render(sc, spec, values, sctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtRenderSchematic, w.GetName())
+	}
+	// keep the metadata of the templated workload
+	if rw.GetName() == "" {
+		rw.SetName(w.GetName())
+	}
+	rw.SetLabels(util.MergeMap(rw.GetLabels(), w.GetLabels()))
+	rw.SetAnnotations(util.MergeMap(rw.GetAnnotations(), w.GetAnnotations()))
+	return rw, nil
+}
+
 // A Parameter may be used to set the supplied paths to the supplied value.
 type Parameter struct {
 	// Name of this parameter.
@@ -374,6 +736,11 @@ func resolve(cp []v1alpha2.ComponentParameter, cpv []v1alpha2.ComponentParameter
 
 	for _, p := range cp {
 		_, ok := set[p.Name]
+		if !ok && p.Default != nil {
+			// This parameter is not set, fall back to its default value.
+			set[p.Name] = &Parameter{Name: p.Name, Value: *p.Default}
+			ok = true
+		}
 		if !ok && p.Required != nil && *p.Required {
 			// This parameter is required, but not set.
 			return nil, errors.Errorf(errFmtRequiredParam, p.Name)
@@ -383,6 +750,15 @@ func resolve(cp []v1alpha2.ComponentParameter, cpv []v1alpha2.ComponentParameter
 			continue
 		}
 
+		if errs := util.ValidateParameterValue(field.NewPath("parameterValues").Key(p.Name), p, set[p.Name].Value); len(errs) > 0 {
+			return nil, errors.Wrapf(errs.ToAggregate(), errFmtInvalidParam, p.Name)
+		}
+
+		v, err := transform(set[p.Name].Value, p.Transforms)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtTransformParam, p.Name)
+		}
+		set[p.Name].Value = v
 		set[p.Name].FieldPaths = p.FieldPaths
 	}
 
@@ -14,11 +14,12 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package applicationconfiguration
+package render
 
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
@@ -30,11 +31,15 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -56,6 +61,8 @@ func TestRenderComponents(t *testing.T) {
 	componentName := "coolcomponent"
 	workloadName := "coolworkload"
 	traitName := "coolTrait"
+	historyLimit := int32(2)
+	traitDefName := "cooltraits.example.com"
 	revisionName := "coolcomponent-aa1111"
 	revisionName2 := "coolcomponent-bb2222"
 
@@ -89,6 +96,29 @@ func TestRenderComponents(t *testing.T) {
 			},
 		},
 	}
+	labeledAC := ac.DeepCopy()
+	labeledAC.SetLabels(map[string]string{"team": "cool"})
+	labeledAC.SetAnnotations(map[string]string{
+		"owner":                            "cool",
+		corev1.LastAppliedConfigAnnotation: "{}",
+	})
+	auxAC := ac.DeepCopy()
+	auxAC.Spec.Components[0].Traits[0].WorkloadName = "config"
+	pinnedAC := ac.DeepCopy()
+	pinnedAC.SetAnnotations(map[string]string{
+		oam.AnnotationPinRevisions:    "true",
+		oam.AnnotationPinnedRevisions: `{"` + componentName + `":"` + revisionName + `"}`,
+	})
+	auxComponent := &v1alpha2.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: componentName, Namespace: namespace},
+		Spec: v1alpha2.ComponentSpec{
+			Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)},
+			AuxiliaryWorkloads: []v1alpha2.AuxiliaryWorkload{{
+				Name:     "config",
+				Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)},
+			}},
+		},
+	}
 	ref := metav1.NewControllerRef(ac, v1alpha2.ApplicationConfigurationGroupVersionKind)
 	errTrait := errors.New("errTrait")
 
@@ -122,6 +152,21 @@ func TestRenderComponents(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtGetComponent, componentName),
 			},
 		},
+		"GetPinnedRevisionError": {
+			reason: "Components pinned at admission should be rendered from the revision they are pinned to",
+			fields: fields{
+				client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if _, ok := obj.(*v1.ControllerRevision); ok && key.Name == revisionName {
+						return errBoom
+					}
+					return nil
+				}},
+			},
+			args: args{ac: pinnedAC},
+			want: want{
+				err: errors.Wrapf(errBoom, "cannot get component revision %q", revisionName),
+			},
+		},
 		"ResolveParamsError": {
 			reason: "An error resolving the parameters of a component should be returned",
 			fields: fields{
@@ -258,6 +303,216 @@ func TestRenderComponents(t *testing.T) {
 				},
 			},
 		},
+		"SuccessWithStandardLabels": {
+			reason: "Rendered resources should carry the trait label and the labels and annotations of the AppConfig",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					if td, ok := obj.(*v1alpha2.TraitDefinition); ok {
+						td.SetName(traitDefName)
+					}
+					return nil
+				})},
+				params: ParameterResolveFn(func(_ []v1alpha2.ComponentParameter, _ []v1alpha2.ComponentParameterValue) ([]Parameter, error) {
+					return nil, nil
+				}),
+				workload: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					w := &unstructured.Unstructured{}
+					w.SetName(workloadName)
+					return w, nil
+				}),
+				trait: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					t := &unstructured.Unstructured{}
+					t.SetName(traitName)
+					return t, nil
+				}),
+			},
+			args: args{ac: labeledAC},
+			want: want{
+				w: []Workload{
+					{
+						ComponentName: componentName,
+						Workload: func() *unstructured.Unstructured {
+							w := &unstructured.Unstructured{}
+							w.SetNamespace(namespace)
+							w.SetName(workloadName)
+							w.SetOwnerReferences([]metav1.OwnerReference{*ref})
+							w.SetLabels(map[string]string{
+								"team":                        "cool",
+								oam.LabelAppComponent:         componentName,
+								oam.LabelAppName:              acName,
+								oam.LabelAppComponentRevision: "",
+								oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
+							})
+							w.SetAnnotations(map[string]string{"owner": "cool"})
+							return w
+						}(),
+						Traits: []*Trait{
+							func() *Trait {
+								t := &unstructured.Unstructured{}
+								t.SetNamespace(namespace)
+								t.SetName(traitName)
+								t.SetOwnerReferences([]metav1.OwnerReference{*ref})
+								t.SetLabels(map[string]string{
+									"team":                        "cool",
+									oam.LabelAppComponent:         componentName,
+									oam.LabelAppName:              acName,
+									oam.LabelAppComponentRevision: "",
+									oam.LabelOAMResourceType:      oam.ResourceTypeTrait,
+									oam.LabelAppTrait:             traitDefName,
+								})
+								t.SetAnnotations(map[string]string{"owner": "cool"})
+								td := v1alpha2.TraitDefinition{}
+								td.SetName(traitDefName)
+								return &Trait{Object: *t, Definition: td}
+							}(),
+						},
+						Scopes: []unstructured.Unstructured{},
+					},
+				},
+			},
+		},
+		"SuccessWithAuxiliaryWorkload": {
+			reason: "Auxiliary workloads should be rendered alongside the workload and be referenced by their traits",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					switch o := obj.(type) {
+					case *v1alpha2.Component:
+						c := auxComponent.DeepCopy()
+						c.DeepCopyInto(o)
+					case *v1alpha2.TraitDefinition:
+						o.Spec.WorkloadRefPath = "spec.workloadRef"
+					}
+					return nil
+				})},
+				params: ParameterResolveFn(func(_ []v1alpha2.ComponentParameter, _ []v1alpha2.ComponentParameterValue) ([]Parameter, error) {
+					return nil, nil
+				}),
+				workload: ResourceRenderFn(renderWorkload),
+				trait: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					t := &unstructured.Unstructured{}
+					t.SetName(traitName)
+					return t, nil
+				}),
+			},
+			args: args{ac: auxAC},
+			want: want{
+				w: []Workload{
+					{
+						ComponentName: componentName,
+						Workload: func() *unstructured.Unstructured {
+							w := &unstructured.Unstructured{}
+							w.SetAPIVersion("apps/v1")
+							w.SetKind("Deployment")
+							w.SetNamespace(namespace)
+							w.SetName(componentName)
+							w.SetOwnerReferences([]metav1.OwnerReference{*ref})
+							w.SetLabels(map[string]string{
+								oam.LabelAppComponent:         componentName,
+								oam.LabelAppName:              acName,
+								oam.LabelAppComponentRevision: "",
+								oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
+							})
+							return w
+						}(),
+						AuxiliaryWorkloads: []AuxiliaryWorkload{
+							func() AuxiliaryWorkload {
+								a := &unstructured.Unstructured{}
+								a.SetAPIVersion("v1")
+								a.SetKind("ConfigMap")
+								a.SetNamespace(namespace)
+								a.SetName(componentName + "-config")
+								a.SetOwnerReferences([]metav1.OwnerReference{*ref})
+								a.SetLabels(map[string]string{
+									oam.LabelAppComponent:         componentName,
+									oam.LabelAppName:              acName,
+									oam.LabelAppComponentRevision: "",
+									oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
+									oam.LabelAppAuxiliaryWorkload: "config",
+								})
+								return AuxiliaryWorkload{Name: "config", Object: *a}
+							}(),
+						},
+						Traits: []*Trait{
+							func() *Trait {
+								t := &unstructured.Unstructured{}
+								t.SetNamespace(namespace)
+								t.SetName(traitName)
+								t.SetOwnerReferences([]metav1.OwnerReference{*ref})
+								t.SetLabels(map[string]string{
+									oam.LabelAppComponent:         componentName,
+									oam.LabelAppName:              acName,
+									oam.LabelAppComponentRevision: "",
+									oam.LabelOAMResourceType:      oam.ResourceTypeTrait,
+								})
+								_ = unstructured.SetNestedMap(t.Object, map[string]interface{}{
+									"apiVersion": "v1",
+									"kind":       "ConfigMap",
+									"name":       componentName + "-config",
+								}, "spec", "workloadRef")
+								td := v1alpha2.TraitDefinition{}
+								td.Spec.WorkloadRefPath = "spec.workloadRef"
+								return &Trait{Object: *t, Definition: td, WorkloadName: "config"}
+							}(),
+						},
+						Scopes: []unstructured.Unstructured{},
+					},
+				},
+			},
+		},
+		"UnknownTraitWorkload": {
+			reason: "An error should be returned if a trait targets a workload the component does not have",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				params: ParameterResolveFn(func(_ []v1alpha2.ComponentParameter, _ []v1alpha2.ComponentParameterValue) ([]Parameter, error) {
+					return nil, nil
+				}),
+				workload: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					w := &unstructured.Unstructured{}
+					w.SetName(workloadName)
+					return w, nil
+				}),
+				trait: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					t := &unstructured.Unstructured{}
+					t.SetName(traitName)
+					return t, nil
+				}),
+			},
+			args: args{ac: auxAC},
+			want: want{
+				err: errors.Errorf(errFmtUnknownWorkload, traitName, "config", componentName),
+			},
+		},
+		"TraitNotApplies": {
+			reason: "An error should be returned if a trait does not apply to the workload it targets",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					if td, ok := obj.(*v1alpha2.TraitDefinition); ok {
+						td.SetName(traitDefName)
+						td.Spec.AppliesToWorkloads = []string{"*.example.com"}
+					}
+					return nil
+				})},
+				params: ParameterResolveFn(func(_ []v1alpha2.ComponentParameter, _ []v1alpha2.ComponentParameterValue) ([]Parameter, error) {
+					return nil, nil
+				}),
+				workload: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					w := &unstructured.Unstructured{}
+					w.SetAPIVersion("apps/v1")
+					w.SetKind("Deployment")
+					w.SetName(workloadName)
+					return w, nil
+				}),
+				trait: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					t := &unstructured.Unstructured{}
+					t.SetName(traitName)
+					return t, nil
+				}),
+			},
+			args: args{ac: ac},
+			want: want{
+				err: errors.Errorf(errFmtTraitNotApplies, traitName, traitDefName, "apps/v1", "Deployment", componentName),
+			},
+		},
 		"Success-With-RevisionName": {
 			reason: "Workload should successfully be rendered with fixed componentRevision",
 			fields: fields{
@@ -311,6 +566,11 @@ func TestRenderComponents(t *testing.T) {
 								oam.LabelAppComponentRevision: revisionName,
 								oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
 							})
+							w.SetAnnotations(map[string]string{
+								oam.AnnotationRevisionHash: util.ComputeComponentRevisionHash(&v1alpha2.Component{
+									Spec: v1alpha2.ComponentSpec{Workload: runtime.RawExtension{Object: &unstructured.Unstructured{}}},
+								}),
+							})
 							return w
 						}(),
 						Traits: []*Trait{
@@ -334,7 +594,7 @@ func TestRenderComponents(t *testing.T) {
 			},
 		},
 		"Success-With-RevisionEnabledTrait": {
-			reason: "Workload name should successfully be rendered with revisionName",
+			reason: "Workload and trait names should successfully be rendered with revisionName",
 			fields: fields{
 				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
 					switch robj := obj.(type) {
@@ -377,19 +637,23 @@ func TestRenderComponents(t *testing.T) {
 								oam.LabelAppComponentRevision: revisionName2,
 								oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
 							})
+							w.SetAnnotations(map[string]string{
+								oam.AnnotationRevisionHash: util.ComputeComponentRevisionHash(&v1alpha2.Component{}),
+							})
 							return w
 						}(),
 						Traits: []*Trait{
 							func() *Trait {
 								t := &unstructured.Unstructured{}
 								t.SetNamespace(namespace)
-								t.SetName(traitName)
+								t.SetName(traitName + "-bb2222")
 								t.SetOwnerReferences([]metav1.OwnerReference{*ref})
 								t.SetLabels(map[string]string{
 									oam.LabelAppComponent:         componentName,
 									oam.LabelAppName:              acName,
 									oam.LabelAppComponentRevision: revisionName2,
 									oam.LabelOAMResourceType:      oam.ResourceTypeTrait,
+									oam.LabelAppTrait:             traitName,
 								})
 								return &Trait{Object: *t,
 									Definition: v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: "coolTrait"}, Spec: v1alpha2.TraitDefinitionSpec{RevisionEnabled: true}}}
@@ -401,6 +665,169 @@ func TestRenderComponents(t *testing.T) {
 				},
 			},
 		},
+		"Success-With-RevisionsPathTrait": {
+			reason: "Traits with a revisionsPath should be rendered with the running revisions of their component, newest first",
+			fields: fields{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+						switch robj := obj.(type) {
+						case *v1alpha2.Component:
+							ccomp := v1alpha2.Component{Status: v1alpha2.ComponentStatus{LatestRevision: &v1alpha2.Revision{Name: revisionName2}}}
+							ccomp.DeepCopyInto(robj)
+						case *v1alpha2.TraitDefinition:
+							ttrait := v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: traitName}, Spec: v1alpha2.TraitDefinitionSpec{RevisionEnabled: true, RevisionsPath: "spec.revisions"}}
+							ttrait.DeepCopyInto(robj)
+						}
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(obj runtime.Object) error {
+						old := unstructured.Unstructured{}
+						old.SetName(revisionName)
+						old.SetUID("old-uid")
+						old.SetLabels(map[string]string{oam.LabelAppComponentRevision: revisionName})
+						obj.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{old}
+						return nil
+					}),
+				},
+				params: ParameterResolveFn(func(_ []v1alpha2.ComponentParameter, _ []v1alpha2.ComponentParameterValue) ([]Parameter, error) {
+					return nil, nil
+				}),
+				workload: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					w := &unstructured.Unstructured{}
+					return w, nil
+				}),
+				trait: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					t := &unstructured.Unstructured{}
+					t.SetName(traitName)
+					return t, nil
+				}),
+			},
+			args: args{ac: ac},
+			want: want{
+				w: []Workload{
+					{
+						ComponentName:         componentName,
+						ComponentRevisionName: revisionName2,
+						Workload: func() *unstructured.Unstructured {
+							w := &unstructured.Unstructured{}
+							w.SetNamespace(namespace)
+							w.SetName(revisionName2)
+							w.SetOwnerReferences([]metav1.OwnerReference{*ref})
+							w.SetLabels(map[string]string{
+								oam.LabelAppComponent:         componentName,
+								oam.LabelAppName:              acName,
+								oam.LabelAppComponentRevision: revisionName2,
+								oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
+							})
+							w.SetAnnotations(map[string]string{
+								oam.AnnotationRevisionHash: util.ComputeComponentRevisionHash(&v1alpha2.Component{}),
+							})
+							return w
+						}(),
+						Traits: []*Trait{
+							func() *Trait {
+								t := &unstructured.Unstructured{}
+								t.SetNamespace(namespace)
+								t.SetName(traitName + "-bb2222")
+								t.SetOwnerReferences([]metav1.OwnerReference{*ref})
+								t.SetLabels(map[string]string{
+									oam.LabelAppComponent:         componentName,
+									oam.LabelAppName:              acName,
+									oam.LabelAppComponentRevision: revisionName2,
+									oam.LabelOAMResourceType:      oam.ResourceTypeTrait,
+									oam.LabelAppTrait:             traitName,
+								})
+								_ = unstructured.SetNestedSlice(t.Object, []interface{}{
+									map[string]interface{}{
+										"revisionName": revisionName2,
+										"workloadRef":  map[string]interface{}{"apiVersion": "", "kind": "", "name": revisionName2},
+									},
+									map[string]interface{}{
+										"revisionName": revisionName,
+										"workloadRef":  map[string]interface{}{"apiVersion": "", "kind": "", "name": revisionName, "uid": "old-uid"},
+									},
+								}, "spec", "revisions")
+								return &Trait{Object: *t,
+									Definition: v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: traitName}, Spec: v1alpha2.TraitDefinitionSpec{RevisionEnabled: true, RevisionsPath: "spec.revisions"}}}
+							}(),
+						},
+						RevisionEnabled: true,
+						Scopes:          []unstructured.Unstructured{},
+					},
+				},
+			},
+		},
+		"Success-With-RevisionEnabledWorkload": {
+			reason: "Workload should be named after the component revision if its WorkloadDefinition is revisionEnabled",
+			fields: fields{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj runtime.Object) error {
+					switch robj := obj.(type) {
+					case *v1alpha2.Component:
+						ccomp := v1alpha2.Component{Status: v1alpha2.ComponentStatus{LatestRevision: &v1alpha2.Revision{Name: revisionName2}}}
+						ccomp.DeepCopyInto(robj)
+					case *v1alpha2.WorkloadDefinition:
+						wd := v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{RevisionEnabled: true, RevisionHistoryLimit: &historyLimit}}
+						wd.DeepCopyInto(robj)
+					}
+					return nil
+				})},
+				params: ParameterResolveFn(func(_ []v1alpha2.ComponentParameter, _ []v1alpha2.ComponentParameterValue) ([]Parameter, error) {
+					return nil, nil
+				}),
+				workload: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					w := &unstructured.Unstructured{}
+					return w, nil
+				}),
+				trait: ResourceRenderFn(func(_ []byte, _ ...Parameter) (*unstructured.Unstructured, error) {
+					t := &unstructured.Unstructured{}
+					t.SetName(traitName)
+					return t, nil
+				}),
+			},
+			args: args{ac: ac},
+			want: want{
+				w: []Workload{
+					{
+						ComponentName:         componentName,
+						ComponentRevisionName: revisionName2,
+						Workload: func() *unstructured.Unstructured {
+							w := &unstructured.Unstructured{}
+							w.SetNamespace(namespace)
+							w.SetName(revisionName2)
+							w.SetOwnerReferences([]metav1.OwnerReference{*ref})
+							w.SetLabels(map[string]string{
+								oam.LabelAppComponent:         componentName,
+								oam.LabelAppName:              acName,
+								oam.LabelAppComponentRevision: revisionName2,
+								oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
+							})
+							w.SetAnnotations(map[string]string{
+								oam.AnnotationRevisionHash: util.ComputeComponentRevisionHash(&v1alpha2.Component{}),
+							})
+							return w
+						}(),
+						Traits: []*Trait{
+							func() *Trait {
+								t := &unstructured.Unstructured{}
+								t.SetNamespace(namespace)
+								t.SetName(traitName)
+								t.SetOwnerReferences([]metav1.OwnerReference{*ref})
+								t.SetLabels(map[string]string{
+									oam.LabelAppComponent:         componentName,
+									oam.LabelAppName:              acName,
+									oam.LabelAppComponentRevision: revisionName2,
+									oam.LabelOAMResourceType:      oam.ResourceTypeTrait,
+								})
+								return &Trait{Object: *t}
+							}(),
+						},
+						RevisionEnabled:      true,
+						RevisionHistoryLimit: &historyLimit,
+						Scopes:               []unstructured.Unstructured{},
+					},
+				},
+			},
+		},
 		"Success-With-WorkloadRef": {
 			reason: "Workload should successfully be rendered with fixed componentRevision",
 			fields: fields{
@@ -467,6 +894,11 @@ func TestRenderComponents(t *testing.T) {
 								oam.LabelAppComponentRevision: revisionName,
 								oam.LabelOAMResourceType:      oam.ResourceTypeWorkload,
 							})
+							w.SetAnnotations(map[string]string{
+								oam.AnnotationRevisionHash: util.ComputeComponentRevisionHash(&v1alpha2.Component{
+									Spec: v1alpha2.ComponentSpec{Workload: runtime.RawExtension{Object: &unstructured.Unstructured{}}},
+								}),
+							})
 							return w
 						}(),
 						Traits: []*Trait{
@@ -500,7 +932,10 @@ func TestRenderComponents(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := &components{tc.fields.client, mock.NewMockDiscoveryMapper(), tc.fields.params, tc.fields.workload, tc.fields.trait}
+			r := &components{tc.fields.client, mock.NewMockDiscoveryMapper(), tc.fields.params, tc.fields.workload, tc.fields.trait,
+				&schematics{client: tc.fields.client}, ResourceValidateFn(func(context.Context, *unstructured.Unstructured) error {
+					return nil
+				})}
 			got, _, err := r.Render(tc.args.ctx, tc.args.ac)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Render(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -661,6 +1096,7 @@ func TestResolveParams(t *testing.T) {
 	required := true
 	paths := []string{"metadata.name"}
 	value := "cool"
+	defaultValue := intstr.FromString("default")
 
 	type args struct {
 		cp  []v1alpha2.ComponentParameter
@@ -704,6 +1140,27 @@ func TestResolveParams(t *testing.T) {
 				err: errors.Errorf(errFmtUnsupportedParam, paramName),
 			},
 		},
+		"InvalidValue": {
+			reason: "An error should be returned when a parameter value does not match the type of its parameter",
+			args: args{
+				cp: []v1alpha2.ComponentParameter{
+					{
+						Name: paramName,
+						Type: v1alpha2.IntegerParameterType,
+					},
+				},
+				cpv: []v1alpha2.ComponentParameterValue{
+					{
+						Name:  paramName,
+						Value: intstr.FromString(value),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(field.ErrorList{field.Invalid(field.NewPath("parameterValues").Key(paramName), value,
+					fmt.Sprintf("parameter %q must be an integer", paramName))}.ToAggregate(), errFmtInvalidParam, paramName),
+			},
+		},
 		"MissingNotRequired": {
 			reason: "Nothing should be returned when an optional parameter is omitted",
 			args: args{
@@ -716,6 +1173,56 @@ func TestResolveParams(t *testing.T) {
 			},
 			want: want{},
 		},
+		"MissingRequiredWithDefault": {
+			reason: "The default value should be returned when a required parameter with a default is omitted",
+			args: args{
+				cp: []v1alpha2.ComponentParameter{
+					{
+						Name:       paramName,
+						FieldPaths: paths,
+						Required:   &required,
+						Default:    &defaultValue,
+					},
+				},
+				cpv: []v1alpha2.ComponentParameterValue{},
+			},
+			want: want{
+				p: []Parameter{
+					{
+						Name:       paramName,
+						FieldPaths: paths,
+						Value:      defaultValue,
+					},
+				},
+			},
+		},
+		"SetOverridesDefault": {
+			reason: "The supplied value should take precedence over the default value",
+			args: args{
+				cp: []v1alpha2.ComponentParameter{
+					{
+						Name:       paramName,
+						FieldPaths: paths,
+						Default:    &defaultValue,
+					},
+				},
+				cpv: []v1alpha2.ComponentParameterValue{
+					{
+						Name:  paramName,
+						Value: intstr.FromString(value),
+					},
+				},
+			},
+			want: want{
+				p: []Parameter{
+					{
+						Name:       paramName,
+						FieldPaths: paths,
+						Value:      intstr.FromString(value),
+					},
+				},
+			},
+		},
 		"SupportedAndSet": {
 			reason: "A parameter should be returned when it is supported and set",
 			args: args{
@@ -742,6 +1249,49 @@ func TestResolveParams(t *testing.T) {
 				},
 			},
 		},
+		"TransformedValue": {
+			reason: "The transforms of a parameter should be applied to its value",
+			args: args{
+				cp: []v1alpha2.ComponentParameter{
+					{
+						Name:       paramName,
+						FieldPaths: paths,
+						Transforms: []v1alpha2.ParameterTransform{{Type: v1alpha2.UpperTransformType}},
+					},
+				},
+				cpv: []v1alpha2.ComponentParameterValue{
+					{
+						Name:  paramName,
+						Value: intstr.FromString(value),
+					},
+				},
+			},
+			want: want{
+				p: []Parameter{
+					{
+						Name:       paramName,
+						FieldPaths: paths,
+						Value:      intstr.FromString("COOL"),
+					},
+				},
+			},
+		},
+		"TransformError": {
+			reason: "Errors transforming a parameter should be returned",
+			args: args{
+				cp: []v1alpha2.ComponentParameter{
+					{
+						Name:       paramName,
+						Transforms: []v1alpha2.ParameterTransform{{Type: "reverse"}},
+						Default:    &defaultValue,
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrapf(errors.Errorf(errFmtUnknownTransform, "reverse"), errFmtTransform, 0, "reverse"),
+					errFmtTransformParam, paramName),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -757,6 +1307,163 @@ func TestResolveParams(t *testing.T) {
 	}
 }
 
+func TestRenderSchematic(t *testing.T) {
+	errBoom := errors.New("boom")
+	workloadType := "webservice"
+	template := `
+parameter: image: string
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	spec: template: spec: containers: [{image: parameter.image}]
+}
+`
+	goTemplate := `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+        - image: {{ .image }}
+`
+	templated := func() *unstructured.Unstructured {
+		w := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "core.oam.dev/v1alpha2",
+			"kind":       "WebService",
+			"spec": map[string]interface{}{
+				"image": "nginx",
+			},
+		}}
+		w.SetName("web")
+		w.SetLabels(map[string]string{oam.WorkloadTypeLabel: workloadType})
+		return w
+	}
+	rendered := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"image": "nginx"},
+					},
+				},
+			},
+		},
+	}}
+	rendered.SetName("web")
+	rendered.SetLabels(map[string]string{oam.WorkloadTypeLabel: workloadType})
+
+	type args struct {
+		client client.Reader
+		c      *v1alpha2.Component
+		w      *unstructured.Unstructured
+		p      []Parameter
+	}
+	type want struct {
+		w   *unstructured.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSchematic": {
+			reason: "A workload without schematic should be returned as is",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				c:      &v1alpha2.Component{},
+				w:      &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Deployment"}},
+			},
+			want: want{
+				w: &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Deployment"}},
+			},
+		},
+		"ComponentSchematic": {
+			reason: "A workload should be rendered from the schematic of its component",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				c: &v1alpha2.Component{Spec: v1alpha2.ComponentSpec{
+					Schematic: &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: template}},
+				}},
+				w: templated(),
+			},
+			want: want{
+				w: rendered,
+			},
+		},
+		"DefinitionSchematic": {
+			reason: "A workload should be rendered from the schematic of the definition its type label refers to",
+			args: args{
+				client: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if key.Name != workloadType {
+						return errBoom
+					}
+					wd := obj.(*v1alpha2.WorkloadDefinition)
+					wd.Spec.Schematic = &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: template}}
+					return nil
+				}},
+				c: &v1alpha2.Component{},
+				w: templated(),
+			},
+			want: want{
+				w: rendered,
+			},
+		},
+		"DefinitionNotFound": {
+			reason: "A workload should be returned as is if its definition is not found",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, workloadType))},
+				c:      &v1alpha2.Component{},
+				w:      templated(),
+			},
+			want: want{
+				w: templated(),
+			},
+		},
+		"ComponentGoTemplate": {
+			reason: "A workload should be rendered from the Go template of its component with the parameters as data",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				c: &v1alpha2.Component{Spec: v1alpha2.ComponentSpec{
+					Schematic: &v1alpha2.Schematic{GoTemplate: &v1alpha2.GoTemplate{Template: goTemplate}},
+				}},
+				w: templated(),
+				p: []Parameter{{Name: "image", Value: intstr.FromString("nginx")}},
+			},
+			want: want{
+				w: rendered,
+			},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the definition should be returned",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				c:      &v1alpha2.Component{},
+				w:      templated(),
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetWorkloadDefinition, workloadType),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &schematics{client: tc.args.client}
+			got, err := r.Render(context.Background(), tc.args.c, tc.args.w, tc.args.p...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Render(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.w, got); diff != "" {
+				t.Errorf("\n%s\nr.Render(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestRenderTraitWithoutMetadataName(t *testing.T) {
 	namespace := "ns"
 	acName := "coolappconfig"
@@ -846,7 +1553,10 @@ func TestRenderTraitWithoutMetadataName(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := &components{tc.fields.client, mock.NewMockDiscoveryMapper(), tc.fields.params, tc.fields.workload, tc.fields.trait}
+			r := &components{tc.fields.client, mock.NewMockDiscoveryMapper(), tc.fields.params, tc.fields.workload, tc.fields.trait,
+				&schematics{client: tc.fields.client}, ResourceValidateFn(func(context.Context, *unstructured.Unstructured) error {
+					return nil
+				})}
 			got, _, _ := r.Render(tc.args.ctx, tc.args.ac)
 			if len(got) == 0 || len(got[0].Traits) == 0 || got[0].Traits[0].Object.GetName() != util.GenTraitName(componentName, ac.Spec.Components[0].Traits[0].DeepCopy()) {
 				t.Errorf("\n%s\nr.Render(...): -want error, +got error:\n%s\n", tc.reason, "Trait name is NOT"+
@@ -896,6 +1606,55 @@ func TestGetDefinitionName(t *testing.T) {
 	}
 }
 
+func TestRevisionTraitName(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{Status: v1alpha2.ApplicationConfigurationStatus{
+		Workloads: []v1alpha2.WorkloadStatus{{ComponentName: "web", ComponentRevisionName: "web-v1"}},
+	}}
+	cases := map[string]struct {
+		reason    string
+		name      string
+		revision  string
+		generated bool
+		want      string
+	}{
+		"Generated": {
+			reason:    "Generated names should be suffixed with the revision",
+			name:      "web-trait-abc",
+			revision:  "web-v2",
+			generated: true,
+			want:      "web-trait-abc-v2",
+		},
+		"GeneratedFromStatus": {
+			reason:    "Generated names reused from the status should replace the suffix of the previous revision",
+			name:      "web-trait-abc-v1",
+			revision:  "web-v2",
+			generated: true,
+			want:      "web-trait-abc-v2",
+		},
+		"GeneratedSameRevision": {
+			reason:    "Generated names of the revision recorded in the status should not change",
+			name:      "web-trait-abc-v1",
+			revision:  "web-v1",
+			generated: true,
+			want:      "web-trait-abc-v1",
+		},
+		"Specified": {
+			reason:   "Specified names should be suffixed with the revision",
+			name:     "route-v1",
+			revision: "web-v2",
+			want:     "route-v1-v2",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := revisionTraitName(ac, "web", tc.revision, tc.name, tc.generated)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrevisionTraitName(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestSetWorkloadInstanceName(t *testing.T) {
 	tests := map[string]struct {
 		traitDefs []v1alpha2.TraitDefinition
@@ -982,6 +1741,143 @@ func TestSetWorkloadInstanceName(t *testing.T) {
 	}
 }
 
+func TestCheckTraitConflicts(t *testing.T) {
+	manualScaler := v1alpha2.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "manualscalertraits.core.oam.dev"},
+		Spec: v1alpha2.TraitDefinitionSpec{
+			Reference:     v1alpha2.DefinitionReference{Name: "manualscalertraits.core.oam.dev"},
+			ConflictsWith: []string{"autoscalers.example.com"},
+		},
+	}
+	autoscaler := v1alpha2.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "autoscalers.example.com"},
+		Spec:       v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "autoscalers.example.com"}},
+	}
+
+	tests := map[string]struct {
+		cts       []v1alpha2.ComponentTrait
+		traitDefs []v1alpha2.TraitDefinition
+		expErr    error
+		reason    string
+	}{
+		"no conflicts": {
+			cts:       []v1alpha2.ComponentTrait{{}, {}},
+			traitDefs: []v1alpha2.TraitDefinition{autoscaler, *util.GetDummyTraitDefinition(&unstructured.Unstructured{})},
+			reason:    "traits whose definitions don't conflict can apply to the same workload",
+		},
+		"conflicting traits": {
+			cts:       []v1alpha2.ComponentTrait{{}, {}},
+			traitDefs: []v1alpha2.TraitDefinition{autoscaler, manualScaler},
+			expErr:    errors.Errorf(errFmtTraitConflict, "autoscalers.example.com", "manualscalertraits.core.oam.dev"),
+			reason:    "conflicting traits can't apply to the same workload, whichever of them declares the conflict",
+		},
+		"conflicting traits of different workloads": {
+			cts:       []v1alpha2.ComponentTrait{{}, {WorkloadName: "aux"}},
+			traitDefs: []v1alpha2.TraitDefinition{autoscaler, manualScaler},
+			reason:    "conflicting traits can apply to different workloads of a component",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkTraitConflicts(tc.cts, tc.traitDefs)
+			if diff := cmp.Diff(tc.expErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncheckTraitConflicts(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandOverWorkloads(t *testing.T) {
+	manager := v1alpha2.TraitDefinition{Spec: v1alpha2.TraitDefinitionSpec{ManageWorkload: true}}
+	newWorkload := func() *Workload {
+		w := &unstructured.Unstructured{}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetName("web")
+		aux := unstructured.Unstructured{}
+		aux.SetAPIVersion("v1")
+		aux.SetKind("Service")
+		aux.SetName("web-svc")
+		trait := func(name string) unstructured.Unstructured {
+			t := unstructured.Unstructured{}
+			t.SetName(name)
+			return t
+		}
+		return &Workload{
+			Workload:           w,
+			AuxiliaryWorkloads: []AuxiliaryWorkload{{Name: "svc", Object: aux}},
+			Traits: []*Trait{
+				{Object: trait("scaler")},
+				{Object: trait("rollout")},
+				{Object: trait("expose"), WorkloadName: "svc"},
+			},
+		}
+	}
+	annotation := func(u *unstructured.Unstructured) string {
+		raw, err := json.Marshal(u.Object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(raw)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		manages []int
+		want    func(w *Workload)
+		wantErr error
+	}{
+		"NotManaged": {
+			reason: "Workloads not managed by a trait should be applied",
+			want:   func(*Workload) {},
+		},
+		"ManagedWorkload": {
+			reason:  "A workload managed by a trait should be handed over to it and not applied",
+			manages: []int{1},
+			want: func(w *Workload) {
+				w.SkipApply = true
+				w.Traits[1].Object.SetAnnotations(map[string]string{oam.AnnotationManagedWorkload: annotation(w.Workload)})
+			},
+		},
+		"ManagedAuxiliaryWorkload": {
+			reason:  "An auxiliary workload managed by a trait should be handed over to it and not applied",
+			manages: []int{2},
+			want: func(w *Workload) {
+				w.AuxiliaryWorkloads[0].SkipApply = true
+				w.Traits[2].Object.SetAnnotations(map[string]string{oam.AnnotationManagedWorkload: annotation(&w.AuxiliaryWorkloads[0].Object)})
+			},
+		},
+		"ManagedTwice": {
+			reason:  "A workload cannot be managed by more than one trait",
+			manages: []int{0, 1},
+			wantErr: errors.Errorf(errFmtWorkloadManagedTwice, "web", "scaler", "rollout"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := newWorkload()
+			for _, i := range tc.manages {
+				w.Traits[i].Definition = manager
+			}
+			err := handOverWorkloads(w)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nhandOverWorkloads(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want == nil {
+				return
+			}
+			want := newWorkload()
+			for _, i := range tc.manages {
+				want.Traits[i].Definition = manager
+			}
+			tc.want(want)
+			if diff := cmp.Diff(want, w); diff != "" {
+				t.Errorf("\n%s\nhandOverWorkloads(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestSetTraitProperties(t *testing.T) {
 	u := &unstructured.Unstructured{}
 	u.SetName("hasName")
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render renders OAM ApplicationConfigurations into the workloads,
+// traits and scopes the ApplicationConfiguration controller applies. CLIs and
+// CI tools use it to render Kubernetes manifests without running the
+// controller.
+package render
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+)
+
+const (
+	errRender         = "cannot render application configuration"
+	errMarshalYAML    = "cannot marshal manifest to YAML"
+	errWriteManifests = "cannot write manifests"
+)
+
+// A Renderer renders ApplicationConfigurations into the workloads and traits
+// the ApplicationConfiguration controller would apply.
+type Renderer struct {
+	components ComponentRenderer
+}
+
+// New returns a Renderer that reads Components, ControllerRevisions,
+// definitions and scopes from the supplied reader.
+func New(r client.Reader, dm discoverymapper.DiscoveryMapper) *Renderer {
+	return &Renderer{components: NewComponentRenderer(r, dm)}
+}
+
+// NewOffline returns a Renderer that reads Components, ControllerRevisions,
+// definitions, scopes and the CRDs rendered workloads are validated against
+// from the supplied reader, e.g. one backed by manifests read from disk.
+// Resource names of workload and trait kinds are guessed from their kinds,
+// since there is no API server to discover them from.
+func NewOffline(r client.Reader) *Renderer {
+	return New(r, &GuessingMapper{})
+}
+
+// Render the supplied ApplicationConfiguration into workloads and traits.
+// Data inputs whose data outputs are not ready are left unset.
+func (r *Renderer) Render(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) ([]*unstructured.Unstructured, error) {
+	workloads, _, err := r.components.Render(ctx, ac)
+	if err != nil {
+		return nil, errors.Wrap(err, errRender)
+	}
+	manifests := make([]*unstructured.Unstructured, 0, len(workloads))
+	for _, w := range workloads {
+		manifests = append(manifests, w.Workload)
+		for _, aw := range w.AuxiliaryWorkloads {
+			manifests = append(manifests, aw.Object.DeepCopy())
+		}
+		for _, t := range w.Traits {
+			manifests = append(manifests, t.Object.DeepCopy())
+		}
+	}
+	return manifests, nil
+}
+
+// WriteYAML writes the supplied manifests to the supplied writer as a multi
+// document YAML stream.
+func WriteYAML(w io.Writer, manifests []*unstructured.Unstructured) error {
+	buf := &bytes.Buffer{}
+	for _, m := range manifests {
+		b, err := yaml.Marshal(m.Object)
+		if err != nil {
+			return errors.Wrap(err, errMarshalYAML)
+		}
+		buf.WriteString("---\n")
+		buf.Write(b)
+	}
+	_, err := w.Write(buf.Bytes())
+	return errors.Wrap(err, errWriteManifests)
+}
+
+var _ discoverymapper.DiscoveryMapper = &GuessingMapper{}
+
+// A GuessingMapper is a DiscoveryMapper that guesses the resource of a kind
+// from the kind itself, e.g. 'ManualScalerTrait' to 'manualscalertraits'. It
+// is used to look up definitions when there is no API server to discover
+// resources from.
+type GuessingMapper struct{}
+
+// GetMapper returns an empty RESTMapper.
+func (m *GuessingMapper) GetMapper() (meta.RESTMapper, error) {
+	return meta.NewDefaultRESTMapper(nil), nil
+}
+
+// Refresh returns an empty RESTMapper.
+func (m *GuessingMapper) Refresh() (meta.RESTMapper, error) {
+	return m.GetMapper()
+}
+
+// RESTMapping guesses the RESTMapping of the supplied GroupKind.
+func (m *GuessingMapper) RESTMapping(gk schema.GroupKind, version ...string) (*meta.RESTMapping, error) {
+	gvk := gk.WithVersion("")
+	if len(version) > 0 {
+		gvk = gk.WithVersion(version[0])
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	return &meta.RESTMapping{Resource: plural, GroupVersionKind: gvk, Scope: meta.RESTScopeNamespace}, nil
+}
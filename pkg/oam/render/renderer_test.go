@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestRenderOffline(t *testing.T) {
+	namespace := "ns"
+	acName := "coolappconfig"
+	componentName := "coolcomponent"
+
+	workload, _ := json.Marshal(&v1alpha2.ContainerizedWorkload{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha2.SchemeGroupVersion.String(),
+			Kind:       v1alpha2.ContainerizedWorkloadKind,
+		},
+	})
+	trait, _ := json.Marshal(&v1alpha2.ManualScalerTrait{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha2.SchemeGroupVersion.String(),
+			Kind:       v1alpha2.ManualScalerTraitKind,
+		},
+		Spec: v1alpha2.ManualScalerTraitSpec{ReplicaCount: 3},
+	})
+	comp := &v1alpha2.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: componentName, Namespace: namespace},
+		Spec: v1alpha2.ComponentSpec{
+			Workload: runtime.RawExtension{Raw: workload},
+		},
+	}
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: acName, Namespace: namespace},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{{
+				ComponentName: componentName,
+				Traits:        []v1alpha2.ComponentTrait{{Trait: runtime.RawExtension{Raw: trait}}},
+			}},
+		},
+	}
+
+	r := NewOffline(offlineClient(t, comp))
+	got, err := r.Render(context.Background(), ac)
+	if err != nil {
+		t.Fatalf("r.Render(...): %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("r.Render(...): want a workload and a trait, got %d manifests", len(got))
+	}
+
+	type manifest struct {
+		Kind, Name, Namespace, App, Component, ResourceType string
+	}
+	summarize := func(us []*unstructured.Unstructured) []manifest {
+		ms := make([]manifest, 0, len(us))
+		for _, u := range us {
+			ms = append(ms, manifest{
+				Kind:         u.GetKind(),
+				Name:         u.GetName(),
+				Namespace:    u.GetNamespace(),
+				App:          u.GetLabels()[oam.LabelAppName],
+				Component:    u.GetLabels()[oam.LabelAppComponent],
+				ResourceType: u.GetLabels()[oam.LabelOAMResourceType],
+			})
+		}
+		return ms
+	}
+	want := []manifest{
+		{
+			Kind:         v1alpha2.ContainerizedWorkloadKind,
+			Name:         componentName,
+			Namespace:    namespace,
+			App:          acName,
+			Component:    componentName,
+			ResourceType: oam.ResourceTypeWorkload,
+		},
+		{
+			Kind:         v1alpha2.ManualScalerTraitKind,
+			Name:         got[1].GetName(),
+			Namespace:    namespace,
+			App:          acName,
+			Component:    componentName,
+			ResourceType: oam.ResourceTypeTrait,
+		},
+	}
+	if diff := cmp.Diff(want, summarize(got)); diff != "" {
+		t.Errorf("r.Render(...): -want, +got:\n%s\n", diff)
+	}
+	if got[1].GetName() == "" {
+		t.Errorf("r.Render(...): trait name is not set")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteYAML(buf, got); err != nil {
+		t.Fatalf("WriteYAML(...): %v", err)
+	}
+	if docs := bytes.Count(buf.Bytes(), []byte("---\n")); docs != len(got) {
+		t.Errorf("WriteYAML(...): want %d documents, got %d", len(got), docs)
+	}
+}
+
+func TestRenderOfflineMissingComponent(t *testing.T) {
+	r := NewOffline(offlineClient(t))
+	ac := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "missing"}},
+		},
+	}
+	if _, err := r.Render(context.Background(), ac); err == nil {
+		t.Errorf("r.Render(...): want error for a missing component, got nil")
+	}
+}
+
+// offlineClient returns a client that reads only the supplied objects, as a
+// CLI reading manifests from disk would.
+func offlineClient(t *testing.T, objs ...runtime.Object) client.Reader {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("clientgoscheme.AddToScheme(...): %v", err)
+	}
+	if err := core.AddToScheme(s); err != nil {
+		t.Fatalf("core.AddToScheme(...): %v", err)
+	}
+	if err := crdv1.AddToScheme(s); err != nil {
+		t.Fatalf("crdv1.AddToScheme(...): %v", err)
+	}
+	return fake.NewFakeClientWithScheme(s, objs...)
+}
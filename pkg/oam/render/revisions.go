@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"sort"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+// ListHistoryWorkloads lists the running workloads of the old revisions of the
+// supplied component of an ApplicationConfiguration, i.e. its workloads other
+// than the supplied current one and the auxiliary workloads.
+func ListHistoryWorkloads(ctx context.Context, c client.Reader, acName, componentName, revisionName string,
+	current *unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	var ul unstructured.UnstructuredList
+	ul.SetKind(current.GetKind())
+	ul.SetAPIVersion(current.GetAPIVersion())
+	if err := c.List(ctx, &ul, client.InNamespace(current.GetNamespace()), client.MatchingLabels{oam.LabelAppName: acName,
+		oam.LabelAppComponent: componentName, oam.LabelOAMResourceType: oam.ResourceTypeWorkload}); err != nil {
+		return nil, err
+	}
+	history := make([]unstructured.Unstructured, 0, len(ul.Items))
+	for _, v := range ul.Items {
+		if v.GetName() == revisionName || v.GetName() == current.GetName() {
+			continue
+		}
+		if _, ok := v.GetLabels()[oam.LabelAppAuxiliaryWorkload]; ok {
+			continue
+		}
+		history = append(history, v)
+	}
+	return history, nil
+}
+
+// WorkloadRevisions returns the revisions of a component whose workloads are
+// running, newest first, given its current workload and the workloads of its
+// old revisions.
+func WorkloadRevisions(revisionName string, current *unstructured.Unstructured, history []unstructured.Unstructured) []v1alpha2.WorkloadRevision {
+	sorted := append([]unstructured.Unstructured{}, history...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ci, cj := sorted[i].GetCreationTimestamp(), sorted[j].GetCreationTimestamp()
+		return cj.Before(&ci)
+	})
+	revisions := make([]v1alpha2.WorkloadRevision, 0, len(sorted)+1)
+	revisions = append(revisions, v1alpha2.WorkloadRevision{RevisionName: revisionName, Reference: typedReference(current)})
+	for i := range sorted {
+		name := sorted[i].GetLabels()[oam.LabelAppComponentRevision]
+		if name == "" {
+			name = sorted[i].GetName()
+		}
+		ref := typedReference(&sorted[i])
+		ref.UID = sorted[i].GetUID()
+		revisions = append(revisions, v1alpha2.WorkloadRevision{RevisionName: name, Reference: ref})
+	}
+	return revisions
+}
+
+func typedReference(u *unstructured.Unstructured) runtimev1alpha1.TypedReference {
+	return runtimev1alpha1.TypedReference{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Name:       u.GetName(),
+	}
+}
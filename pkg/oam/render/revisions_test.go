@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+)
+
+func TestListHistoryWorkloads(t *testing.T) {
+	errBoom := errors.New("boom")
+	workload := func(name string, workloadLabels map[string]string) unstructured.Unstructured {
+		w := unstructured.Unstructured{}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetNamespace("ns")
+		w.SetName(name)
+		w.SetLabels(workloadLabels)
+		return w
+	}
+	current := workload("web-v3", nil)
+
+	cases := map[string]struct {
+		reason string
+		list   test.MockListFn
+		want   []unstructured.Unstructured
+		err    error
+	}{
+		"Success": {
+			reason: "Workloads other than the current and auxiliary workloads should be listed",
+			list: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				want := labels.SelectorFromSet(labels.Set{oam.LabelAppName: "app", oam.LabelAppComponent: "web",
+					oam.LabelOAMResourceType: oam.ResourceTypeWorkload})
+				if lo.Namespace != "ns" || lo.LabelSelector.String() != want.String() {
+					return errors.Errorf("unexpected list options %v", lo)
+				}
+				l := obj.(*unstructured.UnstructuredList)
+				if l.GetKind() != "Deployment" || l.GetAPIVersion() != "apps/v1" {
+					return errors.Errorf("unexpected list kind %s", l.GroupVersionKind())
+				}
+				l.Items = []unstructured.Unstructured{
+					current,
+					workload("web-v2", nil),
+					workload("web-v2-config", map[string]string{oam.LabelAppAuxiliaryWorkload: "config"}),
+					workload("web-v1", nil),
+				}
+				return nil
+			},
+			want: []unstructured.Unstructured{workload("web-v2", nil), workload("web-v1", nil)},
+		},
+		"ListError": {
+			reason: "Errors listing workloads should be returned",
+			list:   test.NewMockListFn(errBoom),
+			err:    errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ListHistoryWorkloads(context.Background(), &test.MockClient{MockList: tc.list}, "app", "web", "web-v3", &current)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nListHistoryWorkloads(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nListHistoryWorkloads(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWorkloadRevisions(t *testing.T) {
+	now := time.Now()
+	workload := func(name, revision string, age time.Duration) unstructured.Unstructured {
+		w := unstructured.Unstructured{}
+		w.SetAPIVersion("apps/v1")
+		w.SetKind("Deployment")
+		w.SetName(name)
+		w.SetUID(types.UID(name + "-uid"))
+		w.SetCreationTimestamp(metav1.NewTime(now.Add(-age)))
+		if revision != "" {
+			w.SetLabels(map[string]string{oam.LabelAppComponentRevision: revision})
+		}
+		return w
+	}
+	current := workload("web-v3", "web-v3", 0)
+	history := []unstructured.Unstructured{
+		workload("web-v1", "", 2*time.Hour),
+		workload("web-canary", "web-v2", time.Hour),
+	}
+
+	want := []v1alpha2.WorkloadRevision{
+		{RevisionName: "web-v3", Reference: runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web-v3"}},
+		{RevisionName: "web-v2", Reference: runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web-canary", UID: "web-canary-uid"}},
+		{RevisionName: "web-v1", Reference: runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web-v1", UID: "web-v1-uid"}},
+	}
+	got := WorkloadRevisions("web-v3", &current, history)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nWorkloadRevisions(...): -want, +got:\n%s\n", diff)
+	}
+	if history[0].GetName() != "web-v1" {
+		t.Errorf("\nWorkloadRevisions(...): the supplied history workloads must not be reordered")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const errFmtApplyScopeTrait = "cannot apply trait %d of scope %s %q to component %q"
+
+// applyScopeTraits returns a copy of the supplied ApplicationConfiguration
+// with the traits declared for each scope added to the components in that
+// scope. The supplied ApplicationConfiguration is returned as is when it
+// declares no scope traits.
+//
+// Scope traits always apply to the main workload of a component. Because
+// they are rendered like any other trait of the component, the instances of
+// a component are garbage collected once it leaves the scope.
+func applyScopeTraits(ac *v1alpha2.ApplicationConfiguration) (*v1alpha2.ApplicationConfiguration, error) {
+	if len(ac.Spec.ScopeTraits) == 0 {
+		return ac, nil
+	}
+	out := ac.DeepCopy()
+	for i := range out.Spec.Components {
+		acc := &out.Spec.Components[i]
+		for _, st := range out.Spec.ScopeTraits {
+			if !inScope(*acc, st.ScopeReference) {
+				continue
+			}
+			for j, ct := range st.Traits {
+				t, err := scopeTrait(ct, accComponentName(*acc))
+				if err != nil {
+					return nil, errors.Wrapf(err, errFmtApplyScopeTrait, j, st.ScopeReference.Kind, st.ScopeReference.Name, accComponentName(*acc))
+				}
+				acc.Traits = append(acc.Traits, t)
+			}
+		}
+	}
+	return out, nil
+}
+
+// inScope returns true if the supplied component is in the referenced scope.
+func inScope(acc v1alpha2.ApplicationConfigurationComponent, ref runtimev1alpha1.TypedReference) bool {
+	for _, cs := range acc.Scopes {
+		if cs.ScopeReference.APIVersion == ref.APIVersion && cs.ScopeReference.Kind == ref.Kind && cs.ScopeReference.Name == ref.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeTrait returns the instance of the supplied scope trait for the named
+// component. A named trait gets the name of the component appended to its
+// name, so that the instances of different components do not collide.
+func scopeTrait(ct v1alpha2.ComponentTrait, componentName string) (v1alpha2.ComponentTrait, error) {
+	t := *ct.DeepCopy()
+	t.WorkloadName = ""
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(t.Trait.Raw); err != nil {
+		return v1alpha2.ComponentTrait{}, err
+	}
+	if u.GetName() == "" {
+		return t, nil
+	}
+	u.SetName(fmt.Sprintf("%s-%s", u.GetName(), componentName))
+	raw, err := json.Marshal(u.Object)
+	if err != nil {
+		return v1alpha2.ComponentTrait{}, err
+	}
+	t.Trait = runtime.RawExtension{Raw: raw}
+	return t, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestApplyScopeTraits(t *testing.T) {
+	trait := func(raw string) v1alpha2.ComponentTrait {
+		return v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: []byte(raw)}}
+	}
+	scope := runtimev1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "app"}
+	other := runtimev1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "other"}
+	component := func(name string, traits []v1alpha2.ComponentTrait, scopes ...runtimev1alpha1.TypedReference) v1alpha2.ApplicationConfigurationComponent {
+		acc := v1alpha2.ApplicationConfigurationComponent{ComponentName: name, Traits: traits}
+		for _, s := range scopes {
+			acc.Scopes = append(acc.Scopes, v1alpha2.ComponentScope{ScopeReference: s})
+		}
+		return acc
+	}
+	appConfig := func(accs []v1alpha2.ApplicationConfigurationComponent, sts ...v1alpha2.ScopeTraits) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components:  accs,
+			ScopeTraits: sts,
+		}}
+	}
+	scaler := trait(`{"apiVersion":"core.oam.dev/v1alpha2","kind":"ManualScalerTrait","spec":{"replicaCount":1}}`)
+	monitor := v1alpha2.ScopeTraits{
+		ScopeReference: scope,
+		Traits:         []v1alpha2.ComponentTrait{trait(`{"apiVersion":"example.com/v1","kind":"Monitor","spec":{"port":8080}}`)},
+	}
+	named := v1alpha2.ScopeTraits{
+		ScopeReference: scope,
+		Traits: []v1alpha2.ComponentTrait{{
+			WorkloadName: "sidecar",
+			Trait:        runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.com/v1","kind":"Monitor","metadata":{"name":"metrics"}}`)},
+		}},
+	}
+	errMalformed := (&unstructured.Unstructured{}).UnmarshalJSON([]byte(`{`))
+
+	cases := map[string]struct {
+		reason string
+		ac     *v1alpha2.ApplicationConfiguration
+		want   *v1alpha2.ApplicationConfiguration
+		err    error
+	}{
+		"NoScopeTraits": {
+			reason: "An ApplicationConfiguration without scope traits should be returned as is",
+			ac:     appConfig([]v1alpha2.ApplicationConfigurationComponent{component("web", []v1alpha2.ComponentTrait{scaler}, scope)}),
+			want:   appConfig([]v1alpha2.ApplicationConfigurationComponent{component("web", []v1alpha2.ComponentTrait{scaler}, scope)}),
+		},
+		"ComponentsInScope": {
+			reason: "Scope traits should be added to the components in the scope only",
+			ac: appConfig([]v1alpha2.ApplicationConfigurationComponent{
+				component("web", []v1alpha2.ComponentTrait{scaler}, scope),
+				component("db", nil, other),
+				component("cache", nil, other, scope),
+			}, monitor),
+			want: appConfig([]v1alpha2.ApplicationConfigurationComponent{
+				component("web", []v1alpha2.ComponentTrait{scaler, monitor.Traits[0]}, scope),
+				component("db", nil, other),
+				component("cache", []v1alpha2.ComponentTrait{monitor.Traits[0]}, other, scope),
+			}, monitor),
+		},
+		"NamedTrait": {
+			reason: "A named scope trait should be instantiated once per component and apply to its main workload",
+			ac: appConfig([]v1alpha2.ApplicationConfigurationComponent{
+				component("web", nil, scope),
+				component("cache", nil, scope),
+			}, named),
+			want: appConfig([]v1alpha2.ApplicationConfigurationComponent{
+				component("web", []v1alpha2.ComponentTrait{trait(`{"apiVersion":"example.com/v1","kind":"Monitor","metadata":{"name":"metrics-web"}}`)}, scope),
+				component("cache", []v1alpha2.ComponentTrait{trait(`{"apiVersion":"example.com/v1","kind":"Monitor","metadata":{"name":"metrics-cache"}}`)}, scope),
+			}, named),
+		},
+		"MalformedTrait": {
+			reason: "A malformed scope trait should be rejected",
+			ac: appConfig([]v1alpha2.ApplicationConfigurationComponent{component("web", nil, scope)}, v1alpha2.ScopeTraits{
+				ScopeReference: scope,
+				Traits:         []v1alpha2.ComponentTrait{trait(`{`)},
+			}),
+			err: errors.Wrapf(errMalformed, errFmtApplyScopeTrait, 0, "HealthScope", "app", "web"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			original := tc.ac.DeepCopy()
+			got, err := applyScopeTraits(tc.ac)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napplyScopeTraits(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\napplyScopeTraits(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(original, tc.ac); diff != "" {
+				t.Errorf("\n%s\napplyScopeTraits(...): must not modify the supplied ApplicationConfiguration:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
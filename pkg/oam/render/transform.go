@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Transform error strings.
+const (
+	errFmtTransformParam    = "cannot transform parameter %q"
+	errFmtTransform         = "cannot apply transform %d of type %q"
+	errFmtUnknownTransform  = "unknown transform type %q"
+	errTransformStringInput = "transform requires a string value"
+	errNoPrintfFormat       = "printf transform requires a format"
+	errFmtBadPrintfFormat   = "bad printf format %q"
+	errDecodeBase64         = "cannot decode base64 value"
+	errParseInt             = "cannot parse integer value"
+)
+
+// transform applies the supplied transforms to the supplied value in order.
+func transform(v intstr.IntOrString, ts []v1alpha2.ParameterTransform) (intstr.IntOrString, error) {
+	for i, t := range ts {
+		var err error
+		if v, err = applyTransform(v, t); err != nil {
+			return intstr.IntOrString{}, errors.Wrapf(err, errFmtTransform, i, t.Type)
+		}
+	}
+	return v, nil
+}
+
+func applyTransform(v intstr.IntOrString, t v1alpha2.ParameterTransform) (intstr.IntOrString, error) {
+	switch t.Type {
+	case v1alpha2.ToStringTransformType:
+		return intstr.FromString(v.String()), nil
+	case v1alpha2.ToIntTransformType:
+		if v.Type == intstr.Int {
+			return v, nil
+		}
+		i, err := strconv.ParseInt(strings.TrimSpace(v.StrVal), 10, 32)
+		if err != nil {
+			return intstr.IntOrString{}, errors.Wrap(err, errParseInt)
+		}
+		return intstr.FromInt(int(i)), nil
+	case v1alpha2.PrintfTransformType:
+		if t.Format == "" {
+			return intstr.IntOrString{}, errors.New(errNoPrintfFormat)
+		}
+		var arg interface{} = v.StrVal
+		if v.Type == intstr.Int {
+			arg = v.IntVal
+		}
+		// fmt reports bad verbs and missing or extra arguments inline.
+		s := fmt.Sprintf(t.Format, arg)
+		if strings.Contains(s, "%!") {
+			return intstr.IntOrString{}, errors.Errorf(errFmtBadPrintfFormat, t.Format)
+		}
+		return intstr.FromString(s), nil
+	case v1alpha2.Base64EncodeTransformType, v1alpha2.Base64DecodeTransformType,
+		v1alpha2.LowerTransformType, v1alpha2.UpperTransformType:
+		if v.Type != intstr.String {
+			return intstr.IntOrString{}, errors.New(errTransformStringInput)
+		}
+		s, err := transformString(v.StrVal, t.Type)
+		if err != nil {
+			return intstr.IntOrString{}, err
+		}
+		return intstr.FromString(s), nil
+	}
+	return intstr.IntOrString{}, errors.Errorf(errFmtUnknownTransform, t.Type)
+}
+
+func transformString(s string, t v1alpha2.ParameterTransformType) (string, error) {
+	switch t {
+	case v1alpha2.Base64EncodeTransformType:
+		return base64.StdEncoding.EncodeToString([]byte(s)), nil
+	case v1alpha2.Base64DecodeTransformType:
+		b, err := base64.StdEncoding.DecodeString(s)
+		return string(b), errors.Wrap(err, errDecodeBase64)
+	case v1alpha2.LowerTransformType:
+		return strings.ToLower(s), nil
+	case v1alpha2.UpperTransformType:
+		return strings.ToUpper(s), nil
+	}
+	return "", errors.Errorf(errFmtUnknownTransform, t)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestTransform(t *testing.T) {
+	_, errParse := strconv.ParseInt("nope", 10, 32)
+
+	cases := map[string]struct {
+		reason string
+		v      intstr.IntOrString
+		ts     []v1alpha2.ParameterTransform
+		want   intstr.IntOrString
+		err    error
+	}{
+		"NoTransforms": {
+			reason: "A value without transforms should be returned as is",
+			v:      intstr.FromString("cool"),
+			want:   intstr.FromString("cool"),
+		},
+		"Base64Encode": {
+			reason: "A string should be base64 encoded",
+			v:      intstr.FromString("cool"),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.Base64EncodeTransformType}},
+			want:   intstr.FromString("Y29vbA=="),
+		},
+		"Base64Decode": {
+			reason: "A base64 encoded string should be decoded",
+			v:      intstr.FromString("Y29vbA=="),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.Base64DecodeTransformType}},
+			want:   intstr.FromString("cool"),
+		},
+		"Base64DecodeError": {
+			reason: "Errors decoding an invalid base64 string should be returned",
+			v:      intstr.FromString("%"),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.Base64DecodeTransformType}},
+			err: errors.Wrapf(errors.Wrap(errors.New("illegal base64 data at input byte 0"), errDecodeBase64),
+				errFmtTransform, 0, v1alpha2.Base64DecodeTransformType),
+		},
+		"ToInt": {
+			reason: "A numeric string should be transformed into an integer",
+			v:      intstr.FromString(" 3 "),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.ToIntTransformType}},
+			want:   intstr.FromInt(3),
+		},
+		"ToIntError": {
+			reason: "Errors parsing a non-numeric string should be returned",
+			v:      intstr.FromString("nope"),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.ToIntTransformType}},
+			err:    errors.Wrapf(errors.Wrap(errParse, errParseInt), errFmtTransform, 0, v1alpha2.ToIntTransformType),
+		},
+		"ToString": {
+			reason: "An integer should be transformed into a string",
+			v:      intstr.FromInt(3),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.ToStringTransformType}},
+			want:   intstr.FromString("3"),
+		},
+		"Printf": {
+			reason: "A value should be formatted using the format of the transform",
+			v:      intstr.FromInt(8080),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.PrintfTransformType, Format: "http://localhost:%d"}},
+			want:   intstr.FromString("http://localhost:8080"),
+		},
+		"PrintfBadFormat": {
+			reason: "A format that does not consume exactly the value should be rejected",
+			v:      intstr.FromString("cool"),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.PrintfTransformType, Format: "%s-%s"}},
+			err: errors.Wrapf(errors.Errorf(errFmtBadPrintfFormat, "%s-%s"),
+				errFmtTransform, 0, v1alpha2.PrintfTransformType),
+		},
+		"Pipeline": {
+			reason: "Transforms should be applied in order",
+			v:      intstr.FromString("NGINX"),
+			ts: []v1alpha2.ParameterTransform{
+				{Type: v1alpha2.LowerTransformType},
+				{Type: v1alpha2.PrintfTransformType, Format: "%s:latest"},
+				{Type: v1alpha2.Base64EncodeTransformType},
+			},
+			want: intstr.FromString("bmdpbng6bGF0ZXN0"),
+		},
+		"StringTransformOfInt": {
+			reason: "String transforms should reject integers",
+			v:      intstr.FromInt(3),
+			ts:     []v1alpha2.ParameterTransform{{Type: v1alpha2.UpperTransformType}},
+			err:    errors.Wrapf(errors.New(errTransformStringInput), errFmtTransform, 0, v1alpha2.UpperTransformType),
+		},
+		"UnknownTransform": {
+			reason: "Unknown transform types should be rejected",
+			v:      intstr.FromString("cool"),
+			ts:     []v1alpha2.ParameterTransform{{Type: "reverse"}},
+			err:    errors.Wrapf(errors.Errorf(errFmtUnknownTransform, "reverse"), errFmtTransform, 0, "reverse"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := transform(tc.v, tc.ts)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntransform(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntransform(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// Validate error strings.
+const (
+	errFmtGetCRD            = "cannot get custom resource definition %q"
+	errFmtConvertCRDSchema  = "cannot convert schema of custom resource definition %q"
+	errFmtBuildCRDValidator = "cannot build validator of custom resource definition %q"
+	errFmtInvalidWorkload   = "workload %q %q %q does not match the schema of custom resource definition %q"
+)
+
+// A ResourceValidator validates a rendered workload before it is applied.
+type ResourceValidator interface {
+	Validate(ctx context.Context, w *unstructured.Unstructured) error
+}
+
+// A ResourceValidateFn validates a rendered workload before it is applied.
+type ResourceValidateFn func(ctx context.Context, w *unstructured.Unstructured) error
+
+// Validate the supplied workload.
+func (fn ResourceValidateFn) Validate(ctx context.Context, w *unstructured.Unstructured) error {
+	return fn(ctx, w)
+}
+
+var _ ResourceValidator = &crdValidator{}
+
+// A crdValidator validates workloads against the OpenAPI schema of the CRD
+// their WorkloadDefinition refers to.
+type crdValidator struct {
+	client client.Reader
+	dm     discoverymapper.DiscoveryMapper
+}
+
+// Validate the supplied workload against the schema of its CRD. Workloads
+// without a WorkloadDefinition, CRD or schema, e.g. built-in Kubernetes
+// resources, are considered valid; the API server validates them on apply.
+func (v *crdValidator) Validate(ctx context.Context, w *unstructured.Unstructured) error {
+	name, errs, err := v.validate(ctx, w)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errors.Wrapf(errs.ToAggregate(), errFmtInvalidWorkload, w.GetAPIVersion(), w.GetKind(), w.GetName(), name)
+	}
+	return nil
+}
+
+// validate the supplied workload against the schema of its CRD, returning the
+// name of the CRD and the fields that don't match its schema.
+func (v *crdValidator) validate(ctx context.Context, w *unstructured.Unstructured) (string, field.ErrorList, error) {
+	wd, err := util.FetchWorkloadDefinition(ctx, v.client, v.dm, w)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, errors.Wrapf(err, errFmtGetWorkloadDefinition, w.GetKind())
+	}
+	name := wd.Spec.Reference.Name
+	crd := &crdv1.CustomResourceDefinition{}
+	if err := v.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return name, nil, nil
+		}
+		return name, nil, errors.Wrapf(err, errFmtGetCRD, name)
+	}
+	s := crdSchema(crd, w.GroupVersionKind())
+	if s == nil {
+		return name, nil, nil
+	}
+	in := &apiextensions.CustomResourceValidation{}
+	if err := crdv1.Convert_v1_CustomResourceValidation_To_apiextensions_CustomResourceValidation(s, in, nil); err != nil {
+		return name, nil, errors.Wrapf(err, errFmtConvertCRDSchema, name)
+	}
+	sv, _, err := validation.NewSchemaValidator(in)
+	if err != nil {
+		return name, nil, errors.Wrapf(err, errFmtBuildCRDValidator, name)
+	}
+	return name, validation.ValidateCustomResource(nil, w.UnstructuredContent(), sv), nil
+}
+
+// ValidateWorkloadSchema returns the fields of the supplied workload that
+// don't match the OpenAPI schema of the CRD its WorkloadDefinition refers to.
+// Workloads without a WorkloadDefinition, CRD or schema have no such fields.
+func ValidateWorkloadSchema(ctx context.Context, c client.Reader, dm discoverymapper.DiscoveryMapper,
+	w *unstructured.Unstructured) (field.ErrorList, error) {
+	_, errs, err := (&crdValidator{client: c, dm: dm}).validate(ctx, w)
+	return errs, err
+}
+
+// crdSchema returns the schema of the version of the supplied CRD that serves
+// the supplied kind, or nil if that version has no schema.
+func crdSchema(crd *crdv1.CustomResourceDefinition, gvk schema.GroupVersionKind) *crdv1.CustomResourceValidation {
+	if crd.Spec.Group != gvk.Group || crd.Spec.Names.Kind != gvk.Kind {
+		return nil
+	}
+	for _, ver := range crd.Spec.Versions {
+		if ver.Name == gvk.Version {
+			return ver.Schema
+		}
+	}
+	return nil
+}
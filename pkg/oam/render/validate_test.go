@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestCRDValidator(t *testing.T) {
+	crdName := "foos.example.com"
+	errBoom := errors.New("boom")
+
+	workload := func(spec map[string]interface{}) *unstructured.Unstructured {
+		w := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		w.SetAPIVersion("example.com/v1")
+		w.SetKind("Foo")
+		w.SetName("foo")
+		w.SetLabels(map[string]string{oam.WorkloadTypeLabel: "foo"})
+		return w
+	}
+	crd := crdv1.CustomResourceDefinition{
+		Spec: crdv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Names: crdv1.CustomResourceDefinitionNames{Kind: "Foo"},
+			Versions: []crdv1.CustomResourceDefinitionVersion{{
+				Name: "v1",
+				Schema: &crdv1.CustomResourceValidation{
+					OpenAPIV3Schema: &crdv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]crdv1.JSONSchemaProps{
+							"spec": {
+								Type:     "object",
+								Required: []string{"replicas"},
+								Properties: map[string]crdv1.JSONSchemaProps{
+									"replicas": {Type: "integer"},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+	get := func(obj runtime.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha2.WorkloadDefinition:
+			o.Spec.Reference.Name = crdName
+		case *crdv1.CustomResourceDefinition:
+			crd.DeepCopyInto(o)
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		w      *unstructured.Unstructured
+		want   error
+	}{
+		"Valid": {
+			reason: "A workload that matches the schema of its CRD should be valid",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, get)},
+			w:      workload(map[string]interface{}{"replicas": int64(2)}),
+		},
+		"Invalid": {
+			reason: "A workload that does not match the schema of its CRD should be invalid",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(nil, get)},
+			w:      workload(map[string]interface{}{}),
+			want: errors.Wrapf(field.ErrorList{field.Required(field.NewPath("spec", "replicas"), "")}.ToAggregate(),
+				errFmtInvalidWorkload, "example.com/v1", "Foo", "foo", crdName),
+		},
+		"NoWorkloadDefinition": {
+			reason: "A workload without a WorkloadDefinition should be valid",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "foo"))},
+			w:      workload(map[string]interface{}{}),
+		},
+		"NoCRD": {
+			reason: "A workload whose WorkloadDefinition refers to no CRD should be valid",
+			client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				if _, ok := obj.(*crdv1.CustomResourceDefinition); ok {
+					return kerrors.NewNotFound(schema.GroupResource{}, crdName)
+				}
+				return get(obj)
+			}},
+			w: workload(map[string]interface{}{}),
+		},
+		"GetCRDError": {
+			reason: "Errors getting the CRD should be returned",
+			client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+				if _, ok := obj.(*crdv1.CustomResourceDefinition); ok {
+					return errBoom
+				}
+				return get(obj)
+			}},
+			w:    workload(map[string]interface{}{}),
+			want: errors.Wrapf(errBoom, errFmtGetCRD, crdName),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &crdValidator{client: tc.client, dm: mock.NewMockDiscoveryMapper()}
+			err := v.Validate(context.Background(), tc.w)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.Validate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
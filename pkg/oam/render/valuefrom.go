@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"math"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// Parameter value reference error strings.
+const (
+	errFmtUnknownFromComponent = "parameter %q of component %q refers to unknown component %q"
+	errFmtCyclicFromComponent  = "parameter values of component %q refer to themselves through other components"
+	errFmtGetValueFrom         = "cannot get value of parameter %q from field %q of component %q"
+	errFmtUnsupportedValueFrom = "value of parameter %q from field %q of component %q is neither a string nor an integer"
+)
+
+// accComponentName returns the name of the component the supplied
+// ApplicationConfigurationComponent refers to.
+func accComponentName(acc v1alpha2.ApplicationConfigurationComponent) string {
+	if acc.RevisionName != "" {
+		return ExtractComponentName(acc.RevisionName)
+	}
+	return acc.ComponentName
+}
+
+// renderOrder returns the indices of the supplied components in an order in
+// which every component comes after the components its parameter values are
+// read from. Components keep their relative order otherwise.
+func renderOrder(accs []v1alpha2.ApplicationConfigurationComponent) ([]int, error) {
+	index := make(map[string]int, len(accs))
+	for i, acc := range accs {
+		index[accComponentName(acc)] = i
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(accs))
+	order := make([]int, 0, len(accs))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf(errFmtCyclicFromComponent, accComponentName(accs[i]))
+		}
+		state[i] = visiting
+		for _, pv := range accs[i].ParameterValues {
+			if pv.ValueFrom == nil {
+				continue
+			}
+			j, ok := index[pv.ValueFrom.FromComponent]
+			if !ok {
+				return errors.Errorf(errFmtUnknownFromComponent, pv.Name, accComponentName(accs[i]), pv.ValueFrom.FromComponent)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		order = append(order, i)
+		return nil
+	}
+	for i := range accs {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// resolveValueFrom returns the parameter values of the supplied component,
+// with the values that refer to another component read from the supplied
+// rendered workloads, keyed by their component name.
+func resolveValueFrom(acc v1alpha2.ApplicationConfigurationComponent, rendered map[string]*Workload) ([]v1alpha2.ComponentParameterValue, error) {
+	values := make([]v1alpha2.ComponentParameterValue, len(acc.ParameterValues))
+	for i, pv := range acc.ParameterValues {
+		values[i] = v1alpha2.ComponentParameterValue{Name: pv.Name, Value: pv.Value}
+		if pv.ValueFrom == nil {
+			continue
+		}
+		from := pv.ValueFrom
+		w, ok := rendered[from.FromComponent]
+		if !ok {
+			return nil, errors.Errorf(errFmtUnknownFromComponent, pv.Name, accComponentName(acc), from.FromComponent)
+		}
+		v, err := fieldpath.Pave(w.Workload.UnstructuredContent()).GetValue(from.FieldPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetValueFrom, pv.Name, from.FieldPath, from.FromComponent)
+		}
+		switch val := v.(type) {
+		case string:
+			values[i].Value = intstr.FromString(val)
+		case int64:
+			values[i].Value = intstr.FromInt(int(val))
+		case float64:
+			if val != math.Trunc(val) {
+				return nil, errors.Errorf(errFmtUnsupportedValueFrom, pv.Name, from.FieldPath, from.FromComponent)
+			}
+			values[i].Value = intstr.FromInt(int(val))
+		default:
+			return nil, errors.Errorf(errFmtUnsupportedValueFrom, pv.Name, from.FieldPath, from.FromComponent)
+		}
+	}
+	return values, nil
+}
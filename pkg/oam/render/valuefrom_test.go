@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+func TestRenderOrder(t *testing.T) {
+	component := func(name string, from ...string) v1alpha2.ApplicationConfigurationComponent {
+		acc := v1alpha2.ApplicationConfigurationComponent{ComponentName: name}
+		for _, f := range from {
+			acc.ParameterValues = append(acc.ParameterValues, v1alpha2.ComponentParameterValue{
+				Name:      "p",
+				ValueFrom: &v1alpha2.ComponentParameterValueFrom{FromComponent: f, FieldPath: "metadata.name"},
+			})
+		}
+		return acc
+	}
+
+	type want struct {
+		order []int
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		accs   []v1alpha2.ApplicationConfigurationComponent
+		want   want
+	}{
+		"NoReferences": {
+			reason: "Components without references should keep their order",
+			accs:   []v1alpha2.ApplicationConfigurationComponent{component("a"), component("b")},
+			want:   want{order: []int{0, 1}},
+		},
+		"References": {
+			reason: "Components should come after the components they refer to",
+			accs:   []v1alpha2.ApplicationConfigurationComponent{component("a", "c"), component("b"), component("c", "b")},
+			want:   want{order: []int{1, 2, 0}},
+		},
+		"RevisionName": {
+			reason: "Components referred to by revision should be found by their component name",
+			accs: []v1alpha2.ApplicationConfigurationComponent{
+				component("a", "b"),
+				{RevisionName: "b-v1"},
+			},
+			want: want{order: []int{1, 0}},
+		},
+		"UnknownComponent": {
+			reason: "References to unknown components should be rejected",
+			accs:   []v1alpha2.ApplicationConfigurationComponent{component("a", "b")},
+			want:   want{err: errors.Errorf(errFmtUnknownFromComponent, "p", "a", "b")},
+		},
+		"Cycle": {
+			reason: "Cyclic references should be rejected",
+			accs:   []v1alpha2.ApplicationConfigurationComponent{component("a", "b"), component("b", "a")},
+			want:   want{err: errors.Errorf(errFmtCyclicFromComponent, "a")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			order, err := renderOrder(tc.accs)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrenderOrder(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.order, order); diff != "" {
+				t.Errorf("\n%s\nrenderOrder(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveValueFrom(t *testing.T) {
+	w := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "db"},
+		"spec": map[string]interface{}{
+			"port":     float64(5432),
+			"ratio":    0.5,
+			"replicas": int64(2),
+		},
+	}}
+	rendered := map[string]*Workload{"db": {ComponentName: "db", Workload: w}}
+	from := func(name, path string) v1alpha2.ComponentParameterValue {
+		return v1alpha2.ComponentParameterValue{
+			Name:      name,
+			ValueFrom: &v1alpha2.ComponentParameterValueFrom{FromComponent: "db", FieldPath: path},
+		}
+	}
+
+	type want struct {
+		values []v1alpha2.ComponentParameterValue
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		acc    v1alpha2.ApplicationConfigurationComponent
+		want   want
+	}{
+		"Resolved": {
+			reason: "Values should be read from the rendered workloads of the referred components",
+			acc: v1alpha2.ApplicationConfigurationComponent{
+				ComponentName: "web",
+				ParameterValues: []v1alpha2.ComponentParameterValue{
+					{Name: "image", Value: intstr.FromString("nginx")},
+					from("host", "metadata.name"),
+					from("port", "spec.port"),
+					from("replicas", "spec.replicas"),
+				},
+			},
+			want: want{values: []v1alpha2.ComponentParameterValue{
+				{Name: "image", Value: intstr.FromString("nginx")},
+				{Name: "host", Value: intstr.FromString("db")},
+				{Name: "port", Value: intstr.FromInt(5432)},
+				{Name: "replicas", Value: intstr.FromInt(2)},
+			}},
+		},
+		"MissingField": {
+			reason: "Errors getting the value should be returned",
+			acc: v1alpha2.ApplicationConfigurationComponent{
+				ComponentName:   "web",
+				ParameterValues: []v1alpha2.ComponentParameterValue{from("host", "spec.host")},
+			},
+			want: want{err: errors.Wrapf(errors.New("spec.host: no such field"), errFmtGetValueFrom, "host", "spec.host", "db")},
+		},
+		"UnsupportedValue": {
+			reason: "Values that are neither strings nor integers should be rejected",
+			acc: v1alpha2.ApplicationConfigurationComponent{
+				ComponentName:   "web",
+				ParameterValues: []v1alpha2.ComponentParameterValue{from("ratio", "spec.ratio")},
+			},
+			want: want{err: errors.Errorf(errFmtUnsupportedValueFrom, "ratio", "spec.ratio", "db")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			values, err := resolveValueFrom(tc.acc, rendered)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolveValueFrom(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.values, values); diff != "" {
+				t.Errorf("\n%s\nresolveValueFrom(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+// A Workload produced by an OAM ApplicationConfiguration.
+type Workload struct {
+	// ComponentName that produced this workload.
+	ComponentName string
+
+	// ComponentRevisionName of current component
+	ComponentRevisionName string
+
+	// A Workload object.
+	Workload *unstructured.Unstructured
+
+	// AuxiliaryWorkloads created alongside this workload.
+	AuxiliaryWorkloads []AuxiliaryWorkload
+
+	// HasDep indicates whether this resource has dependencies and unready to be applied.
+	HasDep bool
+
+	// SkipApply indicates that a trait manages this workload, i.e. creates
+	// and updates it, so it is not applied.
+	SkipApply bool
+
+	// Traits associated with this workload.
+	Traits []*Trait
+
+	// RevisionEnabled means multiple workloads of same component will possibly be alive.
+	RevisionEnabled bool
+
+	// RevisionHistoryLimit is the number of workloads of old revisions that
+	// are retained if RevisionEnabled. All of them are retained if nil.
+	RevisionHistoryLimit *int32
+
+	// Scopes associated with this workload.
+	Scopes []unstructured.Unstructured
+
+	// HealthPolicy of the WorkloadDefinition of this workload, if any.
+	HealthPolicy *v1alpha2.HealthPolicy
+
+	// StatusFields of the WorkloadDefinition of this workload, if any.
+	StatusFields []v1alpha2.StatusField
+
+	// StatusMessage template of the WorkloadDefinition of this workload, if
+	// any.
+	StatusMessage string
+
+	// DefinitionRevisionName of the WorkloadDefinition this workload was
+	// rendered against, if any.
+	DefinitionRevisionName string
+
+	// PodSpecPath of the WorkloadDefinition of this workload, if any.
+	PodSpecPath string
+}
+
+// An AuxiliaryWorkload produced by an OAM ApplicationConfiguration alongside
+// a Workload.
+type AuxiliaryWorkload struct {
+	// Name of this auxiliary workload within its component.
+	Name string
+
+	Object unstructured.Unstructured
+
+	// SkipApply indicates that a trait manages this auxiliary workload, i.e.
+	// creates and updates it, so it is not applied.
+	SkipApply bool
+}
+
+// A Trait produced by an OAM ApplicationConfiguration.
+type Trait struct {
+	Object unstructured.Unstructured
+
+	// HasDep indicates whether this resource has dependencies and unready to be applied.
+	HasDep bool
+
+	// Definition indicates the trait's definition
+	Definition v1alpha2.TraitDefinition
+
+	// WorkloadName is the name of the auxiliary workload this trait applies
+	// to, or empty if it applies to the workload of its component.
+	WorkloadName string
+}
+
+// Status produces the status of this workload and its traits, suitable for use
+// in the status of an ApplicationConfiguration.
+func (w Workload) Status() v1alpha2.WorkloadStatus {
+	acw := v1alpha2.WorkloadStatus{
+		ComponentName:         w.ComponentName,
+		ComponentRevisionName: w.ComponentRevisionName,
+		Reference: runtimev1alpha1.TypedReference{
+			APIVersion: w.Workload.GetAPIVersion(),
+			Kind:       w.Workload.GetKind(),
+			Name:       w.Workload.GetName(),
+		},
+		Traits:                 make([]v1alpha2.WorkloadTrait, len(w.Traits)),
+		Scopes:                 make([]v1alpha2.WorkloadScope, len(w.Scopes)),
+		DefinitionRevisionName: w.DefinitionRevisionName,
+	}
+	for _, aw := range w.AuxiliaryWorkloads {
+		acw.AuxiliaryWorkloads = append(acw.AuxiliaryWorkloads, v1alpha2.AuxiliaryWorkloadStatus{
+			Name: aw.Name,
+			Reference: runtimev1alpha1.TypedReference{
+				APIVersion: aw.Object.GetAPIVersion(),
+				Kind:       aw.Object.GetKind(),
+				Name:       aw.Object.GetName(),
+			},
+		})
+	}
+	for i, tr := range w.Traits {
+		if tr.Definition.Name == util.Dummy && tr.Definition.Spec.Reference.Name == util.Dummy {
+			acw.Traits[i].Message = util.DummyTraitMessage
+		}
+		acw.Traits[i].Reference = runtimev1alpha1.TypedReference{
+			APIVersion: w.Traits[i].Object.GetAPIVersion(),
+			Kind:       w.Traits[i].Object.GetKind(),
+			Name:       w.Traits[i].Object.GetName(),
+		}
+		if rev := tr.Definition.Status.LatestRevision; rev != nil {
+			acw.Traits[i].DefinitionRevisionName = rev.Name
+		}
+	}
+	for i, s := range w.Scopes {
+		acw.Scopes[i].Reference = runtimev1alpha1.TypedReference{
+			APIVersion: s.GetAPIVersion(),
+			Kind:       s.GetKind(),
+			Name:       s.GetName(),
+		}
+	}
+	return acw
+}
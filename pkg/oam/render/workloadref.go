@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	errFmtGetWorkloadRefs     = "cannot get workloadRefs at %q"
+	errFmtWorkloadRefsNotList = "workloadRefs at %q are not a list"
+
+	// workloadRefListSuffix marks a workloadRefPath of a trait that refers to
+	// a list the workloadRef is appended to, e.g. spec.workloadRefs[].
+	workloadRefListSuffix = "[]"
+)
+
+// setWorkloadRef sets the supplied workloadRef at each of the supplied paths
+// of the supplied trait. Paths suffixed with [] refer to lists the workloadRef
+// is appended to, unless they already contain it.
+func setWorkloadRef(trait *unstructured.Unstructured, paths []string, workloadRef runtimev1alpha1.TypedReference) error {
+	p := fieldpath.Pave(trait.UnstructuredContent())
+	for _, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+		if !strings.HasSuffix(path, workloadRefListSuffix) {
+			if err := p.SetValue(path, workloadRef); err != nil {
+				return err
+			}
+			continue
+		}
+		path = strings.TrimSuffix(path, workloadRefListSuffix)
+		var refs []interface{}
+		value, err := p.GetValue(path)
+		switch {
+		case fieldpath.IsNotFound(err):
+		case err != nil:
+			return errors.Wrapf(err, errFmtGetWorkloadRefs, path)
+		default:
+			var ok bool
+			if refs, ok = value.([]interface{}); !ok {
+				return errors.Errorf(errFmtWorkloadRefsNotList, path)
+			}
+		}
+		if ContainsWorkloadRef(refs, workloadRef) {
+			continue
+		}
+		if err := p.SetValue(path, append(refs, workloadRef)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ContainsWorkloadRef returns true if the supplied list of references
+// contains the supplied workloadRef.
+func ContainsWorkloadRef(refs []interface{}, workloadRef runtimev1alpha1.TypedReference) bool {
+	for _, item := range refs {
+		ref, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if (workloadRef.APIVersion == ref["apiVersion"]) &&
+			(workloadRef.Kind == ref["kind"]) &&
+			(workloadRef.Name == ref["name"]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSetWorkloadRef(t *testing.T) {
+	ref := v1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+	refObj := map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web"}
+	other := map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "name": "db"}
+	trait := func(spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	}
+
+	cases := map[string]struct {
+		trait *unstructured.Unstructured
+		paths []string
+		want  *unstructured.Unstructured
+		err   error
+	}{
+		"NoPaths": {
+			trait: trait(map[string]interface{}{}),
+			paths: []string{""},
+			want:  trait(map[string]interface{}{}),
+		},
+		"SetReference": {
+			trait: trait(map[string]interface{}{}),
+			paths: []string{"spec.workloadRef", "spec.target.ref"},
+			want: trait(map[string]interface{}{
+				"workloadRef": refObj,
+				"target":      map[string]interface{}{"ref": refObj},
+			}),
+		},
+		"AppendToMissingList": {
+			trait: trait(map[string]interface{}{}),
+			paths: []string{"spec.workloadRefs[]"},
+			want:  trait(map[string]interface{}{"workloadRefs": []interface{}{refObj}}),
+		},
+		"AppendToList": {
+			trait: trait(map[string]interface{}{"workloadRefs": []interface{}{other}}),
+			paths: []string{"spec.workloadRef", "spec.workloadRefs[]"},
+			want: trait(map[string]interface{}{
+				"workloadRef":  refObj,
+				"workloadRefs": []interface{}{other, refObj},
+			}),
+		},
+		"ListContainsReference": {
+			trait: trait(map[string]interface{}{"workloadRefs": []interface{}{refObj, other}}),
+			paths: []string{"spec.workloadRefs[]"},
+			want:  trait(map[string]interface{}{"workloadRefs": []interface{}{refObj, other}}),
+		},
+		"NotAList": {
+			trait: trait(map[string]interface{}{"workloadRefs": "web"}),
+			paths: []string{"spec.workloadRefs[]"},
+			want:  trait(map[string]interface{}{"workloadRefs": "web"}),
+			err:   errors.Errorf(errFmtWorkloadRefsNotList, "spec.workloadRefs"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := setWorkloadRef(tc.trait, tc.paths, ref)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nsetWorkloadRef(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.trait); diff != "" {
+				t.Errorf("\nsetWorkloadRef(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
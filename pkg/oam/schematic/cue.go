@@ -0,0 +1,58 @@
+package schematic
+
+import (
+	"cuelang.org/go/cue"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ParameterField is the field of a CUE template that is filled with the
+	// parameter values of the workload.
+	ParameterField = "parameter"
+	// OutputField is the field of a CUE template that is rendered as the
+	// workload.
+	OutputField = "output"
+)
+
+const (
+	errCompileCUE      = "cannot compile CUE template"
+	errFillCUE         = "cannot fill parameter values into CUE template"
+	errFillCUEContext  = "cannot fill context into CUE template"
+	errNoCUEOutput     = "CUE template does not define an output"
+	errEvaluateCUE     = "cannot evaluate output of CUE template"
+	errMarshalCUEValue = "cannot marshal output of CUE template"
+)
+
+func renderCUE(template string, parameter, context map[string]interface{}) (*unstructured.Unstructured, error) {
+	var r cue.Runtime
+	// the context is declared so templates can refer to it before it is
+	// filled in. It is declared after the template so positions in errors
+	// match the template.
+	inst, err := r.Compile("-", template+"\n"+ContextField+": _\n")
+	if err != nil {
+		return nil, errors.Wrap(err, errCompileCUE)
+	}
+	inst, err = inst.Fill(parameter, ParameterField)
+	if err != nil {
+		return nil, errors.Wrap(err, errFillCUE)
+	}
+	inst, err = inst.Fill(context, ContextField)
+	if err != nil {
+		return nil, errors.Wrap(err, errFillCUEContext)
+	}
+	output := inst.Lookup(OutputField)
+	if !output.Exists() {
+		return nil, errors.New(errNoCUEOutput)
+	}
+	// the output must be concrete, i.e. every parameter the output refers to
+	// has either a value or a default.
+	if err := output.Validate(cue.Concrete(true)); err != nil {
+		return nil, errors.Wrap(err, errEvaluateCUE)
+	}
+	data, err := output.MarshalJSON()
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalCUEValue)
+	}
+	return unmarshal(data)
+}
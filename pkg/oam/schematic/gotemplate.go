@@ -0,0 +1,34 @@
+package schematic
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	errParseGoTemplate   = "cannot parse Go template"
+	errExecuteGoTemplate = "cannot execute Go template"
+	errConvertGoTemplate = "cannot convert the result of Go template to JSON"
+)
+
+func renderGoTemplate(tmpl string, values map[string]interface{}) (*unstructured.Unstructured, error) {
+	// referring to a parameter that has no value is an error rather than
+	// rendering "<no value>" into the workload.
+	t, err := template.New("workload").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseGoTemplate)
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, values); err != nil {
+		return nil, errors.Wrap(err, errExecuteGoTemplate)
+	}
+	data, err := yaml.YAMLToJSON(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, errConvertGoTemplate)
+	}
+	return unmarshal(data)
+}
@@ -0,0 +1,79 @@
+// Package schematic renders workloads from the templates defined in the
+// schematic of a Component or WorkloadDefinition.
+package schematic
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+// ContextField is the field of a CUE template, and the key of the data of a
+// Go template, that describes the workload being rendered.
+const ContextField = "context"
+
+const (
+	errNoTemplate = "schematic does not define any template"
+	errUnmarshal  = "cannot unmarshal rendered workload"
+)
+
+// IsTemplated returns true if the supplied schematic defines a template.
+func IsTemplated(s *v1alpha2.Schematic) bool {
+	return s != nil && (s.CUE != nil || s.GoTemplate != nil)
+}
+
+// A Context describes the workload a template is rendered for. Templates refer
+// to it as 'context', e.g. 'context.revisionName' in CUE or
+// '{{ .context.revisionName }}' in Go templates.
+type Context struct {
+	// ComponentName is the name of the component of the workload.
+	ComponentName string
+
+	// RevisionName is the name of the component revision of the workload.
+	RevisionName string
+
+	// RevisionHash is the hash of the component revision of the workload.
+	RevisionHash string
+}
+
+func (c Context) values() map[string]interface{} {
+	return map[string]interface{}{
+		"componentName": c.ComponentName,
+		"revisionName":  c.RevisionName,
+		"revisionHash":  c.RevisionHash,
+	}
+}
+
+// Render the template defined by the supplied schematic into a workload. A
+// CUE template is filled with the supplied spec of the workload, while a Go
+// template is executed with the supplied parameter values as data. Both can
+// refer to the supplied context.
+func Render(s *v1alpha2.Schematic, spec, values map[string]interface{}, c Context) (*unstructured.Unstructured, error) {
+	switch {
+	case s != nil && s.CUE != nil:
+		if spec == nil {
+			spec = make(map[string]interface{})
+		}
+		return renderCUE(s.CUE.Template, spec, c.values())
+	case s != nil && s.GoTemplate != nil:
+		data := make(map[string]interface{}, len(values)+1)
+		for k, v := range values {
+			data[k] = v
+		}
+		// the context takes precedence over a parameter of the same name
+		data[ContextField] = c.values()
+		return renderGoTemplate(s.GoTemplate.Template, data)
+	}
+	return nil, errors.New(errNoTemplate)
+}
+
+func unmarshal(data []byte) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &u.Object); err != nil {
+		return nil, errors.Wrap(err, errUnmarshal)
+	}
+	return u, nil
+}
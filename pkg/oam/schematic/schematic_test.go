@@ -0,0 +1,215 @@
+package schematic
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+)
+
+const deploymentTemplate = `
+parameter: {
+	image:    string
+	replicas: *1 | int
+}
+
+output: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	spec: {
+		replicas: parameter.replicas
+		template: spec: containers: [{
+			name:  "main"
+			image: parameter.image
+		}]
+	}
+}
+`
+
+const deploymentGoTemplate = `
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  replicas: {{ .replicas }}
+  template:
+    spec:
+      containers:
+        - name: main
+          image: {{ .image }}
+`
+
+func TestRender(t *testing.T) {
+	type args struct {
+		s      *v1alpha2.Schematic
+		spec   map[string]interface{}
+		values map[string]interface{}
+		c      Context
+	}
+	type want struct {
+		w   *unstructured.Unstructured
+		err bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTemplate": {
+			reason: "An error should be returned when the schematic defines no template",
+			args: args{
+				s: &v1alpha2.Schematic{},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"CUEWithDefault": {
+			reason: "Omitted parameters should fall back to the defaults of the CUE template",
+			args: args{
+				s:    &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: deploymentTemplate}},
+				spec: map[string]interface{}{"image": "nginx"},
+			},
+			want: want{
+				w: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"spec": map[string]interface{}{
+						"replicas": float64(1),
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name":  "main",
+										"image": "nginx",
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+		"CUEMissingParameter": {
+			reason: "An error should be returned when a parameter without default is omitted",
+			args: args{
+				s: &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: deploymentTemplate}},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"CUEMistypedParameter": {
+			reason: "An error should be returned when a parameter does not match its type",
+			args: args{
+				s:    &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: deploymentTemplate}},
+				spec: map[string]interface{}{"image": "nginx", "replicas": "two"},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"CUENoOutput": {
+			reason: "An error should be returned when the CUE template defines no output",
+			args: args{
+				s: &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: `parameter: {}`}},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"GoTemplate": {
+			reason: "A Go template should be executed with the parameter values as data",
+			args: args{
+				s:      &v1alpha2.Schematic{GoTemplate: &v1alpha2.GoTemplate{Template: deploymentGoTemplate}},
+				values: map[string]interface{}{"image": "nginx", "replicas": 2},
+			},
+			want: want{
+				w: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": "apps/v1",
+					"kind":       "Deployment",
+					"spec": map[string]interface{}{
+						"replicas": float64(2),
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"containers": []interface{}{
+									map[string]interface{}{
+										"name":  "main",
+										"image": "nginx",
+									},
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+		"CUEContext": {
+			reason: "A CUE template should be able to refer to the context",
+			args: args{
+				s: &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: `
+output: {
+	kind: "ConfigMap"
+	data: revision: context.revisionName
+}`}},
+				c: Context{ComponentName: "web", RevisionName: "web-v1", RevisionHash: "abc"},
+			},
+			want: want{
+				w: &unstructured.Unstructured{Object: map[string]interface{}{
+					"kind": "ConfigMap",
+					"data": map[string]interface{}{"revision": "web-v1"},
+				}},
+			},
+		},
+		"GoTemplateContext": {
+			reason: "A Go template should be able to refer to the context",
+			args: args{
+				s: &v1alpha2.Schematic{GoTemplate: &v1alpha2.GoTemplate{Template: `
+kind: ConfigMap
+data:
+  hash: {{ .context.revisionHash }}
+  image: {{ .image }}`}},
+				values: map[string]interface{}{"image": "nginx"},
+				c:      Context{ComponentName: "web", RevisionName: "web-v1", RevisionHash: "abc"},
+			},
+			want: want{
+				w: &unstructured.Unstructured{Object: map[string]interface{}{
+					"kind": "ConfigMap",
+					"data": map[string]interface{}{"hash": "abc", "image": "nginx"},
+				}},
+			},
+		},
+		"GoTemplateMissingValue": {
+			reason: "An error should be returned when a Go template refers to a parameter without value",
+			args: args{
+				s:      &v1alpha2.Schematic{GoTemplate: &v1alpha2.GoTemplate{Template: deploymentGoTemplate}},
+				values: map[string]interface{}{"image": "nginx"},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"GoTemplateMalformed": {
+			reason: "An error should be returned when a Go template cannot be parsed",
+			args: args{
+				s: &v1alpha2.Schematic{GoTemplate: &v1alpha2.GoTemplate{Template: "kind: {{ .kind"}},
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Render(tc.args.s, tc.args.spec, tc.args.values, tc.args.c)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nRender(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.w, got); diff != "" {
+				t.Errorf("\n%s\nRender(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -6,21 +6,32 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"path"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	cpv1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -47,6 +58,38 @@ const (
 
 	// DummyTraitMessage is a message for trait which don't have definition found
 	DummyTraitMessage = "No valid TraitDefinition found, all framework capabilities will work as default or disabled"
+
+	// RevisionNameIndex is the field index of ApplicationConfigurations by
+	// the component revisions their components are pinned to
+	RevisionNameIndex = "spec.components.revisionName"
+
+	// ComponentNameIndex is the field index of ApplicationConfigurations by
+	// the components their components follow the latest revision of
+	ComponentNameIndex = "spec.components.componentName"
+
+	// ConsumedRevisionNameIndex is the field index of ApplicationConfigurations
+	// by the component revisions they are pinned to or currently run
+	ConsumedRevisionNameIndex = "status.workloads.componentRevisionName"
+
+	// WorkloadDefinitionIndex is the field index of Components by the
+	// definitions of their workloads and auxiliary workloads
+	WorkloadDefinitionIndex = "spec.workload.definition"
+
+	// TraitDefinitionIndex is the field index of ApplicationConfigurations by
+	// the definitions of their traits
+	TraitDefinitionIndex = "spec.components.traits.definition"
+
+	// ScopeDefinitionIndex is the field index of ApplicationConfigurations by
+	// the definitions of the scopes their components are in
+	ScopeDefinitionIndex = "spec.components.scopes.definition"
+
+	// WorkloadReferenceIndex is the field index of scopes by the workloads
+	// they reference
+	WorkloadReferenceIndex = "spec.workloadRefs"
+
+	// ScopeReferenceIndex is the field index of ApplicationConfigurations by
+	// the scopes their workloads are, or are to be, in
+	ScopeReferenceIndex = "status.workloads.scopes.scopeRef"
 )
 
 const (
@@ -56,6 +99,8 @@ const (
 	ErrLocateAppConfig = "cannot locate the parent application configuration to emit event to"
 	// ErrLocateWorkload is the error while locate the workload
 	ErrLocateWorkload = "cannot find the workload that the trait is referencing to"
+	// ErrLocateWorkloadDefinition is the error while locating the WorkloadDefinition of the workload
+	ErrLocateWorkloadDefinition = "cannot find the WorkloadDefinition of the workload that the trait is referencing to"
 	// ErrFetchChildResources is the error while fetching workload child resources
 	ErrFetchChildResources = "failed to fetch workload child resources"
 
@@ -63,6 +108,14 @@ const (
 	errFmtControllerRevisionData = "cannot get valid component data from controllerRevision %q"
 	errFmtGetComponent           = "cannot get component %q"
 	errFmtInvalidRevisionType    = "invalid type of revision %s, type should not be %v"
+	errFmtInvalidConflictRule    = "invalid conflictsWith rule %q of trait definition %q"
+	errFmtInvalidAppliesToRule   = "invalid appliesToWorkloads rule %q of %s %q"
+	errFmtInvalidPinnedRevisions = "invalid annotation %q"
+	errFmtAmbiguousDefinition    = "%ss %s all refer to %q and none is named after it"
+	errFmtVersionNotServed       = "version %q of CustomResourceDefinition %q is not served, served versions are %v"
+	errFmtNoServedVersion        = "CustomResourceDefinition %q serves no version"
+	errFmtGetPodSpec             = "cannot get the pod spec at %q"
+	errFmtPodSpecNotObject       = "the pod spec at %q is not an object"
 )
 
 // A ConditionedObject is an Object type with condition field
@@ -119,6 +172,42 @@ func FetchWorkload(ctx context.Context, c client.Client, mLog logr.Logger, oamTr
 	return &workload, nil
 }
 
+// FetchTraitWorkload fetches the workload that the supplied trait refers to,
+// and its WorkloadDefinition. Workloads without a WorkloadDefinition get a
+// dummy one, since they are not blocked from running. Errors are wrapped with
+// ErrLocateWorkload or ErrLocateWorkloadDefinition, so that trait controllers
+// can report them as is; the cause is a NotFound error if the workload is
+// gone.
+func FetchTraitWorkload(ctx context.Context, c client.Client, dm discoverymapper.DiscoveryMapper, mLog logr.Logger,
+	oamTrait oam.Trait) (*unstructured.Unstructured, *v1alpha2.WorkloadDefinition, error) {
+	workload, err := FetchWorkload(ctx, c, mLog, oamTrait)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, ErrLocateWorkload)
+	}
+	wd, err := FetchWorkloadDefinitionOrDummy(ctx, c, dm, mLog, workload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return workload, wd, nil
+}
+
+// FetchWorkloadDefinitionOrDummy fetches the WorkloadDefinition of the
+// supplied workload, or returns a dummy one if it has none, like
+// FetchTraitWorkload does for the workloads of traits. Errors are wrapped with
+// ErrLocateWorkloadDefinition.
+func FetchWorkloadDefinitionOrDummy(ctx context.Context, c client.Reader, dm discoverymapper.DiscoveryMapper, mLog logr.Logger,
+	workload *unstructured.Unstructured) (*v1alpha2.WorkloadDefinition, error) {
+	wd, err := FetchWorkloadDefinition(ctx, c, dm, workload)
+	if apierrors.IsNotFound(err) {
+		mLog.Info("Workload has no WorkloadDefinition", "kind", workload.GetKind(), "workload name", workload.GetName())
+		return GetDummyWorkloadDefinition(workload), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, ErrLocateWorkloadDefinition)
+	}
+	return wd, nil
+}
+
 // GetDummyTraitDefinition will generate a dummy TraitDefinition for CustomResource that won't block app from running.
 // OAM runtime will report warning if they got this dummy definition.
 func GetDummyTraitDefinition(u *unstructured.Unstructured) *v1alpha2.TraitDefinition {
@@ -147,100 +236,384 @@ func GetDummyWorkloadDefinition(u *unstructured.Unstructured) *v1alpha2.Workload
 	}
 }
 
-// FetchScopeDefinition fetch corresponding scopeDefinition given a scope
-func FetchScopeDefinition(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
-	scope *unstructured.Unstructured) (*v1alpha2.ScopeDefinition, error) {
-	// The name of the scopeDefinition CR is the CRD name of the scope
-	// TODO(wonderflow): we haven't support scope definition label type yet.
-	spName, err := GetDefinitionName(dm, scope, "")
+// conflictsWithLabelSelectorPrefix is the prefix of conflictsWith rules of
+// TraitDefinitions that select conflicting traits by the labels of their
+// TraitDefinition.
+const conflictsWithLabelSelectorPrefix = "labelSelector:"
+
+// TraitConflictsWith returns true if the other TraitDefinition matches one of
+// the conflictsWith rules of the supplied TraitDefinition. Rules match the
+// name of the other TraitDefinition or of its CustomResourceDefinition, its API
+// group if prefixed with '*.', or the labels of the other TraitDefinition if
+// prefixed with 'labelSelector:'.
+func TraitConflictsWith(td, other *v1alpha2.TraitDefinition) (bool, error) {
+	for _, rule := range td.Spec.ConflictsWith {
+		switch {
+		case strings.HasPrefix(rule, conflictsWithLabelSelectorPrefix):
+			sel, err := labels.Parse(strings.TrimPrefix(rule, conflictsWithLabelSelectorPrefix))
+			if err != nil {
+				return false, errors.Wrapf(err, errFmtInvalidConflictRule, rule, td.GetName())
+			}
+			if sel.Matches(labels.Set(other.GetLabels())) {
+				return true, nil
+			}
+		case strings.HasPrefix(rule, "*."):
+			if strings.HasSuffix(other.Spec.Reference.Name, rule[1:]) {
+				return true, nil
+			}
+		case rule == other.GetName() || rule == other.Spec.Reference.Name:
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TraitsConflict returns true if either of the supplied TraitDefinitions
+// conflicts with the other one.
+func TraitsConflict(a, b *v1alpha2.TraitDefinition) (bool, error) {
+	if conflict, err := TraitConflictsWith(a, b); conflict || err != nil {
+		return conflict, err
+	}
+	return TraitConflictsWith(b, a)
+}
+
+// TraitAppliesToWorkload returns true if the supplied workload matches one of
+// the appliesToWorkloads rules of the supplied TraitDefinition, or if it has
+// none. Rules match the name of the WorkloadDefinition of the workload, e.g.
+// containerizedworkloads.core.oam.dev, or its kind in kind.group/version or
+// kind.group format, e.g. deployment.apps/v1. Kinds are matched case
+// insensitively, rules may contain shell wildcards, e.g. *.core.oam.dev.
+func TraitAppliesToWorkload(dm discoverymapper.DiscoveryMapper, td *v1alpha2.TraitDefinition, w *unstructured.Unstructured) (bool, error) {
+	return appliesToWorkload(dm, td.Spec.AppliesToWorkloads, w, "trait definition", td.GetName())
+}
+
+// ScopeAppliesToWorkload returns true if the supplied workload matches one of
+// the appliesToWorkloads rules of the supplied ScopeDefinition, or if it has
+// none. Rules match workloads as they do for TraitAppliesToWorkload.
+func ScopeAppliesToWorkload(dm discoverymapper.DiscoveryMapper, sd *v1alpha2.ScopeDefinition, w *unstructured.Unstructured) (bool, error) {
+	return appliesToWorkload(dm, sd.Spec.AppliesToWorkloads, w, "scope definition", sd.GetName())
+}
+
+func appliesToWorkload(dm discoverymapper.DiscoveryMapper, rules []string, w *unstructured.Unstructured, definitionKind, definitionName string) (bool, error) {
+	if len(rules) == 0 {
+		return true, nil
+	}
+	gvk := w.GroupVersionKind()
+	kindGroup := strings.ToLower(gvk.Kind)
+	if gvk.Group != "" {
+		kindGroup += "." + gvk.Group
+	}
+	candidates := []string{kindGroup, kindGroup + "/" + gvk.Version}
+	// workloads of kinds the API server doesn't serve have no definition
+	if name, err := GetDefinitionName(dm, w, oam.WorkloadTypeLabel); err == nil {
+		candidates = append(candidates, name)
+	}
+	for _, rule := range rules {
+		for _, c := range candidates {
+			match, err := path.Match(strings.ToLower(rule), strings.ToLower(c))
+			if err != nil {
+				return false, errors.Wrapf(err, errFmtInvalidAppliesToRule, rule, definitionKind, definitionName)
+			}
+			if match {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// ValidateParameterValue validates the supplied value of the supplied
+// parameter against the type, enum and range the parameter declares.
+func ValidateParameterValue(fldPath *field.Path, p v1alpha2.ComponentParameter, v intstr.IntOrString) field.ErrorList {
+	var allErrs field.ErrorList
+	switch p.Type {
+	case v1alpha2.StringParameterType:
+		if v.Type != intstr.String {
+			allErrs = append(allErrs, field.Invalid(fldPath, v.IntVal, fmt.Sprintf("parameter %q must be a string", p.Name)))
+		}
+	case v1alpha2.IntegerParameterType:
+		if v.Type != intstr.Int {
+			allErrs = append(allErrs, field.Invalid(fldPath, v.StrVal, fmt.Sprintf("parameter %q must be an integer", p.Name)))
+		}
+	case v1alpha2.BooleanParameterType:
+		if v.Type != intstr.String || (v.StrVal != "true" && v.StrVal != "false") {
+			allErrs = append(allErrs, field.NotSupported(fldPath, v.String(), []string{"true", "false"}))
+		}
+	}
+	if len(p.Enum) > 0 {
+		supported := make([]string, 0, len(p.Enum))
+		found := false
+		for _, e := range p.Enum {
+			supported = append(supported, e.String())
+			if e == v {
+				found = true
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.NotSupported(fldPath, v.String(), supported))
+		}
+	}
+	if v.Type == intstr.Int {
+		if p.Minimum != nil && int64(v.IntVal) < *p.Minimum {
+			allErrs = append(allErrs, field.Invalid(fldPath, v.IntVal,
+				fmt.Sprintf("parameter %q must be greater than or equal to %d", p.Name, *p.Minimum)))
+		}
+		if p.Maximum != nil && int64(v.IntVal) > *p.Maximum {
+			allErrs = append(allErrs, field.Invalid(fldPath, v.IntVal,
+				fmt.Sprintf("parameter %q must be less than or equal to %d", p.Name, *p.Maximum)))
+		}
+	}
+	return allErrs
+}
+
+// A DefinitionFinder finds the definitions of a kind, e.g. TraitDefinition,
+// that refer to a resource, e.g. deployments.apps. Readers that implement it,
+// e.g. the definition cache, resolve objects to definitions that are not
+// named after the resource they refer to.
+type DefinitionFinder interface {
+	FindDefinitions(kind, resource string) []string
+}
+
+// ResolveDefinitionName returns the name of the definition of the supplied
+// kind, e.g. TraitDefinition, of the supplied object. It is the definition
+// named by the type label of the object if it has one. Otherwise it is the
+// only definition that refers to the resource of the object, if the supplied
+// reader is a DefinitionFinder, and else the definition named after the
+// resource, e.g. deployments.apps.
+func ResolveDefinitionName(r client.Reader, dm discoverymapper.DiscoveryMapper, kind string,
+	u *unstructured.Unstructured) (string, error) {
+	typeLabel := ""
+	switch kind {
+	case v1alpha2.WorkloadDefinitionKind:
+		typeLabel = oam.WorkloadTypeLabel
+	case v1alpha2.TraitDefinitionKind:
+		typeLabel = oam.TraitTypeLabel
+	}
+	resource, err := GetDefinitionName(dm, u, typeLabel)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	// the scopeDefinition crd is cluster scoped
-	nn := types.NamespacedName{Name: spName}
-	// Fetch the corresponding scopeDefinition CR
-	scopeDefinition := &v1alpha2.ScopeDefinition{}
-	if err := r.Get(ctx, nn, scopeDefinition); err != nil {
-		return nil, err
+	if _, ok := u.GetLabels()[typeLabel]; ok && typeLabel != "" {
+		return resource, nil
 	}
-	return scopeDefinition, nil
+	f, ok := r.(DefinitionFinder)
+	if !ok {
+		return resource, nil
+	}
+	names := f.FindDefinitions(kind, resource)
+	switch len(names) {
+	case 0:
+		return resource, nil
+	case 1:
+		return names[0], nil
+	}
+	// the definition named after the resource is its default
+	for _, name := range names {
+		if name == resource {
+			return name, nil
+		}
+	}
+	return "", errors.Errorf(errFmtAmbiguousDefinition, kind, strings.Join(names, ", "), resource)
 }
 
-// FetchTraitDefinition fetch corresponding traitDefinition given a trait
-func FetchTraitDefinition(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
-	trait *unstructured.Unstructured) (*v1alpha2.TraitDefinition, error) {
-	// The name of the traitDefinition CR is the CRD name of the trait
-	trName, err := GetDefinitionName(dm, trait, oam.TraitTypeLabel)
+// ServedVersions returns the versions the supplied CRD serves, in the order
+// they are listed.
+func ServedVersions(crd *crdv1.CustomResourceDefinition) []string {
+	var served []string
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			served = append(served, v.Name)
+		}
+	}
+	return served
+}
+
+// DefinitionVersion returns the version of the supplied CRD that objects of a
+// definition with the supplied reference are created at. It is the version of
+// the reference, which must be served, or else the storage version of the CRD
+// if it is served, or else its first served version.
+func DefinitionVersion(crd *crdv1.CustomResourceDefinition, ref v1alpha2.DefinitionReference) (string, error) {
+	served := ServedVersions(crd)
+	if ref.Version != "" {
+		for _, v := range served {
+			if v == ref.Version {
+				return v, nil
+			}
+		}
+		return "", errors.Errorf(errFmtVersionNotServed, ref.Version, crd.GetName(), served)
+	}
+	for _, v := range crd.Spec.Versions {
+		if v.Served && v.Storage {
+			return v.Name, nil
+		}
+	}
+	if len(served) == 0 {
+		return "", errors.Errorf(errFmtNoServedVersion, crd.GetName())
+	}
+	return served[0], nil
+}
+
+// A NamespacedDefinitionGetter gets the definitions registered in a
+// namespace, e.g. by a DefinitionCatalog, and returns a NotFound error if there
+// is none. Readers that implement it, e.g. the definition cache, resolve
+// objects to the definitions of their namespace before the cluster scoped
+// definitions.
+type NamespacedDefinitionGetter interface {
+	GetNamespacedDefinition(ctx context.Context, namespace, name string, obj runtime.Object) error
+}
+
+// fetchDefinition reads the definition of the supplied name into the supplied
+// object. It is the definition registered in the supplied namespace if the
+// reader is a NamespacedDefinitionGetter that has one, and else the cluster
+// scoped definition.
+func fetchDefinition(ctx context.Context, r client.Reader, namespace, name string, obj runtime.Object) error {
+	if g, ok := r.(NamespacedDefinitionGetter); ok && namespace != "" {
+		if err := g.GetNamespacedDefinition(ctx, namespace, name, obj); !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	// the definition crds are cluster scoped
+	return r.Get(ctx, types.NamespacedName{Name: name}, obj)
+}
+
+// A Definition is a WorkloadDefinition, TraitDefinition or ScopeDefinition.
+type Definition interface {
+	metav1.Object
+	runtime.Object
+}
+
+// FetchDefinition fetches the definition of the supplied OAM resource, i.e.
+// the WorkloadDefinition of a workload, the TraitDefinition of a trait or the
+// ScopeDefinition of a scope. The kind of the definition is that of the type
+// label or the resource type label of the resource if it has one, and else
+// the first kind, of workload, trait and scope, the resource has a
+// definition of. Definitions of all kinds are read through the supplied
+// reader, e.g. the definition cache. The error is a NotFound error if the
+// resource has no definition.
+func FetchDefinition(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
+	u *unstructured.Unstructured) (Definition, error) {
+	var name string
+	for _, kind := range definitionKinds(u) {
+		var def Definition
+		switch kind {
+		case v1alpha2.WorkloadDefinitionKind:
+			def = &v1alpha2.WorkloadDefinition{}
+		case v1alpha2.TraitDefinitionKind:
+			def = &v1alpha2.TraitDefinition{}
+		case v1alpha2.ScopeDefinitionKind:
+			def = &v1alpha2.ScopeDefinition{}
+		}
+		n, err := fetchDefinitionOfKind(ctx, r, dm, kind, u, def)
+		if err == nil {
+			return def, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		name = n
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "definitions"}, name)
+}
+
+// definitionKinds returns the kinds of definition the supplied OAM resource
+// may have, in the order they are tried.
+func definitionKinds(u *unstructured.Unstructured) []string {
+	l := u.GetLabels()
+	if _, ok := l[oam.WorkloadTypeLabel]; ok {
+		return []string{v1alpha2.WorkloadDefinitionKind}
+	}
+	if _, ok := l[oam.TraitTypeLabel]; ok {
+		return []string{v1alpha2.TraitDefinitionKind}
+	}
+	switch l[oam.LabelOAMResourceType] {
+	case oam.ResourceTypeWorkload:
+		return []string{v1alpha2.WorkloadDefinitionKind}
+	case oam.ResourceTypeTrait:
+		return []string{v1alpha2.TraitDefinitionKind}
+	}
+	return []string{v1alpha2.WorkloadDefinitionKind, v1alpha2.TraitDefinitionKind, v1alpha2.ScopeDefinitionKind}
+}
+
+// fetchDefinitionOfKind reads the definition of the supplied kind of the
+// supplied OAM resource into the supplied object, and returns its name.
+func fetchDefinitionOfKind(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper, kind string,
+	u *unstructured.Unstructured, obj runtime.Object) (string, error) {
+	name, err := ResolveDefinitionName(r, dm, kind, u)
 	if err != nil {
+		return "", err
+	}
+	return name, fetchDefinition(ctx, r, u.GetNamespace(), name, obj)
+}
+
+// FetchScopeDefinition fetches the ScopeDefinition of the supplied scope. It
+// is FetchDefinition for resources known to be scopes.
+func FetchScopeDefinition(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
+	scope *unstructured.Unstructured) (*v1alpha2.ScopeDefinition, error) {
+	sd := &v1alpha2.ScopeDefinition{}
+	if _, err := fetchDefinitionOfKind(ctx, r, dm, v1alpha2.ScopeDefinitionKind, scope, sd); err != nil {
 		return nil, err
 	}
-	// the traitDefinition crd is cluster scoped
-	nn := types.NamespacedName{Name: trName}
-	// Fetch the corresponding traitDefinition CR
-	traitDefinition := &v1alpha2.TraitDefinition{}
-	if err := r.Get(ctx, nn, traitDefinition); err != nil {
+	return sd, nil
+}
+
+// FetchTraitDefinition fetches the TraitDefinition of the supplied trait. It
+// is FetchDefinition for resources known to be traits.
+func FetchTraitDefinition(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
+	trait *unstructured.Unstructured) (*v1alpha2.TraitDefinition, error) {
+	td := &v1alpha2.TraitDefinition{}
+	if _, err := fetchDefinitionOfKind(ctx, r, dm, v1alpha2.TraitDefinitionKind, trait, td); err != nil {
 		return nil, err
 	}
-	return traitDefinition, nil
+	return td, nil
 }
 
-// FetchWorkloadDefinition fetch corresponding workloadDefinition given a workload
+// FetchWorkloadDefinition fetches the WorkloadDefinition of the supplied
+// workload. It is FetchDefinition for resources known to be workloads.
 func FetchWorkloadDefinition(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
 	workload *unstructured.Unstructured) (*v1alpha2.WorkloadDefinition, error) {
-	// The name of the workloadDefinition CR is the CRD name of the component
-	wldName, err := GetDefinitionName(dm, workload, oam.WorkloadTypeLabel)
-	if err != nil {
-		return nil, err
-	}
-	// the workloadDefinition crd is cluster scoped
-	nn := types.NamespacedName{Name: wldName}
-	// Fetch the corresponding workloadDefinition CR
-	workloadDefinition := &v1alpha2.WorkloadDefinition{}
-	if err := r.Get(ctx, nn, workloadDefinition); err != nil {
+	wd := &v1alpha2.WorkloadDefinition{}
+	if _, err := fetchDefinitionOfKind(ctx, r, dm, v1alpha2.WorkloadDefinitionKind, workload, wd); err != nil {
 		return nil, err
 	}
-	return workloadDefinition, nil
+	return wd, nil
 }
 
-// FetchWorkloadChildResources fetch corresponding child resources given a workload
-func FetchWorkloadChildResources(ctx context.Context, mLog logr.Logger, r client.Reader,
-	dm discoverymapper.DiscoveryMapper, workload *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
-	// Fetch the corresponding workloadDefinition CR
-	workloadDefinition, err := FetchWorkloadDefinition(ctx, r, dm, workload)
-	if err != nil {
-		// No definition will won't block app from running
-		if apierrors.IsNotFound(err) {
-			return nil, nil
-		}
-		return nil, err
+// DeprecationWarning returns a warning that the definition of the supplied
+// kind and name is deprecated, naming the definition that replaces it if
+// there is one.
+func DeprecationWarning(kind, name, replacedBy string) string {
+	if replacedBy == "" {
+		return fmt.Sprintf("%s %q is deprecated", kind, name)
 	}
-	return fetchChildResources(ctx, mLog, r, workload, workloadDefinition.Spec.ChildResourceKinds)
+	return fmt.Sprintf("%s %q is deprecated, use %q instead", kind, name, replacedBy)
 }
 
-func fetchChildResources(ctx context.Context, mLog logr.Logger, r client.Reader, workload *unstructured.Unstructured,
-	wcrl []v1alpha2.ChildResourceKind) ([]*unstructured.Unstructured, error) {
+// FetchWorkloadChildResources lists the child resources of the supplied
+// workload, i.e. the resources of the childResourceKinds of the supplied
+// WorkloadDefinition in the namespace of the workload that the workload owns.
+// Workloads without a WorkloadDefinition, i.e. a nil one, have no child
+// resources.
+func FetchWorkloadChildResources(ctx context.Context, r client.Reader, workload *unstructured.Unstructured,
+	wd *v1alpha2.WorkloadDefinition) ([]*unstructured.Unstructured, error) {
+	if wd == nil {
+		return nil, nil
+	}
 	var childResources []*unstructured.Unstructured
 	// list by each child resource type with namespace and possible label selector
-	for _, wcr := range wcrl {
+	for _, wcr := range wd.Spec.ChildResourceKinds {
 		crs := unstructured.UnstructuredList{}
 		crs.SetAPIVersion(wcr.APIVersion)
 		crs.SetKind(wcr.Kind)
-		mLog.Info("List child resource kind", "APIVersion", wcr.APIVersion, "Kind", wcr.Kind, "owner UID",
-			workload.GetUID())
 		if err := r.List(ctx, &crs, client.InNamespace(workload.GetNamespace()),
 			client.MatchingLabels(wcr.Selector)); err != nil {
-			mLog.Error(err, "failed to list object", "api version", crs.GetAPIVersion(), "kind", crs.GetKind())
 			return nil, err
 		}
-		// pick the ones that is owned by the workload
-		for _, cr := range crs.Items {
-			for _, owner := range cr.GetOwnerReferences() {
+		// pick the ones that are owned by the workload
+		for i := range crs.Items {
+			for _, owner := range crs.Items[i].GetOwnerReferences() {
 				if owner.UID == workload.GetUID() {
-					mLog.Info("Find a child resource we are looking for",
-						"APIVersion", cr.GetAPIVersion(), "Kind", cr.GetKind(),
-						"Name", cr.GetName(), "owner", owner.UID)
-					or := cr // have to do a copy as the range variable is a reference and will change
-					childResources = append(childResources, &or)
+					childResources = append(childResources, &crs.Items[i])
+					break
 				}
 			}
 		}
@@ -248,6 +621,67 @@ func fetchChildResources(ctx context.Context, mLog logr.Logger, r client.Reader,
 	return childResources, nil
 }
 
+// HasScaleSubresource returns true if the supplied resource serves the scale
+// subresource, like Deployments, StatefulSets and custom resources whose CRD
+// enables it do.
+func HasScaleSubresource(dc discovery.ServerResourcesInterface, gvr schema.GroupVersionResource) (bool, error) {
+	resources, err := dc.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == gvr.Resource+"/scale" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FetchWorkloadPodSpec returns a paved accessor to the pod spec of the
+// supplied workload, at the podSpecPath of its WorkloadDefinition. Changes
+// made through the accessor are made to the workload. It returns a nil
+// accessor if the workload has no WorkloadDefinition, or one without
+// podSpecPath.
+func FetchWorkloadPodSpec(ctx context.Context, r client.Reader, dm discoverymapper.DiscoveryMapper,
+	workload *unstructured.Unstructured) (*fieldpath.Paved, error) {
+	workloadDefinition, err := FetchWorkloadDefinition(ctx, r, dm, workload)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(workloadDefinition.Spec.PodSpecPath) == 0 {
+		return nil, nil
+	}
+	return PavePodSpec(workload, workloadDefinition.Spec.PodSpecPath)
+}
+
+// PavePodSpec returns a paved accessor to the pod spec at the supplied path of
+// the supplied workload, creating an empty pod spec if the workload has none.
+// Changes made through the accessor are made to the workload.
+func PavePodSpec(workload *unstructured.Unstructured, podSpecPath string) (*fieldpath.Paved, error) {
+	if workload.Object == nil {
+		workload.Object = make(map[string]interface{})
+	}
+	p := fieldpath.Pave(workload.Object)
+	v, err := p.GetValue(podSpecPath)
+	if fieldpath.IsNotFound(err) {
+		if err := p.SetValue(podSpecPath, map[string]interface{}{}); err != nil {
+			return nil, errors.Wrapf(err, errFmtGetPodSpec, podSpecPath)
+		}
+		v, err = p.GetValue(podSpecPath)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtGetPodSpec, podSpecPath)
+	}
+	podSpec, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf(errFmtPodSpecNotObject, podSpecPath)
+	}
+	return fieldpath.Pave(podSpec), nil
+}
+
 // PatchCondition condition for a conditioned object
 func PatchCondition(ctx context.Context, r client.StatusClient, workload ConditionedObject,
 	condition ...cpv1alpha1.Condition) error {
@@ -266,12 +700,25 @@ type labelAnnotationObject interface {
 	SetAnnotations(annotations map[string]string)
 }
 
+// annotations that only make sense on the parent object and must not be
+// passed through to its children.
+var ignoredPassThroughAnnotations = map[string]bool{
+	corev1.LastAppliedConfigAnnotation: true,
+	oam.AnnotationRenderDiff:           true,
+}
+
 // PassLabelAndAnnotation passes through labels and annotation objectMeta from the parent to the child object
 func PassLabelAndAnnotation(parentObj oam.Object, childObj labelAnnotationObject) {
 	// pass app-config labels
 	childObj.SetLabels(MergeMap(parentObj.GetLabels(), childObj.GetLabels()))
 	// pass app-config annotation
-	childObj.SetAnnotations(MergeMap(parentObj.GetAnnotations(), childObj.GetAnnotations()))
+	annotations := make(map[string]string, len(parentObj.GetAnnotations()))
+	for k, v := range parentObj.GetAnnotations() {
+		if !ignoredPassThroughAnnotations[k] {
+			annotations[k] = v
+		}
+	}
+	childObj.SetAnnotations(MergeMap(annotations, childObj.GetAnnotations()))
 }
 
 // GetDefinitionName return the Definition name of any resources
@@ -327,25 +774,197 @@ func GenTraitName(componentName string, ct *v1alpha2.ComponentTrait) string {
 // ComputeHash returns a hash value calculated from pod template and
 // a collisionCount to avoid hash collision. The hash will be safe encoded to
 // avoid bad words.
+// The workload name of the trait is only hashed when set, so that traits of
+// the main workload of a component keep their names.
 func ComputeHash(trait *v1alpha2.ComponentTrait) string {
 	componentTraitHasher := fnv.New32a()
-	DeepHashObject(componentTraitHasher, *trait)
+	content := hashPrinter.Sprintf("%#v", *trait)
+	if trait.WorkloadName == "" {
+		content = strings.Replace(content, " WorkloadName:(string) ", " ", 1)
+	}
+	_, _ = componentTraitHasher.Write([]byte(content))
 
 	return rand.SafeEncodeString(fmt.Sprint(componentTraitHasher.Sum32()))
 }
 
+// ComputeComponentRevisionHash returns a hash value calculated from the spec
+// of the supplied component, which identifies its revision. Only semantic
+// changes of the spec change the hash: workloads are hashed as decoded JSON
+// without the metadata maintained by the API server and other controllers.
+// The hash will be safe encoded to avoid bad words.
+func ComputeComponentRevisionHash(c *v1alpha2.Component) string {
+	componentHasher := fnv.New32a()
+	DeepHashObject(componentHasher, componentRevisionContent(c))
+
+	return rand.SafeEncodeString(fmt.Sprint(componentHasher.Sum32()))
+}
+
+// ComputeDefinitionRevisionHash returns a hash value calculated from the
+// supplied spec of a WorkloadDefinition or TraitDefinition, which identifies
+// its revision. The hash will be safe encoded to avoid bad words.
+func ComputeDefinitionRevisionHash(spec interface{}) string {
+	definitionHasher := fnv.New32a()
+	DeepHashObject(definitionHasher, spec)
+
+	return rand.SafeEncodeString(fmt.Sprint(definitionHasher.Sum32()))
+}
+
+// DefinitionRevisionName returns the name of the supplied revision of the
+// definition of the supplied kind and name, e.g.
+// traitdefinition-scaler-v2.
+func DefinitionRevisionName(kind, name string, revision int64) string {
+	return fmt.Sprintf("%s-%s-v%d", strings.ToLower(kind), name, revision)
+}
+
+// VolumeClaimName returns the name of the PersistentVolumeClaim of the named
+// claim of the named VolumeClaimTrait, which is also the name of the volume
+// the claim is mounted as.
+func VolumeClaimName(traitName, claimName string) string {
+	return fmt.Sprintf("%s-%s", traitName, claimName)
+}
+
+// ignoredWorkloadMetadata are the metadata fields of a component's workloads
+// whose changes don't create a new revision.
+var ignoredWorkloadMetadata = []string{
+	"annotations", "creationTimestamp", "generation", "managedFields", "resourceVersion", "selfLink", "uid",
+}
+
+// componentRevisionContent returns the content of the spec of the supplied
+// component that identifies its revision.
+func componentRevisionContent(c *v1alpha2.Component) []interface{} {
+	spec := c.Spec.DeepCopy()
+	// changing the revision history limit doesn't create a new revision
+	spec.RevisionHistoryLimit = nil
+	content := []interface{}{semanticWorkload(spec.Workload)}
+	for _, aw := range spec.AuxiliaryWorkloads {
+		content = append(content, aw.Name, semanticWorkload(aw.Workload))
+	}
+	spec.Workload = runtime.RawExtension{}
+	spec.AuxiliaryWorkloads = nil
+	return append(content, *spec)
+}
+
+// semanticWorkload decodes the supplied workload and strips the metadata that
+// doesn't change its meaning. Workloads that can't be decoded are returned as
+// is.
+func semanticWorkload(r runtime.RawExtension) interface{} {
+	raw := r.Raw
+	if len(raw) == 0 && r.Object != nil {
+		b, err := json.Marshal(r.Object)
+		if err != nil {
+			return r.Object
+		}
+		raw = b
+	}
+	w := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &w); err != nil {
+		return string(raw)
+	}
+	if md, ok := w["metadata"].(map[string]interface{}); ok {
+		for _, f := range ignoredWorkloadMetadata {
+			delete(md, f)
+		}
+		if len(md) == 0 {
+			delete(w, "metadata")
+		}
+	}
+	return w
+}
+
+// DiffComponentRevisions returns the sorted paths of the fields of the spec
+// that differ between the supplied revisions of a component. Like
+// ComputeComponentRevisionHash only semantic changes are considered.
+// Auxiliary workloads and parameters are keyed by their names, e.g.
+// workload.spec.replicas, auxiliaryWorkloads.config.data or
+// parameters.image.fieldPaths.
+func DiffComponentRevisions(from, to *v1alpha2.Component) []string {
+	paths := diffValues("", componentDiffContent(from), componentDiffContent(to))
+	sort.Strings(paths)
+	return paths
+}
+
+// componentDiffContent returns the content of the spec of the supplied
+// component that is compared by DiffComponentRevisions.
+func componentDiffContent(c *v1alpha2.Component) map[string]interface{} {
+	content := map[string]interface{}{"workload": semanticWorkload(c.Spec.Workload)}
+	if len(c.Spec.AuxiliaryWorkloads) > 0 {
+		aux := make(map[string]interface{}, len(c.Spec.AuxiliaryWorkloads))
+		for _, aw := range c.Spec.AuxiliaryWorkloads {
+			aux[aw.Name] = semanticWorkload(aw.Workload)
+		}
+		content["auxiliaryWorkloads"] = aux
+	}
+	if len(c.Spec.Parameters) > 0 {
+		params := make(map[string]interface{}, len(c.Spec.Parameters))
+		for _, p := range c.Spec.Parameters {
+			m, _ := Object2Map(p)
+			delete(m, "name")
+			params[p.Name] = m
+		}
+		content["parameters"] = params
+	}
+	if c.Spec.Schematic != nil {
+		m, _ := Object2Map(c.Spec.Schematic)
+		content["schematic"] = m
+	}
+	return content
+}
+
+// diffValues returns the paths of the fields that differ between the supplied
+// values, which are decoded JSON.
+func diffValues(path string, from, to interface{}) []string {
+	fm, fok := from.(map[string]interface{})
+	tm, tok := to.(map[string]interface{})
+	if fok && tok {
+		var paths []string
+		for k, v := range fm {
+			paths = append(paths, diffValues(joinFieldPath(path, k), v, tm[k])...)
+		}
+		for k := range tm {
+			if _, ok := fm[k]; !ok {
+				paths = append(paths, joinFieldPath(path, k))
+			}
+		}
+		return paths
+	}
+	fs, fok := from.([]interface{})
+	ts, tok := to.([]interface{})
+	if fok && tok && len(fs) == len(ts) {
+		var paths []string
+		for i := range fs {
+			paths = append(paths, diffValues(fmt.Sprintf("%s[%d]", path, i), fs[i], ts[i])...)
+		}
+		return paths
+	}
+	if !reflect.DeepEqual(from, to) {
+		return []string{path}
+	}
+	return nil
+}
+
+func joinFieldPath(path, key string) string {
+	switch {
+	case path == "":
+		return key
+	case strings.ContainsAny(key, ".[]"):
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	return path + "." + key
+}
+
 // DeepHashObject writes specified object to hash using the spew library
 // which follows pointers and prints actual values of the nested objects
 // ensuring the hash does not change when a pointer changes.
 func DeepHashObject(hasher hash.Hash, objectToWrite interface{}) {
 	hasher.Reset()
-	printer := spew.ConfigState{
-		Indent:         " ",
-		SortKeys:       true,
-		DisableMethods: true,
-		SpewKeys:       true,
-	}
-	_, _ = printer.Fprintf(hasher, "%#v", objectToWrite)
+	_, _ = hashPrinter.Fprintf(hasher, "%#v", objectToWrite)
+}
+
+var hashPrinter = spew.ConfigState{
+	Indent:         " ",
+	SortKeys:       true,
+	DisableMethods: true,
+	SpewKeys:       true,
 }
 
 // GetComponent will get Component and RevisionName by AppConfigComponent
@@ -389,9 +1008,398 @@ func UnpackRevisionData(rev *appsv1.ControllerRevision) (*v1alpha2.Component, er
 	return &comp, err
 }
 
-// AddLabels will merge labels with existing labels
+// AppConfigRevisionNames returns the component revisions the components of
+// the supplied ApplicationConfiguration are pinned to. It is the extractor of
+// the RevisionNameIndex field index.
+func AppConfigRevisionNames(o runtime.Object) []string {
+	ac, ok := o.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, acc := range ac.Spec.Components {
+		if acc.RevisionName != "" {
+			names = append(names, acc.RevisionName)
+		}
+	}
+	return names
+}
+
+// IndexAppConfigsByRevisionName registers the RevisionNameIndex field index,
+// which allows ApplicationConfigurations to be listed by the component
+// revision they are pinned to.
+func IndexAppConfigsByRevisionName(ctx context.Context, i client.FieldIndexer) error {
+	return i.IndexField(ctx, &v1alpha2.ApplicationConfiguration{}, RevisionNameIndex, AppConfigRevisionNames)
+}
+
+// AppConfigComponentNames returns the components whose latest revision the
+// components of the supplied ApplicationConfiguration follow. It is the
+// extractor of the ComponentNameIndex field index.
+func AppConfigComponentNames(o runtime.Object) []string {
+	ac, ok := o.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, acc := range ac.Spec.Components {
+		if acc.ComponentName != "" {
+			names = append(names, acc.ComponentName)
+		}
+	}
+	return names
+}
+
+// IndexAppConfigsByComponentName registers the ComponentNameIndex field
+// index, which allows ApplicationConfigurations to be listed by the component
+// whose latest revision they follow.
+func IndexAppConfigsByComponentName(ctx context.Context, i client.FieldIndexer) error {
+	return i.IndexField(ctx, &v1alpha2.ApplicationConfiguration{}, ComponentNameIndex, AppConfigComponentNames)
+}
+
+// AppConfigConsumedRevisionNames returns the component revisions that the
+// supplied ApplicationConfiguration is pinned to or currently runs. It is the
+// extractor of the ConsumedRevisionNameIndex field index.
+func AppConfigConsumedRevisionNames(o runtime.Object) []string {
+	ac, ok := o.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return nil
+	}
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	for _, name := range AppConfigRevisionNames(ac) {
+		add(name)
+	}
+	// pinned revisions that can't be parsed are never rendered
+	pinned, _ := PinnedRevisionNames(ac)
+	for _, acc := range ac.Spec.Components {
+		add(pinned[acc.ComponentName])
+	}
+	for _, w := range ac.Status.Workloads {
+		add(w.ComponentRevisionName)
+	}
+	return names
+}
+
+// PinnedRevisionNames returns the revisions the components of the supplied
+// ApplicationConfiguration were pinned to at admission, keyed by component
+// name. Components are only pinned if the ApplicationConfiguration has the
+// pin revisions annotation.
+func PinnedRevisionNames(ac *v1alpha2.ApplicationConfiguration) (map[string]string, error) {
+	if ac.GetAnnotations()[oam.AnnotationPinRevisions] != "true" {
+		return nil, nil
+	}
+	raw, ok := ac.GetAnnotations()[oam.AnnotationPinnedRevisions]
+	if !ok {
+		return nil, nil
+	}
+	pinned := map[string]string{}
+	if err := json.Unmarshal([]byte(raw), &pinned); err != nil {
+		return nil, errors.Wrapf(err, errFmtInvalidPinnedRevisions, oam.AnnotationPinnedRevisions)
+	}
+	return pinned, nil
+}
+
+// IndexAppConfigsByConsumedRevisionName registers the
+// ConsumedRevisionNameIndex field index, which allows ApplicationConfigurations
+// to be listed by the component revision they pin or run.
+func IndexAppConfigsByConsumedRevisionName(ctx context.Context, i client.FieldIndexer) error {
+	return i.IndexField(ctx, &v1alpha2.ApplicationConfiguration{}, ConsumedRevisionNameIndex, AppConfigConsumedRevisionNames)
+}
+
+// definitionIndexKey returns the key of the supplied object in the definition
+// field indexes: the definition name of its type label if it has one,
+// otherwise its kind.group.
+func definitionIndexKey(u *unstructured.Unstructured, typeLabel string) string {
+	if name, ok := u.GetLabels()[typeLabel]; ok && typeLabel != "" {
+		return name
+	}
+	return u.GroupVersionKind().GroupKind().String()
+}
+
+// rawDefinitionIndexKey returns the key of the supplied raw object in the
+// definition field indexes, or an empty string if it can't be decoded.
+func rawDefinitionIndexKey(raw runtime.RawExtension, typeLabel string) string {
+	u := &unstructured.Unstructured{}
+	if raw.Object != nil {
+		o, err := Object2Unstructured(raw.Object)
+		if err != nil {
+			return ""
+		}
+		u = o
+	} else if err := json.Unmarshal(raw.Raw, &u.Object); err != nil {
+		return ""
+	}
+	if u.GetKind() == "" {
+		return ""
+	}
+	return definitionIndexKey(u, typeLabel)
+}
+
+// appendUnique appends the supplied key to keys unless it is empty or was
+// already appended.
+func appendUnique(keys []string, key string) []string {
+	if key == "" {
+		return keys
+	}
+	for _, k := range keys {
+		if k == key {
+			return keys
+		}
+	}
+	return append(keys, key)
+}
+
+// DefinitionIndexKeys returns the keys that objects of the definition of the
+// supplied kind and name, which refers to the supplied resource (e.g.
+// deployments.apps), are indexed by in the definition field indexes. Objects
+// are indexed by the kinds of the resource only if objects without a type
+// label resolve to the definition, i.e. if it is the only definition that
+// refers to the resource or is named after it.
+func DefinitionIndexKeys(r client.Reader, dm discoverymapper.DiscoveryMapper, kind, name, resource string) ([]string, error) {
+	keys := []string{name}
+	if name != resource {
+		f, ok := r.(DefinitionFinder)
+		if !ok {
+			return keys, nil
+		}
+		if names := f.FindDefinitions(kind, resource); len(names) != 1 || names[0] != name {
+			return keys, nil
+		}
+	}
+	mapper, err := dm.GetMapper()
+	if err != nil {
+		return nil, err
+	}
+	kinds, err := mapper.KindsFor(schema.ParseGroupResource(resource).WithVersion(""))
+	if meta.IsNoMatchError(err) {
+		// objects of resources that are not served have no definition
+		return keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range kinds {
+		keys = appendUnique(keys, k.GroupKind().String())
+	}
+	return keys, nil
+}
+
+// DefinitionUsers returns the namespaced names of the objects that the
+// supplied definition field index maps any of the supplied keys to, e.g. the
+// keys returned by DefinitionIndexKeys, in the order they are listed. The
+// objects are listed into the lists newList returns.
+func DefinitionUsers(ctx context.Context, r client.Reader, newList func() runtime.Object, index string, keys []string) ([]string, error) {
+	var names []string
+	for _, key := range keys {
+		l := newList()
+		if err := r.List(ctx, l, client.MatchingFields{index: key}); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			o, err := meta.Accessor(item)
+			if err != nil {
+				return nil, err
+			}
+			names = appendUnique(names, o.GetNamespace()+"/"+o.GetName())
+		}
+	}
+	return names, nil
+}
+
+// ComponentWorkloadDefinitions returns the definition index keys of the
+// workload and auxiliary workloads of the supplied Component. It is the
+// extractor of the WorkloadDefinitionIndex field index.
+func ComponentWorkloadDefinitions(o runtime.Object) []string {
+	comp, ok := o.(*v1alpha2.Component)
+	if !ok {
+		return nil
+	}
+	keys := appendUnique(nil, rawDefinitionIndexKey(comp.Spec.Workload, oam.WorkloadTypeLabel))
+	for _, aw := range comp.Spec.AuxiliaryWorkloads {
+		keys = appendUnique(keys, rawDefinitionIndexKey(aw.Workload, oam.WorkloadTypeLabel))
+	}
+	return keys
+}
+
+// IndexComponentsByWorkloadDefinition registers the WorkloadDefinitionIndex
+// field index, which allows Components to be listed by the definition of
+// their workloads.
+func IndexComponentsByWorkloadDefinition(ctx context.Context, i client.FieldIndexer) error {
+	return i.IndexField(ctx, &v1alpha2.Component{}, WorkloadDefinitionIndex, ComponentWorkloadDefinitions)
+}
+
+// AppConfigTraitDefinitions returns the definition index keys of the traits of
+// the supplied ApplicationConfiguration. It is the extractor of the
+// TraitDefinitionIndex field index.
+func AppConfigTraitDefinitions(o runtime.Object) []string {
+	ac, ok := o.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, acc := range ac.Spec.Components {
+		for _, ct := range acc.Traits {
+			keys = appendUnique(keys, rawDefinitionIndexKey(ct.Trait, oam.TraitTypeLabel))
+		}
+	}
+	for _, st := range ac.Spec.ScopeTraits {
+		for _, ct := range st.Traits {
+			keys = appendUnique(keys, rawDefinitionIndexKey(ct.Trait, oam.TraitTypeLabel))
+		}
+	}
+	return keys
+}
+
+// IndexAppConfigsByTraitDefinition registers the TraitDefinitionIndex field
+// index, which allows ApplicationConfigurations to be listed by the
+// definition of their traits.
+func IndexAppConfigsByTraitDefinition(ctx context.Context, i client.FieldIndexer) error {
+	return i.IndexField(ctx, &v1alpha2.ApplicationConfiguration{}, TraitDefinitionIndex, AppConfigTraitDefinitions)
+}
+
+// AppConfigScopeDefinitions returns the definition index keys of the scopes
+// the components of the supplied ApplicationConfiguration are in. It is the
+// extractor of the ScopeDefinitionIndex field index.
+func AppConfigScopeDefinitions(o runtime.Object) []string {
+	ac, ok := o.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, acc := range ac.Spec.Components {
+		for _, s := range acc.Scopes {
+			gk := schema.FromAPIVersionAndKind(s.ScopeReference.APIVersion, s.ScopeReference.Kind).GroupKind()
+			keys = appendUnique(keys, gk.String())
+		}
+	}
+	return keys
+}
+
+// IndexAppConfigsByScopeDefinition registers the ScopeDefinitionIndex field
+// index, which allows ApplicationConfigurations to be listed by the
+// definition of the scopes their components are in.
+func IndexAppConfigsByScopeDefinition(ctx context.Context, i client.FieldIndexer) error {
+	return i.IndexField(ctx, &v1alpha2.ApplicationConfiguration{}, ScopeDefinitionIndex, AppConfigScopeDefinitions)
+}
+
+// WorkloadReferenceIndexKey returns the WorkloadReferenceIndex key of the
+// referenced workload, e.g. "Deployment.apps/example".
+func WorkloadReferenceIndexKey(ref cpv1alpha1.TypedReference) string {
+	return ref.GroupVersionKind().GroupKind().String() + "/" + ref.Name
+}
+
+// ScopeWorkloadReferences returns the WorkloadReferenceIndex keys of the
+// workloads the supplied scope references. It is the extractor of the
+// WorkloadReferenceIndex field index.
+func ScopeWorkloadReferences(o runtime.Object) []string {
+	s, ok := o.(oam.WorkloadsReferencer)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, ref := range s.GetWorkloadReferences() {
+		keys = appendUnique(keys, WorkloadReferenceIndexKey(ref))
+	}
+	return keys
+}
+
+// IndexScopesByWorkloadReference registers the WorkloadReferenceIndex field
+// index for the kind of the supplied scope, which allows scopes of the kind
+// to be listed by the workloads they reference.
+func IndexScopesByWorkloadReference(ctx context.Context, i client.FieldIndexer, s oam.Scope) error {
+	return i.IndexField(ctx, s, WorkloadReferenceIndex, ScopeWorkloadReferences)
+}
+
+// ListScopesByWorkloadReference lists the scopes of the supplied namespace
+// that reference the supplied workload into the supplied list, e.g. a
+// HealthScopeList. The WorkloadReferenceIndex field index must be registered
+// for the kind of the scopes.
+func ListScopesByWorkloadReference(ctx context.Context, r client.Reader, l runtime.Object, namespace string,
+	ref cpv1alpha1.TypedReference) error {
+	return r.List(ctx, l, client.InNamespace(namespace), client.MatchingFields{WorkloadReferenceIndex: WorkloadReferenceIndexKey(ref)})
+}
+
+// ScopeReferenceIndexKey returns the ScopeReferenceIndex key of the referenced
+// scope, e.g. "HealthScope.core.oam.dev/example".
+func ScopeReferenceIndexKey(ref cpv1alpha1.TypedReference) string {
+	return ref.GroupVersionKind().GroupKind().String() + "/" + ref.Name
+}
+
+// AppConfigScopeReferences returns the ScopeReferenceIndex keys of the scopes
+// the components of the supplied ApplicationConfiguration are in, and the
+// scopes its workloads are recorded to be in. It is the extractor of the
+// ScopeReferenceIndex field index.
+func AppConfigScopeReferences(o runtime.Object) []string {
+	ac, ok := o.(*v1alpha2.ApplicationConfiguration)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, acc := range ac.Spec.Components {
+		for _, s := range acc.Scopes {
+			keys = appendUnique(keys, ScopeReferenceIndexKey(s.ScopeReference))
+		}
+	}
+	for _, w := range ac.Status.Workloads {
+		for _, s := range w.Scopes {
+			keys = appendUnique(keys, ScopeReferenceIndexKey(s.Reference))
+		}
+	}
+	return keys
+}
+
+// IndexAppConfigsByScopeReference registers the ScopeReferenceIndex field
+// index, which allows ApplicationConfigurations to be listed by the scopes
+// their workloads are in.
+func IndexAppConfigsByScopeReference(ctx context.Context, i client.FieldIndexer) error {
+	return i.IndexField(ctx, &v1alpha2.ApplicationConfiguration{}, ScopeReferenceIndex, AppConfigScopeReferences)
+}
+
+// WorkloadsInScope returns the references to the workloads of the
+// ApplicationConfigurations of the supplied namespace that are recorded to be
+// in the referenced scope, in the order they are listed. The
+// ScopeReferenceIndex field index must be registered.
+func WorkloadsInScope(ctx context.Context, r client.Reader, namespace string, scope cpv1alpha1.TypedReference) ([]cpv1alpha1.TypedReference, error) {
+	l := &v1alpha2.ApplicationConfigurationList{}
+	key := ScopeReferenceIndexKey(scope)
+	if err := r.List(ctx, l, client.InNamespace(namespace), client.MatchingFields{ScopeReferenceIndex: key}); err != nil {
+		return nil, err
+	}
+	var refs []cpv1alpha1.TypedReference
+	seen := map[string]bool{}
+	for _, ac := range l.Items {
+		for _, w := range ac.Status.Workloads {
+			for _, s := range w.Scopes {
+				if ScopeReferenceIndexKey(s.Reference) != key || seen[WorkloadReferenceIndexKey(w.Reference)] {
+					continue
+				}
+				seen[WorkloadReferenceIndexKey(w.Reference)] = true
+				refs = append(refs, w.Reference)
+			}
+		}
+	}
+	return refs, nil
+}
+
+// AddLabels will merge labels with existing labels. The supplied labels take
+// precedence and are never modified.
 func AddLabels(o *unstructured.Unstructured, labels map[string]string) {
-	o.SetLabels(MergeMap(o.GetLabels(), labels))
+	newLabels := make(map[string]string, len(labels))
+	for k, v := range labels {
+		newLabels[k] = v
+	}
+	o.SetLabels(MergeMap(o.GetLabels(), newLabels))
 }
 
 // MergeMap merges two could be nil maps
@@ -6,18 +6,29 @@ import (
 	"fmt"
 	"hash/adler32"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -247,6 +258,88 @@ func TestFetchWorkloadTraitReference(t *testing.T) {
 	}
 }
 
+func TestFetchTraitWorkload(t *testing.T) {
+	ctx := context.Background()
+	log := ctrl.Log.WithName("TestFetchTraitWorkload")
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = mock.NewMockRESTMapping("containerizedworkloads")
+	trait := &v1alpha2.VolumeClaimTrait{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "data"},
+		Spec: v1alpha2.VolumeClaimTraitSpec{
+			WorkloadReference: v1alpha1.TypedReference{
+				APIVersion: v1alpha2.SchemeGroupVersion.String(),
+				Kind:       v1alpha2.ContainerizedWorkloadKind,
+				Name:       "web",
+			},
+		},
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion(v1alpha2.SchemeGroupVersion.String())
+	workload.SetKind(v1alpha2.ContainerizedWorkloadKind)
+	workload.SetNamespace("ns")
+	workload.SetName("web")
+	wd := v1alpha2.WorkloadDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "containerizedworkloads.core.oam.dev"},
+		Spec:       v1alpha2.WorkloadDefinitionSpec{PodSpecPath: "spec.template.spec"},
+	}
+	boom := errors.New("boom")
+	get := func(workloadErr, definitionErr error) test.MockGetFn {
+		return func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *unstructured.Unstructured:
+				if workloadErr == nil {
+					*o = *workload.DeepCopy()
+				}
+				return workloadErr
+			case *v1alpha2.WorkloadDefinition:
+				if definitionErr == nil {
+					*o = wd
+				}
+				return definitionErr
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		get    test.MockGetFn
+		wantWL *unstructured.Unstructured
+		wantWD *v1alpha2.WorkloadDefinition
+		errMsg string
+	}{
+		"workload and definition found": {
+			get:    get(nil, nil),
+			wantWL: workload,
+			wantWD: &wd,
+		},
+		"workload without definition gets a dummy one": {
+			get:    get(nil, kerrors.NewNotFound(schema.GroupResource{}, "containerizedworkloads.core.oam.dev")),
+			wantWL: workload,
+			wantWD: util.GetDummyWorkloadDefinition(workload),
+		},
+		"workload cannot be fetched": {
+			get:    get(boom, nil),
+			errMsg: errors.Wrap(boom, util.ErrLocateWorkload).Error(),
+		},
+		"definition cannot be fetched": {
+			get:    get(nil, boom),
+			errMsg: errors.Wrap(boom, util.ErrLocateWorkloadDefinition).Error(),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotWL, gotWD, err := util.FetchTraitWorkload(ctx, &test.MockClient{MockGet: tc.get}, dm, log, trait)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errMsg)
+			}
+			assert.Equal(t, tc.wantWL, gotWL)
+			assert.Equal(t, tc.wantWD, gotWD)
+		})
+	}
+}
+
 func TestScopeRelatedUtils(t *testing.T) {
 
 	ctx := context.Background()
@@ -563,7 +656,6 @@ func TestChildResources(t *testing.T) {
 		},
 	}
 
-	log := ctrl.Log.WithName("ManualScalarTraitReconciler")
 	crkl := []v1alpha2.ChildResourceKind{
 		{
 			Kind:       "Deployment",
@@ -603,9 +695,11 @@ func TestChildResources(t *testing.T) {
 		l.Items = []unstructured.Unstructured{*u}
 		return nil
 	}
+	withChildren := workloadDefinition
+	withChildren.Spec.ChildResourceKinds = crkl
 	type fields struct {
-		getFunc  test.ObjectFn
-		listFunc test.ObjectFn
+		definition *v1alpha2.WorkloadDefinition
+		listFunc   test.ObjectFn
 	}
 	type want struct {
 		crks []*unstructured.Unstructured
@@ -616,25 +710,20 @@ func TestChildResources(t *testing.T) {
 		fields fields
 		want   want
 	}{
-		"FetchWorkloadChildResources fail when getWorkloadDefinition fails": {
+		"FetchWorkloadChildResources fail when listing child resources fails": {
 			fields: fields{
-				getFunc: func(obj runtime.Object) error {
+				definition: &withChildren,
+				listFunc: func(runtime.Object) error {
 					return getErr
 				},
-				listFunc: nilListFunc,
 			},
 			want: want{
 				crks: nil,
 				err:  getErr,
 			},
 		},
-		"FetchWorkloadChildResources return nothing when the workloadDefinition doesn't have child list": {
+		"FetchWorkloadChildResources return nothing when the workload has no definition": {
 			fields: fields{
-				getFunc: func(obj runtime.Object) error {
-					o, _ := obj.(*v1alpha2.WorkloadDefinition)
-					*o = workloadDefinition
-					return nil
-				},
 				listFunc: nilListFunc,
 			},
 			want: want{
@@ -642,15 +731,19 @@ func TestChildResources(t *testing.T) {
 				err:  nil,
 			},
 		},
+		"FetchWorkloadChildResources return nothing when the workloadDefinition doesn't have child list": {
+			fields: fields{
+				definition: &workloadDefinition,
+				listFunc:   nilListFunc,
+			},
+			want: want{
+				crks: nil,
+				err:  nil,
+			},
+		},
 		"FetchWorkloadChildResources Success": {
 			fields: fields{
-				getFunc: func(obj runtime.Object) error {
-					o, _ := obj.(*v1alpha2.WorkloadDefinition)
-					w := workloadDefinition
-					w.Spec.ChildResourceKinds = crkl
-					*o = w
-					return nil
-				},
+				definition: &withChildren,
 				listFunc: func(o runtime.Object) error {
 					l := o.(*unstructured.UnstructuredList)
 					switch l.GetKind() {
@@ -673,13 +766,7 @@ func TestChildResources(t *testing.T) {
 		},
 		"FetchWorkloadChildResources with many resources only pick the child one": {
 			fields: fields{
-				getFunc: func(obj runtime.Object) error {
-					o, _ := obj.(*v1alpha2.WorkloadDefinition)
-					w := workloadDefinition
-					w.Spec.ChildResourceKinds = crkl
-					*o = w
-					return nil
-				},
+				definition: &withChildren,
 				listFunc: func(o runtime.Object) error {
 					l := o.(*unstructured.UnstructuredList)
 					l.Items = []unstructured.Unstructured{oResource, oResource, oResource, oResource,
@@ -702,16 +789,108 @@ func TestChildResources(t *testing.T) {
 	}
 	for name, tc := range cases {
 		tclient := test.MockClient{
-			MockGet:  test.NewMockGetFn(nil, tc.fields.getFunc),
 			MockList: test.NewMockListFn(nil, tc.fields.listFunc),
 		}
-		got, err := util.FetchWorkloadChildResources(ctx, log, &tclient, mock.NewMockDiscoveryMapper(), unstructuredWorkload)
+		got, err := util.FetchWorkloadChildResources(ctx, &tclient, unstructuredWorkload, tc.fields.definition)
 		t.Log(fmt.Sprint("Running test: ", name))
 		assert.Equal(t, tc.want.err, err)
 		assert.Equal(t, tc.want.crks, got)
 	}
 }
 
+func TestPavePodSpec(t *testing.T) {
+	tests := map[string]struct {
+		workload *unstructured.Unstructured
+		path     string
+		set      bool
+		exp      map[string]interface{}
+		wantErr  bool
+	}{
+		"existing pod spec": {
+			workload: &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{"hostNetwork": false}}},
+			}},
+			path: "spec.template.spec",
+			set:  true,
+			exp: map[string]interface{}{
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{"hostNetwork": true}}},
+			},
+		},
+		"missing pod spec": {
+			workload: &unstructured.Unstructured{},
+			path:     "spec.podSpec",
+			set:      true,
+			exp:      map[string]interface{}{"spec": map[string]interface{}{"podSpec": map[string]interface{}{"hostNetwork": true}}},
+		},
+		"pod spec not an object": {
+			workload: &unstructured.Unstructured{Object: map[string]interface{}{"spec": "web"}},
+			path:     "spec",
+			exp:      map[string]interface{}{"spec": "web"},
+			wantErr:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			podSpec, err := util.PavePodSpec(tc.workload, tc.path)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.set {
+				assert.NoError(t, podSpec.SetBool("hostNetwork", true))
+			}
+			assert.Equal(t, tc.exp, tc.workload.Object)
+		})
+	}
+}
+
+func TestFetchWorkloadPodSpec(t *testing.T) {
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = mock.NewMockRESTMapping("deployments")
+	deploy := func() *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		return u
+	}
+	getDefinition := func(podSpecPath string) test.MockGetFn {
+		return func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+			obj.(*v1alpha2.WorkloadDefinition).Spec.PodSpecPath = podSpecPath
+			return nil
+		}
+	}
+
+	tests := map[string]struct {
+		get     test.MockGetFn
+		exp     bool
+		wantErr bool
+	}{
+		"definition with podSpecPath": {
+			get: getDefinition("spec.template.spec"),
+			exp: true,
+		},
+		"definition without podSpecPath": {
+			get: getDefinition(""),
+		},
+		"no definition": {
+			get: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "deployments.apps")),
+		},
+		"cannot get definition": {
+			get:     test.NewMockGetFn(errors.New("boom")),
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := deploy()
+			podSpec, err := util.FetchWorkloadPodSpec(context.Background(), &test.MockClient{MockGet: tc.get}, dm, w)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.exp, podSpec != nil)
+			if podSpec != nil {
+				_, err := fieldpath.Pave(w.Object).GetValue("spec.template.spec")
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestUnstructured(t *testing.T) {
 	tests := map[string]struct {
 		u         *unstructured.Unstructured
@@ -841,6 +1020,11 @@ func TestComputeHash(t *testing.T) {
 			},
 			exp: "5ddc8b7556",
 		},
+		{
+			name:     "auxiliary workload",
+			template: &v1alpha2.ComponentTrait{WorkloadName: "config"},
+			exp:      "844c7c7f4b",
+		},
 	}
 	for _, test := range test {
 		got := util.ComputeHash(test.template)
@@ -850,6 +1034,111 @@ func TestComputeHash(t *testing.T) {
 	}
 }
 
+func TestComputeComponentRevisionHash(t *testing.T) {
+	comp := func(image string) *v1alpha2.Component {
+		return &v1alpha2.Component{
+			ObjectMeta: metav1.ObjectMeta{Name: "comp"},
+			Spec:       v1alpha2.ComponentSpec{Workload: runtime.RawExtension{Raw: []byte(`{"image":"` + image + `"}`)}},
+		}
+	}
+	renamed := comp("nginx")
+	renamed.SetName("renamed")
+
+	assert.Equal(t, util.ComputeComponentRevisionHash(comp("nginx")), util.ComputeComponentRevisionHash(renamed),
+		"hash should only depend on the component spec")
+	assert.NotEqual(t, util.ComputeComponentRevisionHash(comp("nginx")), util.ComputeComponentRevisionHash(comp("httpd")),
+		"components with different specs should have different hashes")
+
+	limit := int32(5)
+	limited := comp("nginx")
+	limited.Spec.RevisionHistoryLimit = &limit
+	assert.Equal(t, util.ComputeComponentRevisionHash(comp("nginx")), util.ComputeComponentRevisionHash(limited),
+		"changing the revision history limit should not change the hash")
+	assert.Equal(t, &limit, limited.Spec.RevisionHistoryLimit, "the component must not be modified")
+
+	workload := func(raw string) *v1alpha2.Component {
+		return &v1alpha2.Component{Spec: v1alpha2.ComponentSpec{Workload: runtime.RawExtension{Raw: []byte(raw)}}}
+	}
+	base := util.ComputeComponentRevisionHash(workload(`{"kind":"Deployment","metadata":{"labels":{"app":"web"}},"spec":{"replicas":1}}`))
+	assert.Equal(t, base, util.ComputeComponentRevisionHash(workload(
+		`{"spec": {"replicas": 1}, "metadata": {"labels": {"app": "web"}, "annotations": {"foo": "bar"}, "resourceVersion": "2"}, "kind": "Deployment"}`)),
+		"formatting and metadata churn should not change the hash")
+	assert.NotEqual(t, base, util.ComputeComponentRevisionHash(workload(`{"kind":"Deployment","metadata":{"labels":{"app":"api"}},"spec":{"replicas":1}}`)),
+		"changing workload labels should change the hash")
+	assert.Equal(t, util.ComputeComponentRevisionHash(workload(`{"kind":"Deployment"}`)),
+		util.ComputeComponentRevisionHash(&v1alpha2.Component{Spec: v1alpha2.ComponentSpec{Workload: runtime.RawExtension{
+			Object: &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Deployment"}}}}}),
+		"a workload object should hash the same as its raw JSON")
+
+	withAux := func(raw string) *v1alpha2.Component {
+		c := workload(`{"kind":"Deployment"}`)
+		c.Spec.AuxiliaryWorkloads = []v1alpha2.AuxiliaryWorkload{{Name: "svc", Workload: runtime.RawExtension{Raw: []byte(raw)}}}
+		return c
+	}
+	assert.NotEqual(t, util.ComputeComponentRevisionHash(withAux(`{"kind":"Service"}`)),
+		util.ComputeComponentRevisionHash(withAux(`{"kind":"Ingress"}`)),
+		"changing an auxiliary workload should change the hash")
+	assert.Equal(t, util.ComputeComponentRevisionHash(withAux(`{"kind":"Service"}`)),
+		util.ComputeComponentRevisionHash(withAux(`{"kind":"Service","metadata":{"annotations":{"foo":"bar"}}}`)),
+		"metadata churn of an auxiliary workload should not change the hash")
+}
+
+func TestDiffComponentRevisions(t *testing.T) {
+	comp := func(workload string, params ...v1alpha2.ComponentParameter) *v1alpha2.Component {
+		return &v1alpha2.Component{Spec: v1alpha2.ComponentSpec{
+			Workload:   runtime.RawExtension{Raw: []byte(workload)},
+			Parameters: params,
+		}}
+	}
+	image := v1alpha2.ComponentParameter{Name: "image", FieldPaths: []string{"spec.image"}}
+	tests := map[string]struct {
+		from   *v1alpha2.Component
+		to     *v1alpha2.Component
+		exp    []string
+		reason string
+	}{
+		"no change": {
+			from:   comp(`{"kind":"Foo","spec":{"image":"nginx"}}`, image),
+			to:     comp(`{"spec": {"image": "nginx"}, "kind": "Foo", "metadata": {"annotations": {"foo": "bar"}}}`, image),
+			reason: "formatting and metadata churn should not be reported",
+		},
+		"workload changes": {
+			from: comp(`{"kind":"Foo","spec":{"image":"nginx","ports":[80],"debug":true}}`),
+			to:   comp(`{"kind":"Foo","spec":{"image":"httpd","ports":[80,443],"replicas":2}}`),
+			exp: []string{"workload.spec.debug", "workload.spec.image", "workload.spec.ports",
+				"workload.spec.replicas"},
+			reason: "changed, removed and added workload fields should be reported",
+		},
+		"parameter changes": {
+			from: comp(`{"kind":"Foo"}`, image, v1alpha2.ComponentParameter{Name: "port"}),
+			to: comp(`{"kind":"Foo"}`, v1alpha2.ComponentParameter{Name: "image", FieldPaths: []string{"spec.containers[0].image"}},
+				v1alpha2.ComponentParameter{Name: "replicas"}),
+			exp:    []string{"parameters.image.fieldPaths[0]", "parameters.port", "parameters.replicas"},
+			reason: "parameters should be keyed by their names",
+		},
+		"auxiliary workload changes": {
+			from: comp(`{"kind":"Foo"}`),
+			to: func() *v1alpha2.Component {
+				c := comp(`{"kind":"Foo"}`)
+				c.Spec.AuxiliaryWorkloads = []v1alpha2.AuxiliaryWorkload{{Name: "config.v1", Workload: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap"}`)}}}
+				return c
+			}(),
+			exp:    []string{"auxiliaryWorkloads"},
+			reason: "added auxiliary workloads should be reported",
+		},
+	}
+	for name, ti := range tests {
+		t.Log("Running: " + name)
+		assert.Equal(t, ti.exp, util.DiffComponentRevisions(ti.from, ti.to), ti.reason)
+	}
+	from := comp(`{"kind":"Foo"}`)
+	from.Spec.AuxiliaryWorkloads = []v1alpha2.AuxiliaryWorkload{{Name: "config.v1", Workload: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap"}`)}}}
+	to := from.DeepCopy()
+	to.Spec.AuxiliaryWorkloads[0].Workload.Raw = []byte(`{"kind":"Secret"}`)
+	assert.Equal(t, []string{"auxiliaryWorkloads[config.v1].kind"}, util.DiffComponentRevisions(from, to),
+		"auxiliary workloads should be keyed by their names")
+}
+
 func TestDeepHashObject(t *testing.T) {
 	successCases := []func() interface{}{
 		func() interface{} { return 8675309 },
@@ -1082,6 +1371,836 @@ func TestUnpackRevisionData(t *testing.T) {
 	}
 }
 
+func TestAppConfigRevisionNames(t *testing.T) {
+	tests := map[string]struct {
+		obj    runtime.Object
+		exp    []string
+		reason string
+	}{
+		"not an ApplicationConfiguration": {
+			obj:    &v1alpha2.Component{},
+			reason: "objects other than ApplicationConfigurations should not be indexed",
+		},
+		"no pinned revisions": {
+			obj: &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "comp1"}},
+			}},
+			reason: "components that follow the latest revision should not be indexed",
+		},
+		"pinned revisions": {
+			obj: &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{
+					{RevisionName: "comp1-v1"},
+					{ComponentName: "comp2"},
+					{RevisionName: "comp3-v2"},
+				},
+			}},
+			exp:    []string{"comp1-v1", "comp3-v2"},
+			reason: "every pinned revision should be indexed",
+		},
+	}
+	for name, ti := range tests {
+		t.Log("Running: " + name)
+		assert.Equal(t, ti.exp, util.AppConfigRevisionNames(ti.obj), ti.reason)
+	}
+}
+
+func TestAppConfigComponentNames(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{
+		Components: []v1alpha2.ApplicationConfigurationComponent{
+			{ComponentName: "comp1"},
+			{RevisionName: "comp2-v1"},
+			{ComponentName: "comp3"},
+		},
+	}}
+	assert.Equal(t, []string{"comp1", "comp3"}, util.AppConfigComponentNames(ac),
+		"only components that follow the latest revision should be indexed")
+	assert.Nil(t, util.AppConfigComponentNames(&v1alpha2.Component{}),
+		"objects other than ApplicationConfigurations should not be indexed")
+}
+
+func TestDefinitionIndexes(t *testing.T) {
+	raw := func(apiVersion, kind, typeLabel, typeName string) runtime.RawExtension {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion(apiVersion)
+		u.SetKind(kind)
+		if typeLabel != "" {
+			u.SetLabels(map[string]string{typeLabel: typeName})
+		}
+		b, _ := json.Marshal(u)
+		return runtime.RawExtension{Raw: b}
+	}
+	comp := &v1alpha2.Component{Spec: v1alpha2.ComponentSpec{
+		Workload: raw("apps/v1", "Deployment", "", ""),
+		AuxiliaryWorkloads: []v1alpha2.AuxiliaryWorkload{
+			{Name: "svc", Workload: raw("v1", "Service", oam.WorkloadTypeLabel, "service")},
+			{Name: "web", Workload: raw("apps/v1", "Deployment", "", "")},
+		},
+	}}
+	assert.Equal(t, []string{"Deployment.apps", "service"}, util.ComponentWorkloadDefinitions(comp),
+		"workloads should be indexed by their type label or kind, once")
+	assert.Nil(t, util.ComponentWorkloadDefinitions(&v1alpha2.ApplicationConfiguration{}),
+		"objects other than Components should not be indexed")
+
+	ac := &v1alpha2.ApplicationConfiguration{Spec: v1alpha2.ApplicationConfigurationSpec{
+		Components: []v1alpha2.ApplicationConfigurationComponent{
+			{
+				ComponentName: "comp1",
+				Traits: []v1alpha2.ComponentTrait{
+					{Trait: raw("core.oam.dev/v1alpha2", "ManualScalerTrait", "", "")},
+					{Trait: raw("example.com/v1", "Route", oam.TraitTypeLabel, "route")},
+				},
+				Scopes: []v1alpha2.ComponentScope{{ScopeReference: v1alpha1.TypedReference{
+					APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "health"}}},
+			},
+			{
+				ComponentName: "comp2",
+				Traits:        []v1alpha2.ComponentTrait{{Trait: runtime.RawExtension{Raw: []byte("{")}}},
+			},
+		},
+	}}
+	assert.Equal(t, []string{"ManualScalerTrait.core.oam.dev", "route"}, util.AppConfigTraitDefinitions(ac),
+		"traits should be indexed by their type label or kind, and undecodable traits skipped")
+	assert.Equal(t, []string{"HealthScope.core.oam.dev"}, util.AppConfigScopeDefinitions(ac),
+		"scopes should be indexed by their kind")
+	assert.Nil(t, util.AppConfigTraitDefinitions(comp), "objects other than ApplicationConfigurations should not be indexed")
+	assert.Nil(t, util.AppConfigScopeDefinitions(comp), "objects other than ApplicationConfigurations should not be indexed")
+}
+
+func TestScopeWorkloadReferences(t *testing.T) {
+	hs := &v1alpha2.HealthScope{Spec: v1alpha2.HealthScopeSpec{WorkloadReferences: []v1alpha1.TypedReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		{APIVersion: "core.oam.dev/v1alpha2", Kind: "ContainerizedWorkload", Name: "web"},
+		{APIVersion: "apps/v1beta1", Kind: "Deployment", Name: "web"},
+	}}}
+	assert.Equal(t, []string{"Deployment.apps/web", "ContainerizedWorkload.core.oam.dev/web"}, util.ScopeWorkloadReferences(hs),
+		"workloads should be indexed by their kind and name regardless of version")
+	assert.Nil(t, util.ScopeWorkloadReferences(&v1alpha2.HealthScope{}), "scopes without workloads should not be indexed")
+	assert.Nil(t, util.ScopeWorkloadReferences(&v1alpha2.Component{}), "objects other than scopes should not be indexed")
+}
+
+func TestAppConfigScopeReferences(t *testing.T) {
+	hs := v1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "app"}
+	ns := v1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "NetworkScope", Name: "app"}
+	ac := &v1alpha2.ApplicationConfiguration{
+		Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{
+			{ComponentName: "web", Scopes: []v1alpha2.ComponentScope{{ScopeReference: hs}}},
+			{ComponentName: "db", Scopes: []v1alpha2.ComponentScope{{ScopeReference: hs}}},
+		}},
+		Status: v1alpha2.ApplicationConfigurationStatus{Workloads: []v1alpha2.WorkloadStatus{
+			{ComponentName: "web", Scopes: []v1alpha2.WorkloadScope{{Reference: hs}, {Reference: ns}}},
+		}},
+	}
+	assert.Equal(t, []string{"HealthScope.core.oam.dev/app", "NetworkScope.core.oam.dev/app"}, util.AppConfigScopeReferences(ac),
+		"application configurations should be indexed by the scopes in their spec and status")
+	assert.Nil(t, util.AppConfigScopeReferences(&v1alpha2.ApplicationConfiguration{}), "application configurations without scopes should not be indexed")
+	assert.Nil(t, util.AppConfigScopeReferences(&v1alpha2.Component{}), "objects other than application configurations should not be indexed")
+}
+
+func TestWorkloadsInScope(t *testing.T) {
+	hs := v1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "app"}
+	other := v1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "other"}
+	web := v1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+	db := v1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "db"}
+	errBoom := errors.New("boom")
+
+	tests := map[string]struct {
+		list    func(obj runtime.Object, opts ...client.ListOption) error
+		exp     []v1alpha1.TypedReference
+		wantErr error
+	}{
+		"workloads in scope": {
+			list: func(obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				if lo.Namespace != "ns" || lo.FieldSelector.String() != util.ScopeReferenceIndex+"=HealthScope.core.oam.dev/app" {
+					return errBoom
+				}
+				obj.(*v1alpha2.ApplicationConfigurationList).Items = []v1alpha2.ApplicationConfiguration{
+					{Status: v1alpha2.ApplicationConfigurationStatus{Workloads: []v1alpha2.WorkloadStatus{
+						{Reference: web, Scopes: []v1alpha2.WorkloadScope{{Reference: other}, {Reference: hs}}},
+						{Reference: db, Scopes: []v1alpha2.WorkloadScope{{Reference: other}}},
+					}}},
+					{Status: v1alpha2.ApplicationConfigurationStatus{Workloads: []v1alpha2.WorkloadStatus{
+						{Reference: db, Scopes: []v1alpha2.WorkloadScope{{Reference: hs}}},
+						{Reference: web, Scopes: []v1alpha2.WorkloadScope{{Reference: hs}}},
+					}}},
+				}
+				return nil
+			},
+			exp: []v1alpha1.TypedReference{web, db},
+		},
+		"list error": {
+			list:    func(runtime.Object, ...client.ListOption) error { return errBoom },
+			wantErr: errBoom,
+		},
+	}
+	for name, tc := range tests {
+		c := &test.MockClient{MockList: func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			return tc.list(obj, opts...)
+		}}
+		got, err := util.WorkloadsInScope(context.Background(), c, "ns", hs)
+		assert.Equal(t, tc.wantErr, err, name)
+		assert.Equal(t, tc.exp, got, name)
+	}
+}
+
+func TestDefinitionIndexKeys(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockGetMapper = func() (meta.RESTMapper, error) { return restMapper, nil }
+
+	tests := map[string]struct {
+		r        client.Reader
+		name     string
+		resource string
+		exp      []string
+	}{
+		"named after its resource": {
+			r:        &test.MockClient{},
+			name:     "deployments.apps",
+			resource: "deployments.apps",
+			exp:      []string{"deployments.apps", "Deployment.apps"},
+		},
+		"not named after its resource": {
+			r:        &test.MockClient{},
+			name:     "webservice",
+			resource: "deployments.apps",
+			exp:      []string{"webservice"},
+		},
+		"the only definition of its resource": {
+			r:        &definitionFinder{"deployments.apps": {"webservice"}},
+			name:     "webservice",
+			resource: "deployments.apps",
+			exp:      []string{"webservice", "Deployment.apps"},
+		},
+		"one of several definitions of its resource": {
+			r:        &definitionFinder{"deployments.apps": {"webservice", "worker"}},
+			name:     "webservice",
+			resource: "deployments.apps",
+			exp:      []string{"webservice"},
+		},
+		"resource not served": {
+			r:        &test.MockClient{},
+			name:     "foos.example.com",
+			resource: "foos.example.com",
+			exp:      []string{"foos.example.com"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			keys, err := util.DefinitionIndexKeys(tc.r, dm, v1alpha2.WorkloadDefinitionKind, tc.name, tc.resource)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.exp, keys)
+		})
+	}
+}
+
+// A definitionFinder finds the definitions of any kind by the resource they
+// refer to.
+type definitionFinder map[string][]string
+
+func (f definitionFinder) FindDefinitions(_, resource string) []string {
+	return f[resource]
+}
+
+func (f definitionFinder) Get(context.Context, client.ObjectKey, runtime.Object) error {
+	return nil
+}
+
+func (f definitionFinder) List(context.Context, runtime.Object, ...client.ListOption) error {
+	return nil
+}
+
+func TestResolveDefinitionName(t *testing.T) {
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = mock.NewMockRESTMapping("deployments")
+	deploy := func(labels map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		u.SetLabels(labels)
+		return u
+	}
+
+	tests := map[string]struct {
+		r       client.Reader
+		u       *unstructured.Unstructured
+		exp     string
+		wantErr bool
+	}{
+		"type label": {
+			r:   &definitionFinder{"deployments.apps": {"worker"}},
+			u:   deploy(map[string]string{oam.WorkloadTypeLabel: "webservice"}),
+			exp: "webservice",
+		},
+		"named after resource without finder": {
+			r:   &test.MockClient{},
+			u:   deploy(nil),
+			exp: "deployments.apps",
+		},
+		"named after resource without definitions": {
+			r:   &definitionFinder{},
+			u:   deploy(nil),
+			exp: "deployments.apps",
+		},
+		"only definition of resource": {
+			r:   &definitionFinder{"deployments.apps": {"webservice"}},
+			u:   deploy(nil),
+			exp: "webservice",
+		},
+		"definition named after resource among several": {
+			r:   &definitionFinder{"deployments.apps": {"deployments.apps", "webservice"}},
+			u:   deploy(nil),
+			exp: "deployments.apps",
+		},
+		"ambiguous definitions": {
+			r:       &definitionFinder{"deployments.apps": {"webservice", "worker"}},
+			u:       deploy(nil),
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := util.ResolveDefinitionName(tc.r, dm, v1alpha2.WorkloadDefinitionKind, tc.u)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.exp, got)
+		})
+	}
+}
+
+// namespacedDefinitions is a reader of cluster scoped TraitDefinitions that
+// refer to the resource of their name, and of the TraitDefinitions registered
+// in namespaces, keyed by namespace and name.
+type namespacedDefinitions map[string]map[string]string
+
+func (d namespacedDefinitions) GetNamespacedDefinition(_ context.Context, namespace, name string, obj runtime.Object) error {
+	ref, ok := d[namespace][name]
+	if !ok {
+		return kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "traitdefinitions"}, name)
+	}
+	td := obj.(*v1alpha2.TraitDefinition)
+	td.SetName(name)
+	td.SetNamespace(namespace)
+	td.Spec.Reference.Name = ref
+	return nil
+}
+
+func (d namespacedDefinitions) Get(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+	td := obj.(*v1alpha2.TraitDefinition)
+	td.SetName(key.Name)
+	td.Spec.Reference.Name = key.Name
+	return nil
+}
+
+func (d namespacedDefinitions) List(context.Context, runtime.Object, ...client.ListOption) error {
+	return nil
+}
+
+func TestFetchNamespacedTraitDefinition(t *testing.T) {
+	dm := mock.NewMockDiscoveryMapper()
+	r := namespacedDefinitions{"team": {"scaler": "autoscalers.example.com"}}
+	trait := func(namespace, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("core.oam.dev/v1alpha2")
+		u.SetKind("ManualScalerTrait")
+		u.SetNamespace(namespace)
+		u.SetLabels(map[string]string{oam.TraitTypeLabel: name})
+		return u
+	}
+
+	tests := map[string]struct {
+		r             client.Reader
+		trait         *unstructured.Unstructured
+		wantNamespace string
+		wantRef       string
+	}{
+		"registered in namespace": {
+			r:             r,
+			trait:         trait("team", "scaler"),
+			wantNamespace: "team",
+			wantRef:       "autoscalers.example.com",
+		},
+		"not registered in namespace": {
+			r:       r,
+			trait:   trait("team", "route"),
+			wantRef: "route",
+		},
+		"registered in other namespace": {
+			r:       r,
+			trait:   trait("other", "scaler"),
+			wantRef: "scaler",
+		},
+		"without namespace": {
+			r:       r,
+			trait:   trait("", "scaler"),
+			wantRef: "scaler",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			td, err := util.FetchTraitDefinition(context.Background(), tc.r, dm, tc.trait)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantNamespace, td.GetNamespace())
+			assert.Equal(t, tc.wantRef, td.Spec.Reference.Name)
+		})
+	}
+}
+
+func TestFetchDefinition(t *testing.T) {
+	errBoom := errors.New("boom")
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = mock.NewMockRESTMapping("foos")
+	// definitions returns a reader of the definitions of the supplied kinds
+	definitions := func(kinds ...string) client.Reader {
+		return &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+			kind := reflect.TypeOf(obj).Elem().Name()
+			for _, k := range kinds {
+				if k == kind {
+					obj.(metav1.Object).SetName(key.Name)
+					return nil
+				}
+			}
+			return kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: strings.ToLower(kind) + "s"}, key.Name)
+		}}
+	}
+	resource := func(labels map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("example.com/v1")
+		u.SetKind("Foo")
+		u.SetLabels(labels)
+		return u
+	}
+
+	tests := map[string]struct {
+		r        client.Reader
+		u        *unstructured.Unstructured
+		wantKind string
+		wantName string
+		wantErr  error
+	}{
+		"workload type label": {
+			r:        definitions(v1alpha2.WorkloadDefinitionKind, v1alpha2.TraitDefinitionKind),
+			u:        resource(map[string]string{oam.WorkloadTypeLabel: "webservice"}),
+			wantKind: v1alpha2.WorkloadDefinitionKind,
+			wantName: "webservice",
+		},
+		"trait resource type label": {
+			r:        definitions(v1alpha2.WorkloadDefinitionKind, v1alpha2.TraitDefinitionKind),
+			u:        resource(map[string]string{oam.LabelOAMResourceType: oam.ResourceTypeTrait}),
+			wantKind: v1alpha2.TraitDefinitionKind,
+			wantName: "foos.example.com",
+		},
+		"first kind with a definition": {
+			r:        definitions(v1alpha2.ScopeDefinitionKind),
+			u:        resource(nil),
+			wantKind: v1alpha2.ScopeDefinitionKind,
+			wantName: "foos.example.com",
+		},
+		"labeled kind without a definition": {
+			r:       definitions(v1alpha2.TraitDefinitionKind),
+			u:       resource(map[string]string{oam.LabelOAMResourceType: oam.ResourceTypeWorkload}),
+			wantErr: kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "definitions"}, "foos.example.com"),
+		},
+		"no definition": {
+			r:       definitions(),
+			u:       resource(nil),
+			wantErr: kerrors.NewNotFound(schema.GroupResource{Group: v1alpha2.Group, Resource: "definitions"}, "foos.example.com"),
+		},
+		"get error": {
+			r:       &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			u:       resource(nil),
+			wantErr: errBoom,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			def, err := util.FetchDefinition(context.Background(), tc.r, dm, tc.u)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+				assert.Nil(t, def)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantKind, reflect.TypeOf(def).Elem().Name())
+			assert.Equal(t, tc.wantName, def.GetName())
+		})
+	}
+}
+
+func TestDefinitionVersion(t *testing.T) {
+	crd := func(versions ...crdv1.CustomResourceDefinitionVersion) *crdv1.CustomResourceDefinition {
+		return &crdv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"},
+			Spec:       crdv1.CustomResourceDefinitionSpec{Versions: versions},
+		}
+	}
+	alpha := crdv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true}
+	beta := crdv1.CustomResourceDefinitionVersion{Name: "v1beta1", Served: true, Storage: true}
+	unserved := crdv1.CustomResourceDefinitionVersion{Name: "v1"}
+
+	tests := map[string]struct {
+		crd     *crdv1.CustomResourceDefinition
+		version string
+		exp     string
+		wantErr bool
+	}{
+		"explicit served version": {
+			crd:     crd(alpha, beta),
+			version: "v1alpha1",
+			exp:     "v1alpha1",
+		},
+		"explicit version not served": {
+			crd:     crd(alpha, beta, unserved),
+			version: "v1",
+			wantErr: true,
+		},
+		"storage version": {
+			crd: crd(alpha, beta),
+			exp: "v1beta1",
+		},
+		"first served version": {
+			crd: crd(unserved, alpha),
+			exp: "v1alpha1",
+		},
+		"no served version": {
+			crd:     crd(unserved),
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := util.DefinitionVersion(tc.crd, v1alpha2.DefinitionReference{Name: "foos.example.com", Version: tc.version})
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.exp, got)
+		})
+	}
+}
+
+func TestTraitsConflict(t *testing.T) {
+	ingress := &v1alpha2.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "ingress", Labels: map[string]string{"traffic": "true"}},
+		Spec:       v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "ingresses.networking.k8s.io"}},
+	}
+	tests := map[string]struct {
+		conflictsWith []string
+		exp           bool
+		expErr        bool
+		reason        string
+	}{
+		"no rules": {
+			reason: "traits without conflictsWith rules conflict with no other traits",
+		},
+		"definition name": {
+			conflictsWith: []string{"route", "ingress"},
+			exp:           true,
+			reason:        "rules should match the name of the TraitDefinition",
+		},
+		"CRD name": {
+			conflictsWith: []string{"ingresses.networking.k8s.io"},
+			exp:           true,
+			reason:        "rules should match the name of the CRD of the TraitDefinition",
+		},
+		"API group": {
+			conflictsWith: []string{"*.networking.k8s.io"},
+			exp:           true,
+			reason:        "wildcard rules should match the API group of the TraitDefinition",
+		},
+		"other API group": {
+			conflictsWith: []string{"*.k8s.io.example.com"},
+			reason:        "wildcard rules should not match other API groups",
+		},
+		"label selector": {
+			conflictsWith: []string{"labelSelector:traffic=true"},
+			exp:           true,
+			reason:        "label selector rules should match the labels of the TraitDefinition",
+		},
+		"invalid label selector": {
+			conflictsWith: []string{"labelSelector:traffic in ("},
+			expErr:        true,
+			reason:        "invalid label selector rules should return an error",
+		},
+	}
+	for name, ti := range tests {
+		t.Log("Running: " + name)
+		td := &v1alpha2.TraitDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "route"},
+			Spec:       v1alpha2.TraitDefinitionSpec{ConflictsWith: ti.conflictsWith},
+		}
+		got, err := util.TraitsConflict(td, ingress)
+		assert.Equal(t, ti.expErr, err != nil, ti.reason)
+		assert.Equal(t, ti.exp, got, ti.reason)
+		// conflicts are symmetric
+		got, err = util.TraitsConflict(ingress, td)
+		assert.Equal(t, ti.expErr, err != nil, ti.reason)
+		assert.Equal(t, ti.exp, got, ti.reason)
+	}
+}
+
+func TestHasScaleSubresource(t *testing.T) {
+	dc := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "deployments"}, {Name: "deployments/scale"}, {Name: "deployments/status"}, {Name: "controllerrevisions"},
+		},
+	}}}}
+	tests := map[string]struct {
+		gvr    schema.GroupVersionResource
+		exp    bool
+		expErr bool
+		reason string
+	}{
+		"scalable": {
+			gvr:    schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			exp:    true,
+			reason: "resources that serve the scale subresource should be scalable",
+		},
+		"not scalable": {
+			gvr:    schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "controllerrevisions"},
+			reason: "resources that don't serve the scale subresource should not be scalable",
+		},
+		"unknown group version": {
+			gvr:    schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"},
+			expErr: true,
+			reason: "errors discovering the resources of the group version should be returned",
+		},
+	}
+	for name, ti := range tests {
+		t.Log("Running: " + name)
+		got, err := util.HasScaleSubresource(dc, ti.gvr)
+		assert.Equal(t, ti.expErr, err != nil, ti.reason)
+		assert.Equal(t, ti.exp, got, ti.reason)
+	}
+}
+
+func TestTraitAppliesToWorkload(t *testing.T) {
+	deploy := &unstructured.Unstructured{}
+	deploy.SetAPIVersion("apps/v1")
+	deploy.SetKind("Deployment")
+	cw := &unstructured.Unstructured{}
+	cw.SetAPIVersion("core.oam.dev/v1alpha2")
+	cw.SetKind("ContainerizedWorkload")
+	dm := mock.NewMockDiscoveryMapper()
+	dm.MockRESTMapping = func(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+		return &meta.RESTMapping{Resource: schema.GroupVersionResource{Resource: strings.ToLower(gk.Kind) + "s", Group: gk.Group}}, nil
+	}
+
+	tests := map[string]struct {
+		appliesTo []string
+		w         *unstructured.Unstructured
+		exp       bool
+		expErr    bool
+		reason    string
+	}{
+		"no rules": {
+			w:      deploy,
+			exp:    true,
+			reason: "traits without appliesToWorkloads rules apply to all workloads",
+		},
+		"kind, group and version": {
+			appliesTo: []string{"Deployment.apps/v1"},
+			w:         deploy,
+			exp:       true,
+			reason:    "rules should match the kind, group and version of the workload",
+		},
+		"kind and group": {
+			appliesTo: []string{"containerizedworkload.core.oam.dev"},
+			w:         cw,
+			exp:       true,
+			reason:    "rules should match the kind and group of the workload",
+		},
+		"definition name": {
+			appliesTo: []string{"containerizedworkloads.core.oam.dev"},
+			w:         cw,
+			exp:       true,
+			reason:    "rules should match the name of the WorkloadDefinition of the workload",
+		},
+		"wildcard": {
+			appliesTo: []string{"*.core.oam.dev"},
+			w:         cw,
+			exp:       true,
+			reason:    "wildcard rules should match every kind of their group",
+		},
+		"other kinds": {
+			appliesTo: []string{"*.core.oam.dev", "statefulset.apps/v1"},
+			w:         deploy,
+			reason:    "rules should not match other kinds",
+		},
+		"invalid rule": {
+			appliesTo: []string{"[.apps"},
+			w:         deploy,
+			expErr:    true,
+			reason:    "invalid rules should return an error",
+		},
+	}
+	for name, ti := range tests {
+		t.Log("Running: " + name)
+		td := &v1alpha2.TraitDefinition{Spec: v1alpha2.TraitDefinitionSpec{AppliesToWorkloads: ti.appliesTo}}
+		got, err := util.TraitAppliesToWorkload(dm, td, ti.w)
+		assert.Equal(t, ti.expErr, err != nil, ti.reason)
+		assert.Equal(t, ti.exp, got, ti.reason)
+	}
+}
+
+func TestScopeAppliesToWorkload(t *testing.T) {
+	cw := &unstructured.Unstructured{}
+	cw.SetAPIVersion("core.oam.dev/v1alpha2")
+	cw.SetKind("ContainerizedWorkload")
+	dm := mock.NewMockDiscoveryMapper()
+
+	tests := map[string]struct {
+		appliesTo []string
+		exp       bool
+		expErr    bool
+		reason    string
+	}{
+		"no rules": {
+			exp:    true,
+			reason: "scopes without appliesToWorkloads rules should accept all workloads",
+		},
+		"matching rule": {
+			appliesTo: []string{"deployment.apps", "containerizedworkload.core.oam.dev"},
+			exp:       true,
+			reason:    "scopes should accept workloads that match one of their rules",
+		},
+		"other kinds": {
+			appliesTo: []string{"deployment.apps"},
+			reason:    "scopes should not accept workloads of other kinds",
+		},
+		"invalid rule": {
+			appliesTo: []string{"[.apps"},
+			expErr:    true,
+			reason:    "invalid rules should return an error",
+		},
+	}
+	for name, ti := range tests {
+		t.Log("Running: " + name)
+		sd := &v1alpha2.ScopeDefinition{Spec: v1alpha2.ScopeDefinitionSpec{AppliesToWorkloads: ti.appliesTo}}
+		got, err := util.ScopeAppliesToWorkload(dm, sd, cw)
+		assert.Equal(t, ti.expErr, err != nil, ti.reason)
+		assert.Equal(t, ti.exp, got, ti.reason)
+	}
+}
+
+func TestValidateParameterValue(t *testing.T) {
+	minimum, maximum := int64(1), int64(5)
+	fldPath := field.NewPath("value")
+
+	tests := map[string]struct {
+		p      v1alpha2.ComponentParameter
+		v      intstr.IntOrString
+		expErr int
+		reason string
+	}{
+		"no constraints": {
+			p:      v1alpha2.ComponentParameter{Name: "p"},
+			v:      intstr.FromInt(10),
+			reason: "parameters without constraints accept any value",
+		},
+		"string": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Type: v1alpha2.StringParameterType},
+			v:      intstr.FromInt(1),
+			expErr: 1,
+			reason: "string parameters should reject integer values",
+		},
+		"integer": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Type: v1alpha2.IntegerParameterType},
+			v:      intstr.FromString("1"),
+			expErr: 1,
+			reason: "integer parameters should reject string values",
+		},
+		"boolean": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Type: v1alpha2.BooleanParameterType},
+			v:      intstr.FromString("false"),
+			reason: "boolean parameters should accept \"true\" and \"false\"",
+		},
+		"not boolean": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Type: v1alpha2.BooleanParameterType},
+			v:      intstr.FromString("yes"),
+			expErr: 1,
+			reason: "boolean parameters should reject other values",
+		},
+		"enum": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Enum: []intstr.IntOrString{intstr.FromString("a"), intstr.FromInt(1)}},
+			v:      intstr.FromInt(1),
+			reason: "values listed in the enum should be accepted",
+		},
+		"not in enum": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Enum: []intstr.IntOrString{intstr.FromString("a"), intstr.FromInt(1)}},
+			v:      intstr.FromString("1"),
+			expErr: 1,
+			reason: "values not listed in the enum should be rejected",
+		},
+		"in range": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Minimum: &minimum, Maximum: &maximum},
+			v:      intstr.FromInt(5),
+			reason: "integer values within the range should be accepted",
+		},
+		"out of range": {
+			p:      v1alpha2.ComponentParameter{Name: "p", Type: v1alpha2.StringParameterType, Minimum: &minimum},
+			v:      intstr.FromInt(0),
+			expErr: 2,
+			reason: "every violated constraint should be reported",
+		},
+	}
+	for name, ti := range tests {
+		t.Log("Running: " + name)
+		errs := util.ValidateParameterValue(fldPath, ti.p, ti.v)
+		assert.Equal(t, ti.expErr, len(errs), ti.reason)
+	}
+}
+
+func TestAppConfigConsumedRevisionNames(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{
+				{RevisionName: "comp1-v1"},
+				{ComponentName: "comp2"},
+			},
+		},
+		Status: v1alpha2.ApplicationConfigurationStatus{
+			Workloads: []v1alpha2.WorkloadStatus{
+				{ComponentName: "comp1", ComponentRevisionName: "comp1-v1"},
+				{ComponentName: "comp2", ComponentRevisionName: "comp2-v3"},
+				{ComponentName: "comp3"},
+			},
+		},
+	}
+	assert.Equal(t, []string{"comp1-v1", "comp2-v3"}, util.AppConfigConsumedRevisionNames(ac),
+		"pinned and running revisions should be indexed once")
+	ac.SetAnnotations(map[string]string{
+		oam.AnnotationPinRevisions:    "true",
+		oam.AnnotationPinnedRevisions: `{"comp2":"comp2-v4"}`,
+	})
+	assert.Equal(t, []string{"comp1-v1", "comp2-v4", "comp2-v3"}, util.AppConfigConsumedRevisionNames(ac),
+		"revisions pinned at admission should be indexed")
+	assert.Nil(t, util.AppConfigConsumedRevisionNames(&v1alpha2.Component{}),
+		"objects other than ApplicationConfigurations should not be indexed")
+}
+
+func TestPinnedRevisionNames(t *testing.T) {
+	ac := &v1alpha2.ApplicationConfiguration{}
+	pinned, err := util.PinnedRevisionNames(ac)
+	assert.NoError(t, err)
+	assert.Nil(t, pinned, "components should not be pinned without the pin revisions annotation")
+
+	ac.SetAnnotations(map[string]string{
+		oam.AnnotationPinRevisions:    "true",
+		oam.AnnotationPinnedRevisions: `{"comp":"comp-v2"}`,
+	})
+	pinned, err = util.PinnedRevisionNames(ac)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"comp": "comp-v2"}, pinned)
+
+	ac.SetAnnotations(map[string]string{
+		oam.AnnotationPinRevisions:    "true",
+		oam.AnnotationPinnedRevisions: "comp-v2",
+	})
+	_, err = util.PinnedRevisionNames(ac)
+	assert.Error(t, err, "malformed pinned revisions should return an error")
+}
+
 func TestPassThroughObjMeta(t *testing.T) {
 	ac := &v1alpha2.ApplicationConfiguration{}
 
@@ -1135,6 +2254,16 @@ func TestPassThroughObjMeta(t *testing.T) {
 		"core.oam.dev/controller":  "oam-kubernetes-runtime",
 	}
 	assert.Equal(t, wantLabels, gotLabels)
+
+	t.Log("annotations of the parent only should not be passed through")
+	ac.SetAnnotations(map[string]string{
+		"key1":                             "value1",
+		corev1.LastAppliedConfigAnnotation: "{}",
+		oam.AnnotationRenderDiff:           oam.RenderDiffEnabled,
+	})
+	var c unstructured.Unstructured
+	util.PassLabelAndAnnotation(ac, &c)
+	assert.Equal(t, map[string]string{"key1": "value1"}, c.GetAnnotations())
 }
 
 func TestAddLabels(t *testing.T) {
@@ -1180,6 +2309,7 @@ func TestAddLabels(t *testing.T) {
 		wantObj := tc.want
 		util.AddLabels(obj, tc.newLabels)
 		assert.Equal(t, wantObj.GetLabels(), obj.GetLabels())
+		assert.Equal(t, map[string]string{"newKey": "newValue"}, tc.newLabels)
 	}
 }
 
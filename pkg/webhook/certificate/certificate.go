@@ -0,0 +1,429 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certificate manages the serving certificate of the admission
+// webhook server.
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Defaults of a Config.
+const (
+	DefaultValidity      = 365 * 24 * time.Hour
+	DefaultRotateBefore  = 30 * 24 * time.Hour
+	DefaultCheckInterval = time.Hour
+)
+
+// Keys of the certificate Secret.
+const (
+	CACertKey = "ca.crt"
+	CertKey   = corev1.TLSCertKey
+	KeyKey    = corev1.TLSPrivateKeyKey
+)
+
+const (
+	errGenerateKey       = "cannot generate private key"
+	errGenerateCert      = "cannot generate certificate"
+	errMarshalKey        = "cannot marshal private key"
+	errGetSecret         = "cannot get certificate secret"
+	errCreateSecret      = "cannot create certificate secret"
+	errUpdateSecret      = "cannot update certificate secret"
+	errNotReady          = "webhook serving certificate is not ready"
+	errFmtWriteFile      = "cannot write certificate file %q"
+	errFmtGetWebhookConf = "cannot get webhook configuration %q"
+	errFmtPatchCABundle  = "cannot patch caBundle of webhook configuration %q"
+	errFmtGetCRD         = "cannot get custom resource definition %q"
+	errFmtPatchCRD       = "cannot patch conversion of custom resource definition %q"
+)
+
+// servicePort is the port of the Service the webhook server is exposed at.
+const servicePort = 443
+
+// Config of a Rotator.
+type Config struct {
+	// CertDir the webhook server reads its certificate and key from.
+	CertDir string
+
+	// SecretName and SecretNamespace of the Secret that stores the CA and
+	// the serving certificate, so that all replicas serve the same one.
+	SecretName      string
+	SecretNamespace string
+
+	// ServiceName and ServiceNamespace of the Service the webhook server is
+	// exposed by. The serving certificate is valid for its DNS names.
+	ServiceName      string
+	ServiceNamespace string
+
+	// ValidatingWebhookConfigurations and MutatingWebhookConfigurations
+	// whose webhooks are served by the Service. The CA is patched into their
+	// caBundle.
+	ValidatingWebhookConfigurations []string
+	MutatingWebhookConfigurations   []string
+
+	// CustomResourceDefinitions whose versions are converted by the
+	// conversion webhook served at ConversionPath by the Service. Their
+	// conversion strategy is set to Webhook, with the CA as caBundle.
+	CustomResourceDefinitions []string
+	ConversionPath            string
+
+	// Validity of generated certificates. Defaults to DefaultValidity.
+	Validity time.Duration
+
+	// RotateBefore is how long before it expires a certificate is rotated.
+	// Defaults to DefaultRotateBefore.
+	RotateBefore time.Duration
+
+	// CheckInterval is how often the certificate is checked for rotation.
+	// Defaults to DefaultCheckInterval.
+	CheckInterval time.Duration
+}
+
+// A Rotator generates and rotates the serving certificate of the webhook
+// server, and patches its CA into the webhook configurations.
+type Rotator struct {
+	client client.Client
+	cfg    Config
+	log    logging.Logger
+	now    func() time.Time
+
+	mu    sync.RWMutex
+	ready bool
+}
+
+var _ manager.Runnable = &Rotator{}
+var _ manager.LeaderElectionRunnable = &Rotator{}
+
+// NewRotator returns a Rotator of the certificate described by the supplied
+// config. Its client must not be backed by a cache that has not started yet.
+func NewRotator(c client.Client, cfg Config, l logging.Logger) *Rotator {
+	if cfg.Validity == 0 {
+		cfg.Validity = DefaultValidity
+	}
+	if cfg.RotateBefore == 0 {
+		cfg.RotateBefore = DefaultRotateBefore
+	}
+	if cfg.CheckInterval == 0 {
+		cfg.CheckInterval = DefaultCheckInterval
+	}
+	return &Rotator{client: c, cfg: cfg, log: l, now: time.Now}
+}
+
+// Ensure the certificate stored in the Secret is valid for longer than the
+// rotation period, generating a new one if it is not, write it to the
+// certificate directory, and patch its CA into the webhook configurations.
+func (r *Rotator) Ensure(ctx context.Context) error {
+	ca, cert, key, err := r.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.writeFiles(cert, key); err != nil {
+		return err
+	}
+	if err := r.patchCABundles(ctx, ca); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.ready = true
+	r.mu.Unlock()
+	return nil
+}
+
+// Start rotating the certificate until the supplied channel is closed.
+func (r *Rotator) Start(stop <-chan struct{}) error {
+	t := time.NewTicker(r.cfg.CheckInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+			if err := r.Ensure(context.Background()); err != nil {
+				r.log.Info("Cannot rotate webhook serving certificate", "error", err)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false, every replica serves the webhooks and
+// needs the certificate.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Ready returns an error until the certificate has been written to the
+// certificate directory. It may be used as a readiness check.
+func (r *Rotator) Ready(_ *http.Request) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.ready {
+		return errors.New(errNotReady)
+	}
+	return nil
+}
+
+func (r *Rotator) ensureSecret(ctx context.Context) (ca, cert, key []byte, err error) {
+	s := &corev1.Secret{}
+	nn := types.NamespacedName{Namespace: r.cfg.SecretNamespace, Name: r.cfg.SecretName}
+	err = r.client.Get(ctx, nn, s)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, nil, nil, errors.Wrap(err, errGetSecret)
+	}
+	exists := err == nil
+	ca, cert, key = s.Data[CACertKey], s.Data[CertKey], s.Data[KeyKey]
+	if exists && Valid(ca, cert, key, r.dnsNames(), r.now().Add(r.cfg.RotateBefore)) {
+		return ca, cert, key, nil
+	}
+
+	r.log.Info("Generating webhook serving certificate", "secret", nn.String())
+	ca, cert, key, err = Generate(r.dnsNames(), r.now(), r.cfg.Validity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	s.Data = map[string][]byte{CACertKey: ca, CertKey: cert, KeyKey: key}
+	if !exists {
+		s.SetNamespace(nn.Namespace)
+		s.SetName(nn.Name)
+		s.Type = corev1.SecretTypeTLS
+		err = errors.Wrap(r.client.Create(ctx, s), errCreateSecret)
+	} else {
+		err = errors.Wrap(r.client.Update(ctx, s), errUpdateSecret)
+	}
+	if kerrors.IsAlreadyExists(errors.Cause(err)) || kerrors.IsConflict(errors.Cause(err)) {
+		// another replica stored a certificate first, use that one
+		return r.ensureSecret(ctx)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ca, cert, key, nil
+}
+
+func (r *Rotator) dnsNames() []string {
+	svc, ns := r.cfg.ServiceName, r.cfg.ServiceNamespace
+	return []string{svc, svc + "." + ns, svc + "." + ns + ".svc", svc + "." + ns + ".svc.cluster.local"}
+}
+
+func (r *Rotator) writeFiles(cert, key []byte) error {
+	if err := os.MkdirAll(r.cfg.CertDir, 0700); err != nil {
+		return errors.Wrapf(err, errFmtWriteFile, r.cfg.CertDir)
+	}
+	// the key is written first, the webhook server reloads both files
+	// whenever the certificate changes
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{KeyKey, key}, {CertKey, cert}} {
+		path := filepath.Join(r.cfg.CertDir, f.name)
+		if cur, err := ioutil.ReadFile(filepath.Clean(path)); err == nil && bytes.Equal(cur, f.data) {
+			continue
+		}
+		if err := ioutil.WriteFile(path, f.data, 0600); err != nil {
+			return errors.Wrapf(err, errFmtWriteFile, path)
+		}
+	}
+	return nil
+}
+
+func (r *Rotator) patchCABundles(ctx context.Context, ca []byte) error {
+	for _, name := range r.cfg.ValidatingWebhookConfigurations {
+		vwc := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, vwc); err != nil {
+			return errors.Wrapf(err, errFmtGetWebhookConf, name)
+		}
+		changed := false
+		for i := range vwc.Webhooks {
+			changed = r.setCABundle(&vwc.Webhooks[i].ClientConfig, ca) || changed
+		}
+		if !changed {
+			continue
+		}
+		if err := r.client.Update(ctx, vwc); err != nil {
+			return errors.Wrapf(err, errFmtPatchCABundle, name)
+		}
+	}
+	for _, name := range r.cfg.MutatingWebhookConfigurations {
+		mwc := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, mwc); err != nil {
+			return errors.Wrapf(err, errFmtGetWebhookConf, name)
+		}
+		changed := false
+		for i := range mwc.Webhooks {
+			changed = r.setCABundle(&mwc.Webhooks[i].ClientConfig, ca) || changed
+		}
+		if !changed {
+			continue
+		}
+		if err := r.client.Update(ctx, mwc); err != nil {
+			return errors.Wrapf(err, errFmtPatchCABundle, name)
+		}
+	}
+	for _, name := range r.cfg.CustomResourceDefinitions {
+		crd := &crdv1.CustomResourceDefinition{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return errors.Wrapf(err, errFmtGetCRD, name)
+		}
+		if !r.setConversion(crd, ca) {
+			continue
+		}
+		if err := r.client.Update(ctx, crd); err != nil {
+			return errors.Wrapf(err, errFmtPatchCRD, name)
+		}
+	}
+	return nil
+}
+
+// setConversion sets the conversion of the CRD to the conversion webhook
+// served by the Service, and returns true if it changed.
+func (r *Rotator) setConversion(crd *crdv1.CustomResourceDefinition, ca []byte) bool {
+	path, port := r.cfg.ConversionPath, int32(servicePort)
+	// the conversion webhook of controller-runtime serves v1beta1 reviews
+	conversion := &crdv1.CustomResourceConversion{
+		Strategy: crdv1.WebhookConverter,
+		Webhook: &crdv1.WebhookConversion{
+			ClientConfig: &crdv1.WebhookClientConfig{
+				Service: &crdv1.ServiceReference{
+					Namespace: r.cfg.ServiceNamespace,
+					Name:      r.cfg.ServiceName,
+					Path:      &path,
+					Port:      &port,
+				},
+				CABundle: ca,
+			},
+			ConversionReviewVersions: []string{"v1beta1"},
+		},
+	}
+	if equality.Semantic.DeepEqual(crd.Spec.Conversion, conversion) {
+		return false
+	}
+	crd.Spec.Conversion = conversion
+	return true
+}
+
+// setCABundle sets the caBundle of webhooks served by the Service, and
+// returns true if it changed.
+func (r *Rotator) setCABundle(cc *admissionregistrationv1beta1.WebhookClientConfig, ca []byte) bool {
+	if cc.Service == nil || cc.Service.Name != r.cfg.ServiceName || cc.Service.Namespace != r.cfg.ServiceNamespace {
+		return false
+	}
+	if bytes.Equal(cc.CABundle, ca) {
+		return false
+	}
+	cc.CABundle = ca
+	return true
+}
+
+// Generate a self-signed CA, and a serving certificate and key for the
+// supplied DNS names signed by it, all PEM encoded.
+func Generate(dnsNames []string, notBefore time.Time, validity time.Duration) (ca, cert, key []byte, err error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errGenerateKey)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(notBefore.UnixNano()),
+		Subject:               pkix.Name{CommonName: "oam-kubernetes-runtime-webhook-ca"},
+		NotBefore:             notBefore.Add(-time.Hour),
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, caKey.Public(), caKey)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errGenerateCert)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errGenerateCert)
+	}
+
+	servingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errGenerateKey)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(notBefore.UnixNano() + 1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    notBefore.Add(-time.Hour),
+		NotAfter:     notBefore.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, servingKey.Public(), caKey)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errGenerateCert)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(servingKey)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errMarshalKey)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// Valid returns true if the supplied certificate matches the supplied key, is
+// signed by the supplied CA, and is valid for the supplied DNS names at the
+// supplied time.
+func Valid(ca, cert, key []byte, dnsNames []string, at time.Time) bool {
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return false
+	}
+	c, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return false
+	}
+	for _, name := range dnsNames {
+		if _, err := c.Verify(x509.VerifyOptions{DNSName: name, Roots: roots, CurrentTime: at}); err != nil {
+			return false
+		}
+	}
+	return true
+}
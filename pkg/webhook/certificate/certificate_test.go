@@ -0,0 +1,196 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenerateValid(t *testing.T) {
+	now := time.Now()
+	names := []string{"svc", "svc.ns.svc"}
+	ca, cert, key, err := Generate(names, now, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate(...): %v", err)
+	}
+	otherCA, _, _, err := Generate(names, now, time.Hour)
+	if err != nil {
+		t.Fatalf("Generate(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		ca     []byte
+		names  []string
+		at     time.Time
+		want   bool
+	}{
+		"Valid": {
+			reason: "A generated certificate should be valid for its names",
+			ca:     ca,
+			names:  names,
+			at:     now,
+			want:   true,
+		},
+		"OtherName": {
+			reason: "A generated certificate should not be valid for other names",
+			ca:     ca,
+			names:  []string{"other"},
+			at:     now,
+		},
+		"Expired": {
+			reason: "A generated certificate should not be valid after it expires",
+			ca:     ca,
+			names:  names,
+			at:     now.Add(2 * time.Hour),
+		},
+		"OtherCA": {
+			reason: "A generated certificate should not be valid for another CA",
+			ca:     otherCA,
+			names:  names,
+			at:     now,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Valid(tc.ca, cert, key, tc.names, tc.at); got != tc.want {
+				t.Errorf("\n%s\nValid(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRotatorEnsure(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir(...): %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	webhook := func(svc string) admissionregistrationv1beta1.ValidatingWebhook {
+		return admissionregistrationv1beta1.ValidatingWebhook{
+			Name: svc + ".example.com",
+			ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+				Service: &admissionregistrationv1beta1.ServiceReference{Namespace: "oam-system", Name: svc},
+			},
+		}
+	}
+	vwc := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "oam"},
+		Webhooks:   []admissionregistrationv1beta1.ValidatingWebhook{webhook("oam-webhook"), webhook("other-webhook")},
+	}
+	crd := &crdv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "components.core.oam.dev"},
+		Spec: crdv1.CustomResourceDefinitionSpec{
+			Conversion: &crdv1.CustomResourceConversion{Strategy: crdv1.NoneConverter},
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = crdv1.AddToScheme(scheme)
+	c := fake.NewFakeClientWithScheme(scheme, vwc, crd)
+	r := NewRotator(c, Config{
+		CertDir:                         dir,
+		SecretName:                      "webhook-server-cert",
+		SecretNamespace:                 "oam-system",
+		ServiceName:                     "oam-webhook",
+		ServiceNamespace:                "oam-system",
+		ValidatingWebhookConfigurations: []string{"oam"},
+		CustomResourceDefinitions:       []string{"components.core.oam.dev"},
+		ConversionPath:                  "/convert",
+	}, logging.NewNopLogger())
+
+	if err := r.Ready(nil); err == nil {
+		t.Errorf("r.Ready(...): want error before the certificate is ensured")
+	}
+	if err := r.Ensure(ctx); err != nil {
+		t.Fatalf("r.Ensure(...): %v", err)
+	}
+	if err := r.Ready(nil); err != nil {
+		t.Errorf("r.Ready(...): %v", err)
+	}
+
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "oam-system", Name: "webhook-server-cert"}, s); err != nil {
+		t.Fatalf("c.Get(...): %v", err)
+	}
+	if !Valid(s.Data[CACertKey], s.Data[CertKey], s.Data[KeyKey], r.dnsNames(), time.Now()) {
+		t.Errorf("r.Ensure(...): want a valid certificate stored in the secret")
+	}
+	for _, k := range []string{CertKey, KeyKey} {
+		got, err := ioutil.ReadFile(filepath.Join(dir, k))
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile(...): %v", err)
+		}
+		if !bytes.Equal(got, s.Data[k]) {
+			t.Errorf("r.Ensure(...): want %s written to the certificate directory", k)
+		}
+	}
+	got := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "oam"}, got); err != nil {
+		t.Fatalf("c.Get(...): %v", err)
+	}
+	if !bytes.Equal(got.Webhooks[0].ClientConfig.CABundle, s.Data[CACertKey]) {
+		t.Errorf("r.Ensure(...): want the CA patched into the caBundle of webhooks served by the service")
+	}
+	if len(got.Webhooks[1].ClientConfig.CABundle) != 0 {
+		t.Errorf("r.Ensure(...): want the caBundle of webhooks served by other services unchanged")
+	}
+	gotCRD := &crdv1.CustomResourceDefinition{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "components.core.oam.dev"}, gotCRD); err != nil {
+		t.Fatalf("c.Get(...): %v", err)
+	}
+	if cv := gotCRD.Spec.Conversion; cv.Strategy != crdv1.WebhookConverter || cv.Webhook == nil ||
+		cv.Webhook.ClientConfig.Service.Name != "oam-webhook" || *cv.Webhook.ClientConfig.Service.Path != "/convert" ||
+		!bytes.Equal(cv.Webhook.ClientConfig.CABundle, s.Data[CACertKey]) {
+		t.Errorf("r.Ensure(...): want the conversion of the CRD set to the conversion webhook served by the service")
+	}
+
+	check := func(reason string, now time.Time, rotated bool) {
+		r.now = func() time.Time { return now }
+		if err := r.Ensure(ctx); err != nil {
+			t.Fatalf("r.Ensure(...): %v", err)
+		}
+		cur := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "oam-system", Name: "webhook-server-cert"}, cur); err != nil {
+			t.Fatalf("c.Get(...): %v", err)
+		}
+		if changed := !bytes.Equal(cur.Data[CertKey], s.Data[CertKey]); changed != rotated {
+			t.Errorf("\n%s\nr.Ensure(...): want rotated %t, got %t", reason, rotated, changed)
+		}
+	}
+	check("A valid certificate should be kept", time.Now(), false)
+	check("A certificate that expires within the rotation period should be rotated",
+		time.Now().Add(DefaultValidity-DefaultRotateBefore+time.Hour), true)
+}
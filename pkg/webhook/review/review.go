@@ -0,0 +1,277 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package review serves admission requests sent as AdmissionReviews of
+// either admission.k8s.io/v1 or admission.k8s.io/v1beta1.
+package review
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	errEmptyBody         = "request body is empty"
+	errFmtContentType    = "content type %q is not application/json"
+	errDecodeReview      = "cannot decode the admission review"
+	errNoRequest         = "admission review has no request"
+	errFmtUnexpectedKind = "unexpected admission review kind %s"
+	errEncodeReview      = "cannot encode the admission review"
+)
+
+const (
+	kindAdmissionReview = "AdmissionReview"
+	contentTypeJSON     = "application/json"
+
+	// warningAnnotationPrefix prefixes the audit annotations that carry the
+	// warnings of an admission response, because the admission responses of
+	// this version of the Kubernetes API have no warnings field yet. They
+	// are moved into one when the response is written.
+	warningAnnotationPrefix = "warning.review.oam.dev/"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+
+	reviewlog = logf.Log.WithName("admission review")
+)
+
+func init() {
+	utilruntime.Must(admissionv1.AddToScheme(scheme))
+	utilruntime.Must(admissionv1beta1.AddToScheme(scheme))
+}
+
+// A Webhook serves admission requests with the admission handler it wraps. It
+// responds with an AdmissionReview of the version the request was sent as, so
+// it may be registered with admissionReviewVersions v1 and v1beta1.
+type Webhook struct {
+	*admission.Webhook
+}
+
+// New returns a Webhook that serves admission requests with the supplied
+// handler.
+func New(h admission.Handler) *Webhook {
+	wh := &admission.Webhook{Handler: h}
+	_ = wh.InjectLogger(reviewlog)
+	return &Webhook{Webhook: wh}
+}
+
+// ServeHTTP decodes the AdmissionReview, handles its request and writes the
+// response as an AdmissionReview of the same version.
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, gv, err := decode(r)
+	if err != nil {
+		reviewlog.Error(err, "unable to decode the request")
+		write(w, gv, admission.Errored(http.StatusBadRequest, err))
+		return
+	}
+	reviewlog.V(1).Info("received request", "version", gv.String(), "UID", req.UID, "kind", req.Kind,
+		"resource", req.Resource)
+	write(w, gv, wh.Handle(r.Context(), req))
+}
+
+// decode the admission request of an AdmissionReview. The version of the
+// AdmissionReview is returned even if it cannot be decoded, falling back to
+// v1beta1 if it is unknown.
+func decode(r *http.Request) (admission.Request, schema.GroupVersion, error) {
+	req := admission.Request{}
+	gv := admissionv1beta1.SchemeGroupVersion
+	if r.Body == nil {
+		return req, gv, errors.New(errEmptyBody)
+	}
+	if ct := r.Header.Get("Content-Type"); ct != contentTypeJSON {
+		return req, gv, errors.Errorf(errFmtContentType, ct)
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return req, gv, errors.Wrap(err, errDecodeReview)
+	}
+	obj, gvk, err := codecs.UniversalDeserializer().Decode(body, nil, nil)
+	if gvk != nil && gvk.GroupVersion() == admissionv1.SchemeGroupVersion {
+		gv = admissionv1.SchemeGroupVersion
+	}
+	if err != nil {
+		return req, gv, errors.Wrap(err, errDecodeReview)
+	}
+	switch ar := obj.(type) {
+	case *admissionv1.AdmissionReview:
+		if ar.Request == nil {
+			return req, gv, errors.New(errNoRequest)
+		}
+		req.AdmissionRequest = fromV1Request(ar.Request)
+	case *admissionv1beta1.AdmissionReview:
+		if ar.Request == nil {
+			return req, gv, errors.New(errNoRequest)
+		}
+		req.AdmissionRequest = *ar.Request
+	default:
+		return req, gv, errors.Errorf(errFmtUnexpectedKind, gvk)
+	}
+	return req, gv, nil
+}
+
+// WithWarnings returns the supplied admission response with the supplied
+// warnings added, which API servers of Kubernetes 1.19 and later return to
+// the client that sent the request. Older API servers ignore them.
+func WithWarnings(resp admission.Response, warnings ...string) admission.Response {
+	if len(warnings) == 0 {
+		return resp
+	}
+	n := len(Warnings(resp))
+	annotations := make(map[string]string, len(resp.AuditAnnotations)+len(warnings))
+	for k, v := range resp.AuditAnnotations {
+		annotations[k] = v
+	}
+	for i, warning := range warnings {
+		annotations[warningAnnotationPrefix+strconv.Itoa(n+i)] = warning
+	}
+	resp.AuditAnnotations = annotations
+	return resp
+}
+
+// Warnings returns the warnings of the supplied admission response, in the
+// order they were added.
+func Warnings(resp admission.Response) []string {
+	indexes := map[int]string{}
+	for k, v := range resp.AuditAnnotations {
+		if !strings.HasPrefix(k, warningAnnotationPrefix) {
+			continue
+		}
+		if i, err := strconv.Atoi(strings.TrimPrefix(k, warningAnnotationPrefix)); err == nil {
+			indexes[i] = v
+		}
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+	order := make([]int, 0, len(indexes))
+	for i := range indexes {
+		order = append(order, i)
+	}
+	sort.Ints(order)
+	warnings := make([]string, 0, len(order))
+	for _, i := range order {
+		warnings = append(warnings, indexes[i])
+	}
+	return warnings
+}
+
+// withoutWarnings returns the audit annotations of the supplied admission
+// response that don't carry warnings, or nil if there are none.
+func withoutWarnings(resp admission.Response) map[string]string {
+	var annotations map[string]string
+	for k, v := range resp.AuditAnnotations {
+		if strings.HasPrefix(k, warningAnnotationPrefix) {
+			continue
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// An admissionReview is an AdmissionReview of either version, whose response
+// may carry warnings.
+type admissionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Response        interface{} `json:"response,omitempty"`
+}
+
+type v1Response struct {
+	*admissionv1.AdmissionResponse
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type v1beta1Response struct {
+	*admissionv1beta1.AdmissionResponse
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// write the admission response as an AdmissionReview of the supplied version.
+func write(w http.ResponseWriter, gv schema.GroupVersion, resp admission.Response) {
+	warnings := Warnings(resp)
+	resp.AuditAnnotations = withoutWarnings(resp)
+	ar := &admissionReview{TypeMeta: metav1.TypeMeta{APIVersion: gv.String(), Kind: kindAdmissionReview}}
+	if gv == admissionv1.SchemeGroupVersion {
+		ar.Response = &v1Response{AdmissionResponse: toV1Response(&resp.AdmissionResponse), Warnings: warnings}
+	} else {
+		ar.Response = &v1beta1Response{AdmissionResponse: &resp.AdmissionResponse, Warnings: warnings}
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(ar); err != nil {
+		reviewlog.Error(errors.Wrap(err, errEncodeReview), "unable to encode the response")
+		return
+	}
+	reviewlog.V(1).Info("wrote response", "version", gv.String(), "UID", resp.UID, "allowed", resp.Allowed,
+		"result", resp.Result)
+}
+
+// fromV1Request converts an admission/v1 request to the admission/v1beta1
+// request admission handlers process. The two versions share their fields.
+func fromV1Request(in *admissionv1.AdmissionRequest) admissionv1beta1.AdmissionRequest {
+	return admissionv1beta1.AdmissionRequest{
+		UID:                in.UID,
+		Kind:               in.Kind,
+		Resource:           in.Resource,
+		SubResource:        in.SubResource,
+		RequestKind:        in.RequestKind,
+		RequestResource:    in.RequestResource,
+		RequestSubResource: in.RequestSubResource,
+		Name:               in.Name,
+		Namespace:          in.Namespace,
+		Operation:          admissionv1beta1.Operation(in.Operation),
+		UserInfo:           in.UserInfo,
+		Object:             in.Object,
+		OldObject:          in.OldObject,
+		DryRun:             in.DryRun,
+		Options:            in.Options,
+	}
+}
+
+// toV1Response converts the admission/v1beta1 response of an admission handler
+// to an admission/v1 response.
+func toV1Response(in *admissionv1beta1.AdmissionResponse) *admissionv1.AdmissionResponse {
+	out := &admissionv1.AdmissionResponse{
+		UID:              in.UID,
+		Allowed:          in.Allowed,
+		Result:           in.Result,
+		Patch:            in.Patch,
+		AuditAnnotations: in.AuditAnnotations,
+	}
+	if in.PatchType != nil {
+		pt := admissionv1.PatchType(*in.PatchType)
+		out.PatchType = &pt
+	}
+	return out
+}
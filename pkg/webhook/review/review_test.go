@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestServeHTTP(t *testing.T) {
+	// the handler admits creations, patches them and warns about them
+	h := admission.HandlerFunc(func(_ context.Context, req admission.Request) admission.Response {
+		if req.Operation != admissionv1beta1.Create {
+			return admission.Denied("only creations are admitted")
+		}
+		return WithWarnings(admission.PatchResponseFromRaw([]byte(`{}`), []byte(`{"metadata":{"name":"patched"}}`)), "patched")
+	})
+	wh := New(h)
+
+	tests := []struct {
+		caseName    string
+		contentType string
+		body        string
+		apiVersion  string
+		uid         string
+		allowed     bool
+		patched     bool
+		warnings    []string
+	}{
+		{
+			caseName:    "Test admission/v1 reviews are answered as admission/v1",
+			contentType: "application/json",
+			body:        `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"v1-uid","operation":"CREATE"}}`,
+			apiVersion:  "admission.k8s.io/v1",
+			uid:         "v1-uid",
+			allowed:     true,
+			patched:     true,
+			warnings:    []string{"patched"},
+		},
+		{
+			caseName:    "Test admission/v1 requests are handled",
+			contentType: "application/json",
+			body:        `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"v1-uid","operation":"DELETE"}}`,
+			apiVersion:  "admission.k8s.io/v1",
+			uid:         "v1-uid",
+			allowed:     false,
+		},
+		{
+			caseName:    "Test admission/v1beta1 reviews are answered as admission/v1beta1",
+			contentType: "application/json",
+			body:        `{"apiVersion":"admission.k8s.io/v1beta1","kind":"AdmissionReview","request":{"uid":"v1beta1-uid","operation":"CREATE"}}`,
+			apiVersion:  "admission.k8s.io/v1beta1",
+			uid:         "v1beta1-uid",
+			allowed:     true,
+			patched:     true,
+			warnings:    []string{"patched"},
+		},
+		{
+			caseName:    "Test admission/v1 reviews without a request are rejected",
+			contentType: "application/json",
+			body:        `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview"}`,
+			apiVersion:  "admission.k8s.io/v1",
+			allowed:     false,
+		},
+		{
+			caseName:    "Test unknown reviews are rejected as admission/v1beta1",
+			contentType: "application/json",
+			body:        `{"apiVersion":"admission.k8s.io/v2","kind":"AdmissionReview","request":{"uid":"v2-uid"}}`,
+			apiVersion:  "admission.k8s.io/v1beta1",
+			allowed:     false,
+		},
+		{
+			caseName:    "Test reviews of other content types are rejected",
+			contentType: "application/yaml",
+			body:        `{"apiVersion":"admission.k8s.io/v1","kind":"AdmissionReview","request":{"uid":"v1-uid"}}`,
+			apiVersion:  "admission.k8s.io/v1beta1",
+			allowed:     false,
+		},
+	}
+	for _, tc := range tests {
+		msg := fmt.Sprintf("Test case: %q", tc.caseName)
+		r := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(tc.body))
+		r.Header.Set("Content-Type", tc.contentType)
+		w := httptest.NewRecorder()
+		wh.ServeHTTP(w, r)
+
+		got := struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Response   *struct {
+				admissionv1.AdmissionResponse
+				Warnings []string `json:"warnings"`
+			} `json:"response"`
+		}{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got), msg)
+		assert.Equal(t, tc.apiVersion, got.APIVersion, msg)
+		assert.Equal(t, "AdmissionReview", got.Kind, msg)
+		if !assert.NotNil(t, got.Response, msg) {
+			continue
+		}
+		assert.Equal(t, tc.uid, string(got.Response.UID), msg)
+		assert.Equal(t, tc.allowed, got.Response.Allowed, msg)
+		assert.Equal(t, tc.patched, got.Response.PatchType != nil, msg)
+		assert.Equal(t, tc.warnings, got.Response.Warnings, msg)
+		assert.Empty(t, got.Response.AuditAnnotations, msg)
+	}
+}
+
+func TestWithWarnings(t *testing.T) {
+	resp := admission.Allowed("")
+	resp.AuditAnnotations = map[string]string{"example.com/audit": "true"}
+	resp = WithWarnings(resp, "first")
+	resp = WithWarnings(resp)
+	for i := 2; i <= 11; i++ {
+		resp = WithWarnings(resp, fmt.Sprintf("warning %d", i))
+	}
+
+	want := []string{"first"}
+	for i := 2; i <= 11; i++ {
+		want = append(want, fmt.Sprintf("warning %d", i))
+	}
+	assert.Equal(t, want, Warnings(resp))
+	assert.Equal(t, map[string]string{"example.com/audit": "true"}, withoutWarnings(resp))
+}
@@ -1,19 +1,188 @@
 package v1alpha2
 
 import (
-	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/applicationconfiguration"
-	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/component"
+	"context"
+	"net/http"
+	"time"
 
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/metrics"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/policy"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/applicationconfiguration"
+)
+
+// Names of the admission handlers of the DefaultRegistry.
+const (
+	AppConfigValidatingHandler          = "applicationconfiguration-validating"
+	AppConfigMutatingHandler            = "applicationconfiguration-mutating"
+	ComponentValidatingHandler          = "component-validating"
+	ComponentMutatingHandler            = "component-mutating"
+	ControllerRevisionValidatingHandler = "controllerrevision-validating"
+	WorkloadDefinitionValidatingHandler = "workloaddefinition-validating"
+	TraitDefinitionValidatingHandler    = "traitdefinition-validating"
+	ScopeDefinitionValidatingHandler    = "scopedefinition-validating"
+
+	// Handlers that deny the deletion of definitions that are still in use.
+	WorkloadDefinitionDeletionValidatingHandler = "workloaddefinition-deletion-validating"
+	TraitDefinitionDeletionValidatingHandler    = "traitdefinition-deletion-validating"
+	ScopeDefinitionDeletionValidatingHandler    = "scopedefinition-deletion-validating"
+
+	// ScopeDeletionValidatingHandler denies the deletion of scopes that
+	// still reference workloads.
+	ScopeDeletionValidatingHandler = "scope-deletion-validating"
+
+	// ConversionHandler converts Components and ApplicationConfigurations
+	// between the API versions they are served at.
+	ConversionHandler = "conversion"
 )
 
+// ConversionPath is the default path the conversion handler is served at.
+const ConversionPath = "/convert"
+
+var admitlog = logf.Log.WithName("admission webhook")
+
+// Options configures the admission handlers registered by AddWithOptions or
+// Registry.AddToManager.
+type Options struct {
+	// Disabled admission handlers are not registered.
+	Disabled []string
+
+	// Paths the admission handlers are served at, keyed by handler name.
+	// Handlers without a path are served at their default path.
+	Paths map[string]string
+
+	// FailurePolicy of the admission handlers. Requests that a handler fails
+	// to process, rather than denies, are admitted if it is Ignore and
+	// rejected if it is Fail. Defaults to Fail.
+	FailurePolicy admissionregistrationv1beta1.FailurePolicyType
+
+	// NamespaceSelector selects the namespaces whose requests are processed
+	// by the admission handlers. Requests in other namespaces are admitted
+	// as they are, requests for cluster scoped resources are always
+	// processed. Requests in all namespaces are processed if it is nil.
+	NamespaceSelector labels.Selector
+
+	// Port the webhook server listens on. The port the manager was
+	// configured with is used if it is zero.
+	Port int
+
+	// CertDir contains the certificate and key of the webhook server. The
+	// directory the manager was configured with is used if it is empty.
+	CertDir string
+
+	// Policies are evaluated against the resources ApplicationConfigurations
+	// render to, and their violations deny admission. No policies are
+	// evaluated if it is nil.
+	Policies policy.Evaluator
+
+	// Limits on the size of ApplicationConfigurations. Limits that are zero
+	// are not enforced.
+	Limits applicationconfiguration.Limits
+
+	// RejectDeprecatedDefinitions rejects Components and
+	// ApplicationConfigurations that start using deprecated definitions,
+	// rather than warning about them.
+	RejectDeprecatedDefinitions bool
+
+	// DefinitionClient is injected into the admission handlers instead of the
+	// client of the manager, e.g. to read definitions from a definition cache
+	// shared with the controllers. The client of the manager is injected if it
+	// is nil.
+	DefinitionClient client.Client
+}
+
 // Add will be called in main and register all validation handlers
 func Add(mgr manager.Manager) error {
-	if err := applicationconfiguration.RegisterValidatingHandler(mgr); err != nil {
-		return err
+	return AddWithOptions(mgr, Options{})
+}
+
+// AddWithOptions registers the admission handlers of the DefaultRegistry that
+// are not disabled by the supplied options, configured by them.
+func AddWithOptions(mgr manager.Manager, o Options) error {
+	return DefaultRegistry().AddToManager(mgr, o)
+}
+
+// An optionsHandler applies the failure policy and namespace selector of the
+// admission handler it wraps, and records metrics of its decisions.
+type optionsHandler struct {
+	admission.Handler
+
+	// Wrapped handlers that Handler wraps. Their fields are injected too.
+	Wrapped []admission.Handler
+
+	Name              string
+	Client            client.Reader
+	DefinitionClient  client.Client
+	FailurePolicy     admissionregistrationv1beta1.FailurePolicyType
+	NamespaceSelector labels.Selector
+}
+
+// Handle the supplied request if it is in a selected namespace, and apply the
+// failure policy to the response.
+func (h *optionsHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	start := time.Now()
+	resp := h.handle(ctx, req)
+	result, reason := metrics.ResultAllowed, ""
+	if !resp.Allowed {
+		result = metrics.ResultErrored
+		if resp.Result != nil && resp.Result.Code == http.StatusForbidden {
+			result, reason = metrics.ResultDenied, string(resp.Result.Reason)
+		}
+	}
+	metrics.ObserveAdmission(h.Name, string(req.Operation), result, reason, time.Since(start))
+	return resp
+}
+
+func (h *optionsHandler) handle(ctx context.Context, req admission.Request) admission.Response {
+	if h.NamespaceSelector != nil && req.Namespace != "" {
+		ns := &corev1.Namespace{}
+		if err := h.Client.Get(ctx, types.NamespacedName{Name: req.Namespace}, ns); err != nil {
+			return h.fail(req, admission.Errored(http.StatusInternalServerError, err))
+		}
+		if !h.NamespaceSelector.Matches(labels.Set(ns.GetLabels())) {
+			return admission.Allowed("")
+		}
+	}
+	return h.fail(req, h.Handler.Handle(ctx, req))
+}
+
+// fail admits requests the wrapped handler failed to process if the failure
+// policy is Ignore. Denied requests are still denied.
+func (h *optionsHandler) fail(req admission.Request, resp admission.Response) admission.Response {
+	if h.FailurePolicy != admissionregistrationv1beta1.Ignore || resp.Allowed || resp.Result == nil ||
+		resp.Result.Code == http.StatusForbidden {
+		return resp
+	}
+	admitlog.Info("ignore admission failure", "resource", req.Resource, "namespace", req.Namespace,
+		"name", req.Name, "message", resp.Result.Message)
+	return admission.Allowed("")
+}
+
+var _ inject.Injector = &optionsHandler{}
+
+// InjectFunc injects the fields of the wrapped handlers, e.g. their client and
+// decoder. The definition client, if any, replaces the injected client.
+func (h *optionsHandler) InjectFunc(f inject.Func) error {
+	handlers := append(append([]admission.Handler{}, h.Wrapped...), h.Handler)
+	for _, w := range handlers {
+		if err := f(w); err != nil {
+			return err
+		}
+		if h.DefinitionClient == nil {
+			continue
+		}
+		if _, err := inject.ClientInto(h.DefinitionClient, w); err != nil {
+			return err
+		}
 	}
-	applicationconfiguration.RegisterMutatingHandler(mgr)
-	component.RegisterMutatingHandler(mgr)
-	component.RegisterValidatingHandler(mgr)
 	return nil
 }
@@ -0,0 +1,190 @@
+package v1alpha2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1beta1"
+)
+
+func TestOptionsHandler(t *testing.T) {
+	errBoom := errors.New("boom")
+	getNamespace := func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		if key.Name == "selected" {
+			obj.(*corev1.Namespace).SetLabels(map[string]string{"oam": "enabled"})
+		}
+		return nil
+	}
+	denied := admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+		return admission.Denied("denied")
+	})
+	errored := admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+		return admission.Errored(http.StatusInternalServerError, errBoom)
+	})
+	request := func(namespace string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{Namespace: namespace}}
+	}
+
+	tests := []struct {
+		caseName      string
+		handler       admission.Handler
+		failurePolicy admissionregistrationv1beta1.FailurePolicyType
+		selector      labels.Selector
+		mockGet       test.MockGetFn
+		req           admission.Request
+		pass          bool
+	}{
+		{
+			caseName: "Test requests are handled by the wrapped handler",
+			handler:  denied,
+			req:      request("default"),
+			pass:     false,
+		},
+		{
+			caseName: "Test errors fail requests by default",
+			handler:  errored,
+			req:      request("default"),
+			pass:     false,
+		},
+		{
+			caseName:      "Test errors admit requests if the failure policy is Ignore",
+			handler:       errored,
+			failurePolicy: admissionregistrationv1beta1.Ignore,
+			req:           request("default"),
+			pass:          true,
+		},
+		{
+			caseName:      "Test denied requests are denied even if the failure policy is Ignore",
+			handler:       denied,
+			failurePolicy: admissionregistrationv1beta1.Ignore,
+			req:           request("default"),
+			pass:          false,
+		},
+		{
+			caseName: "Test requests in selected namespaces are handled",
+			handler:  denied,
+			selector: labels.SelectorFromSet(labels.Set{"oam": "enabled"}),
+			mockGet:  getNamespace,
+			req:      request("selected"),
+			pass:     false,
+		},
+		{
+			caseName: "Test requests in other namespaces are admitted",
+			handler:  denied,
+			selector: labels.SelectorFromSet(labels.Set{"oam": "enabled"}),
+			mockGet:  getNamespace,
+			req:      request("other"),
+			pass:     true,
+		},
+		{
+			caseName: "Test requests for cluster scoped resources are handled",
+			handler:  denied,
+			selector: labels.SelectorFromSet(labels.Set{"oam": "enabled"}),
+			mockGet:  getNamespace,
+			req:      request(""),
+			pass:     false,
+		},
+		{
+			caseName: "Test errors getting the namespace fail requests",
+			handler:  denied,
+			selector: labels.Everything(),
+			mockGet:  test.NewMockGetFn(errBoom),
+			req:      request("default"),
+			pass:     false,
+		},
+	}
+	for _, tc := range tests {
+		h := &optionsHandler{
+			Handler:           tc.handler,
+			Client:            &test.MockClient{MockGet: tc.mockGet},
+			FailurePolicy:     tc.failurePolicy,
+			NamespaceSelector: tc.selector,
+		}
+		resp := h.Handle(context.Background(), tc.req)
+		assert.Equal(t, tc.pass, resp.Allowed, fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	r := DefaultRegistry()
+	seen := map[string]bool{}
+	for _, h := range r.handlers {
+		assert.False(t, seen[h.name], fmt.Sprintf("handler %q should be registered once", h.name))
+		assert.NotEmpty(t, h.path, fmt.Sprintf("handler %q should have a default path", h.name))
+		seen[h.name] = true
+	}
+	assert.Equal(t, len(r.handlers), len(r.Names()))
+}
+
+func TestRegistry(t *testing.T) {
+	noop := func(manager.Manager, Options) (admission.Handler, error) { return nil, nil }
+	wrap := func(_ manager.Manager, _ Options, h admission.Handler) (admission.Handler, error) { return h, nil }
+
+	r := NewRegistry()
+	assert.NoError(t, r.Register("custom", "/validating-custom", noop))
+	assert.EqualError(t, r.Register("custom", "/validating-custom", noop), fmt.Sprintf(errFmtHandlerRegistered, "custom"))
+	assert.EqualError(t, r.Register(ConversionHandler, ConversionPath, noop), fmt.Sprintf(errFmtHandlerRegistered, ConversionHandler))
+	assert.NoError(t, r.Wrap("custom", wrap))
+	assert.NoError(t, r.Wrap("custom", wrap))
+	assert.EqualError(t, r.Wrap("unknown", wrap), fmt.Sprintf(errFmtHandlerNotRegistered, "unknown"))
+	assert.Equal(t, []string{"custom"}, r.Names())
+	assert.Len(t, r.get("custom").wrappers, 2)
+}
+
+func TestOptionsHandlerInjectFunc(t *testing.T) {
+	inner := admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+		return admission.Allowed("")
+	})
+	outer := admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+		return admission.Allowed("")
+	})
+	var injected int
+	h := &optionsHandler{Handler: outer, Wrapped: []admission.Handler{inner}}
+	assert.NoError(t, h.InjectFunc(func(interface{}) error {
+		injected++
+		return nil
+	}))
+	assert.Equal(t, 2, injected)
+}
+
+func TestConvertible(t *testing.T) {
+	s := runtime.NewScheme()
+	assert.NoError(t, core.AddToScheme(s))
+	for _, obj := range []runtime.Object{
+		&v1alpha2.Component{}, &v1beta1.Component{},
+		&v1alpha2.ApplicationConfiguration{}, &v1beta1.ApplicationConfiguration{},
+	} {
+		ok, err := conversion.IsConvertible(s, obj)
+		assert.NoError(t, err)
+		assert.True(t, ok, fmt.Sprintf("%T should be convertible", obj))
+	}
+
+	in := &v1beta1.ApplicationConfiguration{}
+	in.SetName("example-appconfig")
+	in.Spec.Components = []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "example-component"}}
+	hub := &v1alpha2.ApplicationConfiguration{}
+	assert.NoError(t, in.ConvertTo(hub))
+	assert.Equal(t, v1alpha2.ApplicationConfigurationGroupVersionKind, hub.GroupVersionKind())
+	out := &v1beta1.ApplicationConfiguration{}
+	assert.NoError(t, out.ConvertFrom(hub))
+	assert.Equal(t, v1beta1.ApplicationConfigurationGroupVersionKind, out.GroupVersionKind())
+	out.TypeMeta = in.TypeMeta
+	assert.Equal(t, in, out, "conversion through the hub should round trip")
+}
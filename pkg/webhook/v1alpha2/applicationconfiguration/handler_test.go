@@ -10,9 +10,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
@@ -171,6 +173,62 @@ var _ = Describe("ApplicationConfiguration Admission controller Test", func() {
 					trait:  traitWithType.DeepCopyObject(),
 					wanted: util.JSONMarshal(mutatedTrait),
 				},
+				"expand shorthand case": {
+					client: &test.MockClient{
+						MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha2.TraitDefinition:
+								Expect(key.Name).Should(Equal(traitTypeName))
+								*o = traitDef
+							case *crdv1.CustomResourceDefinition:
+								*o = crd
+							}
+							return nil
+						},
+					},
+					trait: map[string]interface{}{traitTypeName: map[string]interface{}{"key": "test"}},
+					wanted: util.JSONMarshal(&unstructured.Unstructured{Object: map[string]interface{}{
+						"apiVersion": baseTrait.GetAPIVersion(),
+						"kind":       baseTrait.GetKind(),
+						"metadata": map[string]interface{}{
+							"labels": map[string]interface{}{oam.TraitTypeLabel: traitTypeName},
+						},
+						"spec": map[string]interface{}{"key": "test"},
+					}}),
+				},
+				"resolve alias case": {
+					client: &test.MockClient{
+						MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha2.TraitDefinition:
+								return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+							case *crdv1.CustomResourceDefinition:
+								*o = crd
+							}
+							return nil
+						},
+						MockList: func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+							td := traitDef.DeepCopy()
+							td.Spec.Aliases = []string{"scaler"}
+							list.(*v1alpha2.TraitDefinitionList).Items = []v1alpha2.TraitDefinition{*td}
+							return nil
+						},
+					},
+					trait: func() runtime.Object {
+						t := traitWithType.DeepCopy()
+						t.Object[TraitTypeField] = "scaler"
+						return t
+					}(),
+					wanted: util.JSONMarshal(mutatedTrait),
+				},
+				"unknown alias case": {
+					client: &test.MockClient{
+						MockGet:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "scaler")),
+						MockList: test.NewMockListFn(nil),
+					},
+					trait:  map[string]interface{}{"scaler": nil},
+					errMsg: "not found",
+				},
 			}
 			for testCase, test := range tests {
 				By(fmt.Sprintf("start test : %s", testCase))
@@ -178,7 +236,7 @@ var _ = Describe("ApplicationConfiguration Admission controller Test", func() {
 				injc := handler.(inject.Client)
 				injc.InjectClient(test.client)
 				mutatingHandler := handler.(*MutatingHandler)
-				err := mutatingHandler.Mutate(&appConfig)
+				err := mutatingHandler.Mutate(context.TODO(), &appConfig)
 				if len(test.errMsg) == 0 {
 					Expect(err).Should(BeNil())
 					Expect(appConfig.Spec.Components[0].Traits[0].Trait.Raw).Should(BeEquivalentTo(test.wanted))
@@ -187,6 +245,93 @@ var _ = Describe("ApplicationConfiguration Admission controller Test", func() {
 				}
 			}
 		})
+
+		It("Test pin component revisions", func() {
+			appConfig.Spec.Components[0].Traits = nil
+			appConfig.Spec.Components = append(appConfig.Spec.Components,
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "new-comp"},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "missing-comp"},
+				v1alpha2.ApplicationConfigurationComponent{RevisionName: "other-comp-v1"})
+			latest := "example-comp-v2"
+			injc := handler.(inject.Client)
+			injc.InjectClient(&test.MockClient{
+				MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+					o, ok := obj.(*v1alpha2.Component)
+					Expect(ok).Should(BeTrue())
+					Expect(key.Name).ShouldNot(Equal("other-comp-v1"))
+					switch key.Name {
+					case "example-comp":
+						o.Status.LatestRevision = &v1alpha2.Revision{Name: latest}
+					case "missing-comp":
+						return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+					}
+					return nil
+				},
+			})
+			mutatingHandler := handler.(*MutatingHandler)
+
+			By("Not pinning revisions without the pin revisions annotation")
+			Expect(mutatingHandler.Mutate(context.TODO(), &appConfig)).Should(BeNil())
+			Expect(appConfig.GetAnnotations()).ShouldNot(HaveKey(oam.AnnotationPinnedRevisions))
+
+			By("Pinning the latest revision of components that have one, skipping components that do not exist")
+			appConfig.SetAnnotations(map[string]string{oam.AnnotationPinRevisions: "true"})
+			Expect(mutatingHandler.Mutate(context.TODO(), &appConfig)).Should(BeNil())
+			Expect(appConfig.GetAnnotations()).Should(HaveKeyWithValue(oam.AnnotationPinnedRevisions, `{"example-comp":"example-comp-v2"}`))
+			pinned, err := util.PinnedRevisionNames(&appConfig)
+			Expect(err).Should(BeNil())
+			Expect(pinned).Should(Equal(map[string]string{"example-comp": "example-comp-v2"}))
+
+			By("Keeping the pinned revision when the component has a newer one")
+			latest = "example-comp-v3"
+			Expect(mutatingHandler.Mutate(context.TODO(), &appConfig)).Should(BeNil())
+			Expect(appConfig.GetAnnotations()).Should(HaveKeyWithValue(oam.AnnotationPinnedRevisions, `{"example-comp":"example-comp-v2"}`))
+
+			By("Unpinning components that are removed")
+			appConfig.Spec.Components = appConfig.Spec.Components[1:]
+			Expect(mutatingHandler.Mutate(context.TODO(), &appConfig)).Should(BeNil())
+			Expect(appConfig.GetAnnotations()).Should(HaveKeyWithValue(oam.AnnotationPinnedRevisions, `{}`))
+
+			By("Failing when a component cannot be fetched")
+			injc.InjectClient(&test.MockClient{MockGet: test.NewMockGetFn(fmt.Errorf("boom"))})
+			err = mutatingHandler.Mutate(context.TODO(), &appConfig)
+			Expect(err).ShouldNot(BeNil())
+			Expect(err.Error()).Should(Equal(`cannot get component "new-comp": boom`))
+		})
+
+		It("Test keep pinned revisions on update", func() {
+			appConfig.Spec.Components[0].Traits = nil
+			injc := handler.(inject.Client)
+			injc.InjectClient(&test.MockClient{
+				MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+					obj.(*v1alpha2.Component).Status.LatestRevision = &v1alpha2.Revision{Name: "example-comp-v3"}
+					return nil
+				},
+			})
+			old := appConfig.DeepCopy()
+			old.SetAnnotations(map[string]string{
+				oam.AnnotationPinRevisions:    "true",
+				oam.AnnotationPinnedRevisions: `{"example-comp":"example-comp-v2"}`,
+			})
+			// the update omits the pinned revisions
+			appConfig.SetAnnotations(map[string]string{oam.AnnotationPinRevisions: "true"})
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Update,
+					Object:    runtime.RawExtension{Raw: util.JSONMarshal(appConfig)},
+					OldObject: runtime.RawExtension{Raw: util.JSONMarshal(old)},
+				},
+			}
+			resp := handler.Handle(context.TODO(), req)
+			Expect(resp.Allowed).Should(BeTrue())
+			var pinned interface{}
+			for _, patch := range resp.Patches {
+				if patch.Path == "/metadata/annotations/app.oam.dev~1pinned-revisions" {
+					pinned = patch.Value
+				}
+			}
+			Expect(pinned).Should(Equal(`{"example-comp":"example-comp-v2"}`))
+		})
 	})
 
 	It("Test validating handler", func() {
@@ -229,6 +374,7 @@ var _ = Describe("ApplicationConfiguration Admission controller Test", func() {
 				}
 				return nil
 			},
+			MockList: test.NewMockListFn(nil),
 		}
 		tests := map[string]struct {
 			trait     interface{}
@@ -20,9 +20,11 @@ const (
 	errFmtGetTraitDefinition = "cannot find trait definition %q %q %q"
 )
 
-// checkComponentVersionEnabled check whethter a component is versioning mechanism enabled
+// checkComponentVersionEnabled check whethter a component of an
+// ApplicationConfiguration of the supplied namespace is versioning mechanism
+// enabled
 func checkComponentVersionEnabled(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
-	acc *v1alpha2.ApplicationConfigurationComponent) (bool, error) {
+	namespace string, acc *v1alpha2.ApplicationConfigurationComponent) (bool, error) {
 	if acc.RevisionName != "" {
 		return true, nil
 	}
@@ -31,6 +33,7 @@ func checkComponentVersionEnabled(ctx context.Context, client client.Reader, dm
 		if err := json.Unmarshal(ct.Trait.Raw, ut); err != nil {
 			return false, errors.Wrap(err, errUnmarshalTrait)
 		}
+		ut.SetNamespace(namespace)
 		td, err := util.FetchTraitDefinition(ctx, client, dm, ut)
 		if err != nil && !apierrors.IsNotFound(err) {
 			return false, errors.Wrapf(err, errFmtGetTraitDefinition, ut.GetAPIVersion(), ut.GetKind(), ut.GetName())
@@ -63,3 +66,21 @@ func checkParams(cp []v1alpha2.ComponentParameter, cpv []v1alpha2.ComponentParam
 	}
 	return true, ""
 }
+
+// checkRequiredParams will check whether every required parameter without a default value is assigned
+func checkRequiredParams(cp []v1alpha2.ComponentParameter, cpv []v1alpha2.ComponentParameterValue) (bool, string) {
+	assigned := make(map[string]bool)
+	for _, v := range cpv {
+		assigned[v.Name] = true
+	}
+	for _, v := range cp {
+		if v.Required == nil || !*v.Required || v.Default != nil {
+			continue
+		}
+		if !assigned[v.Name] {
+			// check fails if a required parameter has neither a value nor a default
+			return false, v.Name
+		}
+	}
+	return true, ""
+}
@@ -104,7 +104,7 @@ func TestCheckComponentVersionEnabled(t *testing.T) {
 	for _, tv := range tests {
 		func(t *testing.T) {
 			mockClient.MockGet = tv.mockGetFun
-			result, _ := checkComponentVersionEnabled(ctx, mockClient, mapper, &tv.acc)
+			result, _ := checkComponentVersionEnabled(ctx, mockClient, mapper, "default", &tv.acc)
 			assert.Equal(t, tv.result, result, fmt.Sprintf("Test case: %q", tv.caseName))
 		}(t)
 	}
@@ -168,3 +168,61 @@ func TestCheckParams(t *testing.T) {
 
 	}
 }
+
+func TestCheckRequiredParams(t *testing.T) {
+	required := true
+	pName := "requiredParam"
+	defaultValue := intstr.FromString("default")
+	requiredParam := v1alpha2.ComponentParameter{
+		Name:     pName,
+		Required: &required,
+	}
+	requiredParamWithDefault := v1alpha2.ComponentParameter{
+		Name:     pName,
+		Required: &required,
+		Default:  &defaultValue,
+	}
+	optionalParam := v1alpha2.ComponentParameter{
+		Name: pName,
+	}
+	paramValue := v1alpha2.ComponentParameterValue{
+		Name:  pName,
+		Value: intstr.FromString("value"),
+	}
+	tests := []struct {
+		caseName        string
+		cps             []v1alpha2.ComponentParameter
+		cpvs            []v1alpha2.ComponentParameterValue
+		expectResult    bool
+		expectParamName string
+	}{
+		{
+			caseName:        "required param is not assigned",
+			cps:             []v1alpha2.ComponentParameter{requiredParam},
+			expectResult:    false,
+			expectParamName: pName,
+		},
+		{
+			caseName:     "required param is assigned",
+			cps:          []v1alpha2.ComponentParameter{requiredParam},
+			cpvs:         []v1alpha2.ComponentParameterValue{paramValue},
+			expectResult: true,
+		},
+		{
+			caseName:     "required param has a default value",
+			cps:          []v1alpha2.ComponentParameter{requiredParamWithDefault},
+			expectResult: true,
+		},
+		{
+			caseName:     "optional param is not assigned",
+			cps:          []v1alpha2.ComponentParameter{optionalParam},
+			expectResult: true,
+		},
+	}
+
+	for _, tc := range tests {
+		result, paramName := checkRequiredParams(tc.cps, tc.cpvs)
+		assert.Equal(t, tc.expectResult, result, fmt.Sprintf("test case: %v", tc.caseName))
+		assert.Equal(t, tc.expectParamName, paramName, fmt.Sprintf("test case: %v", tc.caseName))
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"reflect"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/pkg/errors"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,12 +19,16 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
+)
+
+const (
+	errFmtGetComponent = "cannot get component %q"
 )
 
 const (
@@ -31,6 +38,16 @@ const (
 	TraitSpecField = "properties"
 )
 
+// reservedTraitFields may not be the type of a trait in shorthand syntax.
+var reservedTraitFields = map[string]bool{
+	"apiVersion":   true,
+	"kind":         true,
+	"metadata":     true,
+	"spec":         true,
+	TraitTypeField: true,
+	TraitSpecField: true,
+}
+
 // MutatingHandler handles Component
 type MutatingHandler struct {
 	Client client.Client
@@ -52,8 +69,16 @@ func (h *MutatingHandler) Handle(ctx context.Context, req admission.Request) adm
 	if err != nil {
 		return admission.Errored(http.StatusBadRequest, err)
 	}
+	// revisions that were pinned before stay pinned, even if the update omits them
+	if req.Operation == admissionv1beta1.Update && len(req.OldObject.Raw) > 0 {
+		old := &v1alpha2.ApplicationConfiguration{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		keepPinnedRevisions(obj, old)
+	}
 	// mutate the object
-	if err := h.Mutate(obj); err != nil {
+	if err := h.Mutate(ctx, obj); err != nil {
 		mutatelog.Error(err, "failed to mutate the applicationConfiguration", "name", obj.Name)
 		return admission.Errored(http.StatusBadRequest, err)
 	}
@@ -73,7 +98,7 @@ func (h *MutatingHandler) Handle(ctx context.Context, req admission.Request) adm
 }
 
 // Mutate sets all the default value for the Component
-func (h *MutatingHandler) Mutate(obj *v1alpha2.ApplicationConfiguration) error {
+func (h *MutatingHandler) Mutate(ctx context.Context, obj *v1alpha2.ApplicationConfiguration) error {
 	mutatelog.Info("mutate", "name", obj.Name)
 
 	for compIdx, comp := range obj.Spec.Components {
@@ -83,7 +108,7 @@ func (h *MutatingHandler) Mutate(obj *v1alpha2.ApplicationConfiguration) error {
 			if err := json.Unmarshal(tr.Trait.Raw, &content); err != nil {
 				return err
 			}
-			rawByte, mutated, err := h.mutateTrait(content, comp.ComponentName)
+			rawByte, mutated, err := h.mutateTrait(ctx, content, comp.ComponentName)
 			if err != nil {
 				return err
 			}
@@ -99,10 +124,69 @@ func (h *MutatingHandler) Mutate(obj *v1alpha2.ApplicationConfiguration) error {
 		}
 	}
 
+	return h.pinRevisions(ctx, obj)
+}
+
+// pinRevisions pins every component the ApplicationConfiguration refers to
+// by name to its latest revision, if it asks its components to be pinned.
+// Components stay pinned to the revision they were first pinned to, and are
+// unpinned when they are removed from the ApplicationConfiguration.
+// Components that do not exist or have no revision yet are pinned once they
+// have one.
+func (h *MutatingHandler) pinRevisions(ctx context.Context, obj *v1alpha2.ApplicationConfiguration) error {
+	if obj.GetAnnotations()[oam.AnnotationPinRevisions] != "true" {
+		return nil
+	}
+	existing, err := util.PinnedRevisionNames(obj)
+	if err != nil {
+		return err
+	}
+	pinned := make(map[string]string)
+	for _, acc := range obj.Spec.Components {
+		if acc.ComponentName == "" {
+			continue
+		}
+		if rev, ok := existing[acc.ComponentName]; ok {
+			pinned[acc.ComponentName] = rev
+			continue
+		}
+		c := &v1alpha2.Component{}
+		err := h.Client.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: acc.ComponentName}, c)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetComponent, acc.ComponentName)
+		}
+		if c.Status.LatestRevision == nil {
+			continue
+		}
+		pinned[acc.ComponentName] = c.Status.LatestRevision.Name
+	}
+	raw, err := json.Marshal(pinned)
+	if err != nil {
+		return err
+	}
+	mutatelog.Info("pin component revisions", "name", obj.Name, "revisions", string(raw))
+	obj.SetAnnotations(util.MergeMap(obj.GetAnnotations(), map[string]string{oam.AnnotationPinnedRevisions: string(raw)}))
 	return nil
 }
 
-func (h *MutatingHandler) mutateTrait(content map[string]interface{}, compName string) ([]byte, bool, error) {
+// keepPinnedRevisions copies the pinned revisions of the supplied old
+// ApplicationConfiguration to the supplied one, unless it records its own.
+func keepPinnedRevisions(obj, old *v1alpha2.ApplicationConfiguration) {
+	if _, ok := obj.GetAnnotations()[oam.AnnotationPinnedRevisions]; ok {
+		return
+	}
+	raw, ok := old.GetAnnotations()[oam.AnnotationPinnedRevisions]
+	if !ok {
+		return
+	}
+	obj.SetAnnotations(util.MergeMap(obj.GetAnnotations(), map[string]string{oam.AnnotationPinnedRevisions: raw}))
+}
+
+func (h *MutatingHandler) mutateTrait(ctx context.Context, content map[string]interface{}, compName string) ([]byte, bool, error) {
+	expandShorthand(content)
 	if content[TraitTypeField] == nil {
 		return nil, false, nil
 	}
@@ -111,14 +195,13 @@ func (h *MutatingHandler) mutateTrait(content map[string]interface{}, compName s
 		return nil, false, fmt.Errorf("name of trait should be string instead of %s", reflect.TypeOf(content[TraitTypeField]))
 	}
 	mutatelog.Info("the trait refers to traitDefinition by name", "compName", compName, "trait name", traitType)
-	// Fetch the corresponding traitDefinition CR, the traitDefinition crd is cluster scoped
-	traitDefinition := &v1alpha2.TraitDefinition{}
-	if err := h.Client.Get(context.TODO(), types.NamespacedName{Name: traitType}, traitDefinition); err != nil {
+	traitDefinition, err := h.fetchTraitDefinition(ctx, traitType)
+	if err != nil {
 		return nil, false, err
 	}
 	// fetch the CRDs definition
 	customResourceDefinition := &crdv1.CustomResourceDefinition{}
-	if err := h.Client.Get(context.TODO(), types.NamespacedName{Name: traitDefinition.Spec.Reference.Name}, customResourceDefinition); err != nil {
+	if err := h.Client.Get(ctx, types.NamespacedName{Name: traitDefinition.Spec.Reference.Name}, customResourceDefinition); err != nil {
 		return nil, false, err
 	}
 	// reconstruct the trait CR
@@ -133,15 +216,20 @@ func (h *MutatingHandler) mutateTrait(content map[string]interface{}, compName s
 		Object: content,
 	}
 	// find out the GVK from the CRD definition and set
+	version, err := util.DefinitionVersion(customResourceDefinition, traitDefinition.Spec.Reference)
+	if err != nil {
+		return nil, false, err
+	}
 	apiVersion := metav1.GroupVersion{
 		Group:   customResourceDefinition.Spec.Group,
-		Version: customResourceDefinition.Spec.Versions[0].Name,
+		Version: version,
 	}.String()
 	trait.SetAPIVersion(apiVersion)
 	trait.SetKind(customResourceDefinition.Spec.Names.Kind)
 	mutatelog.Info("Set the trait GVK", "trait api version", trait.GetAPIVersion(), "trait Kind", trait.GetKind())
-	// add traitType label
-	trait.SetLabels(util.MergeMap(trait.GetLabels(), map[string]string{oam.TraitTypeLabel: traitType}))
+	// add traitType label, traits referred to by an alias are labelled with
+	// the name of their traitDefinition
+	trait.SetLabels(util.MergeMap(trait.GetLabels(), map[string]string{oam.TraitTypeLabel: traitDefinition.GetName()}))
 	// copy back the object
 	rawBye, err := json.Marshal(trait.Object)
 	if err != nil {
@@ -150,6 +238,52 @@ func (h *MutatingHandler) mutateTrait(content map[string]interface{}, compName s
 	return rawBye, true, nil
 }
 
+// expandShorthand expands a trait in shorthand syntax, e.g. scaler: {replicas: 3},
+// into one that refers to its traitDefinition by name, e.g. name: scaler,
+// properties: {replicas: 3}. Other traits are left as they are.
+func expandShorthand(content map[string]interface{}) {
+	if len(content) != 1 {
+		return
+	}
+	for k, v := range content {
+		if reservedTraitFields[k] {
+			return
+		}
+		if _, ok := v.(map[string]interface{}); !ok && v != nil {
+			return
+		}
+		delete(content, k)
+		content[TraitTypeField] = k
+		if v != nil {
+			content[TraitSpecField] = v
+		}
+	}
+}
+
+// fetchTraitDefinition fetches the traitDefinition with the supplied name, or
+// the one that has it as an alias if there is none
+func (h *MutatingHandler) fetchTraitDefinition(ctx context.Context, name string) (*v1alpha2.TraitDefinition, error) {
+	// the traitDefinition crd is cluster scoped
+	td := &v1alpha2.TraitDefinition{}
+	err := h.Client.Get(ctx, types.NamespacedName{Name: name}, td)
+	if !apierrors.IsNotFound(err) {
+		return td, err
+	}
+	l := &v1alpha2.TraitDefinitionList{}
+	if lerr := h.Client.List(ctx, l); lerr != nil {
+		return nil, lerr
+	}
+	for i := range l.Items {
+		for _, alias := range l.Items[i].Spec.Aliases {
+			if alias == name {
+				mutatelog.Info("the trait refers to traitDefinition by alias", "alias", name, "traitDefinition", l.Items[i].GetName())
+				return &l.Items[i], nil
+			}
+		}
+	}
+	return nil, err
+}
+
 var _ inject.Client = &MutatingHandler{}
 
 // InjectClient injects the client into the ComponentMutatingHandler
@@ -166,8 +300,12 @@ func (h *MutatingHandler) InjectDecoder(d *admission.Decoder) error {
 	return nil
 }
 
+// MutatingHandlerPath is the path application configuration mutation is
+// served at by default
+const MutatingHandlerPath = "/mutating-core-oam-dev-v1alpha2-applicationconfigurations"
+
 // RegisterMutatingHandler will register component mutation handler to the webhook
 func RegisterMutatingHandler(mgr manager.Manager) {
 	server := mgr.GetWebhookServer()
-	server.Register("/mutating-core-oam-dev-v1alpha2-applicationconfigurations", &webhook.Admission{Handler: &MutatingHandler{}})
+	server.Register(MutatingHandlerPath, review.New(&MutatingHandler{}))
 }
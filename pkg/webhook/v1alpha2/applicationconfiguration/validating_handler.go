@@ -5,20 +5,34 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	controller "github.com/crossplane/oam-kubernetes-runtime/pkg/controller/v1alpha2/applicationconfiguration"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/policy"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/quota"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/render"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
 
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/pkg/errors"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -29,17 +43,129 @@ const (
 
 	errFmtUnmarshalWorkload = "Error occurs when unmarshal workload of component %q error: %q"
 
+	reasonFmtRequiredParamMissing = "Required parameter %q of component %q MUST be assigned a value."
+
+	errFmtCheckRequiredParams = "Error occurs when checking required parameters. %q"
+
+	reasonFmtInvalidParamValues = "Parameter values MUST match the type, enum and range of their parameters. %s"
+
+	errFmtCheckParamValues = "Error occurs when checking parameter values. %q"
+
+	reasonFmtComponentNotFound = "Component %q referenced by spec.components[%d] MUST exist in namespace %q."
+
+	reasonFmtRevisionNotFound = "Component revision %q referenced by spec.components[%d] MUST exist in namespace %q."
+
+	reasonFmtTraitDefinitionNotFound = "Trait %q %q of spec.components[%d].traits[%d] MUST have a TraitDefinition."
+
+	reasonFmtScopeDefinitionNotFound = "Scope %q %q %q of spec.components[%d].scopes[%d] MUST have a ScopeDefinition."
+
+	errFmtCheckReferences = "Error occurs when checking references. %q"
+
+	reasonFmtWorkloadDefinitionNotAllowed = "WorkloadDefinition %q of component %q referenced by spec.components[%d] MUST be allowed by DefinitionUsagePolicy %q."
+
+	reasonFmtTraitDefinitionNotAllowed = "TraitDefinition %q of spec.components[%d].traits[%d] MUST be allowed by DefinitionUsagePolicy %q."
+
+	errFmtCheckDefinitionUsage = "Error occurs when checking definition usage policies. %q"
+
+	reasonFmtDuplicateComponent = "Component %q of spec.components[%d] MUST NOT be listed more than once, it is also listed by spec.components[%d]."
+
+	reasonFmtDuplicateTrait = "TraitDefinition %q of spec.components[%d].traits[%d] MUST NOT apply more than once to the same workload, it also applies by spec.components[%d].traits[%d]."
+
+	reasonFmtTraitConflict = "TraitDefinition %q of spec.components[%d].traits[%d] conflicts with TraitDefinition %q of spec.components[%d].traits[%d], they MUST NOT apply to the same workload."
+
+	errFmtCheckTraitConflicts = "Error occurs when checking trait conflicts. %q"
+
+	reasonFmtTraitNotApplies = "TraitDefinition %q of spec.components[%d].traits[%d] MUST apply to workload %q %q, it only applies to %v."
+
+	errFmtCheckTraitsApply = "Error occurs when checking the workloads traits apply to. %q"
+
+	reasonFmtScopeNotApplies = "ScopeDefinition %q of spec.components[%d].scopes[%d] MUST apply to workload %q %q, it only applies to %v."
+
+	errFmtCheckScopesApply = "Error occurs when checking the workloads scopes apply to. %q"
+
+	reasonFmtScopeOverlap = "ScopeDefinition %q of spec.components[%d].scopes[%d] MUST allow component overlap, the component is also in scope %q of spec.components[%d].scopes[%d]."
+
+	errFmtCheckScopeOverlap = "Error occurs when checking overlapping scopes. %q"
+
+	reasonFmtRenderFailed = "ApplicationConfiguration MUST render successfully. %q"
+
+	reasonFmtTooManyComponents = "ApplicationConfiguration MUST NOT have more than %d components, it has %d."
+
+	reasonFmtTooManyTraits = "Component %q of spec.components[%d] MUST NOT have more than %d traits, it has %d."
+
+	reasonFmtObjectTooLarge = "Rendered %q %q %q MUST NOT be larger than %d bytes, it is %d bytes."
+
+	errFmtCheckObjectSize = "Error occurs when checking the size of rendered objects. %q"
+
+	reasonFmtPolicyViolations = "ApplicationConfiguration MUST satisfy the admission policies. %s"
+
+	errFmtCheckPolicies = "Error occurs when evaluating admission policies. %q"
+
+	reasonFmtDeprecatedDefinition = "ApplicationConfiguration MUST NOT start using deprecated definitions. %s"
+
+	reasonFmtResourceQuotaExceeded = "ResourceQuotaScope %q of spec.components[%d].scopes[%d] MUST NOT exceed its hard limits, its workloads would use %s."
+
+	errFmtCheckResourceQuotas = "Error occurs when checking resource quota scopes. %q"
+
 	// WorkloadNamePath indicates field path of workload name
 	WorkloadNamePath = "metadata.name"
 )
 
 var appConfigResource = v1alpha2.SchemeGroupVersion.WithResource("applicationconfigurations")
 
+// A DryRunRenderer renders an ApplicationConfiguration without applying it.
+type DryRunRenderer interface {
+	Render(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) ([]*unstructured.Unstructured, error)
+}
+
+// A DryRunRenderFn renders an ApplicationConfiguration without applying it.
+type DryRunRenderFn func(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) ([]*unstructured.Unstructured, error)
+
+// Render the supplied ApplicationConfiguration.
+func (fn DryRunRenderFn) Render(ctx context.Context, ac *v1alpha2.ApplicationConfiguration) ([]*unstructured.Unstructured, error) {
+	return fn(ctx, ac)
+}
+
+// Limits on the size of ApplicationConfigurations, that protect the
+// controller and the API server from pathological applications. Limits that
+// are zero are not enforced.
+type Limits struct {
+	// MaxComponents is the maximum number of components of an
+	// ApplicationConfiguration.
+	MaxComponents int
+
+	// MaxTraitsPerComponent is the maximum number of traits of a component of
+	// an ApplicationConfiguration.
+	MaxTraitsPerComponent int
+
+	// MaxRenderedObjectSize is the maximum size in bytes of each object an
+	// ApplicationConfiguration renders to, e.g. a workload or trait. It is
+	// only enforced if ApplicationConfigurations are rendered in dry-run.
+	MaxRenderedObjectSize int
+}
+
 // ValidatingHandler handles CloneSet
 type ValidatingHandler struct {
 	Client client.Client
 	Mapper discoverymapper.DiscoveryMapper
 
+	// Renderer renders ApplicationConfigurations in dry-run, so that errors
+	// that would fail their reconciliation are returned at admission. No
+	// dry-run render happens if it is nil.
+	Renderer DryRunRenderer
+
+	// Policies are evaluated against the resources ApplicationConfigurations
+	// render to in dry-run, and their violations deny admission. No policies
+	// are evaluated if it or the Renderer is nil.
+	Policies policy.Evaluator
+
+	// Limits on the size of ApplicationConfigurations.
+	Limits Limits
+
+	// RejectDeprecated rejects ApplicationConfigurations that start using
+	// deprecated TraitDefinitions, rather than warning about them.
+	RejectDeprecated bool
+
 	// Decoder decodes objects
 	Decoder *admission.Decoder
 }
@@ -71,13 +197,70 @@ func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) a
 			klog.Info("create or update failed", "name", obj.Name, "errMsg", allErrs.ToAggregate().Error())
 			return admission.Denied(allErrs.ToAggregate().Error())
 		}
+		if pass, reason := checkLimits(h.Limits, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
 		if pass, reason := checkRevisionName(obj); !pass {
 			return admission.ValidationResponse(false, reason)
 		}
+		if pass, reason := checkDuplicateComponents(obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if pass, reason := checkReferences(ctx, h.Client, h.Mapper, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if pass, reason := checkDefinitionUsage(ctx, h.Client, h.Mapper, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if pass, reason := checkTraitConflicts(ctx, h.Client, h.Mapper, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if pass, reason := checkTraitsApplyToWorkloads(ctx, h.Client, h.Mapper, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if pass, reason := checkScopesApplyToWorkloads(ctx, h.Client, h.Mapper, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if pass, reason := checkScopeOverlap(ctx, h.Client, h.Mapper, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
 		if pass, reason := checkWorkloadNameForVersioning(ctx, h.Client, h.Mapper, obj); !pass {
 			return admission.ValidationResponse(false, reason)
 		}
+		if pass, reason := checkRequiredParamsAssigned(ctx, h.Client, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if pass, reason := checkParamValues(ctx, h.Client, obj); !pass {
+			return admission.ValidationResponse(false, reason)
+		}
+		if h.Renderer != nil {
+			resources, err := h.Renderer.Render(ctx, obj)
+			if err != nil {
+				return admission.ValidationResponse(false, fmt.Sprintf(reasonFmtRenderFailed, err.Error()))
+			}
+			if pass, reason := checkRenderedObjectSize(h.Limits, resources); !pass {
+				return admission.ValidationResponse(false, reason)
+			}
+			if pass, reason := checkPolicies(ctx, h.Policies, obj, resources); !pass {
+				return admission.ValidationResponse(false, reason)
+			}
+			if pass, reason := checkResourceQuotaScopes(ctx, h.Client, h.Mapper, obj, resources); !pass {
+				return admission.ValidationResponse(false, reason)
+			}
+		}
+		var old *v1alpha2.ApplicationConfiguration
+		if req.Operation == admissionv1beta1.Update && len(req.OldObject.Raw) != 0 {
+			old = &v1alpha2.ApplicationConfiguration{}
+			if err := h.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+		}
+		warnings, pass, reason := checkDeprecatedDefinitions(ctx, h.Client, h.Mapper, h.RejectDeprecated, obj, old)
+		if !pass {
+			return admission.ValidationResponse(false, reason)
+		}
 		// TODO(wonderflow): Add more validation logic here.
+		return review.WithWarnings(admission.ValidationResponse(true, ""), warnings...)
 	}
 	return admission.ValidationResponse(true, "")
 }
@@ -89,26 +272,20 @@ func ValidateTraitObject(obj *v1alpha2.ApplicationConfiguration) field.ErrorList
 	for cidx, comp := range obj.Spec.Components {
 		for idx, tr := range comp.Traits {
 			fldPath := field.NewPath("spec").Child("components").Index(cidx).Child("traits").Index(idx).Child("trait")
-			var content map[string]interface{}
-			if err := json.Unmarshal(tr.Trait.Raw, &content); err != nil {
-				allErrs = append(allErrs, field.Invalid(fldPath, string(tr.Trait.Raw),
-					"the trait is malformed"))
+			errs, ok := validateTrait(fldPath, tr)
+			allErrs = append(allErrs, errs...)
+			if !ok {
 				return allErrs
 			}
-			if content[TraitTypeField] != nil {
-				allErrs = append(allErrs, field.Invalid(fldPath, string(tr.Trait.Raw),
-					"the trait contains 'name' info that should be mutated to GVK"))
-			}
-			if content[TraitSpecField] != nil {
-				allErrs = append(allErrs, field.Invalid(fldPath, string(tr.Trait.Raw),
-					"the trait contains 'properties' info that should be mutated to spec"))
-			}
-			trait := unstructured.Unstructured{
-				Object: content,
-			}
-			if len(trait.GetAPIVersion()) == 0 || len(trait.GetKind()) == 0 {
-				allErrs = append(allErrs, field.Invalid(fldPath, content,
-					fmt.Sprintf("the trait data missing GVK, api = %s, kind = %s,", trait.GetAPIVersion(), trait.GetKind())))
+		}
+	}
+	for sidx, st := range obj.Spec.ScopeTraits {
+		for idx, tr := range st.Traits {
+			fldPath := field.NewPath("spec").Child("scopeTraits").Index(sidx).Child("traits").Index(idx).Child("trait")
+			errs, ok := validateTrait(fldPath, tr)
+			allErrs = append(allErrs, errs...)
+			if !ok {
+				return allErrs
 			}
 		}
 	}
@@ -116,6 +293,33 @@ func ValidateTraitObject(obj *v1alpha2.ApplicationConfiguration) field.ErrorList
 	return allErrs
 }
 
+// validateTrait validates the supplied trait at the supplied path. It returns
+// false if the trait is malformed.
+func validateTrait(fldPath *field.Path, tr v1alpha2.ComponentTrait) (field.ErrorList, bool) {
+	var allErrs field.ErrorList
+	var content map[string]interface{}
+	if err := json.Unmarshal(tr.Trait.Raw, &content); err != nil {
+		return append(allErrs, field.Invalid(fldPath, string(tr.Trait.Raw),
+			"the trait is malformed")), false
+	}
+	if content[TraitTypeField] != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, string(tr.Trait.Raw),
+			"the trait contains 'name' info that should be mutated to GVK"))
+	}
+	if content[TraitSpecField] != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, string(tr.Trait.Raw),
+			"the trait contains 'properties' info that should be mutated to spec"))
+	}
+	trait := unstructured.Unstructured{
+		Object: content,
+	}
+	if len(trait.GetAPIVersion()) == 0 || len(trait.GetKind()) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, content,
+			fmt.Sprintf("the trait data missing GVK, api = %s, kind = %s,", trait.GetAPIVersion(), trait.GetKind())))
+	}
+	return allErrs, true
+}
+
 func checkRevisionName(appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
 	for _, v := range appConfig.Spec.Components {
 		if v.ComponentName != "" && v.RevisionName != "" {
@@ -125,12 +329,467 @@ func checkRevisionName(appConfig *v1alpha2.ApplicationConfiguration) (bool, stri
 	return true, ""
 }
 
+// checkLimits check whether the ApplicationConfiguration has more components,
+// or any component more traits, than the supplied limits allow
+func checkLimits(limits Limits, appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	if n := len(appConfig.Spec.Components); limits.MaxComponents > 0 && n > limits.MaxComponents {
+		return false, fmt.Sprintf(reasonFmtTooManyComponents, limits.MaxComponents, n)
+	}
+	if limits.MaxTraitsPerComponent <= 0 {
+		return true, ""
+	}
+	for i, acc := range appConfig.Spec.Components {
+		if n := len(acc.Traits); n > limits.MaxTraitsPerComponent {
+			name := acc.ComponentName
+			if acc.RevisionName != "" {
+				name = acc.RevisionName
+			}
+			return false, fmt.Sprintf(reasonFmtTooManyTraits, name, i, limits.MaxTraitsPerComponent, n)
+		}
+	}
+	return true, ""
+}
+
+// checkRenderedObjectSize check whether any of the objects the
+// ApplicationConfiguration renders to is larger than the supplied limits allow
+func checkRenderedObjectSize(limits Limits, resources []*unstructured.Unstructured) (bool, string) {
+	if limits.MaxRenderedObjectSize <= 0 {
+		return true, ""
+	}
+	for _, r := range resources {
+		raw, err := json.Marshal(r)
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckObjectSize, err.Error())
+		}
+		if len(raw) > limits.MaxRenderedObjectSize {
+			return false, fmt.Sprintf(reasonFmtObjectTooLarge, r.GetAPIVersion(), r.GetKind(), r.GetName(),
+				limits.MaxRenderedObjectSize, len(raw))
+		}
+	}
+	return true, ""
+}
+
+// checkDuplicateComponents check whether a component or component revision
+// is listed more than once. Different revisions of the same component may be
+// listed, e.g. to split traffic between them.
+func checkDuplicateComponents(appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	seen := make(map[string]int, len(appConfig.Spec.Components))
+	for i, acc := range appConfig.Spec.Components {
+		// revision names are unique across components
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = acc.RevisionName
+		}
+		if j, ok := seen[name]; ok {
+			return false, fmt.Sprintf(reasonFmtDuplicateComponent, name, i, j)
+		}
+		seen[name] = i
+	}
+	return true, ""
+}
+
+// checkReferences check whether every referenced component or component
+// revision exists, every trait has a TraitDefinition and every scope has a
+// ScopeDefinition
+func checkReferences(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	ns := appConfig.GetNamespace()
+	for i, acc := range appConfig.Spec.Components {
+		if _, _, err := util.GetComponent(ctx, client, acc, ns); err != nil {
+			if !apierrors.IsNotFound(errors.Cause(err)) {
+				return false, fmt.Sprintf(errFmtCheckReferences, err.Error())
+			}
+			if acc.RevisionName != "" {
+				return false, fmt.Sprintf(reasonFmtRevisionNotFound, acc.RevisionName, i, ns)
+			}
+			return false, fmt.Sprintf(reasonFmtComponentNotFound, acc.ComponentName, i, ns)
+		}
+		for j, ct := range acc.Traits {
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+				return false, fmt.Sprintf(errFmtCheckReferences, errors.Wrap(err, errUnmarshalTrait).Error())
+			}
+			t.SetNamespace(appConfig.GetNamespace())
+			if _, err := util.FetchTraitDefinition(ctx, client, dm, t); err != nil {
+				if !isDefinitionNotFound(err) {
+					return false, fmt.Sprintf(errFmtCheckReferences, err.Error())
+				}
+				return false, fmt.Sprintf(reasonFmtTraitDefinitionNotFound, t.GetAPIVersion(), t.GetKind(), i, j)
+			}
+		}
+		for j, cs := range acc.Scopes {
+			ref := cs.ScopeReference
+			s := &unstructured.Unstructured{}
+			s.SetAPIVersion(ref.APIVersion)
+			s.SetKind(ref.Kind)
+			s.SetName(ref.Name)
+			s.SetNamespace(appConfig.GetNamespace())
+			if _, err := util.FetchScopeDefinition(ctx, client, dm, s); err != nil {
+				if !isDefinitionNotFound(err) {
+					return false, fmt.Sprintf(errFmtCheckReferences, err.Error())
+				}
+				return false, fmt.Sprintf(reasonFmtScopeDefinitionNotFound, ref.APIVersion, ref.Kind, ref.Name, i, j)
+			}
+		}
+	}
+	return true, ""
+}
+
+// checkDefinitionUsage check whether the workloads and traits of the
+// ApplicationConfiguration are of WorkloadDefinitions and TraitDefinitions
+// allowed by every DefinitionUsagePolicy that selects it
+func checkDefinitionUsage(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	policies, err := selectDefinitionUsagePolicies(ctx, client, appConfig)
+	if err != nil {
+		return false, fmt.Sprintf(errFmtCheckDefinitionUsage, err.Error())
+	}
+	if len(policies) == 0 {
+		return true, ""
+	}
+	for i, acc := range appConfig.Spec.Components {
+		c, _, err := util.GetComponent(ctx, client, acc, appConfig.GetNamespace())
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckDefinitionUsage, err.Error())
+		}
+		workloads := []runtime.RawExtension{c.Spec.Workload}
+		for _, aw := range c.Spec.AuxiliaryWorkloads {
+			workloads = append(workloads, aw.Workload)
+		}
+		for _, raw := range workloads {
+			if len(raw.Raw) == 0 {
+				continue
+			}
+			w := &unstructured.Unstructured{}
+			if err := json.Unmarshal(raw.Raw, w); err != nil {
+				return false, fmt.Sprintf(errFmtUnmarshalWorkload, c.GetName(), err.Error())
+			}
+			// the kind of workloads rendered from a schematic is unknown
+			// until they are rendered
+			if _, ok := w.GetLabels()[oam.WorkloadTypeLabel]; !ok && w.GetKind() == "" {
+				continue
+			}
+			name, err := util.ResolveDefinitionName(client, dm, v1alpha2.WorkloadDefinitionKind, w)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckDefinitionUsage, err.Error())
+			}
+			for _, p := range policies {
+				if !definitionAllowed(p.Spec.WorkloadDefinitions, name) {
+					return false, fmt.Sprintf(reasonFmtWorkloadDefinitionNotAllowed, name, c.GetName(), i, p.GetName())
+				}
+			}
+		}
+		for j, ct := range acc.Traits {
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+				return false, fmt.Sprintf(errFmtCheckDefinitionUsage, errors.Wrap(err, errUnmarshalTrait).Error())
+			}
+			t.SetNamespace(appConfig.GetNamespace())
+			name, err := util.ResolveDefinitionName(client, dm, v1alpha2.TraitDefinitionKind, t)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckDefinitionUsage, err.Error())
+			}
+			for _, p := range policies {
+				if !definitionAllowed(p.Spec.TraitDefinitions, name) {
+					return false, fmt.Sprintf(reasonFmtTraitDefinitionNotAllowed, name, i, j, p.GetName())
+				}
+			}
+		}
+	}
+	return true, ""
+}
+
+// selectDefinitionUsagePolicies returns the DefinitionUsagePolicies that
+// select the supplied ApplicationConfiguration
+func selectDefinitionUsagePolicies(ctx context.Context, c client.Reader,
+	appConfig *v1alpha2.ApplicationConfiguration) ([]v1alpha2.DefinitionUsagePolicy, error) {
+	l := &v1alpha2.DefinitionUsagePolicyList{}
+	if err := c.List(ctx, l); err != nil {
+		return nil, err
+	}
+	var nsLabels labels.Set
+	selected := make([]v1alpha2.DefinitionUsagePolicy, 0, len(l.Items))
+	for _, p := range l.Items {
+		if p.Spec.NamespaceSelector != nil {
+			if nsLabels == nil {
+				ns := &corev1.Namespace{}
+				if err := c.Get(ctx, types.NamespacedName{Name: appConfig.GetNamespace()}, ns); err != nil {
+					return nil, err
+				}
+				nsLabels = labels.Set(ns.GetLabels())
+			}
+			sel, err := metav1.LabelSelectorAsSelector(p.Spec.NamespaceSelector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid namespaceSelector of DefinitionUsagePolicy %q", p.GetName())
+			}
+			if !sel.Matches(nsLabels) {
+				continue
+			}
+		}
+		if p.Spec.Selector != nil {
+			sel, err := metav1.LabelSelectorAsSelector(p.Spec.Selector)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid selector of DefinitionUsagePolicy %q", p.GetName())
+			}
+			if !sel.Matches(labels.Set(appConfig.GetLabels())) {
+				continue
+			}
+		}
+		selected = append(selected, p)
+	}
+	return selected, nil
+}
+
+// definitionAllowed returns true if the named definition is in the supplied
+// allowed definitions, or no definitions are listed
+func definitionAllowed(allowed []string, name string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTraitConflicts check whether traits that apply to the same workload
+// conflict with each other according to their TraitDefinitions, or are of the
+// same TraitDefinition that doesn't allow multiple traits per workload
+func checkTraitConflicts(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	for i, acc := range appConfig.Spec.Components {
+		traitDefs := make([]*v1alpha2.TraitDefinition, len(acc.Traits))
+		for j, ct := range acc.Traits {
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+				return false, fmt.Sprintf(errFmtCheckTraitConflicts, errors.Wrap(err, errUnmarshalTrait).Error())
+			}
+			t.SetNamespace(appConfig.GetNamespace())
+			td, err := util.FetchTraitDefinition(ctx, client, dm, t)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckTraitConflicts, err.Error())
+			}
+			traitDefs[j] = td
+		}
+		for j := range traitDefs {
+			for k := j + 1; k < len(traitDefs); k++ {
+				if acc.Traits[j].WorkloadName != acc.Traits[k].WorkloadName {
+					continue
+				}
+				if traitDefs[j].GetName() == traitDefs[k].GetName() && !traitDefs[j].Spec.AllowMultiple {
+					return false, fmt.Sprintf(reasonFmtDuplicateTrait, traitDefs[j].GetName(), i, k, i, j)
+				}
+				conflict, err := util.TraitsConflict(traitDefs[j], traitDefs[k])
+				if err != nil {
+					return false, fmt.Sprintf(errFmtCheckTraitConflicts, err.Error())
+				}
+				if conflict {
+					return false, fmt.Sprintf(reasonFmtTraitConflict, traitDefs[j].GetName(), i, j, traitDefs[k].GetName(), i, k)
+				}
+			}
+		}
+	}
+	return true, ""
+}
+
+// checkTraitsApplyToWorkloads check whether every trait applies to the kind of
+// the workload it targets according to its TraitDefinition. Workloads whose
+// kind is only known once they are rendered, e.g. from a schematic, are
+// checked when the ApplicationConfiguration is reconciled.
+func checkTraitsApplyToWorkloads(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	for i, acc := range appConfig.Spec.Components {
+		if len(acc.Traits) == 0 {
+			continue
+		}
+		c, _, err := util.GetComponent(ctx, client, acc, appConfig.GetNamespace())
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckTraitsApply, err.Error())
+		}
+		targets := map[string]runtime.RawExtension{"": c.Spec.Workload}
+		for _, aw := range c.Spec.AuxiliaryWorkloads {
+			targets[aw.Name] = aw.Workload
+		}
+		for j, ct := range acc.Traits {
+			raw, ok := targets[ct.WorkloadName]
+			if !ok || len(raw.Raw) == 0 {
+				continue
+			}
+			w := &unstructured.Unstructured{}
+			if err := json.Unmarshal(raw.Raw, w); err != nil {
+				return false, fmt.Sprintf(errFmtUnmarshalWorkload, c.GetName(), err.Error())
+			}
+			if w.GetKind() == "" {
+				continue
+			}
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+				return false, fmt.Sprintf(errFmtCheckTraitsApply, errors.Wrap(err, errUnmarshalTrait).Error())
+			}
+			t.SetNamespace(appConfig.GetNamespace())
+			td, err := util.FetchTraitDefinition(ctx, client, dm, t)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckTraitsApply, err.Error())
+			}
+			applies, err := util.TraitAppliesToWorkload(dm, td, w)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckTraitsApply, err.Error())
+			}
+			if !applies {
+				return false, fmt.Sprintf(reasonFmtTraitNotApplies, td.GetName(), i, j, w.GetAPIVersion(), w.GetKind(), td.Spec.AppliesToWorkloads)
+			}
+		}
+	}
+	return true, ""
+}
+
+// checkScopesApplyToWorkloads check whether the workload of every component
+// may join the scopes of the component according to their ScopeDefinitions.
+// Workloads whose kind is only known once they are rendered are checked when
+// the ApplicationConfiguration is reconciled.
+func checkScopesApplyToWorkloads(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	for i, acc := range appConfig.Spec.Components {
+		if len(acc.Scopes) == 0 {
+			continue
+		}
+		c, _, err := util.GetComponent(ctx, client, acc, appConfig.GetNamespace())
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckScopesApply, err.Error())
+		}
+		if len(c.Spec.Workload.Raw) == 0 {
+			continue
+		}
+		w := &unstructured.Unstructured{}
+		if err := json.Unmarshal(c.Spec.Workload.Raw, w); err != nil {
+			return false, fmt.Sprintf(errFmtUnmarshalWorkload, c.GetName(), err.Error())
+		}
+		if w.GetKind() == "" {
+			continue
+		}
+		for j, cs := range acc.Scopes {
+			s := &unstructured.Unstructured{}
+			s.SetAPIVersion(cs.ScopeReference.APIVersion)
+			s.SetKind(cs.ScopeReference.Kind)
+			s.SetName(cs.ScopeReference.Name)
+			s.SetNamespace(appConfig.GetNamespace())
+			sd, err := util.FetchScopeDefinition(ctx, client, dm, s)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckScopesApply, err.Error())
+			}
+			applies, err := util.ScopeAppliesToWorkload(dm, sd, w)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckScopesApply, err.Error())
+			}
+			if !applies {
+				return false, fmt.Sprintf(reasonFmtScopeNotApplies, sd.GetName(), i, j, w.GetAPIVersion(), w.GetKind(), sd.Spec.AppliesToWorkloads)
+			}
+		}
+	}
+	return true, ""
+}
+
+// checkScopeOverlap checks that no component of the supplied
+// ApplicationConfiguration is in more than one scope of a kind whose
+// ScopeDefinition doesn't allow component overlap.
+func checkScopeOverlap(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	for i, acc := range appConfig.Spec.Components {
+		for j, cs := range acc.Scopes {
+			ref := cs.ScopeReference
+			for k, other := range acc.Scopes[:j] {
+				if other.ScopeReference.APIVersion != ref.APIVersion || other.ScopeReference.Kind != ref.Kind ||
+					other.ScopeReference.Name == ref.Name {
+					continue
+				}
+				s := &unstructured.Unstructured{}
+				s.SetAPIVersion(ref.APIVersion)
+				s.SetKind(ref.Kind)
+				s.SetName(ref.Name)
+				s.SetNamespace(appConfig.GetNamespace())
+				sd, err := util.FetchScopeDefinition(ctx, client, dm, s)
+				if err != nil {
+					return false, fmt.Sprintf(errFmtCheckScopeOverlap, err.Error())
+				}
+				if !sd.Spec.AllowComponentOverlap {
+					return false, fmt.Sprintf(reasonFmtScopeOverlap, sd.GetName(), i, j, other.ScopeReference.Name, i, k)
+				}
+				break
+			}
+		}
+	}
+	return true, ""
+}
+
+// checkDeprecatedDefinitions returns a warning for each deprecated
+// TraitDefinition the traits of the supplied ApplicationConfiguration are of.
+// If deprecated definitions are rejected, it rejects the
+// ApplicationConfiguration if the supplied old ApplicationConfiguration, if
+// any, didn't use one of them yet.
+func checkDeprecatedDefinitions(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper, reject bool,
+	appConfig, old *v1alpha2.ApplicationConfiguration) ([]string, bool, string) {
+	used := map[string]bool{}
+	if old != nil {
+		for _, td := range traitDefinitions(ctx, client, dm, old) {
+			used[td.GetName()] = true
+		}
+	}
+	var warnings, rejected []string
+	for _, td := range traitDefinitions(ctx, client, dm, appConfig) {
+		if !td.Spec.Deprecated {
+			continue
+		}
+		warning := util.DeprecationWarning(v1alpha2.TraitDefinitionKind, td.GetName(), td.Spec.ReplacedBy)
+		warnings = append(warnings, warning)
+		if reject && !used[td.GetName()] {
+			rejected = append(rejected, warning)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, false, fmt.Sprintf(reasonFmtDeprecatedDefinition, strings.Join(rejected, "; "))
+	}
+	return warnings, true, ""
+}
+
+// traitDefinitions returns the distinct TraitDefinitions of the traits of the
+// supplied ApplicationConfiguration. Traits that are malformed, or whose
+// definition can't be fetched, are skipped; checkReferences rejects them.
+func traitDefinitions(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration) []*v1alpha2.TraitDefinition {
+	seen := map[string]bool{}
+	var defs []*v1alpha2.TraitDefinition
+	for _, acc := range appConfig.Spec.Components {
+		for _, ct := range acc.Traits {
+			t := &unstructured.Unstructured{}
+			if err := json.Unmarshal(ct.Trait.Raw, t); err != nil {
+				continue
+			}
+			t.SetNamespace(appConfig.GetNamespace())
+			td, err := util.FetchTraitDefinition(ctx, client, dm, t)
+			if err != nil || seen[td.GetName()] {
+				continue
+			}
+			seen[td.GetName()] = true
+			defs = append(defs, td)
+		}
+	}
+	return defs
+}
+
+// isDefinitionNotFound returns true if the supplied error indicates that the
+// definition or the kind it defines doesn't exist
+func isDefinitionNotFound(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
+
 // checkWorkloadNameForVersioning check whether versioning-enabled component workload name is empty
 func checkWorkloadNameForVersioning(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
 	appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
 	for _, v := range appConfig.Spec.Components {
 		acc := v
-		vEnabled, err := checkComponentVersionEnabled(ctx, client, dm, &acc)
+		vEnabled, err := checkComponentVersionEnabled(ctx, client, dm, appConfig.GetNamespace(), &acc)
 		if err != nil {
 			return false, fmt.Sprintf(errFmtCheckWorkloadName, err.Error())
 		}
@@ -160,6 +819,162 @@ func checkWorkloadNameForVersioning(ctx context.Context, client client.Reader, d
 	return true, ""
 }
 
+// checkRequiredParamsAssigned check whether all required parameters of referenced components are assigned
+func checkRequiredParamsAssigned(ctx context.Context, client client.Reader, appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	for _, v := range appConfig.Spec.Components {
+		c, _, err := util.GetComponent(ctx, client, v, appConfig.GetNamespace())
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckRequiredParams, err.Error())
+		}
+		if ok, paramName := checkRequiredParams(c.Spec.Parameters, v.ParameterValues); !ok {
+			return false, fmt.Sprintf(reasonFmtRequiredParamMissing, paramName, c.GetName())
+		}
+	}
+	return true, ""
+}
+
+// checkParamValues check whether every parameter value matches the type, enum
+// and range of the parameter it is assigned to. Values from other components
+// are only known at render time, thus not checked
+func checkParamValues(ctx context.Context, client client.Reader, appConfig *v1alpha2.ApplicationConfiguration) (bool, string) {
+	var allErrs field.ErrorList
+	for i, acc := range appConfig.Spec.Components {
+		c, _, err := util.GetComponent(ctx, client, acc, appConfig.GetNamespace())
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckParamValues, err.Error())
+		}
+		params := make(map[string]v1alpha2.ComponentParameter, len(c.Spec.Parameters))
+		for _, p := range c.Spec.Parameters {
+			params[p.Name] = p
+		}
+		for j, v := range acc.ParameterValues {
+			p, ok := params[v.Name]
+			if !ok || v.ValueFrom != nil {
+				continue
+			}
+			fldPath := field.NewPath("spec", "components").Index(i).Child("parameterValues").Index(j).Child("value")
+			allErrs = append(allErrs, util.ValidateParameterValue(fldPath, p, v.Value)...)
+		}
+	}
+	if len(allErrs) > 0 {
+		return false, fmt.Sprintf(reasonFmtInvalidParamValues, allErrs.ToAggregate().Error())
+	}
+	return true, ""
+}
+
+// checkPolicies check whether the resources the ApplicationConfiguration
+// renders to satisfy the supplied policies
+func checkPolicies(ctx context.Context, policies policy.Evaluator, appConfig *v1alpha2.ApplicationConfiguration,
+	resources []*unstructured.Unstructured) (bool, string) {
+	if policies == nil {
+		return true, ""
+	}
+	violations, err := policies.Evaluate(ctx, appConfig, resources)
+	if err != nil {
+		return false, fmt.Sprintf(errFmtCheckPolicies, err.Error())
+	}
+	if len(violations) == 0 {
+		return true, ""
+	}
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.String()
+	}
+	return false, fmt.Sprintf(reasonFmtPolicyViolations, strings.Join(msgs, "; "))
+}
+
+// checkResourceQuotaScopes checks that the workloads the supplied
+// ApplicationConfiguration renders to don't make the ResourceQuotaScopes they
+// are in exceed their hard limits. Only resources whose use increases are
+// checked, so that workloads of scopes that already exceed their limits can
+// still be scaled down.
+func checkResourceQuotaScopes(ctx context.Context, client client.Reader, dm discoverymapper.DiscoveryMapper,
+	appConfig *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) (bool, string) {
+	workloads := map[string]*unstructured.Unstructured{}
+	for _, r := range resources {
+		l := r.GetLabels()
+		if l[oam.LabelOAMResourceType] != oam.ResourceTypeWorkload || l[oam.LabelAppAuxiliaryWorkload] != "" {
+			continue
+		}
+		workloads[l[oam.LabelAppComponent]] = r
+	}
+
+	type member struct {
+		component, scope int
+		workload         *unstructured.Unstructured
+	}
+	var names []string
+	members := map[string][]member{}
+	for i, acc := range appConfig.Spec.Components {
+		name := acc.ComponentName
+		if acc.RevisionName != "" {
+			name = controller.ExtractComponentName(acc.RevisionName)
+		}
+		wl, ok := workloads[name]
+		if !ok {
+			continue
+		}
+		for j, cs := range acc.Scopes {
+			ref := cs.ScopeReference
+			if ref.APIVersion != v1alpha2.SchemeGroupVersion.String() || ref.Kind != v1alpha2.ResourceQuotaScopeKind {
+				continue
+			}
+			if _, ok := members[ref.Name]; !ok {
+				names = append(names, ref.Name)
+			}
+			members[ref.Name] = append(members[ref.Name], member{component: i, scope: j, workload: wl})
+		}
+	}
+
+	for _, name := range names {
+		rs := &v1alpha2.ResourceQuotaScope{}
+		err := client.Get(ctx, types.NamespacedName{Namespace: appConfig.GetNamespace(), Name: name}, rs)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckResourceQuotas, err.Error())
+		}
+		hard := quota.Normalize(rs.Spec.Hard)
+		if len(hard) == 0 {
+			continue
+		}
+		used := quota.Add(quota.Zero(), rs.Status.Used)
+		for _, m := range members[name] {
+			u, err := quota.WorkloadUsage(ctx, client, dm, m.workload)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckResourceQuotas, err.Error())
+			}
+			if u == nil {
+				// the use recorded for the workload, if any, is kept
+				continue
+			}
+			gk := m.workload.GroupVersionKind().GroupKind()
+			for _, w := range rs.Status.Workloads {
+				if w.TargetWorkload.GroupVersionKind().GroupKind() == gk && w.TargetWorkload.Name == m.workload.GetName() {
+					used = quota.Subtract(used, w.Used)
+				}
+			}
+			used = quota.Add(used, u)
+		}
+		var exceeded []string
+		for _, r := range quota.Exceeded(hard, used) {
+			before := rs.Status.Used[r]
+			after := used[r]
+			if after.Cmp(before) <= 0 {
+				continue
+			}
+			h := hard[r]
+			exceeded = append(exceeded, fmt.Sprintf("%s %s of %s", r, after.String(), h.String()))
+		}
+		if len(exceeded) > 0 {
+			m := members[name][0]
+			return false, fmt.Sprintf(reasonFmtResourceQuotaExceeded, name, m.component, m.scope, strings.Join(exceeded, ", "))
+		}
+	}
+	return true, ""
+}
+
 var _ inject.Client = &ValidatingHandler{}
 
 // InjectClient injects the client into the ValidatingHandler
@@ -176,15 +991,30 @@ func (h *ValidatingHandler) InjectDecoder(d *admission.Decoder) error {
 	return nil
 }
 
+// ValidatingHandlerPath is the path application configuration validation is
+// served at by default
+const ValidatingHandlerPath = "/validating-core-oam-dev-v1alpha2-applicationconfigurations"
+
+// NewValidatingHandler returns an application configuration validation
+// handler for the supplied manager
+func NewValidatingHandler(mgr manager.Manager) (*ValidatingHandler, error) {
+	mapper, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatingHandler{
+		Mapper:   mapper,
+		Renderer: render.New(mgr.GetClient(), mapper),
+	}, nil
+}
+
 // RegisterValidatingHandler will register application configuration validation to webhook
 func RegisterValidatingHandler(mgr manager.Manager) error {
 	server := mgr.GetWebhookServer()
-	mapper, err := discoverymapper.New(mgr.GetConfig())
+	h, err := NewValidatingHandler(mgr)
 	if err != nil {
 		return err
 	}
-	server.Register("/validating-core-oam-dev-v1alpha2-applicationconfigurations", &webhook.Admission{Handler: &ValidatingHandler{
-		Mapper: mapper,
-	}})
+	server.Register(ValidatingHandlerPath, review.New(h))
 	return nil
 }
@@ -3,6 +3,7 @@ package applicationconfiguration
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -10,16 +11,27 @@ import (
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/policy"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
 
+	runtimev1alpha1 "github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	json "github.com/json-iterator/go"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -46,6 +58,7 @@ func TestApplicationConfigurationValidation(t *testing.T) {
 						}}}}
 				return nil
 			},
+			MockList: test.NewMockListFn(nil),
 		},
 	}
 	resource := metav1.GroupVersionResource{Group: "core.oam.dev", Version: "v1alpha2", Resource: "applicationconfigurations"}
@@ -384,3 +397,1043 @@ func TestCheckWorkloadNameForVersioning(t *testing.T) {
 		}(t)
 	}
 }
+
+func TestCheckReferences(t *testing.T) {
+	ctx := context.Background()
+	mockClient := test.NewMockClient()
+	mapper := mock.NewMockDiscoveryMapper()
+	getErr := errors.New("get error")
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+
+	msTraitRaw, _ := json.Marshal(v1alpha2.ManualScalerTrait{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ManualScalerTrait",
+			APIVersion: "core.oam.dev/v1alpha2",
+		},
+	})
+	appConfig := func(acc v1alpha2.ApplicationConfigurationComponent) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns"},
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{acc},
+			},
+		}
+	}
+	withTraitAndScope := appConfig(v1alpha2.ApplicationConfigurationComponent{
+		ComponentName: "c",
+		Traits:        []v1alpha2.ComponentTrait{{Trait: runtime.RawExtension{Raw: msTraitRaw}}},
+		Scopes: []v1alpha2.ComponentScope{{ScopeReference: runtimev1alpha1.TypedReference{
+			APIVersion: "core.oam.dev/v1alpha2",
+			Kind:       "HealthScope",
+			Name:       "hs",
+		}}},
+	})
+	// notFoundFor returns a get function that only fails to get objects of
+	// the same type as the supplied object
+	notFoundFor := func(missing runtime.Object) test.MockGetFn {
+		return func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+			if fmt.Sprintf("%T", obj) == fmt.Sprintf("%T", missing) {
+				return notFound
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		mockGetFunc  test.MockGetFn
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes when every reference exists",
+			appConfig:    withTraitAndScope,
+			mockGetFunc:  test.NewMockGetFn(nil),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for missing component",
+			appConfig:    appConfig(v1alpha2.ApplicationConfigurationComponent{ComponentName: "c"}),
+			mockGetFunc:  notFoundFor(&v1alpha2.Component{}),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtComponentNotFound, "c", 0, "ns"),
+		},
+		{
+			caseName:     "Test validation fails for missing component revision",
+			appConfig:    appConfig(v1alpha2.ApplicationConfigurationComponent{RevisionName: "c-v1"}),
+			mockGetFunc:  notFoundFor(&appsv1.ControllerRevision{}),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtRevisionNotFound, "c-v1", 0, "ns"),
+		},
+		{
+			caseName:     "Test validation fails for trait without TraitDefinition",
+			appConfig:    withTraitAndScope,
+			mockGetFunc:  notFoundFor(&v1alpha2.TraitDefinition{}),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTraitDefinitionNotFound, "core.oam.dev/v1alpha2", "ManualScalerTrait", 0, 0),
+		},
+		{
+			caseName:     "Test validation fails for scope without ScopeDefinition",
+			appConfig:    withTraitAndScope,
+			mockGetFunc:  notFoundFor(&v1alpha2.ScopeDefinition{}),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtScopeDefinitionNotFound, "core.oam.dev/v1alpha2", "HealthScope", "hs", 0, 0),
+		},
+		{
+			caseName:     "Test getComponent error occurs during validation",
+			appConfig:    appConfig(v1alpha2.ApplicationConfigurationComponent{ComponentName: "c"}),
+			mockGetFunc:  test.NewMockGetFn(getErr),
+			expectResult: false,
+			expectReason: "Error occurs when checking references. \"cannot get component \\\"c\\\": get error\"",
+		},
+	}
+	for _, tc := range tests {
+		func(t *testing.T) {
+			mockClient.MockGet = tc.mockGetFunc
+			result, reason := checkReferences(ctx, mockClient, mapper, &tc.appConfig)
+			assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+		}(t)
+	}
+}
+
+func TestCheckParamValues(t *testing.T) {
+	ctx := context.Background()
+	mockClient := test.NewMockClient()
+	maxReplicas := int64(3)
+	comp := v1alpha2.Component{Spec: v1alpha2.ComponentSpec{Parameters: []v1alpha2.ComponentParameter{
+		{Name: "replicas", Type: v1alpha2.IntegerParameterType, Maximum: &maxReplicas},
+		{Name: "tier", Enum: []intstr.IntOrString{intstr.FromString("web"), intstr.FromString("db")}},
+	}}}
+	mockClient.MockGet = func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		if o, ok := obj.(*v1alpha2.Component); ok {
+			comp.DeepCopyInto(o)
+		}
+		return nil
+	}
+	appConfig := func(cpv ...v1alpha2.ComponentParameterValue) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "c", ParameterValues: cpv}},
+			},
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName: "Test validation passes for valid parameter values",
+			appConfig: appConfig(
+				v1alpha2.ComponentParameterValue{Name: "replicas", Value: intstr.FromInt(2)},
+				v1alpha2.ComponentParameterValue{Name: "tier", Value: intstr.FromString("web")},
+			),
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation skips values from other components",
+			appConfig: appConfig(v1alpha2.ComponentParameterValue{
+				Name:      "replicas",
+				ValueFrom: &v1alpha2.ComponentParameterValueFrom{FromComponent: "db", FieldPath: "spec.replicas"},
+			}),
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation fails for every invalid parameter value",
+			appConfig: appConfig(
+				v1alpha2.ComponentParameterValue{Name: "replicas", Value: intstr.FromInt(5)},
+				v1alpha2.ComponentParameterValue{Name: "tier", Value: intstr.FromString("cache")},
+			),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtInvalidParamValues, "[spec.components[0].parameterValues[0].value: "+
+				"Invalid value: 5: parameter \"replicas\" must be less than or equal to 3, "+
+				"spec.components[0].parameterValues[1].value: Unsupported value: \"cache\": supported values: \"web\", \"db\"]"),
+		},
+	}
+	for _, tc := range tests {
+		result, reason := checkParamValues(ctx, mockClient, &tc.appConfig)
+		assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+		assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
+
+func TestCheckDuplicateComponents(t *testing.T) {
+	appConfig := func(acc ...v1alpha2.ApplicationConfigurationComponent) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{Components: acc},
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName: "Test validation passes for different components",
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "web"},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "db"},
+			),
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation passes for different revisions of a component",
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{RevisionName: "web-v1"},
+				v1alpha2.ApplicationConfigurationComponent{RevisionName: "web-v2"},
+			),
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation fails for a component listed twice",
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "web"},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "db"},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "web"},
+			),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtDuplicateComponent, "web", 2, 0),
+		},
+		{
+			caseName: "Test validation fails for a component revision listed twice",
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{RevisionName: "web-v1"},
+				v1alpha2.ApplicationConfigurationComponent{RevisionName: "web-v1"},
+			),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtDuplicateComponent, "web-v1", 1, 0),
+		},
+	}
+	for _, tc := range tests {
+		result, reason := checkDuplicateComponents(&tc.appConfig)
+		assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+		assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	appConfig := func(acc ...v1alpha2.ApplicationConfigurationComponent) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{Components: acc},
+		}
+	}
+	traits := func(n int) []v1alpha2.ComponentTrait {
+		return make([]v1alpha2.ComponentTrait, n)
+	}
+
+	tests := []struct {
+		caseName     string
+		limits       Limits
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName: "Test validation passes without limits",
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "web", Traits: traits(3)},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "db"},
+			),
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation passes within limits",
+			limits:   Limits{MaxComponents: 2, MaxTraitsPerComponent: 3},
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "web", Traits: traits(3)},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "db"},
+			),
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation fails for too many components",
+			limits:   Limits{MaxComponents: 1},
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "web"},
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "db"},
+			),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTooManyComponents, 1, 2),
+		},
+		{
+			caseName: "Test validation fails for a component with too many traits",
+			limits:   Limits{MaxTraitsPerComponent: 2},
+			appConfig: appConfig(
+				v1alpha2.ApplicationConfigurationComponent{ComponentName: "web", Traits: traits(2)},
+				v1alpha2.ApplicationConfigurationComponent{RevisionName: "db-v1", Traits: traits(3)},
+			),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTooManyTraits, "db-v1", 1, 2, 3),
+		},
+	}
+	for _, tc := range tests {
+		result, reason := checkLimits(tc.limits, &tc.appConfig)
+		assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+		assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
+
+func TestCheckRenderedObjectSize(t *testing.T) {
+	deploy := &unstructured.Unstructured{}
+	deploy.SetAPIVersion("apps/v1")
+	deploy.SetKind("Deployment")
+	deploy.SetName("web")
+	raw, _ := json.Marshal(deploy)
+	size := len(raw)
+
+	tests := []struct {
+		caseName     string
+		limits       Limits
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes without limits",
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation passes for objects within limits",
+			limits:       Limits{MaxRenderedObjectSize: size},
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for objects larger than limits",
+			limits:       Limits{MaxRenderedObjectSize: size - 1},
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtObjectTooLarge, "apps/v1", "Deployment", "web", size-1, size),
+		},
+	}
+	for _, tc := range tests {
+		result, reason := checkRenderedObjectSize(tc.limits, []*unstructured.Unstructured{deploy})
+		assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+		assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
+
+func TestCheckTraitConflicts(t *testing.T) {
+	ctx := context.Background()
+	mockClient := test.NewMockClient()
+	mapper := mock.NewMockDiscoveryMapper()
+
+	trait := func(kind, workloadName string) v1alpha2.ComponentTrait {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"labels": map[string]string{"trait.oam.dev/type": kind}},
+		})
+		return v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: raw}, WorkloadName: workloadName}
+	}
+	// the manual scaler conflicts with the autoscaler, sidecars allow multiples
+	mockClient.MockGet = func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		if o, ok := obj.(*v1alpha2.TraitDefinition); ok {
+			*o = v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+			switch key.Name {
+			case "ManualScaler":
+				o.Spec.ConflictsWith = []string{"Autoscaler"}
+			case "Sidecar":
+				o.Spec.AllowMultiple = true
+			}
+		}
+		return nil
+	}
+	appConfig := func(traits ...v1alpha2.ComponentTrait) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: "c", Traits: traits}},
+			},
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes for traits that don't conflict",
+			appConfig:    appConfig(trait("Autoscaler", ""), trait("Ingress", "")),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for conflicting traits",
+			appConfig:    appConfig(trait("Autoscaler", ""), trait("Ingress", ""), trait("ManualScaler", "")),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTraitConflict, "Autoscaler", 0, 0, "ManualScaler", 0, 2),
+		},
+		{
+			caseName:     "Test validation passes for conflicting traits of different workloads",
+			appConfig:    appConfig(trait("Autoscaler", ""), trait("ManualScaler", "aux")),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for traits of the same kind",
+			appConfig:    appConfig(trait("Ingress", ""), trait("Autoscaler", ""), trait("Ingress", "")),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtDuplicateTrait, "Ingress", 0, 2, 0, 0),
+		},
+		{
+			caseName:     "Test validation passes for traits of the same kind of different workloads",
+			appConfig:    appConfig(trait("Ingress", ""), trait("Ingress", "aux")),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation passes for traits of the same kind that allows multiples",
+			appConfig:    appConfig(trait("Sidecar", ""), trait("Sidecar", "")),
+			expectResult: true,
+		},
+	}
+	for _, tc := range tests {
+		func(t *testing.T) {
+			result, reason := checkTraitConflicts(ctx, mockClient, mapper, &tc.appConfig)
+			assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+		}(t)
+	}
+}
+
+func TestCheckTraitsApplyToWorkloads(t *testing.T) {
+	ctx := context.Background()
+	mockClient := test.NewMockClient()
+	mapper := mock.NewMockDiscoveryMapper()
+
+	traitRaw, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Autoscaler",
+	})
+	mockClient.MockGet = func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha2.TraitDefinition:
+			*o = v1alpha2.TraitDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "autoscalers.example.com"},
+				Spec:       v1alpha2.TraitDefinitionSpec{AppliesToWorkloads: []string{"deployment.apps/v1"}},
+			}
+		case *v1alpha2.Component:
+			*o = v1alpha2.Component{
+				ObjectMeta: metav1.ObjectMeta{Name: "c"},
+				Spec: v1alpha2.ComponentSpec{
+					Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)},
+					AuxiliaryWorkloads: []v1alpha2.AuxiliaryWorkload{{
+						Name:     "config",
+						Workload: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)},
+					}},
+				},
+			}
+		}
+		return nil
+	}
+	appConfig := func(workloadName string) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "c",
+					Traits:        []v1alpha2.ComponentTrait{{Trait: runtime.RawExtension{Raw: traitRaw}, WorkloadName: workloadName}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes for trait that applies to its workload",
+			appConfig:    appConfig(""),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for trait that does not apply to its workload",
+			appConfig:    appConfig("config"),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTraitNotApplies, "autoscalers.example.com", 0, 0, "v1", "ConfigMap", []string{"deployment.apps/v1"}),
+		},
+	}
+	for _, tc := range tests {
+		func(t *testing.T) {
+			result, reason := checkTraitsApplyToWorkloads(ctx, mockClient, mapper, &tc.appConfig)
+			assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+		}(t)
+	}
+}
+
+func TestCheckScopesApplyToWorkloads(t *testing.T) {
+	ctx := context.Background()
+	mockClient := test.NewMockClient()
+	mapper := mock.NewMockDiscoveryMapper()
+
+	// network scopes only accept deployments, health scopes accept all
+	mockClient.MockGet = func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha2.ScopeDefinition:
+			*o = v1alpha2.ScopeDefinition{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+			if key.Name == "networkscopes.example.com" {
+				o.Spec.AppliesToWorkloads = []string{"deployment.apps"}
+			}
+		case *v1alpha2.Component:
+			*o = v1alpha2.Component{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+				Spec: v1alpha2.ComponentSpec{
+					Workload: runtime.RawExtension{Raw: []byte(fmt.Sprintf(`{"apiVersion":"apps/v1","kind":%q}`, key.Name))},
+				},
+			}
+		}
+		return nil
+	}
+	mapper.MockRESTMapping = func(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+		return &meta.RESTMapping{Resource: schema.GroupVersionResource{Resource: strings.ToLower(gk.Kind) + "s", Group: gk.Group}}, nil
+	}
+	// components are named after the kind of their workload
+	appConfig := func(component, scopeKind string) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: component,
+					Scopes: []v1alpha2.ComponentScope{{ScopeReference: runtimev1alpha1.TypedReference{
+						APIVersion: "example.com/v1",
+						Kind:       scopeKind,
+						Name:       "s",
+					}}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes for workload the scope applies to",
+			appConfig:    appConfig("Deployment", "NetworkScope"),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation passes for scope that applies to all workloads",
+			appConfig:    appConfig("StatefulSet", "HealthScope"),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for workload the scope does not apply to",
+			appConfig:    appConfig("StatefulSet", "NetworkScope"),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtScopeNotApplies, "networkscopes.example.com", 0, 0, "apps/v1", "StatefulSet", []string{"deployment.apps"}),
+		},
+	}
+	for _, tc := range tests {
+		func(t *testing.T) {
+			result, reason := checkScopesApplyToWorkloads(ctx, mockClient, mapper, &tc.appConfig)
+			assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+		}(t)
+	}
+}
+
+func TestCheckScopeOverlap(t *testing.T) {
+	ctx := context.Background()
+	mockClient := test.NewMockClient()
+	mapper := mock.NewMockDiscoveryMapper()
+
+	// components may overlap in health scopes, but not in quota scopes
+	mockClient.MockGet = func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		if o, ok := obj.(*v1alpha2.ScopeDefinition); ok {
+			*o = v1alpha2.ScopeDefinition{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+			o.Spec.AllowComponentOverlap = key.Name == "healthscopes.example.com"
+		}
+		return nil
+	}
+	mapper.MockRESTMapping = func(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+		return &meta.RESTMapping{Resource: schema.GroupVersionResource{Resource: strings.ToLower(gk.Kind) + "s", Group: gk.Group}}, nil
+	}
+	scope := func(kind, name string) v1alpha2.ComponentScope {
+		return v1alpha2.ComponentScope{ScopeReference: runtimev1alpha1.TypedReference{
+			APIVersion: "example.com/v1",
+			Kind:       kind,
+			Name:       name,
+		}}
+	}
+	appConfig := func(scopes ...v1alpha2.ComponentScope) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{
+					ComponentName: "c",
+					Scopes:        scopes,
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes for scopes of different kinds",
+			appConfig:    appConfig(scope("QuotaScope", "a"), scope("HealthScope", "b")),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation passes for scopes that allow component overlap",
+			appConfig:    appConfig(scope("HealthScope", "a"), scope("HealthScope", "b")),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for scopes that don't allow component overlap",
+			appConfig:    appConfig(scope("QuotaScope", "a"), scope("HealthScope", "b"), scope("QuotaScope", "c")),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtScopeOverlap, "quotascopes.example.com", 0, 2, "a", 0, 0),
+		},
+	}
+	for _, tc := range tests {
+		func(t *testing.T) {
+			result, reason := checkScopeOverlap(ctx, mockClient, mapper, &tc.appConfig)
+			assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+		}(t)
+	}
+}
+
+func TestCheckDefinitionUsage(t *testing.T) {
+	ctx := context.Background()
+	mapper := mock.NewMockDiscoveryMapper()
+
+	// the tenant namespace may only use web services and ingresses, and
+	// AppConfigs labelled as experimental may not use any traits
+	policies := []v1alpha2.DefinitionUsagePolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-catalog"},
+			Spec: v1alpha2.DefinitionUsagePolicySpec{
+				NamespaceSelector:   &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+				WorkloadDefinitions: []string{"webservice"},
+				TraitDefinitions:    []string{"ingress"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-experimental-traits"},
+			Spec: v1alpha2.DefinitionUsagePolicySpec{
+				Selector:         &metav1.LabelSelector{MatchLabels: map[string]string{"experimental": "true"}},
+				TraitDefinitions: []string{"none"},
+			},
+		},
+	}
+	workload := func(typ string) runtime.RawExtension {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"labels": map[string]string{"workload.oam.dev/type": typ}},
+		})
+		return runtime.RawExtension{Raw: raw}
+	}
+	trait := func(typ string) v1alpha2.ComponentTrait {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Trait",
+			"metadata":   map[string]interface{}{"labels": map[string]string{"trait.oam.dev/type": typ}},
+		})
+		return v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: raw}}
+	}
+	mockClient := &test.MockClient{
+		MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *corev1.Namespace:
+				if key.Name == "tenant" {
+					o.SetLabels(map[string]string{"tenant": "true"})
+				}
+			case *v1alpha2.Component:
+				*o = v1alpha2.Component{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+					Spec:       v1alpha2.ComponentSpec{Workload: workload(key.Name)},
+				}
+			}
+			return nil
+		},
+		MockList: func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+			list.(*v1alpha2.DefinitionUsagePolicyList).Items = policies
+			return nil
+		},
+	}
+	// components are named after the WorkloadDefinition of their workload
+	appConfig := func(namespace string, labels map[string]string, component string,
+		traits ...v1alpha2.ComponentTrait) v1alpha2.ApplicationConfiguration {
+		return v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace, Labels: labels},
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{{ComponentName: component, Traits: traits}},
+			},
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		appConfig    v1alpha2.ApplicationConfiguration
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes for allowed definitions",
+			appConfig:    appConfig("tenant", nil, "webservice", trait("ingress")),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for a WorkloadDefinition that isn't allowed",
+			appConfig:    appConfig("tenant", nil, "worker"),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtWorkloadDefinitionNotAllowed, "worker", "worker", 0, "tenant-catalog"),
+		},
+		{
+			caseName:     "Test validation fails for a TraitDefinition that isn't allowed",
+			appConfig:    appConfig("tenant", nil, "webservice", trait("ingress"), trait("autoscaler")),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTraitDefinitionNotAllowed, "autoscaler", 0, 1, "tenant-catalog"),
+		},
+		{
+			caseName:     "Test validation passes for any definitions in namespaces that aren't selected",
+			appConfig:    appConfig("other", nil, "worker", trait("autoscaler")),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails for definitions not allowed by a policy selecting the AppConfig by label",
+			appConfig:    appConfig("other", map[string]string{"experimental": "true"}, "worker", trait("autoscaler")),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTraitDefinitionNotAllowed, "autoscaler", 0, 0, "no-experimental-traits"),
+		},
+		{
+			caseName:     "Test validation fails for definitions not allowed by every policy selecting the AppConfig",
+			appConfig:    appConfig("tenant", map[string]string{"experimental": "true"}, "webservice", trait("ingress")),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtTraitDefinitionNotAllowed, "ingress", 0, 0, "no-experimental-traits"),
+		},
+	}
+	for _, tc := range tests {
+		func(t *testing.T) {
+			result, reason := checkDefinitionUsage(ctx, mockClient, mapper, &tc.appConfig)
+			assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+		}(t)
+	}
+}
+
+func TestCheckDeprecatedDefinitions(t *testing.T) {
+	ctx := context.Background()
+	mockClient := test.NewMockClient()
+	mapper := mock.NewMockDiscoveryMapper()
+
+	trait := func(kind string) v1alpha2.ComponentTrait {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"labels": map[string]string{"trait.oam.dev/type": kind}},
+		})
+		return v1alpha2.ComponentTrait{Trait: runtime.RawExtension{Raw: raw}}
+	}
+	// the manual scaler is deprecated in favour of the autoscaler
+	mockClient.MockGet = func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+		if o, ok := obj.(*v1alpha2.TraitDefinition); ok {
+			*o = v1alpha2.TraitDefinition{ObjectMeta: metav1.ObjectMeta{Name: key.Name}}
+			if key.Name == "ManualScaler" {
+				o.Spec.Deprecated = true
+				o.Spec.ReplacedBy = "Autoscaler"
+			}
+		}
+		return nil
+	}
+	appConfig := func(traits ...v1alpha2.ComponentTrait) *v1alpha2.ApplicationConfiguration {
+		return &v1alpha2.ApplicationConfiguration{
+			Spec: v1alpha2.ApplicationConfigurationSpec{
+				Components: []v1alpha2.ApplicationConfigurationComponent{
+					{ComponentName: "a", Traits: traits},
+					{ComponentName: "b", Traits: traits},
+				},
+			},
+		}
+	}
+	warning := util.DeprecationWarning(v1alpha2.TraitDefinitionKind, "ManualScaler", "Autoscaler")
+
+	tests := []struct {
+		caseName       string
+		reject         bool
+		appConfig      *v1alpha2.ApplicationConfiguration
+		old            *v1alpha2.ApplicationConfiguration
+		expectWarnings []string
+		expectResult   bool
+		expectReason   string
+	}{
+		{
+			caseName:     "Test validation passes for traits of current definitions",
+			appConfig:    appConfig(trait("Autoscaler")),
+			expectResult: true,
+		},
+		{
+			caseName:       "Test deprecated definitions are warned about once",
+			appConfig:      appConfig(trait("Autoscaler"), trait("ManualScaler")),
+			expectWarnings: []string{warning},
+			expectResult:   true,
+		},
+		{
+			caseName:     "Test validation fails for deprecated definitions if they are rejected",
+			reject:       true,
+			appConfig:    appConfig(trait("ManualScaler")),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtDeprecatedDefinition, warning),
+		},
+		{
+			caseName:       "Test validation passes for deprecated definitions that were already used",
+			reject:         true,
+			appConfig:      appConfig(trait("ManualScaler")),
+			old:            appConfig(trait("ManualScaler"), trait("Autoscaler")),
+			expectWarnings: []string{warning},
+			expectResult:   true,
+		},
+	}
+	for _, tc := range tests {
+		func(t *testing.T) {
+			warnings, result, reason := checkDeprecatedDefinitions(ctx, mockClient, mapper, tc.reject, tc.appConfig, tc.old)
+			assert.Equal(t, tc.expectWarnings, warnings, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+			assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+		}(t)
+	}
+}
+
+func TestDryRunRender(t *testing.T) {
+	cwRaw, _ := json.Marshal(v1alpha2.ContainerizedWorkload{})
+	mockClient := &test.MockClient{
+		MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+			if o, ok := obj.(*appsv1.ControllerRevision); ok {
+				*o = appsv1.ControllerRevision{Data: runtime.RawExtension{Object: &v1alpha2.Component{
+					Spec: v1alpha2.ComponentSpec{Workload: runtime.RawExtension{Raw: cwRaw}},
+				}}}
+			}
+			return nil
+		},
+		MockList: test.NewMockListFn(nil),
+	}
+	var scheme = runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	dec, _ := admission.NewDecoder(scheme)
+	app, _ := json.Marshal(v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{
+			{RevisionName: "r1"},
+		}}})
+	req := admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Resource: metav1.GroupVersionResource{Group: "core.oam.dev", Version: "v1alpha2", Resource: "applicationconfigurations"},
+			Object:   runtime.RawExtension{Raw: app},
+		},
+	}
+	renderErr := errors.New("required parameter \"image\" not specified")
+
+	tests := []struct {
+		caseName string
+		renderer DryRunRenderer
+		pass     bool
+		reason   string
+	}{
+		{
+			caseName: "Test validation passes when the ApplicationConfiguration renders",
+			renderer: DryRunRenderFn(func(context.Context, *v1alpha2.ApplicationConfiguration) ([]*unstructured.Unstructured, error) {
+				return nil, nil
+			}),
+			pass: true,
+		},
+		{
+			caseName: "Test validation fails with the render error",
+			renderer: DryRunRenderFn(func(_ context.Context, ac *v1alpha2.ApplicationConfiguration) ([]*unstructured.Unstructured, error) {
+				assert.Equal(t, "test", ac.GetName())
+				return nil, renderErr
+			}),
+			pass:   false,
+			reason: fmt.Sprintf(reasonFmtRenderFailed, renderErr.Error()),
+		},
+		{
+			caseName: "Test validation skips the dry-run render without a renderer",
+			pass:     true,
+		},
+	}
+	for _, tc := range tests {
+		h := &ValidatingHandler{Client: mockClient, Mapper: mock.NewMockDiscoveryMapper(), Decoder: dec, Renderer: tc.renderer}
+		resp := h.Handle(context.Background(), req)
+		assert.Equal(t, tc.pass, resp.Allowed, fmt.Sprintf("Test case: %q", tc.caseName))
+		assert.Equal(t, tc.reason, string(resp.Result.Reason), fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
+
+func TestCheckPolicies(t *testing.T) {
+	ctx := context.Background()
+	appConfig := &v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"}}
+	w := &unstructured.Unstructured{}
+	w.SetKind("Deployment")
+	w.SetName("web")
+	violation := policy.Violation{Policy: "registry.cue", Resource: `Deployment "web"`, Message: "image nginx is not trusted"}
+
+	tests := []struct {
+		caseName     string
+		policies     policy.Evaluator
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes without policies",
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation passes when no policy is violated",
+			policies: policy.EvaluateFn(func(_ context.Context, ac *v1alpha2.ApplicationConfiguration, resources []*unstructured.Unstructured) ([]policy.Violation, error) {
+				assert.Equal(t, appConfig, ac)
+				assert.Equal(t, []*unstructured.Unstructured{w}, resources)
+				return nil, nil
+			}),
+			expectResult: true,
+		},
+		{
+			caseName: "Test validation fails for every violated policy",
+			policies: policy.EvaluateFn(func(context.Context, *v1alpha2.ApplicationConfiguration, []*unstructured.Unstructured) ([]policy.Violation, error) {
+				return []policy.Violation{violation, violation}, nil
+			}),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtPolicyViolations, violation.String()+"; "+violation.String()),
+		},
+		{
+			caseName: "Test validation fails when policies cannot be evaluated",
+			policies: policy.EvaluateFn(func(context.Context, *v1alpha2.ApplicationConfiguration, []*unstructured.Unstructured) ([]policy.Violation, error) {
+				return nil, errors.New("boom")
+			}),
+			expectResult: false,
+			expectReason: fmt.Sprintf(errFmtCheckPolicies, "boom"),
+		},
+	}
+	for _, tc := range tests {
+		result, reason := checkPolicies(ctx, tc.policies, appConfig, []*unstructured.Unstructured{w})
+		assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+		assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
+
+func TestCheckResourceQuotaScopes(t *testing.T) {
+	ctx := context.Background()
+	mapper := mock.NewMockDiscoveryMapper()
+
+	// other workloads of the scope request 500m cpu, web requests 1 cpu
+	scope := func(hard, used, web string) *v1alpha2.ResourceQuotaScope {
+		return &v1alpha2.ResourceQuotaScope{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "budget"},
+			Spec: v1alpha2.ResourceQuotaScopeSpec{
+				Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(hard)},
+			},
+			Status: v1alpha2.ResourceQuotaScopeStatus{
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(used)},
+				Workloads: []v1alpha2.ResourceQuotaScopeWorkload{{
+					TargetWorkload: runtimev1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+					Used:           corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(web)},
+				}},
+			},
+		}
+	}
+	appConfig := &v1alpha2.ApplicationConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test-ns"},
+		Spec: v1alpha2.ApplicationConfigurationSpec{
+			Components: []v1alpha2.ApplicationConfigurationComponent{{
+				ComponentName: "web",
+				Scopes: []v1alpha2.ComponentScope{
+					{ScopeReference: runtimev1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "HealthScope", Name: "health"}},
+					{ScopeReference: runtimev1alpha1.TypedReference{APIVersion: "core.oam.dev/v1alpha2", Kind: "ResourceQuotaScope", Name: "budget"}},
+				},
+			}},
+		},
+	}
+	// web renders to a Deployment whose replicas request 750m cpu each
+	web := func(replicas int64) *unstructured.Unstructured {
+		w := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"namespace": "test-ns", "name": "web"},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{
+							"name":      "web",
+							"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "750m"}},
+						}},
+					},
+				},
+			},
+		}}
+		w.SetLabels(map[string]string{oam.LabelAppComponent: "web", oam.LabelOAMResourceType: oam.ResourceTypeWorkload})
+		return w
+	}
+	getScope := func(rs *v1alpha2.ResourceQuotaScope, err error) test.MockGetFn {
+		return func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+			o, ok := obj.(*v1alpha2.ResourceQuotaScope)
+			if !ok {
+				return kerrors.NewNotFound(schema.GroupResource{}, "")
+			}
+			if err != nil {
+				return err
+			}
+			*o = *rs
+			return nil
+		}
+	}
+
+	tests := []struct {
+		caseName     string
+		get          test.MockGetFn
+		workload     *unstructured.Unstructured
+		expectResult bool
+		expectReason string
+	}{
+		{
+			caseName:     "Test validation passes when the scope does not exceed its hard limits",
+			get:          getScope(scope("2", "1500m", "1"), nil),
+			workload:     web(2),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails when the scope would exceed its hard limits",
+			get:          getScope(scope("2", "1500m", "1"), nil),
+			workload:     web(3),
+			expectResult: false,
+			expectReason: fmt.Sprintf(reasonFmtResourceQuotaExceeded, "budget", 0, 1, "requests.cpu 2750m of 2"),
+		},
+		{
+			caseName:     "Test validation passes when the use of a scope that exceeds its hard limits decreases",
+			get:          getScope(scope("1", "3", "2500m"), nil),
+			workload:     web(1),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation passes when the scope does not exist",
+			get:          getScope(nil, kerrors.NewNotFound(schema.GroupResource{}, "budget")),
+			workload:     web(3),
+			expectResult: true,
+		},
+		{
+			caseName:     "Test validation fails when the scope cannot be fetched",
+			get:          getScope(nil, errors.New("boom")),
+			workload:     web(3),
+			expectResult: false,
+			expectReason: fmt.Sprintf(errFmtCheckResourceQuotas, "boom"),
+		},
+	}
+	for _, tc := range tests {
+		c := &test.MockClient{MockGet: tc.get}
+		result, reason := checkResourceQuotaScopes(ctx, c, mapper, appConfig, []*unstructured.Unstructured{tc.workload})
+		assert.Equal(t, tc.expectResult, result, fmt.Sprintf("Test case: %q", tc.caseName))
+		assert.Equal(t, tc.expectReason, reason, fmt.Sprintf("Test case: %q", tc.caseName))
+	}
+}
@@ -9,11 +9,15 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilpointer "k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
@@ -21,7 +25,9 @@ import (
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
 	. "github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/component"
 )
 
@@ -122,6 +128,15 @@ var _ = Describe("Component Admission controller Test", func() {
 			mutatedWorkload := baseWorkload.DeepCopy()
 			mutatedWorkload.SetNamespace(component.GetNamespace())
 			mutatedWorkload.SetLabels(util.MergeMap(label, map[string]string{oam.WorkloadTypeLabel: workloadTypeName}))
+			// set up the templated workloadDefinition and result
+			templatedWorkloadDef := workloadDef.DeepCopy()
+			templatedWorkloadDef.Spec.Schematic = &v1alpha2.Schematic{CUE: &v1alpha2.CUE{Template: "output: {}"}}
+			templatedWorkload := workloadWithType.DeepCopy()
+			delete(templatedWorkload.Object, TypeField)
+			templatedWorkload.SetAPIVersion(v1alpha2.SchemeGroupVersion.String())
+			templatedWorkload.SetKind(workloadTypeName)
+			templatedWorkload.SetNamespace(component.GetNamespace())
+			templatedWorkload.SetLabels(util.MergeMap(label, map[string]string{oam.WorkloadTypeLabel: workloadTypeName}))
 			tests := map[string]struct {
 				client   client.Client
 				workload interface{}
@@ -170,6 +185,21 @@ var _ = Describe("Component Admission controller Test", func() {
 					workload: workloadWithType.DeepCopyObject(),
 					wanted:   util.JSONMarshal(mutatedWorkload),
 				},
+				"templated workload case": {
+					client: &test.MockClient{
+						MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha2.WorkloadDefinition:
+								*o = *templatedWorkloadDef
+							case *crdv1.CustomResourceDefinition:
+								Fail("templated workload should not refer to a CRD")
+							}
+							return nil
+						},
+					},
+					workload: workloadWithType.DeepCopyObject(),
+					wanted:   util.JSONMarshal(templatedWorkload),
+				},
 			}
 			for testCase, test := range tests {
 				By(fmt.Sprintf("start test : %s", testCase))
@@ -189,7 +219,10 @@ var _ = Describe("Component Admission controller Test", func() {
 	})
 
 	It("Test validating handler", func() {
-		var handler admission.Handler = &ValidatingHandler{}
+		var handler admission.Handler = &ValidatingHandler{
+			Client: test.NewMockClient(),
+			Mapper: mock.NewMockDiscoveryMapper(),
+		}
 		decoderInjector := handler.(admission.DecoderInjector)
 		decoderInjector.InjectDecoder(decoder)
 		By("Creating valid workload")
@@ -269,4 +302,399 @@ var _ = Describe("Component Admission controller Test", func() {
 		Expect(resp.Allowed).Should(BeFalse())
 	})
 
+	It("Test validating workloads against the schema of their CRD", func() {
+		handler := &ValidatingHandler{Mapper: mock.NewMockDiscoveryMapper()}
+		handler.InjectDecoder(decoder)
+		schemaCRD := crd.DeepCopy()
+		schemaCRD.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"] = crdv1.JSONSchemaProps{
+			Type:     "object",
+			Required: []string{"image", "replicas"},
+			Properties: map[string]crdv1.JSONSchemaProps{
+				"image":    {Type: "string"},
+				"replicas": {Type: "integer"},
+			},
+		}
+		get := func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha2.WorkloadDefinition:
+				o.Spec.Reference.Name = schemaCRD.GetName()
+			case *crdv1.CustomResourceDefinition:
+				schemaCRD.DeepCopyInto(o)
+			}
+			return nil
+		}
+		workload := func(spec map[string]interface{}) runtime.RawExtension {
+			w := unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+			w.SetAPIVersion("example.com/v1")
+			w.SetKind("Foo")
+			return runtime.RawExtension{Raw: util.JSONMarshal(w.Object)}
+		}
+		component.Spec.Parameters = []v1alpha2.ComponentParameter{{Name: "image", FieldPaths: []string{"spec.image"}}}
+		tests := map[string]struct {
+			client    client.Client
+			workload  runtime.RawExtension
+			auxiliary []v1alpha2.AuxiliaryWorkload
+			pass      bool
+			reason    string
+		}{
+			"valid workload": {
+				client:   &test.MockClient{MockGet: get},
+				workload: workload(map[string]interface{}{"image": "nginx", "replicas": 2}),
+				pass:     true,
+			},
+			"required field set by a parameter": {
+				client:   &test.MockClient{MockGet: get},
+				workload: workload(map[string]interface{}{"replicas": 2}),
+				pass:     true,
+			},
+			"required field missing": {
+				client:   &test.MockClient{MockGet: get},
+				workload: workload(map[string]interface{}{"image": "nginx"}),
+				reason:   "spec.workload.spec.replicas: Required value",
+			},
+			"field of the wrong type": {
+				client:   &test.MockClient{MockGet: get},
+				workload: workload(map[string]interface{}{"image": "nginx", "replicas": "two"}),
+				reason:   "spec.workload.spec.replicas: Invalid value",
+			},
+			"invalid auxiliary workload": {
+				client:    &test.MockClient{MockGet: get},
+				workload:  workload(map[string]interface{}{"image": "nginx", "replicas": 2}),
+				auxiliary: []v1alpha2.AuxiliaryWorkload{{Name: "aux", Workload: workload(map[string]interface{}{"replicas": 2})}},
+				reason:    "spec.auxiliaryWorkloads[0].workload.spec.image: Required value",
+			},
+			"no WorkloadDefinition": {
+				client:   &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "foo"))},
+				workload: workload(map[string]interface{}{}),
+				pass:     true,
+			},
+			"get CRD error": {
+				client: &test.MockClient{MockGet: func(ctx context.Context, key types.NamespacedName, obj runtime.Object) error {
+					if _, ok := obj.(*crdv1.CustomResourceDefinition); ok {
+						return fmt.Errorf("boom")
+					}
+					return get(ctx, key, obj)
+				}},
+				workload: workload(map[string]interface{}{}),
+				reason:   "boom",
+			},
+		}
+		for testCase, test := range tests {
+			By(fmt.Sprintf("start test : %s", testCase))
+			handler.InjectClient(test.client)
+			c := component.DeepCopy()
+			c.Spec.Workload = test.workload
+			c.Spec.AuxiliaryWorkloads = test.auxiliary
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Create,
+					Resource:  reqResource,
+					Object:    runtime.RawExtension{Raw: util.JSONMarshal(c)},
+				},
+			}
+			resp := handler.Handle(context.TODO(), req)
+			Expect(resp.Allowed).Should(Equal(test.pass))
+			if !test.pass {
+				Expect(string(resp.Result.Reason)).Should(ContainSubstring(test.reason))
+			}
+		}
+	})
+
+	It("Test validating components that use deprecated definitions", func() {
+		// WorkloadDefinitions are deprecated unless they are named "current"
+		get := func(_ context.Context, key types.NamespacedName, obj runtime.Object) error {
+			if wd, ok := obj.(*v1alpha2.WorkloadDefinition); ok {
+				wd.SetName(key.Name)
+				wd.Spec.Deprecated = key.Name != "current"
+				wd.Spec.ReplacedBy = "current"
+			}
+			return nil
+		}
+		workload := func(definition string) runtime.RawExtension {
+			w := unstructured.Unstructured{}
+			w.SetAPIVersion("example.com/v1")
+			w.SetKind("Foo")
+			w.SetLabels(map[string]string{oam.WorkloadTypeLabel: definition})
+			return runtime.RawExtension{Raw: util.JSONMarshal(w.Object)}
+		}
+		warning := util.DeprecationWarning(v1alpha2.WorkloadDefinitionKind, "legacy", "current")
+		tests := map[string]struct {
+			reject    bool
+			operation admissionv1beta1.Operation
+			old       string
+			workload  string
+			pass      bool
+			warnings  []string
+		}{
+			"current definition": {
+				operation: admissionv1beta1.Create,
+				workload:  "current",
+				pass:      true,
+			},
+			"deprecated definition is warned about": {
+				operation: admissionv1beta1.Create,
+				workload:  "legacy",
+				pass:      true,
+				warnings:  []string{warning},
+			},
+			"deprecated definition is rejected": {
+				reject:    true,
+				operation: admissionv1beta1.Create,
+				workload:  "legacy",
+			},
+			"update starting to use a deprecated definition is rejected": {
+				reject:    true,
+				operation: admissionv1beta1.Update,
+				old:       "current",
+				workload:  "legacy",
+			},
+			"update of a component that already used a deprecated definition is warned about": {
+				reject:    true,
+				operation: admissionv1beta1.Update,
+				old:       "legacy",
+				workload:  "legacy",
+				pass:      true,
+				warnings:  []string{warning},
+			},
+		}
+		for testCase, tc := range tests {
+			By(fmt.Sprintf("start test : %s", testCase))
+			handler := &ValidatingHandler{Mapper: mock.NewMockDiscoveryMapper(), RejectDeprecated: tc.reject}
+			handler.InjectDecoder(decoder)
+			handler.InjectClient(&test.MockClient{MockGet: get, MockList: test.NewMockListFn(nil)})
+			c := component.DeepCopy()
+			c.Spec.Workload = workload(tc.workload)
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Operation: tc.operation,
+					Resource:  reqResource,
+					Object:    runtime.RawExtension{Raw: util.JSONMarshal(c)},
+				},
+			}
+			if tc.old != "" {
+				old := component.DeepCopy()
+				old.Spec.Workload = workload(tc.old)
+				req.OldObject = runtime.RawExtension{Raw: util.JSONMarshal(old)}
+			}
+			resp := handler.Handle(context.TODO(), req)
+			Expect(resp.Allowed).Should(Equal(tc.pass))
+			Expect(review.Warnings(resp)).Should(Equal(tc.warnings))
+			if !tc.pass {
+				Expect(string(resp.Result.Reason)).Should(ContainSubstring(warning))
+			}
+		}
+	})
+
+	It("Test validating updates of in-use components", func() {
+		handler := &ValidatingHandler{Mapper: mock.NewMockDiscoveryMapper()}
+		handler.InjectDecoder(decoder)
+		workload := func(kind string) runtime.RawExtension {
+			w := unstructured.Unstructured{}
+			w.SetAPIVersion("example.com/v1")
+			w.SetKind(kind)
+			return runtime.RawExtension{Raw: util.JSONMarshal(w.Object)}
+		}
+		existing := component.DeepCopy()
+		existing.Spec.Workload = workload("Foo")
+		trait := unstructured.Unstructured{}
+		trait.SetAPIVersion("example.com/v1")
+		trait.SetKind("Rollout")
+		appConfig := v1alpha2.ApplicationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-appconfig", Namespace: namespace},
+			Spec: v1alpha2.ApplicationConfigurationSpec{Components: []v1alpha2.ApplicationConfigurationComponent{{
+				ComponentName: componentName,
+				ParameterValues: []v1alpha2.ComponentParameterValue{
+					{Name: "image", Value: intstr.FromString("nginx")},
+				},
+				Traits: []v1alpha2.ComponentTrait{{Trait: runtime.RawExtension{Raw: util.JSONMarshal(trait.Object)}}},
+			}}},
+		}
+		list := func(acs ...v1alpha2.ApplicationConfiguration) test.MockListFn {
+			return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+				lo := &client.ListOptions{}
+				lo.ApplyOptions(opts)
+				Expect(lo.Namespace).Should(Equal(namespace))
+				Expect(lo.FieldSelector.String()).Should(Equal(util.ComponentNameIndex + "=" + componentName))
+				obj.(*v1alpha2.ApplicationConfigurationList).Items = acs
+				return nil
+			}
+		}
+		get := func(wdRevisionEnabled, tdRevisionEnabled bool) test.MockGetFn {
+			return func(_ context.Context, _ types.NamespacedName, obj runtime.Object) error {
+				switch o := obj.(type) {
+				case *v1alpha2.WorkloadDefinition:
+					o.Spec.RevisionEnabled = wdRevisionEnabled
+				case *v1alpha2.TraitDefinition:
+					if !tdRevisionEnabled {
+						return kerrors.NewNotFound(schema.GroupResource{}, "rollouts.example.com")
+					}
+					o.Spec.RevisionEnabled = true
+				}
+				return nil
+			}
+		}
+		kindChanged := existing.DeepCopy()
+		kindChanged.Spec.Workload = workload("Bar")
+		paramRemoved := existing.DeepCopy()
+		paramRemoved.Spec.Parameters = nil
+		paramAdded := existing.DeepCopy()
+		paramAdded.Spec.Parameters = append(paramAdded.Spec.Parameters, v1alpha2.ComponentParameter{
+			Name:       "replicas",
+			Required:   utilpointer.BoolPtr(true),
+			FieldPaths: []string{"spec.replicas"},
+		})
+		tests := map[string]struct {
+			client  client.Client
+			updated *v1alpha2.Component
+			pass    bool
+			reason  string
+		}{
+			"not in use": {
+				client:  &test.MockClient{MockList: list(), MockGet: test.NewMockGetFn(nil)},
+				updated: kindChanged,
+				pass:    true,
+			},
+			"list error": {
+				client:  &test.MockClient{MockList: test.NewMockListFn(fmt.Errorf("boom")), MockGet: test.NewMockGetFn(nil)},
+				updated: kindChanged,
+				reason:  "boom",
+			},
+			"non-breaking update": {
+				client:  &test.MockClient{MockList: list(appConfig), MockGet: get(false, false)},
+				updated: existing.DeepCopy(),
+				pass:    true,
+			},
+			"workload type changed": {
+				client:  &test.MockClient{MockList: list(appConfig), MockGet: get(false, false)},
+				updated: kindChanged,
+				reason:  "workload type changed",
+			},
+			"assigned parameter removed": {
+				client:  &test.MockClient{MockList: list(appConfig), MockGet: get(false, false)},
+				updated: paramRemoved,
+				reason:  `parameter "image" it assigns was removed`,
+			},
+			"required parameter added": {
+				client:  &test.MockClient{MockList: list(appConfig), MockGet: get(false, false)},
+				updated: paramAdded,
+				reason:  `required parameter "replicas" it doesn't assign was added`,
+			},
+			"revisionEnabled workload": {
+				client:  &test.MockClient{MockList: list(appConfig), MockGet: get(true, false)},
+				updated: kindChanged,
+				pass:    true,
+			},
+			"revisionEnabled trait": {
+				client:  &test.MockClient{MockList: list(appConfig), MockGet: get(false, true)},
+				updated: kindChanged,
+				pass:    true,
+			},
+		}
+		for testCase, test := range tests {
+			By(fmt.Sprintf("start test : %s", testCase))
+			handler.InjectClient(test.client)
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Update,
+					Resource:  reqResource,
+					Object:    runtime.RawExtension{Raw: util.JSONMarshal(test.updated)},
+					OldObject: runtime.RawExtension{Raw: util.JSONMarshal(existing)},
+				},
+			}
+			resp := handler.Handle(context.TODO(), req)
+			Expect(resp.Allowed).Should(Equal(test.pass))
+			if !test.pass {
+				Expect(string(resp.Result.Reason)).Should(ContainSubstring(test.reason))
+			}
+		}
+	})
+
+	It("Test validating the image pull secrets of workloads", func() {
+		handler := &ValidatingHandler{Mapper: mock.NewMockDiscoveryMapper()}
+		handler.InjectDecoder(decoder)
+		handler.InjectClient(&test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "foo"))})
+		// only the secret named "exists" exists
+		secrets := &test.MockClient{MockGet: func(_ context.Context, key types.NamespacedName, obj runtime.Object) error {
+			if _, ok := obj.(*corev1.Secret); ok && key.Name == "exists" && key.Namespace == namespace {
+				return nil
+			}
+			return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+		}}
+		workload := func(spec v1alpha2.ContainerizedWorkloadSpec) runtime.RawExtension {
+			return runtime.RawExtension{Raw: util.JSONMarshal(v1alpha2.ContainerizedWorkload{
+				TypeMeta: metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.ContainerizedWorkloadKind},
+				Spec:     spec,
+			})}
+		}
+		exists, missing := "exists", "missing"
+		tests := map[string]struct {
+			secrets    client.Reader
+			workload   runtime.RawExtension
+			auxiliary  []v1alpha2.AuxiliaryWorkload
+			parameters []v1alpha2.ComponentParameter
+			pass       bool
+			reason     string
+		}{
+			"existing secrets": {
+				secrets: secrets,
+				workload: workload(v1alpha2.ContainerizedWorkloadSpec{
+					ImagePullSecrets: []string{exists},
+					Containers:       []v1alpha2.Container{{Name: "app", Image: "app", ImagePullSecret: &exists}},
+				}),
+				pass: true,
+			},
+			"missing workload secret": {
+				secrets:  secrets,
+				workload: workload(v1alpha2.ContainerizedWorkloadSpec{ImagePullSecrets: []string{exists, missing}}),
+				reason:   `spec.workload.spec.imagePullSecrets[1]: Not found: "missing"`,
+			},
+			"missing container secret": {
+				secrets: secrets,
+				workload: workload(v1alpha2.ContainerizedWorkloadSpec{
+					InitContainers: []v1alpha2.Container{{Name: "init", Image: "init", ImagePullSecret: &missing}},
+				}),
+				reason: `spec.workload.spec.initContainers[0].imagePullSecret: Not found: "missing"`,
+			},
+			"missing auxiliary workload secret": {
+				secrets:   secrets,
+				workload:  workload(v1alpha2.ContainerizedWorkloadSpec{}),
+				auxiliary: []v1alpha2.AuxiliaryWorkload{{Name: "aux", Workload: workload(v1alpha2.ContainerizedWorkloadSpec{ImagePullSecrets: []string{missing}})}},
+				reason:    `spec.auxiliaryWorkloads[0].workload.spec.imagePullSecrets[0]: Not found: "missing"`,
+			},
+			"secret set by a parameter": {
+				secrets: secrets,
+				workload: workload(v1alpha2.ContainerizedWorkloadSpec{
+					Containers: []v1alpha2.Container{{Name: "app", Image: "app", ImagePullSecret: &missing}},
+				}),
+				parameters: []v1alpha2.ComponentParameter{{Name: "secret", FieldPaths: []string{"spec.containers[0].imagePullSecret"}}},
+				pass:       true,
+			},
+			"get secret error": {
+				secrets:  &test.MockClient{MockGet: test.NewMockGetFn(fmt.Errorf("boom"))},
+				workload: workload(v1alpha2.ContainerizedWorkloadSpec{ImagePullSecrets: []string{exists}}),
+				reason:   "boom",
+			},
+		}
+		for testCase, test := range tests {
+			By(fmt.Sprintf("start test : %s", testCase))
+			handler.SecretReader = test.secrets
+			c := component.DeepCopy()
+			c.Spec.Workload = test.workload
+			c.Spec.AuxiliaryWorkloads = test.auxiliary
+			c.Spec.Parameters = test.parameters
+			req := admission.Request{
+				AdmissionRequest: admissionv1beta1.AdmissionRequest{
+					Operation: admissionv1beta1.Create,
+					Resource:  reqResource,
+					Object:    runtime.RawExtension{Raw: util.JSONMarshal(c)},
+				},
+			}
+			resp := handler.Handle(context.TODO(), req)
+			Expect(resp.Allowed).Should(Equal(test.pass))
+			if !test.pass {
+				Expect(string(resp.Result.Reason)).Should(ContainSubstring(test.reason))
+			}
+		}
+	})
+
 })
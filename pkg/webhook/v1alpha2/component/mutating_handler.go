@@ -30,12 +30,13 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/schematic"
 	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
 )
 
 const (
@@ -102,23 +103,14 @@ func (h *MutatingHandler) Mutate(obj *v1alpha2.Component) error {
 		if err := h.Client.Get(context.TODO(), types.NamespacedName{Name: workloadType}, workloadDefinition); err != nil {
 			return err
 		}
-		// fetch the CRDs definition
-		customResourceDefinition := &crdv1.CustomResourceDefinition{}
-		if err := h.Client.Get(context.TODO(), types.NamespacedName{Name: workloadDefinition.Spec.Reference.Name}, customResourceDefinition); err != nil {
-			return err
-		}
 		// reconstruct the workload CR
 		delete(content, TypeField)
 		workload := unstructured.Unstructured{
 			Object: content,
 		}
-		// find out the GVK from the CRD definition and set
-		apiVersion := metav1.GroupVersion{
-			Group:   customResourceDefinition.Spec.Group,
-			Version: customResourceDefinition.Spec.Versions[0].Name,
-		}.String()
-		workload.SetAPIVersion(apiVersion)
-		workload.SetKind(customResourceDefinition.Spec.Names.Kind)
+		if err := h.setWorkloadGVK(&workload, workloadType, workloadDefinition); err != nil {
+			return err
+		}
 		mutatelog.Info("Set the component workload GVK", "workload api version", workload.GetAPIVersion(), "workload Kind", workload.GetKind())
 		// copy namespace/label/annotation to the workload and add workloadType label
 		workload.SetNamespace(obj.GetNamespace())
@@ -135,6 +127,38 @@ func (h *MutatingHandler) Mutate(obj *v1alpha2.Component) error {
 	return nil
 }
 
+// setWorkloadGVK sets the GVK of the workload according to the CRD that its workloadDefinition refers to
+func (h *MutatingHandler) setWorkloadGVK(workload *unstructured.Unstructured, workloadType string,
+	workloadDefinition *v1alpha2.WorkloadDefinition) error {
+	if schematic.IsTemplated(workloadDefinition.Spec.Schematic) {
+		// the workload is rendered from the template of the workloadDefinition, so there is no CRD to refer to
+		if workload.GetAPIVersion() == "" {
+			workload.SetAPIVersion(v1alpha2.SchemeGroupVersion.String())
+		}
+		if workload.GetKind() == "" {
+			workload.SetKind(workloadType)
+		}
+		return nil
+	}
+	// fetch the CRDs definition
+	customResourceDefinition := &crdv1.CustomResourceDefinition{}
+	if err := h.Client.Get(context.TODO(), types.NamespacedName{Name: workloadDefinition.Spec.Reference.Name}, customResourceDefinition); err != nil {
+		return err
+	}
+	// find out the GVK from the CRD definition and set
+	version, err := util.DefinitionVersion(customResourceDefinition, workloadDefinition.Spec.Reference)
+	if err != nil {
+		return err
+	}
+	apiVersion := metav1.GroupVersion{
+		Group:   customResourceDefinition.Spec.Group,
+		Version: version,
+	}.String()
+	workload.SetAPIVersion(apiVersion)
+	workload.SetKind(customResourceDefinition.Spec.Names.Kind)
+	return nil
+}
+
 var _ inject.Client = &MutatingHandler{}
 
 // InjectClient injects the client into the ComponentMutatingHandler
@@ -151,8 +175,11 @@ func (h *MutatingHandler) InjectDecoder(d *admission.Decoder) error {
 	return nil
 }
 
+// MutatingHandlerPath is the path component mutation is served at by default
+const MutatingHandlerPath = "/mutating-core-oam-dev-v1alpha2-components"
+
 // RegisterMutatingHandler will register component mutation handler to the webhook
 func RegisterMutatingHandler(mgr manager.Manager) {
 	server := mgr.GetWebhookServer()
-	server.Register("/mutating-core-oam-dev-v1alpha2-components", &webhook.Admission{Handler: &MutatingHandler{}})
+	server.Register(MutatingHandlerPath, review.New(&MutatingHandler{}))
 }
@@ -20,26 +20,63 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/render"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
+)
+
+const (
+	reasonFmtBreakingUpdate = "component %q is used by ApplicationConfiguration %q without revisioning and the update " +
+		"is breaking: %s; make its WorkloadDefinition or one of its traits revisionEnabled, or pin the " +
+		"ApplicationConfiguration to a revisionName"
+
+	reasonFmtWorkloadTypeChanged     = "workload type changed from %q to %q"
+	reasonFmtParameterRemoved        = "parameter %q it assigns was removed"
+	reasonFmtRequiredParameterAdded  = "required parameter %q it doesn't assign was added"
+	errFmtCheckComponentInUse        = "cannot check ApplicationConfigurations that use component %q: %v"
+	errFmtCheckComponentRevisionable = "cannot check whether component %q is revisionEnabled: %v"
+
+	reasonFmtInvalidWorkloadSchema = "workloads of component %q MUST match the schema of their CustomResourceDefinition: %s"
+	errFmtCheckWorkloadSchema      = "cannot check the schema of the workloads of component %q: %v"
+
+	reasonFmtDeprecatedDefinition = "component %q MUST NOT start using a deprecated definition: %s"
+
+	reasonFmtMissingPullSecrets = "workloads of component %q MUST refer to existing image pull secrets: %s"
+	errFmtCheckPullSecrets      = "cannot check the image pull secrets of the workloads of component %q: %v"
 )
 
 // ValidatingHandler handles Component
 type ValidatingHandler struct {
-	// To use the client, you need to do the following:
-	// - uncomment it
-	// - import sigs.k8s.io/controller-runtime/pkg/client
-	// - uncomment the InjectClient method at the bottom of this file.
-	// Client  client.Client
+	Client client.Reader
+	Mapper discoverymapper.DiscoveryMapper
+
+	// SecretReader reads the image pull secrets workloads refer to. It
+	// should not be cached, so that the secrets of the cluster aren't. The
+	// Client reads them if it is nil.
+	SecretReader client.Reader
+
+	// RejectDeprecated rejects components that start using deprecated
+	// WorkloadDefinitions, rather than warning about them.
+	RejectDeprecated bool
 
 	// Decoder decodes objects
 	Decoder *admission.Decoder
@@ -61,20 +98,108 @@ func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) a
 		return admission.Denied(err.Error())
 	}
 
+	var old *v1alpha2.Component
 	switch req.AdmissionRequest.Operation { //nolint:exhaustive
 	case admissionv1beta1.Create:
 		if allErrs := ValidateComponentObject(obj); len(allErrs) > 0 {
 			validatelog.Info("create failed", "name", obj.Name, "errMsg", allErrs.ToAggregate().Error())
 			return admission.Denied(allErrs.ToAggregate().Error())
 		}
+		if pass, reason := h.checkWorkloadSchema(ctx, obj); !pass {
+			validatelog.Info("create failed", "name", obj.Name, "errMsg", reason)
+			return admission.Denied(reason)
+		}
+		if pass, reason := h.checkPullSecrets(ctx, obj); !pass {
+			validatelog.Info("create failed", "name", obj.Name, "errMsg", reason)
+			return admission.Denied(reason)
+		}
 	case admissionv1beta1.Update:
 		if allErrs := ValidateComponentObject(obj); len(allErrs) > 0 {
 			validatelog.Info("update failed", "name", obj.Name, "errMsg", allErrs.ToAggregate().Error())
 			return admission.Denied(allErrs.ToAggregate().Error())
 		}
+		if pass, reason := h.checkWorkloadSchema(ctx, obj); !pass {
+			validatelog.Info("update failed", "name", obj.Name, "errMsg", reason)
+			return admission.Denied(reason)
+		}
+		if pass, reason := h.checkPullSecrets(ctx, obj); !pass {
+			validatelog.Info("update failed", "name", obj.Name, "errMsg", reason)
+			return admission.Denied(reason)
+		}
+		if len(req.OldObject.Raw) != 0 {
+			old = &v1alpha2.Component{}
+			if err := h.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			if pass, reason := h.checkInUseUpdate(ctx, old, obj); !pass {
+				validatelog.Info("update failed", "name", obj.Name, "errMsg", reason)
+				return admission.Denied(reason)
+			}
+		}
+	default:
+		return admission.Allowed("")
+	}
+
+	warnings, pass, reason := h.checkDeprecatedDefinitions(ctx, obj, old)
+	if !pass {
+		validatelog.Info("admission failed", "name", obj.Name, "errMsg", reason)
+		return admission.Denied(reason)
+	}
+	return review.WithWarnings(admission.Allowed(""), warnings...)
+}
+
+// checkDeprecatedDefinitions returns a warning for each deprecated
+// WorkloadDefinition the supplied component uses. It rejects the component
+// if the handler rejects deprecated definitions and the supplied old
+// component, if any, didn't use one of them yet.
+func (h *ValidatingHandler) checkDeprecatedDefinitions(ctx context.Context, obj, old *v1alpha2.Component) ([]string, bool, string) {
+	used := map[string]bool{}
+	if old != nil {
+		for _, wd := range h.workloadDefinitions(ctx, old) {
+			used[wd.GetName()] = true
+		}
+	}
+	var warnings, rejected []string
+	for _, wd := range h.workloadDefinitions(ctx, obj) {
+		if !wd.Spec.Deprecated {
+			continue
+		}
+		warning := util.DeprecationWarning(v1alpha2.WorkloadDefinitionKind, wd.GetName(), wd.Spec.ReplacedBy)
+		warnings = append(warnings, warning)
+		if h.RejectDeprecated && !used[wd.GetName()] {
+			rejected = append(rejected, warning)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, false, fmt.Sprintf(reasonFmtDeprecatedDefinition, obj.GetName(), strings.Join(rejected, "; "))
 	}
+	return warnings, true, ""
+}
 
-	return admission.Allowed("")
+// workloadDefinitions returns the distinct WorkloadDefinitions of the
+// workloads of the supplied component. Workloads that are malformed, or whose
+// definition can't be fetched, are skipped; other checks reject them.
+func (h *ValidatingHandler) workloadDefinitions(ctx context.Context, comp *v1alpha2.Component) []*v1alpha2.WorkloadDefinition {
+	raws := [][]byte{comp.Spec.Workload.Raw}
+	for _, aw := range comp.Spec.AuxiliaryWorkloads {
+		raws = append(raws, aw.Workload.Raw)
+	}
+	seen := map[string]bool{}
+	var defs []*v1alpha2.WorkloadDefinition
+	for _, raw := range raws {
+		w, err := unmarshalUnstructured(raw)
+		if err != nil || w.GetKind() == "" {
+			continue
+		}
+		w.SetNamespace(comp.GetNamespace())
+		wd, err := util.FetchWorkloadDefinition(ctx, h.Client, h.Mapper, w)
+		if err != nil || seen[wd.GetName()] {
+			continue
+		}
+		seen[wd.GetName()] = true
+		defs = append(defs, wd)
+	}
+	return defs
 }
 
 // ValidateComponentObject validates the Component on creation
@@ -103,7 +228,276 @@ func ValidateComponentObject(obj *v1alpha2.Component) field.ErrorList {
 	return allErrs
 }
 
-/*
+// checkWorkloadSchema rejects components whose workloads don't match the
+// OpenAPI schema of the CRD their WorkloadDefinition refers to, because every
+// ApplicationConfiguration that uses them would fail to render. Fields that
+// the schema requires may be omitted from the main workload if parameters set
+// them.
+func (h *ValidatingHandler) checkWorkloadSchema(ctx context.Context, obj *v1alpha2.Component) (bool, string) {
+	var parameterized []string
+	for _, p := range obj.Spec.Parameters {
+		parameterized = append(parameterized, p.FieldPaths...)
+	}
+	fldPath := field.NewPath("spec")
+	allErrs, err := h.validateWorkloadSchema(ctx, fldPath.Child("workload"), obj.Spec.Workload.Raw, parameterized)
+	if err != nil {
+		return false, fmt.Sprintf(errFmtCheckWorkloadSchema, obj.GetName(), err)
+	}
+	for i, aw := range obj.Spec.AuxiliaryWorkloads {
+		errs, err := h.validateWorkloadSchema(ctx, fldPath.Child("auxiliaryWorkloads").Index(i).Child("workload"),
+			aw.Workload.Raw, nil)
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckWorkloadSchema, obj.GetName(), err)
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	if len(allErrs) > 0 {
+		return false, fmt.Sprintf(reasonFmtInvalidWorkloadSchema, obj.GetName(), allErrs.ToAggregate().Error())
+	}
+	return true, ""
+}
+
+// validateWorkloadSchema returns the fields of the supplied raw workload that
+// don't match the schema of its CRD, except required fields that are set by
+// the supplied parameter field paths.
+func (h *ValidatingHandler) validateWorkloadSchema(ctx context.Context, fldPath *field.Path, raw []byte,
+	parameterized []string) (field.ErrorList, error) {
+	w, err := unmarshalUnstructured(raw)
+	if err != nil || w.GetAPIVersion() == "" || w.GetKind() == "" {
+		// malformed workloads are rejected by ValidateComponentObject
+		return nil, nil
+	}
+	errs, err := render.ValidateWorkloadSchema(ctx, h.Client, h.Mapper, w)
+	if err != nil {
+		return nil, err
+	}
+	allErrs := field.ErrorList{}
+	for _, e := range errs {
+		if e.Type == field.ErrorTypeRequired && setByParameter(e.Field, parameterized) {
+			continue
+		}
+		if e.Field == "" {
+			e.Field = fldPath.String()
+		} else {
+			e.Field = fldPath.String() + "." + e.Field
+		}
+		allErrs = append(allErrs, e)
+	}
+	return allErrs, nil
+}
+
+// checkPullSecrets rejects components whose ContainerizedWorkloads, or
+// workloads that embed their spec, refer to image pull secrets that don't
+// exist in the namespace of the component, because their pods could not pull
+// their images. Secrets that parameters set are not checked.
+func (h *ValidatingHandler) checkPullSecrets(ctx context.Context, obj *v1alpha2.Component) (bool, string) {
+	var parameterized []string
+	for _, p := range obj.Spec.Parameters {
+		parameterized = append(parameterized, p.FieldPaths...)
+	}
+	fldPath := field.NewPath("spec")
+	refs := pullSecretRefs(fldPath.Child("workload"), obj.Spec.Workload.Raw, parameterized)
+	for i, aw := range obj.Spec.AuxiliaryWorkloads {
+		refs = append(refs, pullSecretRefs(fldPath.Child("auxiliaryWorkloads").Index(i).Child("workload"), aw.Workload.Raw, nil)...)
+	}
+	r := h.SecretReader
+	if r == nil {
+		r = h.Client
+	}
+	allErrs := field.ErrorList{}
+	for _, ref := range refs {
+		err := r.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.name}, &corev1.Secret{})
+		if apierrors.IsNotFound(err) {
+			allErrs = append(allErrs, field.NotFound(ref.path, ref.name))
+			continue
+		}
+		if err != nil {
+			return false, fmt.Sprintf(errFmtCheckPullSecrets, obj.GetName(), err)
+		}
+	}
+	if len(allErrs) > 0 {
+		return false, fmt.Sprintf(reasonFmtMissingPullSecrets, obj.GetName(), allErrs.ToAggregate().Error())
+	}
+	return true, ""
+}
+
+// A pullSecretRef is an image pull secret a workload refers to.
+type pullSecretRef struct {
+	path *field.Path
+	name string
+}
+
+// pullSecretRefs returns the image pull secrets the supplied raw workload
+// refers to if it is a ContainerizedWorkload, or a workload of this runtime
+// that embeds its spec, except those set by the supplied parameter field
+// paths.
+func pullSecretRefs(fldPath *field.Path, raw []byte, parameterized []string) []pullSecretRef {
+	w, err := unmarshalUnstructured(raw)
+	if err != nil || w.GroupVersionKind().GroupVersion() != v1alpha2.SchemeGroupVersion {
+		return nil
+	}
+	switch w.GetKind() {
+	case v1alpha2.ContainerizedWorkloadKind, v1alpha2.TaskWorkloadKind, v1alpha2.StatefulWorkloadKind, v1alpha2.DaemonWorkloadKind:
+	default:
+		return nil
+	}
+	spec := struct {
+		Spec v1alpha2.ContainerizedWorkloadSpec `json:"spec"`
+	}{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		// malformed workloads are rejected by the schema of their CRD
+		return nil
+	}
+	// a parameter may set the secret, or a field the secret is within
+	parameterSet := func(path *field.Path) bool {
+		p := strings.TrimPrefix(path.String(), fldPath.String()+".")
+		for _, fp := range parameterized {
+			if setByParameter(p, []string{fp}) || setByParameter(fp, []string{p}) {
+				return true
+			}
+		}
+		return false
+	}
+	var refs []pullSecretRef
+	add := func(path *field.Path, name string) {
+		if name != "" && !parameterSet(path) {
+			refs = append(refs, pullSecretRef{path: path, name: name})
+		}
+	}
+	specPath := fldPath.Child("spec")
+	for i, name := range spec.Spec.ImagePullSecrets {
+		add(specPath.Child("imagePullSecrets").Index(i), name)
+	}
+	for _, c := range []struct {
+		field      string
+		containers []v1alpha2.Container
+	}{{"initContainers", spec.Spec.InitContainers}, {"containers", spec.Spec.Containers}} {
+		for i, container := range c.containers {
+			if container.ImagePullSecret != nil {
+				add(specPath.Child(c.field).Index(i).Child("imagePullSecret"), *container.ImagePullSecret)
+			}
+		}
+	}
+	return refs
+}
+
+// setByParameter returns true if the supplied field, or a field within it, is
+// one of the supplied parameter field paths.
+func setByParameter(fieldPath string, parameterized []string) bool {
+	for _, fp := range parameterized {
+		if fp == fieldPath || strings.HasPrefix(fp, fieldPath+".") || strings.HasPrefix(fp, fieldPath+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkInUseUpdate rejects breaking updates of a component that is used by
+// ApplicationConfigurations that follow its latest revision, unless the
+// update is rolled out to them as a new revision.
+func (h *ValidatingHandler) checkInUseUpdate(ctx context.Context, old, obj *v1alpha2.Component) (bool, string) {
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := h.Client.List(ctx, acs, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{util.ComponentNameIndex: obj.GetName()}); err != nil {
+		return false, fmt.Sprintf(errFmtCheckComponentInUse, obj.GetName(), err)
+	}
+	if len(acs.Items) == 0 {
+		return true, ""
+	}
+	w, err := unmarshalUnstructured(obj.Spec.Workload.Raw)
+	if err != nil {
+		return false, fmt.Sprintf(errFmtCheckComponentRevisionable, obj.GetName(), err)
+	}
+	w.SetNamespace(obj.GetNamespace())
+	wd, err := util.FetchWorkloadDefinition(ctx, h.Client, h.Mapper, w)
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return false, fmt.Sprintf(errFmtCheckComponentRevisionable, obj.GetName(), err)
+	}
+	if err == nil && wd.Spec.RevisionEnabled {
+		return true, ""
+	}
+	for _, ac := range acs.Items {
+		for _, acc := range ac.Spec.Components {
+			if acc.ComponentName != obj.GetName() {
+				continue
+			}
+			reason := breakingChange(old, obj, acc)
+			if reason == "" {
+				continue
+			}
+			enabled, err := h.traitRevisionEnabled(ctx, ac.GetNamespace(), acc)
+			if err != nil {
+				return false, fmt.Sprintf(errFmtCheckComponentRevisionable, obj.GetName(), err)
+			}
+			if !enabled {
+				return false, fmt.Sprintf(reasonFmtBreakingUpdate, obj.GetName(), ac.GetName(), reason)
+			}
+		}
+	}
+	return true, ""
+}
+
+// traitRevisionEnabled returns true if any trait of the supplied component of
+// an ApplicationConfiguration of the supplied namespace is revisionEnabled.
+func (h *ValidatingHandler) traitRevisionEnabled(ctx context.Context, namespace string,
+	acc v1alpha2.ApplicationConfigurationComponent) (bool, error) {
+	for _, ct := range acc.Traits {
+		t, err := unmarshalUnstructured(ct.Trait.Raw)
+		if err != nil {
+			return false, err
+		}
+		t.SetNamespace(namespace)
+		td, err := util.FetchTraitDefinition(ctx, h.Client, h.Mapper, t)
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if td.Spec.RevisionEnabled {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// breakingChange returns why updating the old component to the new one breaks
+// the supplied ApplicationConfiguration component, or an empty string if it
+// doesn't.
+func breakingChange(old, obj *v1alpha2.Component, acc v1alpha2.ApplicationConfigurationComponent) string {
+	ow, oerr := unmarshalUnstructured(old.Spec.Workload.Raw)
+	nw, nerr := unmarshalUnstructured(obj.Spec.Workload.Raw)
+	if oerr == nil && nerr == nil && ow.GroupVersionKind() != nw.GroupVersionKind() {
+		return fmt.Sprintf(reasonFmtWorkloadTypeChanged, ow.GroupVersionKind(), nw.GroupVersionKind())
+	}
+	defined := make(map[string]bool, len(obj.Spec.Parameters))
+	for _, p := range obj.Spec.Parameters {
+		defined[p.Name] = true
+	}
+	assigned := make(map[string]bool, len(acc.ParameterValues))
+	for _, v := range acc.ParameterValues {
+		assigned[v.Name] = true
+		if !defined[v.Name] {
+			return fmt.Sprintf(reasonFmtParameterRemoved, v.Name)
+		}
+	}
+	for _, p := range obj.Spec.Parameters {
+		if p.Required != nil && *p.Required && p.Default == nil && !assigned[p.Name] {
+			return fmt.Sprintf(reasonFmtRequiredParameterAdded, p.Name)
+		}
+	}
+	return ""
+}
+
+// unmarshalUnstructured unmarshals the supplied raw workload or trait.
+func unmarshalUnstructured(raw []byte) (*unstructured.Unstructured, error) {
+	w := &unstructured.Unstructured{}
+	if err := json.Unmarshal(raw, &w.Object); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
 var _ inject.Client = &ValidatingHandler{}
 
 // InjectClient injects the client into the ComponentValidatingHandler
@@ -111,7 +505,7 @@ func (h *ValidatingHandler) InjectClient(c client.Client) error {
 	h.Client = c
 	return nil
 }
-*/
+
 var _ admission.DecoderInjector = &ValidatingHandler{}
 
 // InjectDecoder injects the decoder into the ComponentValidatingHandler
@@ -120,8 +514,27 @@ func (h *ValidatingHandler) InjectDecoder(d *admission.Decoder) error {
 	return nil
 }
 
+// ValidatingHandlerPath is the path component validation is served at by
+// default
+const ValidatingHandlerPath = "/validating-core-oam-dev-v1alpha2-components"
+
+// NewValidatingHandler returns a component validation handler for the
+// supplied manager
+func NewValidatingHandler(mgr manager.Manager) (*ValidatingHandler, error) {
+	mapper, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatingHandler{Mapper: mapper, SecretReader: mgr.GetAPIReader()}, nil
+}
+
 // RegisterValidatingHandler will regsiter component mutation handler to the webhook
-func RegisterValidatingHandler(mgr manager.Manager) {
+func RegisterValidatingHandler(mgr manager.Manager) error {
 	server := mgr.GetWebhookServer()
-	server.Register("/validating-core-oam-dev-v1alpha2-components", &webhook.Admission{Handler: &ValidatingHandler{}})
+	h, err := NewValidatingHandler(mgr)
+	if err != nil {
+		return err
+	}
+	server.Register(ValidatingHandlerPath, review.New(h))
+	return nil
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerrevision
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
+)
+
+const (
+	reasonFmtRevisionInUse = "component revision %q MUST NOT be deleted while it is used by ApplicationConfiguration(s) %s"
+
+	errFmtListAppConfigs = "cannot list ApplicationConfigurations that use component revision %q: %v"
+)
+
+// ValidatingHandler prevents component revisions that are referenced by the
+// revisionName of an ApplicationConfiguration from being deleted.
+type ValidatingHandler struct {
+	Client client.Reader
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &ValidatingHandler{}
+
+// Handle validates the deletion of ControllerRevisions here
+func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Delete || len(req.OldObject.Raw) == 0 {
+		return admission.Allowed("")
+	}
+	rev := &appsv1.ControllerRevision{}
+	if err := h.Decoder.DecodeRaw(req.OldObject, rev); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !isComponentRevision(rev) {
+		return admission.Allowed("")
+	}
+	acs := &v1alpha2.ApplicationConfigurationList{}
+	if err := h.Client.List(ctx, acs, client.InNamespace(rev.GetNamespace()),
+		client.MatchingFields{util.RevisionNameIndex: rev.GetName()}); err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf(errFmtListAppConfigs, rev.GetName(), err))
+	}
+	if len(acs.Items) == 0 {
+		return admission.Allowed("")
+	}
+	names := make([]string, 0, len(acs.Items))
+	for _, ac := range acs.Items {
+		names = append(names, fmt.Sprintf("%q", ac.GetName()))
+	}
+	return admission.Denied(fmt.Sprintf(reasonFmtRevisionInUse, rev.GetName(), strings.Join(names, ", ")))
+}
+
+// isComponentRevision returns true if the supplied ControllerRevision is a
+// revision of an OAM Component.
+func isComponentRevision(rev *appsv1.ControllerRevision) bool {
+	for _, o := range rev.GetOwnerReferences() {
+		if o.Kind == v1alpha2.ComponentKind && o.APIVersion == v1alpha2.SchemeGroupVersion.String() {
+			return true
+		}
+	}
+	return false
+}
+
+var _ inject.Client = &ValidatingHandler{}
+
+// InjectClient injects the client into the ValidatingHandler
+func (h *ValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &ValidatingHandler{}
+
+// InjectDecoder injects the decoder into the ValidatingHandler
+func (h *ValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
+
+// ValidatingHandlerPath is the path controller revision validation is served
+// at by default
+const ValidatingHandlerPath = "/validating-apps-v1-controllerrevisions"
+
+// RegisterValidatingHandler will register component revision validation to webhook
+func RegisterValidatingHandler(mgr manager.Manager) {
+	server := mgr.GetWebhookServer()
+	server.Register(ValidatingHandlerPath, review.New(&ValidatingHandler{}))
+}
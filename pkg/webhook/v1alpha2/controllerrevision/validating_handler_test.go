@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerrevision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestControllerRevisionValidation(t *testing.T) {
+	revName := "comp-v1"
+	errBoom := errors.New("boom")
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	dec, _ := admission.NewDecoder(scheme)
+
+	componentRev, _ := json.Marshal(appsv1.ControllerRevision{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ControllerRevision"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      revName,
+			Namespace: "test-ns",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: v1alpha2.SchemeGroupVersion.String(),
+				Kind:       v1alpha2.ComponentKind,
+				Name:       "comp",
+			}},
+		},
+	})
+	otherRev, _ := json.Marshal(appsv1.ControllerRevision{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ControllerRevision"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sts-1234",
+			Namespace: "test-ns",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "StatefulSet",
+				Name:       "sts",
+			}},
+		},
+	})
+	deleteReq := func(raw []byte) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Delete,
+			Name:      revName,
+			Namespace: "test-ns",
+			OldObject: runtime.RawExtension{Raw: raw},
+		}}
+	}
+	list := func(names ...string) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			if lo.Namespace != "test-ns" || !lo.FieldSelector.Matches(fields.Set{util.RevisionNameIndex: revName}) {
+				return fmt.Errorf("unexpected list options %v", lo)
+			}
+			l := obj.(*v1alpha2.ApplicationConfigurationList)
+			for _, n := range names {
+				l.Items = append(l.Items, v1alpha2.ApplicationConfiguration{ObjectMeta: metav1.ObjectMeta{Name: n}})
+			}
+			return nil
+		}
+	}
+
+	tests := map[string]struct {
+		req    admission.Request
+		list   test.MockListFn
+		pass   bool
+		reason string
+	}{
+		"revision in use": {
+			req:    deleteReq(componentRev),
+			list:   list("app1", "app2"),
+			reason: fmt.Sprintf(reasonFmtRevisionInUse, revName, `"app1", "app2"`),
+		},
+		"revision not in use": {
+			req:  deleteReq(componentRev),
+			list: list(),
+			pass: true,
+		},
+		"list error": {
+			req:  deleteReq(componentRev),
+			list: test.NewMockListFn(errBoom),
+		},
+		"not a component revision": {
+			req:  deleteReq(otherRev),
+			list: test.NewMockListFn(errBoom),
+			pass: true,
+		},
+		"not a deletion": {
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Object:    runtime.RawExtension{Raw: componentRev},
+			}},
+			list: test.NewMockListFn(errBoom),
+			pass: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &ValidatingHandler{Client: &test.MockClient{MockList: tc.list}, Decoder: dec}
+			resp := h.Handle(context.Background(), tc.req)
+			assert.Equal(t, tc.pass, resp.Allowed)
+			if tc.reason != "" {
+				assert.Equal(t, tc.reason, string(resp.Result.Reason))
+			}
+		})
+	}
+}
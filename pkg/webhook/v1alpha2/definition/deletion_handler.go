@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reasonFmtDefinitionInUse = "%s %q MUST NOT be deleted while it is used by %s(s) %s"
+
+	errFmtDefinitionIndexKeys = "cannot determine the resources of definition %q: %v"
+	errFmtListUsers           = "cannot list %ss that use definition %q: %v"
+)
+
+// DeletionValidatingHandler prevents WorkloadDefinitions, TraitDefinitions
+// and ScopeDefinitions from being deleted while Components or
+// ApplicationConfigurations use them, because they could no longer be
+// rendered.
+type DeletionValidatingHandler struct {
+	Client client.Reader
+	Mapper discoverymapper.DiscoveryMapper
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &DeletionValidatingHandler{}
+
+// Handle validates the deletion of definitions here
+func (h *DeletionValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Delete || len(req.OldObject.Raw) == 0 {
+		return admission.Allowed("")
+	}
+	var kind, name, resource, index, userKind string
+	newList := func() runtime.Object { return &v1alpha2.ApplicationConfigurationList{} }
+	switch schema.GroupVersionResource(req.Resource) {
+	case workloadDefinitionResource:
+		wd := &v1alpha2.WorkloadDefinition{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, wd); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		kind, name, resource = v1alpha2.WorkloadDefinitionKind, wd.GetName(), wd.Spec.Reference.Name
+		index, userKind = util.WorkloadDefinitionIndex, v1alpha2.ComponentKind
+		newList = func() runtime.Object { return &v1alpha2.ComponentList{} }
+	case traitDefinitionResource:
+		td := &v1alpha2.TraitDefinition{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, td); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		kind, name, resource = v1alpha2.TraitDefinitionKind, td.GetName(), td.Spec.Reference.Name
+		index, userKind = util.TraitDefinitionIndex, v1alpha2.ApplicationConfigurationKind
+	case scopeDefinitionResource:
+		sd := &v1alpha2.ScopeDefinition{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, sd); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		kind, name, resource = v1alpha2.ScopeDefinitionKind, sd.GetName(), sd.Spec.Reference.Name
+		index, userKind = util.ScopeDefinitionIndex, v1alpha2.ApplicationConfigurationKind
+	default:
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf(errFmtUnexpectedResource, req.Resource.Resource))
+	}
+	keys, err := util.DefinitionIndexKeys(h.Client, h.Mapper, kind, name, resource)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf(errFmtDefinitionIndexKeys, name, err))
+	}
+	users, err := util.DefinitionUsers(ctx, h.Client, newList, index, keys)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf(errFmtListUsers, userKind, name, err))
+	}
+	if len(users) == 0 {
+		return admission.Allowed("")
+	}
+	for i := range users {
+		users[i] = fmt.Sprintf("%q", users[i])
+	}
+	return admission.Denied(fmt.Sprintf(reasonFmtDefinitionInUse, kind, name, userKind, strings.Join(users, ", ")))
+}
+
+var _ inject.Client = &DeletionValidatingHandler{}
+
+// InjectClient injects the client into the DeletionValidatingHandler
+func (h *DeletionValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &DeletionValidatingHandler{}
+
+// InjectDecoder injects the decoder into the DeletionValidatingHandler
+func (h *DeletionValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
+
+// Paths the validation of definition deletion is served at by default
+const (
+	WorkloadDefinitionDeletionValidatingPath = "/validating-core-oam-dev-v1alpha2-workloaddefinitions-deletion"
+	TraitDefinitionDeletionValidatingPath    = "/validating-core-oam-dev-v1alpha2-traitdefinitions-deletion"
+	ScopeDefinitionDeletionValidatingPath    = "/validating-core-oam-dev-v1alpha2-scopedefinitions-deletion"
+)
+
+// NewDeletionValidatingHandler returns a handler that validates the deletion
+// of definitions for the supplied manager
+func NewDeletionValidatingHandler(mgr manager.Manager) (*DeletionValidatingHandler, error) {
+	mapper, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &DeletionValidatingHandler{Mapper: mapper}, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+func TestDefinitionDeletionValidation(t *testing.T) {
+	errBoom := errors.New("boom")
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	dec, _ := admission.NewDecoder(scheme)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper := mock.NewMockDiscoveryMapper()
+	mapper.MockGetMapper = func() (meta.RESTMapper, error) { return restMapper, nil }
+
+	deleteReq := func(resource string, obj runtime.Object) admission.Request {
+		raw, _ := json.Marshal(obj)
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Delete,
+			Resource:  metav1.GroupVersionResource{Group: "core.oam.dev", Version: "v1alpha2", Resource: resource},
+			OldObject: runtime.RawExtension{Raw: raw},
+		}}
+	}
+	wd := func(name, resource string) *v1alpha2.WorkloadDefinition {
+		return &v1alpha2.WorkloadDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha2.WorkloadDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: resource}},
+		}
+	}
+	td := &v1alpha2.TraitDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "scaler"},
+		Spec:       v1alpha2.TraitDefinitionSpec{Reference: v1alpha2.DefinitionReference{Name: "scalers.example.com"}},
+	}
+	// users maps the keys of the supplied index to the names of the objects
+	// that use them
+	list := func(index string, users map[string][]string) test.MockListFn {
+		return func(_ context.Context, obj runtime.Object, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			for key, names := range users {
+				if !lo.FieldSelector.Matches(fields.Set{index: key}) {
+					continue
+				}
+				for _, n := range names {
+					om := metav1.ObjectMeta{Namespace: "default", Name: n}
+					switch l := obj.(type) {
+					case *v1alpha2.ComponentList:
+						l.Items = append(l.Items, v1alpha2.Component{ObjectMeta: om})
+					case *v1alpha2.ApplicationConfigurationList:
+						l.Items = append(l.Items, v1alpha2.ApplicationConfiguration{ObjectMeta: om})
+					}
+				}
+			}
+			return nil
+		}
+	}
+
+	tests := map[string]struct {
+		req    admission.Request
+		list   test.MockListFn
+		pass   bool
+		reason string
+	}{
+		"workload definition used by kind": {
+			req:  deleteReq("workloaddefinitions", wd("deployments.apps", "deployments.apps")),
+			list: list(util.WorkloadDefinitionIndex, map[string][]string{"Deployment.apps": {"web", "db"}}),
+			reason: fmt.Sprintf(reasonFmtDefinitionInUse, v1alpha2.WorkloadDefinitionKind, "deployments.apps",
+				v1alpha2.ComponentKind, `"default/web", "default/db"`),
+		},
+		"workload definition used by type label": {
+			req:  deleteReq("workloaddefinitions", wd("webservice", "deployments.apps")),
+			list: list(util.WorkloadDefinitionIndex, map[string][]string{"webservice": {"web"}}),
+			reason: fmt.Sprintf(reasonFmtDefinitionInUse, v1alpha2.WorkloadDefinitionKind, "webservice",
+				v1alpha2.ComponentKind, `"default/web"`),
+		},
+		"workload definition not named after its resource is not used by kind": {
+			req:  deleteReq("workloaddefinitions", wd("webservice", "deployments.apps")),
+			list: list(util.WorkloadDefinitionIndex, map[string][]string{"Deployment.apps": {"web"}}),
+			pass: true,
+		},
+		"trait definition used": {
+			req:  deleteReq("traitdefinitions", td),
+			list: list(util.TraitDefinitionIndex, map[string][]string{"scaler": {"app"}}),
+			reason: fmt.Sprintf(reasonFmtDefinitionInUse, v1alpha2.TraitDefinitionKind, "scaler",
+				v1alpha2.ApplicationConfigurationKind, `"default/app"`),
+		},
+		"trait definition not used": {
+			req:  deleteReq("traitdefinitions", td),
+			list: list(util.TraitDefinitionIndex, nil),
+			pass: true,
+		},
+		"list error": {
+			req:  deleteReq("traitdefinitions", td),
+			list: test.NewMockListFn(errBoom),
+		},
+		"not a deletion": {
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Resource:  metav1.GroupVersionResource{Group: "core.oam.dev", Version: "v1alpha2", Resource: "traitdefinitions"},
+			}},
+			list: test.NewMockListFn(errBoom),
+			pass: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &DeletionValidatingHandler{Client: &test.MockClient{MockList: tc.list}, Mapper: mapper, Decoder: dec}
+			resp := h.Handle(context.Background(), tc.req)
+			assert.Equal(t, tc.pass, resp.Allowed)
+			if tc.reason != "" {
+				assert.Equal(t, tc.reason, string(resp.Result.Reason))
+			}
+		})
+	}
+}
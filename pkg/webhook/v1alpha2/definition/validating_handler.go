@@ -0,0 +1,405 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
+)
+
+const (
+	reasonDefinitionNotInstalled  = "must refer to an installed CustomResourceDefinition or API resource"
+	reasonInvalidAppliesTo        = "must be a workload definition name or a workload kind in kind.group/version or kind.group format"
+	reasonFmtAliasInUse           = "is already the name or an alias of TraitDefinition %q"
+	reasonVersionNotServed        = "must be a version the referenced resource is served at"
+	reasonWorkloadRefListPath     = "must be a field path to a list before the [] suffix"
+	reasonReplacedByNotDeprecated = "must only be set on deprecated definitions"
+
+	// workloadRefListSuffix marks a workloadRefPath that refers to a list
+	workloadRefListSuffix = "[]"
+
+	errFmtUnexpectedResource = "unexpected resource %q"
+	errFmtGetCRD             = "cannot get custom resource definition %q: %v"
+	errFmtDiscoverResource   = "cannot discover resource %q: %v"
+	errFmtListTraitDefs      = "cannot list trait definitions: %v"
+
+	labelSelectorPrefix = "labelSelector:"
+)
+
+var (
+	workloadDefinitionResource = v1alpha2.SchemeGroupVersion.WithResource("workloaddefinitions")
+	traitDefinitionResource    = v1alpha2.SchemeGroupVersion.WithResource("traitdefinitions")
+	scopeDefinitionResource    = v1alpha2.SchemeGroupVersion.WithResource("scopedefinitions")
+)
+
+// ValidatingHandler validates WorkloadDefinitions, TraitDefinitions and
+// ScopeDefinitions.
+type ValidatingHandler struct {
+	Client client.Reader
+	Mapper discoverymapper.DiscoveryMapper
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &ValidatingHandler{}
+
+// Handle validates definitions here
+func (h *ValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var ref v1alpha2.DefinitionReference
+	var allErrs field.ErrorList
+	switch schema.GroupVersionResource(req.Resource) {
+	case workloadDefinitionResource:
+		wd := &v1alpha2.WorkloadDefinition{}
+		if err := h.Decoder.Decode(req, wd); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		ref = wd.Spec.Reference
+		allErrs = ValidateWorkloadDefinitionSpec(&wd.Spec, field.NewPath("spec"))
+	case traitDefinitionResource:
+		td := &v1alpha2.TraitDefinition{}
+		if err := h.Decoder.Decode(req, td); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		ref = td.Spec.Reference
+		allErrs = ValidateTraitDefinitionSpec(&td.Spec, field.NewPath("spec"))
+		aliasErrs, err := h.validateAliases(ctx, td, field.NewPath("spec", "aliases"))
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		allErrs = append(allErrs, aliasErrs...)
+	case scopeDefinitionResource:
+		sd := &v1alpha2.ScopeDefinition{}
+		if err := h.Decoder.Decode(req, sd); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		ref = sd.Spec.Reference
+		allErrs = ValidateScopeDefinitionSpec(&sd.Spec, field.NewPath("spec"))
+	default:
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf(errFmtUnexpectedResource, req.Resource.Resource))
+	}
+	refErrs, err := h.validateReference(ctx, ref, field.NewPath("spec", "definitionRef"))
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if allErrs = append(allErrs, refErrs...); len(allErrs) > 0 {
+		return admission.Denied(allErrs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// validateReference validates that the supplied reference refers to an
+// installed CRD, or to a built-in resource, e.g. deployments.apps, that serves
+// the version of the reference if it has one.
+func (h *ValidatingHandler) validateReference(ctx context.Context, ref v1alpha2.DefinitionReference, fldPath *field.Path) (field.ErrorList, error) {
+	namePath := fldPath.Child("name")
+	if ref.Name == "" {
+		return field.ErrorList{field.Required(namePath, "")}, nil
+	}
+	crd := &crdv1.CustomResourceDefinition{}
+	err := h.Client.Get(ctx, types.NamespacedName{Name: ref.Name}, crd)
+	if err == nil {
+		if _, err := util.DefinitionVersion(crd, ref); err != nil {
+			return field.ErrorList{field.NotSupported(fldPath.Child("version"), ref.Version, util.ServedVersions(crd))}, nil
+		}
+		return nil, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf(errFmtGetCRD, ref.Name, err)
+	}
+	served, err := resourceServed(h.Mapper, ref.Name, "")
+	if err != nil {
+		return nil, fmt.Errorf(errFmtDiscoverResource, ref.Name, err)
+	}
+	if !served {
+		return field.ErrorList{field.Invalid(namePath, ref.Name, reasonDefinitionNotInstalled)}, nil
+	}
+	if ref.Version == "" {
+		return nil, nil
+	}
+	if served, err = resourceServed(h.Mapper, ref.Name, ref.Version); err != nil {
+		return nil, fmt.Errorf(errFmtDiscoverResource, ref.Name, err)
+	}
+	if !served {
+		return field.ErrorList{field.Invalid(fldPath.Child("version"), ref.Version, reasonVersionNotServed)}, nil
+	}
+	return nil, nil
+}
+
+// validateAliases validates that no other TraitDefinition has an alias of the
+// supplied one as its name or alias.
+func (h *ValidatingHandler) validateAliases(ctx context.Context, td *v1alpha2.TraitDefinition, fldPath *field.Path) (field.ErrorList, error) {
+	if len(td.Spec.Aliases) == 0 {
+		return nil, nil
+	}
+	l := &v1alpha2.TraitDefinitionList{}
+	if err := h.Client.List(ctx, l); err != nil {
+		return nil, fmt.Errorf(errFmtListTraitDefs, err)
+	}
+	used := make(map[string]string)
+	for _, other := range l.Items {
+		if other.GetName() == td.GetName() {
+			continue
+		}
+		used[other.GetName()] = other.GetName()
+		for _, alias := range other.Spec.Aliases {
+			used[alias] = other.GetName()
+		}
+	}
+	var allErrs field.ErrorList
+	for i, alias := range td.Spec.Aliases {
+		if other, ok := used[alias]; ok {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), alias, fmt.Sprintf(reasonFmtAliasInUse, other)))
+		}
+	}
+	return allErrs, nil
+}
+
+// resourceServed returns true if the API server serves the resource with the
+// supplied name in resource.group format, at the supplied version if any.
+func resourceServed(dm discoverymapper.DiscoveryMapper, name, version string) (bool, error) {
+	gr := schema.ParseGroupResource(name)
+	gvr := gr.WithVersion(version)
+	mapper, err := dm.GetMapper()
+	if err != nil {
+		return false, err
+	}
+	if _, err = mapper.KindFor(gvr); meta.IsNoMatchError(err) {
+		// the resource may have been installed after the mapper was cached
+		if mapper, err = dm.Refresh(); err != nil {
+			return false, err
+		}
+		_, err = mapper.KindFor(gvr)
+	}
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ValidateWorkloadDefinitionSpec validates the supplied WorkloadDefinitionSpec.
+func ValidateWorkloadDefinitionSpec(spec *v1alpha2.WorkloadDefinitionSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := validateFieldPath(spec.PodSpecPath, fldPath.Child("podSpecPath"))
+	if spec.RevisionLabel != "" {
+		for _, msg := range validation.IsQualifiedName(spec.RevisionLabel) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("revisionLabel"), spec.RevisionLabel, msg))
+		}
+	}
+	for i, k := range spec.ChildResourceKinds {
+		kindPath := fldPath.Child("childResourceKinds").Index(i)
+		if k.APIVersion == "" {
+			allErrs = append(allErrs, field.Required(kindPath.Child("apiVersion"), ""))
+		} else if _, err := schema.ParseGroupVersion(k.APIVersion); err != nil {
+			allErrs = append(allErrs, field.Invalid(kindPath.Child("apiVersion"), k.APIVersion, err.Error()))
+		}
+		if k.Kind == "" {
+			allErrs = append(allErrs, field.Required(kindPath.Child("kind"), ""))
+		}
+	}
+	allErrs = append(allErrs, validateStatusFields(spec.StatusFields, spec.StatusMessage, fldPath)...)
+	if spec.ReplacedBy != "" && !spec.Deprecated {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replacedBy"), spec.ReplacedBy, reasonReplacedByNotDeprecated))
+	}
+	return allErrs
+}
+
+// ValidateTraitDefinitionSpec validates the supplied TraitDefinitionSpec.
+func ValidateTraitDefinitionSpec(spec *v1alpha2.TraitDefinitionSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := validateWorkloadRefPath(spec.WorkloadRefPath, fldPath.Child("workloadRefPath"))
+	for i, p := range spec.WorkloadRefPaths {
+		pathPath := fldPath.Child("workloadRefPaths").Index(i)
+		if p == "" {
+			allErrs = append(allErrs, field.Required(pathPath, ""))
+			continue
+		}
+		allErrs = append(allErrs, validateWorkloadRefPath(p, pathPath)...)
+	}
+	allErrs = append(allErrs, validateFieldPath(spec.RevisionsPath, fldPath.Child("revisionsPath"))...)
+	for i, rule := range spec.AppliesToWorkloads {
+		if msg := validateAppliesTo(rule); msg != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("appliesToWorkloads").Index(i), rule, msg))
+		}
+	}
+	seen := make(map[string]bool, len(spec.Aliases))
+	for i, alias := range spec.Aliases {
+		aliasPath := fldPath.Child("aliases").Index(i)
+		for _, msg := range validation.IsDNS1123Label(alias) {
+			allErrs = append(allErrs, field.Invalid(aliasPath, alias, msg))
+		}
+		if seen[alias] {
+			allErrs = append(allErrs, field.Duplicate(aliasPath, alias))
+		}
+		seen[alias] = true
+	}
+	for i, rule := range spec.ConflictsWith {
+		if !strings.HasPrefix(rule, labelSelectorPrefix) {
+			continue
+		}
+		if _, err := labels.Parse(strings.TrimPrefix(rule, labelSelectorPrefix)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("conflictsWith").Index(i), rule, err.Error()))
+		}
+	}
+	allErrs = append(allErrs, validateStatusFields(spec.StatusFields, spec.StatusMessage, fldPath)...)
+	if spec.ReplacedBy != "" && !spec.Deprecated {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replacedBy"), spec.ReplacedBy, reasonReplacedByNotDeprecated))
+	}
+	return allErrs
+}
+
+// ValidateScopeDefinitionSpec validates the supplied ScopeDefinitionSpec.
+func ValidateScopeDefinitionSpec(spec *v1alpha2.ScopeDefinitionSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := validateFieldPath(spec.WorkloadRefsPath, fldPath.Child("workloadRefsPath"))
+	for i, rule := range spec.AppliesToWorkloads {
+		if msg := validateAppliesTo(rule); msg != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("appliesToWorkloads").Index(i), rule, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateFieldPath(p string, fldPath *field.Path) field.ErrorList {
+	if p == "" {
+		return nil
+	}
+	if _, err := fieldpath.Parse(p); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, p, err.Error())}
+	}
+	return nil
+}
+
+// validateStatusFields validates the statusFields and the statusMessage
+// template of a definition spec.
+func validateStatusFields(sf []v1alpha2.StatusField, msg string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := make(map[string]bool, len(sf))
+	for i, f := range sf {
+		fieldPath := fldPath.Child("statusFields").Index(i)
+		switch {
+		case f.Name == "":
+			allErrs = append(allErrs, field.Required(fieldPath.Child("name"), ""))
+		case seen[f.Name]:
+			allErrs = append(allErrs, field.Duplicate(fieldPath.Child("name"), f.Name))
+		}
+		seen[f.Name] = true
+		if f.FieldPath == "" {
+			allErrs = append(allErrs, field.Required(fieldPath.Child("fieldPath"), ""))
+			continue
+		}
+		allErrs = append(allErrs, validateFieldPath(f.FieldPath, fieldPath.Child("fieldPath"))...)
+	}
+	if _, err := template.New("status").Parse(msg); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("statusMessage"), msg, err.Error()))
+	}
+	return allErrs
+}
+
+// validateWorkloadRefPath validates a field path, that may be suffixed with []
+// to refer to a list the workloadRef is appended to.
+func validateWorkloadRefPath(p string, fldPath *field.Path) field.ErrorList {
+	if p == workloadRefListSuffix {
+		return field.ErrorList{field.Invalid(fldPath, p, reasonWorkloadRefListPath)}
+	}
+	return validateFieldPath(strings.TrimSuffix(p, workloadRefListSuffix), fldPath)
+}
+
+// validateAppliesTo returns why the supplied appliesToWorkloads rule is
+// invalid, or an empty string if it is valid.
+func validateAppliesTo(rule string) string {
+	if rule == "" {
+		return reasonInvalidAppliesTo
+	}
+	if _, err := path.Match(rule, ""); err != nil {
+		return err.Error()
+	}
+	if !strings.Contains(rule, "/") {
+		return ""
+	}
+	gv, err := schema.ParseGroupVersion(rule)
+	if err != nil || gv.Group == "" || gv.Version == "" {
+		return reasonInvalidAppliesTo
+	}
+	return ""
+}
+
+var _ inject.Client = &ValidatingHandler{}
+
+// InjectClient injects the client into the ValidatingHandler
+func (h *ValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &ValidatingHandler{}
+
+// InjectDecoder injects the decoder into the ValidatingHandler
+func (h *ValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
+
+// Paths definition validation is served at by default
+const (
+	WorkloadDefinitionValidatingPath = "/validating-core-oam-dev-v1alpha2-workloaddefinitions"
+	TraitDefinitionValidatingPath    = "/validating-core-oam-dev-v1alpha2-traitdefinitions"
+	ScopeDefinitionValidatingPath    = "/validating-core-oam-dev-v1alpha2-scopedefinitions"
+)
+
+// NewValidatingHandler returns a definition validation handler for the
+// supplied manager
+func NewValidatingHandler(mgr manager.Manager) (*ValidatingHandler, error) {
+	mapper, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatingHandler{Mapper: mapper}, nil
+}
+
+// RegisterValidatingHandler will register definition validation to webhook
+func RegisterValidatingHandler(mgr manager.Manager) error {
+	server := mgr.GetWebhookServer()
+	h, err := NewValidatingHandler(mgr)
+	if err != nil {
+		return err
+	}
+	for _, p := range []string{WorkloadDefinitionValidatingPath, TraitDefinitionValidatingPath, ScopeDefinitionValidatingPath} {
+		server.Register(p, review.New(h))
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestDefinitionValidation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	dec, _ := admission.NewDecoder(scheme)
+
+	// foos.example.com is an installed CRD, deployments.apps a built-in resource
+	mockClient := &test.MockClient{
+		MockGet: func(_ context.Context, key types.NamespacedName, obj runtime.Object) error {
+			if crd, ok := obj.(*crdv1.CustomResourceDefinition); ok && key.Name == "foos.example.com" {
+				crd.Spec.Versions = []crdv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: false},
+					{Name: "v1", Served: true, Storage: true},
+				}
+				return nil
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		},
+		// the scaler alias is taken
+		MockList: func(_ context.Context, list runtime.Object, _ ...client.ListOption) error {
+			list.(*v1alpha2.TraitDefinitionList).Items = []v1alpha2.TraitDefinition{{
+				ObjectMeta: metav1.ObjectMeta{Name: "scalers.example.com"},
+				Spec:       v1alpha2.TraitDefinitionSpec{Aliases: []string{"scaler"}},
+			}}
+			return nil
+		},
+	}
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper := mock.NewMockDiscoveryMapper()
+	mapper.MockGetMapper = func() (meta.RESTMapper, error) { return restMapper, nil }
+	mapper.MockRefresh = func() (meta.RESTMapper, error) { return restMapper, nil }
+
+	request := func(resource string, obj runtime.Object) admission.Request {
+		kinds, _, _ := scheme.ObjectKinds(obj)
+		obj.GetObjectKind().SetGroupVersionKind(kinds[0])
+		raw, _ := json.Marshal(obj)
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Create,
+			Resource:  metav1.GroupVersionResource{Group: v1alpha2.Group, Version: v1alpha2.Version, Resource: resource},
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	}
+	ref := func(name string) v1alpha2.DefinitionReference {
+		return v1alpha2.DefinitionReference{Name: name}
+	}
+
+	tests := map[string]struct {
+		req     admission.Request
+		pass    bool
+		reasons []string
+	}{
+		"valid workload definition of a CRD": {
+			req: request("workloaddefinitions", &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference:          ref("foos.example.com"),
+				PodSpecPath:        "spec.template.spec",
+				RevisionLabel:      "app.oam.dev/revision",
+				ChildResourceKinds: []v1alpha2.ChildResourceKind{{APIVersion: "apps/v1", Kind: "Deployment"}},
+				StatusFields:       []v1alpha2.StatusField{{Name: "ready", FieldPath: "status.readyReplicas"}},
+				StatusMessage:      "{{.ready}} replicas ready",
+			}}),
+			pass: true,
+		},
+		"valid workload definition of a built-in resource": {
+			req:  request("workloaddefinitions", &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{Reference: ref("deployments.apps")}}),
+			pass: true,
+		},
+		"workload definition of a served version": {
+			req: request("workloaddefinitions", &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference: v1alpha2.DefinitionReference{Name: "foos.example.com", Version: "v1"},
+			}}),
+			pass: true,
+		},
+		"workload definition of a version that is not served": {
+			req: request("workloaddefinitions", &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference: v1alpha2.DefinitionReference{Name: "foos.example.com", Version: "v1alpha1"},
+			}}),
+			reasons: []string{"spec.definitionRef.version", "Unsupported value"},
+		},
+		"workload definition of a built-in resource version that is not served": {
+			req: request("workloaddefinitions", &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference: v1alpha2.DefinitionReference{Name: "deployments.apps", Version: "v1beta1"},
+			}}),
+			reasons: []string{"spec.definitionRef.version", reasonVersionNotServed},
+		},
+		"workload definition of a resource that is not installed": {
+			req:     request("workloaddefinitions", &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{Reference: ref("bars.example.com")}}),
+			reasons: []string{"spec.definitionRef.name", reasonDefinitionNotInstalled},
+		},
+		"invalid workload definition": {
+			req: request("workloaddefinitions", &v1alpha2.WorkloadDefinition{Spec: v1alpha2.WorkloadDefinitionSpec{
+				Reference:          ref("foos.example.com"),
+				PodSpecPath:        "spec[template",
+				ChildResourceKinds: []v1alpha2.ChildResourceKind{{APIVersion: "apps/v1/v2"}},
+				StatusFields: []v1alpha2.StatusField{
+					{FieldPath: "status.ready"},
+					{Name: "ip", FieldPath: "status..ip"},
+					{Name: "ip"},
+				},
+				StatusMessage: "{{.ip",
+				ReplacedBy:    "bars.example.com",
+			}}),
+			reasons: []string{"spec.podSpecPath", "spec.childResourceKinds[0].apiVersion", "spec.childResourceKinds[0].kind",
+				"spec.statusFields[0].name: Required value", "spec.statusFields[1].fieldPath", "spec.statusFields[2].name: Duplicate value",
+				"spec.statusFields[2].fieldPath: Required value", "spec.statusMessage", "spec.replacedBy"},
+		},
+		"valid trait definition": {
+			req: request("traitdefinitions", &v1alpha2.TraitDefinition{Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:          ref("foos.example.com"),
+				WorkloadRefPath:    "spec.workloadRef",
+				WorkloadRefPaths:   []string{"spec.targets[]"},
+				AppliesToWorkloads: []string{"deployment.apps/v1", "*.core.oam.dev", "containerizedworkloads.core.oam.dev"},
+				ConflictsWith:      []string{"labelSelector:scaler=true", "autoscalers.example.com"},
+				Aliases:            []string{"foo"},
+				Deprecated:         true,
+				ReplacedBy:         "bars.example.com",
+			}}),
+			pass: true,
+		},
+		"trait definition updating its own aliases": {
+			req: request("traitdefinitions", &v1alpha2.TraitDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "scalers.example.com"},
+				Spec:       v1alpha2.TraitDefinitionSpec{Reference: ref("foos.example.com"), Aliases: []string{"scaler"}},
+			}),
+			pass: true,
+		},
+		"trait definition with aliases in use": {
+			req: request("traitdefinitions", &v1alpha2.TraitDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"},
+				Spec: v1alpha2.TraitDefinitionSpec{
+					Reference: ref("foos.example.com"),
+					Aliases:   []string{"scaler", "Foo.Bar", "foo", "foo"},
+				},
+			}),
+			reasons: []string{"spec.aliases[0]", "scalers.example.com", "spec.aliases[1]", "spec.aliases[3]: Duplicate value"},
+		},
+		"invalid trait definition": {
+			req: request("traitdefinitions", &v1alpha2.TraitDefinition{Spec: v1alpha2.TraitDefinitionSpec{
+				Reference:          ref("foos.example.com"),
+				RevisionsPath:      "spec.revisions[",
+				WorkloadRefPaths:   []string{"", "[]", "spec..targets[]"},
+				AppliesToWorkloads: []string{"deployment.apps/", "[deployment"},
+				ConflictsWith:      []string{"labelSelector:scaler in ("},
+			}}),
+			reasons: []string{"spec.revisionsPath", "spec.workloadRefPaths[0]: Required value", "spec.workloadRefPaths[1]",
+				"spec.workloadRefPaths[2]", "spec.appliesToWorkloads[0]", "spec.appliesToWorkloads[1]", "spec.conflictsWith[0]"},
+		},
+		"invalid scope definition": {
+			req: request("scopedefinitions", &v1alpha2.ScopeDefinition{Spec: v1alpha2.ScopeDefinitionSpec{
+				Reference:          ref(""),
+				WorkloadRefsPath:   "spec..workloadRefs",
+				AppliesToWorkloads: []string{"containerizedworkloads.core.oam.dev", "[deployment"},
+			}}),
+			reasons: []string{"spec.workloadRefsPath", "spec.definitionRef.name: Required value", "spec.appliesToWorkloads[1]"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &ValidatingHandler{Client: mockClient, Mapper: mapper, Decoder: dec}
+			resp := h.Handle(context.Background(), tc.req)
+			assert.Equal(t, tc.pass, resp.Allowed, string(resp.Result.Reason))
+			for _, reason := range tc.reasons {
+				assert.True(t, strings.Contains(string(resp.Result.Reason), reason),
+					"reason %q should contain %q", resp.Result.Reason, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/review"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/applicationconfiguration"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/component"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/controllerrevision"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/definition"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/webhook/v1alpha2/scope"
+)
+
+const (
+	errFmtHandlerRegistered    = "admission handler %q is already registered"
+	errFmtHandlerNotRegistered = "admission handler %q is not registered"
+	errFmtNewHandler           = "cannot create admission handler %q"
+)
+
+// A HandlerFactory creates an admission handler configured by the supplied
+// options.
+type HandlerFactory func(mgr manager.Manager, o Options) (admission.Handler, error)
+
+// A HandlerWrapper wraps the supplied admission handler, e.g. to run
+// additional checks before or after it. The fields of the wrapped handler,
+// e.g. its client and decoder, are injected as if it was not wrapped.
+type HandlerWrapper func(mgr manager.Manager, o Options, h admission.Handler) (admission.Handler, error)
+
+type registration struct {
+	name     string
+	path     string
+	new      HandlerFactory
+	wrappers []HandlerWrapper
+}
+
+// A Registry of named admission handlers. Embedders of the runtime may
+// register their own handlers, or wrap the handlers of this package, before
+// adding them to a manager.
+type Registry struct {
+	handlers []*registration
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// DefaultRegistry returns a Registry of the admission handlers of this
+// package.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	// definitions of all kinds share a handler, and a deletion handler
+	var def *definition.ValidatingHandler
+	newDefinitionHandler := func(mgr manager.Manager, _ Options) (admission.Handler, error) {
+		if def != nil {
+			return def, nil
+		}
+		h, err := definition.NewValidatingHandler(mgr)
+		if err != nil {
+			return nil, err
+		}
+		def = h
+		return def, nil
+	}
+	var del *definition.DeletionValidatingHandler
+	newDefinitionDeletionHandler := func(mgr manager.Manager, _ Options) (admission.Handler, error) {
+		if del != nil {
+			return del, nil
+		}
+		h, err := definition.NewDeletionValidatingHandler(mgr)
+		if err != nil {
+			return nil, err
+		}
+		del = h
+		return del, nil
+	}
+
+	r.mustRegister(AppConfigValidatingHandler, applicationconfiguration.ValidatingHandlerPath, func(mgr manager.Manager, o Options) (admission.Handler, error) {
+		h, err := applicationconfiguration.NewValidatingHandler(mgr)
+		if err != nil {
+			return nil, err
+		}
+		h.Policies = o.Policies
+		h.Limits = o.Limits
+		h.RejectDeprecated = o.RejectDeprecatedDefinitions
+		return h, nil
+	})
+	r.mustRegister(AppConfigMutatingHandler, applicationconfiguration.MutatingHandlerPath, func(manager.Manager, Options) (admission.Handler, error) {
+		return &applicationconfiguration.MutatingHandler{}, nil
+	})
+	r.mustRegister(ComponentMutatingHandler, component.MutatingHandlerPath, func(manager.Manager, Options) (admission.Handler, error) {
+		return &component.MutatingHandler{}, nil
+	})
+	r.mustRegister(ComponentValidatingHandler, component.ValidatingHandlerPath, func(mgr manager.Manager, o Options) (admission.Handler, error) {
+		h, err := component.NewValidatingHandler(mgr)
+		if err != nil {
+			return nil, err
+		}
+		h.RejectDeprecated = o.RejectDeprecatedDefinitions
+		return h, nil
+	})
+	r.mustRegister(ControllerRevisionValidatingHandler, controllerrevision.ValidatingHandlerPath, func(manager.Manager, Options) (admission.Handler, error) {
+		return &controllerrevision.ValidatingHandler{}, nil
+	})
+	r.mustRegister(WorkloadDefinitionValidatingHandler, definition.WorkloadDefinitionValidatingPath, newDefinitionHandler)
+	r.mustRegister(TraitDefinitionValidatingHandler, definition.TraitDefinitionValidatingPath, newDefinitionHandler)
+	r.mustRegister(ScopeDefinitionValidatingHandler, definition.ScopeDefinitionValidatingPath, newDefinitionHandler)
+	r.mustRegister(WorkloadDefinitionDeletionValidatingHandler, definition.WorkloadDefinitionDeletionValidatingPath, newDefinitionDeletionHandler)
+	r.mustRegister(TraitDefinitionDeletionValidatingHandler, definition.TraitDefinitionDeletionValidatingPath, newDefinitionDeletionHandler)
+	r.mustRegister(ScopeDefinitionDeletionValidatingHandler, definition.ScopeDefinitionDeletionValidatingPath, newDefinitionDeletionHandler)
+	r.mustRegister(ScopeDeletionValidatingHandler, scope.DeletionValidatingPath, func(mgr manager.Manager, _ Options) (admission.Handler, error) {
+		return scope.NewDeletionValidatingHandler(mgr)
+	})
+	return r
+}
+
+func (r *Registry) mustRegister(name, path string, f HandlerFactory) {
+	if err := r.Register(name, path, f); err != nil {
+		panic(err)
+	}
+}
+
+// Register an admission handler created by the supplied factory, that is
+// served at the supplied default path. Names are unique within a Registry.
+func (r *Registry) Register(name, path string, f HandlerFactory) error {
+	if r.get(name) != nil || name == ConversionHandler {
+		return errors.Errorf(errFmtHandlerRegistered, name)
+	}
+	r.handlers = append(r.handlers, &registration{name: name, path: path, new: f})
+	return nil
+}
+
+// Wrap the registered admission handler of the supplied name. Wrappers are
+// applied in the order they are added, i.e. the last wrapper added is the
+// outermost.
+func (r *Registry) Wrap(name string, w HandlerWrapper) error {
+	h := r.get(name)
+	if h == nil {
+		return errors.Errorf(errFmtHandlerNotRegistered, name)
+	}
+	h.wrappers = append(h.wrappers, w)
+	return nil
+}
+
+// Names of the registered admission handlers, in the order they were
+// registered.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.handlers))
+	for _, h := range r.handlers {
+		names = append(names, h.name)
+	}
+	return names
+}
+
+func (r *Registry) get(name string) *registration {
+	for _, h := range r.handlers {
+		if h.name == name {
+			return h
+		}
+	}
+	return nil
+}
+
+// AddToManager registers the admission handlers that are not disabled by the
+// supplied options with the webhook server of the supplied manager,
+// configured by them.
+func (r *Registry) AddToManager(mgr manager.Manager, o Options) error {
+	server := mgr.GetWebhookServer()
+	if o.Port != 0 {
+		server.Port = o.Port
+	}
+	if o.CertDir != "" {
+		server.CertDir = o.CertDir
+	}
+	disabled := make(map[string]bool, len(o.Disabled))
+	for _, name := range o.Disabled {
+		disabled[name] = true
+	}
+	for _, h := range r.handlers {
+		if disabled[h.name] {
+			admitlog.Info("admission handler disabled", "name", h.name)
+			continue
+		}
+		handler, err := h.new(mgr, o)
+		if err != nil {
+			return errors.Wrapf(err, errFmtNewHandler, h.name)
+		}
+		wrapped := make([]admission.Handler, 0, len(h.wrappers))
+		for _, w := range h.wrappers {
+			wrapped = append(wrapped, handler)
+			if handler, err = w(mgr, o, handler); err != nil {
+				return errors.Wrapf(err, errFmtNewHandler, h.name)
+			}
+		}
+		path := h.path
+		if p, ok := o.Paths[h.name]; ok {
+			path = p
+		}
+		server.Register(path, review.New(&optionsHandler{
+			Handler:           handler,
+			Wrapped:           wrapped,
+			Name:              h.name,
+			Client:            mgr.GetClient(),
+			DefinitionClient:  o.DefinitionClient,
+			FailurePolicy:     o.FailurePolicy,
+			NamespaceSelector: o.NamespaceSelector,
+		}))
+	}
+	if !disabled[ConversionHandler] {
+		path := ConversionPath
+		if p, ok := o.Paths[ConversionHandler]; ok {
+			path = p
+		}
+		// the scheme of the manager is injected when the server starts
+		server.Register(path, &conversion.Webhook{})
+	}
+	return nil
+}
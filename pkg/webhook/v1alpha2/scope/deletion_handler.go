@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/discoverymapper"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/util"
+)
+
+const (
+	reasonFmtScopeHasWorkloads = "%s %q MUST NOT be deleted while it references workload(s) %s, remove them from the scope first"
+
+	errFmtGetScopeDefinition = "cannot get the ScopeDefinition of %s %q: %v"
+	errFmtGetWorkloadRefs    = "cannot get the workloads %s %q references at %q: %v"
+)
+
+// DeletionValidatingHandler prevents scopes from being deleted while they
+// reference workloads, so that ApplicationConfigurations don't record their
+// workloads to be in scopes that no longer exist. Workloads are removed from
+// their scopes when they leave them, e.g. when their ApplicationConfiguration
+// is deleted, after which the scope can be deleted.
+type DeletionValidatingHandler struct {
+	Client client.Reader
+	Mapper discoverymapper.DiscoveryMapper
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &DeletionValidatingHandler{}
+
+// Handle validates the deletion of scopes here
+func (h *DeletionValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1beta1.Delete || len(req.OldObject.Raw) == 0 {
+		return admission.Allowed("")
+	}
+	s := &unstructured.Unstructured{}
+	if err := h.Decoder.DecodeRaw(req.OldObject, s); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	sd, err := util.FetchScopeDefinition(ctx, h.Client, h.Mapper, s)
+	if apierrors.IsNotFound(err) {
+		// the scope is not an OAM scope, or its definition was deleted
+		return admission.Allowed("")
+	}
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf(errFmtGetScopeDefinition, s.GetKind(), s.GetName(), err))
+	}
+	path := sd.Spec.WorkloadRefsPath
+	if path == "" {
+		return admission.Allowed("")
+	}
+	value, err := fieldpath.Pave(s.UnstructuredContent()).GetValue(path)
+	if fieldpath.IsNotFound(err) {
+		return admission.Allowed("")
+	}
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf(errFmtGetWorkloadRefs, s.GetKind(), s.GetName(), path, err))
+	}
+	refs, _ := value.([]interface{})
+	names := make([]string, 0, len(refs))
+	for _, r := range refs {
+		ref, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		names = append(names, fmt.Sprintf("%s %q", ref["kind"], ref["name"]))
+	}
+	if len(names) == 0 {
+		return admission.Allowed("")
+	}
+	return admission.Denied(fmt.Sprintf(reasonFmtScopeHasWorkloads, s.GetKind(), s.GetName(), strings.Join(names, ", ")))
+}
+
+var _ inject.Client = &DeletionValidatingHandler{}
+
+// InjectClient injects the client into the DeletionValidatingHandler
+func (h *DeletionValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &DeletionValidatingHandler{}
+
+// InjectDecoder injects the decoder into the DeletionValidatingHandler
+func (h *DeletionValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
+
+// DeletionValidatingPath is the path the validation of scope deletion is
+// served at by default
+const DeletionValidatingPath = "/validating-core-oam-dev-v1alpha2-scopes-deletion"
+
+// NewDeletionValidatingHandler returns a handler that validates the deletion
+// of scopes for the supplied manager
+func NewDeletionValidatingHandler(mgr manager.Manager) (*DeletionValidatingHandler, error) {
+	mapper, err := discoverymapper.New(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &DeletionValidatingHandler{Mapper: mapper}, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/apis/core/v1alpha1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core"
+	"github.com/crossplane/oam-kubernetes-runtime/apis/core/v1alpha2"
+	"github.com/crossplane/oam-kubernetes-runtime/pkg/oam/mock"
+)
+
+func TestScopeDeletionValidation(t *testing.T) {
+	errBoom := errors.New("boom")
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	dec, _ := admission.NewDecoder(scheme)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(v1alpha2.SchemeGroupVersion.WithKind(v1alpha2.HealthScopeKind), meta.RESTScopeNamespace)
+	mapper := mock.NewMockDiscoveryMapper()
+	mapper.MockGetMapper = func() (meta.RESTMapper, error) { return restMapper, nil }
+
+	deleteReq := func(refs ...v1alpha1.TypedReference) admission.Request {
+		raw, _ := json.Marshal(&v1alpha2.HealthScope{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha2.SchemeGroupVersion.String(), Kind: v1alpha2.HealthScopeKind},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+			Spec:       v1alpha2.HealthScopeSpec{WorkloadReferences: refs},
+		})
+		return admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: admissionv1beta1.Delete,
+			Resource:  metav1.GroupVersionResource{Group: "core.oam.dev", Version: "v1alpha2", Resource: "healthscopes"},
+			OldObject: runtime.RawExtension{Raw: raw},
+		}}
+	}
+	getDefinition := func(path string) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+			sd, ok := obj.(*v1alpha2.ScopeDefinition)
+			if !ok {
+				return errBoom
+			}
+			sd.Spec.WorkloadRefsPath = path
+			return nil
+		}
+	}
+	web := v1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+	db := v1alpha1.TypedReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "db"}
+
+	tests := map[string]struct {
+		req    admission.Request
+		get    test.MockGetFn
+		pass   bool
+		reason string
+	}{
+		"scope references workloads": {
+			req:    deleteReq(web, db),
+			get:    getDefinition("spec.workloadRefs"),
+			reason: fmt.Sprintf(reasonFmtScopeHasWorkloads, v1alpha2.HealthScopeKind, "app", `Deployment "web", Deployment "db"`),
+		},
+		"scope references no workloads": {
+			req:  deleteReq(),
+			get:  getDefinition("spec.workloadRefs"),
+			pass: true,
+		},
+		"scope does not record its workloads": {
+			req:  deleteReq(web),
+			get:  getDefinition(""),
+			pass: true,
+		},
+		"scope has no definition": {
+			req:  deleteReq(web),
+			get:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Group: "core.oam.dev", Resource: "scopedefinitions"}, "healthscopes.core.oam.dev")),
+			pass: true,
+		},
+		"get definition error": {
+			req: deleteReq(web),
+			get: test.NewMockGetFn(errBoom),
+		},
+		"not a deletion": {
+			req: admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Update,
+				Resource:  metav1.GroupVersionResource{Group: "core.oam.dev", Version: "v1alpha2", Resource: "healthscopes"},
+			}},
+			get:  test.NewMockGetFn(errBoom),
+			pass: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &DeletionValidatingHandler{Client: &test.MockClient{MockGet: tc.get}, Mapper: mapper, Decoder: dec}
+			resp := h.Handle(context.Background(), tc.req)
+			assert.Equal(t, tc.pass, resp.Allowed)
+			if tc.reason != "" {
+				assert.Equal(t, tc.reason, string(resp.Result.Reason))
+			}
+		})
+	}
+}